@@ -1,16 +1,37 @@
 package main
 
 import (
+	"bufio"
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
+	"os/exec"
+	"os/signal"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/beck/go-coverage-analyzer/internal/analyzer"
+	"github.com/beck/go-coverage-analyzer/internal/backup"
+	"github.com/beck/go-coverage-analyzer/internal/command"
 	"github.com/beck/go-coverage-analyzer/internal/config"
+	"github.com/beck/go-coverage-analyzer/internal/daemon"
+	"github.com/beck/go-coverage-analyzer/internal/dashboard"
+	"github.com/beck/go-coverage-analyzer/internal/doctor"
 	"github.com/beck/go-coverage-analyzer/internal/generator"
+	"github.com/beck/go-coverage-analyzer/internal/history"
+	"github.com/beck/go-coverage-analyzer/internal/hook"
+	"github.com/beck/go-coverage-analyzer/internal/i18n"
+	"github.com/beck/go-coverage-analyzer/internal/ide"
+	"github.com/beck/go-coverage-analyzer/internal/manifest"
+	"github.com/beck/go-coverage-analyzer/internal/mutator"
 	"github.com/beck/go-coverage-analyzer/internal/reporter"
+	"github.com/beck/go-coverage-analyzer/internal/storage"
+	"github.com/beck/go-coverage-analyzer/pkg/coverage"
 	"github.com/beck/go-coverage-analyzer/pkg/models"
 	"github.com/spf13/cobra"
 )
@@ -18,12 +39,29 @@ import (
 var (
 	version = "0.1.0"
 	cfg     *config.Config
+	cfgErr  error                  // why the configuration failed to load and the defaults are used, for gcov doctor
+	numbers *reporter.NumberFormat // how reports print percentages, from --precision and --absolute
 )
 
+// exitFailure is the exit code when a coverage gate, such as the threshold, fails
+const exitFailure = 1
+
+// Exit codes for the validate command
+const (
+	exitSyntaxError      = 2
+	exitCompileError     = 3
+	exitRuntimeError     = 4
+	exitWarningsExceeded = 5
+)
+
+// exitInterrupted is the exit code after SIGINT or SIGTERM, as shells report for SIGINT
+const exitInterrupted = 130
+
 func main() {
 	var err error
 	cfg, err = config.Load()
 	if err != nil {
+		cfgErr = err
 		// Use default configuration if loading fails
 		cfg = &config.Config{}
 		if err := setDefaultConfig(cfg); err != nil {
@@ -35,10 +73,61 @@ func main() {
 		}
 	}
 
-	if err := rootCmd.Execute(); err != nil {
+	// The first interrupt cancels the running command and the go processes it started; once it is
+	// cancelled, signals are no longer caught, so a second one kills gcov right away
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	if err := rootCmd.ExecuteContext(ctx); err != nil {
+		if ctx.Err() != nil {
+			fmt.Fprintf(os.Stderr, "Interrupted: %v\n", err)
+			os.Exit(exitInterrupted)
+		}
+		var exitErr *exitError
+		if errors.As(err, &exitErr) {
+			if exitErr.err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", exitErr.err)
+			}
+			os.Exit(exitErr.code)
+		}
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
+	stop()
+}
+
+// exitError makes gcov exit with a specific code. Commands return it instead of calling os.Exit, so
+// deferred cleanup runs and the run functions can be called without ending the process
+type exitError struct {
+	code int
+	err  error // nil when the command already reported why it failed
+}
+
+// exitWithCode returns an error exiting with code; without a message the failure was already reported
+func exitWithCode(code int, format string, args ...interface{}) error {
+	if format == "" {
+		return &exitError{code: code}
+	}
+	return &exitError{code: code, err: fmt.Errorf(format, args...)}
+}
+
+func (e *exitError) Error() string {
+	if e.err == nil {
+		return fmt.Sprintf("exit status %d", e.code)
+	}
+	return e.err.Error()
+}
+
+func (e *exitError) Unwrap() error {
+	return e.err
+}
+
+// ExitCode returns the code gcov exits with
+func (e *exitError) ExitCode() int {
+	return e.code
 }
 
 // setDefaultConfig initializes a configuration with default values
@@ -48,19 +137,40 @@ func setDefaultConfig(cfg *config.Config) error {
 	cfg.CoverageThreshold = 80.0
 	cfg.CalculateComplexity = true
 	cfg.MinComplexity = 1
+	cfg.HighComplexityThreshold = models.DefaultHighComplexityThreshold
+	cfg.FunctionLimits.MaxLines = models.DefaultFunctionLineLimit
+	cfg.FunctionLimits.MaxParams = models.DefaultParamLimit
 	cfg.OutputFormat = "console"
 	cfg.OutputDir = "."
 	cfg.Verbose = false
+	cfg.Precision = reporter.DefaultPrecision
 	cfg.ProfileOutput = "coverage.out"
+	cfg.HistoryDir = ".gcov/history"
+	cfg.Hook.Threshold = 80.0
+	cfg.Hook.DiffBase = "@{upstream}"
+	cfg.Notifications.MaxRegressions = 5
+	cfg.AI.Endpoint = "https://api.openai.com/v1"
+	cfg.AI.Model = "gpt-4o-mini"
+	cfg.AI.APIKeyEnv = "OPENAI_API_KEY"
+	cfg.AI.MinComplexity = generator.DefaultAIMinComplexity
 	cfg.TemplateStyle = "standard"
 	cfg.GenerateMocks = true
+	cfg.MockFramework = generator.MockFrameworkTestify
 	cfg.TableDriven = true
 	cfg.GenerateBenchmarks = false
 	cfg.OverwriteTests = false
 	cfg.MaxTestCases = 10
+	cfg.UnexportedMinComplexity = 3
+	cfg.Validation.Count = 1
+	cfg.Validation.Timeout = 10 * time.Minute
+	cfg.TestLint.MaxAssertions = generator.DefaultMaxAssertions
+	cfg.TestLint.MaxTestLines = generator.DefaultMaxTestLines
+	cfg.TestLint.FailOn = generator.SeverityError
+	cfg.CommandTimeout = 30 * time.Minute
 	cfg.MaxConcurrency = 4
 	cfg.EnableCaching = true
 	cfg.IgnoreFunctions = []string{}
+	cfg.ExemptionsFile = analyzer.DefaultExemptionsFile
 	cfg.CustomPatterns = []string{}
 	cfg.GoVersions = []string{}
 	cfg.BuildTags = []string{}
@@ -81,24 +191,55 @@ Features:
 - CI/CD integration and IDE support
 - Template customization and extensibility`,
 	Version: version,
-	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+	// main reports errors itself, and usage only helps with flag and argument errors, which cobra
+	// reports before the command runs
+	SilenceErrors: true,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
 		// Load configuration from flags
 		if configPath, _ := cmd.Flags().GetString("config"); configPath != "" {
 			if newCfg, err := config.LoadFromFile(configPath); err == nil {
-				cfg = newCfg
+				cfg, cfgErr = newCfg, nil
+			} else {
+				cfgErr = err
 			}
 		}
+
+		storage.Configure(&storage.Options{
+			S3Region:     cfg.Storage.S3Region,
+			S3Endpoint:   cfg.Storage.S3Endpoint,
+			GCSTokenEnv:  cfg.Storage.GCSTokenEnv,
+			HTTPTokenEnv: cfg.Storage.HTTPTokenEnv,
+			Timeout:      cfg.Storage.Timeout,
+		})
+
+		if err := reporter.SetColorMode(colorMode(cmd)); err != nil {
+			return err
+		}
+		var err error
+		if numbers, err = reporter.NewNumberFormat(numberFormat(cmd)); err != nil {
+			return err
+		}
+		return setupMessages(cmd)
 	},
 }
 
 var analyzeCmd = &cobra.Command{
-	Use:   "analyze [project-path]",
+	Use:   "analyze [project-path | file.go | directory]",
 	Short: "Analyze test coverage for a Go project",
 	Long: `Analyze the specified Go project (or current directory) to identify
 uncovered code paths and generate a comprehensive coverage report.
 
 The analyzer integrates with Go's built-in coverage tools to provide detailed
-insights into test coverage gaps at function, package, and project levels.`,
+insights into test coverage gaps at function, package, and project levels.
+
+Given a Go file, or a directory inside a module that has no go.mod of its own,
+only that file or subtree is parsed and matched against the profile, and the
+coverage of each of its functions is printed. This is quick enough to run from
+an editor on save:
+
+  gcov analyze ./internal/reporter/reporter.go`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runAnalysis,
 }
@@ -118,28 +259,295 @@ var validateCmd = &cobra.Command{
 	Short: "Validate existing or generated tests",
 	Long: `Validate test files for syntax, compilation, and quality issues.
 This command checks test files for common problems, ensures they compile
-and run correctly, and provides recommendations for improvement.`,
+and run correctly, and provides recommendations for improvement.
+With --output junit the result is written as JUnit XML, so CI systems show
+validation failures as test failures.`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runValidation,
 }
 
+var lintTestsCmd = &cobra.Command{
+	Use:   "lint-tests [project-path]",
+	Short: "Find test smells in test files",
+	Long: `Check every test file for test smells: tests without assertions or with too
+many, helpers failing tests without calling t.Helper, time.Sleep, discarded
+errors, overlong tests and duplicated table cases. Each finding has a severity
+(error, warning or info); the command fails when a finding reaches --fail-on.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runLintTests,
+}
+
 var reportCmd = &cobra.Command{
 	Use:   "report [project-path]",
 	Short: "Generate coverage reports without analysis",
 	Long: `Generate coverage reports from existing coverage data without
 running a new analysis. Useful for creating different report formats
-from previously collected coverage information.`,
+from previously collected coverage information. With --from, a result saved by
+gcov analyze --save is reported as it is, without parsing the project again.
+
+With --serve, the HTML report is served from memory instead, and open pages
+reload whenever the profile or saved result changes, for example after
+go test -coverprofile=coverage.out ./... runs again.`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runReporting,
 }
 
+var compareCmd = &cobra.Command{
+	Use:   "compare [project-path]",
+	Short: "Compare coverage against a git revision",
+	Long: `Compare the coverage of the project against its coverage at a git ref, such
+as a release tag, in one command. The ref is checked out into a temporary git
+worktree, where the tests run to generate its profile; the tests of the project
+itself, uncommitted changes included, run the same way, so both sides are
+measured alike. With --profile=false the project's existing profile is used,
+and the comparison fails when it is missing or older than the sources. The
+worktree is removed once both are analyzed.
+
+  gcov compare --against v1.4.0`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runComparison,
+}
+
+var prioritizeCmd = &cobra.Command{
+	Use:   "prioritize [project-path]",
+	Short: "Rank uncovered functions by testing impact",
+	Long: `Rank uncovered functions by the coverage they would add if tested,
+weighted by complexity and export status. The resulting backlog shows the
+estimated effort for each function and the projected coverage after testing it.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runPrioritization,
+}
+
+var mutateCmd = &cobra.Command{
+	Use:   "mutate [project-path]",
+	Short: "Run mutation testing against covered functions",
+	Long: `Apply simple mutations (conditional flips, arithmetic operator swaps and
+return-value changes) to covered functions and rerun the tests for each mutant.
+The mutation score per package shows whether coverage is backed by assertions
+that actually detect behavioural changes.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runMutation,
+}
+
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Record and chart coverage history",
+	Long: `Manage the coverage history store. Snapshots of overall and per-package
+coverage are recorded after analysis and can be charted over time or per git tag.
+When two or more snapshots exist, HTML reports include the coverage timeline.`,
+}
+
+var pruneCmd = &cobra.Command{
+	Use:   "prune [project-path]",
+	Short: "Find generated tests whose targets were removed or changed signature",
+	Long: `Scan test files for tests written by gcov generate, recognized by their
+//gcov:generated marker, and report those whose target function no longer exists
+or has a different signature than when the test was generated. With
+--attribute-tests every test is also run on its own, and generated tests that no
+longer cover their target are reported too. With --delete the stale tests are
+removed along with imports only they used, and dropped from the manifest.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runPrune,
+}
+
+var affectedCmd = &cobra.Command{
+	Use:   "affected [project-path]",
+	Short: "Run only the tests affected by the changes since a git ref",
+	Long: `Find the functions changed since --diff-base and select the tests that cover
+them according to the test matrix, plus every test in a changed test file. The
+matrix is read from --matrix, a JSON result of gcov analyze --attribute-tests
+recorded earlier, or else attributed now by running every test once. Prints the
+narrowed go test command, or runs it with --run.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runAffected,
+}
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor [project-path]",
+	Short: "Check the environment gcov needs and suggest fixes",
+	Long: `Diagnose the problems that make gcov fail before it gets to the coverage:
+the Go toolchain and its version, the module and whether it builds and passes
+go vet, the coverage profile, the configuration, test and report templates, and
+write permissions. Every problem comes with the fix to apply. Exits with 1 when
+a check fails.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runDoctor,
+}
+
+var hookCmd = &cobra.Command{
+	Use:   "hook",
+	Short: "Check the coverage of changed code from git hooks",
+	Long: `Install a git pre-commit or pre-push hook, or run the check such a hook runs:
+the tests of the packages with changed Go files are run with coverage, and the
+hook fails when the changed functions are covered less than hook.threshold in
+gcov.yaml (80% by default). Frameworks such as pre-commit and lefthook call
+gcov hook run directly, passing the files to check.`,
+}
+
+var hookInstallCmd = &cobra.Command{
+	Use:   "install [project-path]",
+	Short: "Install a git hook running gcov hook run",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runHookInstall,
+}
+
+var hookRunCmd = &cobra.Command{
+	Use:   "run [files...]",
+	Short: "Check the coverage of the staged or pushed changes",
+	Long: `Run the tests of the packages with changed Go files and fail when the changed
+functions are covered less than the threshold. With --stage pre-commit the staged
+changes are checked, with pre-push the changes since --diff-base. Files given as
+arguments, as pre-commit and lefthook pass them, limit the check to those files.`,
+	RunE: runHookRun,
+}
+
+var manifestCmd = &cobra.Command{
+	Use:   "manifest [project-path]",
+	Short: "List the files and tests gcov generated",
+	Long: `Show the artifacts recorded in .gcov/manifest.json: every test gcov generate
+wrote, with the function and signature it targets and the gcov version that wrote
+it, as well as helper and mock files.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runManifest,
+}
+
+var templatesCmd = &cobra.Command{
+	Use:   "templates",
+	Short: "Manage test templates",
+	Long: `The built-in test templates are embedded in the gcov binary. Export them to
+customize, then point templates.custom_templates_dir in gcov.yaml at the directory;
+a relative directory is resolved from the location of the config file.`,
+}
+
+var templatesExportCmd = &cobra.Command{
+	Use:   "export <dir>",
+	Short: "Write the embedded templates to a directory for customization",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runTemplatesExport,
+}
+
+var restoreCmd = &cobra.Command{
+	Use:   "restore [project-path]",
+	Short: "Restore files gcov generate overwrote from their backups",
+	Long: `Write back the content files had before gcov generate overwrote them. Backups
+are kept in .gcov/backups, one directory per run, when generating with --backup or
+backup_overwritten in gcov.yaml. Without --run the most recent run is restored.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runRestore,
+}
+
+var schemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Print the JSON Schema of the JSON analysis result",
+	Long: `Print the JSON Schema (draft 2020-12) of the result gcov analyze --output json
+writes. Every result carries a schema_version following semantic versioning:
+within a major version fields are only added, never removed, renamed or retyped,
+so consumers that ignore unknown fields keep working across minor releases. A new
+major version may change the shape and comes with a new schema.`,
+	Args: cobra.NoArgs,
+	RunE: runSchema,
+}
+
+var funcAtCmd = &cobra.Command{
+	Use:   "func-at <file.go:line>",
+	Short: "Show the coverage of the function enclosing a source line",
+	Long: `Analyze only the given file and report the function or closure enclosing the
+line: its coverage, complexity, the line ranges that never ran and the gcov
+generate command writing tests for it. With --output json this is the query
+editor plugins build hovers and code lenses on:
+
+  gcov func-at internal/reporter/reporter.go:123 --output json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runFuncAt,
+}
+
+var ideCmd = &cobra.Command{
+	Use:   "ide [project-path]",
+	Short: "Serve editor extensions with JSON-RPC over stdin and stdout",
+	Long: `Run until stdin closes, answering JSON-RPC 2.0 requests, one JSON message per
+line, so an editor extension keeps a warm analyzer instead of starting gcov for
+every request. Methods:
+
+  analyze              analyze the project and cache the result
+  coverageForFile      {"file": "..."} coverage, complexity and uncovered lines
+                       of every function of a file
+  generateForFunction  {"file": "...", "function": "Receiver.Name", "dryRun": false,
+                       "overwrite": false} generate tests for one function
+  shutdown             stop serving
+
+Files changed since the cached analysis, or a changed profile, are analyzed again
+on their own. Anything gcov prints besides responses goes to stderr.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runIDE,
+}
+
+var daemonCmd = &cobra.Command{
+	Use:   "daemon",
+	Short: "Keep a gcov process with the project's analysis in memory",
+	Long: `A gcov daemon keeps the parsed sources and the latest results of a project in
+memory and serves them over a unix socket. While one runs, gcov analyze, generate,
+prioritize and func-at on the project delegate their analysis to it, so repeated
+runs only parse the files changed since; when no file changed, the cached result
+is returned at once. Pass --no-daemon to analyze in the command itself.`,
+}
+
+var daemonStartCmd = &cobra.Command{
+	Use:   "start [project-path]",
+	Short: "Start a daemon for the project in the background",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runDaemonStart,
+}
+
+var daemonRunCmd = &cobra.Command{
+	Use:   "run [project-path]",
+	Short: "Run the daemon for the project in the foreground, for process supervisors",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runDaemonRun,
+}
+
+var daemonStopCmd = &cobra.Command{
+	Use:   "stop [project-path]",
+	Short: "Stop the daemon of the project",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runDaemonStop,
+}
+
+var daemonStatusCmd = &cobra.Command{
+	Use:   "status [project-path]",
+	Short: "Show whether a daemon serves the project, and what it keeps",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runDaemonStatus,
+}
+
+var historyRecordCmd = &cobra.Command{
+	Use:   "record [project-path]",
+	Short: "Analyze the project and record a coverage snapshot",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runHistoryRecord,
+}
+
+var historyChartCmd = &cobra.Command{
+	Use:   "chart [project-path]",
+	Short: "Render recorded coverage history as an SVG line chart",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runHistoryChart,
+}
+
 func init() {
 	// Global flags
 	rootCmd.PersistentFlags().StringP("config", "c", "", "Configuration file path")
 	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "Enable verbose output")
-	rootCmd.PersistentFlags().StringP("output", "o", "console", "Output format (console, json, html, xml)")
+	rootCmd.PersistentFlags().StringP("color", "", reporter.ColorModeAuto, "Color console output: auto (when stdout is a terminal and NO_COLOR is unset), always or never")
+	rootCmd.PersistentFlags().BoolP("no-color", "", false, "Disable colors (same as --color never)")
+	rootCmd.PersistentFlags().BoolP("plain", "", false, "Print ASCII tags such as [OK] and [WARN] instead of emojis")
+	rootCmd.PersistentFlags().IntP("precision", "", reporter.DefaultPrecision, "Decimals of the percentages in reports (0 to 4)")
+	rootCmd.PersistentFlags().BoolP("absolute", "", false, "Lead with covered/total statement and function counts instead of percentages")
+	rootCmd.PersistentFlags().StringP("messages", "", "", "JSON message catalog translating console messages")
+	rootCmd.PersistentFlags().StringP("output", "o", "console", "Output format (console, json, html, xml, sarif, markdown, csv, tsv)")
 	rootCmd.PersistentFlags().StringSliceP("exclude", "e", []string{"vendor", "testdata", ".git"}, "Directories to exclude")
 	rootCmd.PersistentFlags().Float64P("threshold", "t", 80.0, "Coverage threshold percentage")
+	rootCmd.PersistentFlags().BoolP("no-daemon", "", false, "Analyze in this process even when a gcov daemon serves the project")
+	rootCmd.PersistentFlags().DurationP("command-timeout", "", 30*time.Minute, "Timeout for each go test, go build and go list run gcov starts (0 disables)")
 
 	// Analyze command flags
 	analyzeCmd.Flags().BoolP("include-tests", "i", false, "Include test files in analysis")
@@ -148,39 +556,222 @@ func init() {
 	analyzeCmd.Flags().StringP("profile-output", "", "coverage.out", "Coverage profile output file")
 	analyzeCmd.Flags().BoolP("complexity", "", true, "Calculate cyclomatic complexity")
 	analyzeCmd.Flags().IntP("min-complexity", "", 1, "Minimum complexity threshold for reporting")
+	analyzeCmd.Flags().IntP("max-function-lines", "", models.DefaultFunctionLineLimit, "Report functions with more lines of code as oversized (default from function_limits.max_lines)")
+	analyzeCmd.Flags().IntP("max-params", "", models.DefaultParamLimit, "Report functions with more parameters as oversized (default from function_limits.max_params)")
+	analyzeCmd.Flags().IntP("complexity-warn", "", models.DefaultHighComplexityThreshold, "Complexity above which functions count as highly complex (default from high_complexity_threshold)")
+	analyzeCmd.Flags().Float64P("api-threshold", "", 0, "Minimum exported API coverage percentage (0 disables)")
+	analyzeCmd.Flags().BoolP("record-history", "", false, "Record a coverage snapshot in the history store")
+	analyzeCmd.Flags().BoolP("notify", "", false, "Post a coverage summary to the configured Slack/Teams webhooks")
+	analyzeCmd.Flags().BoolP("fail-on-new-uncovered", "", false, "Fail only when functions added or modified in the change set are uncovered")
+	analyzeCmd.Flags().StringP("diff-base", "", "", "Git ref the change set is diffed against (for --fail-on-new-uncovered)")
+	analyzeCmd.Flags().StringP("baseline", "", "", "JSON analysis result of the baseline (for --fail-on-new-uncovered)")
+	analyzeCmd.Flags().StringP("group-by", "", "package", "Group report details by package, owner (CODEOWNERS or config owners mapping), author (git blame of uncovered code) or receiver type")
+	analyzeCmd.Flags().BoolP("blame", "", false, "Record the author of most lines of each uncovered function by git blame (implied by --group-by author)")
+	analyzeCmd.Flags().BoolP("show-uncovered-lines", "", false, "List the line ranges partially covered functions never ran")
+	analyzeCmd.Flags().IntP("uncovered-context", "", 0, "Source lines shown around each uncovered range (e.g. 2)")
+	analyzeCmd.Flags().StringP("granularity", "", reporter.GranularityPackage, "Rows of csv and tsv output: package, file or function")
+	analyzeCmd.Flags().StringP("ci", "", "", "Also print coverage statistics for a CI server: teamcity (service messages) or bamboo (variables to inject), on stderr when a non-console report goes to stdout")
+	analyzeCmd.Flags().StringP("detail", "", reporter.DetailFunctions, "Report detail: functions, or blocks to add the uncovered line ranges of every function to JSON and HTML")
+	analyzeCmd.Flags().BoolP("attribute-tests", "", false, "Run every top-level test on its own to record which functions it covers (slow)")
+	analyzeCmd.Flags().Float64P("fail-under", "", 0, "Exit with 1 when coverage is below this percentage (default: --threshold)")
+	analyzeCmd.Flags().Float64P("fail-under-function", "", 0, "Exit with 1 when the share of covered functions is below this percentage (0 disables)")
+	analyzeCmd.Flags().Float64P("fail-under-branch", "", 0, "Exit with 1 when branch coverage is below this percentage (0 disables)")
+	analyzeCmd.Flags().Float64P("fail-under-package", "", 0, "Exit with 1 when the worst package's coverage is below this percentage (0 disables)")
+	analyzeCmd.Flags().StringP("save", "", "", "Save the full analysis result as JSON to a path or s3://, gs:// or http(s):// URL, for gcov report --from")
+	analyzeCmd.Flags().StringP("artifacts-dir", "", "", "Generate the profile and write it, the JSON result, HTML report, Markdown summary and badge to this directory")
+	analyzeCmd.Flags().StringP("exemptions", "", "", "YAML file of functions and files exempted from thresholds until they expire (default from exemptions_file, gcov-exemptions.yaml)")
+	analyzeCmd.Flags().BoolP("self-profile", "", false, "Record how long each analysis phase takes and the peak heap, in the JSON metadata and on stderr")
+	analyzeCmd.Flags().StringP("pprof-dir", "", "", "Write CPU and heap pprof profiles of the analysis to this directory (implies --self-profile)")
+	analyzeCmd.Flags().BoolP("no-fail", "", false, "Report failed threshold and change set checks without a non-zero exit code")
 
 	// Generate command flags
 	generateCmd.Flags().BoolP("dry-run", "d", false, "Preview generated tests without writing files")
-	generateCmd.Flags().StringP("template-style", "", "standard", "Test template style (standard, testify, table)")
+	generateCmd.Flags().StringP("dry-run-format", "", generator.DryRunDiff, "What --dry-run prints for each file: diff (unified diff against the file on disk), summary or full")
+	generateCmd.Flags().StringP("template-style", "", "standard", "Test template style (standard, testify, gotest-tools, go-cmp, table, snapshot)")
 	generateCmd.Flags().BoolP("generate-mocks", "m", true, "Generate mocks for interfaces")
+	generateCmd.Flags().StringP("mock-framework", "", generator.MockFrameworkTestify, "Mock style for interfaces (testify, fake with overridable function fields)")
 	generateCmd.Flags().BoolP("table-driven", "", true, "Generate table-driven tests when applicable")
+	generateCmd.Flags().BoolP("parallel-tests", "", false, "Call t.Parallel() in generated tests and their subtests")
 	generateCmd.Flags().BoolP("benchmarks", "b", false, "Generate benchmark tests")
+	generateCmd.Flags().BoolP("benchmark-baseline", "", false, "With --benchmarks, also write bench_compare.sh comparing benchmarks against a git ref")
 	generateCmd.Flags().BoolP("overwrite", "w", false, "Overwrite existing test files")
+	generateCmd.Flags().BoolP("backup", "", false, "Keep the previous content of overwritten files in .gcov/backups (undo with gcov restore)")
 	generateCmd.Flags().StringSliceP("ignore-functions", "", []string{}, "Function patterns to ignore")
+	generateCmd.Flags().StringSliceP("function", "", []string{}, "Only generate tests for these functions, Name or Receiver.Name")
 	generateCmd.Flags().IntP("max-cases", "", 10, "Maximum test cases per function")
+	generateCmd.Flags().BoolP("include-unexported", "", false, "Also generate tests for unexported functions")
+	generateCmd.Flags().IntP("unexported-min-complexity", "", 3, "Minimum complexity for unexported functions to get tests")
+	generateCmd.Flags().BoolP("evaluate", "", false, "Execute pure functions to compute expected values")
+	generateCmd.Flags().BoolP("stress", "", false, "Generate concurrent stress tests for functions using goroutines, channels or sync")
+	generateCmd.Flags().BoolP("examples", "", false, "Generate ExampleXxx functions with Output comments for simple exported functions")
+	generateCmd.Flags().StringP("suite-style", "", "none", "Shared fixture setup for tests needing temp dirs, env vars or servers (none, testmain, testify-suite)")
+	generateCmd.Flags().BoolP("main-harness", "", false, "Generate a harness running the command of main packages in a subprocess, so their coverage counts")
+	generateCmd.Flags().StringP("test-package-mode", "", generator.TestPackageSame, "Package generated tests are declared in: same, external (package foo_test, exported API only) or auto (follow existing tests)")
+	generateCmd.Flags().StringP("test-name-pattern", "", generator.DefaultTestNamePattern, "Go template naming generated tests, with .Function, .Receiver and .Case (e.g. Test{{.Receiver}}_{{.Function}})")
+	generateCmd.Flags().Int64P("seed", "", generator.DefaultSeed, "Seed for generated test data, so regenerating produces the same values")
+	generateCmd.Flags().BoolP("ai", "", false, "Ask the configured OpenAI-compatible endpoint (ai section of gcov.yaml) for tests of complex functions")
+	generateCmd.Flags().IntP("ai-min-complexity", "", generator.DefaultAIMinComplexity, "Minimum complexity for functions to be handed to the AI backend")
+	generateCmd.Flags().BoolP("snapshot", "", false, "Compare large outputs against golden files (same as --template-style snapshot)")
+	generateCmd.Flags().IntP("concurrency", "j", 0, "Number of source files whose tests are generated in parallel (default: max_concurrency)")
+	generateCmd.Flags().BoolP("skeleton", "", false, "Generate skipped tests with placeholder expectations, marked TODO for a human to fill in")
 
 	// Validate command flags
 	validateCmd.Flags().StringP("test-file", "", "", "Specific test file to validate")
 	validateCmd.Flags().BoolP("compile-check", "", true, "Check if tests compile")
 	validateCmd.Flags().BoolP("run-tests", "", true, "Run tests to check execution")
 	validateCmd.Flags().BoolP("quality-check", "", true, "Run quality checks on test structure")
+	validateCmd.Flags().IntP("detect-flaky", "", 0, "Run the test suite N times and report tests that alternate pass/fail")
+	validateCmd.Flags().BoolP("race", "", false, "Run tests with the race detector")
+	validateCmd.Flags().BoolP("shuffle", "", false, "Shuffle test order for flaky detection runs")
+	validateCmd.Flags().IntP("count", "", 1, "Run each test N times (go test -count)")
+	validateCmd.Flags().DurationP("test-timeout", "", 10*time.Minute, "Timeout for the go test run (go test -timeout)")
+	validateCmd.Flags().StringP("run", "", "", "Only run tests matching the regular expression (go test -run)")
+	validateCmd.Flags().StringSliceP("test-flags", "", []string{}, "Additional flags passed through to go test")
+	validateCmd.Flags().IntP("max-warnings", "", -1, "Fail when more than N warnings are reported (-1 disables the check)")
+	validateCmd.Flags().StringP("output-file", "", "", "Output file path for json, sarif or junit output (default: stdout)")
 
 	// Report command flags
 	reportCmd.Flags().StringP("input", "", "coverage.out", "Input coverage profile file")
+	reportCmd.Flags().StringP("from", "", "", "Analysis result saved with gcov analyze --save, reported without analyzing the project again")
 	reportCmd.Flags().StringP("output-file", "", "", "Output file path (default: stdout)")
 	reportCmd.Flags().BoolP("open", "", false, "Open HTML report in browser")
+	reportCmd.Flags().StringP("serve", "", "", "Serve the HTML report on this address (e.g. :8080) instead of writing it, reloading it when the profile or --from result changes")
+	reportCmd.Flags().BoolP("show-uncovered-lines", "", false, "List the line ranges partially covered functions never ran")
+	reportCmd.Flags().IntP("uncovered-context", "", 0, "Source lines shown around each uncovered range (e.g. 2)")
+	reportCmd.Flags().IntP("complexity-warn", "", models.DefaultHighComplexityThreshold, "Complexity above which functions count as highly complex (default from high_complexity_threshold, or the saved result's with --from)")
+	reportCmd.Flags().StringP("granularity", "", reporter.GranularityPackage, "Rows of csv and tsv output: package, file or function")
+	reportCmd.Flags().StringP("ci", "", "", "Also print coverage statistics for a CI server: teamcity (service messages) or bamboo (variables to inject), on stderr when a non-console report goes to stdout")
+	reportCmd.Flags().StringP("detail", "", reporter.DetailFunctions, "Report detail: functions, or blocks to add the uncovered line ranges of every function to JSON and HTML")
+
+	// Compare command flags
+	compareCmd.Flags().StringP("against", "", "", "Git ref to compare against, such as a tag, branch or commit (required)")
+	compareCmd.Flags().StringP("package", "p", "", "Specific package pattern to analyze")
+	compareCmd.Flags().BoolP("profile", "", true, "Generate the coverage profile of the project before comparing, as the ref's is; false uses the existing one when it is fresh")
+	_ = compareCmd.MarkFlagRequired("against")
+
+	// Prioritize command flags
+	prioritizeCmd.Flags().StringP("package", "p", "", "Specific package pattern to analyze")
+	prioritizeCmd.Flags().BoolP("profile", "", false, "Generate coverage profile")
+	prioritizeCmd.Flags().IntP("limit", "l", 20, "Maximum number of functions to list (0 for all)")
+	prioritizeCmd.Flags().StringP("output-file", "", "", "Output file path (default: stdout)")
+
+	// Mutate command flags
+	mutateCmd.Flags().StringP("package", "p", "", "Specific package pattern to analyze")
+	mutateCmd.Flags().BoolP("profile", "", false, "Generate coverage profile")
+	mutateCmd.Flags().IntP("concurrency", "j", 0, "Number of mutants to test in parallel (default: max_concurrency)")
+	mutateCmd.Flags().IntP("max-mutants", "", 200, "Maximum number of mutants to test (0 for all)")
+	mutateCmd.Flags().DurationP("timeout", "", 2*time.Minute, "Timeout for each mutant test run")
+	mutateCmd.Flags().StringP("output-file", "", "", "Output file path (default: stdout)")
+
+	// History command flags
+	historyRecordCmd.Flags().StringP("package", "p", "", "Specific package pattern to analyze")
+	historyRecordCmd.Flags().BoolP("profile", "", false, "Generate coverage profile")
+	historyChartCmd.Flags().StringP("output", "o", "coverage-history.svg", "Output SVG file path")
+	historyChartCmd.Flags().BoolP("by-tag", "", false, "Only chart snapshots recorded at a git tag")
+
+	// Lint tests command flags
+	lintTestsCmd.Flags().IntP("max-assertions", "", generator.DefaultMaxAssertions, "Assertions a test may make before it is reported")
+	lintTestsCmd.Flags().IntP("max-test-lines", "", generator.DefaultMaxTestLines, "Lines a test function may span before it is reported")
+	lintTestsCmd.Flags().StringP("fail-on", "", generator.SeverityError, "Fail when a finding has at least this severity (error, warning, info, none)")
+	lintTestsCmd.Flags().StringP("output-file", "", "", "Output file path (default: stdout)")
+
+	// Go-style package patterns selecting the packages commands cover
+	for _, cmd := range []*cobra.Command{analyzeCmd, generateCmd, reportCmd, validateCmd, lintTestsCmd} {
+		cmd.Flags().StringSliceP("include-packages", "", []string{}, "Only cover packages matching these Go-style patterns (e.g. ./internal/...)")
+		cmd.Flags().StringSliceP("exclude-packages", "", []string{}, "Leave out packages matching these Go-style patterns")
+	}
+
+	// File classes coverage is computed over, and how the profile is read
+	for _, cmd := range []*cobra.Command{analyzeCmd, reportCmd} {
+		cmd.Flags().StringSliceP("classes", "", []string{}, "File classes counted in coverage: production, test, helper, generated, vendored (default from config, production)")
+		cmd.Flags().BoolP("stream-profile", "", false, "Read the coverage profile line by line, keeping blocks only for selected packages (for huge monorepos)")
+		cmd.Flags().BoolP("include-init", "", false, "Report the coverage of init functions and package-level variable initializers as initialization")
+		cmd.Flags().BoolP("include-cgo", "", false, "Count functions of files importing \"C\" in the totals, which leave them out as not instrumentable")
+	}
+
+	// Analysis warnings, such as profile files not in the project, fail the command in strict mode
+	for _, cmd := range []*cobra.Command{analyzeCmd, generateCmd, reportCmd} {
+		cmd.Flags().BoolP("strict", "", false, "Exit with 1 when the analysis warns, e.g. no profile block matched a project file or a file does not parse")
+		cmd.Flags().BoolP("require-fresh-profile", "", false, "Exit with 1 when source files were modified after the coverage profile was written")
+	}
+
+	// Progress of long runs, on stderr
+	for _, cmd := range []*cobra.Command{analyzeCmd, generateCmd} {
+		cmd.Flags().BoolP("no-progress", "", false, "Do not report progress (a bar with ETA on terminals, periodic lines otherwise)")
+	}
+
+	// Func-at command flags
+	funcAtCmd.Flags().StringP("input", "", "coverage.out", "Input coverage profile file, relative to the module")
+
+	// Prune command flags
+	pruneCmd.Flags().BoolP("delete", "", false, "Delete stale generated tests instead of only reporting them")
+	pruneCmd.Flags().BoolP("attribute-tests", "", false, "Run every test on its own and also report generated tests that no longer cover their target")
+
+	// Affected command flags
+	affectedCmd.Flags().StringP("diff-base", "", "HEAD", "Git ref the working tree is diffed against")
+	affectedCmd.Flags().StringP("matrix", "", "", "JSON analysis result with a test matrix (default: attribute coverage to tests now)")
+	affectedCmd.Flags().BoolP("run", "", false, "Run the affected tests instead of only printing the go test command")
+
+	// Hook command flags
+	hookInstallCmd.Flags().StringP("type", "", hook.PreCommit, "Hook to install: pre-commit or pre-push")
+	hookInstallCmd.Flags().BoolP("force", "f", false, "Replace an existing hook gcov did not install")
+	hookRunCmd.Flags().StringP("stage", "", hook.PreCommit, "Changes to check: pre-commit (staged) or pre-push (since --diff-base)")
+	hookRunCmd.Flags().StringP("diff-base", "", "@{upstream}", "Git ref pre-push checks diff against (default from hook.diff_base)")
+	hookRunCmd.Flags().StringP("project", "", ".", "Go project the changes are checked in")
+
+	// Manifest command flags
+	manifestCmd.Flags().StringP("function", "", "", "Only list artifacts for this function, Name or Receiver.Name")
+	manifestCmd.Flags().StringP("kind", "", "", "Only list artifacts of this kind (test, helper, mock)")
+
+	// Restore command flags
+	restoreCmd.Flags().StringP("run", "", "", "Backup run to restore (default: the most recent)")
+	restoreCmd.Flags().StringSliceP("file", "f", []string{}, "Only restore these files, relative to the project")
+	restoreCmd.Flags().BoolP("list", "l", false, "List the backup runs and their files instead of restoring")
+
+	templatesExportCmd.Flags().BoolP("overwrite", "w", false, "Overwrite templates that already exist in the directory")
+	templatesCmd.AddCommand(templatesExportCmd)
+
+	hookCmd.AddCommand(hookInstallCmd)
+	hookCmd.AddCommand(hookRunCmd)
+
+	daemonCmd.AddCommand(daemonStartCmd)
+	daemonCmd.AddCommand(daemonRunCmd)
+	daemonCmd.AddCommand(daemonStopCmd)
+	daemonCmd.AddCommand(daemonStatusCmd)
+
+	historyCmd.AddCommand(historyRecordCmd)
+	historyCmd.AddCommand(historyChartCmd)
 
 	// Add subcommands
 	rootCmd.AddCommand(analyzeCmd)
 	rootCmd.AddCommand(generateCmd)
 	rootCmd.AddCommand(validateCmd)
+	rootCmd.AddCommand(lintTestsCmd)
 	rootCmd.AddCommand(reportCmd)
+	rootCmd.AddCommand(compareCmd)
+	rootCmd.AddCommand(prioritizeCmd)
+	rootCmd.AddCommand(mutateCmd)
+	rootCmd.AddCommand(historyCmd)
+	rootCmd.AddCommand(pruneCmd)
+	rootCmd.AddCommand(affectedCmd)
+	rootCmd.AddCommand(doctorCmd)
+	rootCmd.AddCommand(hookCmd)
+	rootCmd.AddCommand(manifestCmd)
+	rootCmd.AddCommand(templatesCmd)
+	rootCmd.AddCommand(schemaCmd)
+	rootCmd.AddCommand(restoreCmd)
+	rootCmd.AddCommand(funcAtCmd)
+	rootCmd.AddCommand(ideCmd)
+	rootCmd.AddCommand(daemonCmd)
 }
 
 func runAnalysis(cmd *cobra.Command, args []string) error {
-	projectPath := "."
+	projectPath, scope := ".", ""
 	if len(args) > 0 {
-		projectPath = args[0]
+		var err error
+		if projectPath, scope, err = analysisScope(args[0]); err != nil {
+			return err
+		}
 	}
 
 	// Get command-line flags
@@ -194,6 +785,75 @@ func runAnalysis(cmd *cobra.Command, args []string) error {
 	profileOutput, _ := cmd.Flags().GetString("profile-output")
 	calculateComplexity, _ := cmd.Flags().GetBool("complexity")
 	minComplexity, _ := cmd.Flags().GetInt("min-complexity")
+	highComplexity := complexityWarn(cmd)
+	maxLines, maxParams := functionLimits(cmd)
+	apiThreshold, _ := cmd.Flags().GetFloat64("api-threshold")
+	recordHistory, _ := cmd.Flags().GetBool("record-history")
+	notify, _ := cmd.Flags().GetBool("notify")
+	failOnNewUncovered, _ := cmd.Flags().GetBool("fail-on-new-uncovered")
+	diffBase, _ := cmd.Flags().GetString("diff-base")
+	baselinePath, _ := cmd.Flags().GetString("baseline")
+	groupBy, _ := cmd.Flags().GetString("group-by")
+	blame, _ := cmd.Flags().GetBool("blame")
+	showUncoveredLines, _ := cmd.Flags().GetBool("show-uncovered-lines")
+	uncoveredContext, _ := cmd.Flags().GetInt("uncovered-context")
+	detail, _ := cmd.Flags().GetString("detail")
+	if detail != reporter.DetailFunctions && detail != reporter.DetailBlocks {
+		return fmt.Errorf("invalid --detail %q (valid: functions, blocks)", detail)
+	}
+	granularity, _ := cmd.Flags().GetString("granularity")
+	if !reporter.ValidGranularity(granularity) {
+		return fmt.Errorf("invalid --granularity %q (valid: package, file, function)", granularity)
+	}
+	ci := ciServer(cmd)
+	if !reporter.ValidCI(ci) {
+		return fmt.Errorf("invalid --ci %q (valid: teamcity, bamboo)", ci)
+	}
+	attributeTests, _ := cmd.Flags().GetBool("attribute-tests")
+	thresholds := failUnderThresholds(cmd, threshold)
+	noFail, _ := cmd.Flags().GetBool("no-fail")
+	savePath, _ := cmd.Flags().GetString("save")
+	artifactsDir, _ := cmd.Flags().GetString("artifacts-dir")
+	selfProfile, _ := cmd.Flags().GetBool("self-profile")
+	pprofDir, _ := cmd.Flags().GetString("pprof-dir")
+	if artifactsDir != "" {
+		// The profile is an artifact too, so it is always generated into the directory
+		if err := os.MkdirAll(artifactsDir, 0755); err != nil {
+			return fmt.Errorf("failed to create artifacts directory: %w", err)
+		}
+		absDir, err := filepath.Abs(artifactsDir)
+		if err != nil {
+			return fmt.Errorf("failed to resolve artifacts directory: %w", err)
+		}
+		generateProfile = true
+		profileOutput = filepath.Join(absDir, reporter.ArtifactProfile)
+	}
+	includePackages, excludePackages := packagePatterns(cmd)
+	classification, classes := fileClassification(cmd)
+	streamProfile, _ := cmd.Flags().GetBool("stream-profile")
+	if !cmd.Flags().Changed("stream-profile") {
+		streamProfile = cfg.StreamProfile
+	}
+	includeInit, _ := cmd.Flags().GetBool("include-init")
+	if !cmd.Flags().Changed("include-init") {
+		includeInit = cfg.IncludeInit
+	}
+	if scope != "" && recordHistory {
+		return fmt.Errorf("--record-history needs the whole project, not %s", scope)
+	}
+
+	exemptions, err := loadExemptions(cmd, projectPath)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	exemptFunctions, exemptFiles := analyzer.ExemptionPatterns(exemptions, now)
+
+	// With --no-fail the checks below are still reported, but never fail the command
+	failCode := exitFailure
+	if noFail {
+		failCode = 0
+	}
 
 	// Configure analysis options
 	opts := &analyzer.Options{
@@ -201,45 +861,246 @@ func runAnalysis(cmd *cobra.Command, args []string) error {
 		ExcludeDirs:         excludeDirs,
 		IncludeTests:        includeTests,
 		PackagePattern:      packagePattern,
+		IncludePackages:     includePackages,
+		ExcludePackages:     excludePackages,
+		Classification:      classification,
+		Classes:             classes,
 		GenerateProfile:     generateProfile,
+		StreamProfile:       streamProfile,
 		ProfileOutput:       profileOutput,
 		CalculateComplexity: calculateComplexity,
 		MinComplexity:       minComplexity,
+		HighComplexity:      highComplexity,
+		MaxFunctionLines:    maxLines,
+		MaxParams:           maxParams,
+		AttributeTests:      attributeTests,
+		SelfProfile:         selfProfile,
+		PprofDir:            pprofDir,
+		Scope:               scope,
 		Verbose:             verbose,
+		CommandTimeout:      commandTimeout(cmd),
+		Progress:            progressFunc(cmd),
+		IgnoreFunctions:     cfg.IgnoreFunctions,
+		ExemptFunctions:     exemptFunctions,
+		ExemptFiles:         exemptFiles,
+		IncludeInit:         includeInit,
+		IncludeCgo:          includeCgo(cmd),
 	}
 
 	if verbose {
-		fmt.Printf("🔍 Analyzing Go project at: %s\n", projectPath)
+		i18n.Printf("🔍 Analyzing Go project at: %s\n", projectPath)
+		if scope != "" {
+			i18n.Printf("🎯 Limited to: %s\n", scope)
+		}
 	}
 
 	// Run coverage analysis
-	result, err := analyzer.Analyze(opts)
+	result, err := analyze(cmd, opts)
 	if err != nil {
 		return fmt.Errorf("analysis failed: %w", err)
 	}
 
+	// A profile older than the sources misreports what changed since, so offer to regenerate it
+	if reportStaleProfile(result) {
+		if requireFreshProfile(cmd) {
+			if failCode != 0 {
+				return exitWithCode(failCode, "")
+			}
+		} else if offerRegeneration(result.Metadata.ProfilePath) {
+			opts.GenerateProfile = true
+			opts.ProfileOutput = result.Metadata.ProfilePath
+			result, err = analyze(cmd, opts)
+			if err != nil {
+				return fmt.Errorf("analysis failed: %w", err)
+			}
+		}
+	}
+	if result.Metadata.SelfProfile != nil {
+		printSelfProfile(result.Metadata.SelfProfile)
+		if pprofDir != "" {
+			i18n.Fprintf(os.Stderr, "   pprof profiles: %s, %s\n",
+				filepath.Join(pprofDir, analyzer.CPUProfileFile), filepath.Join(pprofDir, analyzer.HeapProfileFile))
+		}
+	}
+
 	// Generate report
 	reportOpts := &reporter.Options{
-		Format:      outputFormat,
-		Threshold:   threshold,
-		Verbose:     verbose,
-		ShowDetails: verbose,
+		Format:       outputFormat,
+		Threshold:    threshold,
+		APIThreshold: apiThreshold,
+		Verbose:      verbose,
+		ShowDetails:  verbose,
+		GroupBy:      groupBy,
+
+		ShowUncoveredLines: showUncoveredLines,
+		UncoveredContext:   uncoveredContext,
+		Detail:             detail,
+		Granularity:        granularity,
+		CI:                 ci,
+		Templates:          cfg.ReportTemplates.Paths(),
+		Numbers:            numbers,
+	}
+
+	switch groupBy {
+	case "owner":
+		ownershipOpts := &analyzer.OwnershipOptions{
+			ProjectPath: projectPath,
+			Owners:      cfg.Owners,
+			Verbose:     verbose,
+		}
+		if err := analyzer.ApplyOwnership(result, ownershipOpts); err != nil {
+			return fmt.Errorf("ownership grouping failed: %w", err)
+		}
+	case "author":
+		blame = true
+	case "package", "type", "":
+	default:
+		return fmt.Errorf("unsupported --group-by value: %s (valid: package, owner, author, type)", groupBy)
+	}
+
+	if blame {
+		blameOpts := &analyzer.BlameOptions{
+			ProjectPath:    projectPath,
+			CommandTimeout: commandTimeout(cmd),
+			Verbose:        verbose,
+		}
+		if err := analyzer.ApplyBlame(cmd.Context(), result, blameOpts); err != nil {
+			return fmt.Errorf("blaming uncovered code failed: %w", err)
+		}
+	}
+
+	analyzer.ApplyAreas(result, &analyzer.AreaOptions{
+		Areas:      cfg.Areas,
+		Thresholds: cfg.AreaThresholds,
+		Verbose:    verbose,
+	})
+	analyzer.ApplyExemptions(result, exemptions, now)
+
+	if savePath != "" {
+		if err := analyzer.SaveResult(result, savePath); err != nil {
+			return err
+		}
+		if verbose {
+			i18n.Printf("💾 Analysis result saved to %s\n", savePath)
+		}
+	}
+
+	// Compare against the latest snapshot recorded before this run
+	var baseline *models.CoverageSnapshot
+	if notify {
+		if snapshots := loadHistory(projectPath, verbose); len(snapshots) > 0 {
+			baseline = snapshots[len(snapshots)-1]
+		}
+	}
+
+	if recordHistory {
+		if err := historyStore(projectPath).Save(history.NewSnapshot(result, projectPath)); err != nil {
+			return fmt.Errorf("failed to record history: %w", err)
+		}
+		if verbose {
+			i18n.Printf("🕒 Coverage snapshot recorded\n")
+		}
+	}
+
+	if strings.ToLower(outputFormat) == "html" {
+		reportOpts.History = loadHistory(projectPath, verbose)
 	}
 
 	if err := reporter.Generate(result, reportOpts); err != nil {
 		return fmt.Errorf("report generation failed: %w", err)
 	}
 
-	// Exit with error code if coverage is below threshold
-	if result.OverallCoverage < threshold {
+	if artifactsDir != "" {
+		written, err := reporter.WriteArtifacts(result, artifactsDir, reportOpts)
+		if err != nil {
+			return err
+		}
 		if verbose {
-			fmt.Printf("\n❌ Coverage %.1f%% is below threshold %.1f%%\n", result.OverallCoverage, threshold)
+			i18n.Printf("📦 Wrote %d artifacts and the profile to %s\n", len(written), artifactsDir)
 		}
-		os.Exit(1)
+	}
+
+	if notify {
+		notifyOpts := &reporter.NotifyOptions{
+			SlackWebhookURL: cfg.Notifications.SlackWebhookURL,
+			TeamsWebhookURL: cfg.Notifications.TeamsWebhookURL,
+			MessageTemplate: cfg.Notifications.MessageTemplate,
+			MaxRegressions:  cfg.Notifications.MaxRegressions,
+			Baseline:        baseline,
+			Verbose:         verbose,
+			Numbers:         numbers,
+		}
+
+		// A failed notification should not fail the analysis itself
+		if err := reporter.Notify(result, notifyOpts); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+	}
+
+	// Data-quality problems would otherwise only show in verbose output
+	if strictMode(cmd) && reportWarnings(result.Warnings) && failCode != 0 {
+		return exitWithCode(failCode, "")
+	}
+
+	// Warn about exemptions running out, and fail on expired ones whose code is still uncovered
+	if reportExemptions(result) && failCode != 0 {
+		return exitWithCode(failCode, "")
+	}
+
+	// Exit with error code if exported API coverage is below its own threshold
+	if apiThreshold > 0 && result.Summary.ExportedAPI > 0 && result.APICoverage < apiThreshold {
+		if verbose {
+			i18n.Printf("\n❌ API coverage %s is below threshold %s\n",
+				numbers.Percent(result.APICoverage), numbers.Percent(apiThreshold))
+		}
+		if failCode != 0 {
+			return exitWithCode(failCode, "")
+		}
+	}
+
+	// Gate on the change set instead of the total percentage when requested
+	if failOnNewUncovered {
+		newUncovered, err := analyzer.FindNewUncoveredFunctions(result, &analyzer.ChangeSetOptions{
+			ProjectPath:  projectPath,
+			DiffBase:     diffBase,
+			BaselinePath: baselinePath,
+		})
+		if err != nil {
+			return fmt.Errorf("change set check failed: %w", err)
+		}
+
+		if len(newUncovered) > 0 {
+			i18n.Fprintf(os.Stderr, "\n❌ %d new or modified functions are not covered by tests:\n", len(newUncovered))
+			for _, function := range newUncovered {
+				i18n.Fprintf(os.Stderr, "   • %s:%d %s\n", function.File, function.StartLine, function.Name)
+			}
+			if failCode != 0 {
+				return exitWithCode(failCode, "")
+			}
+			return nil
+		}
+
+		if verbose {
+			i18n.Printf("\n✅ All new and modified functions are covered\n")
+		}
+		return nil
+	}
+
+	// Exit with error code if any metric is below its failure threshold
+	if failures := analyzer.CheckThresholds(result, thresholds); len(failures) > 0 {
+		i18n.Fprintf(os.Stderr, "\n❌ %d coverage thresholds not met:\n", len(failures))
+		for _, failure := range failures {
+			i18n.Fprintf(os.Stderr, "   • %s\n", failure.Format(numbers.Percent))
+		}
+		if failCode != 0 {
+			return exitWithCode(failCode, "")
+		}
+		return nil
 	}
 
 	if verbose {
-		fmt.Printf("\n✅ Coverage %.1f%% meets threshold %.1f%%\n", result.OverallCoverage, threshold)
+		i18n.Printf("\n✅ Coverage %s meets threshold %s\n",
+			numbers.Percent(result.OverallCoverage), numbers.Percent(thresholds.Overall))
 	}
 
 	return nil
@@ -254,69 +1115,222 @@ func runGeneration(cmd *cobra.Command, args []string) error {
 	// Get command-line flags
 	verbose, _ := cmd.Flags().GetBool("verbose")
 	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	dryRunFormat, _ := cmd.Flags().GetString("dry-run-format")
 	templateStyle, _ := cmd.Flags().GetString("template-style")
 	generateMocks, _ := cmd.Flags().GetBool("generate-mocks")
+	mockFramework, _ := cmd.Flags().GetString("mock-framework")
 	tableDriven, _ := cmd.Flags().GetBool("table-driven")
+	parallel, _ := cmd.Flags().GetBool("parallel-tests")
 	benchmarks, _ := cmd.Flags().GetBool("benchmarks")
+	benchmarkBaseline, _ := cmd.Flags().GetBool("benchmark-baseline")
 	overwrite, _ := cmd.Flags().GetBool("overwrite")
+	backupOverwritten, _ := cmd.Flags().GetBool("backup")
+	concurrency, _ := cmd.Flags().GetInt("concurrency")
 	ignoreFunctions, _ := cmd.Flags().GetStringSlice("ignore-functions")
+	functions, _ := cmd.Flags().GetStringSlice("function")
 	maxCases, _ := cmd.Flags().GetInt("max-cases")
 	excludeDirs, _ := cmd.Flags().GetStringSlice("exclude")
+	includeUnexported, _ := cmd.Flags().GetBool("include-unexported")
+	unexportedMinComplexity, _ := cmd.Flags().GetInt("unexported-min-complexity")
+	evaluate, _ := cmd.Flags().GetBool("evaluate")
+	snapshot, _ := cmd.Flags().GetBool("snapshot")
+	skeleton, _ := cmd.Flags().GetBool("skeleton")
+	stress, _ := cmd.Flags().GetBool("stress")
+	examples, _ := cmd.Flags().GetBool("examples")
+	suiteStyle, _ := cmd.Flags().GetString("suite-style")
+	testPackageMode, _ := cmd.Flags().GetString("test-package-mode")
+	mainHarness, _ := cmd.Flags().GetBool("main-harness")
+	seed, _ := cmd.Flags().GetInt64("seed")
+	testNamePattern, _ := cmd.Flags().GetString("test-name-pattern")
+	useAI, _ := cmd.Flags().GetBool("ai")
+	aiMinComplexity, _ := cmd.Flags().GetInt("ai-min-complexity")
+
+	// Fall back to configured values when flags are not given
+	if !cmd.Flags().Changed("mock-framework") && cfg.MockFramework != "" {
+		mockFramework = cfg.MockFramework
+	}
+	if mockFramework != generator.MockFrameworkTestify && mockFramework != generator.MockFrameworkFake {
+		return fmt.Errorf("invalid --mock-framework %q (valid: testify, fake)", mockFramework)
+	}
+	switch dryRunFormat {
+	case generator.DryRunDiff, generator.DryRunSummary, generator.DryRunFull:
+	default:
+		return fmt.Errorf("invalid --dry-run-format %q (valid: diff, summary, full)", dryRunFormat)
+	}
+	if !cmd.Flags().Changed("include-unexported") {
+		includeUnexported = cfg.IncludeUnexported
+	}
+	if !cmd.Flags().Changed("backup") {
+		backupOverwritten = cfg.BackupOverwritten
+	}
+	if concurrency < 1 {
+		concurrency = cfg.MaxConcurrency
+	}
+	if !cmd.Flags().Changed("unexported-min-complexity") && cfg.UnexportedMinComplexity > 0 {
+		unexportedMinComplexity = cfg.UnexportedMinComplexity
+	}
+	if !cmd.Flags().Changed("parallel-tests") {
+		parallel = cfg.ParallelTests
+	}
+	if !cmd.Flags().Changed("benchmark-baseline") {
+		benchmarkBaseline = cfg.BenchmarkBaseline
+	}
+	if !cmd.Flags().Changed("evaluate") {
+		evaluate = cfg.EvaluateExpected
+	}
+	if !cmd.Flags().Changed("stress") {
+		stress = cfg.StressTests
+	}
+	if !cmd.Flags().Changed("examples") {
+		examples = cfg.GenerateExamples
+	}
+	if !cmd.Flags().Changed("suite-style") && cfg.SuiteStyle != "" {
+		suiteStyle = cfg.SuiteStyle
+	}
+	if !cmd.Flags().Changed("main-harness") {
+		mainHarness = cfg.MainHarness
+	}
+	if !cmd.Flags().Changed("test-package-mode") && cfg.TestPackageMode != "" {
+		testPackageMode = cfg.TestPackageMode
+	}
+	switch testPackageMode {
+	case generator.TestPackageSame, generator.TestPackageExternal, generator.TestPackageAuto:
+	default:
+		return fmt.Errorf("invalid --test-package-mode %q (valid: same, external, auto)", testPackageMode)
+	}
+	if !cmd.Flags().Changed("seed") && cfg.Seed != 0 {
+		seed = cfg.Seed
+	}
+	if !cmd.Flags().Changed("test-name-pattern") && cfg.TestNamePattern != "" {
+		testNamePattern = cfg.TestNamePattern
+	}
+	if !cmd.Flags().Changed("ai") {
+		useAI = cfg.AI.Enabled
+	}
+	if !cmd.Flags().Changed("ai-min-complexity") && cfg.AI.MinComplexity > 0 {
+		aiMinComplexity = cfg.AI.MinComplexity
+	}
+	if snapshot {
+		templateStyle = "snapshot"
+	}
+
+	var aiBackend generator.AIBackend
+	if useAI {
+		if cfg.AI.Endpoint == "" {
+			return fmt.Errorf("--ai requires ai.endpoint in the configuration")
+		}
+		aiBackend = generator.NewOpenAIBackend(cfg.AI.Endpoint, cfg.AI.Model, os.Getenv(cfg.AI.APIKeyEnv), cfg.AI.Timeout)
+	}
 
 	if verbose {
-		fmt.Printf("🛠️  Generating tests for project: %s\n", projectPath)
+		i18n.Printf("🛠️  Generating tests for project: %s\n", projectPath)
 		if dryRun {
-			fmt.Println("👀 Running in dry-run mode (no files will be written)")
+			i18n.Println("👀 Running in dry-run mode (no files will be written)")
 		}
 	}
 
 	// First run analysis to identify uncovered code
+	includePackages, excludePackages := packagePatterns(cmd)
+	classification, classes := fileClassification(cmd)
+	progress := progressFunc(cmd)
 	analyzeOpts := &analyzer.Options{
 		ProjectPath:         projectPath,
 		ExcludeDirs:         excludeDirs,
+		IncludePackages:     includePackages,
+		ExcludePackages:     excludePackages,
+		Classification:      classification,
+		Classes:             classes,
 		IncludeTests:        false, // Don't include tests in generation analysis
 		CalculateComplexity: true,
 		Verbose:             verbose,
+		CommandTimeout:      commandTimeout(cmd),
+		Progress:            progress,
+
+		IncludeUnexported:       includeUnexported,
+		UnexportedMinComplexity: unexportedMinComplexity,
+		IgnoreFunctions:         ignoreFunctions,
 	}
 
-	result, err := analyzer.Analyze(analyzeOpts)
+	result, err := analyze(cmd, analyzeOpts)
 	if err != nil {
 		return fmt.Errorf("analysis for generation failed: %w", err)
 	}
+	if reportStaleProfile(result) && requireFreshProfile(cmd) {
+		return exitWithCode(exitFailure, "")
+	}
 
 	// Configure generation options
 	genOpts := &generator.Options{
 		ProjectPath:        projectPath,
 		DryRun:             dryRun,
+		DryRunFormat:       dryRunFormat,
 		TemplateStyle:      templateStyle,
+		TemplatesDir:       cfg.GetTemplatesDir(),
 		GenerateMocks:      generateMocks,
+		MockFramework:      mockFramework,
 		TableDriven:        tableDriven,
+		ParallelTests:      parallel,
+		Skeleton:           skeleton,
 		GenerateBenchmarks: benchmarks,
+		BenchmarkBaseline:  benchmarkBaseline,
 		Overwrite:          overwrite,
+		Backup:             backupOverwritten,
 		IgnoreFunctions:    ignoreFunctions,
+		Functions:          functions,
 		MaxTestCases:       maxCases,
+		EvaluateExpected:   evaluate,
+		StressTests:        stress,
+		Examples:           examples,
+		SuiteStyle:         suiteStyle,
+		TestPackageMode:    testPackageMode,
+		MainHarness:        mainHarness,
+		Seed:               seed,
+		TestNamePattern:    testNamePattern,
+		AIBackend:          aiBackend,
+		AIMinComplexity:    aiMinComplexity,
+		MaxConcurrency:     concurrency,
+		Version:            version,
 		Verbose:            verbose,
+		CommandTimeout:     commandTimeout(cmd),
+		Progress:           progress,
 	}
 
 	// Generate tests
-	genResult, err := generator.Generate(result, genOpts)
+	genResult, err := generator.Generate(cmd.Context(), result, genOpts)
+	if genResult != nil && genResult.BackupRun != "" {
+		i18n.Fprintf(os.Stderr, "💾 Overwritten files backed up as run %s (undo with gcov restore --run %s)\n", genResult.BackupRun, genResult.BackupRun)
+	}
 	if err != nil {
+		if genResult != nil {
+			fmt.Fprintf(os.Stderr, "Kept %d test files generated before stopping\n", len(genResult.GeneratedFiles))
+		}
 		return fmt.Errorf("test generation failed: %w", err)
 	}
 
 	if verbose {
-		fmt.Printf("\n📊 Generation Summary:\n")
+		i18n.Printf("\n📊 Generation Summary:\n")
 		fmt.Printf("   Tests Generated: %d\n", genResult.TestsGenerated)
 		fmt.Printf("   Files Created:   %d\n", genResult.FilesCreated)
+		fmt.Printf("   Files Modified:  %d\n", genResult.FilesModified)
+		fmt.Printf("   Files Unchanged: %d\n", genResult.FilesUnchanged)
 		fmt.Printf("   Functions Covered: %d\n", genResult.FunctionsCovered)
+		fmt.Printf("   Seed:            %d\n", genResult.Seed)
+		fmt.Printf("   Error Paths:     %d targeted, %d unreachable by inputs\n", genResult.ErrorPathsTargeted, len(genResult.UnreachableErrorPaths))
+		if aiBackend != nil {
+			fmt.Printf("   AI Tests:        %d accepted, %d rejected\n", genResult.AITestsAccepted, genResult.AITestsRejected)
+		}
 
 		if !dryRun {
-			fmt.Printf("\n✅ Test generation completed successfully\n")
+			i18n.Printf("\n✅ Test generation completed successfully\n")
 		} else {
-			fmt.Printf("\n👀 Dry-run completed - no files were written\n")
+			i18n.Printf("\n👀 Dry-run completed - no files were written\n")
 		}
 	}
 
+	if strictMode(cmd) && reportWarnings(append(result.Warnings, genResult.Warnings...)) {
+		return exitWithCode(exitFailure, "")
+	}
+
 	return nil
 }
 
@@ -332,35 +1346,67 @@ func runValidation(cmd *cobra.Command, args []string) error {
 	_, _ = cmd.Flags().GetBool("compile-check")
 	_, _ = cmd.Flags().GetBool("run-tests")
 	_, _ = cmd.Flags().GetBool("quality-check")
+	detectFlaky, _ := cmd.Flags().GetInt("detect-flaky")
+	race, _ := cmd.Flags().GetBool("race")
+	shuffle, _ := cmd.Flags().GetBool("shuffle")
+	count, _ := cmd.Flags().GetInt("count")
+	testTimeout, _ := cmd.Flags().GetDuration("test-timeout")
+	runPattern, _ := cmd.Flags().GetString("run")
+	testFlags, _ := cmd.Flags().GetStringSlice("test-flags")
+	maxWarnings, _ := cmd.Flags().GetInt("max-warnings")
+	outputFormat, _ := cmd.Flags().GetString("output")
+	outputFile, _ := cmd.Flags().GetString("output-file")
+	includePackages, excludePackages := packagePatterns(cmd)
+
+	// Fall back to configured go test settings when flags are not given
+	if !cmd.Flags().Changed("race") {
+		race = cfg.Validation.Race
+	}
+	if !cmd.Flags().Changed("count") && cfg.Validation.Count > 0 {
+		count = cfg.Validation.Count
+	}
+	if !cmd.Flags().Changed("test-timeout") && cfg.Validation.Timeout > 0 {
+		testTimeout = cfg.Validation.Timeout
+	}
+	if !cmd.Flags().Changed("run") {
+		runPattern = cfg.Validation.Run
+	}
+	testFlags = append(append([]string{}, cfg.Validation.TestFlags...), testFlags...)
 
 	if verbose {
-		fmt.Printf("🔍 Validating tests in project: %s\n", projectPath)
+		i18n.Printf("🔍 Validating tests in project: %s\n", projectPath)
 		if testFile != "" {
-			fmt.Printf("🔍 Focusing on test file: %s\n", testFile)
+			i18n.Printf("🔍 Focusing on test file: %s\n", testFile)
 		}
 	}
 
 	// Create validator
 	validator := generator.NewTestValidator(projectPath, verbose)
+	validator.SetOptions(&generator.ValidationOptions{
+		DetectFlaky: detectFlaky,
+		Race:        race,
+		Shuffle:     shuffle,
+		Count:       count,
+		Timeout:     testTimeout,
+		Run:         runPattern,
+		ExtraFlags:  testFlags,
+		Smells: generator.SmellOptions{
+			MaxAssertions: cfg.TestLint.MaxAssertions,
+			MaxTestLines:  cfg.TestLint.MaxTestLines,
+		},
+
+		CommandTimeout: commandTimeout(cmd),
+	})
+
+	var validationResult *generator.ValidationResult
+	var err error
 
 	if testFile != "" {
 		// Validate specific test file
-		result, err := validator.ValidateIndividualTest(testFile)
+		validationResult, err = validator.ValidateIndividualTest(cmd.Context(), testFile)
 		if err != nil {
 			return fmt.Errorf("validation failed: %w", err)
 		}
-
-		if verbose {
-			fmt.Println(validator.GetValidationSummary(result))
-		}
-
-		if !result.Valid {
-			return fmt.Errorf("test validation failed")
-		}
-
-		if verbose {
-			fmt.Println("✅ Test validation passed")
-		}
 	} else {
 		// Create a dummy generation result to validate all test files
 		result := &models.GenerationResult{
@@ -368,55 +1414,164 @@ func runValidation(cmd *cobra.Command, args []string) error {
 		}
 
 		// Find all test files in the project
-		err := filepath.Walk(projectPath, func(path string, info os.FileInfo, err error) error {
-			if err != nil {
-				return err
-			}
-
-			if strings.HasSuffix(info.Name(), "_test.go") {
-				relativePath, err := filepath.Rel(projectPath, path)
-				if err != nil {
-					relativePath = path
-				}
-
-				result.GeneratedFiles = append(result.GeneratedFiles, &models.GeneratedFile{
-					Path: relativePath,
-				})
-			}
-			return nil
-		})
-
+		testFiles, err := findTestFiles(projectPath, includePackages, excludePackages)
 		if err != nil {
-			return fmt.Errorf("failed to find test files: %w", err)
+			return err
+		}
+		for _, testFile := range testFiles {
+			result.GeneratedFiles = append(result.GeneratedFiles, &models.GeneratedFile{
+				Path: testFile,
+			})
 		}
 
 		if len(result.GeneratedFiles) == 0 {
 			if verbose {
-				fmt.Println("⚠️ No test files found in project")
+				i18n.Println("⚠️ No test files found in project")
 			}
 			return nil
 		}
 
 		// Run validation
-		validationResult, err := validator.ValidateTests(result)
+		validationResult, err = validator.ValidateTests(cmd.Context(), result)
 		if err != nil {
 			return fmt.Errorf("validation failed: %w", err)
 		}
+	}
 
+	// Output the result
+	if outputFormat == "" || outputFormat == "console" {
 		if verbose {
 			fmt.Println(validator.GetValidationSummary(validationResult))
 		}
-
-		if !validationResult.Valid {
-			return fmt.Errorf("test validation failed")
+	} else {
+		reportOpts := &reporter.Options{
+			Format:     outputFormat,
+			OutputFile: outputFile,
+			Verbose:    verbose,
+			Numbers:    numbers,
 		}
 
-		if verbose {
-			fmt.Printf("✅ All %d test files validated successfully\n", len(result.GeneratedFiles))
+		if err := reporter.GenerateValidationReport(validationResult, reportOpts); err != nil {
+			return fmt.Errorf("validation report failed: %w", err)
 		}
 	}
 
-	return nil
+	// Exit with a code that identifies the failing validation stage
+	if code, reason := validationExitCode(validationResult, maxWarnings); code != 0 {
+		return exitWithCode(code, "test validation failed: %s", reason)
+	}
+
+	if verbose {
+		i18n.Println("✅ Test validation passed")
+	}
+
+	return nil
+}
+
+// findTestFiles returns the test files of a project, relative to it, in packages the patterns select
+func findTestFiles(projectPath string, includePackages, excludePackages []string) ([]string, error) {
+	testFiles := make([]string, 0)
+	err := filepath.Walk(projectPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() && path != projectPath && strings.HasPrefix(info.Name(), ".") {
+			return filepath.SkipDir
+		}
+
+		if strings.HasSuffix(info.Name(), "_test.go") {
+			relativePath, err := filepath.Rel(projectPath, path)
+			if err != nil {
+				relativePath = path
+			}
+			if coverage.MatchPackages(filepath.Dir(relativePath), includePackages, excludePackages) {
+				testFiles = append(testFiles, relativePath)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find test files: %w", err)
+	}
+	return testFiles, nil
+}
+
+func runLintTests(cmd *cobra.Command, args []string) error {
+	projectPath := "."
+	if len(args) > 0 {
+		projectPath = args[0]
+	}
+
+	verbose, _ := cmd.Flags().GetBool("verbose")
+	outputFormat, _ := cmd.Flags().GetString("output")
+	outputFile, _ := cmd.Flags().GetString("output-file")
+	maxAssertions, _ := cmd.Flags().GetInt("max-assertions")
+	maxTestLines, _ := cmd.Flags().GetInt("max-test-lines")
+	failOn, _ := cmd.Flags().GetString("fail-on")
+	includePackages, excludePackages := packagePatterns(cmd)
+
+	// Fall back to configured thresholds when flags are not given
+	if !cmd.Flags().Changed("max-assertions") && cfg.TestLint.MaxAssertions > 0 {
+		maxAssertions = cfg.TestLint.MaxAssertions
+	}
+	if !cmd.Flags().Changed("max-test-lines") && cfg.TestLint.MaxTestLines > 0 {
+		maxTestLines = cfg.TestLint.MaxTestLines
+	}
+	if !cmd.Flags().Changed("fail-on") && cfg.TestLint.FailOn != "" {
+		failOn = cfg.TestLint.FailOn
+	}
+	if failOn != "none" && generator.SeverityRank(failOn) == 0 {
+		return fmt.Errorf("invalid --fail-on: %s (must be error, warning, info or none)", failOn)
+	}
+
+	testFiles, err := findTestFiles(projectPath, includePackages, excludePackages)
+	if err != nil {
+		return err
+	}
+	if verbose {
+		i18n.Printf("🔍 Checking %d test files for test smells\n", len(testFiles))
+	}
+
+	report := generator.LintTests(projectPath, testFiles, &generator.SmellOptions{
+		MaxAssertions: maxAssertions,
+		MaxTestLines:  maxTestLines,
+	})
+
+	if err := reporter.GenerateTestLintReport(report, &reporter.Options{
+		Format:     outputFormat,
+		OutputFile: outputFile,
+		Verbose:    verbose,
+		Numbers:    numbers,
+	}); err != nil {
+		return fmt.Errorf("test lint report failed: %w", err)
+	}
+
+	if failOn != "none" {
+		if count := report.Count(failOn); count > 0 {
+			i18n.Fprintf(os.Stderr, "❌ %d test smells of severity %s or above\n", count, failOn)
+			return exitWithCode(exitFailure, "")
+		}
+	}
+
+	return nil
+}
+
+// validationExitCode maps a validation result to the validate command's exit code
+func validationExitCode(result *generator.ValidationResult, maxWarnings int) (int, string) {
+	switch {
+	case len(result.SyntaxErrors) > 0:
+		return exitSyntaxError, fmt.Sprintf("%d syntax errors", len(result.SyntaxErrors))
+	case len(result.CompileErrors) > 0:
+		return exitCompileError, fmt.Sprintf("%d compile errors", len(result.CompileErrors))
+	case len(result.RuntimeErrors) > 0:
+		return exitRuntimeError, fmt.Sprintf("%d runtime errors", len(result.RuntimeErrors))
+	case !result.Valid:
+		return exitRuntimeError, "tests did not pass"
+	case maxWarnings >= 0 && len(result.Warnings) > maxWarnings:
+		return exitWarningsExceeded, fmt.Sprintf("%d warnings exceed the maximum of %d", len(result.Warnings), maxWarnings)
+	}
+
+	return 0, ""
 }
 
 func runReporting(cmd *cobra.Command, args []string) error {
@@ -429,12 +1584,45 @@ func runReporting(cmd *cobra.Command, args []string) error {
 	verbose, _ := cmd.Flags().GetBool("verbose")
 	outputFormat, _ := cmd.Flags().GetString("output")
 	inputFile, _ := cmd.Flags().GetString("input")
+	fromFile, _ := cmd.Flags().GetString("from")
+	if fromFile != "" && cmd.Flags().Changed("input") {
+		return fmt.Errorf("--from and --input are mutually exclusive")
+	}
 	outputFile, _ := cmd.Flags().GetString("output-file")
 	openReport, _ := cmd.Flags().GetBool("open")
+	serveAddr, _ := cmd.Flags().GetString("serve")
 	threshold, _ := cmd.Flags().GetFloat64("threshold")
+	showUncoveredLines, _ := cmd.Flags().GetBool("show-uncovered-lines")
+	uncoveredContext, _ := cmd.Flags().GetInt("uncovered-context")
+	detail, _ := cmd.Flags().GetString("detail")
+	if detail != reporter.DetailFunctions && detail != reporter.DetailBlocks {
+		return fmt.Errorf("invalid --detail %q (valid: functions, blocks)", detail)
+	}
+	granularity, _ := cmd.Flags().GetString("granularity")
+	if !reporter.ValidGranularity(granularity) {
+		return fmt.Errorf("invalid --granularity %q (valid: package, file, function)", granularity)
+	}
+	ci := ciServer(cmd)
+	if !reporter.ValidCI(ci) {
+		return fmt.Errorf("invalid --ci %q (valid: teamcity, bamboo)", ci)
+	}
+	includePackages, excludePackages := packagePatterns(cmd)
+	classification, classes := fileClassification(cmd)
+	streamProfile, _ := cmd.Flags().GetBool("stream-profile")
+	if !cmd.Flags().Changed("stream-profile") {
+		streamProfile = cfg.StreamProfile
+	}
+	includeInit, _ := cmd.Flags().GetBool("include-init")
+	if !cmd.Flags().Changed("include-init") {
+		includeInit = cfg.IncludeInit
+	}
 
 	if verbose {
-		fmt.Printf("📋 Generating report from: %s\n", inputFile)
+		source := inputFile
+		if fromFile != "" {
+			source = fromFile
+		}
+		i18n.Printf("📋 Generating report from: %s\n", source)
 	}
 
 	// Configure reporting options
@@ -446,16 +1634,1365 @@ func runReporting(cmd *cobra.Command, args []string) error {
 		Threshold:   threshold,
 		Verbose:     verbose,
 		ShowDetails: verbose,
+
+		ShowUncoveredLines: showUncoveredLines,
+		UncoveredContext:   uncoveredContext,
+		Detail:             detail,
+		Granularity:        granularity,
+		CI:                 ci,
+		IncludePackages:    includePackages,
+		ExcludePackages:    excludePackages,
+		Classification:     classification,
+		Classes:            classes,
+		StreamProfile:      streamProfile,
+		IncludeInit:        includeInit,
+		IncludeCgo:         includeCgo(cmd),
+		CommandTimeout:     commandTimeout(cmd),
+		Templates:          cfg.ReportTemplates.Paths(),
+		HighComplexity:     complexityWarn(cmd),
+		MaxFunctionLines:   cfg.FunctionLimits.MaxLines,
+		MaxParams:          cfg.FunctionLimits.MaxParams,
+		Numbers:            numbers,
+	}
+
+	if strings.ToLower(outputFormat) == "html" || serveAddr != "" {
+		reportOpts.History = loadHistory(projectPath, verbose)
+	}
+
+	if serveAddr != "" {
+		return serveReport(cmd, projectPath, serveAddr, fromFile, reportOpts)
+	}
+
+	// Report a saved analysis result as it is, without parsing the project
+	if fromFile != "" {
+		if !cmd.Flags().Changed("complexity-warn") {
+			reportOpts.HighComplexity = 0
+		}
+		result, err := analyzer.LoadResult(fromFile)
+		if err != nil {
+			return fmt.Errorf("failed to load analysis result: %w", err)
+		}
+		if err := reporter.Generate(result, reportOpts); err != nil {
+			return fmt.Errorf("report generation failed: %w", err)
+		}
+		if reportStaleProfile(result) && requireFreshProfile(cmd) {
+			return exitWithCode(exitFailure, "")
+		}
+		if strictMode(cmd) && reportWarnings(result.Warnings) {
+			return exitWithCode(exitFailure, "")
+		}
+		return nil
 	}
 
 	// Generate report from existing coverage data
-	if err := reporter.GenerateFromProfile(projectPath, reportOpts); err != nil {
+	result, err := reporter.GenerateFromProfile(cmd.Context(), projectPath, reportOpts)
+	if err != nil {
 		return fmt.Errorf("report generation failed: %w", err)
 	}
+	if reportStaleProfile(result) && requireFreshProfile(cmd) {
+		return exitWithCode(exitFailure, "")
+	}
+	if strictMode(cmd) && reportWarnings(result.Warnings) {
+		return exitWithCode(exitFailure, "")
+	}
+
+	if verbose {
+		i18n.Printf("✅ Report generated successfully\n")
+	}
+
+	return nil
+}
+
+// serveReport serves the HTML report of a saved result, or of the project with its profile, until
+// interrupted, analyzing again whenever the result or profile changes
+func serveReport(cmd *cobra.Command, projectPath, addr, fromFile string, reportOpts *reporter.Options) error {
+	watch := fromFile
+	analyze := func(ctx context.Context) (*models.AnalysisResult, error) {
+		return analyzer.LoadResult(fromFile)
+	}
+	if fromFile == "" {
+		watch = reportOpts.InputFile
+		if !filepath.IsAbs(watch) {
+			watch = filepath.Join(projectPath, watch)
+		}
+		analyze = func(ctx context.Context) (*models.AnalysisResult, error) {
+			return reporter.AnalyzeProfile(ctx, projectPath, reportOpts)
+		}
+	} else if !cmd.Flags().Changed("complexity-warn") {
+		reportOpts.HighComplexity = 0
+	}
+
+	return dashboard.Serve(cmd.Context(), &dashboard.Options{
+		Addr:    addr,
+		Watch:   watch,
+		Analyze: analyze,
+		Report:  reportOpts,
+		Open:    reportOpts.OpenReport,
+	})
+}
+
+func runComparison(cmd *cobra.Command, args []string) error {
+	projectPath := "."
+	if len(args) > 0 {
+		projectPath = args[0]
+	}
+
+	// Get command-line flags
+	verbose, _ := cmd.Flags().GetBool("verbose")
+	excludeDirs, _ := cmd.Flags().GetStringSlice("exclude")
+	threshold, _ := cmd.Flags().GetFloat64("threshold")
+	against, _ := cmd.Flags().GetString("against")
+	packagePattern, _ := cmd.Flags().GetString("package")
+	generateProfile, _ := cmd.Flags().GetBool("profile")
+
+	opts := &analyzer.Options{
+		ProjectPath:         projectPath,
+		ExcludeDirs:         excludeDirs,
+		PackagePattern:      packagePattern,
+		GenerateProfile:     generateProfile,
+		CalculateComplexity: true,
+		Verbose:             verbose,
+		CommandTimeout:      commandTimeout(cmd),
+		IgnoreFunctions:     cfg.IgnoreFunctions,
+		IncludeCgo:          cfg.IncludeCgo,
+	}
+
+	current, err := analyze(cmd, opts)
+	if err != nil {
+		return fmt.Errorf("analysis failed: %w", err)
+	}
+
+	// The ref's profile is always fresh, so a missing one or one measuring older sources would skew the comparison
+	if current.Metadata.ProfilePath == "" {
+		return fmt.Errorf("no coverage profile found in %s; generate one or drop --profile=false", projectPath)
+	}
+	if stale := current.StaleProfile; stale != nil {
+		return fmt.Errorf("%s is older than %d source files modified since, such as %s; regenerate it or drop --profile=false",
+			filepath.Base(current.Metadata.ProfilePath), len(stale.ModifiedFiles), stale.ModifiedFiles[0])
+	}
+
+	if verbose {
+		i18n.Printf("🔀 Analyzing %s for comparison\n", against)
+	}
+	previous, err := analyzer.AnalyzeRevision(cmd.Context(), opts, against)
+	if err != nil {
+		return err
+	}
+
+	reportOpts := &reporter.Options{
+		Threshold: threshold,
+		Verbose:   verbose,
+		Numbers:   numbers,
+	}
+
+	if err := reporter.GenerateComparisonReport(current, previous, reportOpts); err != nil {
+		return fmt.Errorf("comparison report failed: %w", err)
+	}
+
+	return nil
+}
+
+func runPrioritization(cmd *cobra.Command, args []string) error {
+	projectPath := "."
+	if len(args) > 0 {
+		projectPath = args[0]
+	}
+
+	// Get command-line flags
+	verbose, _ := cmd.Flags().GetBool("verbose")
+	outputFormat, _ := cmd.Flags().GetString("output")
+	outputFile, _ := cmd.Flags().GetString("output-file")
+	excludeDirs, _ := cmd.Flags().GetStringSlice("exclude")
+	threshold, _ := cmd.Flags().GetFloat64("threshold")
+	packagePattern, _ := cmd.Flags().GetString("package")
+	generateProfile, _ := cmd.Flags().GetBool("profile")
+	limit, _ := cmd.Flags().GetInt("limit")
+
+	opts := &analyzer.Options{
+		ProjectPath:         projectPath,
+		ExcludeDirs:         excludeDirs,
+		PackagePattern:      packagePattern,
+		GenerateProfile:     generateProfile,
+		CalculateComplexity: true,
+		Verbose:             verbose,
+		CommandTimeout:      commandTimeout(cmd),
+	}
+
+	result, err := analyze(cmd, opts)
+	if err != nil {
+		return fmt.Errorf("analysis failed: %w", err)
+	}
+
+	priorities := analyzer.PrioritizeTests(result, limit)
+
+	reportOpts := &reporter.Options{
+		Format:     outputFormat,
+		OutputFile: outputFile,
+		Threshold:  threshold,
+		Verbose:    verbose,
+		Numbers:    numbers,
+	}
+
+	if err := reporter.GeneratePrioritizationReport(priorities, result, reportOpts); err != nil {
+		return fmt.Errorf("prioritization report failed: %w", err)
+	}
+
+	return nil
+}
+
+func runMutation(cmd *cobra.Command, args []string) error {
+	projectPath := "."
+	if len(args) > 0 {
+		projectPath = args[0]
+	}
+
+	// Get command-line flags
+	verbose, _ := cmd.Flags().GetBool("verbose")
+	outputFormat, _ := cmd.Flags().GetString("output")
+	outputFile, _ := cmd.Flags().GetString("output-file")
+	excludeDirs, _ := cmd.Flags().GetStringSlice("exclude")
+	threshold, _ := cmd.Flags().GetFloat64("threshold")
+	packagePattern, _ := cmd.Flags().GetString("package")
+	generateProfile, _ := cmd.Flags().GetBool("profile")
+	concurrency, _ := cmd.Flags().GetInt("concurrency")
+	maxMutants, _ := cmd.Flags().GetInt("max-mutants")
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+
+	if concurrency < 1 {
+		concurrency = cfg.MaxConcurrency
+	}
+
+	result, err := analyzer.Analyze(cmd.Context(), &analyzer.Options{
+		ProjectPath:         projectPath,
+		ExcludeDirs:         excludeDirs,
+		PackagePattern:      packagePattern,
+		GenerateProfile:     generateProfile,
+		CalculateComplexity: true,
+		Verbose:             verbose,
+		CommandTimeout:      commandTimeout(cmd),
+	})
+	if err != nil {
+		return fmt.Errorf("analysis for mutation testing failed: %w", err)
+	}
+
+	if result.Metadata.ProfilePath == "" {
+		return fmt.Errorf("mutation testing requires coverage data; run with --profile or provide coverage.out")
+	}
 
 	if verbose {
-		fmt.Printf("✅ Report generated successfully\n")
+		i18n.Printf("🧬 Running mutation testing with %d workers\n", concurrency)
+	}
+
+	report, err := mutator.Run(cmd.Context(), result, &mutator.Options{
+		ProjectPath: projectPath,
+		Concurrency: concurrency,
+		MaxMutants:  maxMutants,
+		Timeout:     timeout,
+		Verbose:     verbose,
+	})
+	if err != nil {
+		return fmt.Errorf("mutation testing failed: %w", err)
+	}
+
+	reportOpts := &reporter.Options{
+		Format:     outputFormat,
+		OutputFile: outputFile,
+		Threshold:  threshold,
+		Verbose:    verbose,
+		Numbers:    numbers,
+	}
+
+	if err := reporter.GenerateMutationReport(report, reportOpts); err != nil {
+		return fmt.Errorf("mutation report failed: %w", err)
+	}
+
+	return nil
+}
+
+func runHistoryRecord(cmd *cobra.Command, args []string) error {
+	projectPath := "."
+	if len(args) > 0 {
+		projectPath = args[0]
+	}
+
+	// Get command-line flags
+	verbose, _ := cmd.Flags().GetBool("verbose")
+	excludeDirs, _ := cmd.Flags().GetStringSlice("exclude")
+	packagePattern, _ := cmd.Flags().GetString("package")
+	generateProfile, _ := cmd.Flags().GetBool("profile")
+
+	result, err := analyzer.Analyze(cmd.Context(), &analyzer.Options{
+		ProjectPath:     projectPath,
+		ExcludeDirs:     excludeDirs,
+		PackagePattern:  packagePattern,
+		GenerateProfile: generateProfile,
+		Verbose:         verbose,
+		CommandTimeout:  commandTimeout(cmd),
+	})
+	if err != nil {
+		return fmt.Errorf("analysis failed: %w", err)
+	}
+
+	store := historyStore(projectPath)
+	snapshot := history.NewSnapshot(result, projectPath)
+	if err := store.Save(snapshot); err != nil {
+		return fmt.Errorf("failed to record history: %w", err)
+	}
+
+	i18n.Printf("🕒 Recorded coverage snapshot %s in %s\n", numbers.Percent(snapshot.OverallCoverage), store.Dir())
+	return nil
+}
+
+func runHistoryChart(cmd *cobra.Command, args []string) error {
+	projectPath := "."
+	if len(args) > 0 {
+		projectPath = args[0]
+	}
+
+	// Get command-line flags
+	verbose, _ := cmd.Flags().GetBool("verbose")
+	outputFile, _ := cmd.Flags().GetString("output")
+	byTag, _ := cmd.Flags().GetBool("by-tag")
+
+	snapshots, err := historyStore(projectPath).Load()
+	if err != nil {
+		return fmt.Errorf("failed to load history: %w", err)
+	}
+
+	if byTag {
+		snapshots = history.FilterTagged(snapshots)
+	}
+
+	reportOpts := &reporter.Options{
+		OutputFile: outputFile,
+		Verbose:    verbose,
+		Numbers:    numbers,
+	}
+
+	if err := reporter.GenerateTimelineChart(snapshots, reportOpts); err != nil {
+		return fmt.Errorf("chart generation failed: %w", err)
 	}
 
 	return nil
 }
+
+func runPrune(cmd *cobra.Command, args []string) error {
+	projectPath := "."
+	if len(args) > 0 {
+		projectPath = args[0]
+	}
+
+	verbose, _ := cmd.Flags().GetBool("verbose")
+	excludeDirs, _ := cmd.Flags().GetStringSlice("exclude")
+	deleteStale, _ := cmd.Flags().GetBool("delete")
+	attributeTests, _ := cmd.Flags().GetBool("attribute-tests")
+
+	result, err := analyzer.Analyze(cmd.Context(), &analyzer.Options{
+		ProjectPath:    projectPath,
+		ExcludeDirs:    excludeDirs,
+		Verbose:        verbose,
+		CommandTimeout: commandTimeout(cmd),
+		AttributeTests: attributeTests,
+		Progress:       progressFunc(cmd),
+	})
+	if err != nil {
+		return fmt.Errorf("analysis for pruning failed: %w", err)
+	}
+
+	pruneResult, err := generator.Prune(result, &generator.PruneOptions{
+		ProjectPath: projectPath,
+		ExcludeDirs: excludeDirs,
+		Delete:      deleteStale,
+		Verbose:     verbose,
+	})
+	if err != nil {
+		return fmt.Errorf("prune failed: %w", err)
+	}
+
+	if len(pruneResult.Stale) == 0 {
+		i18n.Printf("✅ All %d generated tests match their targets\n", pruneResult.Scanned)
+		return nil
+	}
+
+	for _, stale := range pruneResult.Stale {
+		i18n.Printf("   • %s:%d %s: %s\n", stale.File, stale.Line, stale.TestName, stale.Reason())
+	}
+
+	if deleteStale {
+		i18n.Printf("🧹 Deleted %d stale generated tests\n", pruneResult.Deleted)
+		for _, file := range pruneResult.DeletedFiles {
+			fmt.Printf("   Removed empty test file %s\n", file)
+		}
+		return nil
+	}
+
+	i18n.Fprintf(os.Stderr, "\n❌ %d of %d generated tests are stale (rerun with --delete to remove them)\n", len(pruneResult.Stale), pruneResult.Scanned)
+	return exitWithCode(exitFailure, "")
+}
+
+func runAffected(cmd *cobra.Command, args []string) error {
+	projectPath := "."
+	if len(args) > 0 {
+		projectPath = args[0]
+	}
+
+	verbose, _ := cmd.Flags().GetBool("verbose")
+	excludeDirs, _ := cmd.Flags().GetStringSlice("exclude")
+	diffBase, _ := cmd.Flags().GetString("diff-base")
+	matrixPath, _ := cmd.Flags().GetString("matrix")
+	run, _ := cmd.Flags().GetBool("run")
+
+	result, err := analyzer.Analyze(cmd.Context(), &analyzer.Options{
+		ProjectPath:    projectPath,
+		ExcludeDirs:    excludeDirs,
+		Verbose:        verbose,
+		CommandTimeout: commandTimeout(cmd),
+		AttributeTests: matrixPath == "",
+		Progress:       progressFunc(cmd),
+	})
+	if err != nil {
+		return fmt.Errorf("analysis for affected tests failed: %w", err)
+	}
+	if matrixPath != "" {
+		if result.TestMatrix, err = analyzer.LoadTestMatrix(matrixPath); err != nil {
+			return err
+		}
+	}
+
+	affected, err := analyzer.FindAffectedTests(result, &analyzer.AffectedOptions{
+		ProjectPath: projectPath,
+		DiffBase:    diffBase,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to find affected tests: %w", err)
+	}
+
+	if verbose {
+		for _, function := range affected.ChangedFunctions {
+			fmt.Printf("   changed %s (%s:%d)\n", function.Key(), function.File, function.StartLine)
+		}
+	}
+	if len(affected.Tests) == 0 {
+		i18n.Printf("✅ No tests are affected by the %d changed functions since %s\n", len(affected.ChangedFunctions), diffBase)
+		return nil
+	}
+
+	goArgs := affected.GoTestArgs()
+	if !run {
+		quoted := append([]string{"go"}, goArgs...)
+		quoted[len(quoted)-1] = "'" + quoted[len(quoted)-1] + "'"
+		fmt.Println(strings.Join(quoted, " "))
+		return nil
+	}
+
+	i18n.Printf("🧪 Running %d affected tests in %d packages\n", len(affected.Tests), len(affected.Packages))
+	goTest := command.Go(cmd.Context(), goArgs...)
+	goTest.Dir = projectPath
+	goTest.Stdout = os.Stdout
+	goTest.Stderr = os.Stderr
+	if err := goTest.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return exitWithCode(exitErr.ExitCode(), "")
+		}
+		return fmt.Errorf("failed to run affected tests: %w", err)
+	}
+	return nil
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	projectPath := "."
+	if len(args) > 0 {
+		projectPath = args[0]
+	}
+
+	i18n.Printf("🩺 Checking the gcov environment for %s\n", projectPath)
+	checks := doctor.Run(cmd.Context(), &doctor.Options{
+		ProjectPath:    projectPath,
+		Config:         cfg,
+		ConfigErr:      cfgErr,
+		CommandTimeout: commandTimeout(cmd),
+	})
+
+	for _, check := range checks {
+		// The status marker stays in the format, where plain mode replaces it with an ASCII tag
+		format := "✅ %s: %s\n"
+		switch check.Status {
+		case doctor.StatusWarn:
+			format = "⚠️ %s: %s\n"
+		case doctor.StatusFail:
+			format = "❌ %s: %s\n"
+		}
+		i18n.Printf(format, check.Name, strings.ReplaceAll(check.Detail, "\n", "\n      "))
+		if check.Fix != "" {
+			i18n.Printf("   → %s\n", check.Fix)
+		}
+	}
+
+	if doctor.Failed(checks) {
+		return exitWithCode(exitFailure, "")
+	}
+	return nil
+}
+
+func runHookInstall(cmd *cobra.Command, args []string) error {
+	projectPath := "."
+	if len(args) > 0 {
+		projectPath = args[0]
+	}
+
+	hookType, _ := cmd.Flags().GetString("type")
+	force, _ := cmd.Flags().GetBool("force")
+
+	hookPath, err := hook.Install(projectPath, hookType, force)
+	if err != nil {
+		return fmt.Errorf("hook installation failed: %w", err)
+	}
+
+	i18n.Printf("🪝 Installed %s hook %s\n", hookType, hookPath)
+	return nil
+}
+
+func runHookRun(cmd *cobra.Command, args []string) error {
+	verbose, _ := cmd.Flags().GetBool("verbose")
+	excludeDirs, _ := cmd.Flags().GetStringSlice("exclude")
+	projectPath, _ := cmd.Flags().GetString("project")
+	stage, _ := cmd.Flags().GetString("stage")
+	diffBase, _ := cmd.Flags().GetString("diff-base")
+	if !cmd.Flags().Changed("diff-base") && cfg.Hook.DiffBase != "" {
+		diffBase = cfg.Hook.DiffBase
+	}
+	threshold, _ := cmd.Flags().GetFloat64("threshold")
+	if !cmd.Flags().Changed("threshold") {
+		threshold = cfg.Hook.Threshold
+	}
+	if stage != hook.PreCommit && stage != hook.PrePush {
+		return fmt.Errorf("invalid --stage %q (valid: pre-commit, pre-push)", stage)
+	}
+
+	// Frameworks pass the files relative to the working directory; the diff names them relative to the project
+	files := make([]string, 0, len(args))
+	for _, file := range args {
+		if !strings.HasSuffix(file, ".go") {
+			continue
+		}
+		if rel, err := relativeTo(projectPath, file); err == nil {
+			files = append(files, rel)
+		}
+	}
+	if len(args) > 0 && len(files) == 0 {
+		return nil
+	}
+
+	changeSet, err := analyzer.LoadChangeSet(&analyzer.DiffCoverageOptions{
+		ProjectPath: projectPath,
+		Staged:      stage == hook.PreCommit,
+		DiffBase:    diffBase,
+		Files:       files,
+	})
+	if err != nil {
+		// A branch pushed for the first time has no upstream to diff against yet
+		if stage == hook.PrePush && !cmd.Flags().Changed("diff-base") {
+			i18n.Fprintf(os.Stderr, "⚠️ Skipping the coverage check: %v\n", err)
+			return nil
+		}
+		return fmt.Errorf("failed to read the changes: %w", err)
+	}
+
+	packages := changeSet.Packages(projectPath)
+	if len(packages) == 0 {
+		if verbose {
+			i18n.Println("✅ No Go packages changed")
+		}
+		return nil
+	}
+
+	// The profile of a partial run must not replace the project's coverage.out
+	profile, err := os.CreateTemp("", "gcov-hook-*.out")
+	if err != nil {
+		return fmt.Errorf("failed to create coverage profile: %w", err)
+	}
+	profile.Close()
+	defer os.Remove(profile.Name())
+
+	i18n.Printf("🪝 Checking coverage of the changes in %d packages\n", len(packages))
+	result, err := analyzer.Analyze(cmd.Context(), &analyzer.Options{
+		ProjectPath:     projectPath,
+		ExcludeDirs:     excludeDirs,
+		IncludePackages: packages,
+		PackagePattern:  strings.Join(packages, " "),
+		GenerateProfile: true,
+		ProfileOutput:   profile.Name(),
+		Verbose:         verbose,
+		CommandTimeout:  commandTimeout(cmd),
+	})
+	if err != nil {
+		return fmt.Errorf("analysis of the changes failed: %w", err)
+	}
+
+	diff := changeSet.Coverage(result)
+	if diff.Coverage < threshold {
+		i18n.Fprintf(os.Stderr, "❌ Changed code is %s covered, below the threshold of %s\n",
+			numbers.Percent(diff.Coverage), numbers.Percent(threshold))
+		for _, function := range diff.Uncovered {
+			i18n.Fprintf(os.Stderr, "   • %s:%d %s\n", function.File, function.StartLine, function.Name)
+		}
+		return exitWithCode(exitFailure, "")
+	}
+
+	i18n.Printf("✅ Changed code is %s covered (%d functions, threshold %s)\n",
+		numbers.Percent(diff.Coverage), len(diff.Functions), numbers.Percent(threshold))
+	return nil
+}
+
+// printSelfProfile prints where the analysis spent its time and memory to stderr, keeping stdout for the report
+func printSelfProfile(profile *models.SelfProfile) {
+	i18n.Fprintf(os.Stderr, "⏱️ Analysis took %v, peak heap %.1f MiB, %.1f MiB allocated, %d GC cycles\n",
+		profile.Total.Round(time.Millisecond), float64(profile.PeakHeapBytes)/(1<<20),
+		float64(profile.TotalAllocated)/(1<<20), profile.GCCycles)
+	for _, phase := range profile.Phases {
+		share := 0.0
+		if profile.Total > 0 {
+			share = float64(phase.Duration) / float64(profile.Total) * 100
+		}
+		fmt.Fprintf(os.Stderr, "   %-15s %10v %5.1f%%\n", phase.Name, phase.Duration.Round(time.Millisecond), share)
+	}
+}
+
+// relativeTo returns a path, relative to the working directory, relative to the project instead
+func relativeTo(projectPath, path string) (string, error) {
+	absProject, err := filepath.Abs(projectPath)
+	if err != nil {
+		return "", err
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Rel(absProject, absPath)
+}
+
+func runFuncAt(cmd *cobra.Command, args []string) error {
+	path, lineText, ok := strings.Cut(args[0], ":")
+	line, err := strconv.Atoi(lineText)
+	if !ok || err != nil || line < 1 {
+		return fmt.Errorf("invalid location %q (expected file.go:line)", args[0])
+	}
+
+	verbose, _ := cmd.Flags().GetBool("verbose")
+	outputFormat, _ := cmd.Flags().GetString("output")
+	threshold, _ := cmd.Flags().GetFloat64("threshold")
+	input, _ := cmd.Flags().GetString("input")
+	if outputFormat != "console" && outputFormat != "json" {
+		return fmt.Errorf("unsupported output format for func-at: %s (valid: console, json)", outputFormat)
+	}
+
+	projectPath, file, err := analysisScope(path)
+	if err != nil {
+		return err
+	}
+	if file == "" {
+		return fmt.Errorf("%s is not a Go file", path)
+	}
+
+	opts := &analyzer.Options{
+		ProjectPath:         projectPath,
+		ProfilePath:         input,
+		Scope:               file,
+		CalculateComplexity: true,
+		HighComplexity:      cfg.HighComplexityThreshold,
+		Verbose:             verbose,
+		CommandTimeout:      commandTimeout(cmd),
+	}
+	result, err := analyze(cmd, opts)
+	if err != nil {
+		return fmt.Errorf("analysis failed: %w", err)
+	}
+
+	at, declared := reporter.FunctionAt(result, file, line)
+	if at == nil {
+		return fmt.Errorf("no function encloses %s:%d", path, line)
+	}
+
+	// Tests are generated for the declared function, also when the line is in one of its closures
+	if relProject, err := relativeTo(".", projectPath); err == nil && !strings.HasPrefix(relProject, "..") {
+		projectPath = relProject
+	}
+	packagePattern := "."
+	if dir := filepath.ToSlash(filepath.Dir(file)); dir != "." {
+		packagePattern = "./" + dir
+	}
+	generate := []string{"gcov", "generate", shellArg(projectPath),
+		"--include-packages", shellArg(packagePattern), "--function", shellArg(declared.Key())}
+	if !declared.IsExported {
+		generate = append(generate, "--include-unexported")
+	}
+	at.GenerateCommand = strings.Join(generate, " ")
+
+	return reporter.WriteFunctionAt(os.Stdout, at, outputFormat, threshold, numbers)
+}
+
+func runIDE(cmd *cobra.Command, args []string) error {
+	projectPath := "."
+	if len(args) > 0 {
+		projectPath = args[0]
+	}
+
+	verbose, _ := cmd.Flags().GetBool("verbose")
+	excludeDirs, _ := cmd.Flags().GetStringSlice("exclude")
+
+	// Generation follows the configuration, with the defaults of gcov generate where it has none
+	generation := &generator.Options{
+		TemplateStyle:   cfg.TemplateStyle,
+		TemplatesDir:    cfg.GetTemplatesDir(),
+		TestNamePattern: cfg.TestNamePattern,
+		GenerateMocks:   true,
+		MockFramework:   cfg.MockFramework,
+		TableDriven:     true,
+		ParallelTests:   cfg.ParallelTests,
+		Backup:          cfg.BackupOverwritten,
+		MaxTestCases:    10,
+		TestPackageMode: cfg.TestPackageMode,
+		SuiteStyle:      cfg.SuiteStyle,
+		Seed:            cfg.Seed,
+		MaxConcurrency:  cfg.MaxConcurrency,
+		Version:         version,
+		Verbose:         verbose,
+		CommandTimeout:  commandTimeout(cmd),
+	}
+	if generation.TemplateStyle == "" {
+		generation.TemplateStyle = "standard"
+	}
+	if generation.TestNamePattern == "" {
+		generation.TestNamePattern = generator.DefaultTestNamePattern
+	}
+	if generation.MockFramework == "" {
+		generation.MockFramework = generator.MockFrameworkTestify
+	}
+	if generation.TestPackageMode == "" {
+		generation.TestPackageMode = generator.TestPackageSame
+	}
+	if generation.SuiteStyle == "" {
+		generation.SuiteStyle = "none"
+	}
+	if generation.Seed == 0 {
+		generation.Seed = generator.DefaultSeed
+	}
+
+	// Responses own stdout, so everything else gcov prints goes to stderr
+	out := os.Stdout
+	os.Stdout = os.Stderr
+	defer func() { os.Stdout = out }()
+
+	server := ide.NewServer(&ide.Options{
+		ProjectPath: projectPath,
+		Analysis: &analyzer.Options{
+			ExcludeDirs:         excludeDirs,
+			IncludePackages:     cfg.IncludePackages,
+			ExcludePackages:     cfg.ExcludePackages,
+			CalculateComplexity: true,
+			HighComplexity:      cfg.HighComplexityThreshold,
+			MaxFunctionLines:    cfg.FunctionLimits.MaxLines,
+			MaxParams:           cfg.FunctionLimits.MaxParams,
+			Verbose:             verbose,
+			CommandTimeout:      commandTimeout(cmd),
+
+			IncludeUnexported:       cfg.IncludeUnexported,
+			UnexportedMinComplexity: cfg.UnexportedMinComplexity,
+		},
+		Generation: generation,
+	})
+	if verbose {
+		i18n.Fprintf(os.Stderr, "🔌 Serving %s over stdin and stdout\n", projectPath)
+	}
+	return server.Serve(cmd.Context(), os.Stdin, out)
+}
+
+func runDaemonStart(cmd *cobra.Command, args []string) error {
+	projectPath := "."
+	if len(args) > 0 {
+		projectPath = args[0]
+	}
+	absPath, err := filepath.Abs(projectPath)
+	if err != nil {
+		return fmt.Errorf("failed to get absolute path: %w", err)
+	}
+
+	if status, err := daemon.GetStatus(absPath); err == nil {
+		i18n.Printf("🔌 A gcov daemon already serves %s (pid %d)\n", absPath, status.PID)
+		return nil
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to find the gcov executable: %w", err)
+	}
+	logPath := filepath.Join(absPath, daemon.LogFile)
+	if err := os.MkdirAll(filepath.Dir(logPath), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(logPath), err)
+	}
+	logFile, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open daemon log: %w", err)
+	}
+	defer logFile.Close()
+
+	daemonArgs := []string{"daemon", "run", absPath}
+	if configFile, _ := cmd.Flags().GetString("config"); configFile != "" {
+		if configFile, err = filepath.Abs(configFile); err != nil {
+			return fmt.Errorf("failed to get absolute path: %w", err)
+		}
+		daemonArgs = append(daemonArgs, "--config", configFile)
+	}
+	if verbose, _ := cmd.Flags().GetBool("verbose"); verbose {
+		daemonArgs = append(daemonArgs, "--verbose")
+	}
+
+	process := exec.Command(executable, daemonArgs...)
+	process.Dir = absPath
+	process.Stdout, process.Stderr = logFile, logFile
+	command.Detach(process)
+	if err := process.Start(); err != nil {
+		return fmt.Errorf("failed to start the daemon: %w", err)
+	}
+	exited := make(chan error, 1)
+	go func() { exited <- process.Wait() }()
+
+	// Wait for the daemon to answer on its socket, or to fail
+	deadline := time.After(10 * time.Second)
+	for {
+		select {
+		case err := <-exited:
+			return fmt.Errorf("the daemon exited (%v), see %s", err, logPath)
+		case <-deadline:
+			return fmt.Errorf("the daemon did not start listening in time, see %s", logPath)
+		case <-time.After(50 * time.Millisecond):
+		}
+		if status, err := daemon.GetStatus(absPath); err == nil {
+			i18n.Printf("🔌 gcov daemon serving %s (pid %d, socket %s)\n", absPath, status.PID, status.Socket)
+			return nil
+		}
+	}
+}
+
+func runDaemonRun(cmd *cobra.Command, args []string) error {
+	projectPath := "."
+	if len(args) > 0 {
+		projectPath = args[0]
+	}
+	verbose, _ := cmd.Flags().GetBool("verbose")
+	return daemon.Serve(cmd.Context(), projectPath, version, verbose)
+}
+
+func runDaemonStop(cmd *cobra.Command, args []string) error {
+	projectPath := "."
+	if len(args) > 0 {
+		projectPath = args[0]
+	}
+	if err := daemon.Stop(projectPath); err != nil {
+		return err
+	}
+	i18n.Printf("🛑 gcov daemon stopped\n")
+	return nil
+}
+
+func runDaemonStatus(cmd *cobra.Command, args []string) error {
+	projectPath := "."
+	if len(args) > 0 {
+		projectPath = args[0]
+	}
+	status, err := daemon.GetStatus(projectPath)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Project:      %s\n", status.ProjectPath)
+	fmt.Printf("PID:          %d\n", status.PID)
+	fmt.Printf("Version:      %s\n", status.Version)
+	fmt.Printf("Socket:       %s\n", status.Socket)
+	fmt.Printf("Uptime:       %s\n", time.Since(status.Started).Round(time.Second))
+	fmt.Printf("Analyses:     %d (%d answered from cache)\n", status.Analyses, status.CacheHits)
+	fmt.Printf("Parsed files: %d\n", status.CachedFiles)
+	return nil
+}
+
+// analyze runs an analysis in the gcov daemon serving the project when there is one, unless
+// --no-daemon is given, and in this process otherwise. Profiling the analysis with --pprof-dir
+// needs it to run here
+func analyze(cmd *cobra.Command, opts *analyzer.Options) (*models.AnalysisResult, error) {
+	noDaemon, _ := cmd.Flags().GetBool("no-daemon")
+	if !noDaemon && opts.PprofDir == "" {
+		// The daemon reuses results until a file they depend on changes, these included
+		opts.InputFiles = nil
+		for _, path := range []string{cfg.File, exemptionsPath(cmd, opts.ProjectPath)} {
+			if path != "" {
+				opts.InputFiles = append(opts.InputFiles, path)
+			}
+		}
+
+		result, delegated, err := daemon.Analyze(cmd.Context(), opts, version)
+		if delegated {
+			if opts.Verbose {
+				i18n.Printf("🔌 Analyzed by the gcov daemon\n")
+			}
+			return result, err
+		}
+	}
+	return analyzer.Analyze(cmd.Context(), opts)
+}
+
+// shellArg quotes an argument for a POSIX shell when it holds characters the shell would interpret
+func shellArg(arg string) string {
+	if arg != "" && !strings.ContainsAny(arg, " \t\n'\"\\$`*?[]{}()<>|&;#~!") {
+		return arg
+	}
+	return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+}
+
+// analysisScope splits the path given to analyze into the project and the file or directory analyzed
+// on its own. A Go file, or a directory without a go.mod inside a module, is analyzed within the
+// module holding it; any other path is a whole project, as before
+func analysisScope(path string) (projectPath, scope string, err error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to access %s: %w", path, err)
+	}
+
+	dir := path
+	if !info.IsDir() {
+		if !strings.HasSuffix(path, ".go") {
+			return "", "", fmt.Errorf("%s is not a Go file", path)
+		}
+		dir = filepath.Dir(path)
+	} else if _, err := os.Stat(filepath.Join(path, "go.mod")); err == nil {
+		return path, "", nil
+	}
+
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to resolve %s: %w", path, err)
+	}
+	for moduleDir := absDir; ; moduleDir = filepath.Dir(moduleDir) {
+		if _, err := os.Stat(filepath.Join(moduleDir, "go.mod")); err == nil {
+			scope, err := relativeTo(moduleDir, path)
+			if err != nil {
+				return "", "", err
+			}
+			return moduleDir, scope, nil
+		}
+		if filepath.Dir(moduleDir) == moduleDir {
+			break
+		}
+	}
+
+	if !info.IsDir() {
+		return "", "", fmt.Errorf("%s is not in a Go module", path)
+	}
+	return path, "", nil
+}
+
+func runManifest(cmd *cobra.Command, args []string) error {
+	projectPath := "."
+	if len(args) > 0 {
+		projectPath = args[0]
+	}
+
+	function, _ := cmd.Flags().GetString("function")
+	kind, _ := cmd.Flags().GetString("kind")
+
+	generated, err := manifest.Load(projectPath)
+	if err != nil {
+		return fmt.Errorf("failed to load manifest: %w", err)
+	}
+
+	entries := generated.Filter(kind, function)
+	if len(entries) == 0 {
+		i18n.Println("📭 No generated artifacts recorded")
+		return nil
+	}
+
+	i18n.Printf("📦 %d generated artifacts (manifest updated %s)\n", len(entries), generated.Updated.Format(time.RFC3339))
+	for _, entry := range entries {
+		if entry.Kind != manifest.KindTest {
+			i18n.Printf("   • %s [%s, gcov %s]\n", entry.Path, entry.Kind, entry.Version)
+			continue
+		}
+		i18n.Printf("   • %s %s [%s] %s, gcov %s\n", entry.Path, entry.Test, entry.TestType, entry.Signature, entry.Version)
+	}
+	return nil
+}
+
+func runTemplatesExport(cmd *cobra.Command, args []string) error {
+	overwrite, _ := cmd.Flags().GetBool("overwrite")
+
+	written, err := generator.ExportTemplates(args[0], overwrite)
+	if err != nil {
+		return fmt.Errorf("template export failed: %w", err)
+	}
+
+	i18n.Printf("📋 Exported %d templates to %s\n", len(written), args[0])
+	fmt.Println("   Set templates.custom_templates_dir in gcov.yaml to use them")
+	return nil
+}
+
+func runRestore(cmd *cobra.Command, args []string) error {
+	projectPath := "."
+	if len(args) > 0 {
+		projectPath = args[0]
+	}
+
+	run, _ := cmd.Flags().GetString("run")
+	files, _ := cmd.Flags().GetStringSlice("file")
+	list, _ := cmd.Flags().GetBool("list")
+
+	runs, err := backup.ListRuns(projectPath)
+	if err != nil {
+		return err
+	}
+	if len(runs) == 0 {
+		i18n.Println("📭 No backups recorded")
+		return nil
+	}
+
+	if list {
+		for _, id := range runs {
+			runFiles, err := backup.Files(projectPath, id)
+			if err != nil {
+				return err
+			}
+			i18n.Printf("💾 %s (%d files)\n", id, len(runFiles))
+			for _, file := range runFiles {
+				i18n.Printf("   • %s\n", file)
+			}
+		}
+		return nil
+	}
+
+	if run == "" {
+		run = runs[0]
+	}
+	restored, err := backup.Restore(projectPath, run, files)
+	for _, file := range restored {
+		i18n.Printf("✅ Restored %s\n", file)
+	}
+	if err != nil {
+		return fmt.Errorf("restore failed: %w", err)
+	}
+	i18n.Printf("💾 Restored %d files from backup run %s\n", len(restored), run)
+	return nil
+}
+
+func runSchema(cmd *cobra.Command, args []string) error {
+	schema, err := models.JSONSchema()
+	if err != nil {
+		return fmt.Errorf("failed to build schema: %w", err)
+	}
+
+	fmt.Println(string(schema))
+	return nil
+}
+
+// packagePatterns returns the Go-style include and exclude package patterns of a command, falling back
+// to the configured ones when the flags are not given
+func packagePatterns(cmd *cobra.Command) ([]string, []string) {
+	include, _ := cmd.Flags().GetStringSlice("include-packages")
+	exclude, _ := cmd.Flags().GetStringSlice("exclude-packages")
+	if !cmd.Flags().Changed("include-packages") {
+		include = cfg.IncludePackages
+	}
+	if !cmd.Flags().Changed("exclude-packages") {
+		exclude = cfg.ExcludePackages
+	}
+	return include, exclude
+}
+
+// failUnderThresholds returns the per-metric thresholds gcov analyze fails under, falling back to the
+// configured ones when the flags are not given, and for overall coverage to the coverage threshold
+func failUnderThresholds(cmd *cobra.Command, threshold float64) *analyzer.Thresholds {
+	thresholds := &analyzer.Thresholds{
+		Overall:  cfg.FailUnder.Overall,
+		Function: cfg.FailUnder.Function,
+		Branch:   cfg.FailUnder.Branch,
+		Package:  cfg.FailUnder.Package,
+	}
+	if thresholds.Overall == 0 || cmd.Flags().Changed("threshold") {
+		thresholds.Overall = threshold
+	}
+
+	for flag, value := range map[string]*float64{
+		"fail-under":          &thresholds.Overall,
+		"fail-under-function": &thresholds.Function,
+		"fail-under-branch":   &thresholds.Branch,
+		"fail-under-package":  &thresholds.Package,
+	} {
+		if cmd.Flags().Changed(flag) {
+			*value, _ = cmd.Flags().GetFloat64(flag)
+		}
+	}
+	return thresholds
+}
+
+// colorMode returns the color mode of console output: never with --no-color, else --color, falling
+// back to the configured mode when the flag is not given
+func colorMode(cmd *cobra.Command) string {
+	if noColor, _ := cmd.Flags().GetBool("no-color"); noColor {
+		return reporter.ColorModeNever
+	}
+	mode, _ := cmd.Flags().GetString("color")
+	if !cmd.Flags().Changed("color") && cfg.Color != "" {
+		mode = cfg.Color
+	}
+	return mode
+}
+
+// numberFormat returns the decimals of report percentages and whether reports lead with absolute
+// counts, falling back to the configured settings when the flags are not given
+func numberFormat(cmd *cobra.Command) (int, bool) {
+	precision, _ := cmd.Flags().GetInt("precision")
+	if !cmd.Flags().Changed("precision") {
+		precision = cfg.Precision
+	}
+	absolute, _ := cmd.Flags().GetBool("absolute")
+	return precision, absolute || cfg.Absolute
+}
+
+// loadExemptions reads the exemptions of --exemptions, or else of the exemptions_file setting, relative
+// to the project; only a file named with --exemptions has to exist
+func loadExemptions(cmd *cobra.Command, projectPath string) ([]*models.Exemption, error) {
+	path := exemptionsPath(cmd, projectPath)
+	if path == "" {
+		return nil, nil
+	}
+	return analyzer.LoadExemptions(path, cmd.Flags().Changed("exemptions"))
+}
+
+// exemptionsPath returns the exemptions file of --exemptions, or else of the exemptions_file setting,
+// relative to the project, or "" when there is none
+func exemptionsPath(cmd *cobra.Command, projectPath string) string {
+	path := cfg.ExemptionsFile
+	if cmd.Flags().Changed("exemptions") {
+		path, _ = cmd.Flags().GetString("exemptions")
+	}
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(projectPath, path)
+}
+
+// reportExemptions warns about the exemptions of a result that expire soon and reports the expired
+// ones whose code is still uncovered, returning whether there are any
+func reportExemptions(result *models.AnalysisResult) bool {
+	for _, exemption := range result.Exemptions {
+		if exemption.Status == models.ExemptionExpiring {
+			i18n.Fprintf(os.Stderr, "⚠️ Exemption of %s expires on %s: %s\n",
+				exemptionTarget(exemption), exemption.Expires.Format("2006-01-02"), exemption.Reason)
+		}
+	}
+
+	expired := analyzer.ExpiredExemptions(result)
+	if len(expired) == 0 {
+		return false
+	}
+	i18n.Fprintf(os.Stderr, "\n❌ %d expired exemptions still have uncovered code:\n", len(expired))
+	for _, exemption := range expired {
+		i18n.Fprintf(os.Stderr, "   • %s expired on %s with %d uncovered statements (%s)\n",
+			exemptionTarget(exemption), exemption.Expires.Format("2006-01-02"), exemption.UncoveredStatements, exemption.Reason)
+	}
+	return true
+}
+
+// exemptionTarget names what an exemption covers, its function or file pattern
+func exemptionTarget(exemption *models.Exemption) string {
+	if exemption.Function != "" {
+		return exemption.Function
+	}
+	return exemption.File
+}
+
+// requireFreshProfile reports whether a stale coverage profile fails the command, with
+// --require-fresh-profile or the require_fresh_profile setting
+func requireFreshProfile(cmd *cobra.Command) bool {
+	require, _ := cmd.Flags().GetBool("require-fresh-profile")
+	if !cmd.Flags().Changed("require-fresh-profile") {
+		require = cfg.RequireFreshProfile
+	}
+	return require
+}
+
+// reportStaleProfile warns that the coverage profile of a result predates changes to the sources,
+// listing the most recently modified ones, and returns whether it does
+func reportStaleProfile(result *models.AnalysisResult) bool {
+	stale := result.StaleProfile
+	if stale == nil {
+		return false
+	}
+
+	i18n.Fprintf(os.Stderr, "\n⚠️ Coverage profile %s is stale: %d source files were modified after it was written at %s\n",
+		result.Metadata.ProfilePath, len(stale.ModifiedFiles), stale.ProfileModified.Format("2006-01-02 15:04:05"))
+	for _, file := range stale.ModifiedFiles[:min(5, len(stale.ModifiedFiles))] {
+		i18n.Fprintf(os.Stderr, "   • %s\n", file)
+	}
+	if len(stale.ModifiedFiles) > 5 {
+		fmt.Fprintf(os.Stderr, "   ... and %d more\n", len(stale.ModifiedFiles)-5)
+	}
+	fmt.Fprintf(os.Stderr, "   Their coverage may be misreported; regenerate the profile with gcov analyze --profile\n")
+	return true
+}
+
+// offerRegeneration asks whether to regenerate a stale profile when gcov runs in a terminal, and
+// returns whether the answer was yes
+func offerRegeneration(profilePath string) bool {
+	if !reporter.IsTerminal(os.Stdin) || !reporter.IsTerminal(os.Stderr) {
+		return false
+	}
+	fmt.Fprintf(os.Stderr, "Regenerate %s now by running the tests? [y/N] ", filepath.Base(profilePath))
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+// strictMode reports whether analysis warnings fail the command, with --strict or the strict setting
+func strictMode(cmd *cobra.Command) bool {
+	strict, _ := cmd.Flags().GetBool("strict")
+	if !cmd.Flags().Changed("strict") {
+		strict = cfg.Strict
+	}
+	return strict
+}
+
+// reportWarnings prints the warnings of a run that strict mode fails on, returning whether there are any
+func reportWarnings(warnings []string) bool {
+	if len(warnings) == 0 {
+		return false
+	}
+	i18n.Fprintf(os.Stderr, "\n❌ %d warnings in strict mode:\n", len(warnings))
+	for _, warning := range warnings {
+		i18n.Fprintf(os.Stderr, "   • %s\n", warning)
+	}
+	return true
+}
+
+// ciServer returns the CI server coverage statistics are printed for, with --ci or the ci setting
+func ciServer(cmd *cobra.Command) string {
+	ci, _ := cmd.Flags().GetString("ci")
+	if !cmd.Flags().Changed("ci") {
+		ci = cfg.CI
+	}
+	return strings.ToLower(ci)
+}
+
+// includeCgo reports whether the functions of cgo files count in the totals, with --include-cgo or the
+// include_cgo setting
+func includeCgo(cmd *cobra.Command) bool {
+	include, _ := cmd.Flags().GetBool("include-cgo")
+	if !cmd.Flags().Changed("include-cgo") {
+		include = cfg.IncludeCgo
+	}
+	return include
+}
+
+// setupMessages turns on plain output with --plain or the plain setting, and loads the message
+// catalog given with --messages, falling back to the configured one
+func setupMessages(cmd *cobra.Command) error {
+	plain, _ := cmd.Flags().GetBool("plain")
+	i18n.SetPlain(plain || cfg.Plain)
+
+	messagesFile, _ := cmd.Flags().GetString("messages")
+	if !cmd.Flags().Changed("messages") {
+		messagesFile = cfg.MessagesFile
+	}
+	if messagesFile == "" {
+		return nil
+	}
+	return i18n.LoadCatalog(messagesFile)
+}
+
+// commandTimeout returns the timeout bounding each go command a command runs, falling back to the
+// configured one when the flag is not given
+func commandTimeout(cmd *cobra.Command) time.Duration {
+	timeout, _ := cmd.Flags().GetDuration("command-timeout")
+	if !cmd.Flags().Changed("command-timeout") {
+		timeout = cfg.CommandTimeout
+	}
+	return timeout
+}
+
+// complexityWarn returns the complexity above which functions count as highly complex, falling back to
+// the configured one when the flag is not given
+func complexityWarn(cmd *cobra.Command) int {
+	threshold, _ := cmd.Flags().GetInt("complexity-warn")
+	if !cmd.Flags().Changed("complexity-warn") && cfg.HighComplexityThreshold > 0 {
+		threshold = cfg.HighComplexityThreshold
+	}
+	return threshold
+}
+
+// functionLimits returns the lines of code and parameters above which functions count as oversized,
+// falling back to the configured limits when the flags are not given
+func functionLimits(cmd *cobra.Command) (int, int) {
+	maxLines, _ := cmd.Flags().GetInt("max-function-lines")
+	if !cmd.Flags().Changed("max-function-lines") && cfg.FunctionLimits.MaxLines > 0 {
+		maxLines = cfg.FunctionLimits.MaxLines
+	}
+	maxParams, _ := cmd.Flags().GetInt("max-params")
+	if !cmd.Flags().Changed("max-params") && cfg.FunctionLimits.MaxParams > 0 {
+		maxParams = cfg.FunctionLimits.MaxParams
+	}
+	return maxLines, maxParams
+}
+
+// progressFunc returns the progress callback of a command, or nil when progress is turned off or
+// verbose output already narrates the run
+func progressFunc(cmd *cobra.Command) models.ProgressFunc {
+	verbose, _ := cmd.Flags().GetBool("verbose")
+	noProgress, _ := cmd.Flags().GetBool("no-progress")
+	if verbose || noProgress {
+		return nil
+	}
+	return reporter.NewProgress().Func()
+}
+
+// fileClassification returns the configured file classification rules, nil for the defaults, and the
+// classes coverage is computed over, from the --classes flag when the command has it and it is given
+func fileClassification(cmd *cobra.Command) (*coverage.ClassificationRules, []string) {
+	var rules *coverage.ClassificationRules
+	c := cfg.Classification
+	if len(c.Test)+len(c.Helper)+len(c.Generated)+len(c.Vendored) > 0 {
+		rules = &coverage.ClassificationRules{
+			Test:      c.Test,
+			Helper:    c.Helper,
+			Generated: c.Generated,
+			Vendored:  c.Vendored,
+		}
+	}
+
+	classes := c.Counted
+	if cmd.Flags().Lookup("classes") != nil && cmd.Flags().Changed("classes") {
+		classes, _ = cmd.Flags().GetStringSlice("classes")
+	}
+	return rules, classes
+}
+
+// historyStore returns the project's history store, resolving the configured directory against the project path
+func historyStore(projectPath string) *history.Store {
+	dir := cfg.HistoryDir
+	if dir == "" {
+		dir = history.DefaultDir
+	}
+	if !filepath.IsAbs(dir) && !storage.IsRemote(dir) {
+		dir = filepath.Join(projectPath, dir)
+	}
+	return history.NewStore(dir)
+}
+
+// loadHistory returns recorded snapshots for reports, treating an unreadable store as empty
+func loadHistory(projectPath string, verbose bool) []*models.CoverageSnapshot {
+	snapshots, err := historyStore(projectPath).Load()
+	if err != nil {
+		if verbose {
+			i18n.Printf("⚠️ Skipping coverage history: %v\n", err)
+		}
+		return nil
+	}
+	return snapshots
+}
@@ -0,0 +1,157 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/beck/go-coverage-analyzer/pkg/coverage"
+	"github.com/beck/go-coverage-analyzer/pkg/models"
+)
+
+// AffectedOptions identifies the changes whose tests are selected
+type AffectedOptions struct {
+	ProjectPath string
+	DiffBase    string // git ref the working tree is diffed against
+}
+
+// AffectedTests are the tests a change set can break: those that cover a changed function according
+// to the test matrix, and those declared in changed test files
+type AffectedTests struct {
+	Packages         []string // directories relative to the project, slash-separated
+	Tests            []string
+	ChangedFunctions []*models.Function
+}
+
+// GoTestArgs returns the arguments of the go test command running only the affected tests; tests
+// named alike in other selected packages run too, as -run applies to every package
+func (a *AffectedTests) GoTestArgs() []string {
+	args := []string{"test"}
+	for _, dir := range a.Packages {
+		if dir == "." {
+			args = append(args, ".")
+		} else {
+			args = append(args, "./"+dir)
+		}
+	}
+
+	names := make([]string, len(a.Tests))
+	for i, test := range a.Tests {
+		names[i] = regexp.QuoteMeta(test)
+	}
+	return append(args, "-run", "^("+strings.Join(names, "|")+")$")
+}
+
+// FindAffectedTests selects the tests affected by the changes since opts.DiffBase, using the test
+// matrix of the result to find the tests covering each changed function
+func FindAffectedTests(result *models.AnalysisResult, opts *AffectedOptions) (*AffectedTests, error) {
+	changes, err := changedLines(opts.ProjectPath, opts.DiffBase)
+	if err != nil {
+		return nil, err
+	}
+
+	// Changed functions by directory, then by Key, as test attributions name them
+	changed := make(map[string]map[string]bool)
+	affected := &AffectedTests{ChangedFunctions: make([]*models.Function, 0)}
+	for _, pkg := range result.PackageCoverage {
+		for _, file := range pkg.Files {
+			for _, function := range file.Functions {
+				for _, r := range changes[filepath.ToSlash(function.File)] {
+					if r.start <= function.EndLine && r.end >= function.StartLine {
+						dir := path.Dir(filepath.ToSlash(function.File))
+						if changed[dir] == nil {
+							changed[dir] = make(map[string]bool)
+						}
+						changed[dir][function.Key()] = true
+						affected.ChangedFunctions = append(affected.ChangedFunctions, function)
+						break
+					}
+				}
+			}
+		}
+	}
+	sort.Slice(affected.ChangedFunctions, func(i, j int) bool {
+		if affected.ChangedFunctions[i].File != affected.ChangedFunctions[j].File {
+			return affected.ChangedFunctions[i].File < affected.ChangedFunctions[j].File
+		}
+		return affected.ChangedFunctions[i].StartLine < affected.ChangedFunctions[j].StartLine
+	})
+
+	packages := make(map[string]bool)
+	tests := make(map[string]bool)
+	for _, attribution := range result.TestMatrix {
+		dir := path.Dir(filepath.ToSlash(attribution.File))
+		for _, key := range attribution.Functions {
+			if changed[dir][key] {
+				packages[dir] = true
+				tests[attribution.Test] = true
+				break
+			}
+		}
+	}
+
+	// Changed test files run in full, including tests added since the matrix was recorded
+	for relPath := range changes {
+		if !strings.HasSuffix(relPath, "_test.go") {
+			continue
+		}
+		names, err := testNames(filepath.Join(opts.ProjectPath, filepath.FromSlash(relPath)))
+		if err != nil || len(names) == 0 {
+			continue
+		}
+		packages[path.Dir(relPath)] = true
+		for _, name := range names {
+			tests[name] = true
+		}
+	}
+
+	for dir := range packages {
+		affected.Packages = append(affected.Packages, dir)
+	}
+	for test := range tests {
+		affected.Tests = append(affected.Tests, test)
+	}
+	sort.Strings(affected.Packages)
+	sort.Strings(affected.Tests)
+
+	return affected, nil
+}
+
+// testNames returns the tests declared in a test file, nothing when it was deleted
+func testNames(filePath string) ([]string, error) {
+	file, err := parser.ParseFile(token.NewFileSet(), filePath, nil, parser.SkipObjectResolution)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	names := make([]string, 0)
+	for _, decl := range file.Decls {
+		if funcDecl, ok := decl.(*ast.FuncDecl); ok && funcDecl.Recv == nil && coverage.IsTestName(funcDecl.Name.Name) {
+			names = append(names, funcDecl.Name.Name)
+		}
+	}
+	return names, nil
+}
+
+// LoadTestMatrix reads the test matrix of a JSON analysis result recorded with test attribution
+func LoadTestMatrix(path string) ([]*models.TestAttribution, error) {
+	recorded, err := LoadResult(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read test matrix: %w", err)
+	}
+	if len(recorded.TestMatrix) == 0 {
+		return nil, fmt.Errorf("%s has no test matrix, record it with gcov analyze --attribute-tests -o json", path)
+	}
+
+	return recorded.TestMatrix, nil
+}
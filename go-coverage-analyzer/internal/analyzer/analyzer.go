@@ -1,11 +1,14 @@
 package analyzer
 
 import (
+	"context"
 	"fmt"
+	"os"
 	"path/filepath"
 	"strings"
 	"time"
 
+	"github.com/beck/go-coverage-analyzer/internal/i18n"
 	"github.com/beck/go-coverage-analyzer/pkg/coverage"
 	"github.com/beck/go-coverage-analyzer/pkg/models"
 )
@@ -16,18 +19,63 @@ type Options struct {
 	ExcludeDirs         []string
 	IncludeTests        bool
 	PackagePattern      string
+	IncludePackages     []string // Go-style patterns such as ./internal/..., empty for all packages
+	ExcludePackages     []string
+	Classification      *coverage.ClassificationRules // nil for the default file classification
+	Classes             []string                      // file classes coverage is computed over, empty for production code
 	GenerateProfile     bool
+	StreamProfile       bool // read the profile line by line, for profiles too large to hold in memory
 	ProfileOutput       string
 	ProfilePath         string
 	CalculateComplexity bool
 	MinComplexity       int
+	HighComplexity      int // complexity above which functions count as highly complex, 0 for the default of 10
+	MaxFunctionLines    int // lines of code above which functions count as oversized, 0 for the default
+	MaxParams           int // parameters above which functions count as oversized, 0 for the default
 	Verbose             bool
+	Progress            models.ProgressFunc  `json:"-"` // told how far parsing and analysis are, nil to show nothing
+	CommandTimeout      time.Duration        // bounds each go command, such as go test generating the profile
+	AttributeTests      bool                 // run each top-level test on its own to record the functions it covers
+	SelfProfile         bool                 // record phase timings and the peak heap in Metadata.SelfProfile
+	PprofDir            string               // write CPU and heap pprof profiles of the analysis here, "" for none
+	Scope               string               // file or directory relative to ProjectPath analyzed on its own, "" for all
+	ParseCache          *coverage.ParseCache `json:"-"` // files earlier analyses parsed, kept by long-running processes
+	InputFiles          []string             // config and exemptions files the options were read from, which cached results depend on too
+
+	// Include unexported functions at or above UnexportedMinComplexity as testable
+	IncludeUnexported       bool
+	UnexportedMinComplexity int
+
+	// Patterns of functions left out of the totals, matching their name or Receiver.Name
+	IgnoreFunctions []string
+
+	// Patterns of the functions and files of unexpired exemptions, see ExemptionPatterns
+	ExemptFunctions []string
+	ExemptFiles     []string
+
+	// Report init functions and package-level variable initializers as initialization coverage
+	IncludeInit bool
+
+	// Count the functions of cgo files in the totals, which leave them out as not instrumentable
+	IncludeCgo bool
 }
 
-// Analyze performs coverage analysis on the specified Go project
-func Analyze(opts *Options) (*models.AnalysisResult, error) {
+// Analyze performs coverage analysis on the specified Go project until ctx is cancelled
+func Analyze(ctx context.Context, opts *Options) (*models.AnalysisResult, error) {
 	if opts.Verbose {
-		fmt.Printf("🔍 Starting analysis of: %s\n", opts.ProjectPath)
+		i18n.Printf("🔍 Starting analysis of: %s\n", opts.ProjectPath)
+	}
+
+	if opts.PprofDir != "" {
+		stopPprof, err := startPprof(opts.PprofDir)
+		if err != nil {
+			return nil, err
+		}
+		defer func() {
+			if err := stopPprof(); err != nil {
+				i18n.Fprintf(os.Stderr, "⚠️ %v\n", err)
+			}
+		}()
 	}
 
 	// Create coverage analysis engine
@@ -45,15 +93,37 @@ func Analyze(opts *Options) (*models.AnalysisResult, error) {
 		ProfilePath:         opts.ProfilePath,
 		ProfileOutput:       profileOutput,
 		PackagePattern:      opts.PackagePattern,
+		IncludePackages:     opts.IncludePackages,
+		ExcludePackages:     opts.ExcludePackages,
+		Classification:      opts.Classification,
+		Classes:             opts.Classes,
 		ExcludeDirs:         opts.ExcludeDirs,
 		IncludeTests:        opts.IncludeTests,
 		GenerateProfile:     opts.GenerateProfile,
+		StreamProfile:       opts.StreamProfile,
+		Progress:            opts.Progress,
+		CommandTimeout:      opts.CommandTimeout,
 		CalculateComplexity: opts.CalculateComplexity,
 		MinComplexity:       opts.MinComplexity,
+		HighComplexity:      opts.HighComplexity,
+		MaxFunctionLines:    opts.MaxFunctionLines,
+		MaxParams:           opts.MaxParams,
+		AttributeTests:      opts.AttributeTests,
+		SelfProfile:         opts.SelfProfile || opts.PprofDir != "",
+		Scope:               opts.Scope,
+		ParseCache:          opts.ParseCache,
+
+		IncludeUnexported:       opts.IncludeUnexported,
+		UnexportedMinComplexity: opts.UnexportedMinComplexity,
+		IgnoreFunctions:         opts.IgnoreFunctions,
+		ExemptFunctions:         opts.ExemptFunctions,
+		ExemptFiles:             opts.ExemptFiles,
+		IncludeInit:             opts.IncludeInit,
+		IncludeCgo:              opts.IncludeCgo,
 	}
 
 	// Perform comprehensive analysis
-	result, err := engine.AnalyzeProject(analysisOpts)
+	result, err := engine.AnalyzeProject(ctx, analysisOpts)
 	if err != nil {
 		return nil, fmt.Errorf("coverage analysis failed: %w", err)
 	}
@@ -64,9 +134,9 @@ func Analyze(opts *Options) (*models.AnalysisResult, error) {
 	}
 
 	if opts.Verbose {
-		fmt.Printf("✅ Analysis completed successfully\n")
-		fmt.Printf("📊 Overall Coverage: %.1f%%\n", result.OverallCoverage)
-		fmt.Printf("🔍 Uncovered Functions: %d\n", len(result.UncoveredFunctions))
+		i18n.Printf("✅ Analysis completed successfully\n")
+		i18n.Printf("📊 Overall Coverage: %.1f%%\n", result.OverallCoverage)
+		i18n.Printf("🔍 Uncovered Functions: %d\n", len(result.UncoveredFunctions))
 	}
 
 	return result, nil
@@ -86,9 +156,9 @@ func filterByComplexity(functions []*models.Function, minComplexity int) []*mode
 }
 
 // AnalyzeFromProfile performs analysis from an existing coverage profile
-func AnalyzeFromProfile(profilePath, projectPath string, opts *Options) (*models.AnalysisResult, error) {
+func AnalyzeFromProfile(ctx context.Context, profilePath, projectPath string, opts *Options) (*models.AnalysisResult, error) {
 	if opts.Verbose {
-		fmt.Printf("📋 Analyzing from existing profile: %s\n", profilePath)
+		i18n.Printf("📋 Analyzing from existing profile: %s\n", profilePath)
 	}
 
 	// Update options to use existing profile
@@ -96,18 +166,28 @@ func AnalyzeFromProfile(profilePath, projectPath string, opts *Options) (*models
 		ProjectPath:         projectPath,
 		ProfilePath:         profilePath,
 		PackagePattern:      opts.PackagePattern,
+		IncludePackages:     opts.IncludePackages,
+		ExcludePackages:     opts.ExcludePackages,
+		Classification:      opts.Classification,
+		Classes:             opts.Classes,
 		ExcludeDirs:         opts.ExcludeDirs,
 		IncludeTests:        opts.IncludeTests,
 		GenerateProfile:     false, // Don't generate, use existing
+		StreamProfile:       opts.StreamProfile,
+		Progress:            opts.Progress,
+		CommandTimeout:      opts.CommandTimeout,
 		CalculateComplexity: opts.CalculateComplexity,
 		MinComplexity:       opts.MinComplexity,
+		HighComplexity:      opts.HighComplexity,
+		MaxFunctionLines:    opts.MaxFunctionLines,
+		MaxParams:           opts.MaxParams,
 	}
 
 	// Create coverage analysis engine
 	engine := coverage.NewAnalysisEngine(opts.Verbose)
 
 	// Perform analysis
-	result, err := engine.AnalyzeProject(analysisOpts)
+	result, err := engine.AnalyzeProject(ctx, analysisOpts)
 	if err != nil {
 		return nil, fmt.Errorf("profile analysis failed: %w", err)
 	}
@@ -135,7 +215,7 @@ func ValidateCoverageProfile(profilePath string, verbose bool) error {
 	}
 
 	if verbose {
-		fmt.Printf("✅ Profile is valid: %d blocks across %d files\n",
+		i18n.Printf("✅ Profile is valid: %d blocks across %d files\n",
 			len(profile.Blocks), len(profile.Files))
 	}
 
@@ -143,10 +223,10 @@ func ValidateCoverageProfile(profilePath string, verbose bool) error {
 }
 
 // GenerateCoverageProfile generates a coverage profile for the project
-func GenerateCoverageProfile(projectPath, outputPath, packagePattern string, verbose bool) error {
+func GenerateCoverageProfile(ctx context.Context, projectPath, outputPath, packagePattern string, verbose bool) error {
 	parser := coverage.NewProfileParser(verbose)
 
-	return parser.GenerateProfile(projectPath, outputPath, packagePattern)
+	return parser.GenerateProfile(ctx, projectPath, outputPath, packagePattern)
 }
 
 // GetUncoveredFunctionsByComplexity returns uncovered functions sorted by complexity
@@ -228,12 +308,12 @@ func CalculateTrendData(current, previous *models.AnalysisResult) *models.Covera
 }
 
 // AnalyzePackage performs analysis on a specific package
-func AnalyzePackage(opts *Options, packagePath string) (*models.Package, error) {
+func AnalyzePackage(ctx context.Context, opts *Options, packagePath string) (*models.Package, error) {
 	// Modify options to target specific package
 	modifiedOpts := *opts
 	modifiedOpts.PackagePattern = packagePath
 
-	result, err := Analyze(&modifiedOpts)
+	result, err := Analyze(ctx, &modifiedOpts)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,97 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"regexp"
+	"sort"
+
+	"github.com/beck/go-coverage-analyzer/internal/i18n"
+	"github.com/beck/go-coverage-analyzer/pkg/models"
+)
+
+// AreaOptions configures the feature areas coverage is aggregated over
+type AreaOptions struct {
+	Areas      map[string][]string // area name to path patterns such as internal/billing/**
+	Thresholds map[string]float64  // minimum coverage per area
+	Verbose    bool
+}
+
+// ApplyAreas aggregates coverage per feature area. Areas are matched by CODEOWNERS-style path patterns
+// relative to the project, and a file counts towards every area one of its patterns matches
+func ApplyAreas(result *models.AnalysisResult, opts *AreaOptions) {
+	if len(opts.Areas) == 0 {
+		return
+	}
+
+	names := make([]string, 0, len(opts.Areas))
+	for name := range opts.Areas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	result.AreaCoverage = make([]*models.AreaCoverage, 0, len(names))
+	for _, name := range names {
+		patterns := make([]*regexp.Regexp, 0, len(opts.Areas[name]))
+		for _, pattern := range opts.Areas[name] {
+			patterns = append(patterns, codeownersPattern(pattern))
+		}
+
+		area := &models.AreaCoverage{
+			Area:               name,
+			Patterns:           opts.Areas[name],
+			Threshold:          opts.Thresholds[name],
+			UncoveredFunctions: make([]*models.Function, 0),
+		}
+		for _, pkg := range result.PackageCoverage {
+			for _, file := range pkg.Files {
+				if matchesAny(patterns, filepath.ToSlash(file.Path)) {
+					addAreaFile(area, file)
+				}
+			}
+		}
+
+		if area.Statements > 0 {
+			area.Coverage = float64(area.CoveredStatements) / float64(area.Statements) * 100
+		}
+		if area.TotalFunctions > 0 {
+			area.FunctionCoverage = float64(area.CoveredFunctions) / float64(area.TotalFunctions) * 100
+		}
+		sort.Slice(area.UncoveredFunctions, func(i, j int) bool {
+			return area.UncoveredFunctions[i].Complexity > area.UncoveredFunctions[j].Complexity
+		})
+		result.AreaCoverage = append(result.AreaCoverage, area)
+	}
+
+	if opts.Verbose {
+		i18n.Printf("🗺️  Aggregated coverage for %d areas\n", len(result.AreaCoverage))
+	}
+}
+
+// addAreaFile adds the testable functions of a file to an area
+func addAreaFile(area *models.AreaCoverage, file *models.File) {
+	area.Files++
+	for _, function := range file.Functions {
+		if !function.IsTestable {
+			continue
+		}
+
+		area.TotalFunctions++
+		area.Statements += function.Statements
+		area.CoveredStatements += function.CoveredStatements
+		if function.IsCovered {
+			area.CoveredFunctions++
+		} else {
+			area.UncoveredFunctions = append(area.UncoveredFunctions, function)
+		}
+	}
+}
+
+// matchesAny reports whether any of the patterns matches the path
+func matchesAny(patterns []*regexp.Regexp, path string) bool {
+	for _, pattern := range patterns {
+		if pattern.MatchString(path) {
+			return true
+		}
+	}
+	return false
+}
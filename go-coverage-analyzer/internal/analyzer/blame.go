@@ -0,0 +1,187 @@
+package analyzer
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/beck/go-coverage-analyzer/internal/command"
+	"github.com/beck/go-coverage-analyzer/internal/i18n"
+	"github.com/beck/go-coverage-analyzer/pkg/models"
+)
+
+// uncommittedAuthor is the author git blame gives lines not committed yet, which have no author to ask
+const uncommittedAuthor = "Not Committed Yet"
+
+// BlameOptions configures how the authors of uncovered code are found
+type BlameOptions struct {
+	ProjectPath    string
+	CommandTimeout time.Duration // bounds each git blame run, 0 for none
+	Verbose        bool
+}
+
+// blameLine is the author of a line according to git blame
+type blameLine struct {
+	author string
+	time   int64 // author time of the commit, in seconds since the epoch
+}
+
+// ApplyBlame records the author of most of the lines of every uncovered function, by git blame, and
+// gathers uncovered functions per author for targeted follow-ups. Each file with uncovered functions is
+// blamed once; files git does not track, such as new ones, are skipped
+func ApplyBlame(ctx context.Context, result *models.AnalysisResult, opts *BlameOptions) error {
+	if _, err := gitOutput(ctx, opts.ProjectPath, "rev-parse", "--show-toplevel"); err != nil {
+		return fmt.Errorf("%s is not in a git repository: %w", opts.ProjectPath, err)
+	}
+
+	uncoveredByFile := make(map[string][]*models.Function)
+	for _, pkg := range result.PackageCoverage {
+		for _, file := range pkg.Files {
+			for _, function := range file.Functions {
+				if function.IsTestable && !function.IsCovered {
+					uncoveredByFile[file.Path] = append(uncoveredByFile[file.Path], function)
+				}
+			}
+		}
+	}
+
+	authors := make(map[string]*models.AuthorCoverage)
+	for path, functions := range uncoveredByFile {
+		lines, err := blameFile(ctx, opts, path)
+		if err != nil {
+			if ctx.Err() != nil {
+				return context.Cause(ctx)
+			}
+			warning := fmt.Sprintf("Skipping %s for blame: %v", path, err)
+			result.Warnings = append(result.Warnings, warning)
+			if opts.Verbose {
+				i18n.Fprintf(os.Stderr, "⚠️ %s\n", warning)
+			}
+			continue
+		}
+
+		for _, function := range functions {
+			function.Author = mainAuthor(lines, function.StartLine, function.EndLine)
+			if function.Author == "" {
+				continue
+			}
+
+			author, exists := authors[function.Author]
+			if !exists {
+				author = &models.AuthorCoverage{Author: function.Author, UncoveredFunctions: make([]*models.Function, 0)}
+				authors[function.Author] = author
+			}
+			author.UncoveredFunctions = append(author.UncoveredFunctions, function)
+			author.UncoveredStatements += function.Statements - function.CoveredStatements
+		}
+	}
+
+	result.AuthorCoverage = make([]*models.AuthorCoverage, 0, len(authors))
+	for _, author := range authors {
+		sort.Slice(author.UncoveredFunctions, func(i, j int) bool {
+			return author.UncoveredFunctions[i].Complexity > author.UncoveredFunctions[j].Complexity
+		})
+		result.AuthorCoverage = append(result.AuthorCoverage, author)
+	}
+
+	// Authors with the most uncovered code first
+	sort.Slice(result.AuthorCoverage, func(i, j int) bool {
+		a, b := result.AuthorCoverage[i], result.AuthorCoverage[j]
+		if a.UncoveredStatements != b.UncoveredStatements {
+			return a.UncoveredStatements > b.UncoveredStatements
+		}
+		return a.Author < b.Author
+	})
+
+	if opts.Verbose {
+		i18n.Printf("🕵️ Blamed %d files with uncovered functions, written by %d authors\n",
+			len(uncoveredByFile), len(result.AuthorCoverage))
+	}
+
+	return nil
+}
+
+// blameFile returns the author of each line of a file, by line number from 1
+func blameFile(ctx context.Context, opts *BlameOptions, path string) ([]blameLine, error) {
+	blameCtx, cancel := command.WithTimeout(ctx, opts.CommandTimeout)
+	defer cancel()
+
+	cmd := command.New(blameCtx, "git", "blame", "--line-porcelain", "--", path)
+	cmd.Dir = opts.ProjectPath
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	output, err := cmd.Output()
+	if err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" && blameCtx.Err() == nil {
+			return nil, fmt.Errorf("git blame failed: %s", message)
+		}
+		return nil, fmt.Errorf("git blame failed: %w", command.Err(blameCtx, err))
+	}
+	return parseBlame(output), nil
+}
+
+// parseBlame reads the output of git blame --line-porcelain, where every line of the file is preceded
+// by a header naming its commit and line number, then the details of the commit, such as its author
+func parseBlame(output []byte) []blameLine {
+	lines := []blameLine{{}}
+	var current blameLine
+	number := 0
+
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "\t"):
+			// The content of the line ends its entry
+			for len(lines) <= number {
+				lines = append(lines, blameLine{})
+			}
+			lines[number] = current
+			current = blameLine{}
+		case strings.HasPrefix(line, "author "):
+			current.author = strings.TrimPrefix(line, "author ")
+		case strings.HasPrefix(line, "author-time "):
+			current.time, _ = strconv.ParseInt(strings.TrimPrefix(line, "author-time "), 10, 64)
+		default:
+			// Headers are the commit hash, the original and the final line number
+			if fields := strings.Fields(line); len(fields) >= 3 && len(fields[0]) >= 40 {
+				number, _ = strconv.Atoi(fields[2])
+			}
+		}
+	}
+	return lines
+}
+
+// mainAuthor returns the author of most of the committed lines from start to end, the most recent one
+// on a tie, or "" when none of them is committed
+func mainAuthor(lines []blameLine, start, end int) string {
+	counts := make(map[string]int)
+	latest := make(map[string]int64)
+	for number := start; number <= end && number < len(lines); number++ {
+		line := lines[number]
+		if line.author == "" || line.author == uncommittedAuthor {
+			continue
+		}
+		counts[line.author]++
+		if line.time > latest[line.author] {
+			latest[line.author] = line.time
+		}
+	}
+
+	main := ""
+	for author, count := range counts {
+		if main == "" || count > counts[main] ||
+			(count == counts[main] && (latest[author] > latest[main] || latest[author] == latest[main] && author < main)) {
+			main = author
+		}
+	}
+	return main
+}
@@ -0,0 +1,189 @@
+package analyzer
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/beck/go-coverage-analyzer/pkg/models"
+)
+
+// ChangeSetOptions identifies the change set to check for uncovered functions
+type ChangeSetOptions struct {
+	ProjectPath  string
+	DiffBase     string // git ref to diff the working tree against
+	BaselinePath string // JSON analysis result from a previous run
+}
+
+// lineRange is an inclusive range of changed lines in a file
+type lineRange struct {
+	start, end int
+}
+
+// hunkHeader matches the new-file side of a unified diff hunk header
+var hunkHeader = regexp.MustCompile(`^@@ -\d+(?:,\d+)? \+(\d+)(?:,(\d+))? @@`)
+
+// FindNewUncoveredFunctions returns uncovered functions that were added or modified in the change set
+func FindNewUncoveredFunctions(result *models.AnalysisResult, opts *ChangeSetOptions) ([]*models.Function, error) {
+	var isChanged func(*models.Function) bool
+
+	switch {
+	case opts.DiffBase != "":
+		changes, err := changedLines(opts.ProjectPath, opts.DiffBase)
+		if err != nil {
+			return nil, err
+		}
+		isChanged = func(function *models.Function) bool {
+			for _, r := range changes[filepath.ToSlash(function.File)] {
+				if r.start <= function.EndLine && r.end >= function.StartLine {
+					return true
+				}
+			}
+			return false
+		}
+	case opts.BaselinePath != "":
+		baseline, err := loadBaselineFunctions(opts.BaselinePath)
+		if err != nil {
+			return nil, err
+		}
+		isChanged = func(function *models.Function) bool {
+			previous, exists := baseline[functionKey(function)]
+			if !exists {
+				return true
+			}
+			return previous.Signature != function.Signature ||
+				previous.EndLine-previous.StartLine != function.EndLine-function.StartLine
+		}
+	default:
+		return nil, fmt.Errorf("a git diff base or baseline file is required to detect new functions")
+	}
+
+	newUncovered := make([]*models.Function, 0)
+	for _, function := range result.GetUncoveredFunctions() {
+		if isChanged(function) {
+			newUncovered = append(newUncovered, function)
+		}
+	}
+
+	sort.Slice(newUncovered, func(i, j int) bool {
+		if newUncovered[i].File != newUncovered[j].File {
+			return newUncovered[i].File < newUncovered[j].File
+		}
+		return newUncovered[i].StartLine < newUncovered[j].StartLine
+	})
+
+	return newUncovered, nil
+}
+
+// changedLines returns the lines changed since base for each Go file, keyed by path relative to the project
+func changedLines(projectPath, base string) (map[string][]lineRange, error) {
+	return diffLines(projectPath, base, false)
+}
+
+// stagedLines returns the lines of each Go file staged for commit, keyed by path relative to the project
+func stagedLines(projectPath string) (map[string][]lineRange, error) {
+	return diffLines(projectPath, "", true)
+}
+
+// diffLines returns the lines changed in the index when staged is set, else in the working tree since
+// base, including untracked files
+func diffLines(projectPath, base string, staged bool) (map[string][]lineRange, error) {
+	args := []string{"diff", "--unified=0", "--relative", "--no-color"}
+	if staged {
+		args = append(args, "--cached")
+	} else {
+		args = append(args, base)
+	}
+	cmd := exec.Command("git", append(args, "--", "*.go")...)
+	cmd.Dir = projectPath
+
+	output, err := cmd.Output()
+	if err != nil {
+		if staged {
+			return nil, fmt.Errorf("git diff of staged changes failed: %w", err)
+		}
+		return nil, fmt.Errorf("git diff against %s failed: %w", base, err)
+	}
+
+	changes := make(map[string][]lineRange)
+	currentFile := ""
+
+	scanner := bufio.NewScanner(strings.NewReader(string(output)))
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if strings.HasPrefix(line, "+++ ") {
+			currentFile = ""
+			if path := strings.TrimPrefix(line, "+++ "); path != "/dev/null" {
+				currentFile = strings.TrimPrefix(path, "b/")
+			}
+			continue
+		}
+
+		matches := hunkHeader.FindStringSubmatch(line)
+		if matches == nil || currentFile == "" {
+			continue
+		}
+
+		start, _ := strconv.Atoi(matches[1])
+		count := 1
+		if matches[2] != "" {
+			count, _ = strconv.Atoi(matches[2])
+		}
+
+		// Pure deletions touch the line the removed code was attached to
+		if count == 0 {
+			changes[currentFile] = append(changes[currentFile], lineRange{start: start, end: start})
+			continue
+		}
+		changes[currentFile] = append(changes[currentFile], lineRange{start: start, end: start + count - 1})
+	}
+
+	// Untracked files are not part of the diff but are entirely new; they are never staged
+	if staged {
+		return changes, nil
+	}
+	cmd = exec.Command("git", "ls-files", "--others", "--exclude-standard", "--", "*.go")
+	cmd.Dir = projectPath
+
+	output, err = cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list untracked files: %w", err)
+	}
+
+	for _, path := range strings.Fields(string(output)) {
+		changes[path] = []lineRange{{start: 1, end: math.MaxInt}}
+	}
+
+	return changes, nil
+}
+
+// loadBaselineFunctions reads a JSON analysis result and indexes its functions
+func loadBaselineFunctions(path string) (map[string]*models.Function, error) {
+	baseline, err := LoadResult(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read baseline: %w", err)
+	}
+
+	functions := make(map[string]*models.Function)
+	for _, pkg := range baseline.PackageCoverage {
+		for _, file := range pkg.Files {
+			for _, function := range file.Functions {
+				functions[functionKey(function)] = function
+			}
+		}
+	}
+
+	return functions, nil
+}
+
+// functionKey identifies a function across analysis runs
+func functionKey(function *models.Function) string {
+	return function.Package + "." + function.ReceiverType + "." + function.Name
+}
@@ -0,0 +1,138 @@
+package analyzer
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/beck/go-coverage-analyzer/pkg/models"
+)
+
+// DiffCoverageOptions identifies the change set whose coverage is measured
+type DiffCoverageOptions struct {
+	ProjectPath string
+	Staged      bool     // the changes staged for commit, else those since DiffBase
+	DiffBase    string   // git ref the working tree is diffed against
+	Files       []string // only these files, relative to the project, empty for all changed files
+}
+
+// DiffCoverage is the statement coverage of the functions a change set touches
+type DiffCoverage struct {
+	Functions         []*models.Function // changed testable functions, by file and line
+	Uncovered         []*models.Function // changed functions no test runs
+	Statements        int
+	CoveredStatements int
+	Coverage          float64 // 100 when the change touches no statements
+}
+
+// ChangeSet is the lines a change set touches in each Go file, keyed by path relative to the project
+type ChangeSet struct {
+	lines map[string][]lineRange
+}
+
+// LoadChangeSet reads the change set from git, keeping only opts.Files when given
+func LoadChangeSet(opts *DiffCoverageOptions) (*ChangeSet, error) {
+	var lines map[string][]lineRange
+	var err error
+	if opts.Staged {
+		lines, err = stagedLines(opts.ProjectPath)
+	} else {
+		lines, err = changedLines(opts.ProjectPath, opts.DiffBase)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(opts.Files) > 0 {
+		selected := make(map[string]bool, len(opts.Files))
+		for _, file := range opts.Files {
+			selected[filepath.ToSlash(filepath.Clean(file))] = true
+		}
+		for file := range lines {
+			if !selected[file] {
+				delete(lines, file)
+			}
+		}
+	}
+
+	return &ChangeSet{lines: lines}, nil
+}
+
+// Packages returns the directories, relative to the project, of the packages with changed Go files
+// that still exist, as Go-style patterns such as ./internal/foo
+func (c *ChangeSet) Packages(projectPath string) []string {
+	dirs := make(map[string]bool)
+	for file := range c.lines {
+		if _, err := os.Stat(filepath.Join(projectPath, filepath.FromSlash(file))); err != nil {
+			continue
+		}
+		dirs[path.Dir(file)] = true
+	}
+
+	packages := make([]string, 0, len(dirs))
+	for dir := range dirs {
+		if dir == "." {
+			packages = append(packages, ".")
+		} else {
+			packages = append(packages, "./"+dir)
+		}
+	}
+	sort.Strings(packages)
+	return packages
+}
+
+// Coverage measures how much of the changed functions the tests of the result cover
+func (c *ChangeSet) Coverage(result *models.AnalysisResult) *DiffCoverage {
+	diff := &DiffCoverage{
+		Functions: make([]*models.Function, 0),
+		Uncovered: make([]*models.Function, 0),
+	}
+
+	for _, pkg := range result.PackageCoverage {
+		for _, file := range pkg.Files {
+			if strings.HasSuffix(file.Path, "_test.go") {
+				continue
+			}
+			for _, function := range file.Functions {
+				if !function.IsTestable || !c.touches(function) {
+					continue
+				}
+
+				diff.Functions = append(diff.Functions, function)
+				diff.Statements += function.Statements
+				diff.CoveredStatements += function.CoveredStatements
+				if !function.IsCovered {
+					diff.Uncovered = append(diff.Uncovered, function)
+				}
+			}
+		}
+	}
+
+	diff.Coverage = 100
+	if diff.Statements > 0 {
+		diff.Coverage = float64(diff.CoveredStatements) / float64(diff.Statements) * 100
+	}
+
+	for _, functions := range [][]*models.Function{diff.Functions, diff.Uncovered} {
+		sort.Slice(functions, func(i, j int) bool {
+			if functions[i].File != functions[j].File {
+				return functions[i].File < functions[j].File
+			}
+			return functions[i].StartLine < functions[j].StartLine
+		})
+	}
+
+	return diff
+}
+
+// touches reports whether the change set touches a line of the function
+func (c *ChangeSet) touches(function *models.Function) bool {
+	for _, r := range c.lines[filepath.ToSlash(function.File)] {
+		if r.start <= function.EndLine && r.end >= function.StartLine {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,191 @@
+package analyzer
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/beck/go-coverage-analyzer/pkg/coverage"
+	"github.com/beck/go-coverage-analyzer/pkg/models"
+	"github.com/spf13/viper"
+)
+
+// DefaultExemptionsFile is where exemptions are read from, relative to the project
+const DefaultExemptionsFile = "gcov-exemptions.yaml"
+
+// ExpiryWarning is how long before its expiry date an exemption is reported as expiring
+const ExpiryWarning = 14 * 24 * time.Hour
+
+// exemptionDate is the layout of expiry dates; an exemption holds through the whole day
+const exemptionDate = "2006-01-02"
+
+// exemptionEntry is an exemption as listed in the exemptions file
+type exemptionEntry struct {
+	Function string `mapstructure:"function"`
+	File     string `mapstructure:"file"`
+	Reason   string `mapstructure:"reason"`
+	Expires  any    `mapstructure:"expires"` // YAML reads unquoted dates as times, quoted ones as strings
+}
+
+// LoadExemptions reads the exemptions listed in a YAML file such as
+//
+//	exemptions:
+//	  - function: LegacyClient.*
+//	    reason: replaced by the v2 client next quarter
+//	    expires: 2026-12-31
+//	  - file: internal/legacy/...
+//	    reason: scheduled for removal
+//	    expires: 2026-09-30
+//
+// A missing file lists no exemptions when required is false
+func LoadExemptions(path string, required bool) ([]*models.Exemption, error) {
+	if _, err := os.Stat(path); err != nil {
+		if errors.Is(err, os.ErrNotExist) && !required {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read exemptions: %w", err)
+	}
+
+	v := viper.New()
+	v.SetConfigFile(path)
+	v.SetConfigType("yaml")
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("failed to read exemptions: %w", err)
+	}
+
+	var file struct {
+		Exemptions []exemptionEntry `mapstructure:"exemptions"`
+	}
+	if err := v.Unmarshal(&file); err != nil {
+		return nil, fmt.Errorf("failed to parse exemptions: %w", err)
+	}
+
+	exemptions := make([]*models.Exemption, 0, len(file.Exemptions))
+	for i, entry := range file.Exemptions {
+		if (entry.Function == "") == (entry.File == "") {
+			return nil, fmt.Errorf("exemption %d of %s: set either function or file", i+1, filepath.Base(path))
+		}
+		if entry.Reason == "" {
+			return nil, fmt.Errorf("exemption %d of %s: a reason is required", i+1, filepath.Base(path))
+		}
+		expires, err := expiryDate(entry.Expires)
+		if err != nil {
+			return nil, fmt.Errorf("exemption %d of %s: expires must be a date such as 2026-12-31, got %v", i+1, filepath.Base(path), entry.Expires)
+		}
+
+		exemptions = append(exemptions, &models.Exemption{
+			Function: entry.Function,
+			File:     entry.File,
+			Reason:   entry.Reason,
+			Expires:  expires,
+		})
+	}
+	return exemptions, nil
+}
+
+// expiryDate returns the day an exemption expires, in the local time zone
+func expiryDate(value any) (time.Time, error) {
+	switch value := value.(type) {
+	case time.Time:
+		return time.Date(value.Year(), value.Month(), value.Day(), 0, 0, 0, 0, time.Local), nil
+	case string:
+		return time.ParseInLocation(exemptionDate, value, time.Local)
+	default:
+		return time.Time{}, fmt.Errorf("not a date")
+	}
+}
+
+// exemptionStatus returns whether an exemption is active, expiring soon or expired at now
+func exemptionStatus(exemption *models.Exemption, now time.Time) string {
+	end := exemption.Expires.AddDate(0, 0, 1)
+	switch {
+	case !now.Before(end):
+		return models.ExemptionExpired
+	case end.Sub(now) <= ExpiryWarning:
+		return models.ExemptionExpiring
+	default:
+		return models.ExemptionActive
+	}
+}
+
+// ExemptionPatterns returns the function and file patterns of the exemptions not expired at now, for
+// the analysis to leave out of the totals
+func ExemptionPatterns(exemptions []*models.Exemption, now time.Time) (functions, files []string) {
+	for _, exemption := range exemptions {
+		if exemptionStatus(exemption, now) == models.ExemptionExpired {
+			continue
+		}
+		if exemption.Function != "" {
+			functions = append(functions, exemption.Function)
+		} else {
+			files = append(files, exemption.File)
+		}
+	}
+	return functions, files
+}
+
+// ApplyExemptions records in the result the status of each exemption at now, with the functions it
+// matches and their uncovered statements: the exempted ones while it holds, the testable ones once it
+// has expired
+func ApplyExemptions(result *models.AnalysisResult, exemptions []*models.Exemption, now time.Time) {
+	if len(exemptions) == 0 {
+		return
+	}
+
+	candidates := make([]*models.Function, 0)
+	for _, pkg := range result.PackageCoverage {
+		for _, file := range pkg.Files {
+			for _, function := range file.Functions {
+				if function.IsTestable {
+					candidates = append(candidates, function)
+				}
+			}
+		}
+	}
+	for _, function := range result.UntestableFunctions {
+		if function.UntestableReason == models.UntestableExempted {
+			candidates = append(candidates, function)
+		}
+	}
+
+	result.Exemptions = make([]*models.Exemption, 0, len(exemptions))
+	for _, exemption := range exemptions {
+		status := *exemption
+		status.Status = exemptionStatus(exemption, now)
+		status.Functions = nil
+		status.UncoveredStatements = 0
+
+		for _, function := range candidates {
+			if function.IsTestable != (status.Status == models.ExemptionExpired) {
+				continue
+			}
+			if !matchExemption(exemption, function) {
+				continue
+			}
+			status.Functions = append(status.Functions, function.Key())
+			status.UncoveredStatements += function.Statements - function.CoveredStatements
+		}
+		result.Exemptions = append(result.Exemptions, &status)
+	}
+}
+
+// matchExemption reports whether a function falls under an exemption, by its name or its file
+func matchExemption(exemption *models.Exemption, function *models.Function) bool {
+	if exemption.Function != "" {
+		return coverage.MatchFunctionPatterns([]string{exemption.Function}, function)
+	}
+	return coverage.MatchFilePatterns([]string{exemption.File}, filepath.ToSlash(function.File))
+}
+
+// ExpiredExemptions returns the expired exemptions of a result whose code is still uncovered
+func ExpiredExemptions(result *models.AnalysisResult) []*models.Exemption {
+	var expired []*models.Exemption
+	for _, exemption := range result.Exemptions {
+		if exemption.Status == models.ExemptionExpired && exemption.UncoveredStatements > 0 {
+			expired = append(expired, exemption)
+		}
+	}
+	return expired
+}
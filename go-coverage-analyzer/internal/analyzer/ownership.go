@@ -0,0 +1,266 @@
+package analyzer
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/beck/go-coverage-analyzer/internal/i18n"
+	"github.com/beck/go-coverage-analyzer/pkg/models"
+)
+
+// UnownedOwner groups files that no ownership rule matches
+const UnownedOwner = "(unowned)"
+
+// codeownersLocations are the paths GitHub and GitLab read CODEOWNERS from, relative to the repository root
+var codeownersLocations = []string{"CODEOWNERS", ".github/CODEOWNERS", "docs/CODEOWNERS"}
+
+// OwnershipOptions configures how file owners are resolved
+type OwnershipOptions struct {
+	ProjectPath string
+	Owners      map[string][]string // pattern to owners mapping, used instead of CODEOWNERS when set
+	Verbose     bool
+}
+
+// ownershipRule maps a CODEOWNERS-style pattern to its owners
+type ownershipRule struct {
+	pattern *regexp.Regexp
+	owners  []string
+}
+
+// ApplyOwnership assigns owners to every file and aggregates coverage per owner
+func ApplyOwnership(result *models.AnalysisResult, opts *OwnershipOptions) error {
+	rules, prefix, err := loadOwnershipRules(opts)
+	if err != nil {
+		return err
+	}
+
+	owners := make(map[string]*models.OwnerCoverage)
+	ownerFor := func(name string) *models.OwnerCoverage {
+		if owner, exists := owners[name]; exists {
+			return owner
+		}
+		owner := &models.OwnerCoverage{Owner: name, UncoveredFunctions: make([]*models.Function, 0)}
+		owners[name] = owner
+		return owner
+	}
+
+	for _, pkg := range result.PackageCoverage {
+		for _, file := range pkg.Files {
+			file.Owners = matchOwners(rules, filepath.ToSlash(filepath.Join(prefix, file.Path)))
+
+			fileOwners := file.Owners
+			if len(fileOwners) == 0 {
+				fileOwners = []string{UnownedOwner}
+			}
+
+			for _, name := range fileOwners {
+				owner := ownerFor(name)
+				owner.Files++
+
+				for _, function := range file.Functions {
+					if !function.IsTestable {
+						continue
+					}
+
+					owner.TotalFunctions++
+					owner.Statements += function.Statements
+					owner.CoveredStatements += function.CoveredStatements
+					if function.IsCovered {
+						owner.CoveredFunctions++
+					} else {
+						owner.UncoveredFunctions = append(owner.UncoveredFunctions, function)
+					}
+				}
+			}
+		}
+	}
+
+	result.OwnerCoverage = make([]*models.OwnerCoverage, 0, len(owners))
+	for _, owner := range owners {
+		if owner.Statements > 0 {
+			owner.Coverage = float64(owner.CoveredStatements) / float64(owner.Statements) * 100
+		}
+		if owner.TotalFunctions > 0 {
+			owner.FunctionCoverage = float64(owner.CoveredFunctions) / float64(owner.TotalFunctions) * 100
+		}
+
+		sort.Slice(owner.UncoveredFunctions, func(i, j int) bool {
+			return owner.UncoveredFunctions[i].Complexity > owner.UncoveredFunctions[j].Complexity
+		})
+		result.OwnerCoverage = append(result.OwnerCoverage, owner)
+	}
+
+	sort.Slice(result.OwnerCoverage, func(i, j int) bool {
+		return result.OwnerCoverage[i].Owner < result.OwnerCoverage[j].Owner
+	})
+
+	if opts.Verbose {
+		i18n.Printf("👥 Grouped coverage by %d owners\n", len(result.OwnerCoverage))
+	}
+
+	return nil
+}
+
+// loadOwnershipRules returns the ownership rules and the prefix that maps project-relative paths onto them
+func loadOwnershipRules(opts *OwnershipOptions) ([]*ownershipRule, string, error) {
+	if len(opts.Owners) > 0 {
+		// More specific (longer) patterns are applied last so they take precedence
+		patterns := make([]string, 0, len(opts.Owners))
+		for pattern := range opts.Owners {
+			patterns = append(patterns, pattern)
+		}
+		sort.Slice(patterns, func(i, j int) bool {
+			if len(patterns[i]) != len(patterns[j]) {
+				return len(patterns[i]) < len(patterns[j])
+			}
+			return patterns[i] < patterns[j]
+		})
+
+		rules := make([]*ownershipRule, 0, len(patterns))
+		for _, pattern := range patterns {
+			rules = append(rules, &ownershipRule{pattern: codeownersPattern(pattern), owners: opts.Owners[pattern]})
+		}
+		return rules, "", nil
+	}
+
+	path, root := findCodeowners(opts.ProjectPath)
+	if path == "" {
+		return nil, "", fmt.Errorf("no CODEOWNERS file found and no owners mapping configured")
+	}
+
+	if opts.Verbose {
+		i18n.Printf("👥 Using ownership rules from %s\n", path)
+	}
+
+	rules, err := parseCodeowners(path)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// CODEOWNERS patterns are relative to the repository root, not the project
+	prefix := ""
+	if absProject, err := filepath.Abs(opts.ProjectPath); err == nil {
+		if absRoot, err := filepath.Abs(root); err == nil {
+			if rel, err := filepath.Rel(absRoot, absProject); err == nil && rel != "." {
+				prefix = rel
+			}
+		}
+	}
+
+	return rules, prefix, nil
+}
+
+// findCodeowners looks for a CODEOWNERS file in the project and then in its git repository root
+func findCodeowners(projectPath string) (string, string) {
+	roots := []string{projectPath}
+
+	cmd := exec.Command("git", "rev-parse", "--show-toplevel")
+	cmd.Dir = projectPath
+	if output, err := cmd.Output(); err == nil {
+		roots = append(roots, strings.TrimSpace(string(output)))
+	}
+
+	for _, root := range roots {
+		for _, location := range codeownersLocations {
+			path := filepath.Join(root, location)
+			if info, err := os.Stat(path); err == nil && !info.IsDir() {
+				return path, root
+			}
+		}
+	}
+
+	return "", ""
+}
+
+// parseCodeowners reads ownership rules from a CODEOWNERS file
+func parseCodeowners(path string) ([]*ownershipRule, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open CODEOWNERS: %w", err)
+	}
+	defer file.Close()
+
+	rules := make([]*ownershipRule, 0)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		// Strip trailing comments
+		if idx := strings.Index(line, " #"); idx >= 0 {
+			line = strings.TrimSpace(line[:idx])
+		}
+
+		fields := strings.Fields(line)
+		rules = append(rules, &ownershipRule{
+			pattern: codeownersPattern(fields[0]),
+			owners:  fields[1:],
+		})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read CODEOWNERS: %w", err)
+	}
+
+	return rules, nil
+}
+
+// matchOwners returns the owners of the last rule matching the path, following CODEOWNERS precedence
+func matchOwners(rules []*ownershipRule, path string) []string {
+	for i := len(rules) - 1; i >= 0; i-- {
+		if rules[i].pattern.MatchString(path) {
+			return rules[i].owners
+		}
+	}
+	return nil
+}
+
+// codeownersPattern converts a gitignore-style CODEOWNERS pattern into a regular expression
+func codeownersPattern(pattern string) *regexp.Regexp {
+	dirOnly := strings.HasSuffix(pattern, "/")
+	trimmed := strings.Trim(pattern, "/")
+
+	// Patterns with a leading or inner slash are anchored to the root
+	anchored := strings.HasPrefix(pattern, "/") || strings.Contains(trimmed, "/")
+
+	var expr strings.Builder
+	if anchored {
+		expr.WriteString("^")
+	} else {
+		expr.WriteString("^(?:.*/)?")
+	}
+
+	for i := 0; i < len(trimmed); i++ {
+		switch {
+		case strings.HasPrefix(trimmed[i:], "**/"):
+			expr.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(trimmed[i:], "**"):
+			expr.WriteString(".*")
+			i++
+		case trimmed[i] == '*':
+			expr.WriteString("[^/]*")
+		case trimmed[i] == '?':
+			expr.WriteString("[^/]")
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(trimmed[i])))
+		}
+	}
+
+	// A pattern naming a directory owns everything beneath it
+	if dirOnly {
+		expr.WriteString("/.*$")
+	} else {
+		expr.WriteString("(?:/.*)?$")
+	}
+
+	return regexp.MustCompile(expr.String())
+}
@@ -0,0 +1,97 @@
+package analyzer
+
+import (
+	"sort"
+
+	"github.com/beck/go-coverage-analyzer/pkg/models"
+)
+
+// Effort levels used in test prioritization
+const (
+	EffortLow    = "low"
+	EffortMedium = "medium"
+	EffortHigh   = "high"
+)
+
+// exportedPriorityBoost favours exported functions since they form the public contract
+const exportedPriorityBoost = 1.25
+
+// PrioritizeTests ranks uncovered functions by the coverage they would add if tested.
+// The score combines the estimated coverage gain, complexity and export status; the
+// returned backlog is ordered by descending score and includes cumulative projections.
+func PrioritizeTests(result *models.AnalysisResult, limit int) []*models.TestPriority {
+	totalStmts := 0
+	if result.Summary != nil {
+		totalStmts = result.Summary.TotalLines
+	}
+
+	priorities := make([]*models.TestPriority, 0, len(result.UncoveredFunctions))
+	for _, function := range result.UncoveredFunctions {
+		gain := estimateCoverageGain(function, totalStmts)
+
+		score := gain * (1.0 + float64(function.Complexity)/10.0)
+		if function.IsExported {
+			score *= exportedPriorityBoost
+		}
+
+		priorities = append(priorities, &models.TestPriority{
+			Function:      function,
+			Score:         score,
+			EstimatedGain: gain,
+			Effort:        estimateTestEffort(function),
+		})
+	}
+
+	sort.SliceStable(priorities, func(i, j int) bool {
+		if priorities[i].Score != priorities[j].Score {
+			return priorities[i].Score > priorities[j].Score
+		}
+		a, b := priorities[i].Function, priorities[j].Function
+		if a.Complexity != b.Complexity {
+			return a.Complexity > b.Complexity
+		}
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.StartLine < b.StartLine
+	})
+
+	if limit > 0 && len(priorities) > limit {
+		priorities = priorities[:limit]
+	}
+
+	cumulative := 0.0
+	for i, priority := range priorities {
+		cumulative += priority.EstimatedGain
+		priority.Rank = i + 1
+		priority.CumulativeGain = cumulative
+		priority.ProjectedCoverage = result.OverallCoverage + cumulative
+		if priority.ProjectedCoverage > 100.0 {
+			priority.ProjectedCoverage = 100.0
+		}
+	}
+
+	return priorities
+}
+
+// estimateCoverageGain returns the percentage of total statements covered by testing a function
+func estimateCoverageGain(function *models.Function, totalStmts int) float64 {
+	if totalStmts == 0 {
+		return 0.0
+	}
+
+	uncovered := function.Statements - function.CoveredStatements
+	return float64(uncovered) / float64(totalStmts) * 100.0
+}
+
+// estimateTestEffort classifies how much work a test for the function is likely to need
+func estimateTestEffort(function *models.Function) string {
+	switch {
+	case function.Complexity > 8 || function.CallsExternal || len(function.Dependencies) > 0:
+		return EffortHigh
+	case function.Complexity > 3 || len(function.Parameters) > 2 || function.IsMethod:
+		return EffortMedium
+	default:
+		return EffortLow
+	}
+}
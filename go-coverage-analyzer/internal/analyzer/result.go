@@ -0,0 +1,45 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/beck/go-coverage-analyzer/internal/storage"
+	"github.com/beck/go-coverage-analyzer/pkg/models"
+)
+
+// SaveResult writes the full analysis result as JSON to a path or storage URL, so reports can be
+// generated from it later without analyzing the project again
+func SaveResult(result *models.AnalysisResult, path string) error {
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal analysis result: %w", err)
+	}
+	if err := storage.WriteFile(path, data); err != nil {
+		return fmt.Errorf("failed to save analysis result: %w", err)
+	}
+	return nil
+}
+
+// LoadResult reads an analysis result saved with SaveResult or written by gcov analyze --output json,
+// rejecting results of another schema major version
+func LoadResult(path string) (*models.AnalysisResult, error) {
+	data, err := storage.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var result models.AnalysisResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if err := models.CheckSchemaVersion(result.SchemaVersion); err != nil {
+		return nil, err
+	}
+	if result.Summary == nil || result.Metadata == nil {
+		return nil, fmt.Errorf("%s is not a gcov analysis result", path)
+	}
+	result.LinkFunctions()
+
+	return &result, nil
+}
@@ -0,0 +1,87 @@
+package analyzer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/beck/go-coverage-analyzer/internal/command"
+	"github.com/beck/go-coverage-analyzer/internal/i18n"
+	"github.com/beck/go-coverage-analyzer/pkg/models"
+)
+
+// AnalyzeRevision analyzes the project as it was at a git ref: the ref is checked out into a temporary
+// worktree, where the tests run to generate its profile, and the worktree is removed afterwards. The
+// working tree of the project is left alone, so uncommitted changes can be compared against the ref
+func AnalyzeRevision(ctx context.Context, opts *Options, ref string) (*models.AnalysisResult, error) {
+	projectPath, err := filepath.Abs(opts.ProjectPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve project path: %w", err)
+	}
+
+	// The project may be a module inside the repository, found at the same place in the worktree
+	root, err := gitOutput(ctx, projectPath, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, fmt.Errorf("%s is not in a git repository: %w", opts.ProjectPath, err)
+	}
+	rel, err := filepath.Rel(root, projectPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate the project in its repository: %w", err)
+	}
+
+	tmpDir, err := os.MkdirTemp("", "gcov-revision-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	worktree := filepath.Join(tmpDir, "worktree")
+	if _, err := gitOutput(ctx, root, "worktree", "add", "--detach", worktree, ref); err != nil {
+		return nil, fmt.Errorf("failed to check out %s: %w", ref, err)
+	}
+	defer func() {
+		// Removed even when gcov was interrupted, so git does not keep a stale worktree entry
+		if _, err := gitOutput(context.Background(), root, "worktree", "remove", "--force", worktree); err != nil {
+			i18n.Fprintf(os.Stderr, "⚠️ Failed to remove the worktree of %s: %v\n", ref, err)
+		}
+	}()
+
+	if opts.Verbose {
+		i18n.Printf("🔀 Checked out %s into %s\n", ref, worktree)
+	}
+
+	revisionOpts := *opts
+	revisionOpts.ProjectPath = filepath.Join(worktree, rel)
+	revisionOpts.GenerateProfile = true
+	revisionOpts.ProfilePath = ""
+	revisionOpts.ProfileOutput = filepath.Join(tmpDir, "coverage.out")
+	revisionOpts.Scope = ""
+	revisionOpts.ParseCache = nil
+
+	result, err := Analyze(ctx, &revisionOpts)
+	if err != nil {
+		return nil, fmt.Errorf("analysis of %s failed: %w", ref, err)
+	}
+	return result, nil
+}
+
+// gitOutput runs git in dir and returns its trimmed output, with git's message when it fails
+func gitOutput(ctx context.Context, dir string, args ...string) (string, error) {
+	cmd := command.New(ctx, "git", args...)
+	cmd.Dir = dir
+
+	output, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(bytes.TrimSpace(exitErr.Stderr)) > 0 {
+			return "", errors.New(string(bytes.TrimSpace(exitErr.Stderr)))
+		}
+		return "", command.Err(ctx, err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
@@ -0,0 +1,52 @@
+package analyzer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+)
+
+// Names of the pprof profiles written to Options.PprofDir
+const (
+	CPUProfileFile  = "cpu.pprof"
+	HeapProfileFile = "heap.pprof"
+)
+
+// startPprof starts a CPU profile into dir and returns the function stopping it and writing a heap
+// profile next to it
+func startPprof(dir string) (func() error, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create pprof directory: %w", err)
+	}
+
+	cpuFile, err := os.Create(filepath.Join(dir, CPUProfileFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CPU profile: %w", err)
+	}
+	if err := pprof.StartCPUProfile(cpuFile); err != nil {
+		cpuFile.Close()
+		return nil, fmt.Errorf("failed to start CPU profile: %w", err)
+	}
+
+	return func() error {
+		pprof.StopCPUProfile()
+		if err := cpuFile.Close(); err != nil {
+			return fmt.Errorf("failed to write CPU profile: %w", err)
+		}
+
+		heapFile, err := os.Create(filepath.Join(dir, HeapProfileFile))
+		if err != nil {
+			return fmt.Errorf("failed to create heap profile: %w", err)
+		}
+		defer heapFile.Close()
+
+		// Collect garbage first so the profile shows what the result keeps alive
+		runtime.GC()
+		if err := pprof.WriteHeapProfile(heapFile); err != nil {
+			return fmt.Errorf("failed to write heap profile: %w", err)
+		}
+		return nil
+	}, nil
+}
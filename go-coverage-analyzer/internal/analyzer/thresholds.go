@@ -0,0 +1,95 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/beck/go-coverage-analyzer/pkg/models"
+)
+
+// Thresholds are the minimum percentages an analysis must reach per metric; 0 disables a metric
+type Thresholds struct {
+	Overall  float64
+	Function float64
+	Branch   float64
+	Package  float64 // minimum coverage of the worst package
+}
+
+// ThresholdFailure is a metric below its minimum
+type ThresholdFailure struct {
+	Metric  string // overall, function, branch, package or area
+	Actual  float64
+	Minimum float64
+	Name    string // worst package for the package metric, the area for the area metric
+	Below   int    // packages below the minimum, for the package metric
+}
+
+// Format describes the failure for the failure summary, with its percentages formatted by percent
+func (f *ThresholdFailure) Format(percent func(float64) string) string {
+	switch f.Metric {
+	case "package":
+		return fmt.Sprintf("package coverage %s of %s is below %s (%d packages below)", percent(f.Actual), f.Name, percent(f.Minimum), f.Below)
+	case "area":
+		return fmt.Sprintf("area %s coverage %s is below %s", f.Name, percent(f.Actual), percent(f.Minimum))
+	}
+	return fmt.Sprintf("%s coverage %s is below %s", f.Metric, percent(f.Actual), percent(f.Minimum))
+}
+
+// CheckThresholds returns the metrics of the result that fall below their thresholds, in the order
+// overall, function, branch, package, followed by the areas below their own thresholds
+func CheckThresholds(result *models.AnalysisResult, thresholds *Thresholds) []*ThresholdFailure {
+	var failures []*ThresholdFailure
+
+	metrics := []struct {
+		name    string
+		actual  float64
+		minimum float64
+	}{
+		{"overall", result.OverallCoverage, thresholds.Overall},
+		{"function", result.FunctionCoverage, thresholds.Function},
+		{"branch", result.BranchCoverage, thresholds.Branch},
+	}
+	for _, metric := range metrics {
+		if metric.minimum > 0 && metric.actual < metric.minimum {
+			failures = append(failures, &ThresholdFailure{Metric: metric.name, Actual: metric.actual, Minimum: metric.minimum})
+		}
+	}
+
+	if thresholds.Package > 0 {
+		if failure := worstPackageFailure(result, thresholds.Package); failure != nil {
+			failures = append(failures, failure)
+		}
+	}
+
+	for _, area := range result.AreaCoverage {
+		if area.Threshold > 0 && area.Coverage < area.Threshold {
+			failures = append(failures, &ThresholdFailure{Metric: "area", Actual: area.Coverage, Minimum: area.Threshold, Name: area.Area})
+		}
+	}
+
+	return failures
+}
+
+// worstPackageFailure returns the failure of the package with the lowest coverage, or nil when every
+// package reaches the minimum
+func worstPackageFailure(result *models.AnalysisResult, minimum float64) *ThresholdFailure {
+	below := result.GetLowCoveragePackages(minimum)
+	if len(below) == 0 {
+		return nil
+	}
+
+	// Lowest coverage first, by name on ties so the reported package is stable
+	sort.Slice(below, func(i, j int) bool {
+		if below[i].Coverage != below[j].Coverage {
+			return below[i].Coverage < below[j].Coverage
+		}
+		return below[i].Name < below[j].Name
+	})
+
+	worst := below[0]
+	name := worst.Path
+	if name == "" {
+		name = worst.Name
+	}
+	return &ThresholdFailure{Metric: "package", Actual: worst.Coverage, Minimum: minimum, Name: name, Below: len(below)}
+}
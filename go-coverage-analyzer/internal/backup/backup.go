@@ -0,0 +1,173 @@
+// Package backup keeps the previous content of files gcov overwrites, one directory per run below
+// .gcov/backups, so they can be restored with gcov restore
+package backup
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/beck/go-coverage-analyzer/internal/fileutil"
+)
+
+// DefaultDir is the backup directory, relative to the project
+const DefaultDir = ".gcov/backups"
+
+// runTimeFormat names run directories so they sort chronologically
+const runTimeFormat = "20060102T150405Z"
+
+// Run collects the backups of one gcov run
+type Run struct {
+	root  string // absolute project directory
+	id    string
+	saved int
+}
+
+// NewRun starts a backup run for a project, named by the current time; nothing is written until the
+// first file is saved
+func NewRun(projectPath string) (*Run, error) {
+	root, err := filepath.Abs(projectPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get absolute path: %w", err)
+	}
+	return &Run{root: root, id: time.Now().UTC().Format(runTimeFormat)}, nil
+}
+
+// ID returns the name of the run, which gcov restore --run accepts
+func (r *Run) ID() string {
+	return r.id
+}
+
+// Saved returns how many files the run backed up
+func (r *Run) Saved() int {
+	if r == nil {
+		return 0
+	}
+	return r.saved
+}
+
+// Save keeps the content a file had before it is overwritten. The first content saved for a file in a
+// run wins, so a file written twice restores to its state before the run. A nil run saves nothing
+func (r *Run) Save(path string, content []byte) error {
+	if r == nil {
+		return nil
+	}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("failed to get absolute path: %w", err)
+	}
+	relPath, err := filepath.Rel(r.root, absPath)
+	if err != nil || relPath == ".." || strings.HasPrefix(relPath, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("%s is outside the project", path)
+	}
+
+	backupPath := filepath.Join(r.root, DefaultDir, r.id, relPath)
+	if _, err := os.Stat(backupPath); err == nil {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(backupPath), 0755); err != nil {
+		return fmt.Errorf("failed to create backup directory: %w", err)
+	}
+	if err := fileutil.WriteAtomic(backupPath, content, 0644); err != nil {
+		return fmt.Errorf("failed to back up %s: %w", relPath, err)
+	}
+	r.saved++
+	return nil
+}
+
+// ListRuns returns the backup runs of a project, newest first
+func ListRuns(projectPath string) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(projectPath, DefaultDir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []string{}, nil
+		}
+		return nil, fmt.Errorf("failed to read backups: %w", err)
+	}
+
+	runs := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() {
+			runs = append(runs, entry.Name())
+		}
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(runs)))
+	return runs, nil
+}
+
+// Files returns the slash-separated paths, relative to the project, of the files a run backed up
+func Files(projectPath, run string) ([]string, error) {
+	runDir := filepath.Join(projectPath, DefaultDir, run)
+	if run == "" || strings.ContainsAny(run, `/\.`) {
+		return nil, fmt.Errorf("invalid backup run %q", run)
+	}
+	if _, err := os.Stat(runDir); err != nil {
+		return nil, fmt.Errorf("backup run %s not found", run)
+	}
+
+	files := make([]string, 0)
+	err := filepath.WalkDir(runDir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		relPath, err := filepath.Rel(runDir, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, filepath.ToSlash(relPath))
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list backup run %s: %w", run, err)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// Restore writes the backed up content of a run back to the project, for the given files or every
+// file of the run when none are given, and returns the files restored
+func Restore(projectPath, run string, only []string) ([]string, error) {
+	files, err := Files(projectPath, run)
+	if err != nil {
+		return nil, err
+	}
+
+	backedUp := make(map[string]bool)
+	for _, file := range files {
+		backedUp[file] = true
+	}
+	selected := make(map[string]bool)
+	for _, file := range only {
+		file = filepath.ToSlash(filepath.Clean(file))
+		if !backedUp[file] {
+			return nil, fmt.Errorf("backup run %s has no copy of %s", run, file)
+		}
+		selected[file] = true
+	}
+
+	restored := make([]string, 0, len(files))
+	for _, file := range files {
+		if len(selected) > 0 && !selected[file] {
+			continue
+		}
+
+		content, err := os.ReadFile(filepath.Join(projectPath, DefaultDir, run, filepath.FromSlash(file)))
+		if err != nil {
+			return restored, fmt.Errorf("failed to read backup of %s: %w", file, err)
+		}
+		target := filepath.Join(projectPath, filepath.FromSlash(file))
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return restored, fmt.Errorf("failed to create directory for %s: %w", file, err)
+		}
+		if err := fileutil.WriteAtomic(target, content, 0644); err != nil {
+			return restored, fmt.Errorf("failed to restore %s: %w", file, err)
+		}
+		restored = append(restored, file)
+	}
+	return restored, nil
+}
@@ -0,0 +1,76 @@
+// Package command starts the go and git subprocesses gcov runs, bound to a context so that
+// interrupting gcov also stops them and every process they started, such as the test binaries of go test
+package command
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"time"
+)
+
+// waitDelay bounds how long a cancelled command may keep its output pipes open
+const waitDelay = 5 * time.Second
+
+// TimeoutError is the cause of a context cancelled by WithTimeout because a command ran too long
+type TimeoutError struct {
+	Timeout time.Duration
+}
+
+// Error reports the timeout, and how to raise it
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("timed out after %s (raise it with --command-timeout or command_timeout)", e.Timeout)
+}
+
+// New returns a command that is killed, along with its child processes, when ctx is done
+func New(ctx context.Context, name string, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, name, args...)
+	killProcessTree(cmd)
+	cmd.WaitDelay = waitDelay
+	return cmd
+}
+
+// Go returns a go command bound to ctx like New, running the go tool found by GoTool
+func Go(ctx context.Context, args ...string) *exec.Cmd {
+	return New(ctx, GoTool(), args...)
+}
+
+// GoTool returns the go tool to run: the one on PATH, which exec.LookPath finds as go.exe on Windows,
+// or else the one in $GOROOT/bin, as IDEs and CI images often leave GOROOT set without adding it to PATH
+func GoTool() string {
+	if path, err := exec.LookPath("go"); err == nil {
+		return path
+	}
+	if goroot := os.Getenv("GOROOT"); goroot != "" {
+		tool := filepath.Join(goroot, "bin", "go")
+		if runtime.GOOS == "windows" {
+			tool += ".exe"
+		}
+		if _, err := os.Stat(tool); err == nil {
+			return tool
+		}
+	}
+	// Let the command fail with the usual not found error
+	return "go"
+}
+
+// WithTimeout returns a context for one command that is cancelled with a *TimeoutError cause once
+// timeout passes; a timeout of 0 or less leaves the command without one
+func WithTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeoutCause(ctx, timeout, &TimeoutError{Timeout: timeout})
+}
+
+// Err returns why a command bound to ctx failed: the *TimeoutError when it timed out, the
+// cancellation when gcov was interrupted, and err otherwise
+func Err(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return context.Cause(ctx)
+	}
+	return err
+}
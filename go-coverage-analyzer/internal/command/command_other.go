@@ -0,0 +1,11 @@
+//go:build !unix && !windows
+
+package command
+
+import "os/exec"
+
+// killProcessTree leaves the default cancellation, which kills the command itself
+func killProcessTree(cmd *exec.Cmd) {}
+
+// Detach leaves the command as it is, as there is no session to start it in
+func Detach(cmd *exec.Cmd) {}
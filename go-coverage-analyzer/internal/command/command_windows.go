@@ -0,0 +1,29 @@
+//go:build windows
+
+package command
+
+import (
+	"os/exec"
+	"strconv"
+	"syscall"
+)
+
+// detachedProcess is the DETACHED_PROCESS creation flag, which syscall does not define
+const detachedProcess = 0x00000008
+
+// killProcessTree kills the command and the processes it started with taskkill /T on cancellation,
+// as killing go.exe alone leaves the test binaries it runs behind
+func killProcessTree(cmd *exec.Cmd) {
+	cmd.Cancel = func() error {
+		if err := exec.Command("taskkill", "/T", "/F", "/PID", strconv.Itoa(cmd.Process.Pid)).Run(); err != nil {
+			return cmd.Process.Kill()
+		}
+		return nil
+	}
+}
+
+// Detach starts the command without a console and in a process group of its own, so it keeps running
+// after gcov and the console that started it exit
+func Detach(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{CreationFlags: detachedProcess | syscall.CREATE_NEW_PROCESS_GROUP}
+}
@@ -4,44 +4,103 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/spf13/viper"
 )
 
 // Config represents the application configuration
 type Config struct {
+	File                string    `mapstructure:"-"` // the file the configuration was read from, "" for the defaults
+
 	// Analysis settings
 	ExcludeDirs         []string  `mapstructure:"exclude_dirs"`
 	IncludeTests        bool      `mapstructure:"include_tests"`
 	CoverageThreshold   float64   `mapstructure:"coverage_threshold"`
 	CalculateComplexity bool      `mapstructure:"calculate_complexity"`
 	MinComplexity       int       `mapstructure:"min_complexity"`
+	HighComplexityThreshold int   `mapstructure:"high_complexity_threshold"` // complexity above which functions count as highly complex
+	FunctionLimits      FunctionLimitsConfig `mapstructure:"function_limits"`
+	IncludePackages     []string  `mapstructure:"include_packages"` // Go-style patterns such as ./internal/...
+	ExcludePackages     []string  `mapstructure:"exclude_packages"`
+	Classification      ClassificationConfig `mapstructure:"classification"`
+	FailUnder           FailUnderConfig `mapstructure:"fail_under"`
+	ExemptionsFile      string    `mapstructure:"exemptions_file"` // YAML list of functions and files exempted from thresholds until they expire
+	Strict              bool      `mapstructure:"strict"` // fail when the analysis warns, e.g. about profile files not in the project
+	RequireFreshProfile bool      `mapstructure:"require_fresh_profile"` // fail when sources changed after the coverage profile was written
 	
 	// Output and reporting settings
 	OutputFormat        string    `mapstructure:"output_format"`
 	OutputDir           string    `mapstructure:"output_dir"`
 	Verbose             bool      `mapstructure:"verbose"`
+	Color               string    `mapstructure:"color"` // auto, always or never
+	Plain               bool      `mapstructure:"plain"` // ASCII tags such as [OK] and [WARN] instead of emojis
+	Precision           int       `mapstructure:"precision"` // decimals of the percentages reports print
+	Absolute            bool      `mapstructure:"absolute"` // lead with covered/total counts instead of percentages
+	CI                  string    `mapstructure:"ci"` // teamcity or bamboo to also print coverage statistics for that CI server
+	MessagesFile        string    `mapstructure:"messages_file"` // JSON catalog translating user-facing messages
 	ProfileOutput       string    `mapstructure:"profile_output"`
+	StreamProfile       bool      `mapstructure:"stream_profile"` // read profiles line by line, for huge monorepos
+	IncludeInit         bool      `mapstructure:"include_init"` // report init functions and package-level variable initializers as initialization
+	IncludeCgo          bool      `mapstructure:"include_cgo"` // count functions of cgo files, not instrumentable, in the totals
+	HistoryDir          string    `mapstructure:"history_dir"` // directory, or s3://, gs:// or http(s):// URL
+	Storage             StorageConfig `mapstructure:"storage"`
 	
 	// Test generation settings
 	TemplateStyle       string    `mapstructure:"template_style"`
 	GenerateMocks       bool      `mapstructure:"generate_mocks"`
+	MockFramework       string    `mapstructure:"mock_framework"`
 	TableDriven         bool      `mapstructure:"table_driven"`
+	ParallelTests       bool      `mapstructure:"parallel_tests"`
 	GenerateBenchmarks  bool      `mapstructure:"generate_benchmarks"`
+	BenchmarkBaseline   bool      `mapstructure:"benchmark_baseline"`
 	OverwriteTests      bool      `mapstructure:"overwrite_tests"`
+	BackupOverwritten   bool      `mapstructure:"backup_overwritten"` // keep overwritten files in .gcov/backups for gcov restore
 	MaxTestCases        int       `mapstructure:"max_test_cases"`
-	IgnoreFunctions     []string  `mapstructure:"ignore_functions"`
+	IgnoreFunctions     []string  `mapstructure:"ignore_functions"` // function patterns left out of totals and generation, such as Must*
+	IncludeUnexported   bool      `mapstructure:"include_unexported"`
+	UnexportedMinComplexity int   `mapstructure:"unexported_min_complexity"`
+	EvaluateExpected    bool      `mapstructure:"evaluate_expected"`
+	StressTests         bool      `mapstructure:"stress_tests"`
+	GenerateExamples    bool      `mapstructure:"generate_examples"`
+	SuiteStyle          string    `mapstructure:"suite_style"`
+	MainHarness         bool      `mapstructure:"main_harness"` // generate a subprocess harness for main packages
+	TestPackageMode     string    `mapstructure:"test_package_mode"` // same, external (package foo_test) or auto
+	TestNamePattern     string    `mapstructure:"test_name_pattern"`
+	Seed                int64     `mapstructure:"seed"`
 	
 	// Template configuration
 	Templates           TemplateConfig `mapstructure:"templates"`
 	
+	// Report layout templates
+	ReportTemplates     ReportTemplatesConfig `mapstructure:"report_templates"`
+	
+	// Validation settings
+	Validation          ValidationConfig `mapstructure:"validation"`
+	
+	// Test smell settings
+	TestLint            TestLintConfig `mapstructure:"test_lint"`
+	
+	// Git hook settings
+	Hook                HookConfig `mapstructure:"hook"`
+	
+	// Notification settings
+	Notifications       NotificationConfig `mapstructure:"notifications"`
+	
+	// AI test generation settings
+	AI                  AIConfig  `mapstructure:"ai"`
+	
 	// Performance settings
 	MaxConcurrency      int       `mapstructure:"max_concurrency"`
+	CommandTimeout      time.Duration `mapstructure:"command_timeout"` // bounds each go test, go build and go list run, 0 for none
 	EnableCaching       bool      `mapstructure:"enable_caching"`
 	CacheDir            string    `mapstructure:"cache_dir"`
 	
 	// Advanced settings
 	CustomPatterns      []string  `mapstructure:"custom_patterns"`
+	Owners              map[string][]string `mapstructure:"owners"`
+	Areas               map[string][]string `mapstructure:"areas"` // feature area to path patterns such as internal/billing/**
+	AreaThresholds      map[string]float64 `mapstructure:"area_thresholds"` // minimum coverage per area
 	GoVersions          []string  `mapstructure:"go_versions"`
 	BuildTags           []string  `mapstructure:"build_tags"`
 }
@@ -57,6 +116,92 @@ type TemplateConfig struct {
 	Overrides          map[string]string `mapstructure:"overrides"`
 }
 
+// ClassificationConfig holds the file name and path patterns assigning files to classes, and the
+// classes coverage is computed over
+type ClassificationConfig struct {
+	Test                []string      `mapstructure:"test"`
+	Helper              []string      `mapstructure:"helper"`
+	Generated           []string      `mapstructure:"generated"`
+	Vendored            []string      `mapstructure:"vendored"`
+	Counted             []string      `mapstructure:"counted"`
+}
+
+// FailUnderConfig holds the minimum percentages gcov analyze fails under, per metric; 0 disables a
+// metric, and overall falls back to the coverage threshold
+type FailUnderConfig struct {
+	Overall             float64       `mapstructure:"overall"`
+	Function            float64       `mapstructure:"function"`
+	Branch              float64       `mapstructure:"branch"`
+	Package             float64       `mapstructure:"package"` // worst package
+}
+
+// FunctionLimitsConfig holds the length and parameter count above which functions are reported as
+// oversized; they also set where these factors of the risk score saturate
+type FunctionLimitsConfig struct {
+	MaxLines            int           `mapstructure:"max_lines"` // lines of code, without blank and comment lines
+	MaxParams           int           `mapstructure:"max_params"`
+}
+
+// StorageConfig holds the settings of remote history and result stores. Credentials come from the
+// environment: AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY for S3, and tokens in the named variables
+type StorageConfig struct {
+	S3Region            string        `mapstructure:"s3_region"`
+	S3Endpoint          string        `mapstructure:"s3_endpoint"` // S3-compatible endpoint such as MinIO
+	GCSTokenEnv         string        `mapstructure:"gcs_token_env"`
+	HTTPTokenEnv        string        `mapstructure:"http_token_env"`
+	Timeout             time.Duration `mapstructure:"timeout"`
+}
+
+// ValidationConfig holds go test settings used when validating tests
+type ValidationConfig struct {
+	Race                bool          `mapstructure:"race"`
+	Count               int           `mapstructure:"count"`
+	Timeout             time.Duration `mapstructure:"timeout"`
+	Run                 string        `mapstructure:"run"`
+	TestFlags           []string      `mapstructure:"test_flags"`
+}
+
+// TestLintConfig holds the thresholds of the test smell checks and the severity gcov lint-tests
+// fails on
+type TestLintConfig struct {
+	MaxAssertions       int           `mapstructure:"max_assertions"`
+	MaxTestLines        int           `mapstructure:"max_test_lines"`
+	FailOn              string        `mapstructure:"fail_on"`
+}
+
+// ReportTemplatesConfig holds Go template files, executed against the analysis result, that replace
+// the built-in report layouts
+type ReportTemplatesConfig struct {
+	Console             string        `mapstructure:"console"`
+	HTML                string        `mapstructure:"html"`
+	Markdown            string        `mapstructure:"markdown"`
+}
+
+// HookConfig holds the settings of gcov hook run: the coverage the changed code needs, and the ref
+// pre-push hooks diff against
+type HookConfig struct {
+	Threshold           float64       `mapstructure:"threshold"`
+	DiffBase            string        `mapstructure:"diff_base"`
+}
+
+// NotificationConfig holds webhook settings for posting analysis summaries
+type NotificationConfig struct {
+	SlackWebhookURL     string        `mapstructure:"slack_webhook_url"`
+	TeamsWebhookURL     string        `mapstructure:"teams_webhook_url"`
+	MessageTemplate     string        `mapstructure:"message_template"`
+	MaxRegressions      int           `mapstructure:"max_regressions"`
+}
+
+// AIConfig holds the OpenAI-compatible endpoint used to write tests for complex functions
+type AIConfig struct {
+	Enabled             bool          `mapstructure:"enabled"`
+	Endpoint            string        `mapstructure:"endpoint"`
+	Model               string        `mapstructure:"model"`
+	APIKeyEnv           string        `mapstructure:"api_key_env"`
+	MinComplexity       int           `mapstructure:"min_complexity"`
+	Timeout             time.Duration `mapstructure:"timeout"`
+}
+
 // Load loads configuration from default locations
 func Load() (*Config, error) {
 	v := viper.New()
@@ -84,6 +229,7 @@ func Load() (*Config, error) {
 	if err := v.Unmarshal(&config); err != nil {
 		return nil, fmt.Errorf("error unmarshaling config: %w", err)
 	}
+	config.resolvePaths(v.ConfigFileUsed())
 	
 	return &config, nil
 }
@@ -108,6 +254,7 @@ func LoadFromFile(configPath string) (*Config, error) {
 	if err := v.Unmarshal(&config); err != nil {
 		return nil, fmt.Errorf("error unmarshaling config: %w", err)
 	}
+	config.resolvePaths(v.ConfigFileUsed())
 	
 	return &config, nil
 }
@@ -122,27 +269,106 @@ func (c *Config) Save(configPath string) error {
 	v.Set("coverage_threshold", c.CoverageThreshold)
 	v.Set("calculate_complexity", c.CalculateComplexity)
 	v.Set("min_complexity", c.MinComplexity)
+	v.Set("high_complexity_threshold", c.HighComplexityThreshold)
+	v.Set("function_limits.max_lines", c.FunctionLimits.MaxLines)
+	v.Set("function_limits.max_params", c.FunctionLimits.MaxParams)
+	v.Set("include_packages", c.IncludePackages)
+	v.Set("exclude_packages", c.ExcludePackages)
+	v.Set("stream_profile", c.StreamProfile)
+	v.Set("include_init", c.IncludeInit)
+	v.Set("include_cgo", c.IncludeCgo)
+	v.Set("classification.test", c.Classification.Test)
+	v.Set("classification.helper", c.Classification.Helper)
+	v.Set("classification.generated", c.Classification.Generated)
+	v.Set("classification.vendored", c.Classification.Vendored)
+	v.Set("classification.counted", c.Classification.Counted)
+	v.Set("fail_under.overall", c.FailUnder.Overall)
+	v.Set("fail_under.function", c.FailUnder.Function)
+	v.Set("fail_under.branch", c.FailUnder.Branch)
+	v.Set("fail_under.package", c.FailUnder.Package)
 	
 	v.Set("output_format", c.OutputFormat)
 	v.Set("output_dir", c.OutputDir)
 	v.Set("verbose", c.Verbose)
+	v.Set("color", c.Color)
+	v.Set("plain", c.Plain)
+	v.Set("precision", c.Precision)
+	v.Set("absolute", c.Absolute)
+	v.Set("ci", c.CI)
+	v.Set("messages_file", c.MessagesFile)
 	v.Set("profile_output", c.ProfileOutput)
+	v.Set("history_dir", c.HistoryDir)
+	v.Set("storage.s3_region", c.Storage.S3Region)
+	v.Set("storage.s3_endpoint", c.Storage.S3Endpoint)
+	v.Set("storage.gcs_token_env", c.Storage.GCSTokenEnv)
+	v.Set("storage.http_token_env", c.Storage.HTTPTokenEnv)
+	v.Set("storage.timeout", c.Storage.Timeout.String())
 	
 	v.Set("template_style", c.TemplateStyle)
 	v.Set("generate_mocks", c.GenerateMocks)
+	v.Set("mock_framework", c.MockFramework)
 	v.Set("table_driven", c.TableDriven)
+	v.Set("parallel_tests", c.ParallelTests)
 	v.Set("generate_benchmarks", c.GenerateBenchmarks)
+	v.Set("benchmark_baseline", c.BenchmarkBaseline)
 	v.Set("overwrite_tests", c.OverwriteTests)
+	v.Set("backup_overwritten", c.BackupOverwritten)
 	v.Set("max_test_cases", c.MaxTestCases)
 	v.Set("ignore_functions", c.IgnoreFunctions)
+	v.Set("exemptions_file", c.ExemptionsFile)
+	v.Set("strict", c.Strict)
+	v.Set("require_fresh_profile", c.RequireFreshProfile)
+	v.Set("include_unexported", c.IncludeUnexported)
+	v.Set("unexported_min_complexity", c.UnexportedMinComplexity)
+	v.Set("evaluate_expected", c.EvaluateExpected)
+	v.Set("stress_tests", c.StressTests)
+	v.Set("generate_examples", c.GenerateExamples)
+	v.Set("suite_style", c.SuiteStyle)
+	v.Set("main_harness", c.MainHarness)
+	v.Set("test_package_mode", c.TestPackageMode)
+	v.Set("test_name_pattern", c.TestNamePattern)
+	v.Set("seed", c.Seed)
 	
 	v.Set("templates", c.Templates)
 	
+	v.Set("report_templates.console", c.ReportTemplates.Console)
+	v.Set("report_templates.html", c.ReportTemplates.HTML)
+	v.Set("report_templates.markdown", c.ReportTemplates.Markdown)
+	
+	v.Set("validation.race", c.Validation.Race)
+	v.Set("validation.count", c.Validation.Count)
+	v.Set("validation.timeout", c.Validation.Timeout.String())
+	v.Set("validation.run", c.Validation.Run)
+	v.Set("validation.test_flags", c.Validation.TestFlags)
+	
+	v.Set("test_lint.max_assertions", c.TestLint.MaxAssertions)
+	v.Set("test_lint.max_test_lines", c.TestLint.MaxTestLines)
+	v.Set("test_lint.fail_on", c.TestLint.FailOn)
+	
+	v.Set("hook.threshold", c.Hook.Threshold)
+	v.Set("hook.diff_base", c.Hook.DiffBase)
+	
+	v.Set("notifications.slack_webhook_url", c.Notifications.SlackWebhookURL)
+	v.Set("notifications.teams_webhook_url", c.Notifications.TeamsWebhookURL)
+	v.Set("notifications.message_template", c.Notifications.MessageTemplate)
+	v.Set("notifications.max_regressions", c.Notifications.MaxRegressions)
+	
+	v.Set("ai.enabled", c.AI.Enabled)
+	v.Set("ai.endpoint", c.AI.Endpoint)
+	v.Set("ai.model", c.AI.Model)
+	v.Set("ai.api_key_env", c.AI.APIKeyEnv)
+	v.Set("ai.min_complexity", c.AI.MinComplexity)
+	v.Set("ai.timeout", c.AI.Timeout.String())
+	
 	v.Set("max_concurrency", c.MaxConcurrency)
+	v.Set("command_timeout", c.CommandTimeout.String())
 	v.Set("enable_caching", c.EnableCaching)
 	v.Set("cache_dir", c.CacheDir)
 	
 	v.Set("custom_patterns", c.CustomPatterns)
+	v.Set("owners", c.Owners)
+	v.Set("areas", c.Areas)
+	v.Set("area_thresholds", c.AreaThresholds)
 	v.Set("go_versions", c.GoVersions)
 	v.Set("build_tags", c.BuildTags)
 	
@@ -162,10 +388,41 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("coverage_threshold must be between 0 and 100, got %f", c.CoverageThreshold)
 	}
 	
+	// Validate per-metric failure thresholds
+	for metric, value := range map[string]float64{
+		"overall":  c.FailUnder.Overall,
+		"function": c.FailUnder.Function,
+		"branch":   c.FailUnder.Branch,
+		"package":  c.FailUnder.Package,
+	} {
+		if value < 0 || value > 100 {
+			return fmt.Errorf("fail_under.%s must be between 0 and 100, got %f", metric, value)
+		}
+	}
+	
+	// Validate area thresholds
+	for area, value := range c.AreaThresholds {
+		if _, ok := c.Areas[area]; !ok {
+			return fmt.Errorf("area_thresholds.%s names an area that is not configured in areas", area)
+		}
+		if value < 0 || value > 100 {
+			return fmt.Errorf("area_thresholds.%s must be between 0 and 100, got %f", area, value)
+		}
+	}
+	
 	// Validate min complexity
 	if c.MinComplexity < 1 {
 		return fmt.Errorf("min_complexity must be at least 1, got %d", c.MinComplexity)
 	}
+	if c.HighComplexityThreshold < 1 {
+		return fmt.Errorf("high_complexity_threshold must be at least 1, got %d", c.HighComplexityThreshold)
+	}
+	if c.FunctionLimits.MaxLines < 1 {
+		return fmt.Errorf("function_limits.max_lines must be at least 1, got %d", c.FunctionLimits.MaxLines)
+	}
+	if c.FunctionLimits.MaxParams < 1 {
+		return fmt.Errorf("function_limits.max_params must be at least 1, got %d", c.FunctionLimits.MaxParams)
+	}
 	
 	// Validate max test cases
 	if c.MaxTestCases < 1 {
@@ -177,15 +434,89 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("max_concurrency must be at least 1, got %d", c.MaxConcurrency)
 	}
 	
+	// Validate color mode
+	switch c.Color {
+	case "", "auto", "always", "never":
+	default:
+		return fmt.Errorf("color must be auto, always or never, got %s", c.Color)
+	}
+	
+	// Validate output precision
+	if c.Precision < 0 || c.Precision > 4 {
+		return fmt.Errorf("precision must be between 0 and 4, got %d", c.Precision)
+	}
+	
+	// Validate CI server
+	switch c.CI {
+	case "", "teamcity", "bamboo":
+	default:
+		return fmt.Errorf("ci must be teamcity or bamboo, got %s", c.CI)
+	}
+	
+	// Validate command timeout
+	if c.CommandTimeout < 0 {
+		return fmt.Errorf("command_timeout must not be negative, got %s", c.CommandTimeout)
+	}
+	
+	// Validate storage settings
+	if c.Storage.Timeout < 0 {
+		return fmt.Errorf("storage.timeout must not be negative, got %s", c.Storage.Timeout)
+	}
+	
+	// Validate file classes
+	validClasses := map[string]bool{
+		"production": true,
+		"test":       true,
+		"helper":     true,
+		"generated":  true,
+		"vendored":   true,
+	}
+	for _, class := range c.Classification.Counted {
+		if !validClasses[class] {
+			return fmt.Errorf("invalid classification.counted class: %s (valid: production, test, helper, generated, vendored)", class)
+		}
+	}
+	
+	// Validate go test settings
+	if c.Validation.Count < 0 {
+		return fmt.Errorf("validation.count must not be negative, got %d", c.Validation.Count)
+	}
+	
+	// Validate test smell settings
+	if c.TestLint.MaxAssertions < 0 {
+		return fmt.Errorf("test_lint.max_assertions must not be negative, got %d", c.TestLint.MaxAssertions)
+	}
+	if c.TestLint.MaxTestLines < 0 {
+		return fmt.Errorf("test_lint.max_test_lines must not be negative, got %d", c.TestLint.MaxTestLines)
+	}
+	switch c.TestLint.FailOn {
+	case "", "error", "warning", "info", "none":
+	default:
+		return fmt.Errorf("test_lint.fail_on must be error, warning, info or none, got %s", c.TestLint.FailOn)
+	}
+	
+	// Validate hook settings
+	if c.Hook.Threshold < 0 || c.Hook.Threshold > 100 {
+		return fmt.Errorf("hook.threshold must be between 0 and 100, got %f", c.Hook.Threshold)
+	}
+	
+	// Validate AI settings
+	if c.AI.Enabled && c.AI.Endpoint == "" {
+		return fmt.Errorf("ai.endpoint is required when ai.enabled is set")
+	}
+	
 	// Validate output format
 	validFormats := map[string]bool{
 		"console": true,
 		"json":    true,
 		"html":    true,
 		"xml":     true,
+		"sarif":   true,
+		"csv":     true,
+		"tsv":     true,
 	}
 	if !validFormats[c.OutputFormat] {
-		return fmt.Errorf("invalid output_format: %s (valid: console, json, html, xml)", c.OutputFormat)
+		return fmt.Errorf("invalid output_format: %s (valid: console, json, html, xml, sarif, csv, tsv)", c.OutputFormat)
 	}
 	
 	// Validate template style
@@ -194,9 +525,44 @@ func (c *Config) Validate() error {
 		"testify":  true,
 		"table":    true,
 		"ginkgo":   true,
+		"snapshot": true,
+		"gotest-tools": true,
+		"go-cmp":   true,
 	}
 	if !validStyles[c.TemplateStyle] {
-		return fmt.Errorf("invalid template_style: %s (valid: standard, testify, table, ginkgo)", c.TemplateStyle)
+		return fmt.Errorf("invalid template_style: %s (valid: standard, testify, gotest-tools, go-cmp, table, ginkgo, snapshot)", c.TemplateStyle)
+	}
+	
+	// Validate mock framework
+	validMockFrameworks := map[string]bool{
+		"":        true,
+		"testify": true,
+		"fake":    true,
+	}
+	if !validMockFrameworks[c.MockFramework] {
+		return fmt.Errorf("invalid mock_framework: %s (valid: testify, fake)", c.MockFramework)
+	}
+	
+	// Validate suite style
+	validSuiteStyles := map[string]bool{
+		"":              true,
+		"none":          true,
+		"testmain":      true,
+		"testify-suite": true,
+	}
+	if !validSuiteStyles[c.SuiteStyle] {
+		return fmt.Errorf("invalid suite_style: %s (valid: none, testmain, testify-suite)", c.SuiteStyle)
+	}
+	
+	// Validate test package mode
+	validTestPackageModes := map[string]bool{
+		"":         true,
+		"same":     true,
+		"external": true,
+		"auto":     true,
+	}
+	if !validTestPackageModes[c.TestPackageMode] {
+		return fmt.Errorf("invalid test_package_mode: %s (valid: same, external, auto)", c.TestPackageMode)
 	}
 	
 	// Validate custom templates directory if specified
@@ -206,6 +572,13 @@ func (c *Config) Validate() error {
 		}
 	}
 	
+	// Validate report templates
+	for format, path := range c.ReportTemplates.Paths() {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			return fmt.Errorf("report_templates.%s does not exist: %s", format, path)
+		}
+	}
+	
 	return nil
 }
 
@@ -227,14 +600,42 @@ func (c *Config) GetCacheDir() (string, error) {
 	return cacheDir, nil
 }
 
-// GetTemplatesDir returns the templates directory
+// GetTemplatesDir returns the custom templates directory, or "" to use the templates embedded in the binary
 func (c *Config) GetTemplatesDir() string {
-	if c.Templates.CustomTemplatesDir != "" {
-		return c.Templates.CustomTemplatesDir
+	return c.Templates.CustomTemplatesDir
+}
+
+// Paths returns the configured report templates by output format
+func (r ReportTemplatesConfig) Paths() map[string]string {
+	paths := make(map[string]string)
+	if r.Console != "" {
+		paths["console"] = r.Console
+	}
+	if r.HTML != "" {
+		paths["html"] = r.HTML
+	}
+	if r.Markdown != "" {
+		paths["markdown"] = r.Markdown
+	}
+	return paths
+}
+
+// resolvePaths makes relative paths in the configuration relative to the config file rather than the working directory
+func (c *Config) resolvePaths(configFile string) {
+	c.File = configFile
+	if configFile == "" {
+		return
 	}
 	
-	// Return default templates directory (embedded or relative to binary)
-	return "templates"
+	dir := filepath.Dir(configFile)
+	if c.Templates.CustomTemplatesDir != "" && !filepath.IsAbs(c.Templates.CustomTemplatesDir) {
+		c.Templates.CustomTemplatesDir = filepath.Join(dir, c.Templates.CustomTemplatesDir)
+	}
+	for _, path := range []*string{&c.ReportTemplates.Console, &c.ReportTemplates.HTML, &c.ReportTemplates.Markdown} {
+		if *path != "" && !filepath.IsAbs(*path) {
+			*path = filepath.Join(dir, *path)
+		}
+	}
 }
 
 // IsIgnoredFunction checks if a function should be ignored based on patterns
@@ -294,21 +695,67 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("coverage_threshold", 80.0)
 	v.SetDefault("calculate_complexity", true)
 	v.SetDefault("min_complexity", 1)
+	v.SetDefault("high_complexity_threshold", 10)
+	v.SetDefault("function_limits.max_lines", 80)
+	v.SetDefault("function_limits.max_params", 6)
+	v.SetDefault("include_packages", []string{})
+	v.SetDefault("exclude_packages", []string{})
+	v.SetDefault("stream_profile", false)
+	v.SetDefault("include_init", false)
+	v.SetDefault("include_cgo", false)
+	v.SetDefault("classification.test", []string{})
+	v.SetDefault("classification.helper", []string{"export_test.go", "*_helpers.go", "*_helper.go", "*_helpers_test.go"})
+	v.SetDefault("classification.generated", []string{"*.pb.go", "*_gen.go", "zz_generated*.go"})
+	v.SetDefault("classification.vendored", []string{"vendor/...", "third_party/..."})
+	v.SetDefault("classification.counted", []string{"production"})
+	v.SetDefault("fail_under.overall", 0.0)
+	v.SetDefault("fail_under.function", 0.0)
+	v.SetDefault("fail_under.branch", 0.0)
+	v.SetDefault("fail_under.package", 0.0)
 	
 	// Output defaults
 	v.SetDefault("output_format", "console")
 	v.SetDefault("output_dir", ".")
 	v.SetDefault("verbose", false)
+	v.SetDefault("color", "auto")
+	v.SetDefault("plain", false)
+	v.SetDefault("precision", 1)
+	v.SetDefault("absolute", false)
+	v.SetDefault("ci", "")
+	v.SetDefault("messages_file", "")
 	v.SetDefault("profile_output", "coverage.out")
+	v.SetDefault("history_dir", ".gcov/history")
+	v.SetDefault("storage.s3_region", "")
+	v.SetDefault("storage.s3_endpoint", "")
+	v.SetDefault("storage.gcs_token_env", "GOOGLE_OAUTH_ACCESS_TOKEN")
+	v.SetDefault("storage.http_token_env", "GCOV_STORAGE_TOKEN")
+	v.SetDefault("storage.timeout", "1m")
 	
 	// Generation defaults
 	v.SetDefault("template_style", "standard")
 	v.SetDefault("generate_mocks", true)
+	v.SetDefault("mock_framework", "testify")
 	v.SetDefault("table_driven", true)
+	v.SetDefault("parallel_tests", false)
 	v.SetDefault("generate_benchmarks", false)
+	v.SetDefault("benchmark_baseline", false)
 	v.SetDefault("overwrite_tests", false)
+	v.SetDefault("backup_overwritten", false)
 	v.SetDefault("max_test_cases", 10)
 	v.SetDefault("ignore_functions", []string{})
+	v.SetDefault("exemptions_file", "gcov-exemptions.yaml")
+	v.SetDefault("strict", false)
+	v.SetDefault("require_fresh_profile", false)
+	v.SetDefault("include_unexported", false)
+	v.SetDefault("unexported_min_complexity", 3)
+	v.SetDefault("evaluate_expected", false)
+	v.SetDefault("stress_tests", false)
+	v.SetDefault("generate_examples", false)
+	v.SetDefault("suite_style", "none")
+	v.SetDefault("main_harness", false)
+	v.SetDefault("test_package_mode", "same")
+	v.SetDefault("test_name_pattern", "Test{{.Function}}{{.Case}}")
+	v.SetDefault("seed", 1)
 	
 	// Template defaults
 	v.SetDefault("templates.custom_templates_dir", "")
@@ -319,13 +766,52 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("templates.mock_template", "")
 	v.SetDefault("templates.overrides", map[string]string{})
 	
+	// Validation defaults
+	v.SetDefault("validation.race", false)
+	v.SetDefault("validation.count", 1)
+	v.SetDefault("validation.timeout", "10m")
+	v.SetDefault("validation.run", "")
+	v.SetDefault("validation.test_flags", []string{})
+	
+	// Test smell defaults
+	v.SetDefault("test_lint.max_assertions", 10)
+	v.SetDefault("test_lint.max_test_lines", 100)
+	v.SetDefault("test_lint.fail_on", "error")
+	
+	// Report template defaults
+	v.SetDefault("report_templates.console", "")
+	v.SetDefault("report_templates.html", "")
+	v.SetDefault("report_templates.markdown", "")
+	
+	// Hook defaults
+	v.SetDefault("hook.threshold", 80.0)
+	v.SetDefault("hook.diff_base", "@{upstream}")
+	
+	// Notification defaults
+	v.SetDefault("notifications.slack_webhook_url", "")
+	v.SetDefault("notifications.teams_webhook_url", "")
+	v.SetDefault("notifications.message_template", "")
+	v.SetDefault("notifications.max_regressions", 5)
+	
+	// AI defaults
+	v.SetDefault("ai.enabled", false)
+	v.SetDefault("ai.endpoint", "https://api.openai.com/v1")
+	v.SetDefault("ai.model", "gpt-4o-mini")
+	v.SetDefault("ai.api_key_env", "OPENAI_API_KEY")
+	v.SetDefault("ai.min_complexity", 10)
+	v.SetDefault("ai.timeout", "2m")
+	
 	// Performance defaults
 	v.SetDefault("max_concurrency", 4)
+	v.SetDefault("command_timeout", "30m")
 	v.SetDefault("enable_caching", true)
 	v.SetDefault("cache_dir", "")
 	
 	// Advanced defaults
 	v.SetDefault("custom_patterns", []string{})
+	v.SetDefault("owners", map[string][]string{})
+	v.SetDefault("areas", map[string][]string{})
+	v.SetDefault("area_thresholds", map[string]float64{})
 	v.SetDefault("go_versions", []string{})
 	v.SetDefault("build_tags", []string{})
 }
\ No newline at end of file
@@ -0,0 +1,425 @@
+// Package daemon keeps a gcov process serving the analyses of a project over a unix socket, with
+// the files it parsed and its latest results in memory, so repeated runs on big repositories take
+// well under a second. The CLI delegates its analyses to the daemon when it finds its socket
+package daemon
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/beck/go-coverage-analyzer/internal/analyzer"
+	"github.com/beck/go-coverage-analyzer/internal/i18n"
+	"github.com/beck/go-coverage-analyzer/pkg/coverage"
+	"github.com/beck/go-coverage-analyzer/pkg/models"
+)
+
+// Requests the daemon answers
+const (
+	methodAnalyze = "analyze"
+	methodStatus  = "status"
+	methodStop    = "stop"
+)
+
+// LogFile is where a daemon started in the background writes its output, relative to the project
+const LogFile = ".gcov/daemon.log"
+
+// dialTimeout bounds how long the CLI waits to find out whether a daemon is running
+const dialTimeout = 200 * time.Millisecond
+
+// maxMessageSize bounds a message, large enough for the result of a big repository
+const maxMessageSize = 512 << 20
+
+// Status describes a running daemon
+type Status struct {
+	PID         int       `json:"pid"`
+	Version     string    `json:"version"`
+	ProjectPath string    `json:"project_path"`
+	Socket      string    `json:"socket"`
+	Started     time.Time `json:"started"`
+	Analyses    int       `json:"analyses"`     // analyses run, cached results not included
+	CacheHits   int       `json:"cache_hits"`   // analyses answered from a cached result
+	CachedFiles int       `json:"cached_files"` // parsed files kept in memory
+}
+
+type request struct {
+	Method  string            `json:"method"`
+	Version string            `json:"version"` // gcov version of the client, which must be the daemon's to analyze
+	Options *analyzer.Options `json:"options,omitempty"`
+}
+
+type response struct {
+	Version string                 `json:"version"`
+	Result  *models.AnalysisResult `json:"result,omitempty"`
+	Status  *Status                `json:"status,omitempty"`
+	Error   string                 `json:"error,omitempty"`
+}
+
+// cachedResult is the result of an analysis along with the fingerprint of the files it read
+type cachedResult struct {
+	fingerprint string
+	result      *models.AnalysisResult
+}
+
+// server answers the requests of a daemon
+type server struct {
+	status   Status
+	verbose  bool
+	parses   *coverage.ParseCache
+	listener net.Listener
+
+	mu      sync.Mutex               // analyses run one at a time, sharing the caches
+	results map[string]*cachedResult // by the options of the analysis
+}
+
+// SocketPath returns the socket of the daemon serving a project, in a runtime directory only the user
+// can enter, so no one else can answer in its place. Socket paths are limited to about a hundred bytes,
+// so the project is named by a hash of its path
+func SocketPath(projectPath string) (string, error) {
+	absPath, err := filepath.Abs(projectPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to get absolute path: %w", err)
+	}
+	dir, err := runtimeDir()
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(absPath))
+	return filepath.Join(dir, "gcov-"+hex.EncodeToString(sum[:6])+".sock"), nil
+}
+
+// runtimeDir returns the directory of the sockets of the user's daemons: $XDG_RUNTIME_DIR, or else a
+// directory of the user cache directory that Serve creates private
+func runtimeDir() (string, error) {
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); filepath.IsAbs(dir) {
+		return dir, nil
+	}
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find a directory for the daemon socket: %w", err)
+	}
+	return filepath.Join(cacheDir, "gcov", "run"), nil
+}
+
+// Serve runs the daemon of a project in the foreground until ctx is cancelled or it is stopped
+func Serve(ctx context.Context, projectPath, version string, verbose bool) error {
+	absPath, err := filepath.Abs(projectPath)
+	if err != nil {
+		return fmt.Errorf("failed to get absolute path: %w", err)
+	}
+	socket, err := SocketPath(absPath)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(socket), 0700); err != nil {
+		return fmt.Errorf("failed to create the socket directory: %w", err)
+	}
+
+	// A socket nobody answers on is left over from a daemon that did not exit cleanly
+	if _, err := os.Stat(socket); err == nil {
+		if _, err := GetStatus(absPath); err == nil {
+			return fmt.Errorf("a gcov daemon already serves %s", absPath)
+		}
+		if err := os.Remove(socket); err != nil {
+			return fmt.Errorf("failed to remove stale socket: %w", err)
+		}
+	}
+
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", socket, err)
+	}
+	defer os.Remove(socket)
+
+	s := &server{
+		status: Status{
+			PID:         os.Getpid(),
+			Version:     version,
+			ProjectPath: absPath,
+			Socket:      socket,
+			Started:     time.Now(),
+		},
+		verbose:  verbose,
+		parses:   coverage.NewParseCache(),
+		listener: listener,
+		results:  make(map[string]*cachedResult),
+	}
+
+	stop := context.AfterFunc(ctx, func() { listener.Close() })
+	defer stop()
+
+	if verbose {
+		i18n.Printf("🔌 gcov daemon serving %s on %s\n", absPath, socket)
+	}
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return fmt.Errorf("failed to accept connection: %w", err)
+		}
+		go s.serveConn(ctx, conn)
+	}
+}
+
+// serveConn answers the single request of a connection
+func (s *server) serveConn(ctx context.Context, conn net.Conn) {
+	defer conn.Close()
+
+	// A client that goes away cancels its analysis
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var req request
+	if err := json.NewDecoder(conn).Decode(&req); err != nil {
+		json.NewEncoder(conn).Encode(&response{Version: s.status.Version, Error: fmt.Sprintf("invalid request: %v", err)})
+		return
+	}
+	go func() {
+		// The client sends nothing after its request, so a read returns only once it disconnects
+		var buf [1]byte
+		conn.Read(buf[:])
+		cancel()
+	}()
+
+	resp := &response{Version: s.status.Version}
+	switch {
+	case req.Method == methodStatus:
+		s.mu.Lock()
+		status := s.status
+		s.mu.Unlock()
+		status.CachedFiles = s.parses.Len()
+		resp.Status = &status
+	case req.Method == methodStop:
+		defer s.listener.Close()
+	case req.Method == methodAnalyze && req.Version != s.status.Version:
+		// Answer with the daemon's version only, so the client analyzes on its own
+	case req.Method == methodAnalyze && req.Options != nil:
+		result, err := s.analyze(ctx, req.Options)
+		if err != nil {
+			resp.Error = err.Error()
+		}
+		resp.Result = result
+	default:
+		resp.Error = fmt.Sprintf("unknown request %q", req.Method)
+	}
+
+	if err := json.NewEncoder(conn).Encode(resp); err != nil && s.verbose {
+		i18n.Printf("⚠️ Failed to answer %s request: %v\n", req.Method, err)
+	}
+}
+
+// analyze runs an analysis with the daemon's caches, answering from the cached result of the same
+// analysis when none of the files it read changed since
+func (s *server) analyze(ctx context.Context, opts *analyzer.Options) (*models.AnalysisResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// What the analysis prints goes to the daemon's log, so the daemon decides how much
+	opts.ProjectPath = s.status.ProjectPath
+	opts.ParseCache = s.parses
+	opts.Progress = nil
+	opts.Verbose = s.verbose
+
+	key, err := json.Marshal(opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode options: %w", err)
+	}
+
+	// Results of analyses running tests depend on more than the files, so they are never reused
+	// Files changing while the analysis runs show in the fingerprint taken before it
+	reusable := !opts.GenerateProfile && !opts.AttributeTests
+	var files string
+	if reusable {
+		files = fingerprint(opts)
+		if cached := s.results[string(key)]; cached != nil && cached.fingerprint == files {
+			s.status.CacheHits++
+			return cached.result, nil
+		}
+	}
+
+	startTime := time.Now()
+	result, err := analyzer.Analyze(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	s.status.Analyses++
+	if s.verbose {
+		i18n.Printf("📊 Analyzed %s in %v\n", s.status.ProjectPath, time.Since(startTime))
+	}
+
+	if reusable {
+		s.results[string(key)] = &cachedResult{fingerprint: files, result: result}
+	}
+	return result, nil
+}
+
+// sourceExtensions are the files besides Go ones an analysis reads: assembly and cgo sources mark the
+// functions they implement untestable
+var sourceExtensions = map[string]bool{
+	".go": true, ".s": true, ".S": true, ".c": true, ".h": true, ".cc": true, ".cpp": true, ".cxx": true,
+	".hh": true, ".hpp": true, ".hxx": true, ".m": true, ".syso": true,
+}
+
+// moduleFiles are the files that decide which modules and packages an analysis maps the profile to
+var moduleFiles = map[string]bool{
+	"go.mod": true, "go.sum": true, "go.work": true, "go.work.sum": true,
+}
+
+// fingerprint hashes the size and modification time of every file an analysis reads: the sources and
+// module files of the project, a go.work above it, the config and exemptions files of the options, and
+// the profile it reads or the coverage.out it finds, which change whenever its result may
+func fingerprint(opts *analyzer.Options) string {
+	excluded := make(map[string]bool)
+	for _, dir := range opts.ExcludeDirs {
+		excluded[dir] = true
+	}
+
+	h := sha256.New()
+	addFile(h, filepath.Join(opts.ProjectPath, "coverage.out"))
+	for _, path := range opts.InputFiles {
+		addFile(h, path)
+	}
+
+	// A workspace the project is part of may be declared in any directory above it
+	if dir, err := filepath.Abs(opts.ProjectPath); err == nil {
+		for parent := filepath.Dir(dir); parent != dir; dir, parent = parent, filepath.Dir(parent) {
+			addFile(h, filepath.Join(parent, "go.work"))
+		}
+	}
+	if opts.ProfilePath != "" {
+		profilePath := opts.ProfilePath
+		if !filepath.IsAbs(profilePath) {
+			profilePath = filepath.Join(opts.ProjectPath, profilePath)
+		}
+		addFile(h, profilePath)
+	}
+	filepath.Walk(opts.ProjectPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() {
+			if path != opts.ProjectPath && (excluded[info.Name()] || strings.HasPrefix(info.Name(), ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if sourceExtensions[filepath.Ext(path)] || moduleFiles[info.Name()] {
+			fmt.Fprintf(h, "%s %d %d\n", path, info.Size(), info.ModTime().UnixNano())
+		}
+		return nil
+	})
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// addFile adds the size and modification time of a file to a fingerprint
+func addFile(h hash.Hash, path string) {
+	if path == "" {
+		return
+	}
+	if info, err := os.Stat(path); err == nil {
+		fmt.Fprintf(h, "%s %d %d\n", path, info.Size(), info.ModTime().UnixNano())
+	}
+}
+
+// Analyze runs an analysis in the daemon serving the project, and reports whether there is one of the
+// same version that did; without one the caller analyzes on its own
+func Analyze(ctx context.Context, opts *analyzer.Options, version string) (*models.AnalysisResult, bool, error) {
+	resp, ok, err := call(ctx, opts.ProjectPath, &request{Method: methodAnalyze, Version: version, Options: opts})
+	if !ok {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, true, err
+	}
+	if resp.Version != version {
+		return nil, false, nil
+	}
+	if resp.Error != "" {
+		return nil, true, errors.New(resp.Error)
+	}
+
+	// The result names the project as the caller did, as an analysis of its own would
+	resp.Result.ProjectPath = opts.ProjectPath
+	resp.Result.LinkFunctions()
+	return resp.Result, true, nil
+}
+
+// GetStatus returns the status of the daemon serving a project, or an error when none does
+func GetStatus(projectPath string) (*Status, error) {
+	resp, ok, err := call(context.Background(), projectPath, &request{Method: methodStatus})
+	if !ok {
+		return nil, fmt.Errorf("no gcov daemon serves %s", projectPath)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if resp.Status == nil {
+		return nil, fmt.Errorf("the gcov daemon serving %s runs version %s", projectPath, resp.Version)
+	}
+	return resp.Status, nil
+}
+
+// Stop stops the daemon serving a project
+func Stop(projectPath string) error {
+	_, ok, err := call(context.Background(), projectPath, &request{Method: methodStop})
+	if !ok {
+		return fmt.Errorf("no gcov daemon serves %s", projectPath)
+	}
+	return err
+}
+
+// call sends a request to the daemon serving a project and returns its response, reporting false
+// when no daemon listens. Cancelling ctx closes the connection, which cancels the request
+func call(ctx context.Context, projectPath string, req *request) (*response, bool, error) {
+	socket, err := SocketPath(projectPath)
+	if err != nil {
+		return nil, false, err
+	}
+	// A socket another user created could answer with forged results, so it is no daemon of ours
+	info, err := os.Lstat(socket)
+	if err != nil || !ownSocket(info) {
+		return nil, false, nil
+	}
+	conn, err := net.DialTimeout("unix", socket, dialTimeout)
+	if err != nil {
+		return nil, false, nil
+	}
+	defer conn.Close()
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	// Options travel with an absolute project path, as the daemon runs elsewhere
+	if req.Options != nil {
+		opts := *req.Options
+		if opts.ProjectPath, err = filepath.Abs(opts.ProjectPath); err != nil {
+			return nil, true, fmt.Errorf("failed to get absolute path: %w", err)
+		}
+		req.Options = &opts
+	}
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return nil, true, fmt.Errorf("failed to send request to the gcov daemon: %w", err)
+	}
+
+	var resp response
+	if err := json.NewDecoder(io.LimitReader(conn, maxMessageSize)).Decode(&resp); err != nil {
+		if ctx.Err() != nil {
+			return nil, true, ctx.Err()
+		}
+		return nil, true, fmt.Errorf("failed to read the response of the gcov daemon: %w", err)
+	}
+	return &resp, true, nil
+}
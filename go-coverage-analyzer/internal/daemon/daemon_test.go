@@ -0,0 +1,151 @@
+package daemon
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/beck/go-coverage-analyzer/internal/analyzer"
+	"github.com/beck/go-coverage-analyzer/pkg/models"
+)
+
+// startDaemon serves a project for the length of a test, returning once the daemon answers
+func startDaemon(t *testing.T, projectPath, version string) {
+	t.Helper()
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- Serve(ctx, projectPath, version, false) }()
+	t.Cleanup(func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("Serve() error = %v", err)
+		}
+	})
+
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		if _, err := GetStatus(projectPath); err == nil {
+			return
+		}
+	}
+	t.Fatal("the daemon did not start")
+}
+
+func TestAnalyzeLinksFunctions(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"go.mod":       "module example.com/calc\n\ngo 1.21\n",
+		"calc.go":      "package calc\n\nfunc Add(a, b int) int {\n\treturn a + b\n}\n\nfunc Sub(a, b int) int {\n\treturn a - b\n}\n",
+		"coverage.out": "mode: set\nexample.com/calc/calc.go:3.24,5.2 1 1\nexample.com/calc/calc.go:7.24,9.2 1 0\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(root, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	startDaemon(t, root, "test")
+
+	result, delegated, err := Analyze(context.Background(), &analyzer.Options{ProjectPath: root}, "test")
+	if err != nil || !delegated {
+		t.Fatalf("Analyze() = %v, %v, want a result of the daemon", delegated, err)
+	}
+	if len(result.UncoveredFunctions) != 1 || result.UncoveredFunctions[0].Name != "Sub" {
+		t.Fatalf("UncoveredFunctions = %v, want Sub", result.UncoveredFunctions)
+	}
+
+	// What is recorded on the functions after the analysis, as blame does, shows in every list
+	var sub *models.Function
+	for _, pkg := range result.PackageCoverage {
+		for _, file := range pkg.Files {
+			for _, function := range file.Functions {
+				if function.Name == "Sub" {
+					sub = function
+				}
+			}
+		}
+	}
+	if sub != result.UncoveredFunctions[0] {
+		t.Fatal("UncoveredFunctions holds a copy of the function in PackageCoverage")
+	}
+}
+
+// useCacheDir leaves sockets to the user cache directory, in a home directory of the test
+func useCacheDir(t *testing.T) {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("XDG_RUNTIME_DIR", "")
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CACHE_HOME", filepath.Join(home, ".cache"))
+	t.Setenv("LocalAppData", filepath.Join(home, "AppData", "Local"))
+}
+
+func TestSocketPath(t *testing.T) {
+	project := t.TempDir()
+
+	runtime := t.TempDir()
+	t.Setenv("XDG_RUNTIME_DIR", runtime)
+	socket, err := SocketPath(project)
+	if err != nil || filepath.Dir(socket) != runtime {
+		t.Errorf("SocketPath() = %q, %v, want a socket in $XDG_RUNTIME_DIR", socket, err)
+	}
+
+	useCacheDir(t)
+	cache, err := os.UserCacheDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	socket, err = SocketPath(project)
+	if err != nil || filepath.Dir(socket) != filepath.Join(cache, "gcov", "run") {
+		t.Errorf("SocketPath() = %q, %v, want a socket in the user cache directory", socket, err)
+	}
+}
+
+func TestServeCreatesPrivateSocketDirectory(t *testing.T) {
+	useCacheDir(t)
+	cache, err := os.UserCacheDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	project := t.TempDir()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- Serve(ctx, project, "test", false) }()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	dir := filepath.Join(cache, "gcov", "run")
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		if _, err := GetStatus(project); err == nil {
+			break
+		}
+	}
+	info, err := os.Stat(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0700 {
+		t.Errorf("socket directory permissions = %v, want 0700", perm)
+	}
+}
+
+func TestCallIgnoresFilesThatAreNoSocket(t *testing.T) {
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+	project := t.TempDir()
+
+	// Something other than the daemon at the socket path is never talked to
+	socket, err := SocketPath(project)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(socket, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := GetStatus(project); err == nil {
+		t.Error("GetStatus() found a daemon behind a regular file")
+	}
+}
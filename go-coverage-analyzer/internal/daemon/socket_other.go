@@ -0,0 +1,11 @@
+//go:build !unix
+
+package daemon
+
+import "os"
+
+// ownSocket reports whether a file may be a socket of the user's daemon; without uids the privacy of
+// the directory holding it, under the user profile, is what keeps other users out
+func ownSocket(info os.FileInfo) bool {
+	return !info.IsDir()
+}
@@ -0,0 +1,14 @@
+//go:build unix
+
+package daemon
+
+import (
+	"os"
+	"syscall"
+)
+
+// ownSocket reports whether a file is a socket the user gcov runs as created
+func ownSocket(info os.FileInfo) bool {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	return ok && info.Mode()&os.ModeSocket != 0 && int(stat.Uid) == os.Getuid()
+}
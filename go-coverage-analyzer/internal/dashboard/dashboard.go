@@ -0,0 +1,203 @@
+// Package dashboard serves the HTML report of a project from memory, analyzing the project again
+// whenever its coverage profile changes and reloading the pages open on the report, so a team can keep
+// a coverage dashboard open while they write tests
+package dashboard
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/beck/go-coverage-analyzer/internal/i18n"
+	"github.com/beck/go-coverage-analyzer/internal/reporter"
+	"github.com/beck/go-coverage-analyzer/pkg/models"
+)
+
+// DefaultInterval is how often the watched file is checked for changes
+const DefaultInterval = time.Second
+
+// shutdownTimeout bounds how long open requests may take to finish once serving stops
+const shutdownTimeout = 5 * time.Second
+
+// reloadScript asks the server for the version of the report every two seconds, reloading the page
+// when a new analysis replaced the one it shows
+const reloadScript = `<script>
+(function () {
+    var version = "%d";
+    setInterval(function () {
+        fetch("/version", {cache: "no-store"})
+            .then(function (response) { return response.text(); })
+            .then(function (current) { if (current !== version) { location.reload(); } })
+            .catch(function () {});
+    }, 2000);
+})();
+</script>
+`
+
+// Options configures the dashboard
+type Options struct {
+	Addr     string                                                    // address to listen on, such as :8080
+	Watch    string                                                    // file whose changes trigger a new analysis, the profile or saved result
+	Interval time.Duration                                             // how often Watch is checked, 0 for DefaultInterval
+	Analyze  func(ctx context.Context) (*models.AnalysisResult, error) // analyzes the project
+	Report   *reporter.Options                                         // how the HTML report is rendered
+	Open     bool                                                      // open the dashboard in the browser once it is served
+	Out      io.Writer                                                 // where progress is written, os.Stdout when nil
+}
+
+// Server holds the latest HTML report of the project
+type Server struct {
+	opts    *Options
+	mu      sync.RWMutex
+	page    []byte
+	version int
+	watched time.Time // modification time of the watched file when it was last analyzed
+}
+
+// Serve analyzes the project, then serves its report on opts.Addr until ctx is cancelled, analyzing it
+// again each time the watched file changes. A failed new analysis keeps the previous report
+func Serve(ctx context.Context, opts *Options) error {
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultInterval
+	}
+	if opts.Out == nil {
+		opts.Out = os.Stdout
+	}
+
+	s := &Server{opts: opts}
+	if err := s.refresh(ctx); err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("tcp", opts.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", opts.Addr, err)
+	}
+	url := "http://" + displayAddr(listener.Addr())
+
+	server := &http.Server{Handler: s.handler(), ReadHeaderTimeout: 10 * time.Second}
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.Serve(listener)
+	}()
+
+	i18n.Fprintf(opts.Out, "🌐 Serving the coverage report on %s, reloading when %s changes (Ctrl+C to stop)\n", url, opts.Watch)
+	if opts.Open {
+		if err := reporter.OpenInBrowser(url); err != nil {
+			i18n.Fprintf(os.Stderr, "⚠️ Could not open the browser: %v\n", err)
+		}
+	}
+
+	ticker := time.NewTicker(opts.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+			defer cancel()
+			return server.Shutdown(shutdownCtx)
+		case err := <-serveErr:
+			return err
+		case <-ticker.C:
+			if modTime(opts.Watch).Equal(s.watched) {
+				continue
+			}
+			if err := s.refresh(ctx); err != nil {
+				if ctx.Err() != nil {
+					continue
+				}
+				i18n.Fprintf(os.Stderr, "⚠️ Analysis failed, still serving the previous report: %v\n", err)
+				continue
+			}
+			i18n.Fprintf(opts.Out, "🔁 Report updated after %s changed\n", opts.Watch)
+		}
+	}
+}
+
+// refresh analyzes the project and renders its report, replacing the one served
+func (s *Server) refresh(ctx context.Context) error {
+	// Recorded first, so changes made during the analysis trigger another one
+	s.watched = modTime(s.opts.Watch)
+
+	result, err := s.opts.Analyze(ctx)
+	if err != nil {
+		return err
+	}
+	page, err := reporter.RenderHTML(result, s.opts.Report)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.version++
+	s.page = withReloadScript([]byte(page), s.version)
+	return nil
+}
+
+// handler serves the report at / and its version at /version, which open pages poll
+func (s *Server) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		s.mu.RLock()
+		page := s.page
+		s.mu.RUnlock()
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Header().Set("Cache-Control", "no-store")
+		_, _ = w.Write(page)
+	})
+	mux.HandleFunc("/version", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.RLock()
+		version := s.version
+		s.mu.RUnlock()
+
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Header().Set("Cache-Control", "no-store")
+		_, _ = io.WriteString(w, strconv.Itoa(version))
+	})
+	return mux
+}
+
+// withReloadScript adds the live reload script to a page, before its closing body tag when it has one
+func withReloadScript(page []byte, version int) []byte {
+	script := []byte(fmt.Sprintf(reloadScript, version))
+	i := bytes.LastIndex(page, []byte("</body>"))
+	if i < 0 {
+		return append(page, script...)
+	}
+
+	withScript := make([]byte, 0, len(page)+len(script))
+	withScript = append(withScript, page[:i]...)
+	withScript = append(withScript, script...)
+	return append(withScript, page[i:]...)
+}
+
+// displayAddr returns an address to browse to, localhost when listening on all interfaces
+func displayAddr(addr net.Addr) string {
+	tcpAddr, ok := addr.(*net.TCPAddr)
+	if !ok || !tcpAddr.IP.IsUnspecified() {
+		return addr.String()
+	}
+	return net.JoinHostPort("localhost", strconv.Itoa(tcpAddr.Port))
+}
+
+// modTime returns when a file was last modified, or the zero time when it does not exist
+func modTime(path string) time.Time {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
@@ -0,0 +1,280 @@
+// Package doctor checks the environment gcov runs in, the Go toolchain, the module, its coverage
+// profile, the configuration, templates and writable directories, and suggests a fix for each problem
+package doctor
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"go/version"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/beck/go-coverage-analyzer/internal/command"
+	"github.com/beck/go-coverage-analyzer/internal/config"
+	"github.com/beck/go-coverage-analyzer/internal/generator"
+	"github.com/beck/go-coverage-analyzer/pkg/coverage"
+)
+
+// Statuses of a check
+const (
+	StatusOK   = "ok"
+	StatusWarn = "warn"
+	StatusFail = "fail"
+)
+
+// minCoverDataVersion is the first Go release with go tool covdata, which subprocess coverage needs
+const minCoverDataVersion = "go1.20"
+
+// Options configures the checks
+type Options struct {
+	ProjectPath    string
+	Config         *config.Config
+	ConfigErr      error         // why loading the configuration failed, nil when it loaded
+	CommandTimeout time.Duration // bounds each go command run by the checks; 0 for none
+}
+
+// Check is the outcome of one diagnostic, with the fix to apply when it did not pass
+type Check struct {
+	Name   string
+	Status string
+	Detail string
+	Fix    string
+}
+
+// Failed reports whether any check failed
+func Failed(checks []*Check) bool {
+	for _, check := range checks {
+		if check.Status == StatusFail {
+			return true
+		}
+	}
+	return false
+}
+
+// Run runs every check against the project; later checks still run when earlier ones fail
+func Run(ctx context.Context, opts *Options) []*Check {
+	checks := make([]*Check, 0)
+	goVersion, toolchain := checkToolchain(ctx, opts)
+	checks = append(checks, toolchain)
+
+	module := checkModule(opts, goVersion)
+	checks = append(checks, module)
+	if toolchain.Status != StatusFail && module.Status != StatusFail {
+		checks = append(checks,
+			checkGoCommand(ctx, opts, "go build", StatusFail, "Fix the build errors above: gcov needs the packages to compile to collect coverage", "build", "./..."),
+			checkGoCommand(ctx, opts, "go vet", StatusWarn, "Fix the issues go vet reports, go test runs the same checks and fails on them", "vet", "./..."),
+		)
+	}
+
+	checks = append(checks, checkProfile(opts), checkConfig(opts), checkTemplates(opts), checkWritable(opts))
+	return checks
+}
+
+// checkToolchain finds the go command and its version, which must be recent enough for the module
+// and for merging subprocess coverage
+func checkToolchain(ctx context.Context, opts *Options) (string, *Check) {
+	check := &Check{Name: "Go toolchain"}
+
+	cmdCtx, cancel := command.WithTimeout(ctx, opts.CommandTimeout)
+	defer cancel()
+	goEnv := command.Go(cmdCtx, "env", "GOVERSION")
+	goEnv.Dir = opts.ProjectPath
+	output, err := goEnv.Output()
+	if err != nil {
+		check.Status = StatusFail
+		check.Detail = fmt.Sprintf("go env failed: %v", command.Err(cmdCtx, err))
+		check.Fix = "Install Go from https://go.dev/dl and add its bin directory to PATH, or set GOROOT"
+		return "", check
+	}
+
+	goVersion := strings.TrimSpace(string(output))
+	check.Status = StatusOK
+	check.Detail = goVersion + " at " + command.GoTool()
+	if version.Compare(goVersion, minCoverDataVersion) < 0 {
+		check.Status = StatusWarn
+		check.Fix = "Upgrade to " + minCoverDataVersion + " or later, older toolchains cannot merge the coverage of main package harnesses"
+	}
+	return goVersion, check
+}
+
+// checkModule looks for the go.mod of the project and compares its go directive with the toolchain
+func checkModule(opts *Options, goVersion string) *Check {
+	check := &Check{Name: "Go module"}
+
+	goMod := filepath.Join(opts.ProjectPath, "go.mod")
+	file, err := os.Open(goMod)
+	if err != nil {
+		check.Status = StatusFail
+		check.Detail = "no go.mod in " + opts.ProjectPath
+		check.Fix = "Run go mod init <module path> in the project root, or point gcov at the directory holding go.mod"
+		return check
+	}
+	defer file.Close()
+
+	modulePath, required := "", ""
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[0] == "module" {
+			modulePath = strings.Trim(fields[1], `"`)
+		} else if len(fields) == 2 && fields[0] == "go" {
+			required = fields[1]
+		}
+	}
+
+	check.Status = StatusOK
+	check.Detail = modulePath
+	if required != "" {
+		check.Detail += ", go " + required
+	}
+	if goVersion != "" && required != "" && version.Compare(goVersion, "go"+required) < 0 {
+		check.Status = StatusWarn
+		check.Fix = fmt.Sprintf("go.mod requires go %s but %s is installed: upgrade Go, or set GOTOOLCHAIN=auto to download the required toolchain", required, goVersion)
+	}
+	return check
+}
+
+// checkGoCommand runs a go command over the module, reporting its first lines of output when it fails
+func checkGoCommand(ctx context.Context, opts *Options, name, status, fix string, args ...string) *Check {
+	check := &Check{Name: name}
+
+	cmdCtx, cancel := command.WithTimeout(ctx, opts.CommandTimeout)
+	defer cancel()
+	goCmd := command.Go(cmdCtx, args...)
+	goCmd.Dir = opts.ProjectPath
+	output, err := goCmd.CombinedOutput()
+	if err == nil {
+		check.Status = StatusOK
+		check.Detail = "passed"
+		return check
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	if len(lines) > 5 {
+		lines = append(lines[:5], fmt.Sprintf("... and %d more lines", len(lines)-5))
+	}
+	check.Status = status
+	check.Detail = fmt.Sprintf("%v\n%s", command.Err(cmdCtx, err), strings.Join(lines, "\n"))
+	check.Fix = fix
+	return check
+}
+
+// checkProfile looks for the configured coverage profile and whether it parses
+func checkProfile(opts *Options) *Check {
+	check := &Check{Name: "Coverage profile"}
+
+	profilePath := opts.Config.ProfileOutput
+	if profilePath == "" {
+		profilePath = "coverage.out"
+	}
+	if !filepath.IsAbs(profilePath) {
+		profilePath = filepath.Join(opts.ProjectPath, profilePath)
+	}
+
+	if _, err := os.Stat(profilePath); err != nil {
+		check.Status = StatusWarn
+		check.Detail = "no profile at " + profilePath
+		check.Fix = "Run gcov analyze --profile, or go test -coverprofile=coverage.out ./..., to record one"
+		return check
+	}
+
+	profile, err := coverage.NewProfileParser(false).ParseProfile(profilePath)
+	if err != nil {
+		check.Status = StatusFail
+		check.Detail = err.Error()
+		check.Fix = "Delete " + profilePath + " and record it again with gcov analyze --profile"
+		return check
+	}
+
+	check.Status = StatusOK
+	check.Detail = fmt.Sprintf("%s, %d blocks", profilePath, len(profile.Blocks))
+	return check
+}
+
+// checkConfig reports whether the configuration loaded and is valid
+func checkConfig(opts *Options) *Check {
+	check := &Check{Name: "Configuration"}
+
+	if opts.ConfigErr != nil {
+		check.Status = StatusFail
+		check.Detail = opts.ConfigErr.Error()
+		check.Fix = "Fix the syntax of gcov.yaml, the defaults are used until it loads"
+		return check
+	}
+	if err := opts.Config.Validate(); err != nil {
+		check.Status = StatusFail
+		check.Detail = err.Error()
+		check.Fix = "Correct the setting named above in gcov.yaml, or the GCOV_ environment variable overriding it"
+		return check
+	}
+
+	check.Status = StatusOK
+	check.Detail = "valid"
+	return check
+}
+
+// checkTemplates parses the test templates, custom ones included, and looks for the report templates
+func checkTemplates(opts *Options) *Check {
+	check := &Check{Name: "Templates"}
+
+	templatesDir := opts.Config.GetTemplatesDir()
+	if templatesDir != "" {
+		if info, err := os.Stat(templatesDir); err != nil || !info.IsDir() {
+			check.Status = StatusFail
+			check.Detail = "custom_templates_dir " + templatesDir + " is not a directory"
+			check.Fix = "Run gcov templates export " + templatesDir + " to start from the built-in templates, or unset custom_templates_dir"
+			return check
+		}
+	}
+
+	if err := generator.NewTemplateEngine(templatesDir, false).LoadTemplates(); err != nil {
+		check.Status = StatusFail
+		check.Detail = err.Error()
+		check.Fix = "Fix the template named above, or remove it from " + templatesDir + " to use the built-in one"
+		return check
+	}
+
+	for format, path := range opts.Config.ReportTemplates.Paths() {
+		if _, err := os.Stat(path); err != nil {
+			check.Status = StatusFail
+			check.Detail = fmt.Sprintf("%s report template %s not found", format, path)
+			check.Fix = "Fix report_templates." + format + " in gcov.yaml, relative paths are resolved from the config file"
+			return check
+		}
+	}
+
+	check.Status = StatusOK
+	check.Detail = "built-in"
+	if templatesDir != "" {
+		check.Detail = "custom from " + templatesDir
+	}
+	return check
+}
+
+// checkWritable creates and removes a file in each directory gcov writes to
+func checkWritable(opts *Options) *Check {
+	check := &Check{Name: "Write permissions"}
+
+	dirs := []string{opts.ProjectPath}
+	if outputDir := opts.Config.OutputDir; outputDir != "" && outputDir != "." {
+		dirs = append(dirs, outputDir)
+	}
+	for _, dir := range dirs {
+		file, err := os.CreateTemp(dir, ".gcov-doctor-*")
+		if err != nil {
+			check.Status = StatusFail
+			check.Detail = err.Error()
+			check.Fix = "Make " + dir + " writable by the current user, or set output_dir to a writable directory"
+			return check
+		}
+		file.Close()
+		os.Remove(file.Name())
+	}
+
+	check.Status = StatusOK
+	check.Detail = strings.Join(dirs, ", ")
+	return check
+}
@@ -0,0 +1,327 @@
+package generator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/beck/go-coverage-analyzer/internal/i18n"
+	"github.com/beck/go-coverage-analyzer/pkg/models"
+)
+
+// DefaultAIMinComplexity is the complexity from which functions are handed to the AI backend
+const DefaultAIMinComplexity = 10
+
+// defaultAITimeout bounds each request to the AI backend
+const defaultAITimeout = 2 * time.Minute
+
+// codeFence matches a fenced code block in a model response
+var codeFence = regexp.MustCompile("(?s)```(?:go)?\\s*\\n(.*?)```")
+
+// AIRequest is what an AI backend gets to write a test for one function
+type AIRequest struct {
+	Package   string
+	Function  string
+	Signature string
+	Source    string
+}
+
+// AIBackend writes candidate test code for functions the heuristic generator handles poorly
+type AIBackend interface {
+	GenerateTest(ctx context.Context, request *AIRequest) (string, error)
+}
+
+// OpenAIBackend asks an OpenAI-compatible chat completions endpoint for tests
+type OpenAIBackend struct {
+	endpoint string
+	model    string
+	apiKey   string
+	client   *http.Client
+}
+
+// NewOpenAIBackend creates a backend for the endpoint base URL, such as https://api.openai.com/v1
+func NewOpenAIBackend(endpoint, model, apiKey string, timeout time.Duration) *OpenAIBackend {
+	if timeout <= 0 {
+		timeout = defaultAITimeout
+	}
+	return &OpenAIBackend{
+		endpoint: strings.TrimSuffix(endpoint, "/"),
+		model:    model,
+		apiKey:   apiKey,
+		client:   &http.Client{Timeout: timeout},
+	}
+}
+
+// chatMessage is one message of a chat completions request or response
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// chatRequest is the body of a chat completions request
+type chatRequest struct {
+	Model       string        `json:"model"`
+	Messages    []chatMessage `json:"messages"`
+	Temperature float64       `json:"temperature"`
+}
+
+// chatResponse is the part of a chat completions response the backend reads
+type chatResponse struct {
+	Choices []struct {
+		Message chatMessage `json:"message"`
+	} `json:"choices"`
+}
+
+// GenerateTest sends the function to the endpoint and returns the test file it answers with
+func (b *OpenAIBackend) GenerateTest(ctx context.Context, request *AIRequest) (string, error) {
+	body, err := json.Marshal(chatRequest{
+		Model: b.model,
+		Messages: []chatMessage{
+			{Role: "system", Content: "You write Go unit tests. Answer with one complete Go test file and nothing else. Use only the standard library."},
+			{Role: "user", Content: aiPrompt(request)},
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, b.endpoint+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if b.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+b.apiKey)
+	}
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("request to %s failed: %w", b.endpoint, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode >= 300 {
+		return "", fmt.Errorf("endpoint returned %s: %s", resp.Status, strings.TrimSpace(string(data)))
+	}
+
+	var completion chatResponse
+	if err := json.Unmarshal(data, &completion); err != nil {
+		return "", fmt.Errorf("failed to decode response: %w", err)
+	}
+	if len(completion.Choices) == 0 {
+		return "", fmt.Errorf("endpoint returned no choices")
+	}
+
+	return extractCode(completion.Choices[0].Message.Content), nil
+}
+
+// aiPrompt describes the function a test is wanted for
+func aiPrompt(request *AIRequest) string {
+	var prompt strings.Builder
+	fmt.Fprintf(&prompt, "Write table-driven tests for %s in package %s.\n", request.Function, request.Package)
+	fmt.Fprintf(&prompt, "The test file must declare package %s so it can call unexported identifiers.\n", request.Package)
+	fmt.Fprintf(&prompt, "Signature: %s\n\nSource:\n%s\n", request.Signature, request.Source)
+	return prompt.String()
+}
+
+// extractCode returns the code inside the first fenced block of a response, or the whole response without one
+func extractCode(content string) string {
+	if match := codeFence.FindStringSubmatch(content); match != nil {
+		return strings.TrimSpace(match[1]) + "\n"
+	}
+	return strings.TrimSpace(content) + "\n"
+}
+
+// isAICandidate reports whether a function is complex enough to hand to the AI backend
+func (tg *TestGenerator) isAICandidate(function *models.Function) bool {
+	minComplexity := tg.options.AIMinComplexity
+	if minComplexity <= 0 {
+		minComplexity = DefaultAIMinComplexity
+	}
+	return tg.options.AIBackend != nil && function.Complexity >= minComplexity
+}
+
+// generateAITests asks the AI backend for tests of complex functions and keeps the ones that pass validation;
+// accepted functions are removed from the groups so the heuristic generator skips them
+func (tg *TestGenerator) generateAITests(ctx context.Context, fileGroups map[string][]*models.Function, result *models.GenerationResult) {
+	if tg.options.AIBackend == nil || tg.options.DryRun {
+		return
+	}
+
+	for filePath, functions := range fileGroups {
+		remaining := make([]*models.Function, 0, len(functions))
+		for _, function := range functions {
+			if !tg.isAICandidate(function) || ctx.Err() != nil {
+				remaining = append(remaining, function)
+				continue
+			}
+
+			generatedFile, err := tg.generateAITest(ctx, function)
+			if err != nil {
+				result.AITestsRejected++
+				result.Warnings = append(result.Warnings, fmt.Sprintf("AI test for %s rejected: %v", function.Name, err))
+				if tg.verbose {
+					i18n.Printf("🤖 AI test for %s rejected, falling back to heuristics: %v\n", function.Name, err)
+				}
+				remaining = append(remaining, function)
+				continue
+			}
+
+			result.AITestsAccepted++
+			result.GeneratedFiles = append(result.GeneratedFiles, generatedFile)
+			result.TestsGenerated += generatedFile.TestsGenerated
+			result.FilesCreated++
+			result.FunctionsCovered++
+			if tg.verbose {
+				i18n.Printf("🤖 Accepted AI test for %s: %s\n", function.Name, generatedFile.Path)
+			}
+		}
+
+		if len(remaining) == 0 {
+			delete(fileGroups, filePath)
+		} else {
+			fileGroups[filePath] = remaining
+		}
+	}
+}
+
+// generateAITest writes the backend's candidate for a function and keeps it only if it compiles and passes
+func (tg *TestGenerator) generateAITest(ctx context.Context, function *models.Function) (*models.GeneratedFile, error) {
+	source, err := tg.functionSource(function)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := tg.options.AIBackend.GenerateTest(ctx, &AIRequest{
+		Package:   function.Package,
+		Function:  function.Name,
+		Signature: function.Signature,
+		Source:    source,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	testNames, err := candidateTests(content, function.Package)
+	if err != nil {
+		return nil, err
+	}
+	if content, err = tg.inTestPackage(filepath.Dir(function.File), content); err != nil {
+		return nil, err
+	}
+
+	testFilePath := tg.aiTestFilePath(function)
+	if exists, err := tg.fileExists(testFilePath); err != nil || exists {
+		return nil, fmt.Errorf("%s already exists", testFilePath)
+	}
+	if _, err := tg.writeTestFile(testFilePath, content); err != nil {
+		return nil, err
+	}
+
+	generatedFile := &models.GeneratedFile{
+		Path:           testFilePath,
+		Package:        function.Package,
+		TestsGenerated: len(testNames),
+		Size:           int64(len(content)),
+		Created:        true,
+	}
+	for _, testName := range testNames {
+		generatedFile.TestCases = append(generatedFile.TestCases, &models.TestCase{
+			FunctionName:  function.Name,
+			TestName:      testName,
+			TestType:      "ai",
+			Target:        function.Key(),
+			Signature:     function.TypeSignature(),
+			ExpectedLines: estimateTestLines(content),
+			Complexity:    function.Complexity,
+		})
+	}
+
+	// Only the candidate's own tests run, so failures elsewhere in the package do not reject it
+	validator := NewTestValidator(tg.options.ProjectPath, false)
+	validator.SetOptions(&ValidationOptions{
+		Run:            "^(" + strings.Join(testNames, "|") + ")$",
+		CommandTimeout: tg.options.CommandTimeout,
+	})
+	validation, err := validator.ValidateTests(ctx, &models.GenerationResult{GeneratedFiles: []*models.GeneratedFile{generatedFile}})
+	if err == nil && !validation.Valid {
+		err = fmt.Errorf("%s", firstValidationError(validation))
+	}
+	if err != nil {
+		os.Remove(filepath.Join(tg.options.ProjectPath, testFilePath))
+		return nil, err
+	}
+
+	return generatedFile, nil
+}
+
+// functionSource reads the source of a function from its file
+func (tg *TestGenerator) functionSource(function *models.Function) (string, error) {
+	src, err := os.ReadFile(filepath.Join(tg.options.ProjectPath, function.File))
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", function.File, err)
+	}
+
+	lines := strings.Split(string(src), "\n")
+	if function.StartLine < 1 || function.EndLine > len(lines) || function.StartLine > function.EndLine {
+		return "", fmt.Errorf("invalid line range %d-%d in %s", function.StartLine, function.EndLine, function.File)
+	}
+	return strings.Join(lines[function.StartLine-1:function.EndLine], "\n"), nil
+}
+
+// candidateTests parses candidate test code and returns its test function names
+func candidateTests(content, packageName string) ([]string, error) {
+	file, err := parser.ParseFile(token.NewFileSet(), "", content, 0)
+	if err != nil {
+		return nil, fmt.Errorf("candidate does not parse: %w", err)
+	}
+	if file.Name.Name != packageName && file.Name.Name != packageName+"_test" {
+		return nil, fmt.Errorf("candidate declares package %s, want %s", file.Name.Name, packageName)
+	}
+
+	var testNames []string
+	for _, decl := range file.Decls {
+		if funcDecl, ok := decl.(*ast.FuncDecl); ok && funcDecl.Recv == nil && strings.HasPrefix(funcDecl.Name.Name, "Test") {
+			testNames = append(testNames, funcDecl.Name.Name)
+		}
+	}
+	if len(testNames) == 0 {
+		return nil, fmt.Errorf("candidate contains no test functions")
+	}
+	return testNames, nil
+}
+
+// aiTestFilePath names the file an AI test for a function is written to, next to the heuristic test file
+func (tg *TestGenerator) aiTestFilePath(function *models.Function) string {
+	base := strings.TrimSuffix(tg.getTestFilePath(function.File), "_test.go")
+	name := strings.ToLower(function.Name)
+	if function.ReceiverType != "" {
+		name = strings.ToLower(strings.TrimPrefix(function.ReceiverType, "*")) + "_" + name
+	}
+	return fmt.Sprintf("%s_%s_ai_test.go", base, name)
+}
+
+// firstValidationError returns the first problem that made a validation fail
+func firstValidationError(validation *ValidationResult) string {
+	for _, errs := range [][]string{validation.SyntaxErrors, validation.CompileErrors, validation.RuntimeErrors} {
+		if len(errs) > 0 {
+			return errs[0]
+		}
+	}
+	return "validation failed"
+}
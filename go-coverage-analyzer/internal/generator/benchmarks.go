@@ -0,0 +1,125 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/beck/go-coverage-analyzer/internal/i18n"
+	"github.com/beck/go-coverage-analyzer/internal/manifest"
+	"github.com/beck/go-coverage-analyzer/pkg/models"
+)
+
+// benchmarkHarnessFile compares benchmarks against a git ref, written to the project root
+const benchmarkHarnessFile = "bench_compare.sh"
+
+// BenchmarkSize is an input size sub-benchmarks run with
+type BenchmarkSize struct {
+	Name string
+	N    int
+}
+
+// benchmarkSizes are the input sizes of sub-benchmarks, from a few elements to enough to leave the cache
+var benchmarkSizes = []BenchmarkSize{
+	{Name: "small", N: 10},
+	{Name: "medium", N: 1000},
+	{Name: "large", N: 100000},
+}
+
+// isSizedType reports whether a parameter type carries an input size; numbers are left alone since
+// they often count iterations or recursion depth
+func isSizedType(typeName string) bool {
+	typeName = fieldType(typeName)
+	if typeName == "string" {
+		return true
+	}
+	return strings.HasPrefix(typeName, "[]") && !isFuncType(strings.TrimPrefix(typeName, "[]"))
+}
+
+// hasSizedParams reports whether a function takes input whose size is worth benchmarking
+func hasSizedParams(function *models.Function) bool {
+	for _, param := range function.Parameters {
+		if isSizedType(param.Type) {
+			return true
+		}
+	}
+	return false
+}
+
+// benchmarkValue returns the value a benchmark passes for a parameter, n elements long for sized types
+func benchmarkValue(function *models.Function, param *models.Param, n string) string {
+	if !isSizedType(param.Type) {
+		return paramValue(function, param, "positive")
+	}
+
+	switch typeName := fieldType(param.Type); typeName {
+	case "string":
+		return fmt.Sprintf(`strings.Repeat("x", %s)`, n)
+	case "[]byte":
+		return fmt.Sprintf(`[]byte(strings.Repeat("x", %s))`, n)
+	case "[]string":
+		return fmt.Sprintf(`strings.Fields(strings.Repeat("item ", %s))`, n)
+	default:
+		return fmt.Sprintf("make(%s, %s)", typeName, n)
+	}
+}
+
+// benchmarkVar names the *testing.B of a benchmark so it does not shadow a parameter called b
+func benchmarkVar(function *models.Function) string {
+	if findParam(function, "b") != nil {
+		return "bench"
+	}
+	return "b"
+}
+
+// benchmarkUsesStrings reports whether the sized inputs of a benchmark are built with the strings package
+func benchmarkUsesStrings(function *models.Function) bool {
+	for _, param := range function.Parameters {
+		switch fieldType(param.Type) {
+		case "string", "[]byte", "[]string":
+			return true
+		}
+	}
+	return false
+}
+
+const benchmarkHarnessTemplate = `#!/bin/sh
+# Compares the benchmarks of the working tree against a git ref (HEAD by default) with benchstat.
+# Usage: ./bench_compare.sh [ref]    COUNT and BENCH override the run count and -bench pattern.
+set -e
+
+ref="${1:-HEAD}"
+count="${COUNT:-10}"
+bench="${BENCH:-.}"
+tmp="$(mktemp -d)"
+trap 'git worktree remove --force "$tmp/base" >/dev/null 2>&1; rm -rf "$tmp"' EXIT
+
+git worktree add --detach "$tmp/base" "$ref" >/dev/null
+(cd "$tmp/base" && go test -run '^$' -bench "$bench" -benchmem -count "$count" ./...) > "$tmp/old.txt"
+go test -run '^$' -bench "$bench" -benchmem -count "$count" ./... > "$tmp/new.txt"
+
+go run golang.org/x/perf/cmd/benchstat@latest "$tmp/old.txt" "$tmp/new.txt"
+`
+
+// writeBenchmarkHarness adds the baseline comparison script to the project unless it is already there
+func (tg *TestGenerator) writeBenchmarkHarness() error {
+	harnessPath := filepath.Join(tg.options.ProjectPath, benchmarkHarnessFile)
+	if _, err := os.Stat(harnessPath); err == nil {
+		return nil
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("failed to check benchmark harness existence: %w", err)
+	}
+
+	if err := os.WriteFile(harnessPath, []byte(benchmarkHarnessTemplate), 0755); err != nil {
+		return fmt.Errorf("failed to write benchmark harness: %w", err)
+	}
+
+	tg.recordArtifact(manifest.KindHelper, benchmarkHarnessFile)
+
+	if tg.verbose {
+		i18n.Printf("📊 Created benchmark comparison harness: %s\n", harnessPath)
+	}
+
+	return nil
+}
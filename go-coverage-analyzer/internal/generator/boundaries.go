@@ -0,0 +1,259 @@
+package generator
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/beck/go-coverage-analyzer/pkg/models"
+)
+
+// maxBoundaryCases caps the boundary-value cases generated for one function
+const maxBoundaryCases = 12
+
+// maxLiteralLength is the longest string written out as a literal; longer ones use strings.Repeat
+const maxLiteralLength = 64
+
+// floatStep is how far below and above a floating-point boundary the neighbouring cases go
+const floatStep = 0.01
+
+// caseNameUnsafe matches characters not kept in generated case names
+var caseNameUnsafe = regexp.MustCompile(`[^A-Za-z0-9]+`)
+
+// boundaryValue is a candidate input around a boundary
+type boundaryValue struct {
+	position  string // below, at or above
+	value     string
+	satisfies bool // the boundary comparison holds for this input
+}
+
+// generateBoundaryCases creates cases just below, at and just above each boundary the function compares against
+func (te *TemplateEngine) generateBoundaryCases(function *models.Function) []TestCaseData {
+	var testCases []TestCaseData
+	seen := make(map[string]bool)
+
+	for _, boundary := range function.Boundaries {
+		param := findParam(function, boundary.Param)
+		if param == nil {
+			continue
+		}
+
+		for _, candidate := range boundaryValues(boundary, param.Type) {
+			key := param.Name + "=" + candidate.value
+			if seen[key] || len(testCases) >= maxBoundaryCases {
+				continue
+			}
+			seen[key] = true
+
+			subject := param.Name
+			if boundary.Length {
+				subject = "len_" + param.Name
+			}
+			testCase := TestCaseData{
+				Name:        boundaryCaseName(subject, candidate.position, boundary.Value),
+				Description: fmt.Sprintf("Test %s %s the boundary %s %s", subject, candidate.position, boundary.Op, boundary.Value),
+				Inputs:      make([]InputData, 0, len(function.Parameters)),
+				ExpectError: boundary.GuardsError && candidate.satisfies,
+			}
+
+			for _, p := range function.Parameters {
+				value := paramValue(function, p, "positive")
+				if p.Name == param.Name {
+					value = candidate.value
+				}
+				testCase.Inputs = append(testCase.Inputs, InputData{Name: p.Name, Type: p.Type, Value: value})
+			}
+
+			testCases = append(testCases, testCase)
+		}
+	}
+
+	return testCases
+}
+
+// usesRepeatedStrings reports whether boundary cases of a function build long strings with strings.Repeat
+func (te *TemplateEngine) usesRepeatedStrings(function *models.Function) bool {
+	for _, testCase := range te.generateBoundaryCases(function) {
+		for _, input := range testCase.Inputs {
+			if strings.HasPrefix(input.Value, "strings.Repeat(") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// boundaryValues returns the inputs just below, at and just above a boundary, as far as the parameter type allows
+func boundaryValues(boundary *models.Boundary, paramType string) []boundaryValue {
+	switch {
+	case boundary.Value == "nil":
+		values := []boundaryValue{{"at", "nil", boundary.Op == "=="}}
+		if value, ok := nonNilValue(paramType); ok {
+			values = append(values, boundaryValue{"not", value, boundary.Op == "!="})
+		}
+		return values
+
+	case paramType == "bool":
+		opposite := "true"
+		if boundary.Value == "true" {
+			opposite = "false"
+		}
+		return []boundaryValue{{"at", boundary.Value, boundary.Op == "=="}, {"not", opposite, boundary.Op == "!="}}
+
+	case boundary.Length:
+		n, err := strconv.ParseInt(boundary.Value, 0, 64)
+		if err != nil {
+			return nil
+		}
+		var values []boundaryValue
+		for _, step := range []struct {
+			position string
+			delta    int64
+		}{{"below", -1}, {"at", 0}, {"above", 1}} {
+			length := n + step.delta
+			value, ok := lengthValue(paramType, length)
+			if !ok {
+				continue
+			}
+			values = append(values, boundaryValue{step.position, value, compareInts(length, boundary.Op, n)})
+		}
+		return values
+
+	case isIntegerType(paramType):
+		n, err := strconv.ParseInt(boundary.Value, 0, 64)
+		if err != nil {
+			return nil
+		}
+		var values []boundaryValue
+		for _, step := range []struct {
+			position string
+			delta    int64
+		}{{"below", -1}, {"at", 0}, {"above", 1}} {
+			value := n + step.delta
+			if value < 0 && strings.HasPrefix(paramType, "u") {
+				continue
+			}
+			values = append(values, boundaryValue{step.position, strconv.FormatInt(value, 10), compareInts(value, boundary.Op, n)})
+		}
+		return values
+
+	case isFloatType(paramType):
+		f, err := strconv.ParseFloat(boundary.Value, 64)
+		if err != nil {
+			return nil
+		}
+		var values []boundaryValue
+		for _, step := range []struct {
+			position string
+			delta    float64
+		}{{"below", -floatStep}, {"at", 0}, {"above", floatStep}} {
+			value := f + step.delta
+			values = append(values, boundaryValue{step.position, formatFloat(value), compareFloats(value, boundary.Op, f)})
+		}
+		return values
+
+	case paramType == "string" && strings.ContainsAny(boundary.Value[:1], "\"`"):
+		// Equality checks only have the matching value as an interesting input
+		return []boundaryValue{{"at", boundary.Value, boundary.Op == "=="}}
+	}
+
+	return nil
+}
+
+// lengthValue renders a value of the parameter type with the given length
+func lengthValue(paramType string, length int64) (string, bool) {
+	switch {
+	case length < 0:
+		return "", false
+	case paramType == "string":
+		if length > maxLiteralLength {
+			return fmt.Sprintf(`strings.Repeat("a", %d)`, length), true
+		}
+		return strconv.Quote(strings.Repeat("a", int(length))), true
+	case strings.HasPrefix(paramType, "[]"):
+		return fmt.Sprintf("make(%s, %d)", paramType, length), true
+	}
+	return "", false
+}
+
+// nonNilValue renders an empty but non-nil value of a pointer, slice or map type
+func nonNilValue(paramType string) (string, bool) {
+	switch {
+	case strings.HasPrefix(paramType, "*"):
+		return "&" + strings.TrimPrefix(paramType, "*") + "{}", true
+	case strings.HasPrefix(paramType, "[]"), strings.HasPrefix(paramType, "map["):
+		return paramType + "{}", true
+	}
+	return "", false
+}
+
+// compareInts evaluates a boundary comparison for an integer input
+func compareInts(value int64, op string, boundary int64) bool {
+	switch op {
+	case "<":
+		return value < boundary
+	case "<=":
+		return value <= boundary
+	case ">":
+		return value > boundary
+	case ">=":
+		return value >= boundary
+	case "==":
+		return value == boundary
+	case "!=":
+		return value != boundary
+	}
+	return false
+}
+
+// compareFloats evaluates a boundary comparison for a floating-point input
+func compareFloats(value float64, op string, boundary float64) bool {
+	switch op {
+	case "<":
+		return value < boundary
+	case "<=":
+		return value <= boundary
+	case ">":
+		return value > boundary
+	case ">=":
+		return value >= boundary
+	case "==":
+		return value == boundary
+	case "!=":
+		return value != boundary
+	}
+	return false
+}
+
+// formatFloat renders a float as its shortest Go literal
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// boundaryCaseName names a boundary case like amount_below_100
+func boundaryCaseName(subject, position, value string) string {
+	sign := ""
+	if strings.HasPrefix(value, "-") {
+		sign = "minus_"
+	}
+
+	value = strings.Trim(caseNameUnsafe.ReplaceAllString(value, "_"), "_")
+	if len(value) > 20 {
+		value = value[:20]
+	}
+	if value == "" {
+		return fmt.Sprintf("%s_%s_boundary", subject, position)
+	}
+	return fmt.Sprintf("%s_%s_%s%s", subject, position, sign, value)
+}
+
+// findParam returns the parameter with the given name
+func findParam(function *models.Function, name string) *models.Param {
+	for _, param := range function.Parameters {
+		if param.Name == name {
+			return param
+		}
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package generator
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/beck/go-coverage-analyzer/internal/i18n"
+	"github.com/beck/go-coverage-analyzer/internal/manifest"
+	"github.com/beck/go-coverage-analyzer/pkg/models"
+)
+
+// clockHelperFile holds the fake clocks shared by tests in a package
+const clockHelperFile = "gcov_clock_test.go"
+
+// isClockOf reports whether a type is one of the clock interfaces a function can be handed
+func isClockOf(function *models.Function, t string) bool {
+	for _, clock := range function.Clocks {
+		if clock == t {
+			return true
+		}
+	}
+	return false
+}
+
+// fakeClockType names the frozen fake of a clock interface
+func fakeClockType(clock string) string {
+	return "fake" + strings.ToUpper(clock[:1]) + clock[1:]
+}
+
+// fakeClockValue renders the frozen fake of a clock interface
+func fakeClockValue(clock string) string {
+	return fakeClockType(clock) + "{}"
+}
+
+// hasRecentResults reports whether a function returns clock readings, checked against the current time
+func hasRecentResults(function *models.Function) bool {
+	if !function.UsesTime {
+		return false
+	}
+	for _, returnType := range function.ReturnTypes {
+		if returnType == "time.Time" {
+			return true
+		}
+	}
+	return false
+}
+
+// packageClocks returns the clock interfaces tests of functions in a package directory fake
+func packageClocks(analysisResult *models.AnalysisResult, dir string) []string {
+	seen := make(map[string]bool)
+	for _, pkg := range analysisResult.PackageCoverage {
+		for _, file := range pkg.Files {
+			if filepath.Dir(file.Path) != dir {
+				continue
+			}
+			for _, function := range file.Functions {
+				for _, clock := range function.Clocks {
+					seen[clock] = true
+				}
+			}
+		}
+	}
+
+	clocks := make([]string, 0, len(seen))
+	for clock := range seen {
+		clocks = append(clocks, clock)
+	}
+	sort.Strings(clocks)
+	return clocks
+}
+
+const clockHelperTemplate = `package {{.PackageName}}
+
+import "time"
+
+// testClockTime is the time fake clocks are frozen at
+var testClockTime = time.Date(2024, time.January, 2, 3, 4, 5, 0, time.UTC)
+{{range .Clocks}}{{$fake := fakeClockType .}}
+// {{$fake}} is a {{.}} frozen at testClockTime; its other methods panic until a test implements them
+type {{$fake}} struct {
+	{{.}}
+}
+
+// Now returns testClockTime
+func ({{$fake}}) Now() time.Time {
+	return testClockTime
+}
+{{end}}`
+
+// writeClockHelper adds the fake clocks to a package unless they are already there
+func (tg *TestGenerator) writeClockHelper(dir, packageName string, clocks []string) error {
+	helperPath := filepath.Join(dir, clockHelperFile)
+	exists, err := tg.fileExists(helperPath)
+	if err != nil {
+		return fmt.Errorf("failed to check clock helper existence: %w", err)
+	}
+	if exists {
+		return nil
+	}
+
+	content, err := tg.templateEngine.GenerateFile("clock_helper", &TemplateData{PackageName: packageName, Clocks: clocks})
+	if err == nil {
+		content, err = tg.inTestPackage(dir, content)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to generate clock helper: %w", err)
+	}
+
+	if _, err := tg.writeTestFile(helperPath, content); err != nil {
+		return fmt.Errorf("failed to write clock helper: %w", err)
+	}
+
+	tg.recordArtifact(manifest.KindHelper, helperPath)
+
+	if tg.verbose {
+		i18n.Printf("⏰ Created fake clocks: %s\n", helperPath)
+	}
+
+	return nil
+}
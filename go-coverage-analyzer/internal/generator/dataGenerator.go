@@ -2,24 +2,32 @@ package generator
 
 import (
 	"fmt"
+	"hash/fnv"
 	"math/rand"
 	"strconv"
 	"strings"
-	"time"
+	"sync"
 
+	"github.com/beck/go-coverage-analyzer/internal/i18n"
 	"github.com/beck/go-coverage-analyzer/pkg/models"
 )
 
+// DefaultSeed is the seed used when none is given, so regenerated tests are identical
+const DefaultSeed int64 = 1
+
 // DataGenerator handles intelligent test data generation
 type DataGenerator struct {
+	mu      sync.Mutex // test files are generated concurrently, and each function reseeds rand
 	rand    *rand.Rand
+	seed    int64
 	verbose bool
 }
 
-// NewDataGenerator creates a new data generator
-func NewDataGenerator(verbose bool) *DataGenerator {
+// NewDataGenerator creates a new data generator whose values are reproducible for a given seed
+func NewDataGenerator(seed int64, verbose bool) *DataGenerator {
 	return &DataGenerator{
-		rand:    rand.New(rand.NewSource(time.Now().UnixNano())),
+		rand:    rand.New(rand.NewSource(seed)),
+		seed:    seed,
 		verbose: verbose,
 	}
 }
@@ -57,9 +65,17 @@ type GeneratedTestCase struct {
 // GenerateTestData generates comprehensive test data for a function
 func (dg *DataGenerator) GenerateTestData(function *models.Function, maxCases int) (*TestDataSet, error) {
 	if dg.verbose {
-		fmt.Printf("🎲 Generating test data for function: %s\n", function.Name)
+		i18n.Printf("🎲 Generating test data for function: %s\n", function.Name)
 	}
 
+	dg.mu.Lock()
+	defer dg.mu.Unlock()
+
+	// Each function draws from its own stream, so its values do not depend on generation order
+	key := fnv.New64a()
+	key.Write([]byte(function.Package + "." + function.ReceiverType + "." + function.Name))
+	dg.rand = rand.New(rand.NewSource(dg.seed ^ int64(key.Sum64())))
+
 	testSet := &TestDataSet{
 		FunctionName: function.Name,
 		TestCases:    make([]GeneratedTestCase, 0),
@@ -91,7 +107,7 @@ func (dg *DataGenerator) GenerateTestData(function *models.Function, maxCases in
 	}
 
 	if dg.verbose {
-		fmt.Printf("✅ Generated %d test cases for %s\n", len(testSet.TestCases), function.Name)
+		i18n.Printf("✅ Generated %d test cases for %s\n", len(testSet.TestCases), function.Name)
 	}
 
 	return testSet, nil
@@ -114,6 +130,9 @@ func (dg *DataGenerator) generateCasesForStrategy(function *models.Function, str
 		// Generate inputs for each parameter
 		for _, param := range function.Parameters {
 			value, stringRepr := dg.generateValueForType(param.Type, strategy)
+			if isInterfaceOf(function, param.Type) {
+				value, stringRepr = nil, paramValue(function, param, string(strategy))
+			}
 			testCase.Inputs[param.Name] = value
 			testCase.InputStrings[param.Name] = stringRepr
 		}
@@ -154,6 +173,12 @@ func (dg *DataGenerator) generateValueForType(goType string, strategy Generation
 		return dg.generatePointerValue(goType, strategy)
 	case goType == "interface{}" || goType == "any":
 		return dg.generateInterfaceValue(strategy)
+	case goType == "error":
+		return nil, "nil"
+	case isChanType(goType):
+		return dg.generateChanValue(goType, strategy)
+	case isFuncType(goType):
+		return nil, funcStub(goType)
 	case strings.Contains(goType, "."):
 		return dg.generateCustomTypeValue(goType, strategy)
 	default:
@@ -343,6 +368,16 @@ func (dg *DataGenerator) generateInterfaceValue(strategy GenerationStrategy) (in
 	}
 }
 
+// generateChanValue generates channel test values
+func (dg *DataGenerator) generateChanValue(goType string, strategy GenerationStrategy) (interface{}, string) {
+	switch strategy {
+	case StrategyZero:
+		return nil, "nil"
+	default:
+		return nil, chanValue(goType)
+	}
+}
+
 // generateCustomTypeValue generates values for custom types
 func (dg *DataGenerator) generateCustomTypeValue(goType string, strategy GenerationStrategy) (interface{}, string) {
 	switch strategy {
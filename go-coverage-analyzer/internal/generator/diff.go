@@ -0,0 +1,182 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/beck/go-coverage-analyzer/internal/i18n"
+)
+
+// Dry run formats: what a dry run prints for each file it would write
+const (
+	DryRunSummary = "summary" // one line per file
+	DryRunDiff    = "diff"    // a unified diff against the file on disk, or /dev/null for new files
+	DryRunFull    = "full"    // the whole content of the file
+)
+
+// diffContext is the number of unchanged lines shown around each change in a unified diff
+const diffContext = 3
+
+// diffLine is a line of a diff: ' ' for a line both sides have, '-' for a removed line, '+' for an added one
+type diffLine struct {
+	op   byte
+	text string
+}
+
+// previewFile prints the file a dry run would write at a path relative to the project, in the
+// configured dry run format
+func (tg *TestGenerator) previewFile(relPath, content string) {
+	existing, err := os.ReadFile(filepath.Join(tg.options.ProjectPath, relPath))
+	exists := err == nil
+
+	switch tg.options.DryRunFormat {
+	case DryRunFull:
+		fmt.Printf("=== %s ===\n%s", filepath.ToSlash(relPath), content)
+		if !strings.HasSuffix(content, "\n") {
+			fmt.Println()
+		}
+	case DryRunSummary:
+		lines := strings.Count(content, "\n")
+		switch {
+		case !exists:
+			i18n.Printf("👀 Would create %s (%d lines)\n", relPath, lines)
+		case string(existing) == content:
+			i18n.Printf("👀 Would leave %s unchanged\n", relPath)
+		default:
+			i18n.Printf("👀 Would modify %s (%d lines)\n", relPath, lines)
+		}
+	default:
+		oldName := "/dev/null"
+		if exists {
+			oldName = "a/" + filepath.ToSlash(relPath)
+		}
+		fmt.Print(unifiedDiff(oldName, "b/"+filepath.ToSlash(relPath), string(existing), content))
+	}
+}
+
+// unifiedDiff returns the unified diff turning oldText into newText, or "" when they are the same
+func unifiedDiff(oldName, newName, oldText, newText string) string {
+	if oldText == newText {
+		return ""
+	}
+
+	lines := diffLines(splitLines(oldText), splitLines(newText))
+
+	var diff strings.Builder
+	fmt.Fprintf(&diff, "--- %s\n+++ %s\n", oldName, newName)
+
+	// Group changes less than two contexts apart into one hunk
+	for start := 0; start < len(lines); {
+		first := start
+		for first < len(lines) && lines[first].op == ' ' {
+			first++
+		}
+		if first == len(lines) {
+			break
+		}
+
+		last := first
+		for i := first; i < len(lines); i++ {
+			if lines[i].op != ' ' {
+				last = i
+			} else if i-last > 2*diffContext {
+				break
+			}
+		}
+
+		from := max(0, first-diffContext)
+		to := min(len(lines), last+diffContext+1)
+		writeHunk(&diff, lines, from, to)
+		start = to
+	}
+	return diff.String()
+}
+
+// writeHunk writes the hunk of diff lines from to to, with its header
+func writeHunk(diff *strings.Builder, lines []diffLine, from, to int) {
+	oldBefore, newBefore := 0, 0
+	for _, line := range lines[:from] {
+		if line.op != '+' {
+			oldBefore++
+		}
+		if line.op != '-' {
+			newBefore++
+		}
+	}
+
+	oldCount, newCount := 0, 0
+	for _, line := range lines[from:to] {
+		if line.op != '+' {
+			oldCount++
+		}
+		if line.op != '-' {
+			newCount++
+		}
+	}
+
+	fmt.Fprintf(diff, "@@ -%s +%s @@\n", hunkRange(oldBefore, oldCount), hunkRange(newBefore, newCount))
+	for _, line := range lines[from:to] {
+		fmt.Fprintf(diff, "%c%s\n", line.op, line.text)
+	}
+}
+
+// hunkRange formats the start,count of one side of a hunk header; an empty side starts at the line
+// before it
+func hunkRange(before, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", before)
+	}
+	return fmt.Sprintf("%d,%d", before+1, count)
+}
+
+// splitLines splits text into lines without their line endings
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// diffLines returns the edit script turning a into b along their longest common subsequence
+func diffLines(a, b []string) []diffLine {
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int32, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int32, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	lines := make([]diffLine, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			lines = append(lines, diffLine{' ', a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, diffLine{'-', a[i]})
+			i++
+		default:
+			lines = append(lines, diffLine{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		lines = append(lines, diffLine{'-', a[i]})
+	}
+	for ; j < len(b); j++ {
+		lines = append(lines, diffLine{'+', b[j]})
+	}
+	return lines
+}
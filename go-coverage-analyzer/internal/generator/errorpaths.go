@@ -0,0 +1,128 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/beck/go-coverage-analyzer/internal/i18n"
+	"github.com/beck/go-coverage-analyzer/pkg/models"
+)
+
+// errorPathInput returns the parameter value that takes an error path, or false when inputs alone cannot reach it
+func errorPathInput(function *models.Function, path *models.ErrorPath) (*models.Param, string, bool) {
+	if path.Guard == nil {
+		return nil, "", false
+	}
+
+	param := findParam(function, path.Guard.Param)
+	if param == nil {
+		return nil, "", false
+	}
+
+	for _, candidate := range boundaryValues(path.Guard, param.Type) {
+		if candidate.satisfies {
+			return param, candidate.value, true
+		}
+	}
+	return nil, "", false
+}
+
+// generateErrorPathCases creates a case for each error return whose condition the inputs control,
+// unless an existing case already expects an error from the same inputs
+func (te *TemplateEngine) generateErrorPathCases(function *models.Function, existing []TestCaseData) []TestCaseData {
+	covered := make(map[string]bool)
+	for _, testCase := range existing {
+		if testCase.ExpectError {
+			covered[inputsKey(testCase.Inputs)] = true
+		}
+	}
+
+	var testCases []TestCaseData
+	for _, path := range function.ErrorPaths {
+		param, value, ok := errorPathInput(function, path)
+		if !ok {
+			continue
+		}
+
+		testCase := TestCaseData{
+			Name:        fmt.Sprintf("error_path_line_%d", path.Line),
+			Description: fmt.Sprintf("Test the error returned when %s", path.Condition),
+			Inputs:      make([]InputData, 0, len(function.Parameters)),
+			ExpectError: true,
+		}
+		for _, p := range function.Parameters {
+			input := InputData{Name: p.Name, Type: p.Type, Value: value}
+			if p.Name != param.Name {
+				input.Value = avoidErrorPaths(function, p, paramValue(function, p, "positive"), path)
+			}
+			testCase.Inputs = append(testCase.Inputs, input)
+		}
+
+		key := inputsKey(testCase.Inputs)
+		if covered[key] {
+			continue
+		}
+		covered[key] = true
+		testCases = append(testCases, testCase)
+	}
+
+	return testCases
+}
+
+// avoidErrorPaths swaps a value for one that does not take any other error path guarded by the parameter,
+// so a case reaches the path it targets rather than an earlier one
+func avoidErrorPaths(function *models.Function, param *models.Param, value string, target *models.ErrorPath) string {
+	for _, path := range function.ErrorPaths {
+		if path == target || path.Guard == nil || path.Guard.Param != param.Name {
+			continue
+		}
+
+		candidates := boundaryValues(path.Guard, param.Type)
+		taken := false
+		for _, candidate := range candidates {
+			if candidate.value == value && candidate.satisfies {
+				taken = true
+			}
+		}
+		if !taken {
+			continue
+		}
+		for _, candidate := range candidates {
+			if !candidate.satisfies {
+				value = candidate.value
+				break
+			}
+		}
+	}
+	return value
+}
+
+// trackErrorPaths counts the error paths generated cases target and records those no input can reach
+func (tg *TestGenerator) trackErrorPaths(result *models.GenerationResult, functions []*models.Function) {
+	for _, function := range functions {
+		for _, path := range function.ErrorPaths {
+			if _, _, ok := errorPathInput(function, path); ok {
+				result.ErrorPathsTargeted++
+				continue
+			}
+
+			result.UnreachableErrorPaths = append(result.UnreachableErrorPaths, fmt.Sprintf("%s:%d", function.File, path.Line))
+			if tg.verbose {
+				condition := path.Condition
+				if condition == "" {
+					condition = "unconditional"
+				}
+				i18n.Printf("🚧 No input reaches the error path at %s:%d (%s)\n", function.File, path.Line, condition)
+			}
+		}
+	}
+}
+
+// inputsKey identifies a combination of input values
+func inputsKey(inputs []InputData) string {
+	values := make([]string, len(inputs))
+	for i, input := range inputs {
+		values[i] = input.Value
+	}
+	return strings.Join(values, "\x00")
+}
@@ -0,0 +1,231 @@
+package generator
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/beck/go-coverage-analyzer/internal/command"
+	"github.com/beck/go-coverage-analyzer/internal/i18n"
+	"github.com/beck/go-coverage-analyzer/pkg/models"
+)
+
+// evaluationMarker prefixes each result line printed by the evaluation harness
+const evaluationMarker = "GCOV_EVAL"
+
+// printMarker prefixes each line holding how fmt prints a result
+const printMarker = "GCOV_PRINT"
+
+// evaluationTimeout bounds a single harness run
+const evaluationTimeout = "60s"
+
+// Evaluator computes expected values by running pure functions in a temporary test harness
+type Evaluator struct {
+	projectPath string
+	timeout     time.Duration // bounds each harness run including its build, 0 for none
+	verbose     bool
+	results     map[string]string // call expression to Go literal of the returned value
+	printed     map[string]string // call expression to the returned value as fmt prints it
+}
+
+// evaluationCall is a single function invocation in the harness
+type evaluationCall struct {
+	expression string
+	returnType string
+}
+
+// NewEvaluator creates an evaluator for the project whose harness runs are bounded by commandTimeout
+func NewEvaluator(projectPath string, commandTimeout time.Duration, verbose bool) *Evaluator {
+	return &Evaluator{
+		projectPath: projectPath,
+		timeout:     commandTimeout,
+		verbose:     verbose,
+		results:     make(map[string]string),
+		printed:     make(map[string]string),
+	}
+}
+
+// Evaluate runs every pure function with the inputs of its test cases and records the results, until ctx is cancelled
+func (e *Evaluator) Evaluate(ctx context.Context, functions []*models.Function, testCases func(*models.Function) []TestCaseData) {
+	// A harness lives next to the code it calls, so calls are grouped by package directory
+	calls := make(map[string][]*evaluationCall)
+	packageNames := make(map[string]string)
+
+	for _, function := range functions {
+		if !isPureFunction(function) {
+			continue
+		}
+
+		dir := filepath.Dir(function.File)
+		packageNames[dir] = function.Package
+		for _, testCase := range testCases(function) {
+			if len(testCase.Inputs) != len(function.Parameters) {
+				continue
+			}
+			calls[dir] = append(calls[dir], &evaluationCall{
+				expression: callExpression(function, testCase.Inputs),
+				returnType: function.ReturnTypes[0],
+			})
+		}
+	}
+
+	dirs := make([]string, 0, len(calls))
+	for dir := range calls {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+
+	for _, dir := range dirs {
+		if ctx.Err() != nil {
+			return
+		}
+		if err := e.evaluatePackage(ctx, dir, packageNames[dir], calls[dir]); err != nil {
+			if e.verbose {
+				i18n.Printf("⚠️ Could not evaluate functions in %s, keeping heuristic values: %v\n", dir, err)
+			}
+			continue
+		}
+	}
+
+	if e.verbose {
+		i18n.Printf("🧮 Evaluated %d expected values by executing pure functions\n", len(e.results))
+	}
+}
+
+// Lookup returns the evaluated result of calling the function with the given inputs
+func (e *Evaluator) Lookup(function *models.Function, inputs []InputData) (string, bool) {
+	value, exists := e.results[callExpression(function, inputs)]
+	return value, exists
+}
+
+// LookupPrinted returns how fmt.Println prints the result of calling the function with the given inputs, without the newline
+func (e *Evaluator) LookupPrinted(function *models.Function, inputs []InputData) (string, bool) {
+	value, exists := e.printed[callExpression(function, inputs)]
+	return value, exists
+}
+
+// evaluatePackage writes a harness into the package directory, runs it and collects the printed results
+func (e *Evaluator) evaluatePackage(ctx context.Context, dir, packageName string, calls []*evaluationCall) error {
+	fullDir := filepath.Join(e.projectPath, dir)
+
+	harness, err := os.CreateTemp(fullDir, "gcov_eval_*_test.go")
+	if err != nil {
+		return fmt.Errorf("failed to create harness: %w", err)
+	}
+	defer os.Remove(harness.Name())
+
+	if _, err := harness.WriteString(renderHarness(packageName, calls)); err != nil {
+		harness.Close()
+		return fmt.Errorf("failed to write harness: %w", err)
+	}
+	if err := harness.Close(); err != nil {
+		return fmt.Errorf("failed to write harness: %w", err)
+	}
+
+	ctx, cancel := command.WithTimeout(ctx, e.timeout)
+	defer cancel()
+
+	cmd := command.Go(ctx, "test", "-v", "-count=1", "-timeout", evaluationTimeout, "-run", "^TestGcovEvaluate$", ".")
+	cmd.Dir = fullDir
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("harness failed: %w\n%s", command.Err(ctx, err), strings.TrimSpace(string(output)))
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(string(output)))
+	for scanner.Scan() {
+		fields := strings.SplitN(scanner.Text(), " ", 3)
+		if len(fields) != 3 || (fields[0] != evaluationMarker && fields[0] != printMarker) {
+			continue
+		}
+
+		index, err := strconv.Atoi(fields[1])
+		if err != nil || index < 0 || index >= len(calls) {
+			continue
+		}
+
+		if fields[0] == printMarker {
+			if printed, err := strconv.Unquote(fields[2]); err == nil {
+				e.printed[calls[index].expression] = printed
+			}
+			continue
+		}
+
+		// Panics, NaN and infinities have no literal the generated test could compare against
+		value := fields[2]
+		if value == "panic" || strings.Contains(value, "NaN") || strings.Contains(value, "Inf") {
+			continue
+		}
+		e.results[calls[index].expression] = value
+	}
+
+	return nil
+}
+
+// renderHarness builds a test file that prints the result of each call as a Go literal and as fmt prints it
+func renderHarness(packageName string, calls []*evaluationCall) string {
+	var src strings.Builder
+
+	src.WriteString(fmt.Sprintf("package %s\n\n", packageName))
+	src.WriteString("import (\n\t\"fmt\"\n\t\"strings\"\n\t\"testing\"\n)\n\n")
+	src.WriteString("// Boundary inputs may build long strings\nvar _ = strings.Repeat\n\n")
+	src.WriteString("func TestGcovEvaluate(t *testing.T) {\n")
+	src.WriteString("\tevaluate := func(index int, call func() interface{}) {\n")
+	src.WriteString("\t\tdefer func() {\n")
+	src.WriteString("\t\t\tif r := recover(); r != nil {\n")
+	src.WriteString(fmt.Sprintf("\t\t\t\tfmt.Printf(\"%s %%d panic\\n\", index)\n", evaluationMarker))
+	src.WriteString("\t\t\t}\n")
+	src.WriteString("\t\t}()\n")
+	src.WriteString("\t\tresult := call()\n")
+	src.WriteString(fmt.Sprintf("\t\tfmt.Printf(\"%s %%d %%#v\\n\", index, result)\n", evaluationMarker))
+	src.WriteString(fmt.Sprintf("\t\tfmt.Printf(\"%s %%d %%q\\n\", index, fmt.Sprint(result))\n", printMarker))
+	src.WriteString("\t}\n")
+
+	for i, call := range calls {
+		// Converting keeps the printed literal typed like the declared return value
+		src.WriteString(fmt.Sprintf("\tevaluate(%d, func() interface{} { return %s(%s) })\n", i, call.returnType, call.expression))
+	}
+
+	src.WriteString("}\n")
+	return src.String()
+}
+
+// callExpression renders the call of a function with the given inputs
+func callExpression(function *models.Function, inputs []InputData) string {
+	values := make([]string, 0, len(inputs))
+	for _, input := range inputs {
+		values = append(values, input.Value)
+	}
+	return fmt.Sprintf("%s(%s)", function.Name, strings.Join(values, ", "))
+}
+
+// isPureFunction reports whether a function can be executed in isolation to compute its result
+func isPureFunction(function *models.Function) bool {
+	if function.IsMethod || function.CallsExternal || function.HasErrorReturn || len(function.Fixtures) > 0 {
+		return false
+	}
+
+	// Results depending on the environment, files, the network or the clock differ between runs
+	if len(function.SideEffects()) > 0 {
+		return false
+	}
+
+	if len(function.ReturnTypes) != 1 || !isBasicType(function.ReturnTypes[0]) {
+		return false
+	}
+
+	for _, param := range function.Parameters {
+		if !isBasicType(param.Type) && param.Type != "[]string" && param.Type != "[]int" {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,147 @@
+package generator
+
+import (
+	"fmt"
+	"go/format"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// Mock frameworks selectable with --mock-framework
+const (
+	MockFrameworkTestify = "testify"
+	MockFrameworkFake    = "fake"
+)
+
+// fakeTemplate renders a struct whose methods call overridable function fields
+const fakeTemplate = `package {{.Package}}
+{{if .Imports}}
+import (
+{{range .Imports}}	"{{.}}"
+{{end}})
+{{end}}
+// Fake{{.Name}} is a fake implementation of {{.Name}}; set a method's Func field to override it
+type Fake{{.Name}} struct {
+{{range .Methods}}	{{.Name}}Func func{{funcFieldSignature .}}
+{{end}}}
+
+{{range .Methods}}// {{.Name}} calls {{.Name}}Func when set, and returns zero values otherwise
+func (f *Fake{{$.Name}}) {{.Name}}{{namedResultsSignature .}} {
+	if f.{{.Name}}Func != nil {
+		{{if .Returns}}return {{end}}f.{{.Name}}Func({{fakeCallArgs .}})
+	}{{if .Returns}}
+	return{{end}}
+}
+
+{{end}}`
+
+// generateFakeFile generates a fake implementation with function-field overrides for an interface
+func (mg *MockGenerator) generateFakeFile(iface *MockInterface, projectPath string) (*GeneratedMock, error) {
+	tmpl, err := template.New("fake").Funcs(template.FuncMap{
+		"funcFieldSignature":    mg.buildMethodSignature,
+		"namedResultsSignature": namedResultsSignature,
+		"fakeCallArgs":          fakeCallArgs,
+	}).Parse(fakeTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse fake template: %w", err)
+	}
+
+	templateData := struct {
+		*MockInterface
+		Imports []string
+	}{
+		MockInterface: iface,
+		Imports:       mg.signatureImports(iface),
+	}
+
+	var content strings.Builder
+	if err := tmpl.Execute(&content, templateData); err != nil {
+		return nil, fmt.Errorf("failed to execute fake template: %w", err)
+	}
+
+	// Align the function fields the way gofmt would
+	formatted, err := format.Source([]byte(content.String()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to format fake: %w", err)
+	}
+
+	fakeFileName := fmt.Sprintf("fake_%s.go", strings.ToLower(iface.Name))
+	fakeFilePath := filepath.Join(filepath.Dir(iface.FilePath), fakeFileName)
+
+	// Make path relative to project
+	if relPath, err := filepath.Rel(projectPath, fakeFilePath); err == nil && !strings.HasPrefix(relPath, "..") {
+		fakeFilePath = relPath
+	}
+
+	return &GeneratedMock{
+		Interface:    iface,
+		FilePath:     fakeFilePath,
+		Content:      string(formatted),
+		TestFilePath: iface.FilePath,
+	}, nil
+}
+
+// namedResultsSignature renders a method signature with named results, so a bare return yields zero values
+func namedResultsSignature(method *MockMethod) string {
+	var params []string
+	for _, param := range method.Parameters {
+		params = append(params, fmt.Sprintf("%s %s", param.Name, param.Type))
+	}
+	signature := fmt.Sprintf("(%s)", strings.Join(params, ", "))
+
+	if len(method.Returns) > 0 {
+		var returns []string
+		for _, ret := range method.Returns {
+			returns = append(returns, fmt.Sprintf("%s %s", ret.Name, ret.Type))
+		}
+		signature += " (" + strings.Join(returns, ", ") + ")"
+	}
+
+	return signature
+}
+
+// fakeCallArgs passes a method's parameters on to its function field, spreading a variadic one
+func fakeCallArgs(method *MockMethod) string {
+	var args []string
+	for _, param := range method.Parameters {
+		if strings.HasPrefix(param.Type, "...") {
+			args = append(args, param.Name+"...")
+			continue
+		}
+		args = append(args, param.Name)
+	}
+	return strings.Join(args, ", ")
+}
+
+// signatureImports returns the standard imports the interface's method signatures refer to
+func (mg *MockGenerator) signatureImports(iface *MockInterface) []string {
+	var imports []string
+	needsContext, needsIO := false, false
+
+	for _, method := range iface.Methods {
+		types := make([]string, 0, len(method.Parameters)+len(method.Returns))
+		for _, param := range method.Parameters {
+			types = append(types, param.Type)
+		}
+		for _, ret := range method.Returns {
+			types = append(types, ret.Type)
+		}
+		for _, t := range types {
+			if strings.Contains(t, "context.Context") {
+				needsContext = true
+			}
+			if strings.Contains(t, "io.") {
+				needsIO = true
+			}
+		}
+	}
+
+	if needsContext {
+		imports = append(imports, "context")
+	}
+	if needsIO {
+		imports = append(imports, "io")
+	}
+	return imports
+}
@@ -1,15 +1,24 @@
 package generator
 
 import (
+	"context"
+	"crypto/sha256"
 	"fmt"
 	"go/ast"
 	"go/parser"
 	"go/token"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/beck/go-coverage-analyzer/internal/backup"
+	"github.com/beck/go-coverage-analyzer/internal/fileutil"
+	"github.com/beck/go-coverage-analyzer/internal/i18n"
+	"github.com/beck/go-coverage-analyzer/internal/manifest"
 	"github.com/beck/go-coverage-analyzer/pkg/models"
 )
 
@@ -17,14 +26,36 @@ import (
 type Options struct {
 	ProjectPath        string
 	DryRun             bool
+	DryRunFormat       string // what a dry run prints for each file: summary, diff or full, see DryRunDiff
 	TemplateStyle      string
+	TemplatesDir       string // custom templates overriding the embedded ones, see ExportTemplates
+	TestNamePattern    string // Go template naming tests, see DefaultTestNamePattern
 	GenerateMocks      bool
+	MockFramework      string // testify or fake, see MockFrameworkTestify
 	TableDriven        bool
 	GenerateBenchmarks bool
+	BenchmarkBaseline  bool // write bench_compare.sh comparing benchmarks against a git ref
+	ParallelTests      bool // generated tests and subtests call t.Parallel
+	Skeleton           bool // generated tests skip with a TODO and leave expectations as placeholders
 	Overwrite          bool
+	Backup             bool // keep the previous content of overwritten files in .gcov/backups, see gcov restore
 	IgnoreFunctions    []string
+	Functions          []string // only generate tests for these functions, Receiver.Name or Name; empty for all
 	MaxTestCases       int
+	EvaluateExpected   bool      // execute pure functions to compute expected values
+	StressTests        bool      // add concurrent stress tests for functions using goroutines, channels or sync
+	Examples           bool      // add ExampleXxx functions with Output comments for simple exported functions
+	SuiteStyle         string    // none, testmain or testify-suite for functions needing shared fixtures
+	MainHarness        bool      // run the commands of main packages in a subprocess, see mainHarnessFile
+	TestPackageMode    string    // same, external or auto, see TestPackageSame
+	Seed               int64     // seed for generated test data, see DefaultSeed
+	Version            string    // gcov version recorded in generated tests and the manifest, see DefaultVersion
+	AIBackend          AIBackend // writes tests for complex functions, nil disables it
+	AIMinComplexity    int       // complexity from which functions go to the AI backend, see DefaultAIMinComplexity
+	MaxConcurrency     int       // source files whose tests are generated at the same time, 1 when below
 	Verbose            bool
+	Progress           models.ProgressFunc // told how many source files have their tests generated, may be nil
+	CommandTimeout     time.Duration       // bounds each go command validating or evaluating tests, 0 for none
 }
 
 // TestGenerator orchestrates the test generation process
@@ -33,6 +64,11 @@ type TestGenerator struct {
 	dataGenerator  *DataGenerator
 	mockGenerator  *MockGenerator
 	validator      *TestValidator
+	evaluator      *Evaluator
+	suites         map[string]*suitePlan   // package directory to its fixture file
+	testPackages   map[string]*testPackage // package directory to its external test package, nil for same-package tests
+	artifacts      []*manifest.Entry       // support files written so far, recorded in the manifest with the tests
+	backups        *backup.Run             // previous content of overwritten files, nil when not backing up
 	options        *Options
 	fileSet        *token.FileSet
 	verbose        bool
@@ -40,25 +76,29 @@ type TestGenerator struct {
 
 // NewTestGenerator creates a new test generator
 func NewTestGenerator(opts *Options) *TestGenerator {
+	validator := NewTestValidator(opts.ProjectPath, opts.Verbose)
+	validator.SetOptions(&ValidationOptions{CommandTimeout: opts.CommandTimeout})
+
 	return &TestGenerator{
-		templateEngine: NewTemplateEngine(opts.Verbose),
-		dataGenerator:  NewDataGenerator(opts.Verbose),
-		mockGenerator:  NewMockGenerator(opts.Verbose),
-		validator:      NewTestValidator(opts.ProjectPath, opts.Verbose),
+		templateEngine: NewTemplateEngine(opts.TemplatesDir, opts.Verbose),
+		dataGenerator:  NewDataGenerator(opts.Seed, opts.Verbose),
+		mockGenerator:  NewMockGenerator(opts.MockFramework, opts.Verbose),
+		validator:      validator,
 		options:        opts,
 		fileSet:        token.NewFileSet(),
 		verbose:        opts.Verbose,
 	}
 }
 
-// Generate creates test files for uncovered functions
-func Generate(analysisResult *models.AnalysisResult, opts *Options) (*models.GenerationResult, error) {
+// Generate creates test files for uncovered functions. When ctx is cancelled it stops before the next
+// source file, and returns what was generated so far along with the error
+func Generate(ctx context.Context, analysisResult *models.AnalysisResult, opts *Options) (*models.GenerationResult, error) {
 	startTime := time.Now()
 
 	if opts.Verbose {
-		fmt.Printf("🛠️ Starting test generation for: %s\n", opts.ProjectPath)
+		i18n.Printf("🛠️ Starting test generation for: %s\n", opts.ProjectPath)
 		if opts.DryRun {
-			fmt.Println("👀 Running in dry-run mode")
+			i18n.Println("👀 Running in dry-run mode")
 		}
 	}
 
@@ -73,22 +113,29 @@ func Generate(analysisResult *models.AnalysisResult, opts *Options) (*models.Gen
 	if opts.GenerateMocks {
 		if err := generator.generateMocks(analysisResult); err != nil {
 			if opts.Verbose {
-				fmt.Printf("⚠️ Mock generation failed: %v\n", err)
+				i18n.Printf("⚠️ Mock generation failed: %v\n", err)
 			}
 		}
 	}
 
 	// Generate tests for uncovered functions
-	result, err := generator.generateTests(analysisResult)
+	result, err := generator.generateTests(ctx, analysisResult)
 	if err != nil {
 		return nil, fmt.Errorf("test generation failed: %w", err)
 	}
+	if generator.backups.Saved() > 0 {
+		result.BackupRun = generator.backups.ID()
+	}
+	if ctx.Err() != nil {
+		result.GenerationTime = time.Since(startTime)
+		return result, fmt.Errorf("test generation interrupted: %w", ctx.Err())
+	}
 
 	// Validate generated tests
 	if !opts.DryRun {
-		if err := generator.validateTests(result); err != nil {
+		if err := generator.validateTests(ctx, result); err != nil {
 			if opts.Verbose {
-				fmt.Printf("⚠️ Test validation failed: %v\n", err)
+				i18n.Printf("⚠️ Test validation failed: %v\n", err)
 			}
 		}
 	}
@@ -96,8 +143,8 @@ func Generate(analysisResult *models.AnalysisResult, opts *Options) (*models.Gen
 	result.GenerationTime = time.Since(startTime)
 
 	if opts.Verbose {
-		fmt.Printf("✅ Test generation completed in %v\n", result.GenerationTime)
-		fmt.Printf("📊 Generated %d tests across %d files\n", result.TestsGenerated, result.FilesCreated)
+		i18n.Printf("✅ Test generation completed in %v\n", result.GenerationTime)
+		i18n.Printf("📊 Generated %d tests across %d files\n", result.TestsGenerated, result.FilesCreated)
 	}
 
 	return result, nil
@@ -110,10 +157,26 @@ func (tg *TestGenerator) initialize() error {
 		return fmt.Errorf("failed to load templates: %w", err)
 	}
 
+	namer, err := NewTestNamer(tg.options.TestNamePattern)
+	if err != nil {
+		return fmt.Errorf("invalid test name pattern: %w", err)
+	}
+	tg.templateEngine.SetTestNamer(namer)
+	tg.templateEngine.SetParallel(tg.options.ParallelTests)
+	tg.templateEngine.SetSkeleton(tg.options.Skeleton)
+	tg.templateEngine.SetVersion(tg.options.Version)
+
+	if tg.options.Backup && !tg.options.DryRun {
+		if tg.backups, err = backup.NewRun(tg.options.ProjectPath); err != nil {
+			return fmt.Errorf("failed to start backups: %w", err)
+		}
+		tg.mockGenerator.backups = tg.backups
+	}
+
 	if tg.verbose {
-		fmt.Printf("🔧 Test generator initialized with style: %s\n", tg.options.TemplateStyle)
+		i18n.Printf("🔧 Test generator initialized with style: %s\n", tg.options.TemplateStyle)
 		if tg.options.GenerateMocks {
-			fmt.Println("🎭 Mock generation enabled")
+			i18n.Println("🎭 Mock generation enabled")
 		}
 	}
 
@@ -121,29 +184,66 @@ func (tg *TestGenerator) initialize() error {
 }
 
 // generateTests generates test files for uncovered functions
-func (tg *TestGenerator) generateTests(analysisResult *models.AnalysisResult) (*models.GenerationResult, error) {
+func (tg *TestGenerator) generateTests(ctx context.Context, analysisResult *models.AnalysisResult) (*models.GenerationResult, error) {
 	result := &models.GenerationResult{
 		ProjectPath:    tg.options.ProjectPath,
 		Timestamp:      time.Now(),
+		Seed:           tg.options.Seed,
 		GeneratedFiles: make([]*models.GeneratedFile, 0),
 		Errors:         make([]string, 0),
-		Warnings:       make([]string, 0),
+		Warnings:       append([]string{}, tg.templateEngine.Warnings()...),
 	}
 
 	// Group functions by source file
 	fileGroups := tg.groupFunctionsByFile(analysisResult.UncoveredFunctions)
 
-	for filePath, functions := range fileGroups {
-		if tg.verbose {
-			fmt.Printf("📝 Processing file: %s (%d functions)\n", filePath, len(functions))
+	// Decide which package tests are declared in before anything is generated for them
+	tg.planTestPackages(fileGroups)
+
+	// Decide where shared fixtures go before tests reference them
+	tg.planSuites(fileGroups)
+
+	// Evaluate before any test file is written so new files cannot break the harness build
+	if tg.options.EvaluateExpected || tg.options.Examples {
+		tg.evaluateExpectedValues(ctx, fileGroups)
+	}
+
+	// Complex functions get validated AI tests first, the rest fall through to the templates
+	tg.generateAITests(ctx, fileGroups, result)
+
+	// Name the tests across each package, which the test files planned concurrently below cannot do
+	tg.planTestNames(fileGroups)
+
+	// Test files are planned concurrently but written one at a time in source file order, so results,
+	// errors and the files on disk do not depend on scheduling
+	filePaths := make([]string, 0, len(fileGroups))
+	for filePath := range fileGroups {
+		filePaths = append(filePaths, filePath)
+	}
+	sort.Strings(filePaths)
+	plans, wait := tg.planTestFiles(ctx, filePaths, fileGroups, analysisResult)
+	defer wait()
+
+	for i, filePath := range filePaths {
+		// Files already written are still recorded in suites and the manifest below
+		if ctx.Err() != nil {
+			break
+		}
+		plan := <-plans[i]
+		if plan == nil {
+			break
 		}
 
-		generatedFile, err := tg.generateTestFile(filePath, functions, analysisResult)
+		functions := fileGroups[filePath]
+		generatedFile, err := tg.writeTestPlan(plan, analysisResult)
+		if tg.options.Progress != nil {
+			tg.options.Progress("Generating tests", i+1, len(filePaths))
+		}
 		if err != nil {
 			errorMsg := fmt.Sprintf("Failed to generate tests for %s: %v", filePath, err)
 			result.Errors = append(result.Errors, errorMsg)
 			if tg.verbose {
-				fmt.Printf("❌ %s\n", errorMsg)
+				i18n.Printf("❌ %s\n", errorMsg)
 			}
 			continue
 		}
@@ -151,12 +251,34 @@ func (tg *TestGenerator) generateTests(analysisResult *models.AnalysisResult) (*
 		if generatedFile != nil {
 			result.GeneratedFiles = append(result.GeneratedFiles, generatedFile)
 			result.TestsGenerated += generatedFile.TestsGenerated
-			if generatedFile.Created {
+			switch {
+			case generatedFile.Unchanged:
+				result.FilesUnchanged++
+			case generatedFile.Created:
 				result.FilesCreated++
-			} else {
+			default:
 				result.FilesModified++
 			}
 			result.FunctionsCovered += len(functions)
+			tg.trackErrorPaths(result, functions)
+		}
+	}
+
+	if !tg.options.DryRun {
+		tg.writeSuites(result)
+
+		if tg.options.MainHarness {
+			tg.writeMainHarnesses(analysisResult, result)
+		}
+
+		if tg.options.GenerateBenchmarks && tg.options.BenchmarkBaseline && len(result.GeneratedFiles) > 0 {
+			if err := tg.writeBenchmarkHarness(); err != nil {
+				result.Errors = append(result.Errors, err.Error())
+			}
+		}
+
+		if err := tg.updateManifest(result); err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("Failed to update the manifest: %v", err))
 		}
 	}
 
@@ -174,6 +296,32 @@ func (tg *TestGenerator) generateTests(analysisResult *models.AnalysisResult) (*
 	return result, nil
 }
 
+// evaluateExpectedValues executes pure functions with their generated inputs and hands the results to the template engine
+func (tg *TestGenerator) evaluateExpectedValues(ctx context.Context, fileGroups map[string][]*models.Function) {
+	functions := make([]*models.Function, 0)
+	for _, group := range fileGroups {
+		functions = append(functions, group...)
+	}
+
+	tg.evaluator = NewEvaluator(tg.options.ProjectPath, tg.options.CommandTimeout, tg.verbose)
+	tg.evaluator.Evaluate(ctx, functions, func(function *models.Function) []TestCaseData {
+		return tg.templateEngine.generateTestCases(function, tg.options.TemplateStyle)
+	})
+
+	// Examples only need the printed results, assertions use them when asked to
+	if tg.options.EvaluateExpected {
+		tg.templateEngine.SetEvaluator(tg.evaluator)
+	}
+}
+
+// exampleOutput returns the printed output an example of the function produces, if it was evaluated
+func (tg *TestGenerator) exampleOutput(function *models.Function) (string, bool) {
+	if !tg.options.Examples || tg.evaluator == nil || !function.IsExported {
+		return "", false
+	}
+	return tg.evaluator.LookupPrinted(function, tg.templateEngine.ExampleInputs(function))
+}
+
 // groupFunctionsByFile groups functions by their source file
 func (tg *TestGenerator) groupFunctionsByFile(functions []*models.Function) map[string][]*models.Function {
 	fileGroups := make(map[string][]*models.Function)
@@ -184,6 +332,13 @@ func (tg *TestGenerator) groupFunctionsByFile(functions []*models.Function) map[
 		}
 	}
 
+	// Tests follow the source order, whatever order the analysis found the functions in
+	for _, group := range fileGroups {
+		sort.SliceStable(group, func(i, j int) bool {
+			return group[i].StartLine < group[j].StartLine
+		})
+	}
+
 	return fileGroups
 }
 
@@ -196,6 +351,12 @@ func (tg *TestGenerator) shouldGenerateTest(function *models.Function) bool {
 		}
 	}
 
+	// Skip functions other than the selected ones
+	if len(tg.options.Functions) > 0 && !slices.Contains(tg.options.Functions, function.Key()) &&
+		!slices.Contains(tg.options.Functions, function.Name) {
+		return false
+	}
+
 	// Skip if not testable
 	if !function.IsTestable {
 		return false
@@ -230,22 +391,81 @@ func (tg *TestGenerator) matchesPattern(functionName, pattern string) bool {
 	return false
 }
 
-// generateTestFile generates a test file for functions from a source file
-func (tg *TestGenerator) generateTestFile(sourceFile string, functions []*models.Function, analysisResult *models.AnalysisResult) (*models.GeneratedFile, error) {
+// testFilePlan is the test file planned for the functions of a source file, ready to be written
+type testFilePlan struct {
+	sourceFile   string
+	testFilePath string
+	functions    []*models.Function
+	exists       bool
+	skipped      bool // the test file exists and is not overwritten
+	content      string
+	testCases    []*models.TestCase
+	err          error
+}
+
+// planTestFiles plans the test files of the source files on up to MaxConcurrency workers. The plan of
+// filePaths[i] arrives on plans[i], or nil once ctx is cancelled; wait returns when the workers are done
+func (tg *TestGenerator) planTestFiles(ctx context.Context, filePaths []string, fileGroups map[string][]*models.Function, analysisResult *models.AnalysisResult) (plans []chan *testFilePlan, wait func()) {
+	plans = make([]chan *testFilePlan, len(filePaths))
+	for i := range plans {
+		plans[i] = make(chan *testFilePlan, 1)
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < max(1, tg.options.MaxConcurrency); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				if ctx.Err() != nil {
+					plans[job] <- nil
+					continue
+				}
+				plans[job] <- tg.planTestFile(filePaths[job], fileGroups[filePaths[job]], analysisResult)
+			}
+		}()
+	}
+
+	go func() {
+		for job := range filePaths {
+			jobs <- job
+		}
+		close(jobs)
+	}()
+
+	return plans, wg.Wait
+}
+
+// planTestFile generates the content of the test file for functions from a source file without
+// writing it; it runs concurrently with the plans of other source files
+func (tg *TestGenerator) planTestFile(sourceFile string, functions []*models.Function, analysisResult *models.AnalysisResult) *testFilePlan {
+	if tg.verbose {
+		i18n.Printf("📝 Processing file: %s (%d functions)\n", sourceFile, len(functions))
+	}
+
 	// Determine test file path
-	testFilePath := tg.getTestFilePath(sourceFile)
+	plan := &testFilePlan{
+		sourceFile:   sourceFile,
+		testFilePath: tg.getTestFilePath(sourceFile),
+		functions:    functions,
+	}
+	testFilePath := plan.testFilePath
 
 	// Check if test file already exists and handle accordingly
 	exists, err := tg.fileExists(testFilePath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to check test file existence: %w", err)
+		plan.err = fmt.Errorf("failed to check test file existence: %w", err)
+		return plan
 	}
+	plan.exists = exists
 
 	if exists && !tg.options.Overwrite {
 		if tg.verbose {
-			fmt.Printf("⚠️ Test file %s exists, skipping (use --overwrite to replace)\n", testFilePath)
+			i18n.Printf("⚠️ Test file %s exists, skipping (use --overwrite to replace)\n", testFilePath)
 		}
-		return nil, nil
+		plan.skipped = true
+		return plan
 	}
 
 	// Parse existing test file if it exists to avoid duplicates
@@ -255,22 +475,66 @@ func (tg *TestGenerator) generateTestFile(sourceFile string, functions []*models
 		if err != nil {
 			// We'll add warnings to the result when we have access to it
 			if tg.verbose {
-				fmt.Printf("⚠️ Failed to parse existing tests in %s: %v\n", testFilePath, err)
+				i18n.Printf("⚠️ Failed to parse existing tests in %s: %v\n", testFilePath, err)
 			}
 		}
 	}
 
 	// Generate test content
-	testContent, testCases, err := tg.generateTestFileContent(functions, existingTests, analysisResult)
+	plan.content, plan.testCases, err = tg.generateTestFileContent(functions, existingTests, analysisResult)
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate test content: %w", err)
+		plan.err = fmt.Errorf("failed to generate test content: %w", err)
 	}
+	return plan
+}
+
+// writeTestPlan writes a planned test file along with the helpers its tests need, or previews it in a
+// dry run
+func (tg *TestGenerator) writeTestPlan(plan *testFilePlan, analysisResult *models.AnalysisResult) (*models.GeneratedFile, error) {
+	if plan.err != nil {
+		return nil, plan.err
+	}
+	if plan.skipped {
+		return nil, nil
+	}
+
+	sourceFile, testFilePath, functions := plan.sourceFile, plan.testFilePath, plan.functions
+	exists, testContent, testCases := plan.exists, plan.content, plan.testCases
 
 	// Write test file
-	if !tg.options.DryRun {
-		if err := tg.writeTestFile(testFilePath, testContent); err != nil {
+	changed := true
+	var err error
+	if tg.options.DryRun {
+		tg.previewFile(testFilePath, testContent)
+	} else {
+		if changed, err = tg.writeTestFile(testFilePath, testContent); err != nil {
 			return nil, fmt.Errorf("failed to write test file: %w", err)
 		}
+
+		if tg.usesSnapshots(functions) {
+			if err := tg.writeGoldenHelper(filepath.Dir(sourceFile), functions[0].Package); err != nil {
+				return nil, err
+			}
+		}
+
+		for _, function := range functions {
+			if usesFakeTransport(function) {
+				if err := tg.writeHTTPHelper(filepath.Dir(sourceFile), function.Package); err != nil {
+					return nil, err
+				}
+				break
+			}
+		}
+
+		for _, function := range functions {
+			if len(function.Clocks) > 0 {
+				dir := filepath.Dir(sourceFile)
+				if err := tg.writeClockHelper(dir, function.Package, packageClocks(analysisResult, dir)); err != nil {
+					return nil, err
+				}
+				break
+			}
+		}
 	}
 
 	generatedFile := &models.GeneratedFile{
@@ -280,20 +544,68 @@ func (tg *TestGenerator) generateTestFile(sourceFile string, functions []*models
 		TestCases:      testCases,
 		Size:           int64(len(testContent)),
 		Created:        !exists,
-		Modified:       exists,
+		Modified:       exists && changed,
+		Unchanged:      !changed,
 	}
 
 	if tg.verbose {
-		if exists {
-			fmt.Printf("✏️ Modified test file: %s (%d tests)\n", testFilePath, len(testCases))
+		if !changed {
+			i18n.Printf("✅ Test file unchanged: %s (%d tests)\n", testFilePath, len(testCases))
+		} else if exists {
+			i18n.Printf("✏️ Modified test file: %s (%d tests)\n", testFilePath, len(testCases))
 		} else {
-			fmt.Printf("✨ Created test file: %s (%d tests)\n", testFilePath, len(testCases))
+			i18n.Printf("✨ Created test file: %s (%d tests)\n", testFilePath, len(testCases))
 		}
 	}
 
 	return generatedFile, nil
 }
 
+// usesSnapshots reports whether any of the functions get a snapshot test
+func (tg *TestGenerator) usesSnapshots(functions []*models.Function) bool {
+	if tg.options.TemplateStyle != "snapshot" {
+		return false
+	}
+	for _, function := range functions {
+		if isSnapshotCandidate(function) {
+			return true
+		}
+	}
+	return false
+}
+
+// writeGoldenHelper adds the golden file helper to a package unless it is already there
+func (tg *TestGenerator) writeGoldenHelper(dir, packageName string) error {
+	helperPath := filepath.Join(dir, goldenHelperFile)
+	exists, err := tg.fileExists(helperPath)
+	if err != nil {
+		return fmt.Errorf("failed to check golden helper existence: %w", err)
+	}
+	if exists {
+		return nil
+	}
+
+	content, err := tg.templateEngine.GenerateGoldenHelper(packageName)
+	if err == nil {
+		content, err = tg.inTestPackage(dir, content)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to generate golden helper: %w", err)
+	}
+
+	if _, err := tg.writeTestFile(helperPath, content); err != nil {
+		return fmt.Errorf("failed to write golden helper: %w", err)
+	}
+
+	tg.recordArtifact(manifest.KindHelper, helperPath)
+
+	if tg.verbose {
+		i18n.Printf("📸 Created golden file helper: %s\n", helperPath)
+	}
+
+	return nil
+}
+
 // getTestFilePath generates the test file path for a source file
 func (tg *TestGenerator) getTestFilePath(sourceFile string) string {
 	dir := filepath.Dir(sourceFile)
@@ -363,10 +675,10 @@ func (tg *TestGenerator) generateTestFileContent(functions []*models.Function, e
 
 	// Generate tests for each function
 	for _, function := range functions {
-		testName := "Test" + function.Name
+		testName := tg.templateEngine.TestName(function, "")
 		if existingTests[testName] && !tg.options.Overwrite {
 			if tg.verbose {
-				fmt.Printf("⏭️ Skipping existing test: %s\n", testName)
+				i18n.Printf("⏭️ Skipping existing test: %s\n", testName)
 			}
 			continue
 		}
@@ -375,7 +687,7 @@ func (tg *TestGenerator) generateTestFileContent(functions []*models.Function, e
 		testData, err := tg.dataGenerator.GenerateTestData(function, tg.options.MaxTestCases)
 		if err != nil {
 			if tg.verbose {
-				fmt.Printf("⚠️ Failed to generate test data for %s: %v\n", function.Name, err)
+				i18n.Printf("⚠️ Failed to generate test data for %s: %v\n", function.Name, err)
 			}
 			continue
 		}
@@ -384,11 +696,16 @@ func (tg *TestGenerator) generateTestFileContent(functions []*models.Function, e
 		testContent, err := tg.templateEngine.GenerateTest(function, tg.options.TemplateStyle, tg.options.TableDriven)
 		if err != nil {
 			if tg.verbose {
-				fmt.Printf("⚠️ Failed to generate test for %s: %v\n", function.Name, err)
+				i18n.Printf("⚠️ Failed to generate test for %s: %v\n", function.Name, err)
 			}
 			continue
 		}
 
+		// Fixture-backed tests run as methods of the package suite
+		if tg.options.SuiteStyle == SuiteStyleTestifySuite && len(function.Fixtures) > 0 && tg.suites[filepath.Dir(function.File)] != nil {
+			testContent = toSuiteMethod(testContent, testName, function.Package)
+		}
+
 		contentParts = append(contentParts, testContent)
 
 		// Convert test data to test cases for result tracking
@@ -397,6 +714,8 @@ func (tg *TestGenerator) generateTestFileContent(functions []*models.Function, e
 				FunctionName:  function.Name,
 				TestName:      testName,
 				TestType:      "unit",
+				Target:        function.Key(),
+				Signature:     function.TypeSignature(),
 				InputCount:    len(generatedCase.Inputs),
 				HasMocks:      tg.options.GenerateMocks && tg.needsMocks(function),
 				HasSetup:      false,
@@ -416,6 +735,8 @@ func (tg *TestGenerator) generateTestFileContent(functions []*models.Function, e
 					FunctionName:  function.Name,
 					TestName:      "Benchmark" + function.Name,
 					TestType:      "benchmark",
+					Target:        function.Key(),
+					Signature:     function.TypeSignature(),
 					InputCount:    len(function.Parameters),
 					HasMocks:      false,
 					ExpectedLines: estimateTestLines(benchmarkContent),
@@ -424,9 +745,69 @@ func (tg *TestGenerator) generateTestFileContent(functions []*models.Function, e
 				allTestCases = append(allTestCases, benchmarkCase)
 			}
 		}
+
+		// Generate a documentation example if requested and the output is known
+		if output, ok := tg.exampleOutput(function); ok {
+			exampleContent, err := tg.templateEngine.GenerateExample(function, output)
+			if err == nil {
+				contentParts = append(contentParts, exampleContent)
+				exampleCase := &models.TestCase{
+					FunctionName:  function.Name,
+					TestName:      "Example" + function.Name,
+					TestType:      "example",
+					Target:        function.Key(),
+					Signature:     function.TypeSignature(),
+					InputCount:    len(function.Parameters),
+					ExpectedLines: estimateTestLines(exampleContent),
+					Complexity:    function.Complexity,
+				}
+				allTestCases = append(allTestCases, exampleCase)
+			}
+		}
+
+		// Generate a test of the inputs the function panics on
+		if tg.templateEngine.HasPanicTest(function) {
+			panicContent, err := tg.templateEngine.GeneratePanicTest(function, tg.options.TemplateStyle)
+			if err == nil {
+				contentParts = append(contentParts, panicContent)
+				panicCase := &models.TestCase{
+					FunctionName:  function.Name,
+					TestName:      tg.templateEngine.TestName(function, PanicTestVariant),
+					TestType:      "panic",
+					Target:        function.Key(),
+					Signature:     function.TypeSignature(),
+					InputCount:    len(function.Parameters),
+					ExpectedLines: estimateTestLines(panicContent),
+					Complexity:    function.Complexity,
+				}
+				allTestCases = append(allTestCases, panicCase)
+			}
+		}
+
+		// Generate a race-friendly stress test if requested
+		if tg.options.StressTests && function.Concurrent && function.SQL == nil {
+			stressContent, err := tg.templateEngine.GenerateTest(function, "stress", false)
+			if err == nil {
+				contentParts = append(contentParts, stressContent)
+				stressCase := &models.TestCase{
+					FunctionName:  function.Name,
+					TestName:      tg.templateEngine.TestName(function, StressTestVariant),
+					TestType:      "stress",
+					Target:        function.Key(),
+					Signature:     function.TypeSignature(),
+					InputCount:    len(function.Parameters),
+					ExpectedLines: estimateTestLines(stressContent),
+					Complexity:    function.Complexity,
+				}
+				allTestCases = append(allTestCases, stressCase)
+			}
+		}
 	}
 
-	fullContent := strings.Join(contentParts, "\n")
+	fullContent, err := tg.inTestPackage(filepath.Dir(functions[0].File), strings.Join(contentParts, "\n"))
+	if err != nil {
+		return "", nil, err
+	}
 	return fullContent, allTestCases, nil
 }
 
@@ -437,9 +818,31 @@ func (tg *TestGenerator) generateImports(functions []*models.Function) []string
 	// Add testify if using testify style
 	if tg.options.TemplateStyle == "testify" {
 		imports = append(imports, "github.com/stretchr/testify/assert")
-		if tg.options.GenerateMocks {
+		if tg.options.GenerateMocks && tg.options.MockFramework != MockFrameworkFake {
 			imports = append(imports, "github.com/stretchr/testify/mock")
 		}
+		for _, function := range functions {
+			if tg.templateEngine.HasPanicTest(function) {
+				imports = append(imports, "github.com/stretchr/testify/require")
+				break
+			}
+		}
+	}
+
+	// Add the assertion library of the other third-party styles
+	switch tg.options.TemplateStyle {
+	case "gotest-tools":
+		imports = append(imports, "gotest.tools/v3/assert")
+	case "go-cmp":
+		imports = append(imports, "github.com/google/go-cmp/cmp")
+	}
+
+	// Add sqlmock imports if any function talks to a database
+	for _, function := range functions {
+		if function.SQL != nil {
+			imports = append(imports, "regexp", "github.com/DATA-DOG/go-sqlmock")
+			break
+		}
 	}
 
 	// Add context import if any function uses context
@@ -450,6 +853,43 @@ func (tg *TestGenerator) generateImports(functions []*models.Function) []string
 				break
 			}
 		}
+
+		// Cancellation and deadline cases build their contexts with time
+		if hasContextCases(function) {
+			imports = append(imports, "time")
+		}
+
+		// HTTP tests build a fake transport, stubs may refer to standard packages
+		if usesFakeTransport(function) {
+			imports = append(imports, "net/http")
+		}
+		imports = append(imports, stubImports(function)...)
+
+		// Long boundary strings and sized benchmark inputs are built with strings.Repeat
+		if tg.templateEngine.usesRepeatedStrings(function) || (tg.options.GenerateBenchmarks && benchmarkUsesStrings(function)) {
+			imports = append(imports, "strings")
+		}
+
+		// Clock readings are checked against time.Now
+		if hasRecentResults(function) {
+			imports = append(imports, "time")
+		}
+
+		// Tests set up the fixtures no TestMain or suite shares
+		imports = append(imports, tg.templateEngine.testFixtureImports(function, tg.options.TemplateStyle)...)
+
+		// Examples print their results
+		if _, ok := tg.exampleOutput(function); ok {
+			imports = append(imports, "fmt")
+		}
+
+		// Concurrent functions are checked for leaked goroutines
+		if function.Concurrent {
+			imports = append(imports, "go.uber.org/goleak")
+			if tg.options.StressTests && function.SQL == nil {
+				imports = append(imports, "sync")
+			}
+		}
 	}
 
 	return removeDuplicateStrings(imports)
@@ -460,22 +900,40 @@ func (tg *TestGenerator) needsMocks(function *models.Function) bool {
 	return function.CallsExternal || len(function.Dependencies) > 0
 }
 
-// writeTestFile writes the test content to a file
-func (tg *TestGenerator) writeTestFile(testFilePath, content string) error {
+// writeTestFile writes the test content to a file, reporting whether it changed; a file that already
+// holds the content is left alone, so repeated runs do not touch its modification time
+func (tg *TestGenerator) writeTestFile(testFilePath, content string) (bool, error) {
 	fullPath := filepath.Join(tg.options.ProjectPath, testFilePath)
 
 	// Create directory if it doesn't exist
 	dir := filepath.Dir(fullPath)
 	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("failed to create directory: %w", err)
+		return false, fmt.Errorf("failed to create directory: %w", err)
 	}
 
-	// Write the file
-	if err := os.WriteFile(fullPath, []byte(content), 0644); err != nil {
-		return fmt.Errorf("failed to write file: %w", err)
+	changed, err := writeFileIfChanged(fullPath, []byte(content), 0644, tg.backups)
+	if err != nil {
+		return false, fmt.Errorf("failed to write file: %w", err)
 	}
+	return changed, nil
+}
 
-	return nil
+// writeFileIfChanged writes content to a file unless the file's content already has the same hash,
+// and reports whether it wrote. The file is replaced atomically, and its previous content saved to
+// backups first when there is one
+func writeFileIfChanged(path string, content []byte, perm os.FileMode, backups *backup.Run) (bool, error) {
+	if existing, err := os.ReadFile(path); err == nil {
+		if sha256.Sum256(existing) == sha256.Sum256(content) {
+			return false, nil
+		}
+		if err := backups.Save(path, existing); err != nil {
+			return false, err
+		}
+	}
+	if err := fileutil.WriteAtomic(path, content, perm); err != nil {
+		return false, err
+	}
+	return true, nil
 }
 
 // estimateTestLines estimates the number of lines in a test
@@ -505,7 +963,7 @@ func (tg *TestGenerator) ValidateGeneratedTests(result *models.GenerationResult)
 	}
 
 	if tg.verbose {
-		fmt.Printf("🔍 Validating generated tests...\n")
+		i18n.Printf("🔍 Validating generated tests...\n")
 	}
 
 	// Simple validation: check if files can be parsed as Go code
@@ -525,7 +983,7 @@ func (tg *TestGenerator) ValidateGeneratedTests(result *models.GenerationResult)
 	}
 
 	if tg.verbose {
-		fmt.Printf("✅ All generated tests passed validation\n")
+		i18n.Printf("✅ All generated tests passed validation\n")
 	}
 
 	return nil
@@ -534,7 +992,7 @@ func (tg *TestGenerator) ValidateGeneratedTests(result *models.GenerationResult)
 // generateMocks generates mock files for interfaces
 func (tg *TestGenerator) generateMocks(analysisResult *models.AnalysisResult) error {
 	if tg.verbose {
-		fmt.Println("🎭 Generating mocks for interfaces...")
+		i18n.Println("🎭 Generating mocks for interfaces...")
 	}
 
 	mocks, err := tg.mockGenerator.GenerateMocks(analysisResult.UncoveredFunctions, tg.options.ProjectPath)
@@ -544,37 +1002,50 @@ func (tg *TestGenerator) generateMocks(analysisResult *models.AnalysisResult) er
 
 	if len(mocks) == 0 {
 		if tg.verbose {
-			fmt.Println("🎭 No interfaces found that require mocking")
+			i18n.Println("🎭 No interfaces found that require mocking")
 		}
 		return nil
 	}
 
 	// Write mock files
+	if tg.options.DryRun {
+		for _, mock := range mocks {
+			tg.previewFile(mock.FilePath, mock.Content)
+		}
+	}
 	if err := tg.mockGenerator.WriteMocks(mocks, tg.options.ProjectPath, tg.options.DryRun); err != nil {
 		return fmt.Errorf("failed to write mock files: %w", err)
 	}
+	if !tg.options.DryRun {
+		for _, mock := range mocks {
+			tg.recordArtifact(manifest.KindMock, mock.FilePath)
+		}
+	}
 
 	if tg.verbose {
-		fmt.Printf("🎭 Generated %d mock files\n", len(mocks))
+		i18n.Printf("🎭 Generated %d mock files\n", len(mocks))
 	}
 
 	return nil
 }
 
 // validateTests validates generated tests for quality and correctness
-func (tg *TestGenerator) validateTests(result *models.GenerationResult) error {
+func (tg *TestGenerator) validateTests(ctx context.Context, result *models.GenerationResult) error {
 	if tg.verbose {
-		fmt.Println("🔍 Validating generated tests...")
+		i18n.Println("🔍 Validating generated tests...")
 	}
 
-	validationResult, err := tg.validator.ValidateTests(result)
+	validationResult, err := tg.validator.ValidateTests(ctx, result)
 	if err != nil {
 		return fmt.Errorf("validation failed: %w", err)
 	}
+	if validationResult.TimedOut {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("Validation of generated tests timed out after %s", tg.options.CommandTimeout))
+	}
 
 	if tg.verbose {
 		if validationResult.Valid {
-			fmt.Printf("✅ All tests validated successfully\n")
+			i18n.Printf("✅ All tests validated successfully\n")
 			if validationResult.TestsRun > 0 {
 				fmt.Printf("   Tests: %d passed, %d failed\n", validationResult.TestsPassed, validationResult.TestsFailed)
 			}
@@ -582,7 +1053,7 @@ func (tg *TestGenerator) validateTests(result *models.GenerationResult) error {
 				fmt.Printf("   Coverage: %.1f%%\n", validationResult.CoverageImproved)
 			}
 		} else {
-			fmt.Printf("⚠️ Test validation completed with issues:\n")
+			i18n.Printf("⚠️ Test validation completed with issues:\n")
 			if len(validationResult.SyntaxErrors) > 0 {
 				fmt.Printf("   Syntax errors: %d\n", len(validationResult.SyntaxErrors))
 			}
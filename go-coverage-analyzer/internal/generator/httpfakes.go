@@ -0,0 +1,224 @@
+package generator
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/beck/go-coverage-analyzer/internal/i18n"
+	"github.com/beck/go-coverage-analyzer/internal/manifest"
+	"github.com/beck/go-coverage-analyzer/pkg/models"
+)
+
+// httpHelperFile holds the fake transport shared by HTTP tests in a package
+const httpHelperFile = "gcov_http_test.go"
+
+// qualifiedPackage matches package qualifiers like time in time.Time
+var qualifiedPackage = regexp.MustCompile(`\b([a-z][a-z0-9]*)\.[A-Z]`)
+
+// stdlibImports maps package names found in stubbed signatures to their import paths
+var stdlibImports = map[string]string{
+	"bytes":   "bytes",
+	"context": "context",
+	"http":    "net/http",
+	"io":      "io",
+	"json":    "encoding/json",
+	"os":      "os",
+	"sql":     "database/sql",
+	"time":    "time",
+	"url":     "net/url",
+}
+
+// isRoundTripperType reports whether a type is the http.RoundTripper interface
+func isRoundTripperType(t string) bool {
+	return t == "http.RoundTripper"
+}
+
+// receiverValue builds the receiver of a method, with stubs for its function-typed fields
+func receiverValue(function *models.Function) string {
+	return buildReceiver(function, nil)
+}
+
+// httpReceiverValue builds the receiver of a method with its HTTP client field set to the fake client
+func httpReceiverValue(function *models.Function) string {
+	if function.HTTP == nil || function.HTTP.Field == "" {
+		return buildReceiver(function, nil)
+	}
+	return buildReceiver(function, []string{function.HTTP.Field + ": client"})
+}
+
+// buildReceiver renders &T{...} with the given fields followed by function field stubs and fake clocks
+func buildReceiver(function *models.Function, fields []string) string {
+	for _, field := range function.FuncFields {
+		fields = append(fields, fmt.Sprintf("%s: %s", field.Name, funcStub(field.Type)))
+	}
+	for _, field := range function.ClockFields {
+		fields = append(fields, fmt.Sprintf("%s: %s", field.Name, fakeClockValue(field.Type)))
+	}
+	return fmt.Sprintf("&%s{%s}", strings.TrimPrefix(function.ReceiverType, "*"), strings.Join(fields, ", "))
+}
+
+// httpNeedsClient reports whether an HTTP test passes an *http.Client rather than only the transport
+func httpNeedsClient(function *models.Function) bool {
+	if function.HTTP == nil || function.HTTP.Default {
+		return false
+	}
+	if param := findParam(function, function.HTTP.Param); param != nil {
+		return !isRoundTripperType(param.Type)
+	}
+	return true
+}
+
+// httpArg is the argument an HTTP test passes for a parameter
+func httpArg(function *models.Function, param *models.Param) string {
+	if function.HTTP != nil && param.Name == function.HTTP.Param {
+		if isRoundTripperType(param.Type) {
+			return "transport"
+		}
+		return "client"
+	}
+	if isRoundTripperType(param.Type) {
+		return "&fakeRoundTripper{status: http.StatusOK}"
+	}
+	return paramValue(function, param, "positive") + spread(param.Type)
+}
+
+// usesFakeTransport reports whether tests of a function need the package's fake transport
+func usesFakeTransport(function *models.Function) bool {
+	if function.HTTP != nil {
+		return true
+	}
+	for _, param := range function.Parameters {
+		if isRoundTripperType(param.Type) {
+			return true
+		}
+	}
+	return false
+}
+
+// stubImports returns the standard packages the function stubs of a test refer to
+func stubImports(function *models.Function) []string {
+	var types []string
+	for _, field := range function.FuncFields {
+		types = append(types, field.Type)
+	}
+	for _, param := range function.Parameters {
+		if isFuncType(param.Type) {
+			types = append(types, param.Type)
+		}
+	}
+
+	var imports []string
+	for _, t := range types {
+		for _, match := range qualifiedPackage.FindAllStringSubmatch(t, -1) {
+			if path, ok := stdlibImports[match[1]]; ok {
+				imports = append(imports, path)
+			}
+		}
+	}
+	return imports
+}
+
+const httpTestTemplate = `{{.Comment}}func {{.TestName}}(t *testing.T) {
+	{{if .Function.Concurrent}}defer goleak.VerifyNone(t)
+
+	{{end}}{{if .Parallel}}t.Parallel()
+
+	{{end}}transport := &fakeRoundTripper{status: http.StatusOK, body: "{}"}
+	{{if .Function.HTTP.Default}}defer useDefaultTransport(transport)()
+	{{else if httpNeedsClient .Function}}client := &http.Client{Transport: transport}
+	{{end}}
+	{{if .Function.IsMethod}}receiver := {{httpReceiverValue .Function}}
+	{{end}}{{if .Function.ReturnTypes}}{{range $i, $returnType := .Function.ReturnTypes}}{{if $i}}, {{end}}{{if eq $returnType "error"}}callErr{{else}}_{{end}}{{end}} {{if .Function.HasErrorReturn}}:={{else}}={{end}} {{end}}{{if .Function.IsMethod}}receiver.{{end}}{{.Function.Name}}({{range $i, $param := .Function.Parameters}}{{if $i}}, {{end}}{{httpArg $.Function $param}}{{end}})
+	{{if .Function.HasErrorReturn}}if callErr != nil {
+		t.Errorf("{{.Function.Name}}() unexpected error: %v", callErr)
+	}
+	{{end}}
+	if len(transport.Requests()) == 0 {
+		t.Errorf("{{.Function.Name}}() sent no HTTP request")
+	}
+}`
+
+const httpHelperTemplate = `package {{.PackageName}}
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// fakeRoundTripper answers every request with a canned response and records the requests it saw
+type fakeRoundTripper struct {
+	mu       sync.Mutex
+	status   int
+	body     string
+	err      error
+	requests []*http.Request
+}
+
+// RoundTrip records the request and returns the canned response, or err when set
+func (f *fakeRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.requests = append(f.requests, req)
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &http.Response{
+		StatusCode: f.status,
+		Status:     http.StatusText(f.status),
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(f.body)),
+		Request:    req,
+	}, nil
+}
+
+// Requests returns the requests sent so far
+func (f *fakeRoundTripper) Requests() []*http.Request {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]*http.Request(nil), f.requests...)
+}
+
+// useDefaultTransport routes http.DefaultClient through transport until the returned func restores it
+func useDefaultTransport(transport http.RoundTripper) func() {
+	previous := http.DefaultTransport
+	http.DefaultTransport = transport
+	return func() { http.DefaultTransport = previous }
+}
+`
+
+// writeHTTPHelper adds the fake transport to a package unless it is already there
+func (tg *TestGenerator) writeHTTPHelper(dir, packageName string) error {
+	helperPath := filepath.Join(dir, httpHelperFile)
+	exists, err := tg.fileExists(helperPath)
+	if err != nil {
+		return fmt.Errorf("failed to check HTTP helper existence: %w", err)
+	}
+	if exists {
+		return nil
+	}
+
+	content, err := tg.templateEngine.GenerateFile("http_helper", &TemplateData{PackageName: packageName})
+	if err == nil {
+		content, err = tg.inTestPackage(dir, content)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to generate HTTP helper: %w", err)
+	}
+
+	if _, err := tg.writeTestFile(helperPath, content); err != nil {
+		return fmt.Errorf("failed to write HTTP helper: %w", err)
+	}
+
+	tg.recordArtifact(manifest.KindHelper, helperPath)
+
+	if tg.verbose {
+		i18n.Printf("🌐 Created fake HTTP transport: %s\n", helperPath)
+	}
+
+	return nil
+}
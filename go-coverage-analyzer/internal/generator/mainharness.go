@@ -0,0 +1,160 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/beck/go-coverage-analyzer/internal/i18n"
+	"github.com/beck/go-coverage-analyzer/internal/manifest"
+	"github.com/beck/go-coverage-analyzer/pkg/coverage"
+	"github.com/beck/go-coverage-analyzer/pkg/models"
+)
+
+// mainHarnessFile is the integration harness generated for each main package
+const mainHarnessFile = "gcov_main_harness_test.go"
+
+// mainHarnessTemplate runs the command of a main package in a subprocess: the test binary itself,
+// which TestMain turns into the command when GCOV_RUN_MAIN is set
+const mainHarnessTemplate = `package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"testing"
+	"time"
+)
+
+// runMainEnv tells the test binary to run main instead of the tests
+const runMainEnv = "GCOV_RUN_MAIN"
+
+// TestMain runs the command itself when a test starts the test binary with GCOV_RUN_MAIN set, so
+// runMain can exercise main with arguments in a subprocess
+func TestMain(m *testing.M) {
+	if os.Getenv(runMainEnv) == "1" {
+		main()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// runMain runs main in a subprocess with args and returns its combined output and exit code. A test
+// binary built with coverage leaves the counters of the subprocess in {{.CoverDirEnv}}, which gcov
+// merges into the profile, or in a temporary directory when it is not set
+func runMain(t *testing.T, args ...string) (string, int) {
+	t.Helper()
+
+	coverDir := os.Getenv("{{.CoverDirEnv}}")
+	if coverDir == "" {
+		coverDir = t.TempDir()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, os.Args[0], args...)
+	cmd.Env = append(os.Environ(), runMainEnv+"=1", "GOCOVERDIR="+coverDir)
+	output, err := cmd.CombinedOutput()
+	if ctx.Err() != nil {
+		t.Fatalf("main did not exit within 30s: %s", output)
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return string(output), exitErr.ExitCode()
+	}
+	if err != nil {
+		t.Fatalf("failed to run main: %v", err)
+	}
+	return string(output), 0
+}
+
+// TestMainCommand runs the command with each set of arguments and checks its exit code
+func TestMainCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		wantCode int
+	}{
+		// TODO: add the arguments the command is run with
+		{
+			name:     "help",
+			args:     []string{"-h"},
+			wantCode: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, code := runMain(t, tt.args...)
+			if code != tt.wantCode {
+				t.Errorf("main exited with %d, want %d\n%s", code, tt.wantCode, output)
+			}
+		})
+	}
+}
+`
+
+// writeMainHarnesses writes the integration harness of every main package that has no TestMain yet
+func (tg *TestGenerator) writeMainHarnesses(analysisResult *models.AnalysisResult, result *models.GenerationResult) {
+	dirs := make([]string, 0)
+	for _, pkg := range analysisResult.PackageCoverage {
+		if pkg.Name == "main" {
+			dirs = append(dirs, pkg.Path)
+		}
+	}
+	sort.Strings(dirs)
+
+	for _, dir := range dirs {
+		if reason := tg.mainHarnessConflict(dir); reason != "" {
+			if tg.verbose {
+				i18n.Printf("⚠️ Skipping the main harness for %s: %s\n", dir, reason)
+			}
+			continue
+		}
+
+		content, err := tg.templateEngine.GenerateFile("main_harness", &TemplateData{CoverDirEnv: coverage.SubprocessCoverDirEnv})
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("Failed to generate the main harness for %s: %v", dir, err))
+			continue
+		}
+
+		path := filepath.Join(dir, mainHarnessFile)
+		if _, err := tg.writeTestFile(path, content); err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("Failed to write the main harness for %s: %v", dir, err))
+			continue
+		}
+
+		tg.recordArtifact(manifest.KindHelper, path)
+
+		if tg.verbose {
+			i18n.Printf("🧰 Created main harness: %s\n", path)
+		}
+	}
+}
+
+// mainHarnessConflict returns why a main package cannot get the harness, or "" when it can
+func (tg *TestGenerator) mainHarnessConflict(dir string) string {
+	fullDir := filepath.Join(tg.options.ProjectPath, dir)
+	if _, err := os.Stat(filepath.Join(fullDir, mainHarnessFile)); err == nil {
+		return mainHarnessFile + " already exists"
+	}
+
+	// Only one TestMain per package, and the fixtures of a testmain suite may have claimed it
+	if tg.suites[dir] != nil && tg.options.SuiteStyle == SuiteStyleTestMain {
+		return "its fixtures use TestMain"
+	}
+	testFiles, _ := filepath.Glob(filepath.Join(fullDir, "*_test.go"))
+	for _, testFile := range testFiles {
+		content, err := os.ReadFile(testFile)
+		if err == nil && strings.Contains(string(content), "func TestMain(") {
+			return "package already has a TestMain in " + filepath.Base(testFile)
+		}
+	}
+
+	return ""
+}
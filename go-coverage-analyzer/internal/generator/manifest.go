@@ -0,0 +1,54 @@
+package generator
+
+import (
+	"time"
+
+	"github.com/beck/go-coverage-analyzer/internal/manifest"
+	"github.com/beck/go-coverage-analyzer/pkg/models"
+)
+
+// version returns the gcov version recorded in generated tests and the manifest
+func (tg *TestGenerator) version() string {
+	if tg.options.Version == "" {
+		return DefaultVersion
+	}
+	return tg.options.Version
+}
+
+// recordArtifact notes a support file written alongside the tests, for the manifest
+func (tg *TestGenerator) recordArtifact(kind, path string) {
+	tg.artifacts = append(tg.artifacts, &manifest.Entry{
+		Path:      path,
+		Kind:      kind,
+		Version:   tg.version(),
+		Generated: time.Now(),
+	})
+}
+
+// updateManifest records the tests and support files of a generation run in the project manifest
+func (tg *TestGenerator) updateManifest(result *models.GenerationResult) error {
+	generated, err := manifest.Load(tg.options.ProjectPath)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range result.GeneratedFiles {
+		for _, testCase := range file.TestCases {
+			generated.Record(&manifest.Entry{
+				Path:      file.Path,
+				Kind:      manifest.KindTest,
+				Test:      testCase.TestName,
+				TestType:  testCase.TestType,
+				Function:  testCase.Target,
+				Signature: testCase.Signature,
+				Version:   tg.version(),
+				Generated: result.Timestamp,
+			})
+		}
+	}
+	for _, artifact := range tg.artifacts {
+		generated.Record(artifact)
+	}
+
+	return generated.Save(tg.options.ProjectPath)
+}
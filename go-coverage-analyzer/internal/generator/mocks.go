@@ -10,20 +10,28 @@ import (
 	"strings"
 	"text/template"
 
+	"github.com/beck/go-coverage-analyzer/internal/backup"
+	"github.com/beck/go-coverage-analyzer/internal/i18n"
 	"github.com/beck/go-coverage-analyzer/pkg/models"
 )
 
 // MockGenerator handles the generation of mock interfaces for testing
 type MockGenerator struct {
-	fileSet *token.FileSet
-	verbose bool
+	fileSet   *token.FileSet
+	framework string      // MockFrameworkTestify or MockFrameworkFake
+	backups   *backup.Run // previous content of overwritten mock files, nil when not backing up
+	verbose   bool
 }
 
-// NewMockGenerator creates a new mock generator
-func NewMockGenerator(verbose bool) *MockGenerator {
+// NewMockGenerator creates a new mock generator for the given framework, testify when empty
+func NewMockGenerator(framework string, verbose bool) *MockGenerator {
+	if framework == "" {
+		framework = MockFrameworkTestify
+	}
 	return &MockGenerator{
-		fileSet: token.NewFileSet(),
-		verbose: verbose,
+		fileSet:   token.NewFileSet(),
+		framework: framework,
+		verbose:   verbose,
 	}
 }
 
@@ -69,7 +77,7 @@ type GeneratedMock struct {
 // GenerateMocks generates mock implementations for interfaces used by functions
 func (mg *MockGenerator) GenerateMocks(functions []*models.Function, projectPath string) ([]*GeneratedMock, error) {
 	if mg.verbose {
-		fmt.Println("🎭 Starting mock generation...")
+		i18n.Println("🎭 Starting mock generation...")
 	}
 
 	// Find all interfaces that need mocking
@@ -80,7 +88,7 @@ func (mg *MockGenerator) GenerateMocks(functions []*models.Function, projectPath
 
 	if len(interfaces) == 0 {
 		if mg.verbose {
-			fmt.Println("🎭 No interfaces found that need mocking")
+			i18n.Println("🎭 No interfaces found that need mocking")
 		}
 		return []*GeneratedMock{}, nil
 	}
@@ -89,13 +97,18 @@ func (mg *MockGenerator) GenerateMocks(functions []*models.Function, projectPath
 
 	for _, iface := range interfaces {
 		if mg.verbose {
-			fmt.Printf("🎭 Generating mock for interface: %s\n", iface.Name)
+			i18n.Printf("🎭 Generating mock for interface: %s\n", iface.Name)
+		}
+
+		generate := mg.generateMockFile
+		if mg.framework == MockFrameworkFake {
+			generate = mg.generateFakeFile
 		}
 
-		mock, err := mg.generateMockFile(iface, projectPath)
+		mock, err := generate(iface, projectPath)
 		if err != nil {
 			if mg.verbose {
-				fmt.Printf("⚠️ Failed to generate mock for %s: %v\n", iface.Name, err)
+				i18n.Printf("⚠️ Failed to generate mock for %s: %v\n", iface.Name, err)
 			}
 			continue
 		}
@@ -104,7 +117,7 @@ func (mg *MockGenerator) GenerateMocks(functions []*models.Function, projectPath
 	}
 
 	if mg.verbose {
-		fmt.Printf("🎭 Generated %d mocks\n", len(generatedMocks))
+		i18n.Printf("🎭 Generated %d mocks\n", len(generatedMocks))
 	}
 
 	return generatedMocks, nil
@@ -121,7 +134,7 @@ func (mg *MockGenerator) findInterfacesToMock(functions []*models.Function, proj
 				iface, err := mg.parseInterface(param.Type, function.File, projectPath)
 				if err != nil {
 					if mg.verbose {
-						fmt.Printf("⚠️ Could not parse interface %s: %v\n", param.Type, err)
+						i18n.Printf("⚠️ Could not parse interface %s: %v\n", param.Type, err)
 					}
 					continue
 				}
@@ -378,6 +391,8 @@ func (mg *MockGenerator) typeToString(expr ast.Expr) string {
 		return "*" + mg.typeToString(t.X)
 	case *ast.ArrayType:
 		return "[]" + mg.typeToString(t.Elt)
+	case *ast.Ellipsis:
+		return "..." + mg.typeToString(t.Elt)
 	case *ast.MapType:
 		return "map[" + mg.typeToString(t.Key) + "]" + mg.typeToString(t.Value)
 	case *ast.SelectorExpr:
@@ -471,37 +486,7 @@ func (mg *MockGenerator) buildReturnCall(returns []*MockReturn) string {
 // generateMockFile generates the complete mock file content
 func (mg *MockGenerator) generateMockFile(iface *MockInterface, projectPath string) (*GeneratedMock, error) {
 	// Collect all necessary imports
-	imports := []string{"github.com/stretchr/testify/mock"}
-
-	// Check if we need context import
-	needsContext := false
-	needsIO := false
-
-	for _, method := range iface.Methods {
-		for _, param := range method.Parameters {
-			if strings.Contains(param.Type, "context.Context") {
-				needsContext = true
-			}
-			if strings.Contains(param.Type, "io.") {
-				needsIO = true
-			}
-		}
-		for _, ret := range method.Returns {
-			if strings.Contains(ret.Type, "context.Context") {
-				needsContext = true
-			}
-			if strings.Contains(ret.Type, "io.") {
-				needsIO = true
-			}
-		}
-	}
-
-	if needsContext {
-		imports = append(imports, "context")
-	}
-	if needsIO {
-		imports = append(imports, "io")
-	}
+	imports := append([]string{"github.com/stretchr/testify/mock"}, mg.signatureImports(iface)...)
 
 	// Define the template
 	mockTemplate := `package {{.Package}}
@@ -549,9 +534,8 @@ func (m *Mock{{$.Name}}) {{.Name}}{{.Signature}} {
 	mockFilePath := filepath.Join(filepath.Dir(iface.FilePath), mockFileName)
 
 	// Make path relative to project
-	if strings.HasPrefix(mockFilePath, projectPath) {
-		mockFilePath = strings.TrimPrefix(mockFilePath, projectPath)
-		mockFilePath = strings.TrimPrefix(mockFilePath, "/")
+	if relPath, err := filepath.Rel(projectPath, mockFilePath); err == nil && !strings.HasPrefix(relPath, "..") {
+		mockFilePath = relPath
 	}
 
 	return &GeneratedMock{
@@ -569,18 +553,20 @@ func (mg *MockGenerator) extractInterfaceName(interfaceType string) string {
 	return parts[len(parts)-1]
 }
 
-// getImportPath determines the import path for an interface file
+// getImportPath determines the import path for an interface file, slash separated on every platform
 func (mg *MockGenerator) getImportPath(filePath, projectPath string) string {
-	relativePath := strings.TrimPrefix(filePath, projectPath)
-	relativePath = strings.TrimPrefix(relativePath, "/")
-	return filepath.Dir(relativePath)
+	relativePath, err := filepath.Rel(projectPath, filePath)
+	if err != nil {
+		relativePath = filePath
+	}
+	return filepath.ToSlash(filepath.Dir(relativePath))
 }
 
 // WriteMocks writes generated mocks to files
 func (mg *MockGenerator) WriteMocks(mocks []*GeneratedMock, projectPath string, dryRun bool) error {
 	if dryRun {
 		if mg.verbose {
-			fmt.Printf("🎭 [DRY RUN] Would write %d mock files\n", len(mocks))
+			i18n.Printf("🎭 [DRY RUN] Would write %d mock files\n", len(mocks))
 		}
 		return nil
 	}
@@ -595,12 +581,15 @@ func (mg *MockGenerator) WriteMocks(mocks []*GeneratedMock, projectPath string,
 		}
 
 		// Write mock file
-		if err := os.WriteFile(fullPath, []byte(mock.Content), 0644); err != nil {
+		changed, err := writeFileIfChanged(fullPath, []byte(mock.Content), 0644, mg.backups)
+		if err != nil {
 			return fmt.Errorf("failed to write mock file %s: %w", fullPath, err)
 		}
 
-		if mg.verbose {
-			fmt.Printf("🎭 Generated mock file: %s\n", mock.FilePath)
+		if mg.verbose && !changed {
+			i18n.Printf("🎭 Mock file unchanged: %s\n", mock.FilePath)
+		} else if mg.verbose {
+			i18n.Printf("🎭 Generated mock file: %s\n", mock.FilePath)
 		}
 	}
 
@@ -0,0 +1,187 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"go/token"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/beck/go-coverage-analyzer/internal/i18n"
+	"github.com/beck/go-coverage-analyzer/pkg/models"
+)
+
+// DefaultTestNamePattern names tests TestFuncName, and variants like TestFuncNameConcurrent
+const DefaultTestNamePattern = "Test{{.Function}}{{.Case}}"
+
+// StressTestVariant is the test name variant of concurrent stress tests
+const StressTestVariant = "Concurrent"
+
+// TestNameData is what a test name pattern can refer to
+type TestNameData struct {
+	Function string // function or method name
+	Receiver string // receiver type without the pointer, empty for functions
+	Case     string // test variant such as Concurrent, empty for the main test
+}
+
+// TestNamer names generated test functions after a configurable pattern
+type TestNamer struct {
+	pattern *template.Template
+}
+
+// NewTestNamer parses a test name pattern, the default one when empty
+func NewTestNamer(pattern string) (*TestNamer, error) {
+	if pattern == "" {
+		pattern = DefaultTestNamePattern
+	}
+
+	tmpl, err := template.New("test_name").Parse(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse test name pattern: %w", err)
+	}
+	namer := &TestNamer{pattern: tmpl}
+
+	// go test only runs functions named Test followed by a non-lowercase character
+	for _, sample := range []TestNameData{{Function: "Parse"}, {Function: "Parse", Receiver: "Config", Case: "Concurrent"}} {
+		name, err := namer.render(sample)
+		if err != nil {
+			return nil, err
+		}
+		if !token.IsIdentifier(name) || !isTestName(name) {
+			return nil, fmt.Errorf("test name pattern %q yields %q, which go test does not run", pattern, name)
+		}
+	}
+
+	return namer, nil
+}
+
+// Name returns the name of a test of the function, with variant empty for the main test
+func (n *TestNamer) Name(function *models.Function, variant string) string {
+	return n.NameAs(function, function.Name, variant)
+}
+
+// NameAs returns the name of a test of the function as if it was named name
+func (n *TestNamer) NameAs(function *models.Function, name, variant string) string {
+	testName, err := n.render(TestNameData{
+		Function: name,
+		Receiver: strings.TrimPrefix(function.ReceiverType, "*"),
+		Case:     variant,
+	})
+	if err != nil {
+		return "Test" + name + variant
+	}
+	return testName
+}
+
+// render executes the pattern
+func (n *TestNamer) render(data TestNameData) (string, error) {
+	var buf bytes.Buffer
+	if err := n.pattern.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to execute test name pattern: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// testNameVariants are the variants a function may get tests of, the main test first
+var testNameVariants = []string{"", PanicTestVariant, StressTestVariant}
+
+// planTestNames names the tests of the functions to generate so that none clashes with another test of
+// its package, generated or in a test file left in place. Methods whose tests would clash are named
+// after their receiver type too, e.g. TestMockCache_Delete, and clashes left after that get a number
+func (tg *TestGenerator) planTestNames(fileGroups map[string][]*models.Function) {
+	sourceFiles := make([]string, 0, len(fileGroups))
+	for sourceFile := range fileGroups {
+		sourceFiles = append(sourceFiles, sourceFile)
+	}
+	sort.Strings(sourceFiles)
+
+	// Test files left in place are the ones no source file gets tests in, and existing ones that are
+	// skipped for not being overwritten
+	packages := make(map[string][]*models.Function)
+	rewritten := make(map[string]bool)
+	for _, sourceFile := range sourceFiles {
+		testFilePath := tg.getTestFilePath(sourceFile)
+		if exists, err := tg.fileExists(testFilePath); err != nil || (exists && !tg.options.Overwrite) {
+			continue
+		}
+		rewritten[testFilePath] = true
+		dir := filepath.Dir(sourceFile)
+		packages[dir] = append(packages[dir], fileGroups[sourceFile]...)
+	}
+
+	for dir, functions := range packages {
+		taken := tg.existingTestNames(dir, rewritten)
+
+		claims := make(map[string]int)
+		for _, function := range functions {
+			for _, variant := range testNameVariants {
+				claims[tg.templateEngine.namer.Name(function, variant)]++
+			}
+		}
+
+		for _, function := range functions {
+			name := function.Name
+			if function.ReceiverType != "" && tg.testNamesClash(function, name, taken, claims) {
+				name = strings.TrimPrefix(function.ReceiverType, "*") + "_" + function.Name
+			}
+			for base, i := name, 2; tg.testNamesClash(function, name, taken, nil); i++ {
+				name = fmt.Sprintf("%s%d", base, i)
+			}
+
+			if name != function.Name {
+				tg.templateEngine.SetTestFunctionName(function, name)
+				if tg.verbose {
+					i18n.Printf("🏷️ Naming the test of %s %s, as other tests of its package take its name\n",
+						function.Key(), tg.templateEngine.TestName(function, ""))
+				}
+			}
+			for _, variant := range testNameVariants {
+				taken[tg.templateEngine.TestName(function, variant)] = true
+			}
+		}
+	}
+}
+
+// testNamesClash reports whether a test of the function named after name would take a name taken
+// already, or one claimed by more than the function itself
+func (tg *TestGenerator) testNamesClash(function *models.Function, name string, taken map[string]bool, claims map[string]int) bool {
+	for _, variant := range testNameVariants {
+		testName := tg.templateEngine.namer.NameAs(function, name, variant)
+		if taken[testName] || claims[testName] > 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// existingTestNames returns the names of the tests in the test files of a directory, other than the
+// ones about to be rewritten
+func (tg *TestGenerator) existingTestNames(dir string, rewritten map[string]bool) map[string]bool {
+	names := make(map[string]bool)
+	paths, _ := filepath.Glob(filepath.Join(tg.options.ProjectPath, dir, "*_test.go"))
+	for _, path := range paths {
+		testFilePath := filepath.Join(dir, filepath.Base(path))
+		if rewritten[testFilePath] {
+			continue
+		}
+		tests, err := tg.parseExistingTests(testFilePath)
+		if err != nil && tg.verbose {
+			i18n.Printf("⚠️ Failed to parse existing tests in %s: %v\n", testFilePath, err)
+		}
+		for name := range tests {
+			names[name] = true
+		}
+	}
+	return names
+}
+
+// isTestName reports whether go test treats a function name as a test
+func isTestName(name string) bool {
+	if !strings.HasPrefix(name, "Test") {
+		return false
+	}
+	rest := strings.TrimPrefix(name, "Test")
+	return rest == "" || rest[0] < 'a' || rest[0] > 'z'
+}
@@ -0,0 +1,65 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/beck/go-coverage-analyzer/pkg/models"
+)
+
+func TestPlanTestNames(t *testing.T) {
+	root := t.TempDir()
+	// A test file no source file gets tests in keeps its tests
+	if err := os.WriteFile(filepath.Join(root, "store_test.go"), []byte("package app\n\nimport \"testing\"\n\nfunc TestOpen(t *testing.T) {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cacheDelete := &models.Function{Name: "Delete", ReceiverType: "*Cache", File: "cache.go", Package: "app"}
+	clientDelete := &models.Function{Name: "Delete", ReceiverType: "*Client", File: "client.go", Package: "app"}
+	clientOpen := &models.Function{Name: "Open", ReceiverType: "*Client", File: "client.go", Package: "app"}
+	parse := &models.Function{Name: "Parse", File: "client.go", Package: "app"}
+	fileGroups := map[string][]*models.Function{
+		"cache.go":  {cacheDelete},
+		"client.go": {clientDelete, clientOpen, parse},
+	}
+
+	tests := []struct {
+		pattern string
+		want    map[*models.Function]string
+	}{
+		{DefaultTestNamePattern, map[*models.Function]string{
+			cacheDelete:  "TestCache_Delete",
+			clientDelete: "TestClient_Delete",
+			clientOpen:   "TestClient_Open",
+			parse:        "TestParse",
+		}},
+		{"Test{{.Receiver}}{{.Function}}{{.Case}}", map[*models.Function]string{
+			cacheDelete:  "TestCacheDelete",
+			clientDelete: "TestClientDelete",
+			clientOpen:   "TestClientOpen",
+			parse:        "TestParse",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			tg := NewTestGenerator(&Options{ProjectPath: root})
+			namer, err := NewTestNamer(tt.pattern)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tg.templateEngine.SetTestNamer(namer)
+
+			tg.planTestNames(fileGroups)
+			for function, want := range tt.want {
+				if got := tg.templateEngine.TestName(function, ""); got != want {
+					t.Errorf("TestName(%s) = %q, want %q", function.Key(), got, want)
+				}
+			}
+			if got, want := tg.templateEngine.TestName(cacheDelete, StressTestVariant), tt.want[cacheDelete]+StressTestVariant; got != want {
+				t.Errorf("TestName(%s, %s) = %q, want %q", cacheDelete.Key(), StressTestVariant, got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,216 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/beck/go-coverage-analyzer/pkg/models"
+)
+
+// PanicTestVariant is the test name variant of tests checking the inputs a function panics on
+const PanicTestVariant = "Panics"
+
+// panicPathInputs returns the inputs that take a panic path, or false when inputs alone cannot reach it
+func panicPathInputs(function *models.Function, path *models.PanicPath) ([]InputData, bool) {
+	var param *models.Param
+	var value string
+
+	switch {
+	case path.Guard != nil:
+		param = findParam(function, path.Guard.Param)
+		if param == nil {
+			return nil, false
+		}
+		found := false
+		for _, candidate := range boundaryValues(path.Guard, param.Type) {
+			if candidate.satisfies {
+				value, found = candidate.value, true
+				break
+			}
+		}
+		if !found {
+			return nil, false
+		}
+	case path.Condition != "":
+		return nil, false
+	}
+
+	inputs := make([]InputData, 0, len(function.Parameters))
+	for _, p := range function.Parameters {
+		input := InputData{Name: p.Name, Type: p.Type, Value: value}
+		if param == nil || p.Name != param.Name {
+			// Error returns before the panic would keep it from happening
+			input.Value = avoidErrorPaths(function, p, paramValue(function, p, "positive"), nil)
+		}
+		inputs = append(inputs, input)
+	}
+	return inputs, true
+}
+
+// generatePanicCases creates a case for each panic path the inputs can reach
+func (te *TemplateEngine) generatePanicCases(function *models.Function) []TestCaseData {
+	covered := make(map[string]bool)
+
+	var testCases []TestCaseData
+	for _, path := range function.PanicPaths {
+		inputs, ok := panicPathInputs(function, path)
+		if !ok {
+			continue
+		}
+
+		key := inputsKey(inputs)
+		if covered[key] {
+			continue
+		}
+		covered[key] = true
+
+		description := "Test the unconditional panic"
+		if path.Condition != "" {
+			description = fmt.Sprintf("Test the panic when %s", path.Condition)
+		}
+		testCases = append(testCases, TestCaseData{
+			Name:        fmt.Sprintf("%s_line_%d", path.Kind, path.Line),
+			Description: description,
+			Inputs:      inputs,
+		})
+	}
+
+	return testCases
+}
+
+// withoutPanicCases drops the cases whose inputs make the function panic, which the panic test checks instead
+func (te *TemplateEngine) withoutPanicCases(function *models.Function, testCases []TestCaseData) []TestCaseData {
+	if !function.CanPanic {
+		return testCases
+	}
+
+	kept := testCases[:0]
+	for _, testCase := range testCases {
+		panics := false
+		for _, path := range function.PanicPaths {
+			panics = panics || takesPanicPath(path, testCase.Inputs)
+		}
+		if !panics {
+			kept = append(kept, testCase)
+		}
+	}
+	return kept
+}
+
+// takesPanicPath reports whether inputs meet the condition of a panic path; nil, "" and inputs
+// left out of a case, which stay at their zero value, have length zero
+func takesPanicPath(path *models.PanicPath, inputs []InputData) bool {
+	if path.Guard == nil {
+		return path.Condition == ""
+	}
+
+	var input *InputData
+	for i := range inputs {
+		if inputs[i].Name == path.Guard.Param {
+			input = &inputs[i]
+		}
+	}
+
+	if input == nil || input.Value == "nil" || input.Value == `""` {
+		switch {
+		case path.Guard.Length:
+			n, err := strconv.ParseInt(path.Guard.Value, 0, 64)
+			return err == nil && compareInts(0, path.Guard.Op, n)
+		case path.Guard.Value == "nil":
+			return path.Guard.Op == "=="
+		case input == nil:
+			return false
+		}
+	}
+
+	for _, candidate := range boundaryValues(path.Guard, input.Type) {
+		if candidate.value == input.Value {
+			return candidate.satisfies
+		}
+	}
+	return false
+}
+
+// HasPanicTest reports whether a panic test is generated for the function; database, HTTP and
+// fixture-backed functions are left out as their dependencies are set up by the main test only
+func (te *TemplateEngine) HasPanicTest(function *models.Function) bool {
+	if !function.CanPanic || function.SQL != nil || function.HTTP != nil || len(function.Fixtures) > 0 {
+		return false
+	}
+	return len(te.generatePanicCases(function)) > 0
+}
+
+// GeneratePanicTest renders a test calling the function with each input it panics on,
+// asserting the panic with require.Panics in testify style and recover otherwise
+func (te *TemplateEngine) GeneratePanicTest(function *models.Function, style string) (string, error) {
+	tmpl, exists := te.templates["panic_test"]
+	if !exists {
+		return "", fmt.Errorf("template not found: panic_test")
+	}
+
+	testName := te.TestName(function, PanicTestVariant)
+	data := &TemplateData{
+		PackageName:    function.Package,
+		Function:       function,
+		TestName:       testName,
+		AssertionStyle: style,
+		FileName:       filepath.Base(function.File),
+		Comment:        fmt.Sprintf("// %s checks the inputs %s panics on\n%s\n", testName, function.Name, te.generatedMarker(function)),
+		TestCases:      te.generatePanicCases(function),
+		Parallel:       te.parallel && canRunParallel(function),
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to execute template: %w", err)
+	}
+
+	return buf.String(), nil
+}
+
+// panicCall renders a call to the function discarding its results, as panic assertions only need it to run
+func panicCall(function *models.Function, inputs []InputData) string {
+	var call strings.Builder
+	if len(function.ReturnTypes) > 0 {
+		call.WriteString(strings.TrimSuffix(strings.Repeat("_, ", len(function.ReturnTypes)), ", "))
+		call.WriteString(" = ")
+	}
+	if function.IsMethod {
+		call.WriteString("receiver.")
+	}
+	call.WriteString(function.Name)
+	call.WriteString("(")
+	for i, input := range inputs {
+		if i > 0 {
+			call.WriteString(", ")
+		}
+		call.WriteString(input.Value)
+		call.WriteString(spread(input.Type))
+	}
+	call.WriteString(")")
+	return call.String()
+}
+
+const panicTestTemplate = `{{.Comment}}func {{.TestName}}(t *testing.T) {
+	{{if .Function.Concurrent}}defer goleak.VerifyNone(t)
+
+	{{end}}{{if .Parallel}}t.Parallel()
+
+	{{end}}{{range $case := .TestCases}}t.Run("{{$case.Name}}", func(t *testing.T) {
+		{{if $.Parallel}}t.Parallel()
+
+		{{end}}{{if $.Function.IsMethod}}receiver := {{receiverValue $.Function}}
+		{{end}}{{if eq $.AssertionStyle "testify"}}require.Panics(t, func() {
+			{{panicCall $.Function $case.Inputs}}
+		}){{else}}defer func() {
+			if r := recover(); r == nil {
+				t.Errorf("{{$.Function.Name}}() did not panic")
+			}
+		}()
+		{{panicCall $.Function $case.Inputs}}{{end}}
+	})
+	{{end}}
+}`
@@ -0,0 +1,422 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/beck/go-coverage-analyzer/internal/fileutil"
+	"github.com/beck/go-coverage-analyzer/internal/i18n"
+	"github.com/beck/go-coverage-analyzer/internal/manifest"
+	"github.com/beck/go-coverage-analyzer/pkg/models"
+)
+
+// GeneratedMarker starts the comment directive recording what a generated test was written for, e.g.
+// //gcov:generated version=0.1.0 function=Config.Load signature="func (*Config) Load(string) error"
+const GeneratedMarker = "//gcov:generated "
+
+// DefaultVersion is recorded in generated tests when no gcov version is set
+const DefaultVersion = "dev"
+
+// majorVersion matches the /vN element closing versioned import paths
+var majorVersion = regexp.MustCompile(`^v[0-9]+$`)
+
+// Marker holds the fields of the directive of a generated test
+type Marker struct {
+	Version   string // gcov version that wrote the test
+	Function  string // Receiver.Name or Name of the tested function
+	Signature string // signature of the tested function when the test was written
+}
+
+// generatedMarker renders the directive marking a generated test of the function
+func (te *TemplateEngine) generatedMarker(function *models.Function) string {
+	return fmt.Sprintf("%sversion=%s function=%s signature=%q", GeneratedMarker, te.version, function.Key(), function.TypeSignature())
+}
+
+// ParseMarker reads the directive of a generated test from a comment, or returns false for other comments
+func ParseMarker(comment string) (*Marker, bool) {
+	rest, ok := strings.CutPrefix(comment, GeneratedMarker)
+	if !ok {
+		return nil, false
+	}
+
+	marker := &Marker{}
+	for rest = strings.TrimSpace(rest); rest != ""; rest = strings.TrimSpace(rest) {
+		key, value, ok := strings.Cut(rest, "=")
+		if !ok {
+			return nil, false
+		}
+		if strings.HasPrefix(value, "\"") {
+			quoted, err := strconv.QuotedPrefix(value)
+			if err != nil {
+				return nil, false
+			}
+			rest = value[len(quoted):]
+			value, _ = strconv.Unquote(quoted)
+		} else {
+			value, rest, _ = strings.Cut(value, " ")
+		}
+
+		switch key {
+		case "version":
+			marker.Version = value
+		case "function":
+			marker.Function = value
+		case "signature":
+			marker.Signature = value
+		}
+	}
+
+	if marker.Function == "" || marker.Signature == "" {
+		return nil, false
+	}
+	return marker, true
+}
+
+// PruneOptions configures the search for stale generated tests
+type PruneOptions struct {
+	ProjectPath string
+	ExcludeDirs []string
+	Delete      bool // remove stale tests instead of only reporting them
+	Verbose     bool
+}
+
+// StaleTest is a generated test whose target function no longer exists or has a different signature,
+// or, when the analysis attributed coverage to tests, no longer runs its target
+type StaleTest struct {
+	File      string
+	Line      int
+	TestName  string
+	Target    string // signature recorded in the marker
+	Current   string // current signature of the target, empty when it no longer exists
+	Uncovered bool   // the target is unchanged but the test, run on its own, never reaches it
+}
+
+// Reason describes why the test is stale
+func (s *StaleTest) Reason() string {
+	switch {
+	case s.Uncovered:
+		return "no longer covers its target"
+	case s.Current == "":
+		return "target no longer exists"
+	}
+	return "signature changed to " + s.Current
+}
+
+// PruneResult lists the stale generated tests found and what was deleted
+type PruneResult struct {
+	Scanned      int // generated tests found
+	Stale        []*StaleTest
+	Deleted      int
+	DeletedFiles []string // test files left without tests and removed
+}
+
+// Prune finds generated tests whose targets are gone or changed signature, and deletes them when asked
+func Prune(analysisResult *models.AnalysisResult, opts *PruneOptions) (*PruneResult, error) {
+	targets := pruneTargets(analysisResult)
+	attributions := pruneAttributions(analysisResult)
+	result := &PruneResult{}
+
+	excludeMap := make(map[string]bool)
+	for _, dir := range opts.ExcludeDirs {
+		excludeMap[dir] = true
+	}
+
+	err := filepath.Walk(opts.ProjectPath, func(filePath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			// Copies of tests in .gcov/backups are not the project's tests
+			if excludeMap[info.Name()] || filePath != opts.ProjectPath && strings.HasPrefix(info.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(filePath, "_test.go") {
+			return nil
+		}
+		relPath, err := filepath.Rel(opts.ProjectPath, filePath)
+		if err != nil {
+			return err
+		}
+		dir := filepath.Dir(relPath)
+		return pruneFile(filePath, targets[dir], attributions[dir], opts, result)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan test files: %w", err)
+	}
+
+	if opts.Delete && result.Deleted > 0 {
+		if err := pruneManifest(opts.ProjectPath, result); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// pruneManifest drops the deleted tests and files from the manifest of the project, if it has one
+func pruneManifest(projectPath string, result *PruneResult) error {
+	generated, err := manifest.Load(projectPath)
+	if err != nil {
+		return err
+	}
+	if len(generated.Artifacts) == 0 {
+		return nil
+	}
+
+	for _, stale := range result.Stale {
+		if relPath, err := filepath.Rel(projectPath, stale.File); err == nil {
+			generated.RemoveTest(relPath, stale.TestName)
+		}
+	}
+	for _, file := range result.DeletedFiles {
+		if relPath, err := filepath.Rel(projectPath, file); err == nil {
+			generated.RemoveFile(relPath)
+		}
+	}
+
+	return generated.Save(projectPath)
+}
+
+// pruneTargets indexes functions by directory relative to the project, then by Key
+func pruneTargets(analysisResult *models.AnalysisResult) map[string]map[string]*models.Function {
+	targets := make(map[string]map[string]*models.Function)
+	for _, pkg := range analysisResult.PackageCoverage {
+		for _, file := range pkg.Files {
+			for _, function := range file.Functions {
+				dir := filepath.Clean(filepath.Dir(function.File))
+				if targets[dir] == nil {
+					targets[dir] = make(map[string]*models.Function)
+				}
+				targets[dir][function.Key()] = function
+			}
+		}
+	}
+	return targets
+}
+
+// pruneAttributions indexes the test matrix of the analysis, if it has one, by directory relative to
+// the project, then by test name
+func pruneAttributions(analysisResult *models.AnalysisResult) map[string]map[string]*models.TestAttribution {
+	attributions := make(map[string]map[string]*models.TestAttribution)
+	for _, attribution := range analysisResult.TestMatrix {
+		dir := filepath.Clean(filepath.Dir(attribution.File))
+		if attributions[dir] == nil {
+			attributions[dir] = make(map[string]*models.TestAttribution)
+		}
+		attributions[dir][attribution.Test] = attribution
+	}
+	return attributions
+}
+
+// testMarker returns the marker of a test, or false for tests gcov did not write
+func testMarker(decl *ast.FuncDecl) (*Marker, bool) {
+	if decl.Doc == nil {
+		return nil, false
+	}
+	for _, comment := range decl.Doc.List {
+		if marker, ok := ParseMarker(comment.Text); ok {
+			return marker, true
+		}
+	}
+	return nil, false
+}
+
+// pruneFile checks the generated tests of a file against the functions of its directory, and against
+// what each test covered when run on its own if the analysis attributed coverage to tests
+func pruneFile(filePath string, targets map[string]*models.Function, attributions map[string]*models.TestAttribution, opts *PruneOptions, result *PruneResult) error {
+	src, err := os.ReadFile(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", filePath, err)
+	}
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filePath, src, parser.ParseComments)
+	if err != nil {
+		// Files that do not parse are left to the compiler to report
+		if opts.Verbose {
+			i18n.Printf("⚠️ Skipping %s: %v\n", filePath, err)
+		}
+		return nil
+	}
+
+	stale := make(map[*ast.FuncDecl]bool)
+	for _, decl := range file.Decls {
+		funcDecl, ok := decl.(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+		marker, ok := testMarker(funcDecl)
+		if !ok {
+			continue
+		}
+		result.Scanned++
+
+		current, uncovered := "", false
+		if target := targets[marker.Function]; target != nil {
+			current = target.TypeSignature()
+			if current == marker.Signature {
+				// A test that passed and covers other functions has drifted away from its target; one
+				// that covers nothing, such as a skipped placeholder, is left alone
+				attribution := attributions[funcDecl.Name.Name]
+				if attribution == nil || !attribution.Passed || len(attribution.Functions) == 0 ||
+					slices.Contains(attribution.Functions, marker.Function) {
+					continue
+				}
+				uncovered = true
+			}
+		}
+
+		stale[funcDecl] = true
+		result.Stale = append(result.Stale, &StaleTest{
+			File:      filePath,
+			Line:      fset.Position(funcDecl.Pos()).Line,
+			TestName:  funcDecl.Name.Name,
+			Target:    marker.Signature,
+			Current:   current,
+			Uncovered: uncovered,
+		})
+	}
+
+	if len(stale) == 0 || !opts.Delete {
+		return nil
+	}
+
+	removed, err := removeTests(filePath, fset, file, stale)
+	if err != nil {
+		return err
+	}
+	result.Deleted += len(stale)
+	if removed {
+		result.DeletedFiles = append(result.DeletedFiles, filePath)
+	}
+	return nil
+}
+
+// removeTests rewrites a test file without the given tests and the imports only they used,
+// removing the file when no declarations are left; it reports whether the file was removed
+func removeTests(filePath string, fset *token.FileSet, file *ast.File, tests map[*ast.FuncDecl]bool) (bool, error) {
+	var decls []ast.Decl
+	var removed []ast.Node
+	for _, decl := range file.Decls {
+		if funcDecl, ok := decl.(*ast.FuncDecl); ok && tests[funcDecl] {
+			removed = append(removed, funcDecl)
+			continue
+		}
+		decls = append(decls, decl)
+	}
+	file.Decls = decls
+
+	// Doc comments and comments inside the tests go with them
+	var comments []*ast.CommentGroup
+	for _, group := range file.Comments {
+		inside := false
+		for _, node := range removed {
+			start := node.Pos()
+			if doc := node.(*ast.FuncDecl).Doc; doc != nil {
+				start = doc.Pos()
+			}
+			if start <= group.Pos() && group.End() <= node.End() {
+				inside = true
+				break
+			}
+		}
+		if !inside {
+			comments = append(comments, group)
+		}
+	}
+	file.Comments = comments
+
+	removeUnusedImports(file)
+
+	if len(file.Decls) == 0 {
+		if err := os.Remove(filePath); err != nil {
+			return false, fmt.Errorf("failed to remove %s: %w", filePath, err)
+		}
+		return true, nil
+	}
+
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, file); err != nil {
+		return false, fmt.Errorf("failed to format %s: %w", filePath, err)
+	}
+	if err := fileutil.WriteAtomic(filePath, buf.Bytes(), 0644); err != nil {
+		return false, fmt.Errorf("failed to write %s: %w", filePath, err)
+	}
+	return false, nil
+}
+
+// removeUnusedImports drops imports no selector in the file refers to, and import declarations left empty
+func removeUnusedImports(file *ast.File) {
+	used := make(map[string]bool)
+	ast.Inspect(file, func(n ast.Node) bool {
+		if selector, ok := n.(*ast.SelectorExpr); ok {
+			if ident, ok := selector.X.(*ast.Ident); ok {
+				used[ident.Name] = true
+			}
+		}
+		return true
+	})
+
+	var decls []ast.Decl
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.IMPORT {
+			decls = append(decls, decl)
+			continue
+		}
+
+		var specs []ast.Spec
+		for _, spec := range genDecl.Specs {
+			importSpec := spec.(*ast.ImportSpec)
+			name := importName(importSpec)
+			if name == "_" || name == "." || used[name] {
+				specs = append(specs, spec)
+			}
+		}
+		if len(specs) == 0 {
+			continue
+		}
+		genDecl.Specs = specs
+		decls = append(decls, genDecl)
+	}
+	file.Decls = decls
+
+	var imports []*ast.ImportSpec
+	for _, decl := range file.Decls {
+		if genDecl, ok := decl.(*ast.GenDecl); ok && genDecl.Tok == token.IMPORT {
+			for _, spec := range genDecl.Specs {
+				imports = append(imports, spec.(*ast.ImportSpec))
+			}
+		}
+	}
+	file.Imports = imports
+}
+
+// importName returns the name an import is referred to by, guessing package names from the path
+// the way goimports does: without a /vN element, .vN suffix or go- prefix
+func importName(spec *ast.ImportSpec) string {
+	if spec.Name != nil {
+		return spec.Name.Name
+	}
+
+	importPath := strings.Trim(spec.Path.Value, "\"`")
+	name := path.Base(importPath)
+	if majorVersion.MatchString(name) && path.Dir(importPath) != "." {
+		name = path.Base(path.Dir(importPath))
+	}
+	name, _, _ = strings.Cut(name, ".")
+	return strings.TrimPrefix(name, "go-")
+}
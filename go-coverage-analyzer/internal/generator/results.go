@@ -0,0 +1,130 @@
+package generator
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/beck/go-coverage-analyzer/pkg/coverage"
+)
+
+// packageResults holds the type of the last result of the functions and methods declared in a package
+// directory, test files included, so calls whose last result is blanked can be told apart by it
+type packageResults struct {
+	importPath string              // import path of the package, "" when it is in no module
+	functions  map[string]string   // function name to its last result, "" when it has none
+	methods    map[string][]string // method name to the last result of the method of each receiver
+}
+
+// loadPackageResults reads the declarations of the Go files in dir; files that do not parse are left out
+func loadPackageResults(dir string) *packageResults {
+	results := &packageResults{
+		functions: make(map[string]string),
+		methods:   make(map[string][]string),
+	}
+	results.importPath, _ = coverage.ImportPath(dir)
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return results
+	}
+	fileSet := token.NewFileSet()
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".go") {
+			continue
+		}
+		file, err := parser.ParseFile(fileSet, filepath.Join(dir, entry.Name()), nil, parser.SkipObjectResolution)
+		if err != nil {
+			continue
+		}
+		for _, decl := range file.Decls {
+			funcDecl, ok := decl.(*ast.FuncDecl)
+			if !ok {
+				continue
+			}
+			last := lastResult(funcDecl.Type)
+			if funcDecl.Recv == nil {
+				results.functions[funcDecl.Name.Name] = last
+			} else {
+				results.methods[funcDecl.Name.Name] = append(results.methods[funcDecl.Name.Name], last)
+			}
+		}
+	}
+	return results
+}
+
+// lastResult returns the type of the last result of a function type as source, "" when it has none
+func lastResult(funcType *ast.FuncType) string {
+	if funcType.Results == nil || len(funcType.Results.List) == 0 {
+		return ""
+	}
+	return types.ExprString(funcType.Results.List[len(funcType.Results.List)-1].Type)
+}
+
+// fileImports returns the packages a file imports, by the name it refers to them with
+func fileImports(file *ast.File) map[string]string {
+	imports := make(map[string]string)
+	for _, spec := range file.Imports {
+		importPath, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+		name := path.Base(importPath)
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		imports[name] = importPath
+	}
+	return imports
+}
+
+// resolve returns the type of the last result of a called function, declared in the package or
+// imported from it by an external test package, and whether it could be told from the declarations.
+// Methods resolve when every method of that name in the package agrees on it
+func (r *packageResults) resolve(fun ast.Expr, imports map[string]string) (string, bool) {
+	if r == nil {
+		return "", false
+	}
+
+	// Calls of generic functions may name their type arguments
+	switch index := fun.(type) {
+	case *ast.IndexExpr:
+		fun = index.X
+	case *ast.IndexListExpr:
+		fun = index.X
+	}
+
+	switch fun := fun.(type) {
+	case *ast.Ident:
+		last, ok := r.functions[fun.Name]
+		return last, ok
+	case *ast.SelectorExpr:
+		if pkg, ok := fun.X.(*ast.Ident); ok {
+			if importPath, imported := imports[pkg.Name]; imported {
+				if importPath != r.importPath || r.importPath == "" {
+					return "", false
+				}
+				last, ok := r.functions[fun.Sel.Name]
+				return last, ok
+			}
+		}
+
+		candidates := r.methods[fun.Sel.Name]
+		if len(candidates) == 0 {
+			return "", false
+		}
+		for _, last := range candidates[1:] {
+			if last != candidates[0] {
+				return "", false
+			}
+		}
+		return candidates[0], true
+	}
+	return "", false
+}
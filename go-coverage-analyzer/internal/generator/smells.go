@@ -0,0 +1,407 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/beck/go-coverage-analyzer/pkg/coverage"
+)
+
+// Test smell rule identifiers, checked along with the quality check rules
+const (
+	RuleTooManyAssertions  = "too-many-assertions"
+	RuleMissingHelper      = "missing-t-helper"
+	RuleSleepInTest        = "sleep-in-test"
+	RuleIgnoredError       = "ignored-error"
+	RuleLongTest           = "long-test"
+	RuleDuplicateTableCase = "duplicate-table-case"
+)
+
+// Finding severities, from the most to the least serious
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+	SeverityInfo    = "info"
+)
+
+// QualityRules lists the quality and test smell rules in the order reports describe them
+var QualityRules = []string{
+	RuleEmptyTest,
+	RuleMissingAssertions,
+	RuleTableTestMissingRun,
+	RuleTooManyAssertions,
+	RuleMissingHelper,
+	RuleSleepInTest,
+	RuleIgnoredError,
+	RuleLongTest,
+	RuleDuplicateTableCase,
+}
+
+// ruleSeverities holds the severity findings of each quality and smell rule get
+var ruleSeverities = map[string]string{
+	RuleEmptyTest:           SeverityWarning,
+	RuleMissingAssertions:   SeverityInfo,
+	RuleTableTestMissingRun: SeverityInfo,
+	RuleTooManyAssertions:   SeverityInfo,
+	RuleMissingHelper:       SeverityInfo,
+	RuleSleepInTest:         SeverityWarning,
+	RuleIgnoredError:        SeverityError,
+	RuleLongTest:            SeverityInfo,
+	RuleDuplicateTableCase:  SeverityWarning,
+}
+
+// RuleSeverity returns the severity of a quality or smell rule
+func RuleSeverity(rule string) string {
+	if severity, ok := ruleSeverities[rule]; ok {
+		return severity
+	}
+	return SeverityWarning
+}
+
+// SeverityRank orders severities: 3 for error, 2 for warning, 1 for info and 0 for anything else
+func SeverityRank(severity string) int {
+	switch severity {
+	case SeverityError:
+		return 3
+	case SeverityWarning:
+		return 2
+	case SeverityInfo:
+		return 1
+	}
+	return 0
+}
+
+// Default smell thresholds
+const (
+	DefaultMaxAssertions = 10
+	DefaultMaxTestLines  = 100
+)
+
+// SmellOptions holds the thresholds of the test smell checks; zero values use the defaults
+type SmellOptions struct {
+	MaxAssertions int // assertions a test may make before it checks too much at once
+	MaxTestLines  int // lines a test function may span
+}
+
+// TestLintReport holds the test smells found in the test files of a project
+type TestLintReport struct {
+	ProjectPath string          `json:"project_path"`
+	Timestamp   time.Time       `json:"timestamp"`
+	Files       []*TestFileLint `json:"files"`
+	Tests       int             `json:"tests"`
+	Assertions  int             `json:"assertions"`
+	Errors      int             `json:"errors"`
+	Warnings    int             `json:"warnings"`
+	Infos       int             `json:"infos"`
+}
+
+// TestFileLint holds the test smells of one test file
+type TestFileLint struct {
+	Path              string     `json:"path"`
+	Tests             int        `json:"tests"`
+	Assertions        int        `json:"assertions"`
+	AssertionsPerTest float64    `json:"assertions_per_test"`
+	ParseError        string     `json:"parse_error,omitempty"`
+	Findings          []*Finding `json:"findings,omitempty"`
+}
+
+// Count returns how many findings of the report have at least the given severity; a test file that
+// does not parse counts as an error
+func (r *TestLintReport) Count(minSeverity string) int {
+	count := 0
+	for _, file := range r.Files {
+		if file.ParseError != "" && SeverityRank(minSeverity) <= SeverityRank(SeverityError) {
+			count++
+		}
+		for _, finding := range file.Findings {
+			if SeverityRank(finding.Severity) >= SeverityRank(minSeverity) {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// LintTests checks test files, given relative to the project, for test smells
+func LintTests(projectPath string, testFiles []string, opts *SmellOptions) *TestLintReport {
+	report := &TestLintReport{
+		ProjectPath: projectPath,
+		Timestamp:   time.Now(),
+		Files:       make([]*TestFileLint, 0, len(testFiles)),
+	}
+	fileSet := token.NewFileSet()
+	results := make(map[string]*packageResults) // by package directory
+
+	for _, testFile := range testFiles {
+		lint := &TestFileLint{Path: testFile}
+		report.Files = append(report.Files, lint)
+
+		file, err := parser.ParseFile(fileSet, filepath.Join(projectPath, testFile), nil, parser.ParseComments)
+		if err != nil {
+			lint.ParseError = err.Error()
+			report.Errors++
+			continue
+		}
+		dir := filepath.Join(projectPath, filepath.Dir(testFile))
+		if results[dir] == nil {
+			results[dir] = loadPackageResults(dir)
+		}
+		checker := newSmellChecker(fileSet, testFile, opts)
+		checker.results = results[dir]
+		checker.checkFile(file)
+		lint.Tests, lint.Assertions, lint.Findings = checker.tests, checker.assertions, checker.findings
+		if lint.Tests > 0 {
+			lint.AssertionsPerTest = float64(lint.Assertions) / float64(lint.Tests)
+		}
+
+		report.Tests += lint.Tests
+		report.Assertions += lint.Assertions
+		for _, finding := range lint.Findings {
+			switch finding.Severity {
+			case SeverityError:
+				report.Errors++
+			case SeverityWarning:
+				report.Warnings++
+			default:
+				report.Infos++
+			}
+		}
+	}
+
+	return report
+}
+
+// smellChecker collects the findings of one test file
+type smellChecker struct {
+	fileSet       *token.FileSet
+	path          string
+	maxAssertions int
+	maxTestLines  int
+	helpers       map[string]bool // functions of the file that fail the test they are given
+	imports       map[string]string
+	results       *packageResults // declarations of the package, nil when they were not read
+	tests         int
+	assertions    int
+	findings      []*Finding
+}
+
+// newSmellChecker creates a checker for the test file at path, relative to the project
+func newSmellChecker(fileSet *token.FileSet, path string, opts *SmellOptions) *smellChecker {
+	checker := &smellChecker{
+		fileSet:       fileSet,
+		path:          path,
+		maxAssertions: DefaultMaxAssertions,
+		maxTestLines:  DefaultMaxTestLines,
+	}
+	if opts != nil && opts.MaxAssertions > 0 {
+		checker.maxAssertions = opts.MaxAssertions
+	}
+	if opts != nil && opts.MaxTestLines > 0 {
+		checker.maxTestLines = opts.MaxTestLines
+	}
+	return checker
+}
+
+// checkFile checks every function of a parsed test file, leaving the findings sorted by line
+func (sc *smellChecker) checkFile(file *ast.File) {
+	sc.helpers = coverage.FailingHelpers(file)
+	sc.imports = fileImports(file)
+
+	for _, decl := range file.Decls {
+		funcDecl, ok := decl.(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+
+		if strings.HasPrefix(funcDecl.Name.Name, "Test") {
+			sc.checkTest(funcDecl)
+		}
+		if funcDecl.Body == nil {
+			continue
+		}
+		if !strings.HasPrefix(funcDecl.Name.Name, "Test") {
+			sc.checkHelper(funcDecl)
+		}
+		sc.checkStatements(funcDecl)
+	}
+
+	sort.SliceStable(sc.findings, func(i, j int) bool {
+		return sc.findings[i].Line < sc.findings[j].Line
+	})
+}
+
+// checkTest checks the structure, assertions, length and table cases of a test function
+func (sc *smellChecker) checkTest(funcDecl *ast.FuncDecl) {
+	name := funcDecl.Name.Name
+	line := sc.line(funcDecl.Pos())
+	sc.tests++
+
+	if funcDecl.Body == nil || len(funcDecl.Body.List) == 0 {
+		sc.add(RuleEmptyTest, line, fmt.Sprintf("Empty test function %s in %s", name, sc.path))
+		return
+	}
+
+	assertions := coverage.CountAssertions(funcDecl.Body, sc.helpers)
+	hasTestCases := false
+	ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.CallExpr:
+			if selExpr, ok := node.Fun.(*ast.SelectorExpr); ok && selExpr.Sel.Name == "Run" {
+				hasTestCases = true
+			}
+		case *ast.CompositeLit:
+			sc.checkTableCases(name, node)
+		}
+		return true
+	})
+	sc.assertions += assertions
+
+	switch {
+	case assertions == 0:
+		sc.add(RuleMissingAssertions, line, fmt.Sprintf("Test function %s in %s lacks proper assertions", name, sc.path))
+	case assertions > sc.maxAssertions:
+		sc.add(RuleTooManyAssertions, line,
+			fmt.Sprintf("Test function %s in %s makes %d assertions, more than %d", name, sc.path, assertions, sc.maxAssertions))
+	}
+
+	if !hasTestCases && strings.Contains(name, "Table") {
+		sc.add(RuleTableTestMissingRun, line, fmt.Sprintf("Table test function %s in %s lacks t.Run calls", name, sc.path))
+	}
+
+	if lines := sc.line(funcDecl.End()) - line + 1; lines > sc.maxTestLines {
+		sc.add(RuleLongTest, line,
+			fmt.Sprintf("Test function %s in %s is %d lines long, more than %d", name, sc.path, lines, sc.maxTestLines))
+	}
+}
+
+// checkHelper reports helpers that fail the test without calling t.Helper, which makes failures
+// point at the helper instead of the test calling it
+func (sc *smellChecker) checkHelper(funcDecl *ast.FuncDecl) {
+	if param, fails, helper := coverage.HelperFailures(funcDecl); fails && !helper {
+		sc.add(RuleMissingHelper, sc.line(funcDecl.Pos()),
+			fmt.Sprintf("Helper %s in %s reports failures without calling %s.Helper()", funcDecl.Name.Name, sc.path, param))
+	}
+}
+
+// checkStatements reports sleeps and ignored errors anywhere in a function of the test file
+func (sc *smellChecker) checkStatements(funcDecl *ast.FuncDecl) {
+	name := funcDecl.Name.Name
+	ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.CallExpr:
+			if selExpr, ok := node.Fun.(*ast.SelectorExpr); ok && selExpr.Sel.Name == "Sleep" {
+				if ident, ok := selExpr.X.(*ast.Ident); ok && ident.Name == "time" {
+					sc.add(RuleSleepInTest, sc.line(node.Pos()),
+						fmt.Sprintf("%s in %s calls time.Sleep; wait on a channel or condition instead", name, sc.path))
+				}
+			}
+		case *ast.AssignStmt:
+			// A blank last result of a call is a discarded error when the call returns one last; calls
+			// declared elsewhere, which may return a bool or a count instead, are only worth a look
+			if len(node.Rhs) != 1 {
+				return true
+			}
+			call, ok := node.Rhs[0].(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			if blank, ok := node.Lhs[len(node.Lhs)-1].(*ast.Ident); !ok || blank.Name != "_" {
+				return true
+			}
+			switch last, resolved := sc.results.resolve(call.Fun, sc.imports); {
+			case !resolved:
+				sc.addSeverity(RuleIgnoredError, SeverityInfo, sc.line(node.Pos()),
+					fmt.Sprintf("%s in %s discards the last result of %s, which may be an error", name, sc.path, sc.render(call.Fun)))
+			case last == "error":
+				sc.add(RuleIgnoredError, sc.line(node.Pos()),
+					fmt.Sprintf("%s in %s ignores the error returned by %s", name, sc.path, sc.render(call.Fun)))
+			}
+		}
+		return true
+	})
+}
+
+// checkTableCases reports the cases of a table that repeat an earlier case apart from its name
+func (sc *smellChecker) checkTableCases(testName string, table *ast.CompositeLit) {
+	switch table.Type.(type) {
+	case *ast.ArrayType, *ast.MapType:
+	default:
+		return
+	}
+
+	seen := make(map[string]int)
+	for _, elt := range table.Elts {
+		var testCase ast.Expr = elt
+		if keyValue, ok := elt.(*ast.KeyValueExpr); ok {
+			testCase = keyValue.Value
+		}
+		fields, ok := testCase.(*ast.CompositeLit)
+		if !ok || len(fields.Elts) == 0 {
+			continue
+		}
+
+		key := sc.caseKey(fields)
+		line := sc.line(elt.Pos())
+		if first, ok := seen[key]; ok {
+			sc.add(RuleDuplicateTableCase, line,
+				fmt.Sprintf("Test case of %s in %s at line %d duplicates the case at line %d", testName, sc.path, line, first))
+			continue
+		}
+		seen[key] = line
+	}
+}
+
+// caseKey renders the fields of a table case without the fields naming it
+func (sc *smellChecker) caseKey(fields *ast.CompositeLit) string {
+	parts := make([]string, 0, len(fields.Elts))
+	for _, field := range fields.Elts {
+		if keyValue, ok := field.(*ast.KeyValueExpr); ok {
+			if key, ok := keyValue.Key.(*ast.Ident); ok {
+				switch strings.ToLower(key.Name) {
+				case "name", "desc", "description", "title", "scenario":
+					continue
+				}
+			}
+		}
+		parts = append(parts, sc.render(field))
+	}
+	return strings.Join(parts, "\x00")
+}
+
+// add records a finding with the severity of its rule
+func (sc *smellChecker) add(rule string, line int, message string) {
+	sc.addSeverity(rule, RuleSeverity(rule), line, message)
+}
+
+// addSeverity records a finding with a severity of its own, for findings less certain than their rule
+func (sc *smellChecker) addSeverity(rule, severity string, line int, message string) {
+	sc.findings = append(sc.findings, &Finding{
+		Rule:     rule,
+		Severity: severity,
+		File:     sc.path,
+		Line:     line,
+		Message:  message,
+	})
+}
+
+// line returns the line of a position
+func (sc *smellChecker) line(pos token.Pos) int {
+	return sc.fileSet.Position(pos).Line
+}
+
+// render prints an expression as source
+func (sc *smellChecker) render(node ast.Node) string {
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, sc.fileSet, node); err != nil {
+		return ""
+	}
+	return buf.String()
+}
@@ -0,0 +1,261 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/beck/go-coverage-analyzer/internal/i18n"
+	"github.com/beck/go-coverage-analyzer/internal/manifest"
+	"github.com/beck/go-coverage-analyzer/pkg/models"
+)
+
+// Suite styles for shared test fixtures
+const (
+	SuiteStyleNone         = "none"
+	SuiteStyleTestMain     = "testmain"
+	SuiteStyleTestifySuite = "testify-suite"
+)
+
+// Generated fixture files, one per package directory
+const (
+	testMainFile = "gcov_main_test.go"
+	suiteFile    = "gcov_suite_test.go"
+)
+
+// suitePlan describes the fixture file generated for a package directory
+type suitePlan struct {
+	dir         string
+	packageName string
+	tempDir     bool
+	server      bool
+	envVars     []string
+}
+
+// planSuites decides which package directories get a TestMain or testify suite for their fixtures
+func (tg *TestGenerator) planSuites(fileGroups map[string][]*models.Function) {
+	tg.suites = make(map[string]*suitePlan)
+	if tg.options.SuiteStyle == "" || tg.options.SuiteStyle == SuiteStyleNone {
+		return
+	}
+
+	envVars := make(map[string]map[string]bool)
+	for _, functions := range fileGroups {
+		for _, function := range functions {
+			if len(function.Fixtures) == 0 {
+				continue
+			}
+
+			dir := filepath.Dir(function.File)
+			plan, exists := tg.suites[dir]
+			if !exists {
+				plan = &suitePlan{dir: dir, packageName: function.Package}
+				tg.suites[dir] = plan
+				envVars[dir] = make(map[string]bool)
+			}
+
+			for _, fixture := range function.Fixtures {
+				switch fixture {
+				case models.FixtureTempDir:
+					plan.tempDir = true
+				case models.FixtureServer:
+					plan.server = true
+				}
+			}
+			for _, name := range function.EnvVars {
+				envVars[dir][name] = true
+			}
+		}
+	}
+
+	for dir, plan := range tg.suites {
+		for name := range envVars[dir] {
+			plan.envVars = append(plan.envVars, name)
+		}
+		sort.Strings(plan.envVars)
+
+		// Variables read through non-literal names leave nothing to set up
+		if !plan.tempDir && !plan.server && len(plan.envVars) == 0 {
+			delete(tg.suites, dir)
+			continue
+		}
+
+		// Never clash with fixtures the package already sets up
+		if reason := tg.suiteConflict(plan); reason != "" {
+			if tg.verbose {
+				i18n.Printf("⚠️ Skipping %s fixtures for %s: %s\n", tg.options.SuiteStyle, dir, reason)
+			}
+			delete(tg.suites, dir)
+			continue
+		}
+
+		tg.templateEngine.SetFixtureValues(dir, tg.fixtureValues(plan))
+	}
+}
+
+// suiteConflict returns why a fixture file cannot be added to a package, or "" when it can
+func (tg *TestGenerator) suiteConflict(plan *suitePlan) string {
+	fullDir := filepath.Join(tg.options.ProjectPath, plan.dir)
+
+	name := testMainFile
+	if tg.options.SuiteStyle == SuiteStyleTestifySuite {
+		name = suiteFile
+	}
+	if _, err := os.Stat(filepath.Join(fullDir, name)); err == nil {
+		return name + " already exists"
+	}
+
+	if tg.options.SuiteStyle != SuiteStyleTestMain {
+		return ""
+	}
+
+	testFiles, _ := filepath.Glob(filepath.Join(fullDir, "*_test.go"))
+	for _, testFile := range testFiles {
+		content, err := os.ReadFile(testFile)
+		if err == nil && strings.Contains(string(content), "func TestMain(") {
+			return "package already has a TestMain in " + filepath.Base(testFile)
+		}
+	}
+
+	return ""
+}
+
+// fixtureValues returns the expressions tests use to reach each fixture
+func (tg *TestGenerator) fixtureValues(plan *suitePlan) map[string]string {
+	values := make(map[string]string)
+	if tg.options.SuiteStyle == SuiteStyleTestifySuite {
+		if plan.tempDir {
+			values[models.FixtureTempDir] = "s.tempDir"
+		}
+		if plan.server {
+			values[models.FixtureServer] = "s.server.URL"
+		}
+		return values
+	}
+
+	if plan.tempDir {
+		values[models.FixtureTempDir] = "testTempDir"
+	}
+	if plan.server {
+		values[models.FixtureServer] = "testServer.URL"
+	}
+	return values
+}
+
+// writeSuites writes the planned fixture files
+func (tg *TestGenerator) writeSuites(result *models.GenerationResult) {
+	dirs := make([]string, 0, len(tg.suites))
+	for dir := range tg.suites {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+
+	for _, dir := range dirs {
+		plan := tg.suites[dir]
+		data := tg.suiteTemplateData(plan)
+
+		name := testMainFile
+		templateName := "test_main"
+		if tg.options.SuiteStyle == SuiteStyleTestifySuite {
+			name = suiteFile
+			templateName = "testify_suite"
+		}
+
+		content, err := tg.templateEngine.GenerateFile(templateName, data)
+		if err == nil {
+			content, err = tg.inTestPackage(dir, content)
+		}
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("Failed to generate fixtures for %s: %v", dir, err))
+			continue
+		}
+
+		path := filepath.Join(dir, name)
+		if _, err := tg.writeTestFile(path, content); err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("Failed to write fixtures for %s: %v", dir, err))
+			continue
+		}
+
+		tg.recordArtifact(manifest.KindHelper, path)
+
+		if tg.verbose {
+			i18n.Printf("🧰 Created fixture setup: %s\n", path)
+		}
+	}
+}
+
+// suiteTemplateData builds the setup and teardown code for a fixture file
+func (tg *TestGenerator) suiteTemplateData(plan *suitePlan) *TemplateData {
+	var setup, teardown []string
+	imports := []string{"testing"}
+
+	if tg.options.SuiteStyle == SuiteStyleTestifySuite {
+		if plan.tempDir {
+			imports = append(imports, "os")
+			setup = append(setup, fmt.Sprintf("\tdir, err := os.MkdirTemp(\"\", %q)\n\ts.Require().NoError(err)\n\ts.tempDir = dir", plan.packageName+"-test-*"))
+			teardown = append(teardown, "\tos.RemoveAll(s.tempDir)")
+		}
+		for _, name := range plan.envVars {
+			imports = append(imports, "os")
+			setup = append(setup, fmt.Sprintf("\tos.Setenv(%q, %q)", name, "test"))
+			teardown = append(teardown, fmt.Sprintf("\tos.Unsetenv(%q)", name))
+		}
+		if plan.server {
+			imports = append(imports, "net/http", "net/http/httptest")
+			setup = append(setup, "\ts.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {\n\t\tw.WriteHeader(http.StatusOK)\n\t}))")
+			teardown = append(teardown, "\ts.server.Close()")
+		}
+		imports = append(imports, "github.com/stretchr/testify/suite")
+	} else {
+		imports = append(imports, "os")
+		if plan.tempDir {
+			imports = append(imports, "fmt")
+			setup = append(setup, fmt.Sprintf("\tdir, err := os.MkdirTemp(\"\", %q)\n\tif err != nil {\n\t\tfmt.Fprintf(os.Stderr, \"failed to create temp dir: %%v\\n\", err)\n\t\tos.Exit(1)\n\t}\n\ttestTempDir = dir", plan.packageName+"-test-*"))
+			teardown = append(teardown, "\tos.RemoveAll(testTempDir)")
+		}
+		for _, name := range plan.envVars {
+			setup = append(setup, fmt.Sprintf("\tos.Setenv(%q, %q)", name, "test"))
+		}
+		if plan.server {
+			imports = append(imports, "net/http", "net/http/httptest")
+			setup = append(setup, "\ttestServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {\n\t\tw.WriteHeader(http.StatusOK)\n\t}))")
+			teardown = append(teardown, "\ttestServer.Close()")
+		}
+	}
+
+	// Tear down in reverse order of setup
+	for i, j := 0, len(teardown)-1; i < j; i, j = i+1, j-1 {
+		teardown[i], teardown[j] = teardown[j], teardown[i]
+	}
+
+	return &TemplateData{
+		PackageName:  plan.packageName,
+		TestName:     suiteName(plan.packageName),
+		Imports:      removeDuplicates(imports),
+		SetupCode:    strings.Join(setup, "\n"),
+		TeardownCode: strings.Join(teardown, "\n"),
+		Fixtures:     fixtureFlags(plan),
+	}
+}
+
+// fixtureFlags lists which fixtures a plan sets up, for declaring their variables
+func fixtureFlags(plan *suitePlan) map[string]bool {
+	return map[string]bool{
+		models.FixtureTempDir: plan.tempDir,
+		models.FixtureServer:  plan.server,
+	}
+}
+
+// suiteName names the testify suite of a package
+func suiteName(packageName string) string {
+	return toCamelCase(packageName) + "Suite"
+}
+
+// toSuiteMethod turns a generated test function into a method of the package suite
+func toSuiteMethod(content, testName, packageName string) string {
+	header := fmt.Sprintf("func %s(t *testing.T) {\n", testName)
+	method := fmt.Sprintf("func (s *%s) %s() {\n\tt := s.T()\n", suiteName(packageName), testName)
+	return strings.Replace(content, header, method, 1)
+}
@@ -3,28 +3,106 @@ package generator
 import (
 	"bytes"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"text/template"
 
+	"github.com/beck/go-coverage-analyzer/internal/i18n"
 	"github.com/beck/go-coverage-analyzer/pkg/models"
+	gcovtemplates "github.com/beck/go-coverage-analyzer/templates"
 )
 
 // TemplateEngine handles test template processing
 type TemplateEngine struct {
-	templates map[string]*template.Template
-	verbose   bool
+	templates     map[string]*template.Template
+	evaluator     *Evaluator
+	fixtureValues map[string]map[string]string // package directory to fixture kind to expression
+	templatesDir  string                       // custom templates overriding the embedded ones, empty for none
+	namer         *TestNamer
+	testNames     map[*models.Function]string // name tests of a function use instead of its own, as its tests would clash otherwise
+	parallel      bool                        // generated tests call t.Parallel where safe
+	skeleton      bool                        // generated tests skip with a TODO and leave expectations as placeholders
+	version       string                      // gcov version recorded in the markers of generated tests
+	warnings      []string                    // custom templates skipped, so that the built-in ones are used instead
+	verbose       bool
 }
 
-// NewTemplateEngine creates a new template engine
-func NewTemplateEngine(verbose bool) *TemplateEngine {
+// NewTemplateEngine creates a new template engine, loading custom templates from templatesDir when set
+func NewTemplateEngine(templatesDir string, verbose bool) *TemplateEngine {
+	namer, _ := NewTestNamer(DefaultTestNamePattern)
 	return &TemplateEngine{
-		templates: make(map[string]*template.Template),
-		verbose:   verbose,
+		templates:     make(map[string]*template.Template),
+		fixtureValues: make(map[string]map[string]string),
+		templatesDir:  templatesDir,
+		namer:         namer,
+		testNames:     make(map[*models.Function]string),
+		version:       DefaultVersion,
+		verbose:       verbose,
 	}
 }
 
+// Warnings returns the custom templates that could not be read, and were left for the built-in ones
+func (te *TemplateEngine) Warnings() []string {
+	return te.warnings
+}
+
+// SetTestNamer makes generated tests follow a naming pattern
+func (te *TemplateEngine) SetTestNamer(namer *TestNamer) {
+	te.namer = namer
+}
+
+// SetParallel makes generated tests and their subtests call t.Parallel where safe
+func (te *TemplateEngine) SetParallel(parallel bool) {
+	te.parallel = parallel
+}
+
+// SetSkeleton makes generated tests skip with a TODO and hold placeholders instead of expected values
+func (te *TemplateEngine) SetSkeleton(skeleton bool) {
+	te.skeleton = skeleton
+}
+
+// canRunParallel reports whether tests of a function can run alongside others: goleak sees the
+// goroutines of parallel tests, and the default transport and package fixtures are shared
+func canRunParallel(function *models.Function) bool {
+	if function.Concurrent || len(function.Fixtures) > 0 {
+		return false
+	}
+	return function.HTTP == nil || !function.HTTP.Default
+}
+
+// SetTestFunctionName makes tests of the function name it name instead of its own, e.g. MockCache_Delete
+func (te *TemplateEngine) SetTestFunctionName(function *models.Function, name string) {
+	te.testNames[function] = name
+}
+
+// TestName returns the name of a test of the function, with variant empty for the main test
+func (te *TemplateEngine) TestName(function *models.Function, variant string) string {
+	if name, ok := te.testNames[function]; ok {
+		return te.namer.NameAs(function, name, variant)
+	}
+	return te.namer.Name(function, variant)
+}
+
+// SetVersion sets the gcov version recorded in the markers of generated tests
+func (te *TemplateEngine) SetVersion(version string) {
+	if version != "" {
+		te.version = version
+	}
+}
+
+// SetEvaluator makes generated assertions use values computed by executing the functions
+func (te *TemplateEngine) SetEvaluator(evaluator *Evaluator) {
+	te.evaluator = evaluator
+}
+
+// SetFixtureValues makes tests of functions in dir reach shared fixtures through the given expressions
+func (te *TemplateEngine) SetFixtureValues(dir string, values map[string]string) {
+	te.fixtureValues[dir] = values
+}
+
 // TemplateData contains data for template rendering
 type TemplateData struct {
 	PackageName    string
@@ -42,6 +120,27 @@ type TemplateData struct {
 	ProjectPath    string
 	FileName       string
 	Comment        string
+
+	Parallel         bool            // tests and their subtests call t.Parallel
+	Skeleton         bool            // tests skip until someone fills in the placeholder expectations
+	StressIterations int             // concurrent calls made by stress tests
+	BenchmarkSizes   []BenchmarkSize // input sizes of sub-benchmarks
+	Fixtures         map[string]bool // shared fixtures set up by TestMain or suite files
+	Clocks           []string        // clock interfaces faked by the clock helper
+	CoverDirEnv      string          // variable naming where subprocesses started by tests leave coverage counters
+	Results          []ResultData    // one entry per returned value
+	ResultVars       string          // variables receiving every returned value, e.g. "got1, got2, err"
+	Assign           string          // := when ResultVars declares a variable, = otherwise
+}
+
+// ResultData names the variable and table field holding a returned value
+type ResultData struct {
+	Got    string // variable receiving the value
+	Want   string // table field holding the expected value, empty when the value is not compared
+	Label  string // how failure messages refer to the value
+	Type   string
+	Error  bool
+	Recent bool // a clock reading, checked to be within a minute of the current time
 }
 
 // TestCaseData represents a single test case
@@ -54,6 +153,8 @@ type TestCaseData struct {
 	ErrorMessage   string
 	MockSetup      string
 	Comment        string
+	ResultVars     string // variables receiving the returned values, with _ for unchecked ones
+	Assign         string // := when ResultVars declares a variable, = otherwise
 }
 
 // InputData represents function input parameters
@@ -65,8 +166,12 @@ type InputData struct {
 
 // OutputData represents expected function outputs
 type OutputData struct {
-	Value string
-	Type  string
+	Value  string
+	Type   string
+	Field  string // table field holding the value
+	Got    string // variable the value is compared against
+	Label  string
+	Recent bool // checked to be within a minute of the current time instead
 }
 
 // MockData represents mock generation data
@@ -83,28 +188,41 @@ type MockMethodData struct {
 	ReturnType string
 }
 
-// LoadTemplates loads templates from external files first, then falls back to built-in templates
-func (te *TemplateEngine) LoadTemplates() error {
-	// Try to load external templates first
-	externalTemplates := te.loadExternalTemplates()
-
-	// Built-in templates as fallback
-	builtinTemplates := map[string]string{
+// builtinTemplates returns the templates compiled into gcov, by name
+func builtinTemplates() map[string]string {
+	return map[string]string{
 		"function_test":  functionTestTemplate,
 		"table_test":     tableTestTemplate,
 		"benchmark_test": benchmarkTestTemplate,
 		"testify_test":   testifyTestTemplate,
+		"gotools_test":   gotestToolsTestTemplate,
+		"gocmp_test":     goCmpTestTemplate,
 		"method_test":    methodTestTemplate,
+		"snapshot_test":  snapshotTestTemplate,
+		"sqlmock_test":   sqlmockTestTemplate,
+		"http_test":      httpTestTemplate,
+		"http_helper":    httpHelperTemplate,
+		"clock_helper":   clockHelperTemplate,
+		"panic_test":     panicTestTemplate,
+		"stress_test":    stressTestTemplate,
+		"example_test":   exampleTestTemplate,
+		"test_main":      testMainTemplate,
+		"testify_suite":  testifySuiteTemplate,
+		"main_harness":   mainHarnessTemplate,
+		"golden_helper":  goldenHelperTemplate,
 		"error_test":     errorTestTemplate,
 		"mock_interface": mockInterfaceTemplate,
 		"file_header":    fileHeaderTemplate,
 	}
+}
+
+// LoadTemplates loads the built-in templates, overridden by the embedded packs and the custom templates directory
+func (te *TemplateEngine) LoadTemplates() error {
+	// Try to load external templates first
+	externalTemplates := te.loadExternalTemplates()
 
 	// Merge external and built-in templates (external takes precedence)
-	templates := make(map[string]string)
-	for name, content := range builtinTemplates {
-		templates[name] = content
-	}
+	templates := builtinTemplates()
 	for name, content := range externalTemplates {
 		templates[name] = content
 	}
@@ -128,6 +246,21 @@ func (te *TemplateEngine) LoadTemplates() error {
 		"isMapType":              isMapType,
 		"baseType":               getBaseType,
 		"zeroValue":              getZeroValue,
+		"sqlmockRow":             sqlmockRow,
+		"paramValue":             paramValue,
+		"fieldType":              fieldType,
+		"spread":                 spread,
+		"receiverValue":          receiverValue,
+		"httpReceiverValue":      httpReceiverValue,
+		"httpNeedsClient":        httpNeedsClient,
+		"httpArg":                httpArg,
+		"gotestToolsEqual":       gotestToolsEqual,
+		"hasSizedParams":         hasSizedParams,
+		"benchmarkValue":         benchmarkValue,
+		"benchmarkVar":           benchmarkVar,
+		"fakeClockType":          fakeClockType,
+		"panicCall":              panicCall,
+		"generatedMarker":        te.generatedMarker,
 	}
 
 	for name, tmplContent := range templates {
@@ -139,7 +272,7 @@ func (te *TemplateEngine) LoadTemplates() error {
 	}
 
 	if te.verbose {
-		fmt.Printf("📋 Loaded %d test templates\n", len(templates))
+		i18n.Printf("📋 Loaded %d test templates\n", len(templates))
 	}
 
 	return nil
@@ -163,8 +296,93 @@ func (te *TemplateEngine) GenerateTest(function *models.Function, style string,
 	return buf.String(), nil
 }
 
+// ExampleData contains data for rendering an example function
+type ExampleData struct {
+	Function    *models.Function
+	Inputs      []InputData
+	OutputLines []string
+}
+
+// ExampleInputs returns the inputs an example calls the function with
+func (te *TemplateEngine) ExampleInputs(function *models.Function) []InputData {
+	return te.generatePositiveTestCase(function).Inputs
+}
+
+// GenerateExample renders an ExampleXxx function whose Output comment holds the printed result
+func (te *TemplateEngine) GenerateExample(function *models.Function, output string) (string, error) {
+	tmpl, exists := te.templates["example_test"]
+	if !exists {
+		return "", fmt.Errorf("template not found: example_test")
+	}
+
+	data := &ExampleData{
+		Function:    function,
+		Inputs:      te.ExampleInputs(function),
+		OutputLines: strings.Split(strings.TrimRight(output, " \n"), "\n"),
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to execute template: %w", err)
+	}
+
+	return buf.String(), nil
+}
+
+// GenerateFile renders a whole-file template such as the TestMain or suite fixtures
+func (te *TemplateEngine) GenerateFile(templateName string, data *TemplateData) (string, error) {
+	tmpl, exists := te.templates[templateName]
+	if !exists {
+		return "", fmt.Errorf("template not found: %s", templateName)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to execute template: %w", err)
+	}
+
+	return buf.String(), nil
+}
+
+// GenerateGoldenHelper renders the golden file helper used by snapshot tests
+func (te *TemplateEngine) GenerateGoldenHelper(packageName string) (string, error) {
+	tmpl, exists := te.templates["golden_helper"]
+	if !exists {
+		return "", fmt.Errorf("template not found: golden_helper")
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, &TemplateData{PackageName: packageName}); err != nil {
+		return "", fmt.Errorf("failed to execute template: %w", err)
+	}
+
+	return buf.String(), nil
+}
+
 // selectTemplate chooses the appropriate template based on function and style
 func (te *TemplateEngine) selectTemplate(function *models.Function, style string, tableStyle bool) string {
+	switch style {
+	case "benchmark":
+		return "benchmark_test"
+	case "stress":
+		return "stress_test"
+	}
+
+	// Database code is exercised against sqlmock regardless of style
+	if function.SQL != nil {
+		return "sqlmock_test"
+	}
+
+	// HTTP-calling code runs against a fake transport with a canned response
+	if function.HTTP != nil {
+		return "http_test"
+	}
+
+	// Snapshot style only applies to outputs too large to spell out as literals
+	if style == "snapshot" && isSnapshotCandidate(function) {
+		return "snapshot_test"
+	}
+
 	if tableStyle && len(function.Parameters) > 1 {
 		return "table_test"
 	}
@@ -172,8 +390,10 @@ func (te *TemplateEngine) selectTemplate(function *models.Function, style string
 	switch style {
 	case "testify":
 		return "testify_test"
-	case "benchmark":
-		return "benchmark_test"
+	case "gotest-tools":
+		return "gotools_test"
+	case "go-cmp":
+		return "gocmp_test"
 	default:
 		if function.IsMethod {
 			return "method_test"
@@ -185,21 +405,53 @@ func (te *TemplateEngine) selectTemplate(function *models.Function, style string
 	}
 }
 
+// gotestToolsEqual picks the gotest.tools assertion for a type: Equal compares with ==,
+// which panics on slices and maps and only checks pointer identity, so those use DeepEqual
+func gotestToolsEqual(typeName string) string {
+	if strings.HasPrefix(typeName, "[]") || strings.HasPrefix(typeName, "map[") || strings.HasPrefix(typeName, "*") {
+		return "DeepEqual"
+	}
+	return "Equal"
+}
+
 // buildTemplateData constructs template data for a function
 func (te *TemplateEngine) buildTemplateData(function *models.Function, style string, tableStyle bool) *TemplateData {
+	variant := ""
+	if style == "stress" {
+		variant = StressTestVariant
+	}
+	testName := te.TestName(function, variant)
+
 	data := &TemplateData{
 		PackageName:    function.Package,
 		Function:       function,
-		TestName:       fmt.Sprintf("Test%s", function.Name),
+		TestName:       testName,
 		Imports:        te.generateImports(function, style),
 		TableDriven:    tableStyle,
 		AssertionStyle: style,
 		FileName:       filepath.Base(function.File),
-		Comment:        fmt.Sprintf("// %s tests the %s function\n", fmt.Sprintf("Test%s", function.Name), function.Name),
+		Comment:        fmt.Sprintf("// %s tests the %s function\n%s\n", testName, function.Name, te.generatedMarker(function)),
+
+		Parallel:         te.parallel && canRunParallel(function),
+		Skeleton:         te.skeleton,
+		StressIterations: stressIterations,
+		BenchmarkSizes:   benchmarkSizes,
 	}
 
 	// Generate test cases
 	data.TestCases = te.generateTestCases(function, style)
+	if te.skeleton {
+		applyPlaceholderValues(data.TestCases)
+	} else if te.evaluator != nil {
+		te.applyEvaluatedValues(function, data.TestCases)
+	}
+	if values := te.fixtureValues[filepath.Dir(function.File)]; len(values) > 0 && len(function.Fixtures) > 0 {
+		applyFixtureValues(data.TestCases, values)
+	} else if te.usesTestFixtures(function) {
+		applyFixtureValues(data.TestCases, testFixtureValues)
+		data.SetupCode, _ = te.testFixtureSetup(function, data.TestCases)
+	}
+	bindResults(function, data)
 
 	// Add mocks if needed
 	if te.needsMocks(function) {
@@ -220,12 +472,51 @@ func (te *TemplateEngine) generateImports(function *models.Function, style strin
 		if te.needsMocks(function) {
 			imports = append(imports, "github.com/stretchr/testify/mock")
 		}
+		if te.HasPanicTest(function) {
+			imports = append(imports, "github.com/stretchr/testify/require")
+		}
+	}
+
+	// Add the assertion library of the other third-party styles
+	switch style {
+	case "gotest-tools":
+		imports = append(imports, "gotest.tools/v3/assert")
+	case "go-cmp":
+		imports = append(imports, "github.com/google/go-cmp/cmp")
+	}
+
+	// Add sqlmock if the function talks to a database
+	if function.SQL != nil {
+		imports = append(imports, "regexp", "github.com/DATA-DOG/go-sqlmock")
+	}
+
+	// Add net/http for the fake transport and whatever stubbed signatures refer to
+	if usesFakeTransport(function) {
+		imports = append(imports, "net/http")
 	}
+	imports = append(imports, stubImports(function)...)
 
 	// Add context if function uses it
 	if te.usesContext(function) {
 		imports = append(imports, "context")
 	}
+	if hasContextCases(function) || hasRecentResults(function) {
+		imports = append(imports, "time")
+	}
+	if te.usesRepeatedStrings(function) || (style == "benchmark" && benchmarkUsesStrings(function)) {
+		imports = append(imports, "strings")
+	}
+
+	// Add whatever fixtures set up by the test itself need
+	imports = append(imports, te.testFixtureImports(function, style)...)
+
+	// Add goleak for functions that start goroutines or use channels
+	if function.Concurrent {
+		imports = append(imports, "go.uber.org/goleak")
+		if style == "stress" {
+			imports = append(imports, "sync")
+		}
+	}
 
 	// Add other dependencies based on function signature
 	imports = append(imports, te.extractImports(function)...)
@@ -243,14 +534,213 @@ func (te *TemplateEngine) generateTestCases(function *models.Function, style str
 	// Generate edge cases
 	testCases = append(testCases, te.generateEdgeCases(function)...)
 
+	// Generate cases around the constants the function compares its parameters against
+	testCases = append(testCases, te.generateBoundaryCases(function)...)
+
 	// Generate error cases if function returns error
 	if function.HasErrorReturn {
 		testCases = append(testCases, te.generateErrorCases(function)...)
 	}
 
+	// Generate a case for each error return the inputs can reach
+	testCases = append(testCases, te.generateErrorPathCases(function, testCases)...)
+
+	// Generate cancellation and deadline cases for context-aware functions
+	if hasContextCases(function) {
+		testCases = append(testCases, te.generateContextCases(function)...)
+	}
+
+	// Leave inputs the function panics on to the panic test
+	return te.withoutPanicCases(function, testCases)
+}
+
+// generateContextCases creates cases that pass a cancelled context and one whose deadline has passed
+func (te *TemplateEngine) generateContextCases(function *models.Function) []TestCaseData {
+	contexts := []struct {
+		name        string
+		description string
+		value       string
+	}{
+		{"cancelled_context", "Test with an already cancelled context", cancelledContextValue},
+		{"expired_deadline", "Test with a context whose deadline has passed", expiredContextValue},
+	}
+
+	testCases := make([]TestCaseData, 0, len(contexts))
+	for _, ctx := range contexts {
+		testCase := TestCaseData{
+			Name:        ctx.name,
+			Description: ctx.description,
+			Inputs:      make([]InputData, 0, len(function.Parameters)),
+			ExpectError: true,
+		}
+
+		for _, param := range function.Parameters {
+			value := paramValue(function, param, "positive")
+			if param.Type == "context.Context" {
+				value = ctx.value
+			}
+			testCase.Inputs = append(testCase.Inputs, InputData{
+				Name:  param.Name,
+				Type:  param.Type,
+				Value: value,
+			})
+		}
+
+		testCases = append(testCases, testCase)
+	}
+
 	return testCases
 }
 
+// applyPlaceholderValues replaces heuristic expected values with zero values for someone to fill in
+func applyPlaceholderValues(testCases []TestCaseData) {
+	for i := range testCases {
+		for j := range testCases[i].ExpectedOutput {
+			output := &testCases[i].ExpectedOutput[j]
+			output.Value = getZeroValue(output.Type)
+		}
+	}
+}
+
+// applyEvaluatedValues replaces heuristic expected values with the results of executing the function
+func (te *TemplateEngine) applyEvaluatedValues(function *models.Function, testCases []TestCaseData) {
+	for i := range testCases {
+		value, exists := te.evaluator.Lookup(function, testCases[i].Inputs)
+		if !exists || len(testCases[i].Inputs) != len(function.Parameters) {
+			continue
+		}
+		testCases[i].ExpectedOutput = []OutputData{{Value: value, Type: function.ReturnTypes[0]}}
+	}
+}
+
+// applyFixtureValues points string inputs named like URLs, directories or paths at the shared fixtures
+func applyFixtureValues(testCases []TestCaseData, values map[string]string) {
+	for i := range testCases {
+		for j, input := range testCases[i].Inputs {
+			if input.Type != "string" || input.Value != generateTestValue("string", "positive") {
+				continue
+			}
+
+			name := strings.ToLower(input.Name)
+			switch {
+			case strings.Contains(name, "url") || strings.Contains(name, "endpoint"):
+				if server, ok := values[models.FixtureServer]; ok {
+					testCases[i].Inputs[j].Value = server
+				}
+			case strings.Contains(name, "dir") || strings.Contains(name, "root"):
+				if tempDir, ok := values[models.FixtureTempDir]; ok {
+					testCases[i].Inputs[j].Value = tempDir
+				}
+			case strings.Contains(name, "path") || strings.Contains(name, "file"):
+				if tempDir, ok := values[models.FixtureTempDir]; ok {
+					testCases[i].Inputs[j].Value = tempDir + ` + "/fixture"`
+				}
+			}
+		}
+	}
+}
+
+// bindResults names the variables receiving each returned value and links expected outputs to them
+func bindResults(function *models.Function, data *TemplateData) {
+	data.Results = buildResults(function)
+
+	vars := make([]string, len(data.Results))
+	data.Assign = "="
+	for i, result := range data.Results {
+		vars[i] = result.Got
+		if result.Got != "_" {
+			data.Assign = ":="
+		}
+	}
+	data.ResultVars = strings.Join(vars, ", ")
+
+	for i := range data.TestCases {
+		testCase := &data.TestCases[i]
+		caseVars := make([]string, len(data.Results))
+		declares := false
+
+		for j, result := range data.Results {
+			caseVars[j] = "_"
+			if result.Error {
+				if result.Got != "_" {
+					caseVars[j] = result.Got
+					declares = true
+				}
+				continue
+			}
+			if (result.Want == "" && !result.Recent) || j >= len(testCase.ExpectedOutput) {
+				continue
+			}
+
+			output := &testCase.ExpectedOutput[j]
+			output.Field = result.Want
+			output.Recent = result.Recent
+
+			// Values of failing calls are not compared, so they are discarded
+			if !testCase.ExpectError {
+				output.Got = result.Got
+				output.Label = result.Label
+				caseVars[j] = result.Got
+				declares = true
+			}
+		}
+
+		testCase.ResultVars = strings.Join(caseVars, ", ")
+		testCase.Assign = "="
+		if declares {
+			testCase.Assign = ":="
+		}
+	}
+}
+
+// buildResults names the returned values: got and want for a single value, got1, got2, ... for several
+func buildResults(function *models.Function) []ResultData {
+	values := 0
+	for _, returnType := range function.ReturnTypes {
+		if returnType != "error" {
+			values++
+		}
+	}
+
+	results := make([]ResultData, 0, len(function.ReturnTypes))
+	index := 0
+	seenError := false
+	for _, returnType := range function.ReturnTypes {
+		if returnType == "error" {
+			// Only the first error is checked
+			name := "err"
+			if seenError {
+				name = "_"
+			}
+			seenError = true
+			results = append(results, ResultData{Got: name, Type: returnType, Error: true})
+			continue
+		}
+
+		index++
+		result := ResultData{Got: "got", Want: "want", Type: returnType}
+		if values > 1 {
+			result.Got = fmt.Sprintf("got%d", index)
+			result.Want = fmt.Sprintf("want%d", index)
+			result.Label = " " + result.Got
+		}
+		switch {
+		case isFuncType(returnType):
+			// Functions only compare to nil, so the value is discarded
+			result.Got, result.Want = "_", ""
+		case function.UsesTime && returnType == "time.Time":
+			// Clock readings cannot be spelled out, only checked against the current time
+			result.Want, result.Recent = "", true
+		case function.UsesTime && returnType == "time.Duration":
+			// Elapsed times vary from run to run
+			result.Got, result.Want = "_", ""
+		}
+		results = append(results, result)
+	}
+
+	return results
+}
+
 // generatePositiveTestCase creates a basic positive test case
 func (te *TemplateEngine) generatePositiveTestCase(function *models.Function) TestCaseData {
 	testCase := TestCaseData{
@@ -265,7 +755,7 @@ func (te *TemplateEngine) generatePositiveTestCase(function *models.Function) Te
 		input := InputData{
 			Name:  param.Name,
 			Type:  param.Type,
-			Value: generateTestValue(param.Type, "positive"),
+			Value: paramValue(function, param, "positive"),
 		}
 		testCase.Inputs = append(testCase.Inputs, input)
 	}
@@ -370,8 +860,63 @@ func (te *TemplateEngine) generateErrorCases(function *models.Function) []TestCa
 
 // Helper functions for templates
 
+// stressIterations is the number of concurrent calls a stress test makes
+const stressIterations = 50
+
+// cancelledContextValue is an expression yielding a context that is already cancelled
+const cancelledContextValue = `func() context.Context {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+				return ctx
+			}()`
+
+// expiredContextValue is an expression yielding a context whose deadline has already passed
+const expiredContextValue = `func() context.Context {
+				ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+				defer cancel()
+				return ctx
+			}()`
+
+// hasContextCases reports whether a function gets cancellation and deadline test cases
+func hasContextCases(function *models.Function) bool {
+	if !function.HasErrorReturn {
+		return false
+	}
+	for _, param := range function.Parameters {
+		if param.Type == "context.Context" {
+			return true
+		}
+	}
+	return false
+}
+
 func generateTestValue(paramType, scenario string) string {
+	// Variadic parameters are passed a slice and spread
+	if strings.HasPrefix(paramType, "...") {
+		return generateTestValue(fieldType(paramType), scenario)
+	}
+
+	switch {
+	case isChanType(paramType):
+		if scenario == "positive" {
+			return chanValue(paramType)
+		}
+		return "nil"
+	case isFuncType(paramType):
+		// A nil function would panic as soon as it is called
+		return funcStub(paramType)
+	}
+
 	switch paramType {
+	case "context.Context":
+		return "context.Background()"
+	case "interface{}", "any":
+		if scenario == "positive" {
+			return `"test"`
+		}
+		return "nil"
+	case "error":
+		return "nil"
 	case "string":
 		if scenario == "positive" {
 			return `"test"`
@@ -408,6 +953,14 @@ func generateTestValue(paramType, scenario string) string {
 }
 
 func generateExpectedValue(returnType string, function *models.Function, inputs []InputData) string {
+	if isInterfaceOf(function, returnType) {
+		return "nil"
+	}
+	if returnType == "time.Time" && len(function.Clocks) > 0 {
+		// Times read from a frozen fake clock are known up front
+		return "testClockTime"
+	}
+
 	// Simple heuristics for expected values based on function name and inputs
 	switch returnType {
 	case "string":
@@ -429,6 +982,62 @@ func generateExpectedValue(returnType string, function *models.Function, inputs
 	}
 }
 
+// fieldType returns the type a variable holding a parameter needs, turning variadic ...T into []T
+func fieldType(paramType string) string {
+	if strings.HasPrefix(paramType, "...") {
+		return "[]" + strings.TrimPrefix(paramType, "...")
+	}
+	return paramType
+}
+
+// spread returns the ... that passes a slice to a variadic parameter
+func spread(paramType string) string {
+	if strings.HasPrefix(paramType, "...") {
+		return "..."
+	}
+	return ""
+}
+
+// isSnapshotCandidate reports whether a function returns a single value worth comparing against a golden file
+func isSnapshotCandidate(function *models.Function) bool {
+	// Clock-dependent output would never match its golden file twice
+	if function.UsesTime {
+		return false
+	}
+
+	var outputs []string
+	for _, returnType := range function.ReturnTypes {
+		if returnType != "error" {
+			outputs = append(outputs, returnType)
+		}
+	}
+	if len(outputs) != 1 {
+		return false
+	}
+
+	output := outputs[0]
+	switch {
+	case output == "string", output == "[]byte":
+		return true
+	case isBasicType(output), output == "interface{}", output == "any":
+		return false
+	case strings.HasPrefix(output, "func"), strings.HasPrefix(output, "chan"), strings.HasPrefix(output, "<-chan"):
+		return false
+	default:
+		// Structs, slices and maps
+		return true
+	}
+}
+
+// sqlmockRow renders placeholder values for a row of the given columns; database/sql converts them on Scan
+func sqlmockRow(columns []string) string {
+	values := make([]string, len(columns))
+	for i := range values {
+		values[i] = "1"
+	}
+	return strings.Join(values, ", ")
+}
+
 func isBasicType(t string) bool {
 	basicTypes := []string{"string", "int", "int32", "int64", "float32", "float64", "bool", "byte", "rune"}
 	for _, bt := range basicTypes {
@@ -473,8 +1082,11 @@ func getZeroValue(t string) string {
 		return "0"
 	case "rune":
 		return "0"
+	case "error", "interface{}", "any":
+		return "nil"
 	default:
-		if strings.HasPrefix(t, "[]") || strings.HasPrefix(t, "map[") || strings.HasPrefix(t, "*") {
+		if strings.HasPrefix(t, "[]") || strings.HasPrefix(t, "map[") || strings.HasPrefix(t, "*") ||
+			isChanType(t) || isFuncType(t) {
 			return "nil"
 		}
 		return fmt.Sprintf("%s{}", t)
@@ -534,14 +1146,26 @@ func removeDuplicates(slice []string) []string {
 	return result
 }
 
-// loadExternalTemplates loads templates from external template files
+// loadExternalTemplates loads the embedded template packs, then templates from the custom directory over them
 func (te *TemplateEngine) loadExternalTemplates() map[string]string {
 	templates := make(map[string]string)
 
-	templateDirs := []string{"templates/standard", "templates/testify", "templates/table"}
+	files, _ := fs.Glob(gcovtemplates.Packs, "*/*.tmpl")
+	for _, file := range files {
+		content, err := fs.ReadFile(gcovtemplates.Packs, file)
+		if err != nil {
+			continue
+		}
+		templates[packTemplateName(file)] = string(content)
+	}
+
+	if te.templatesDir == "" {
+		return templates
+	}
 
-	for _, dir := range templateDirs {
-		files, err := filepath.Glob(filepath.Join(dir, "*.tmpl"))
+	// Pack templates as <pack>/<name>.tmpl, overrides of built-in templates as <name>.tmpl
+	for _, pattern := range []string{"*/*.tmpl", "*.tmpl"} {
+		files, err := filepath.Glob(filepath.Join(te.templatesDir, pattern))
 		if err != nil {
 			continue
 		}
@@ -549,19 +1173,16 @@ func (te *TemplateEngine) loadExternalTemplates() map[string]string {
 		for _, file := range files {
 			content, err := os.ReadFile(file)
 			if err != nil {
+				te.warnings = append(te.warnings, fmt.Sprintf("Skipping custom template %s, using the built-in one: %v", file, err))
 				continue
 			}
 
-			// Extract template name from file path
-			baseName := filepath.Base(file)
-			templateName := strings.TrimSuffix(baseName, ".tmpl")
-			dirName := filepath.Base(filepath.Dir(file))
-			fullName := dirName + "_" + templateName
-
+			rel, _ := filepath.Rel(te.templatesDir, file)
+			fullName := packTemplateName(filepath.ToSlash(rel))
 			templates[fullName] = string(content)
 
 			if te.verbose {
-				fmt.Printf("📋 Loaded external template: %s\n", fullName)
+				i18n.Printf("📋 Loaded external template: %s\n", fullName)
 			}
 		}
 	}
@@ -569,8 +1190,67 @@ func (te *TemplateEngine) loadExternalTemplates() map[string]string {
 	return templates
 }
 
+// packTemplateName names a template file like standard/function.tmpl standard_function
+func packTemplateName(file string) string {
+	return strings.ReplaceAll(strings.TrimSuffix(file, ".tmpl"), "/", "_")
+}
+
+// ExportTemplates writes the embedded template packs and the built-in templates to dir for customization
+func ExportTemplates(dir string, overwrite bool) ([]string, error) {
+	contents := make(map[string]string)
+	for name, content := range builtinTemplates() {
+		contents[name+".tmpl"] = content
+	}
+
+	files, err := fs.Glob(gcovtemplates.Packs, "*/*.tmpl")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list template packs: %w", err)
+	}
+	for _, file := range files {
+		content, err := fs.ReadFile(gcovtemplates.Packs, file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read template %s: %w", file, err)
+		}
+		contents[filepath.FromSlash(file)] = string(content)
+	}
+
+	names := make([]string, 0, len(contents))
+	for name := range contents {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	written := make([]string, 0, len(names))
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil && !overwrite {
+			return written, fmt.Errorf("%s already exists (use --overwrite to replace it)", path)
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return written, fmt.Errorf("failed to create directory: %w", err)
+		}
+		if err := os.WriteFile(path, []byte(contents[name]), 0644); err != nil {
+			return written, fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		written = append(written, path)
+	}
+
+	return written, nil
+}
+
 // generateBenchmarkValue generates appropriate values for benchmark tests
 func generateBenchmarkValue(typeName string) string {
+	if strings.HasPrefix(typeName, "...") {
+		return generateBenchmarkValue(fieldType(typeName))
+	}
+
+	switch {
+	case isChanType(typeName):
+		return chanValue(typeName)
+	case isFuncType(typeName):
+		return funcStub(typeName)
+	}
+
 	switch typeName {
 	case "string":
 		return `"benchmark_string"`
@@ -598,85 +1278,382 @@ func generateBenchmarkValue(typeName string) string {
 }
 
 const functionTestTemplate = `{{.Comment}}func {{.TestName}}(t *testing.T) {
-	{{if .HasMocks}}// Setup mocks
+	{{if .Skeleton}}t.Skip("TODO: fill expectations")
+
+	{{end}}{{if .Function.Concurrent}}defer goleak.VerifyNone(t)
+
+	{{end}}{{if .Parallel}}t.Parallel()
+
+	{{end}}{{if .SetupCode}}{{.SetupCode}}
+
+	{{end}}	{{if .HasMocks}}// Setup mocks
 	{{range .MockStructs}}{{.Name}} := &Mock{{.InterfaceName}}{}
 	{{end}}{{end}}
 
 	{{if .TableDriven}}tests := []struct {
 		name string
-		{{range .Function.Parameters}}{{.Name}} {{.Type}}
-		{{end}}{{if .Function.ReturnTypes}}want {{index .Function.ReturnTypes 0}}{{end}}
-		{{if .Function.HasErrorReturn}}wantErr bool{{end}}
+		{{range .Function.Parameters}}{{.Name}} {{fieldType .Type}}
+		{{end}}{{range .Results}}{{if .Want}}{{.Want}} {{.Type}}
+		{{end}}{{end}}{{if .Function.HasErrorReturn}}wantErr bool{{end}}
 	}{
 		{{range .TestCases}}{
 			name: "{{.Name}}",
 			{{range .Inputs}}{{.Name}}: {{.Value}},
-			{{end}}{{range .ExpectedOutput}}want: {{.Value}},
-			{{end}}{{if .ExpectError}}wantErr: true,{{end}}
+			{{end}}{{range .ExpectedOutput}}{{if .Field}}{{.Field}}: {{.Value}},
+			{{end}}{{end}}{{if .ExpectError}}wantErr: true,{{end}}
 		},
 		{{end}}
 	}
 
 	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			{{if .Function.IsMethod}}receiver := &{{.Function.ReceiverType}}{}
-			{{if .Function.ReturnTypes}}got{{if .Function.HasErrorReturn}}, err{{end}} := receiver.{{.Function.Name}}({{range $i, $param := .Function.Parameters}}{{if $i}}, {{end}}tt.{{$param.Name}}{{end}}){{else}}receiver.{{.Function.Name}}({{range $i, $param := .Function.Parameters}}{{if $i}}, {{end}}tt.{{$param.Name}}{{end}}){{end}}{{else}}{{if .Function.ReturnTypes}}got{{if .Function.HasErrorReturn}}, err{{end}} := {{.Function.Name}}({{range $i, $param := .Function.Parameters}}{{if $i}}, {{end}}tt.{{$param.Name}}{{end}}){{else}}{{.Function.Name}}({{range $i, $param := .Function.Parameters}}{{if $i}}, {{end}}tt.{{$param.Name}}{{end}}){{end}}{{end}}
+		{{if .Parallel}}tt := tt // capture the loop variable for parallel subtests before Go 1.22
+		{{end}}t.Run(tt.name, func(t *testing.T) {
+			{{if .Parallel}}t.Parallel()
+
+{{end}}			{{if .Function.IsMethod}}receiver := {{receiverValue .Function}}
+			{{end}}{{if .Function.ReturnTypes}}{{.ResultVars}} {{.Assign}} {{end}}{{if .Function.IsMethod}}receiver.{{end}}{{.Function.Name}}({{range $i, $param := .Function.Parameters}}{{if $i}}, {{end}}tt.{{$param.Name}}{{spread $param.Type}}{{end}})
 
 			{{if .Function.HasErrorReturn}}if (err != nil) != tt.wantErr {
 				t.Errorf("{{.Function.Name}}() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
-			if !tt.wantErr && got != tt.want {
-				t.Errorf("{{.Function.Name}}() = %v, want %v", got, tt.want)
-			}{{else if .Function.ReturnTypes}}if got != tt.want {
-				t.Errorf("{{.Function.Name}}() = %v, want %v", got, tt.want)
-			}{{end}}
+			{{end}}{{range .Results}}{{if .Want}}if {{if $.Function.HasErrorReturn}}!tt.wantErr && {{end}}{{.Got}} != tt.{{.Want}} {
+				t.Errorf("{{$.Function.Name}}(){{.Label}} = %v, want %v", {{.Got}}, tt.{{.Want}})
+			}
+			{{else if .Recent}}if d := time.Since({{.Got}}); {{if $.Function.HasErrorReturn}}!tt.wantErr && (d < -time.Minute || d > time.Minute){{else}}d < -time.Minute || d > time.Minute{{end}} {
+				t.Errorf("{{$.Function.Name}}(){{.Label}} = %v, want about the current time", {{.Got}})
+			}
+			{{end}}{{end}}
 		})
 	}{{else}}// Test case
 	{{range $i, $case := .TestCases}}{{if $i}}
 	t.Run("{{$case.Name}}", func(t *testing.T) {
-		{{end}}{{if $.Function.IsMethod}}receiver := &{{$.Function.ReceiverType}}{}
-		{{if $.Function.ReturnTypes}}got{{if $.Function.HasErrorReturn}}, err{{end}} := receiver.{{$.Function.Name}}({{range $j, $input := $case.Inputs}}{{if $j}}, {{end}}{{$input.Value}}{{end}}){{else}}receiver.{{$.Function.Name}}({{range $j, $input := $case.Inputs}}{{if $j}}, {{end}}{{$input.Value}}{{end}}){{end}}{{else}}{{if $.Function.ReturnTypes}}got{{if $.Function.HasErrorReturn}}, err{{end}} := {{$.Function.Name}}({{range $j, $input := $case.Inputs}}{{if $j}}, {{end}}{{$input.Value}}{{end}}){{else}}{{$.Function.Name}}({{range $j, $input := $case.Inputs}}{{if $j}}, {{end}}{{$input.Value}}{{end}}){{end}}{{end}}
+		{{if $.Parallel}}t.Parallel()
+
+{{end}}		{{end}}{{if $.Function.IsMethod}}receiver := {{receiverValue $.Function}}
+		{{end}}{{if $.Function.ReturnTypes}}{{$case.ResultVars}} {{$case.Assign}} {{end}}{{if $.Function.IsMethod}}receiver.{{end}}{{$.Function.Name}}({{range $j, $input := $case.Inputs}}{{if $j}}, {{end}}{{$input.Value}}{{spread $input.Type}}{{end}})
 
 		{{if $.Function.HasErrorReturn}}{{if $case.ExpectError}}if err == nil {
 			t.Errorf("{{$.Function.Name}}() expected error but got none")
 		}{{else}}if err != nil {
 			t.Errorf("{{$.Function.Name}}() unexpected error: %v", err)
 		}{{end}}{{end}}
-		{{if and $.Function.ReturnTypes (not $case.ExpectError)}}{{range $k, $output := $case.ExpectedOutput}}if got != {{$output.Value}} {
-			t.Errorf("{{$.Function.Name}}() = %v, want %v", got, {{$output.Value}})
-		}{{end}}{{end}}{{if $i}}
+		{{range $case.ExpectedOutput}}{{if .Recent}}if d := time.Since({{.Got}}); d < -time.Minute || d > time.Minute {
+			t.Errorf("{{$.Function.Name}}(){{.Label}} = %v, want about the current time", {{.Got}})
+		}
+		{{else if .Got}}if {{.Got}} != {{.Value}} {
+			t.Errorf("{{$.Function.Name}}(){{.Label}} = %v, want %v", {{.Got}}, {{.Value}})
+		}
+		{{end}}{{end}}{{if $i}}
 	}){{end}}{{end}}{{end}}
 }`
 
 const testifyTestTemplate = `{{.Comment}}func {{.TestName}}(t *testing.T) {
-	{{range .TestCases}}t.Run("{{.Name}}", func(t *testing.T) {
-		// Arrange
-		{{range .Inputs}}{{.Name}} := {{.Value}}
+	{{if .Skeleton}}t.Skip("TODO: fill expectations")
+
+	{{end}}{{if .Function.Concurrent}}defer goleak.VerifyNone(t)
+
+	{{end}}{{if .Parallel}}t.Parallel()
+
+	{{end}}{{if .SetupCode}}{{.SetupCode}}
+
+	{{end}}	{{range $case := .TestCases}}t.Run("{{$case.Name}}", func(t *testing.T) {
+		{{if $.Parallel}}t.Parallel()
+
+{{end}}		// Arrange
+		{{range $case.Inputs}}{{.Name}} := {{.Value}}
 		{{end}}
 
 		// Act
-		{{if .Function.IsMethod}}receiver := &{{.Function.ReceiverType}}{}
-		{{if .Function.ReturnTypes}}result{{if .Function.HasErrorReturn}}, err{{end}} := receiver.{{.Function.Name}}({{range $i, $input := .Inputs}}{{if $i}}, {{end}}{{$input.Name}}{{end}}){{else}}receiver.{{.Function.Name}}({{range $i, $input := .Inputs}}{{if $i}}, {{end}}{{$input.Name}}{{end}}){{end}}{{else}}{{if .Function.ReturnTypes}}result{{if .Function.HasErrorReturn}}, err{{end}} := {{.Function.Name}}({{range $i, $input := .Inputs}}{{if $i}}, {{end}}{{$input.Name}}{{end}}){{else}}{{.Function.Name}}({{range $i, $input := .Inputs}}{{if $i}}, {{end}}{{$input.Name}}{{end}}){{end}}{{end}}
+		{{if $.Function.IsMethod}}receiver := {{receiverValue $.Function}}
+		{{end}}{{if $.Function.ReturnTypes}}{{$case.ResultVars}} {{$case.Assign}} {{end}}{{if $.Function.IsMethod}}receiver.{{end}}{{$.Function.Name}}({{range $i, $input := $case.Inputs}}{{if $i}}, {{end}}{{$input.Name}}{{spread $input.Type}}{{end}})
 
 		// Assert
-		{{if .ExpectError}}assert.Error(t, err){{else}}{{if .Function.HasErrorReturn}}assert.NoError(t, err){{end}}{{end}}
-		{{if and .Function.ReturnTypes (not .ExpectError)}}{{range .ExpectedOutput}}assert.Equal(t, {{.Value}}, result){{end}}{{end}}
+		{{if $.Function.HasErrorReturn}}{{if $case.ExpectError}}assert.Error(t, err){{else}}assert.NoError(t, err){{end}}{{end}}
+		{{range $case.ExpectedOutput}}{{if .Recent}}assert.WithinDuration(t, time.Now(), {{.Got}}, time.Minute)
+		{{else if .Got}}assert.Equal(t, {{.Value}}, {{.Got}})
+		{{end}}{{end}}
 	})
 	{{end}}
 }`
 
-const tableTestTemplate = functionTestTemplate
+const gotestToolsTestTemplate = `{{.Comment}}func {{.TestName}}(t *testing.T) {
+	{{if .Skeleton}}t.Skip("TODO: fill expectations")
+
+	{{end}}{{if .Function.Concurrent}}defer goleak.VerifyNone(t)
+
+	{{end}}{{if .Parallel}}t.Parallel()
 
-const benchmarkTestTemplate = `func Benchmark{{.Function.Name}}(b *testing.B) {
-	{{range .Function.Parameters}}{{.Name}} := {{generateValue .Type "positive"}}
+	{{end}}{{if .SetupCode}}{{.SetupCode}}
+
+	{{end}}	{{range $case := .TestCases}}t.Run("{{$case.Name}}", func(t *testing.T) {
+		{{if $.Parallel}}t.Parallel()
+
+{{end}}		// Arrange
+		{{range $case.Inputs}}{{.Name}} := {{.Value}}
+		{{end}}
+
+		// Act
+		{{if $.Function.IsMethod}}receiver := {{receiverValue $.Function}}
+		{{end}}{{if $.Function.ReturnTypes}}{{$case.ResultVars}} {{$case.Assign}} {{end}}{{if $.Function.IsMethod}}receiver.{{end}}{{$.Function.Name}}({{range $i, $input := $case.Inputs}}{{if $i}}, {{end}}{{$input.Name}}{{spread $input.Type}}{{end}})
+
+		// Assert
+		{{if $.Function.HasErrorReturn}}{{if $case.ExpectError}}assert.Assert(t, err != nil, "expected an error"){{else}}assert.NilError(t, err){{end}}{{end}}
+		{{range $case.ExpectedOutput}}{{if .Recent}}assert.Assert(t, time.Since({{.Got}}).Abs() < time.Minute, "{{$.Function.Name}}(){{.Label}} = %v, want about the current time", {{.Got}})
+		{{else if .Got}}assert.{{gotestToolsEqual .Type}}(t, {{.Got}}, {{.Value}})
+		{{end}}{{end}}
+	})
 	{{end}}
+}`
+
+const goCmpTestTemplate = `{{.Comment}}func {{.TestName}}(t *testing.T) {
+	{{if .Skeleton}}t.Skip("TODO: fill expectations")
+
+	{{end}}{{if .Function.Concurrent}}defer goleak.VerifyNone(t)
+
+	{{end}}{{if .Parallel}}t.Parallel()
+
+	{{end}}{{if .SetupCode}}{{.SetupCode}}
+
+	{{end}}	{{range $case := .TestCases}}t.Run("{{$case.Name}}", func(t *testing.T) {
+		{{if $.Parallel}}t.Parallel()
+
+{{end}}		{{range $case.Inputs}}{{.Name}} := {{.Value}}
+		{{end}}
+		{{if $.Function.IsMethod}}receiver := {{receiverValue $.Function}}
+		{{end}}{{if $.Function.ReturnTypes}}{{$case.ResultVars}} {{$case.Assign}} {{end}}{{if $.Function.IsMethod}}receiver.{{end}}{{$.Function.Name}}({{range $i, $input := $case.Inputs}}{{if $i}}, {{end}}{{$input.Name}}{{spread $input.Type}}{{end}})
+
+		{{if $.Function.HasErrorReturn}}{{if $case.ExpectError}}if err == nil {
+			t.Fatalf("{{$.Function.Name}}() expected error but got none")
+		}{{else}}if err != nil {
+			t.Fatalf("{{$.Function.Name}}() unexpected error: %v", err)
+		}{{end}}{{end}}
+		{{range $case.ExpectedOutput}}{{if .Recent}}if d := time.Since({{.Got}}); d < -time.Minute || d > time.Minute {
+			t.Errorf("{{$.Function.Name}}(){{.Label}} = %v, want about the current time", {{.Got}})
+		}
+		{{else if .Got}}if diff := cmp.Diff({{.Value}}, {{.Got}}); diff != "" {
+			t.Errorf("{{$.Function.Name}}(){{.Label}} mismatch (-want +got):\n%s", diff)
+		}
+		{{end}}{{end}}
+	})
+	{{end}}
+}`
+
+const snapshotTestTemplate = `{{.Comment}}func {{.TestName}}(t *testing.T) {
+	{{if .Function.Concurrent}}defer goleak.VerifyNone(t)
 
-	b.ResetTimer()
-	for i := 0; i < b.N; i++ {
-		{{if .Function.IsMethod}}receiver := &{{.Function.ReceiverType}}{}
-		{{if .Function.ReturnTypes}}_ {{if .Function.HasErrorReturn}}, _{{end}} = receiver.{{.Function.Name}}({{range $i, $param := .Function.Parameters}}{{if $i}}, {{end}}{{$param.Name}}{{end}}){{else}}receiver.{{.Function.Name}}({{range $i, $param := .Function.Parameters}}{{if $i}}, {{end}}{{$param.Name}}{{end}}){{end}}{{else}}{{if .Function.ReturnTypes}}_ {{if .Function.HasErrorReturn}}, _{{end}} = {{.Function.Name}}({{range $i, $param := .Function.Parameters}}{{if $i}}, {{end}}{{$param.Name}}{{end}}){{else}}{{.Function.Name}}({{range $i, $param := .Function.Parameters}}{{if $i}}, {{end}}{{$param.Name}}{{end}}){{end}}{{end}}
+	{{end}}{{if .Parallel}}t.Parallel()
+
+	{{end}}{{if .SetupCode}}{{.SetupCode}}
+
+	{{end}}	{{range .TestCases}}{{if eq (len .Inputs) (len $.Function.Parameters)}}t.Run("{{.Name}}", func(t *testing.T) {
+		{{if $.Parallel}}t.Parallel()
+
+{{end}}		{{if $.Function.IsMethod}}receiver := {{receiverValue $.Function}}
+		{{end}}{{$.ResultVars}} {{$.Assign}} {{if $.Function.IsMethod}}receiver.{{end}}{{$.Function.Name}}({{range $i, $input := .Inputs}}{{if $i}}, {{end}}{{$input.Value}}{{spread $input.Type}}{{end}})
+		{{if $.Function.HasErrorReturn}}if err != nil {
+			t.Fatalf("{{$.Function.Name}}() unexpected error: %v", err)
+		}
+		{{end}}assertGolden(t, "{{$.TestName}}_{{.Name}}", got)
+	})
+	{{end}}{{end}}
+}`
+
+const sqlmockTestTemplate = `{{.Comment}}func {{.TestName}}(t *testing.T) {
+	{{if .Function.Concurrent}}defer goleak.VerifyNone(t)
+
+	{{end}}{{if .Parallel}}t.Parallel()
+
+	{{end}}{{if .SetupCode}}{{.SetupCode}}
+
+	{{end}}	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	defer db.Close()
+	{{if .Function.SQL.Tx}}
+	mock.ExpectBegin()
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatalf("failed to begin transaction: %v", err)
 	}
+	{{end}}
+	{{range .Function.SQL.Queries}}{{if .Exec}}mock.ExpectExec(regexp.QuoteMeta({{quote .Statement}})).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	{{else}}mock.ExpectQuery(regexp.QuoteMeta({{quote .Statement}})).
+		WillReturnRows(sqlmock.NewRows([]string{ {{- range $i, $column := .Columns}}{{if $i}}, {{end}}{{quote $column}}{{end -}} }){{if .Columns}}.AddRow({{sqlmockRow .Columns}}){{end}})
+	{{end}}{{end}}
+	{{if .Function.IsMethod}}receiver := &{{trimPrefix .Function.ReceiverType "*"}}{ {{- if .Function.SQL.Field}}{{.Function.SQL.Field}}: {{if .Function.SQL.Tx}}tx{{else}}db{{end}}{{end -}} }
+	{{end}}{{if .Function.ReturnTypes}}{{range $i, $returnType := .Function.ReturnTypes}}{{if $i}}, {{end}}{{if eq $returnType "error"}}callErr{{else}}_{{end}}{{end}} {{if .Function.HasErrorReturn}}:={{else}}={{end}} {{end}}{{if .Function.IsMethod}}receiver.{{end}}{{.Function.Name}}({{range $i, $param := .Function.Parameters}}{{if $i}}, {{end}}{{if eq $param.Name $.Function.SQL.Param}}{{if $.Function.SQL.Tx}}tx{{else}}db{{end}}{{else}}{{paramValue $.Function $param "positive"}}{{spread $param.Type}}{{end}}{{end}})
+	{{if .Function.HasErrorReturn}}if callErr != nil {
+		t.Errorf("{{.Function.Name}}() unexpected error: %v", callErr)
+	}
+	{{end}}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled database expectations: %v", err)
+	}
+}`
+
+// goldenHelperFile holds the golden file helper shared by snapshot tests in a package
+const goldenHelperFile = "gcov_golden_test.go"
+
+const goldenHelperTemplate = `package {{.PackageName}}
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var updateGolden = flag.Bool("update", false, "update golden files in testdata/golden")
+
+// assertGolden compares got with testdata/golden/<name>.golden, writing the file on the first run or with -update
+func assertGolden(t *testing.T, name string, got interface{}) {
+	t.Helper()
+
+	var data []byte
+	switch v := got.(type) {
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		encoded, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			encoded = []byte(fmt.Sprintf("%+v", v))
+		}
+		data = encoded
+	}
+
+	path := filepath.Join("testdata", "golden", name+".golden")
+	if _, err := os.Stat(path); *updateGolden || os.IsNotExist(err) {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create golden directory: %v", err)
+		}
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			t.Fatalf("failed to write golden file: %v", err)
+		}
+		t.Logf("wrote golden file %s", path)
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read golden file: %v", err)
+	}
+	if !bytes.Equal(data, want) {
+		t.Errorf("%s does not match golden file %s (run with -update to accept)\ngot:\n%s\nwant:\n%s", name, path, data, want)
+	}
+}
+`
+
+const stressTestTemplate = `// {{.TestName}} calls {{.Function.Name}} from many goroutines so go test -race can catch data races
+{{generatedMarker .Function}}
+func {{.TestName}}(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	{{if .Function.IsMethod}}receiver := {{receiverValue .Function}}
+	{{end}}var wg sync.WaitGroup
+	for i := 0; i < {{.StressIterations}}; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			{{if .Function.ReturnTypes}}{{range $i, $returnType := .Function.ReturnTypes}}{{if $i}}, {{end}}_{{end}} = {{end}}{{if .Function.IsMethod}}receiver.{{end}}{{.Function.Name}}({{range $i, $param := .Function.Parameters}}{{if $i}}, {{end}}{{paramValue $.Function $param "positive"}}{{spread $param.Type}}{{end}})
+		}()
+	}
+	wg.Wait()
+}`
+
+const exampleTestTemplate = `{{generatedMarker .Function}}
+func Example{{.Function.Name}}() {
+	fmt.Println({{.Function.Name}}({{range $i, $input := .Inputs}}{{if $i}}, {{end}}{{$input.Value}}{{end}}))
+	// Output:{{range .OutputLines}}
+	//{{if .}} {{.}}{{end}}{{end}}
+}`
+
+const testMainTemplate = `package {{.PackageName}}
+
+import (
+{{range .Imports}}	"{{.}}"
+{{end}})
+{{if or .Fixtures.tempdir .Fixtures.server}}
+var (
+{{if .Fixtures.tempdir}}	testTempDir string
+{{end}}{{if .Fixtures.server}}	testServer  *httptest.Server
+{{end}})
+{{end}}
+// TestMain sets up fixtures shared by the tests in this package
+func TestMain(m *testing.M) {
+{{.SetupCode}}
+
+	code := m.Run()
+{{if .TeardownCode}}
+{{.TeardownCode}}
+{{end}}
+	os.Exit(code)
+}
+`
+
+const testifySuiteTemplate = `package {{.PackageName}}
+
+import (
+{{range .Imports}}	"{{.}}"
+{{end}})
+
+// {{.TestName}} holds fixtures shared by the tests in this package
+type {{.TestName}} struct {
+	suite.Suite
+{{if .Fixtures.tempdir}}	tempDir string
+{{end}}{{if .Fixtures.server}}	server  *httptest.Server
+{{end}}}
+
+// SetupSuite creates the shared fixtures
+func (s *{{.TestName}}) SetupSuite() {
+{{.SetupCode}}
+}
+
+// TearDownSuite releases the shared fixtures
+func (s *{{.TestName}}) TearDownSuite() {
+{{.TeardownCode}}
+}
+
+func Test{{.TestName}}(t *testing.T) {
+	suite.Run(t, new({{.TestName}}))
+}
+`
+
+const tableTestTemplate = functionTestTemplate
+
+const benchmarkTestTemplate = `{{$b := benchmarkVar .Function}}{{generatedMarker .Function}}
+func Benchmark{{.Function.Name}}({{$b}} *testing.B) {
+	{{if hasSizedParams .Function}}for _, benchSize := range []struct {
+		name string
+		n    int
+	}{ {{- range .BenchmarkSizes}}
+		{"{{.Name}}", {{.N}}},{{end}}
+	} {
+		{{range .Function.Parameters}}{{.Name}} := {{benchmarkValue $.Function . "benchSize.n"}}
+		{{end}}{{if .Function.IsMethod}}receiver := {{receiverValue .Function}}
+		{{end}}
+		{{$b}}.Run(benchSize.name, func({{$b}} *testing.B) {
+			{{$b}}.ReportAllocs()
+			{{$b}}.ResetTimer()
+			for i := 0; i < {{$b}}.N; i++ {
+				{{if .Function.ReturnTypes}}{{range $i, $returnType := .Function.ReturnTypes}}{{if $i}}, {{end}}_{{end}} = {{end}}{{if .Function.IsMethod}}receiver.{{end}}{{.Function.Name}}({{range $i, $param := .Function.Parameters}}{{if $i}}, {{end}}{{$param.Name}}{{spread $param.Type}}{{end}})
+			}
+		})
+	}{{else}}{{range .Function.Parameters}}{{.Name}} := {{paramValue $.Function . "positive"}}
+	{{end}}{{if .Function.IsMethod}}receiver := {{receiverValue .Function}}
+	{{end}}
+	{{$b}}.ReportAllocs()
+	{{$b}}.ResetTimer()
+	for i := 0; i < {{$b}}.N; i++ {
+		{{if .Function.ReturnTypes}}{{range $i, $returnType := .Function.ReturnTypes}}{{if $i}}, {{end}}_{{end}} = {{end}}{{if .Function.IsMethod}}receiver.{{end}}{{.Function.Name}}({{range $i, $param := .Function.Parameters}}{{if $i}}, {{end}}{{$param.Name}}{{spread $param.Type}}{{end}})
+	}{{end}}
 }`
 
 const methodTestTemplate = functionTestTemplate
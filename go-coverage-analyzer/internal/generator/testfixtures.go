@@ -0,0 +1,78 @@
+package generator
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/beck/go-coverage-analyzer/pkg/models"
+)
+
+// testFixtureValues are the expressions tests reach fixtures through when they set them up themselves
+var testFixtureValues = map[string]string{
+	models.FixtureTempDir: "tempDir",
+	models.FixtureServer:  "server.URL",
+}
+
+// testServerSetup starts a test server closed by t.Cleanup
+const testServerSetup = `server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)`
+
+// usesTestFixtures reports whether tests of a function set up their fixtures themselves, which they do
+// unless a TestMain or suite shares them; SQL and HTTP tests fake their dependencies instead
+func (te *TemplateEngine) usesTestFixtures(function *models.Function) bool {
+	if len(function.Fixtures) == 0 || function.SQL != nil || function.HTTP != nil {
+		return false
+	}
+	_, shared := te.fixtureValues[filepath.Dir(function.File)]
+	return !shared
+}
+
+// testFixtureSetup returns the statements a test starts with to set up the fixtures its cases use, left to the
+// testing package to clean up, and the imports they need; testFixtureValues must already be applied to the cases
+func (te *TemplateEngine) testFixtureSetup(function *models.Function, testCases []TestCaseData) (string, []string) {
+	if !te.usesTestFixtures(function) {
+		return "", nil
+	}
+
+	var setup, imports []string
+	for _, name := range function.EnvVars {
+		setup = append(setup, fmt.Sprintf("t.Setenv(%q, %q)", name, "test"))
+	}
+
+	// Only declare fixtures some input refers to, unused variables do not compile
+	if casesUse(testCases, testFixtureValues[models.FixtureTempDir]) {
+		setup = append(setup, "tempDir := t.TempDir()")
+	}
+	if casesUse(testCases, testFixtureValues[models.FixtureServer]) {
+		setup = append(setup, testServerSetup)
+		imports = append(imports, "net/http", "net/http/httptest")
+	}
+
+	return strings.Join(setup, "\n\t"), imports
+}
+
+// testFixtureImports returns the imports of the fixtures tests of a function set up themselves
+func (te *TemplateEngine) testFixtureImports(function *models.Function, style string) []string {
+	if !te.usesTestFixtures(function) {
+		return nil
+	}
+	testCases := te.generateTestCases(function, style)
+	applyFixtureValues(testCases, testFixtureValues)
+	_, imports := te.testFixtureSetup(function, testCases)
+	return imports
+}
+
+// casesUse reports whether any input of the cases starts with an expression
+func casesUse(testCases []TestCaseData, expression string) bool {
+	for _, testCase := range testCases {
+		for _, input := range testCase.Inputs {
+			if strings.HasPrefix(input.Value, expression) {
+				return true
+			}
+		}
+	}
+	return false
+}
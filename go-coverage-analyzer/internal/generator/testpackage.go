@@ -0,0 +1,275 @@
+package generator
+
+import (
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/beck/go-coverage-analyzer/internal/i18n"
+	"github.com/beck/go-coverage-analyzer/pkg/coverage"
+	"github.com/beck/go-coverage-analyzer/pkg/models"
+)
+
+// Test package modes, deciding which package generated tests are declared in
+const (
+	TestPackageSame     = "same"     // package foo, tests may reach unexported identifiers
+	TestPackageExternal = "external" // package foo_test, black-box tests of the exported API
+	TestPackageAuto     = "auto"     // external where most existing test files of the package are
+)
+
+// testPackage is the external test package the generated tests of a directory are declared in
+type testPackage struct {
+	name       string // package under test, which the tests import
+	importPath string
+	exported   map[string]bool // package-level identifiers, qualified with the package name in tests
+	unexported map[string]bool // package-level identifiers tests cannot reach
+}
+
+// planTestPackages decides which package directories get external tests, and leaves out of them the
+// functions such tests cannot reach
+func (tg *TestGenerator) planTestPackages(fileGroups map[string][]*models.Function) {
+	tg.testPackages = make(map[string]*testPackage)
+	if tg.options.TestPackageMode == "" || tg.options.TestPackageMode == TestPackageSame {
+		return
+	}
+
+	sourceFiles := make([]string, 0, len(fileGroups))
+	for sourceFile := range fileGroups {
+		sourceFiles = append(sourceFiles, sourceFile)
+	}
+	sort.Strings(sourceFiles)
+
+	for _, sourceFile := range sourceFiles {
+		functions := fileGroups[sourceFile]
+		dir := filepath.Dir(sourceFile)
+		pkg, planned := tg.testPackages[dir]
+		if !planned {
+			pkg = tg.planTestPackage(dir, functions[0].Package)
+			tg.testPackages[dir] = pkg
+		}
+		if pkg == nil {
+			continue
+		}
+
+		reachable := make([]*models.Function, 0, len(functions))
+		for _, function := range functions {
+			if pkg.reaches(function) {
+				reachable = append(reachable, function)
+			} else if tg.verbose {
+				i18n.Printf("⏭️ Skipping %s: external tests of %s cannot reach it\n", function.Key(), pkg.name)
+			}
+		}
+		if len(reachable) == 0 {
+			delete(fileGroups, sourceFile)
+		} else {
+			fileGroups[sourceFile] = reachable
+		}
+	}
+}
+
+// planTestPackage returns the external test package of a directory, or nil when its tests stay in
+// the package itself: in same mode, in auto mode unless most of its test files are external, and
+// for commands or packages outside a module, which a test package cannot import
+func (tg *TestGenerator) planTestPackage(dir, packageName string) *testPackage {
+	if packageName == "main" {
+		return nil
+	}
+
+	fullDir := filepath.Join(tg.options.ProjectPath, dir)
+	if tg.options.TestPackageMode == TestPackageAuto && !usesExternalTests(fullDir, packageName) {
+		return nil
+	}
+
+	importPath, ok := coverage.ImportPath(fullDir)
+	if !ok {
+		if tg.verbose {
+			i18n.Printf("⚠️ Keeping the tests of %s in package %s: no go.mod gives its import path\n", dir, packageName)
+		}
+		return nil
+	}
+
+	pkg := &testPackage{
+		name:       packageName,
+		importPath: importPath,
+		exported:   make(map[string]bool),
+		unexported: make(map[string]bool),
+	}
+
+	// Test files of the package count too, external tests see what export_test.go files declare
+	files, _ := filepath.Glob(filepath.Join(fullDir, "*.go"))
+	fset := token.NewFileSet()
+	for _, path := range files {
+		file, err := parser.ParseFile(fset, path, nil, parser.SkipObjectResolution)
+		if err != nil || file.Name.Name != packageName {
+			continue
+		}
+		for _, name := range packageLevelNames(file) {
+			if ast.IsExported(name) {
+				pkg.exported[name] = true
+			} else {
+				pkg.unexported[name] = true
+			}
+		}
+	}
+
+	return pkg
+}
+
+// usesExternalTests reports whether most test files in a directory declare its external test package
+func usesExternalTests(dir, packageName string) bool {
+	testFiles, _ := filepath.Glob(filepath.Join(dir, "*_test.go"))
+	external, internal := 0, 0
+	fset := token.NewFileSet()
+	for _, path := range testFiles {
+		file, err := parser.ParseFile(fset, path, nil, parser.PackageClauseOnly)
+		if err != nil {
+			continue
+		}
+		switch file.Name.Name {
+		case packageName + "_test":
+			external++
+		case packageName:
+			internal++
+		}
+	}
+	return external > internal
+}
+
+// packageLevelNames returns the names a file declares at package level, methods excluded
+func packageLevelNames(file *ast.File) []string {
+	names := make([]string, 0)
+	for _, decl := range file.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			if decl.Recv == nil {
+				names = append(names, decl.Name.Name)
+			}
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					names = append(names, spec.Name.Name)
+				case *ast.ValueSpec:
+					for _, name := range spec.Names {
+						names = append(names, name.Name)
+					}
+				}
+			}
+		}
+	}
+	return names
+}
+
+// reaches reports whether tests outside the package can call a function: it must be exported, a
+// method of an exported type, and take and return no unexported types of the package
+func (p *testPackage) reaches(function *models.Function) bool {
+	if !function.IsExported {
+		return false
+	}
+
+	receiver := strings.TrimPrefix(function.ReceiverType, "*")
+	if i := strings.Index(receiver, "["); i >= 0 {
+		receiver = receiver[:i]
+	}
+	if receiver != "" && !ast.IsExported(receiver) {
+		return false
+	}
+
+	types := append([]string{}, function.ReturnTypes...)
+	for _, param := range function.Parameters {
+		types = append(types, param.Type)
+	}
+	for _, typ := range types {
+		// Qualified names such as time.Duration belong to other packages
+		words := strings.FieldsFunc(typ, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && r != '.'
+		})
+		for _, word := range words {
+			if p.unexported[word] {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// inTestPackage moves generated source for a directory to its external test package, if it has one:
+// the package clause gets the _test suffix, identifiers of the package under test are qualified with
+// its name, and its import is added when anything uses it
+func (tg *TestGenerator) inTestPackage(dir, content string) (string, error) {
+	pkg := tg.testPackages[dir]
+	if pkg == nil {
+		return content, nil
+	}
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", content, parser.ParseComments)
+	if err != nil {
+		return "", fmt.Errorf("failed to move tests to package %s_test: %w", pkg.name, err)
+	}
+	if file.Name.Name != pkg.name {
+		return content, nil
+	}
+
+	offset := func(pos token.Pos) int {
+		return fset.Position(pos).Offset
+	}
+	edits := []sourceEdit{{offset(file.Name.Pos()), offset(file.Name.End()), pkg.name + "_test"}}
+
+	// Unresolved identifiers are the ones the file does not declare itself
+	qualified := false
+	for _, ident := range file.Unresolved {
+		if pkg.exported[ident.Name] {
+			edits = append(edits, sourceEdit{offset(ident.Pos()), offset(ident.Pos()), pkg.name + "."})
+			qualified = true
+		}
+	}
+	if qualified {
+		edits = append(edits, importEdit(file, content, offset, pkg.importPath))
+	}
+
+	// Applied from the end so the offsets of earlier edits stay valid
+	sort.SliceStable(edits, func(i, j int) bool {
+		return edits[i].start > edits[j].start
+	})
+	for _, edit := range edits {
+		content = content[:edit.start] + edit.text + content[edit.end:]
+	}
+
+	formatted, err := format.Source([]byte(content))
+	if err != nil {
+		return content, nil
+	}
+	return string(formatted), nil
+}
+
+// sourceEdit replaces the bytes between two offsets of a source file
+type sourceEdit struct {
+	start, end int
+	text       string
+}
+
+// importEdit adds an import to a file: to its first import declaration, or after the package clause
+// when it has none
+func importEdit(file *ast.File, content string, offset func(token.Pos) int, importPath string) sourceEdit {
+	spec := fmt.Sprintf("%q", importPath)
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.IMPORT {
+			continue
+		}
+		if genDecl.Lparen.IsValid() {
+			return sourceEdit{offset(genDecl.Rparen), offset(genDecl.Rparen), "\t" + spec + "\n"}
+		}
+		existing := content[offset(genDecl.Specs[0].Pos()):offset(genDecl.Specs[0].End())]
+		return sourceEdit{offset(genDecl.Pos()), offset(genDecl.End()), "import (\n\t" + existing + "\n\t" + spec + "\n)"}
+	}
+	return sourceEdit{offset(file.Name.End()), offset(file.Name.End()), "\n\nimport " + spec}
+}
@@ -0,0 +1,135 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/beck/go-coverage-analyzer/pkg/models"
+)
+
+// chanPrefixes are the ways a channel type can start, most specific first
+var chanPrefixes = []string{"<-chan ", "chan<- ", "chan "}
+
+// isChanType reports whether a type is a channel of any direction
+func isChanType(t string) bool {
+	for _, prefix := range chanPrefixes {
+		if strings.HasPrefix(t, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// isFuncType reports whether a type is a function type
+func isFuncType(t string) bool {
+	return strings.HasPrefix(t, "func(")
+}
+
+// isInterfaceOf reports whether a type is one of the named interfaces in a function's signature
+func isInterfaceOf(function *models.Function, t string) bool {
+	t = strings.TrimPrefix(t, "...")
+	for _, name := range function.Interfaces {
+		if name == t {
+			return true
+		}
+	}
+	return false
+}
+
+// chanValue makes a buffered channel, so a single send by the code under test does not block
+func chanValue(t string) string {
+	elem := t
+	for _, prefix := range chanPrefixes {
+		if strings.HasPrefix(t, prefix) {
+			elem = strings.TrimPrefix(t, prefix)
+			break
+		}
+	}
+	// A bidirectional channel is assignable to either direction
+	return fmt.Sprintf("make(chan %s, 1)", elem)
+}
+
+// funcStub renders a function literal of the given type that returns zero values
+func funcStub(t string) string {
+	results := funcResults(t)
+	if len(results) == 0 {
+		return t + " {}"
+	}
+
+	zeros := make([]string, len(results))
+	for i, result := range results {
+		zeros[i] = getZeroValue(result)
+	}
+	return fmt.Sprintf("%s { return %s }", t, strings.Join(zeros, ", "))
+}
+
+// interfaceValue renders an anonymous struct embedding the interface, which satisfies it
+// without a mock; calling its methods panics until the test provides an implementation
+func interfaceValue(t string) string {
+	return fmt.Sprintf("struct{ %s }{}", t)
+}
+
+// paramValue generates a value for a parameter, using the function's signature to recognize interfaces
+func paramValue(function *models.Function, param *models.Param, scenario string) string {
+	if isClockOf(function, param.Type) {
+		return fakeClockValue(param.Type)
+	}
+	if isInterfaceOf(function, param.Type) {
+		if elem := strings.TrimPrefix(param.Type, "..."); elem != param.Type {
+			return fmt.Sprintf("[]%s{%s}", elem, interfaceValue(elem))
+		}
+		return interfaceValue(param.Type)
+	}
+	return generateTestValue(param.Type, scenario)
+}
+
+// funcResults returns the result types of a function type like func(int) (string, error)
+func funcResults(t string) []string {
+	// Skip past the parameter list
+	depth := 0
+	end := -1
+	for i := len("func"); i < len(t); i++ {
+		switch t[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		}
+		if depth == 0 {
+			end = i
+			break
+		}
+	}
+	if end < 0 {
+		return nil
+	}
+
+	rest := strings.TrimSpace(t[end+1:])
+	if rest == "" {
+		return nil
+	}
+	if strings.HasPrefix(rest, "(") && strings.HasSuffix(rest, ")") {
+		return splitTopLevel(rest[1 : len(rest)-1])
+	}
+	return []string{rest}
+}
+
+// splitTopLevel splits a comma-separated type list, ignoring commas nested in brackets
+func splitTopLevel(list string) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i, r := range list {
+		switch r {
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, strings.TrimSpace(list[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, strings.TrimSpace(list[start:]))
+}
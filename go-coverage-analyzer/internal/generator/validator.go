@@ -1,32 +1,90 @@
 package generator
 
 import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"go/ast"
 	"go/parser"
 	"go/token"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/beck/go-coverage-analyzer/internal/command"
+	"github.com/beck/go-coverage-analyzer/internal/i18n"
 	"github.com/beck/go-coverage-analyzer/pkg/models"
 )
 
 // ValidationResult represents the result of test validation
 type ValidationResult struct {
-	Valid            bool          `json:"valid"`
-	CompilationTime  time.Duration `json:"compilation_time"`
-	ExecutionTime    time.Duration `json:"execution_time"`
-	TestsRun         int           `json:"tests_run"`
-	TestsPassed      int           `json:"tests_passed"`
-	TestsFailed      int           `json:"tests_failed"`
-	CoverageImproved float64       `json:"coverage_improved"`
-	SyntaxErrors     []string      `json:"syntax_errors,omitempty"`
-	CompileErrors    []string      `json:"compile_errors,omitempty"`
-	RuntimeErrors    []string      `json:"runtime_errors,omitempty"`
-	Warnings         []string      `json:"warnings,omitempty"`
+	Valid            bool              `json:"valid"`
+	CompilationTime  time.Duration     `json:"compilation_time"`
+	ExecutionTime    time.Duration     `json:"execution_time"`
+	TestsRun         int               `json:"tests_run"`
+	TestsPassed      int               `json:"tests_passed"`
+	TestsFailed      int               `json:"tests_failed"`
+	CoverageImproved float64           `json:"coverage_improved"`
+	TimedOut         bool              `json:"timed_out,omitempty"`       // a go command ran past the command timeout
+	CompileChecked   bool              `json:"compile_checked,omitempty"` // the compilation step ran
+	TestsExecuted    bool              `json:"tests_executed,omitempty"`  // the go test run ran
+	Files            []*FileValidation `json:"files,omitempty"`
+	SyntaxErrors     []string          `json:"syntax_errors,omitempty"`
+	CompileErrors    []string          `json:"compile_errors,omitempty"`
+	RuntimeErrors    []string          `json:"runtime_errors,omitempty"`
+	Warnings         []string          `json:"warnings,omitempty"`
+	FlakyTests       []*FlakyTest      `json:"flaky_tests,omitempty"`
+	Findings         []*Finding        `json:"findings,omitempty"`
+}
+
+// FileValidation is the syntax check outcome of one validated test file
+type FileValidation struct {
+	Path        string `json:"path"`
+	SyntaxError string `json:"syntax_error,omitempty"`
+}
+
+// Finding is a quality issue tied to a location in a test file
+type Finding struct {
+	Rule     string `json:"rule"`
+	Severity string `json:"severity,omitempty"` // error, warning or info, see RuleSeverity
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Message  string `json:"message"`
+}
+
+// Quality check rule identifiers
+const (
+	RuleEmptyTest           = "empty-test"
+	RuleMissingAssertions   = "missing-assertions"
+	RuleTableTestMissingRun = "table-test-missing-run"
+)
+
+// FlakyTest records a test that both passed and failed across repeated runs
+type FlakyTest struct {
+	Package     string  `json:"package"`
+	Name        string  `json:"name"`
+	Runs        int     `json:"runs"`
+	Failures    int     `json:"failures"`
+	FailureRate float64 `json:"failure_rate"`
+}
+
+// ValidationOptions configures optional validation passes
+type ValidationOptions struct {
+	DetectFlaky int           // number of repeated runs for flaky detection, < 2 disables it
+	Race        bool          // run tests with the race detector
+	Shuffle     bool          // randomize test order on each repeated run
+	Count       int           // go test -count, 0 leaves the go default
+	Timeout     time.Duration // go test -timeout, 0 leaves the go default
+	Run         string        // go test -run filter
+	ExtraFlags  []string      // additional flags passed through to go test
+	Smells      SmellOptions  // thresholds of the test smell checks
+
+	// CommandTimeout bounds each go build and go test run as a whole, 0 for none
+	CommandTimeout time.Duration
 }
 
 // TestValidator validates generated tests for correctness and quality
@@ -34,6 +92,7 @@ type TestValidator struct {
 	fileSet     *token.FileSet
 	projectPath string
 	verbose     bool
+	options     *ValidationOptions
 }
 
 // NewTestValidator creates a new test validator
@@ -42,13 +101,22 @@ func NewTestValidator(projectPath string, verbose bool) *TestValidator {
 		fileSet:     token.NewFileSet(),
 		projectPath: projectPath,
 		verbose:     verbose,
+		options:     &ValidationOptions{},
 	}
 }
 
-// ValidateTests performs comprehensive validation of generated tests
-func (tv *TestValidator) ValidateTests(result *models.GenerationResult) (*ValidationResult, error) {
+// SetOptions configures optional validation passes such as flaky test detection
+func (tv *TestValidator) SetOptions(opts *ValidationOptions) {
+	if opts != nil {
+		tv.options = opts
+	}
+}
+
+// ValidateTests performs comprehensive validation of generated tests; cancelling ctx stops the go
+// commands it runs and returns ctx.Err()
+func (tv *TestValidator) ValidateTests(ctx context.Context, result *models.GenerationResult) (*ValidationResult, error) {
 	if tv.verbose {
-		fmt.Println("🔍 Starting test validation...")
+		i18n.Println("🔍 Starting test validation...")
 	}
 
 	startTime := time.Now()
@@ -62,63 +130,87 @@ func (tv *TestValidator) ValidateTests(result *models.GenerationResult) (*Valida
 
 	// Step 1: Syntax validation
 	if tv.verbose {
-		fmt.Println("🔍 Validating syntax...")
+		i18n.Println("🔍 Validating syntax...")
 	}
 
 	syntaxValid := tv.validateSyntax(result, validationResult)
 	if !syntaxValid {
 		validationResult.Valid = false
 		if tv.verbose {
-			fmt.Printf("❌ Syntax validation failed with %d errors\n", len(validationResult.SyntaxErrors))
+			i18n.Printf("❌ Syntax validation failed with %d errors\n", len(validationResult.SyntaxErrors))
 		}
 		return validationResult, nil
 	}
 
 	// Step 2: Compilation validation
 	if tv.verbose {
-		fmt.Println("🔍 Validating compilation...")
+		i18n.Println("🔍 Validating compilation...")
 	}
 
 	compileStart := time.Now()
-	compileValid := tv.validateCompilation(result, validationResult)
+	compileValid := tv.validateCompilation(ctx, result, validationResult)
 	validationResult.CompilationTime = time.Since(compileStart)
+	validationResult.CompileChecked = true
+	if ctx.Err() != nil {
+		return nil, fmt.Errorf("validation interrupted: %w", ctx.Err())
+	}
 
 	if !compileValid {
 		validationResult.Valid = false
 		if tv.verbose {
-			fmt.Printf("❌ Compilation validation failed with %d errors\n", len(validationResult.CompileErrors))
+			i18n.Printf("❌ Compilation validation failed with %d errors\n", len(validationResult.CompileErrors))
 		}
 		return validationResult, nil
 	}
 
 	// Step 3: Execution validation (if compilation passed)
 	if tv.verbose {
-		fmt.Println("🔍 Validating test execution...")
+		i18n.Println("🔍 Validating test execution...")
 	}
 
 	execStart := time.Now()
-	execValid := tv.validateExecution(result, validationResult)
+	execValid := tv.validateExecution(ctx, result, validationResult)
 	validationResult.ExecutionTime = time.Since(execStart)
+	validationResult.TestsExecuted = true
+	if ctx.Err() != nil {
+		return nil, fmt.Errorf("validation interrupted: %w", ctx.Err())
+	}
 
 	if !execValid {
 		validationResult.Valid = false
 		if tv.verbose {
-			fmt.Printf("❌ Execution validation failed with %d errors\n", len(validationResult.RuntimeErrors))
+			i18n.Printf("❌ Execution validation failed with %d errors\n", len(validationResult.RuntimeErrors))
+		}
+	}
+
+	// Step 3.5: Flaky test detection (repeated runs)
+	if tv.options.DetectFlaky > 1 {
+		if tv.verbose {
+			i18n.Printf("🔍 Detecting flaky tests over %d runs...\n", tv.options.DetectFlaky)
+		}
+
+		tv.detectFlakyTests(ctx, result, validationResult)
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("validation interrupted: %w", ctx.Err())
+		}
+
+		if tv.verbose && len(validationResult.FlakyTests) > 0 {
+			i18n.Printf("⚠️ Found %d flaky tests\n", len(validationResult.FlakyTests))
 		}
 	}
 
 	// Step 4: Quality checks
 	if tv.verbose {
-		fmt.Println("🔍 Running quality checks...")
+		i18n.Println("🔍 Running quality checks...")
 	}
 
 	tv.runQualityChecks(result, validationResult)
 
 	if tv.verbose {
 		if validationResult.Valid {
-			fmt.Printf("✅ All validations passed in %v\n", time.Since(startTime))
+			i18n.Printf("✅ All validations passed in %v\n", time.Since(startTime))
 		} else {
-			fmt.Printf("❌ Validation completed with issues in %v\n", time.Since(startTime))
+			i18n.Printf("❌ Validation completed with issues in %v\n", time.Since(startTime))
 		}
 	}
 
@@ -133,14 +225,17 @@ func (tv *TestValidator) validateSyntax(result *models.GenerationResult, validat
 		fullPath := filepath.Join(tv.projectPath, generatedFile.Path)
 
 		if tv.verbose {
-			fmt.Printf("🔍 Checking syntax: %s\n", generatedFile.Path)
+			i18n.Printf("🔍 Checking syntax: %s\n", generatedFile.Path)
 		}
 
+		fileValidation := &FileValidation{Path: generatedFile.Path}
+		validationResult.Files = append(validationResult.Files, fileValidation)
+
 		// Read the generated file
 		src, err := os.ReadFile(fullPath)
 		if err != nil {
-			validationResult.SyntaxErrors = append(validationResult.SyntaxErrors,
-				fmt.Sprintf("Failed to read %s: %v", generatedFile.Path, err))
+			fileValidation.SyntaxError = fmt.Sprintf("Failed to read %s: %v", generatedFile.Path, err)
+			validationResult.SyntaxErrors = append(validationResult.SyntaxErrors, fileValidation.SyntaxError)
 			allValid = false
 			continue
 		}
@@ -148,8 +243,8 @@ func (tv *TestValidator) validateSyntax(result *models.GenerationResult, validat
 		// Parse the file for syntax errors
 		_, err = parser.ParseFile(tv.fileSet, fullPath, src, parser.ParseComments)
 		if err != nil {
-			validationResult.SyntaxErrors = append(validationResult.SyntaxErrors,
-				fmt.Sprintf("Syntax error in %s: %v", generatedFile.Path, err))
+			fileValidation.SyntaxError = fmt.Sprintf("Syntax error in %s: %v", generatedFile.Path, err)
+			validationResult.SyntaxErrors = append(validationResult.SyntaxErrors, fileValidation.SyntaxError)
 			allValid = false
 		}
 	}
@@ -158,12 +253,11 @@ func (tv *TestValidator) validateSyntax(result *models.GenerationResult, validat
 }
 
 // validateCompilation checks if generated tests compile successfully
-func (tv *TestValidator) validateCompilation(result *models.GenerationResult, validationResult *ValidationResult) bool {
-	// Use go build to check compilation
-	cmd := exec.Command("go", "build", "-o", "/dev/null", "./...")
-	cmd.Dir = tv.projectPath
+func (tv *TestValidator) validateCompilation(ctx context.Context, result *models.GenerationResult, validationResult *ValidationResult) bool {
+	packages := packagePatterns(result)
 
-	output, err := cmd.CombinedOutput()
+	// Use go build to check compilation
+	output, err := tv.runGo(ctx, validationResult, append([]string{"build", "-o", os.DevNull}, packages...)...)
 	if err != nil {
 		validationResult.CompileErrors = append(validationResult.CompileErrors,
 			fmt.Sprintf("Compilation failed: %v\nOutput: %s", err, string(output)))
@@ -171,10 +265,7 @@ func (tv *TestValidator) validateCompilation(result *models.GenerationResult, va
 	}
 
 	// Also specifically check test compilation
-	cmd = exec.Command("go", "test", "-c", "-o", "/dev/null", "./...")
-	cmd.Dir = tv.projectPath
-
-	output, err = cmd.CombinedOutput()
+	output, err = tv.runGo(ctx, validationResult, append([]string{"test", "-c", "-o", os.DevNull}, packages...)...)
 	if err != nil {
 		validationResult.CompileErrors = append(validationResult.CompileErrors,
 			fmt.Sprintf("Test compilation failed: %v\nOutput: %s", err, string(output)))
@@ -185,17 +276,23 @@ func (tv *TestValidator) validateCompilation(result *models.GenerationResult, va
 }
 
 // validateExecution runs the generated tests to ensure they execute properly
-func (tv *TestValidator) validateExecution(result *models.GenerationResult, validationResult *ValidationResult) bool {
+func (tv *TestValidator) validateExecution(ctx context.Context, result *models.GenerationResult, validationResult *ValidationResult) bool {
 	// Run tests with verbose output to get detailed results
-	cmd := exec.Command("go", "test", "-v", "./...")
-	cmd.Dir = tv.projectPath
+	args := append([]string{"test", "-v"}, tv.testFlags()...)
+	args = append(args, packagePatterns(result)...)
 
-	output, err := cmd.CombinedOutput()
+	output, err := tv.runGo(ctx, validationResult, args...)
 	outputStr := string(output)
 
 	// Parse test results
 	tv.parseTestResults(outputStr, validationResult)
 
+	// Surface data races as individual runtime errors
+	for _, report := range extractRaceReports(outputStr) {
+		validationResult.RuntimeErrors = append(validationResult.RuntimeErrors,
+			fmt.Sprintf("Data race detected:\n%s", report))
+	}
+
 	if err != nil {
 		validationResult.RuntimeErrors = append(validationResult.RuntimeErrors,
 			fmt.Sprintf("Test execution failed: %v\nOutput: %s", err, outputStr))
@@ -212,6 +309,191 @@ func (tv *TestValidator) validateExecution(result *models.GenerationResult, vali
 	return true
 }
 
+// runGo runs a go command in the project within the command timeout and returns its combined output;
+// a timeout is flagged on the result and returned as the error
+func (tv *TestValidator) runGo(ctx context.Context, validationResult *ValidationResult, args ...string) ([]byte, error) {
+	ctx, cancel := command.WithTimeout(ctx, tv.options.CommandTimeout)
+	defer cancel()
+
+	cmd := command.Go(ctx, args...)
+	cmd.Dir = tv.projectPath
+
+	output, err := cmd.CombinedOutput()
+	err = command.Err(ctx, err)
+
+	var timeout *command.TimeoutError
+	if errors.As(err, &timeout) {
+		validationResult.TimedOut = true
+	}
+	return output, err
+}
+
+// packagePatterns returns the package directories containing the files under validation
+func packagePatterns(result *models.GenerationResult) []string {
+	seen := make(map[string]bool)
+	patterns := make([]string, 0)
+
+	for _, generatedFile := range result.GeneratedFiles {
+		dir := filepath.ToSlash(filepath.Dir(generatedFile.Path))
+		pattern := "./" + dir
+		if dir == "." {
+			pattern = "."
+		}
+
+		if !seen[pattern] {
+			seen[pattern] = true
+			patterns = append(patterns, pattern)
+		}
+	}
+
+	if len(patterns) == 0 {
+		return []string{"./..."}
+	}
+
+	sort.Strings(patterns)
+	return patterns
+}
+
+// testFlags builds the go test flags shared by every test run
+func (tv *TestValidator) testFlags() []string {
+	flags := make([]string, 0)
+
+	if tv.options.Race {
+		flags = append(flags, "-race")
+	}
+	if tv.options.Count > 0 {
+		flags = append(flags, fmt.Sprintf("-count=%d", tv.options.Count))
+	}
+	if tv.options.Timeout > 0 {
+		flags = append(flags, fmt.Sprintf("-timeout=%s", tv.options.Timeout))
+	}
+	if tv.options.Run != "" {
+		flags = append(flags, "-run", tv.options.Run)
+	}
+
+	return append(flags, tv.options.ExtraFlags...)
+}
+
+// extractRaceReports returns each "WARNING: DATA RACE" block from go test output
+func extractRaceReports(output string) []string {
+	const separator = "=================="
+
+	reports := make([]string, 0)
+	var current strings.Builder
+	inReport := false
+
+	for _, line := range strings.Split(output, "\n") {
+		if strings.HasPrefix(line, "WARNING: DATA RACE") {
+			inReport = true
+			current.Reset()
+		}
+
+		if !inReport {
+			continue
+		}
+
+		if strings.HasPrefix(line, separator) {
+			reports = append(reports, strings.TrimSpace(current.String()))
+			inReport = false
+			continue
+		}
+
+		current.WriteString(line)
+		current.WriteString("\n")
+	}
+
+	return reports
+}
+
+// testEvent is a single line of `go test -json` output
+type testEvent struct {
+	Action  string `json:"Action"`
+	Package string `json:"Package"`
+	Test    string `json:"Test"`
+}
+
+// detectFlakyTests runs the test suite repeatedly and records tests whose outcome changes between runs
+func (tv *TestValidator) detectFlakyTests(ctx context.Context, result *models.GenerationResult, validationResult *ValidationResult) {
+	// Each repeated run must execute the tests exactly once
+	args := append([]string{"test", "-json"}, tv.testFlags()...)
+	args = append(args, "-count=1")
+	if tv.options.Shuffle {
+		args = append(args, "-shuffle=on")
+	}
+	args = append(args, packagePatterns(result)...)
+
+	type outcome struct {
+		pkg, name      string
+		runs, failures int
+	}
+	outcomes := make(map[string]*outcome)
+	order := make([]string, 0)
+
+	for run := 1; run <= tv.options.DetectFlaky && ctx.Err() == nil; run++ {
+		if tv.verbose {
+			i18n.Printf("🔁 Flaky detection run %d/%d\n", run, tv.options.DetectFlaky)
+		}
+
+		runCtx, cancel := command.WithTimeout(ctx, tv.options.CommandTimeout)
+		cmd := command.Go(runCtx, args...)
+		cmd.Dir = tv.projectPath
+
+		// A non-zero exit is expected when tests fail; results come from the JSON events
+		output, err := cmd.Output()
+		err = command.Err(runCtx, err)
+		cancel()
+
+		var timeout *command.TimeoutError
+		if errors.As(err, &timeout) {
+			validationResult.TimedOut = true
+			validationResult.Warnings = append(validationResult.Warnings,
+				fmt.Sprintf("Flaky detection run %d %s", run, err))
+		}
+
+		scanner := bufio.NewScanner(bytes.NewReader(output))
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			var event testEvent
+			if err := json.Unmarshal(scanner.Bytes(), &event); err != nil || event.Test == "" {
+				continue
+			}
+			if event.Action != "pass" && event.Action != "fail" {
+				continue
+			}
+
+			key := event.Package + "." + event.Test
+			o, exists := outcomes[key]
+			if !exists {
+				o = &outcome{pkg: event.Package, name: event.Test}
+				outcomes[key] = o
+				order = append(order, key)
+			}
+			o.runs++
+			if event.Action == "fail" {
+				o.failures++
+			}
+		}
+	}
+
+	for _, key := range order {
+		o := outcomes[key]
+		if o.failures == 0 || o.failures == o.runs {
+			continue
+		}
+
+		flaky := &FlakyTest{
+			Package:     o.pkg,
+			Name:        o.name,
+			Runs:        o.runs,
+			Failures:    o.failures,
+			FailureRate: float64(o.failures) / float64(o.runs) * 100,
+		}
+		validationResult.FlakyTests = append(validationResult.FlakyTests, flaky)
+		validationResult.Warnings = append(validationResult.Warnings,
+			fmt.Sprintf("Flaky test %s in %s failed %d of %d runs", flaky.Name, flaky.Package, flaky.Failures, flaky.Runs))
+	}
+}
+
 // parseTestResults parses go test output to extract test statistics
 func (tv *TestValidator) parseTestResults(output string, validationResult *ValidationResult) {
 	lines := strings.Split(output, "\n")
@@ -258,7 +540,7 @@ func (tv *TestValidator) runQualityChecks(result *models.GenerationResult, valid
 	}
 }
 
-// checkTestQuality checks the quality of individual test files
+// checkTestQuality checks a test file for quality issues and test smells
 func (tv *TestValidator) checkTestQuality(generatedFile *models.GeneratedFile, validationResult *ValidationResult) {
 	fullPath := filepath.Join(tv.projectPath, generatedFile.Path)
 
@@ -272,91 +554,27 @@ func (tv *TestValidator) checkTestQuality(generatedFile *models.GeneratedFile, v
 		return
 	}
 
-	// Check for common quality issues
-	ast.Inspect(file, func(n ast.Node) bool {
-		switch node := n.(type) {
-		case *ast.FuncDecl:
-			if strings.HasPrefix(node.Name.Name, "Test") {
-				tv.checkTestFunction(node, generatedFile.Path, validationResult)
-			}
-		}
-		return true
-	})
-}
-
-// checkTestFunction checks the quality of a specific test function
-func (tv *TestValidator) checkTestFunction(funcDecl *ast.FuncDecl, filePath string, validationResult *ValidationResult) {
-	// Check if test function has proper structure
-	if funcDecl.Body == nil || len(funcDecl.Body.List) == 0 {
-		validationResult.Warnings = append(validationResult.Warnings,
-			fmt.Sprintf("Empty test function %s in %s", funcDecl.Name.Name, filePath))
-		return
-	}
-
-	// Check for proper test setup (t.Run, assertions, etc.)
-	hasAssertions := false
-	hasTestCases := false
-
-	ast.Inspect(funcDecl, func(n ast.Node) bool {
-		switch node := n.(type) {
-		case *ast.CallExpr:
-			if ident, ok := node.Fun.(*ast.Ident); ok {
-				// Look for testing patterns
-				if strings.Contains(ident.Name, "Error") ||
-					strings.Contains(ident.Name, "Fail") ||
-					strings.Contains(ident.Name, "Fatal") {
-					hasAssertions = true
-				}
-			}
-			if selExpr, ok := node.Fun.(*ast.SelectorExpr); ok {
-				if selExpr.Sel.Name == "Run" {
-					hasTestCases = true
-				}
-			}
-		}
-		return true
-	})
-
-	// Report quality issues
-	if !hasAssertions {
-		validationResult.Warnings = append(validationResult.Warnings,
-			fmt.Sprintf("Test function %s in %s lacks proper assertions", funcDecl.Name.Name, filePath))
-	}
-
-	if !hasTestCases && strings.Contains(funcDecl.Name.Name, "Table") {
-		validationResult.Warnings = append(validationResult.Warnings,
-			fmt.Sprintf("Table test function %s in %s lacks t.Run calls", funcDecl.Name.Name, filePath))
+	checker := newSmellChecker(tv.fileSet, generatedFile.Path, &tv.options.Smells)
+	checker.results = loadPackageResults(filepath.Dir(fullPath))
+	checker.checkFile(file)
+	for _, finding := range checker.findings {
+		tv.addFinding(validationResult, finding)
 	}
 }
 
-// ValidateIndividualTest validates a single test file
-func (tv *TestValidator) ValidateIndividualTest(testFilePath string) (*ValidationResult, error) {
-	result := &ValidationResult{
-		Valid:         true,
-		SyntaxErrors:  make([]string, 0),
-		CompileErrors: make([]string, 0),
-		RuntimeErrors: make([]string, 0),
-		Warnings:      make([]string, 0),
-	}
-
-	// Create a minimal generation result for validation
-	generatedFile := &models.GeneratedFile{
-		Path: testFilePath,
-	}
+// addFinding records a located quality finding and its warning message
+func (tv *TestValidator) addFinding(validationResult *ValidationResult, finding *Finding) {
+	validationResult.Findings = append(validationResult.Findings, finding)
+	validationResult.Warnings = append(validationResult.Warnings, finding.Message)
+}
 
+// ValidateIndividualTest validates a single test file, relative to the project, as ValidateTests does:
+// its syntax, then the build and run of its package alone, then the quality of its tests
+func (tv *TestValidator) ValidateIndividualTest(ctx context.Context, testFilePath string) (*ValidationResult, error) {
 	genResult := &models.GenerationResult{
-		GeneratedFiles: []*models.GeneratedFile{generatedFile},
+		GeneratedFiles: []*models.GeneratedFile{{Path: testFilePath}},
 	}
-
-	// Run syntax validation
-	if !tv.validateSyntax(genResult, result) {
-		result.Valid = false
-	}
-
-	// Run quality checks
-	tv.runQualityChecks(genResult, result)
-
-	return result, nil
+	return tv.ValidateTests(ctx, genResult)
 }
 
 // parseFloat parses a string to float64, returns 0 on error
@@ -376,56 +594,67 @@ func parseFloat(s string) (float64, error) {
 func (tv *TestValidator) GetValidationSummary(result *ValidationResult) string {
 	var summary strings.Builder
 
-	summary.WriteString("🔍 Test Validation Summary\n")
+	summary.WriteString(i18n.T("🔍 Test Validation Summary\n"))
 	summary.WriteString("========================\n")
 
 	if result.Valid {
-		summary.WriteString("✅ Overall Status: PASSED\n")
+		summary.WriteString(i18n.T("✅ Overall Status: PASSED\n"))
 	} else {
-		summary.WriteString("❌ Overall Status: FAILED\n")
+		summary.WriteString(i18n.T("❌ Overall Status: FAILED\n"))
 	}
 
-	summary.WriteString(fmt.Sprintf("⏱️  Compilation Time: %v\n", result.CompilationTime))
-	summary.WriteString(fmt.Sprintf("⏱️  Execution Time: %v\n", result.ExecutionTime))
+	summary.WriteString(i18n.Sprintf("⏱️  Compilation Time: %v\n", result.CompilationTime))
+	summary.WriteString(i18n.Sprintf("⏱️  Execution Time: %v\n", result.ExecutionTime))
+	if result.TimedOut {
+		summary.WriteString(i18n.T("⏰ A go command timed out\n"))
+	}
 
 	if result.TestsRun > 0 {
-		summary.WriteString(fmt.Sprintf("🧪 Tests Run: %d\n", result.TestsRun))
-		summary.WriteString(fmt.Sprintf("✅ Tests Passed: %d\n", result.TestsPassed))
+		summary.WriteString(i18n.Sprintf("🧪 Tests Run: %d\n", result.TestsRun))
+		summary.WriteString(i18n.Sprintf("✅ Tests Passed: %d\n", result.TestsPassed))
 
 		if result.TestsFailed > 0 {
-			summary.WriteString(fmt.Sprintf("❌ Tests Failed: %d\n", result.TestsFailed))
+			summary.WriteString(i18n.Sprintf("❌ Tests Failed: %d\n", result.TestsFailed))
 		}
 
 		if result.CoverageImproved > 0 {
-			summary.WriteString(fmt.Sprintf("📈 Coverage: %.1f%%\n", result.CoverageImproved))
+			summary.WriteString(i18n.Sprintf("📈 Coverage: %.1f%%\n", result.CoverageImproved))
 		}
 	}
 
 	if len(result.SyntaxErrors) > 0 {
-		summary.WriteString(fmt.Sprintf("❌ Syntax Errors: %d\n", len(result.SyntaxErrors)))
+		summary.WriteString(i18n.Sprintf("❌ Syntax Errors: %d\n", len(result.SyntaxErrors)))
 		for _, err := range result.SyntaxErrors {
-			summary.WriteString(fmt.Sprintf("   • %s\n", err))
+			summary.WriteString(i18n.Sprintf("   • %s\n", err))
 		}
 	}
 
 	if len(result.CompileErrors) > 0 {
-		summary.WriteString(fmt.Sprintf("❌ Compile Errors: %d\n", len(result.CompileErrors)))
+		summary.WriteString(i18n.Sprintf("❌ Compile Errors: %d\n", len(result.CompileErrors)))
 		for _, err := range result.CompileErrors {
-			summary.WriteString(fmt.Sprintf("   • %s\n", err))
+			summary.WriteString(i18n.Sprintf("   • %s\n", err))
 		}
 	}
 
 	if len(result.RuntimeErrors) > 0 {
-		summary.WriteString(fmt.Sprintf("❌ Runtime Errors: %d\n", len(result.RuntimeErrors)))
+		summary.WriteString(i18n.Sprintf("❌ Runtime Errors: %d\n", len(result.RuntimeErrors)))
 		for _, err := range result.RuntimeErrors {
-			summary.WriteString(fmt.Sprintf("   • %s\n", err))
+			summary.WriteString(i18n.Sprintf("   • %s\n", err))
+		}
+	}
+
+	if len(result.FlakyTests) > 0 {
+		summary.WriteString(i18n.Sprintf("🎲 Flaky Tests: %d\n", len(result.FlakyTests)))
+		for _, flaky := range result.FlakyTests {
+			summary.WriteString(i18n.Sprintf("   • %s (%s): %.0f%% failure rate over %d runs\n",
+				flaky.Name, flaky.Package, flaky.FailureRate, flaky.Runs))
 		}
 	}
 
 	if len(result.Warnings) > 0 {
-		summary.WriteString(fmt.Sprintf("⚠️  Warnings: %d\n", len(result.Warnings)))
+		summary.WriteString(i18n.Sprintf("⚠️  Warnings: %d\n", len(result.Warnings)))
 		for _, warning := range result.Warnings {
-			summary.WriteString(fmt.Sprintf("   • %s\n", warning))
+			summary.WriteString(i18n.Sprintf("   • %s\n", warning))
 		}
 	}
 
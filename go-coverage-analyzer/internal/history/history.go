@@ -0,0 +1,140 @@
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/beck/go-coverage-analyzer/internal/storage"
+	"github.com/beck/go-coverage-analyzer/pkg/models"
+)
+
+// DefaultDir is the history directory used when none is configured, relative to the project
+const DefaultDir = ".gcov/history"
+
+// snapshotTimeFormat names snapshot files so they sort chronologically
+const snapshotTimeFormat = "20060102T150405Z"
+
+// Store persists coverage snapshots as JSON files in a directory or remote storage location
+type Store struct {
+	dir     string
+	backend storage.Backend
+	err     error // why the location could not be opened, returned by Save and Load
+}
+
+// NewStore creates a history store rooted at dir, a directory or an s3://, gs:// or http(s):// URL
+func NewStore(dir string) *Store {
+	backend, err := storage.Open(dir)
+	return &Store{dir: dir, backend: backend, err: err}
+}
+
+// Dir returns the directory or URL the store reads and writes
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+// Save writes a snapshot to the store
+func (s *Store) Save(snapshot *models.CoverageSnapshot) error {
+	if s.err != nil {
+		return s.err
+	}
+
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal snapshot: %w", err)
+	}
+
+	name := snapshot.Timestamp.UTC().Format(snapshotTimeFormat) + ".json"
+	if err := s.backend.Write(name, data); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+
+	return nil
+}
+
+// Load reads all snapshots from the store, oldest first
+func (s *Store) Load() ([]*models.CoverageSnapshot, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	names, err := s.backend.List()
+	if err != nil {
+		if storage.IsNotExist(err) {
+			return []*models.CoverageSnapshot{}, nil
+		}
+		return nil, fmt.Errorf("failed to read history directory: %w", err)
+	}
+
+	snapshots := make([]*models.CoverageSnapshot, 0, len(names))
+	for _, name := range names {
+		if !strings.HasSuffix(name, ".json") {
+			continue
+		}
+
+		data, err := s.backend.Read(name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read snapshot %s: %w", name, err)
+		}
+
+		var snapshot models.CoverageSnapshot
+		if err := json.Unmarshal(data, &snapshot); err != nil {
+			return nil, fmt.Errorf("failed to parse snapshot %s: %w", name, err)
+		}
+		snapshots = append(snapshots, &snapshot)
+	}
+
+	sort.SliceStable(snapshots, func(i, j int) bool {
+		return snapshots[i].Timestamp.Before(snapshots[j].Timestamp)
+	})
+
+	return snapshots, nil
+}
+
+// NewSnapshot captures the coverage of an analysis result along with the current git commit and tag
+func NewSnapshot(result *models.AnalysisResult, projectPath string) *models.CoverageSnapshot {
+	snapshot := &models.CoverageSnapshot{
+		Timestamp:        result.Timestamp,
+		Commit:           gitOutput(projectPath, "rev-parse", "--short", "HEAD"),
+		Tag:              gitOutput(projectPath, "describe", "--tags", "--exact-match"),
+		OverallCoverage:  result.OverallCoverage,
+		FunctionCoverage: result.FunctionCoverage,
+		Packages:         make(map[string]float64, len(result.PackageCoverage)),
+	}
+
+	if snapshot.Timestamp.IsZero() {
+		snapshot.Timestamp = time.Now()
+	}
+
+	for name, pkg := range result.PackageCoverage {
+		snapshot.Packages[name] = pkg.Coverage
+	}
+
+	return snapshot
+}
+
+// FilterTagged returns only the snapshots taken at a git tag
+func FilterTagged(snapshots []*models.CoverageSnapshot) []*models.CoverageSnapshot {
+	tagged := make([]*models.CoverageSnapshot, 0)
+	for _, snapshot := range snapshots {
+		if snapshot.Tag != "" {
+			tagged = append(tagged, snapshot)
+		}
+	}
+	return tagged
+}
+
+// gitOutput runs a git command in dir and returns its trimmed output, or "" on failure
+func gitOutput(dir string, args ...string) string {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+
+	output, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
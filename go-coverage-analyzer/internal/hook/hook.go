@@ -0,0 +1,92 @@
+// Package hook installs git hooks that check the coverage of the changes being committed or pushed
+// with gcov hook run
+package hook
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Hook types gcov installs
+const (
+	PreCommit = "pre-commit"
+	PrePush   = "pre-push"
+)
+
+// marker identifies hooks gcov installed, which it may replace
+const marker = "# Installed by gcov hook install"
+
+// Script returns the hook script running gcov hook run for the project, given relative to the root
+// of the repository, where git runs hooks
+func Script(hookType, projectDir string) string {
+	args := []string{"gcov", "hook", "run", "--stage", hookType}
+	if projectDir != "" && projectDir != "." {
+		args = append(args, "--project", shellQuote(filepath.ToSlash(projectDir)))
+	}
+
+	return "#!/bin/sh\n" +
+		marker + "; checks the coverage of the changed code\n" +
+		"exec " + strings.Join(args, " ") + "\n"
+}
+
+// Install writes the hook of a type into the hooks directory of the project's repository, honoring
+// core.hooksPath, and returns its path. A hook gcov did not install is only replaced with force
+func Install(projectPath, hookType string, force bool) (string, error) {
+	if hookType != PreCommit && hookType != PrePush {
+		return "", fmt.Errorf("unsupported hook type %q (valid: pre-commit, pre-push)", hookType)
+	}
+
+	root, err := gitOutput(projectPath, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", fmt.Errorf("%s is not in a git repository: %w", projectPath, err)
+	}
+	hooksDir, err := gitOutput(projectPath, "rev-parse", "--git-path", "hooks")
+	if err != nil {
+		return "", fmt.Errorf("failed to find the git hooks directory: %w", err)
+	}
+	if !filepath.IsAbs(hooksDir) {
+		hooksDir = filepath.Join(projectPath, hooksDir)
+	}
+
+	absProject, err := filepath.Abs(projectPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to get absolute path: %w", err)
+	}
+	projectDir, err := filepath.Rel(root, absProject)
+	if err != nil {
+		return "", fmt.Errorf("failed to locate %s in the repository: %w", projectPath, err)
+	}
+
+	hookPath := filepath.Join(hooksDir, hookType)
+	if existing, err := os.ReadFile(hookPath); err == nil && !force && !strings.Contains(string(existing), marker) {
+		return "", fmt.Errorf("%s already exists and was not installed by gcov (use --force to replace it)", hookPath)
+	}
+
+	if err := os.MkdirAll(hooksDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create hooks directory: %w", err)
+	}
+	if err := os.WriteFile(hookPath, []byte(Script(hookType, projectDir)), 0755); err != nil {
+		return "", fmt.Errorf("failed to write hook: %w", err)
+	}
+	return hookPath, nil
+}
+
+// gitOutput runs a git command in dir and returns its trimmed output
+func gitOutput(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+
+	output, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// shellQuote quotes a path for a POSIX shell
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,117 @@
+// Package i18n routes the user-facing messages of gcov through a message catalog, so they can be
+// translated, and through the plain output mode, which replaces emojis with ASCII tags
+package i18n
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// tags are the ASCII replacements of the emojis that carry a status in plain mode. Emojis that only
+// decorate a message are dropped along with the space after them
+var tags = []string{
+	"✅", "[OK]",
+	"⚠️", "[WARN]",
+	"❌", "[FAIL]",
+	"⏰", "[TIMEOUT]",
+	"⏭️", "[SKIP]",
+	"🎲", "[FLAKY]",
+	"📈", "[UP]",
+	"📉", "[DOWN]",
+	"➡️", "[SAME]",
+	"•", "-",
+	"→", "->",
+}
+
+// decorations are the emojis plain mode drops
+var decorations = []string{
+	"🔍", "🎭", "📊", "📋", "🎯", "🛠️", "👀", "🧬", "🕒", "📝", "🤖", "⏱️", "👥", "🧹", "📭",
+	"📦", "📣", "🪦", "📄", "🚧", "🧮", "🔧", "✏️", "✨", "📸", "🌐", "🧰", "🔁", "🧪", "🔓", "💾", "🩺",
+}
+
+var (
+	mu       sync.RWMutex
+	plain    bool
+	catalog  map[string]string
+	replacer = newPlainReplacer()
+)
+
+// newPlainReplacer builds the replacer plain mode applies to messages
+func newPlainReplacer() *strings.Replacer {
+	pairs := append([]string{}, tags...)
+	for _, emoji := range decorations {
+		// Wide emojis are followed by one or two spaces to line up with the text after them
+		pairs = append(pairs, emoji+"  ", "", emoji+" ", "", emoji, "")
+	}
+	return strings.NewReplacer(pairs...)
+}
+
+// SetPlain turns the plain output mode on or off
+func SetPlain(enabled bool) {
+	mu.Lock()
+	defer mu.Unlock()
+	plain = enabled
+}
+
+// Plain reports whether the plain output mode is on
+func Plain() bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	return plain
+}
+
+// LoadCatalog reads a message catalog: a JSON object mapping the English messages, format verbs and
+// emojis included, to their translations. Messages the catalog does not list stay in English
+func LoadCatalog(path string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read message catalog %s: %w", path, err)
+	}
+
+	messages := make(map[string]string)
+	if err := json.Unmarshal(content, &messages); err != nil {
+		return fmt.Errorf("failed to parse message catalog %s: %w", path, err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	catalog = messages
+	return nil
+}
+
+// T returns the translation of a message, with emojis replaced in plain mode
+func T(message string) string {
+	mu.RLock()
+	defer mu.RUnlock()
+	if translated, ok := catalog[message]; ok {
+		message = translated
+	}
+	if plain {
+		message = replacer.Replace(message)
+	}
+	return message
+}
+
+// Sprintf formats according to the translation of format
+func Sprintf(format string, args ...any) string {
+	return fmt.Sprintf(T(format), args...)
+}
+
+// Printf prints the translation of format to standard output
+func Printf(format string, args ...any) {
+	fmt.Print(Sprintf(format, args...))
+}
+
+// Fprintf prints the translation of format to w
+func Fprintf(w io.Writer, format string, args ...any) {
+	fmt.Fprint(w, Sprintf(format, args...))
+}
+
+// Println prints the translation of a message to standard output, followed by a newline
+func Println(message string) {
+	fmt.Println(T(message))
+}
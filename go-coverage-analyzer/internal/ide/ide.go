@@ -0,0 +1,308 @@
+// Package ide serves gcov to editor extensions with JSON-RPC 2.0 over stdin and stdout, one message
+// per line, keeping the analysis of the project warm between requests instead of starting the CLI
+// cold for every hover or code lens
+package ide
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/beck/go-coverage-analyzer/internal/analyzer"
+	"github.com/beck/go-coverage-analyzer/internal/generator"
+	"github.com/beck/go-coverage-analyzer/internal/reporter"
+	"github.com/beck/go-coverage-analyzer/pkg/models"
+)
+
+// Methods the server answers
+const (
+	MethodAnalyze             = "analyze"             // analyze the whole project and cache the result
+	MethodCoverageForFile     = "coverageForFile"     // coverage of every function of a file
+	MethodGenerateForFunction = "generateForFunction" // generate tests for one function
+	MethodShutdown            = "shutdown"            // answer, then stop serving
+)
+
+// JSON-RPC 2.0 error codes
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+	codeInternalError  = -32603
+)
+
+// maxMessageSize bounds a request line, generous for file paths and function names
+const maxMessageSize = 1 << 20
+
+// Options configures the server; the analysis and generation options are templates every request
+// starts from
+type Options struct {
+	ProjectPath string
+	Analysis    *analyzer.Options
+	Generation  *generator.Options
+}
+
+// Server answers editor requests, caching the last analysis of the whole project
+type Server struct {
+	opts        *Options
+	result      *models.AnalysisResult // last analysis of the whole project, nil before the first
+	analyzedAt  time.Time              // when it started, files changed since are analyzed again
+	profileTime time.Time              // modification time of the profile it read
+}
+
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"` // absent for notifications, which get no response
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *rpcError) Error() string {
+	return e.Message
+}
+
+// AnalyzeResult answers analyze
+type AnalyzeResult struct {
+	OverallCoverage    float64         `json:"overall_coverage"`
+	Summary            *models.Summary `json:"summary"`
+	UncoveredFunctions int             `json:"uncovered_functions"`
+	AnalysisTime       time.Duration   `json:"analysis_time"`
+}
+
+// FileParams are the parameters of coverageForFile
+type FileParams struct {
+	File string `json:"file"` // absolute, or relative to the project
+}
+
+// FileCoverage answers coverageForFile
+type FileCoverage struct {
+	File      string               `json:"file"` // relative to the project
+	Cached    bool                 `json:"cached"`
+	Functions []*models.FunctionAt `json:"functions"`
+}
+
+// GenerateParams are the parameters of generateForFunction
+type GenerateParams struct {
+	File      string `json:"file"`
+	Function  string `json:"function"` // Receiver.Name for methods, Name otherwise
+	DryRun    bool   `json:"dryRun"`
+	Overwrite bool   `json:"overwrite"`
+}
+
+// NewServer creates a server for a project
+func NewServer(opts *Options) *Server {
+	return &Server{opts: opts}
+}
+
+// Serve answers the requests read from in on out until in ends, shutdown is requested or ctx is
+// cancelled. Requests are answered one at a time, in order
+func (s *Server) Serve(ctx context.Context, in io.Reader, out io.Writer) error {
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxMessageSize)
+	encoder := json.NewEncoder(out)
+
+	for scanner.Scan() {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var req request
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			if err := encoder.Encode(errorResponse(nil, codeParseError, err.Error())); err != nil {
+				return err
+			}
+			continue
+		}
+
+		result, err := s.handle(ctx, &req)
+		if req.ID == nil {
+			if req.Method == MethodShutdown {
+				return nil
+			}
+			continue
+		}
+
+		resp := &response{JSONRPC: "2.0", ID: req.ID, Result: result}
+		if err != nil {
+			resp = errorResponse(req.ID, codeInternalError, err.Error())
+			if rpcErr, ok := err.(*rpcError); ok {
+				resp.Error = rpcErr
+			}
+		}
+		if err := encoder.Encode(resp); err != nil {
+			return err
+		}
+
+		if req.Method == MethodShutdown {
+			return nil
+		}
+	}
+	return scanner.Err()
+}
+
+// handle answers a request
+func (s *Server) handle(ctx context.Context, req *request) (any, error) {
+	if req.JSONRPC != "2.0" {
+		return nil, &rpcError{Code: codeInvalidRequest, Message: `jsonrpc must be "2.0"`}
+	}
+
+	switch req.Method {
+	case MethodAnalyze:
+		return s.analyze(ctx)
+	case MethodCoverageForFile:
+		var params FileParams
+		if err := decodeParams(req.Params, &params); err != nil {
+			return nil, err
+		}
+		return s.coverageForFile(ctx, &params)
+	case MethodGenerateForFunction:
+		var params GenerateParams
+		if err := decodeParams(req.Params, &params); err != nil {
+			return nil, err
+		}
+		return s.generateForFunction(ctx, &params)
+	case MethodShutdown:
+		return struct{}{}, nil
+	default:
+		return nil, &rpcError{Code: codeMethodNotFound, Message: fmt.Sprintf("unknown method %q", req.Method)}
+	}
+}
+
+// analyze analyzes the whole project and caches the result
+func (s *Server) analyze(ctx context.Context) (*AnalyzeResult, error) {
+	startTime := time.Now()
+	opts := *s.opts.Analysis
+	opts.ProjectPath = s.opts.ProjectPath
+
+	result, err := analyzer.Analyze(ctx, &opts)
+	if err != nil {
+		return nil, err
+	}
+
+	s.result, s.analyzedAt, s.profileTime = result, startTime, modTime(result.Metadata.ProfilePath)
+	return &AnalyzeResult{
+		OverallCoverage:    result.OverallCoverage,
+		Summary:            result.Summary,
+		UncoveredFunctions: len(result.UncoveredFunctions),
+		AnalysisTime:       result.Metadata.AnalysisTime,
+	}, nil
+}
+
+// coverageForFile reports the functions of a file, from the cached analysis when the file and the
+// profile did not change since, otherwise from a new analysis of the file alone
+func (s *Server) coverageForFile(ctx context.Context, params *FileParams) (*FileCoverage, error) {
+	file, err := s.relativeFile(params.File)
+	if err != nil {
+		return nil, err
+	}
+
+	result, cached, err := s.resultFor(ctx, file)
+	if err != nil {
+		return nil, err
+	}
+	return &FileCoverage{File: file, Cached: cached, Functions: reporter.FunctionsIn(result, file)}, nil
+}
+
+// generateForFunction generates tests for a function of a file
+func (s *Server) generateForFunction(ctx context.Context, params *GenerateParams) (*models.GenerationResult, error) {
+	if params.Function == "" {
+		return nil, &rpcError{Code: codeInvalidParams, Message: "function is required"}
+	}
+	file, err := s.relativeFile(params.File)
+	if err != nil {
+		return nil, err
+	}
+
+	result, _, err := s.resultFor(ctx, file)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := *s.opts.Generation
+	opts.ProjectPath = s.opts.ProjectPath
+	opts.Functions = []string{params.Function}
+	opts.DryRun = params.DryRun
+	opts.Overwrite = params.Overwrite
+	return generator.Generate(ctx, result, &opts)
+}
+
+// resultFor returns an analysis holding a file, and whether it is the cached one
+func (s *Server) resultFor(ctx context.Context, file string) (*models.AnalysisResult, bool, error) {
+	if s.result != nil && modTime(s.result.Metadata.ProfilePath).Equal(s.profileTime) &&
+		modTime(filepath.Join(s.opts.ProjectPath, file)).Before(s.analyzedAt) {
+		return s.result, true, nil
+	}
+
+	opts := *s.opts.Analysis
+	opts.ProjectPath = s.opts.ProjectPath
+	opts.Scope = file
+	result, err := analyzer.Analyze(ctx, &opts)
+	return result, false, err
+}
+
+// relativeFile returns a Go file of the request relative to the project
+func (s *Server) relativeFile(file string) (string, error) {
+	if file == "" || filepath.Ext(file) != ".go" {
+		return "", &rpcError{Code: codeInvalidParams, Message: fmt.Sprintf("%q is not a Go file", file)}
+	}
+	if !filepath.IsAbs(file) {
+		return filepath.Clean(file), nil
+	}
+
+	absProject, err := filepath.Abs(s.opts.ProjectPath)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Rel(absProject, file)
+}
+
+// decodeParams decodes the parameters of a request
+func decodeParams(params json.RawMessage, v any) error {
+	if len(params) == 0 {
+		return &rpcError{Code: codeInvalidParams, Message: "params are required"}
+	}
+	if err := json.Unmarshal(params, v); err != nil {
+		return &rpcError{Code: codeInvalidParams, Message: err.Error()}
+	}
+	return nil
+}
+
+// errorResponse builds the response of a failed request
+func errorResponse(id json.RawMessage, code int, message string) *response {
+	if id == nil {
+		id = json.RawMessage("null")
+	}
+	return &response{JSONRPC: "2.0", ID: id, Error: &rpcError{Code: code, Message: message}}
+}
+
+// modTime returns when a file was last modified, or the zero time when it does not exist
+func modTime(path string) time.Time {
+	if path == "" {
+		return time.Time{}
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
@@ -0,0 +1,134 @@
+package manifest
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/beck/go-coverage-analyzer/internal/fileutil"
+)
+
+// DefaultPath is the manifest file, relative to the project
+const DefaultPath = ".gcov/manifest.json"
+
+// Kinds of generated artifacts
+const (
+	KindTest   = "test"   // a test function within a test file
+	KindHelper = "helper" // a support file such as fake clocks, fixtures or the benchmark harness
+	KindMock   = "mock"   // a mock implementation of an interface
+)
+
+// Entry records an artifact gcov wrote: a whole file, or a single test within a test file
+type Entry struct {
+	Path      string    `json:"path"` // relative to the project, slash separated
+	Kind      string    `json:"kind"`
+	Test      string    `json:"test,omitempty"`
+	TestType  string    `json:"test_type,omitempty"`
+	Function  string    `json:"function,omitempty"`  // Receiver.Name or Name of the tested function
+	Signature string    `json:"signature,omitempty"` // signature of the tested function when the test was written
+	Version   string    `json:"version"`
+	Generated time.Time `json:"generated"`
+}
+
+// Manifest lists the artifacts gcov wrote to a project
+type Manifest struct {
+	Updated   time.Time `json:"updated"`
+	Artifacts []*Entry  `json:"artifacts"`
+}
+
+// Load reads the manifest of a project, returning an empty one when there is none yet
+func Load(projectPath string) (*Manifest, error) {
+	data, err := os.ReadFile(filepath.Join(projectPath, DefaultPath))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &Manifest{Artifacts: []*Entry{}}, nil
+		}
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+	return &manifest, nil
+}
+
+// Save writes the manifest to a project, artifacts sorted by path and test
+func (m *Manifest) Save(projectPath string) error {
+	sort.SliceStable(m.Artifacts, func(i, j int) bool {
+		if m.Artifacts[i].Path != m.Artifacts[j].Path {
+			return m.Artifacts[i].Path < m.Artifacts[j].Path
+		}
+		return m.Artifacts[i].Test < m.Artifacts[j].Test
+	})
+	m.Updated = time.Now()
+
+	path := filepath.Join(projectPath, DefaultPath)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create manifest directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal manifest: %w", err)
+	}
+
+	if err := fileutil.WriteAtomic(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+
+	return nil
+}
+
+// Record adds an artifact, replacing the entry for the same file and test
+func (m *Manifest) Record(entry *Entry) {
+	entry.Path = filepath.ToSlash(entry.Path)
+	for i, existing := range m.Artifacts {
+		if existing.Path == entry.Path && existing.Test == entry.Test {
+			m.Artifacts[i] = entry
+			return
+		}
+	}
+	m.Artifacts = append(m.Artifacts, entry)
+}
+
+// RemoveTest drops the entry of a test within a file
+func (m *Manifest) RemoveTest(path, test string) {
+	path = filepath.ToSlash(path)
+	m.remove(func(entry *Entry) bool {
+		return entry.Path == path && entry.Test == test
+	})
+}
+
+// RemoveFile drops every entry of a file
+func (m *Manifest) RemoveFile(path string) {
+	path = filepath.ToSlash(path)
+	m.remove(func(entry *Entry) bool {
+		return entry.Path == path
+	})
+}
+
+// Filter returns the artifacts of a kind for a function, empty arguments matching any
+func (m *Manifest) Filter(kind, function string) []*Entry {
+	entries := make([]*Entry, 0)
+	for _, entry := range m.Artifacts {
+		if (kind == "" || entry.Kind == kind) && (function == "" || entry.Function == function) {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// remove drops the entries matching a predicate
+func (m *Manifest) remove(match func(*Entry) bool) {
+	kept := m.Artifacts[:0]
+	for _, entry := range m.Artifacts {
+		if !match(entry) {
+			kept = append(kept, entry)
+		}
+	}
+	m.Artifacts = kept
+}
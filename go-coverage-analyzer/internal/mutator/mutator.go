@@ -0,0 +1,451 @@
+package mutator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/beck/go-coverage-analyzer/internal/command"
+	"github.com/beck/go-coverage-analyzer/internal/i18n"
+	"github.com/beck/go-coverage-analyzer/pkg/models"
+)
+
+// Mutant statuses
+const (
+	StatusKilled   = "killed"
+	StatusSurvived = "survived"
+	StatusTimeout  = "timeout"
+	StatusInvalid  = "invalid"
+)
+
+// Mutation operators
+const (
+	OperatorConditional = "conditional"
+	OperatorArithmetic  = "arithmetic"
+	OperatorReturn      = "return"
+)
+
+// Options contains configuration for mutation testing
+type Options struct {
+	ProjectPath string
+	Concurrency int
+	MaxMutants  int
+	Timeout     time.Duration
+	Verbose     bool
+}
+
+// Mutator applies source mutations to covered functions and runs tests against them
+type Mutator struct {
+	options *Options
+	verbose bool
+}
+
+// mutant pairs a mutation description with the mutated file content
+type mutant struct {
+	info       *models.Mutant
+	sourcePath string
+	packageDir string
+	content    []byte
+}
+
+// conditionalSwaps maps comparison and logical operators to their mutations
+var conditionalSwaps = map[token.Token]token.Token{
+	token.EQL:  token.NEQ,
+	token.NEQ:  token.EQL,
+	token.LSS:  token.GEQ,
+	token.GEQ:  token.LSS,
+	token.GTR:  token.LEQ,
+	token.LEQ:  token.GTR,
+	token.LAND: token.LOR,
+	token.LOR:  token.LAND,
+}
+
+// arithmeticSwaps maps arithmetic operators to their mutations
+var arithmeticSwaps = map[token.Token]token.Token{
+	token.ADD: token.SUB,
+	token.SUB: token.ADD,
+	token.MUL: token.QUO,
+	token.QUO: token.MUL,
+	token.REM: token.MUL,
+}
+
+// NewMutator creates a new mutator
+func NewMutator(opts *Options) *Mutator {
+	if opts.Concurrency < 1 {
+		opts.Concurrency = 1
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 2 * time.Minute
+	}
+
+	return &Mutator{
+		options: opts,
+		verbose: opts.Verbose,
+	}
+}
+
+// Run generates mutants for covered functions and reports how many the tests kill; cancelling ctx
+// stops the running go test processes and leaves the remaining mutants untested
+func Run(ctx context.Context, result *models.AnalysisResult, opts *Options) (*models.MutationReport, error) {
+	startTime := time.Now()
+	m := NewMutator(opts)
+
+	report := &models.MutationReport{
+		ProjectPath: opts.ProjectPath,
+		Timestamp:   time.Now(),
+		Packages:    make(map[string]*models.PackageMutants),
+		Mutants:     make([]*models.Mutant, 0),
+		Errors:      make([]string, 0),
+	}
+
+	mutants, err := m.generateMutants(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate mutants: %w", err)
+	}
+
+	if m.verbose {
+		i18n.Printf("🧬 Generated %d mutants\n", len(mutants))
+	}
+
+	// Packages whose tests already fail cannot tell us anything about mutants
+	mutants = m.filterFailingPackages(ctx, mutants, report)
+
+	m.executeMutants(ctx, mutants)
+	if ctx.Err() != nil {
+		return nil, fmt.Errorf("mutation testing interrupted: %w", ctx.Err())
+	}
+
+	for _, mt := range mutants {
+		report.Mutants = append(report.Mutants, mt.info)
+	}
+	summarize(report)
+	report.Duration = time.Since(startTime)
+
+	return report, nil
+}
+
+// generateMutants creates mutants for every covered, testable function in the result
+func (m *Mutator) generateMutants(result *models.AnalysisResult) ([]*mutant, error) {
+	fileFunctions := make(map[string][]*models.Function)
+	for _, pkg := range result.PackageCoverage {
+		for _, file := range pkg.Files {
+			for _, function := range file.Functions {
+				if function.IsCovered && function.IsTestable {
+					fileFunctions[file.Path] = append(fileFunctions[file.Path], function)
+				}
+			}
+		}
+	}
+
+	files := make([]string, 0, len(fileFunctions))
+	for path := range fileFunctions {
+		files = append(files, path)
+	}
+	sort.Strings(files)
+
+	var mutants []*mutant
+	for _, path := range files {
+		fileMutants, err := m.mutateFile(path, fileFunctions[path])
+		if err != nil {
+			return nil, err
+		}
+
+		for _, mt := range fileMutants {
+			if m.options.MaxMutants > 0 && len(mutants) >= m.options.MaxMutants {
+				return mutants, nil
+			}
+			mt.info.ID = len(mutants) + 1
+			mutants = append(mutants, mt)
+		}
+	}
+
+	return mutants, nil
+}
+
+// mutateFile produces all mutants for the given functions within one source file
+func (m *Mutator) mutateFile(relPath string, functions []*models.Function) ([]*mutant, error) {
+	sourcePath, err := filepath.Abs(filepath.Join(m.options.ProjectPath, relPath))
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", relPath, err)
+	}
+
+	src, err := os.ReadFile(sourcePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", relPath, err)
+	}
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, sourcePath, src, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", relPath, err)
+	}
+
+	targets := make(map[int]*models.Function)
+	for _, function := range functions {
+		targets[function.StartLine] = function
+	}
+
+	var mutants []*mutant
+	render := func(function *models.Function, pos token.Pos, operator, original, mutated string) {
+		var buf bytes.Buffer
+		if err := format.Node(&buf, fset, file); err != nil {
+			return
+		}
+		mutants = append(mutants, &mutant{
+			info: &models.Mutant{
+				Package:  function.Package,
+				File:     relPath,
+				Line:     fset.Position(pos).Line,
+				Function: function.Name,
+				Operator: operator,
+				Original: original,
+				Mutated:  mutated,
+			},
+			sourcePath: sourcePath,
+			packageDir: filepath.Dir(relPath),
+			content:    buf.Bytes(),
+		})
+	}
+
+	for _, decl := range file.Decls {
+		funcDecl, ok := decl.(*ast.FuncDecl)
+		if !ok || funcDecl.Body == nil {
+			continue
+		}
+
+		function, ok := targets[fset.Position(funcDecl.Pos()).Line]
+		if !ok || function.Name != funcDecl.Name.Name {
+			continue
+		}
+
+		ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
+			switch node := n.(type) {
+			case *ast.BinaryExpr:
+				original := node.Op
+				operator := OperatorConditional
+				swap, ok := conditionalSwaps[original]
+				if !ok {
+					operator = OperatorArithmetic
+					swap, ok = arithmeticSwaps[original]
+				}
+				if ok {
+					node.Op = swap
+					render(function, node.OpPos, operator, original.String(), swap.String())
+					node.Op = original
+				}
+			case *ast.ReturnStmt:
+				for i, res := range node.Results {
+					replacement, original, mutated := returnMutation(res)
+					if replacement == nil {
+						continue
+					}
+					node.Results[i] = replacement
+					render(function, res.Pos(), OperatorReturn, original, mutated)
+					node.Results[i] = res
+				}
+			}
+			return true
+		})
+	}
+
+	return mutants, nil
+}
+
+// returnMutation returns a replacement for a returned expression, or nil if it cannot be mutated
+func returnMutation(expr ast.Expr) (ast.Expr, string, string) {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		switch e.Name {
+		case "true":
+			return &ast.Ident{Name: "false", NamePos: e.NamePos}, "true", "false"
+		case "false":
+			return &ast.Ident{Name: "true", NamePos: e.NamePos}, "false", "true"
+		}
+	case *ast.BasicLit:
+		if e.Kind != token.INT && e.Kind != token.FLOAT {
+			return nil, "", ""
+		}
+		mutated := "0"
+		if e.Value == "0" || e.Value == "0.0" {
+			mutated = "1"
+		}
+		return &ast.BasicLit{Kind: e.Kind, Value: mutated, ValuePos: e.ValuePos}, e.Value, mutated
+	}
+	return nil, "", ""
+}
+
+// filterFailingPackages drops mutants in packages whose unmutated tests do not pass
+func (m *Mutator) filterFailingPackages(ctx context.Context, mutants []*mutant, report *models.MutationReport) []*mutant {
+	checked := make(map[string]bool)
+	for _, mt := range mutants {
+		if _, ok := checked[mt.packageDir]; ok {
+			continue
+		}
+
+		status, output := m.runTests(ctx, mt.packageDir, "")
+		checked[mt.packageDir] = status == StatusSurvived
+		if status != StatusSurvived {
+			report.Errors = append(report.Errors,
+				fmt.Sprintf("Skipping package %s: tests do not pass without mutations\n%s", mt.packageDir, output))
+		}
+	}
+
+	filtered := make([]*mutant, 0, len(mutants))
+	for _, mt := range mutants {
+		if checked[mt.packageDir] {
+			filtered = append(filtered, mt)
+		}
+	}
+	return filtered
+}
+
+// executeMutants runs the test suite against each mutant using a bounded worker pool
+func (m *Mutator) executeMutants(ctx context.Context, mutants []*mutant) {
+	jobs := make(chan *mutant)
+	var wg sync.WaitGroup
+
+	for i := 0; i < m.options.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for mt := range jobs {
+				mt.info.Status = m.runMutant(ctx, mt)
+				if m.verbose {
+					i18n.Printf("🧬 Mutant #%d %s:%d %s → %s: %s\n",
+						mt.info.ID, mt.info.File, mt.info.Line, mt.info.Original, mt.info.Mutated, mt.info.Status)
+				}
+			}
+		}()
+	}
+
+	for _, mt := range mutants {
+		if ctx.Err() != nil {
+			break
+		}
+		jobs <- mt
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+// runMutant writes the mutated source to a temporary overlay and runs the package tests
+func (m *Mutator) runMutant(ctx context.Context, mt *mutant) string {
+	tmpDir, err := os.MkdirTemp("", "gcov-mutant-*")
+	if err != nil {
+		return StatusInvalid
+	}
+	defer os.RemoveAll(tmpDir)
+
+	mutatedPath := filepath.Join(tmpDir, filepath.Base(mt.sourcePath))
+	if err := os.WriteFile(mutatedPath, mt.content, 0644); err != nil {
+		return StatusInvalid
+	}
+
+	overlay := struct {
+		Replace map[string]string
+	}{
+		Replace: map[string]string{mt.sourcePath: mutatedPath},
+	}
+
+	overlayData, err := json.Marshal(overlay)
+	if err != nil {
+		return StatusInvalid
+	}
+
+	overlayPath := filepath.Join(tmpDir, "overlay.json")
+	if err := os.WriteFile(overlayPath, overlayData, 0644); err != nil {
+		return StatusInvalid
+	}
+
+	status, _ := m.runTests(ctx, mt.packageDir, overlayPath)
+	return status
+}
+
+// runTests runs go test for a package directory and classifies the outcome.
+// A passing run means a mutant survived; failures to build mark it invalid.
+func (m *Mutator) runTests(ctx context.Context, packageDir, overlayPath string) (string, string) {
+	ctx, cancel := context.WithTimeout(ctx, m.options.Timeout)
+	defer cancel()
+
+	args := []string{"test", "-count=1", "-failfast"}
+	if overlayPath != "" {
+		args = append(args, "-overlay="+overlayPath)
+	}
+	args = append(args, "./"+filepath.ToSlash(packageDir))
+
+	cmd := command.Go(ctx, args...)
+	cmd.Dir = m.options.ProjectPath
+
+	output, err := cmd.CombinedOutput()
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return StatusTimeout, string(output)
+	case err == nil:
+		return StatusSurvived, string(output)
+	case strings.Contains(string(output), "[build failed]") || strings.Contains(string(output), "[setup failed]"):
+		return StatusInvalid, string(output)
+	default:
+		return StatusKilled, string(output)
+	}
+}
+
+// summarize aggregates mutant outcomes into overall and per-package scores. Packages are told apart
+// by directory, as packages in different directories may share a name
+func summarize(report *models.MutationReport) {
+	for _, mt := range report.Mutants {
+		dir := filepath.Dir(mt.File)
+		pkg, ok := report.Packages[dir]
+		if !ok {
+			pkg = &models.PackageMutants{
+				Name: mt.Package,
+				Path: dir,
+			}
+			report.Packages[dir] = pkg
+		}
+
+		pkg.Total++
+		report.TotalMutants++
+
+		switch mt.Status {
+		case StatusKilled:
+			pkg.Killed++
+			report.Killed++
+		case StatusSurvived:
+			pkg.Survived++
+			report.Survived++
+		case StatusTimeout:
+			pkg.TimedOut++
+			report.TimedOut++
+		default:
+			pkg.Invalid++
+			report.Invalid++
+		}
+	}
+
+	for _, pkg := range report.Packages {
+		pkg.MutationScore = mutationScore(pkg.Killed, pkg.TimedOut, pkg.Survived)
+	}
+	report.MutationScore = mutationScore(report.Killed, report.TimedOut, report.Survived)
+}
+
+// mutationScore returns the percentage of valid mutants detected by the tests.
+// Timeouts count as detected since the mutation changed observable behaviour.
+func mutationScore(killed, timedOut, survived int) float64 {
+	valid := killed + timedOut + survived
+	if valid == 0 {
+		return 0.0
+	}
+	return float64(killed+timedOut) / float64(valid) * 100.0
+}
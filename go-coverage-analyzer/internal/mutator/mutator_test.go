@@ -0,0 +1,33 @@
+package mutator
+
+import (
+	"testing"
+
+	"github.com/beck/go-coverage-analyzer/pkg/models"
+)
+
+func TestSummarizeKeepsPackagesSharingANameApart(t *testing.T) {
+	report := &models.MutationReport{
+		Packages: make(map[string]*models.PackageMutants),
+		Mutants: []*models.Mutant{
+			{Package: "util", File: "api/util/strings.go", Status: StatusKilled},
+			{Package: "util", File: "api/util/strings.go", Status: StatusKilled},
+			{Package: "util", File: "worker/util/retry.go", Status: StatusSurvived},
+		},
+	}
+
+	summarize(report)
+
+	if len(report.Packages) != 2 {
+		t.Fatalf("Packages = %v, want one per directory", report.Packages)
+	}
+	for dir, want := range map[string]float64{"api/util": 100, "worker/util": 0} {
+		pkg := report.Packages[dir]
+		if pkg == nil || pkg.Name != "util" || pkg.Path != dir {
+			t.Fatalf("Packages[%q] = %+v, want package util in %s", dir, pkg, dir)
+		}
+		if pkg.MutationScore != want {
+			t.Errorf("Packages[%q].MutationScore = %v, want %v", dir, pkg.MutationScore, want)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package reporter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/beck/go-coverage-analyzer/internal/i18n"
+	"github.com/beck/go-coverage-analyzer/pkg/models"
+)
+
+// printAreaDetails prints coverage aggregated per feature area, against each area's own threshold
+func printAreaDetails(result *models.AnalysisResult, opts *Options) {
+	if len(result.AreaCoverage) == 0 {
+		return
+	}
+
+	fmt.Printf("%s%sCOVERAGE BY AREA%s\n", ColorBold, ColorWhite, ColorReset)
+	fmt.Println(strings.Repeat("-", 80))
+	fmt.Printf("%-24s %-10s %-10s %-8s %-12s %-8s\n", "Area", "Coverage", "Threshold", "Files", "Functions", "Untested")
+	fmt.Println(strings.Repeat("-", 80))
+
+	for _, area := range result.AreaCoverage {
+		threshold := area.Threshold
+		thresholdText := "-"
+		if threshold > 0 {
+			thresholdText = opts.Numbers.Percent(threshold)
+		} else {
+			threshold = opts.Threshold
+		}
+
+		fmt.Printf("%-24s %s%8s%s  %-10s %-8d %5d/%-6d %-8d\n",
+			truncate(area.Area, 24),
+			getCoverageColor(area.Coverage, threshold), opts.Numbers.Percent(area.Coverage), ColorReset,
+			thresholdText,
+			area.Files,
+			area.CoveredFunctions, area.TotalFunctions,
+			len(area.UncoveredFunctions),
+		)
+	}
+
+	for _, area := range result.AreaCoverage {
+		if area.Files == 0 {
+			i18n.Printf("%s⚠️  Area %s matches no files (%s)%s\n", ColorYellow, area.Area, strings.Join(area.Patterns, ", "), ColorReset)
+		}
+	}
+
+	fmt.Println()
+}
@@ -0,0 +1,83 @@
+package reporter
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/beck/go-coverage-analyzer/pkg/models"
+)
+
+// Stable names of the files gcov analyze --artifacts-dir writes, so CI steps can pick them up
+const (
+	ArtifactProfile  = "coverage.out"
+	ArtifactJSON     = "coverage.json"
+	ArtifactHTML     = "coverage.html"
+	ArtifactMarkdown = "coverage.md"
+	ArtifactBadge    = "coverage-badge.svg"
+)
+
+// badgeTemplate is a flat shields.io-style badge; the label is 62 pixels wide, the value 50
+const badgeTemplate = `<svg xmlns="http://www.w3.org/2000/svg" width="112" height="20" role="img" aria-label="coverage: %[1]s">
+  <title>coverage: %[1]s</title>
+  <linearGradient id="s" x2="0" y2="100%%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>
+  <clipPath id="r"><rect width="112" height="20" rx="3" fill="#fff"/></clipPath>
+  <g clip-path="url(#r)">
+    <rect width="62" height="20" fill="#555"/>
+    <rect x="62" width="50" height="20" fill="%[2]s"/>
+    <rect width="112" height="20" fill="url(#s)"/>
+  </g>
+  <g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">
+    <text x="31" y="14">coverage</text>
+    <text x="87" y="14">%[1]s</text>
+  </g>
+</svg>
+`
+
+// WriteArtifacts writes the JSON result, HTML report, Markdown summary and coverage badge of an
+// analysis to dir under their Artifact names, and returns the paths written. The profile is written
+// there by the analysis itself
+func WriteArtifacts(result *models.AnalysisResult, dir string, opts *Options) ([]string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create artifacts directory: %w", err)
+	}
+
+	written := make([]string, 0, 4)
+	for _, artifact := range []struct {
+		format string
+		name   string
+	}{
+		{"json", ArtifactJSON},
+		{"html", ArtifactHTML},
+		{"markdown", ArtifactMarkdown},
+	} {
+		artifactOpts := *opts
+		artifactOpts.Format = artifact.format
+		artifactOpts.OutputFile = filepath.Join(dir, artifact.name)
+		artifactOpts.OpenReport = false
+		artifactOpts.Verbose = false
+		artifactOpts.CI = ""
+		if err := Generate(result, &artifactOpts); err != nil {
+			return written, fmt.Errorf("failed to write %s: %w", artifact.name, err)
+		}
+		written = append(written, artifactOpts.OutputFile)
+	}
+
+	badge := filepath.Join(dir, ArtifactBadge)
+	if err := writeOutput(renderBadge(result.OverallCoverage, opts.Threshold, opts.Numbers), badge); err != nil {
+		return written, fmt.Errorf("failed to write %s: %w", ArtifactBadge, err)
+	}
+	return append(written, badge), nil
+}
+
+// renderBadge returns an SVG badge showing the coverage, green at or above the threshold, yellow
+// within a quarter of it and red below
+func renderBadge(coverage, threshold float64, numbers *NumberFormat) string {
+	color := "#e05d44"
+	if coverage >= threshold {
+		color = "#4c1"
+	} else if coverage >= threshold*0.75 {
+		color = "#dfb317"
+	}
+	return fmt.Sprintf(badgeTemplate, numbers.Percent(coverage), color)
+}
@@ -0,0 +1,52 @@
+package reporter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/beck/go-coverage-analyzer/internal/i18n"
+	"github.com/beck/go-coverage-analyzer/pkg/models"
+)
+
+// printAuthorDetails prints the uncovered code of each author, by git blame
+func printAuthorDetails(result *models.AnalysisResult) {
+	if len(result.AuthorCoverage) == 0 {
+		return
+	}
+
+	fmt.Printf("%s%sUNCOVERED CODE BY AUTHOR (git blame)%s\n", ColorBold, ColorWhite, ColorReset)
+	fmt.Println(strings.Repeat("-", 80))
+	fmt.Printf("%-30s %-10s %-12s %-25s\n", "Author", "Functions", "Statements", "Most Complex")
+	fmt.Println(strings.Repeat("-", 80))
+
+	for _, author := range result.AuthorCoverage {
+		mostComplex := ""
+		if len(author.UncoveredFunctions) > 0 {
+			mostComplex = author.UncoveredFunctions[0].Key()
+		}
+		fmt.Printf("%-30s %s%-10d%s %-12d %-25s\n",
+			truncate(author.Author, 30),
+			ColorRed, len(author.UncoveredFunctions), ColorReset,
+			author.UncoveredStatements,
+			truncate(mostComplex, 25),
+		)
+	}
+
+	fmt.Println()
+}
+
+// printUncoveredByAuthor prints the most complex uncovered functions of each author
+func printUncoveredByAuthor(result *models.AnalysisResult) {
+	fmt.Printf("%s%sUNCOVERED FUNCTIONS BY AUTHOR (Top 5 each)%s\n", ColorBold, ColorWhite, ColorReset)
+	fmt.Println(strings.Repeat("-", 80))
+
+	for _, author := range result.AuthorCoverage {
+		fmt.Printf("%s%s%s (%d uncovered)\n", ColorBold, author.Author, ColorReset, len(author.UncoveredFunctions))
+		for _, function := range author.UncoveredFunctions[:min(5, len(author.UncoveredFunctions))] {
+			i18n.Printf("   • %-30s %s:%d (complexity %d)\n",
+				truncate(function.Name, 30), function.File, function.StartLine, function.Complexity)
+		}
+	}
+
+	fmt.Println()
+}
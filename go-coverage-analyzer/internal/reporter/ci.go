@@ -0,0 +1,82 @@
+package reporter
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/beck/go-coverage-analyzer/pkg/models"
+)
+
+// CI servers whose coverage statistics can be printed along any report
+const (
+	CITeamCity = "teamcity" // ##teamcity[buildStatisticValue ...] service messages
+	CIBamboo   = "bamboo"   // key=value lines for the Inject Bamboo Variables task
+)
+
+// ValidCI reports whether ci names a CI server coverage statistics can be printed for, or is empty
+func ValidCI(ci string) bool {
+	switch ci {
+	case "", CITeamCity, CIBamboo:
+		return true
+	default:
+		return false
+	}
+}
+
+// ciStatistic is a coverage statistic and the key each CI server knows it by
+type ciStatistic struct {
+	teamCity string
+	bamboo   string
+	value    string
+}
+
+// ciStatistics returns the statement and function coverage of a result, as percentages and counts.
+// The TeamCity keys are its built-in code coverage statistics, charted without further setup
+func ciStatistics(result *models.AnalysisResult, numbers *NumberFormat) []ciStatistic {
+	summary := result.Summary
+	return []ciStatistic{
+		{"CodeCoverageS", "coverage.statements", numbers.Number(result.OverallCoverage)},
+		{"CodeCoverageAbsSCovered", "coverage.statements.covered", strconv.Itoa(summary.CoveredLines)},
+		{"CodeCoverageAbsSTotal", "coverage.statements.total", strconv.Itoa(summary.TotalLines)},
+		{"CodeCoverageM", "coverage.functions", numbers.Number(summary.FunctionCoverage)},
+		{"CodeCoverageAbsMCovered", "coverage.functions.covered", strconv.Itoa(summary.TestedFunctions)},
+		{"CodeCoverageAbsMTotal", "coverage.functions.total", strconv.Itoa(summary.TotalFunctions)},
+	}
+}
+
+// WriteCIStatistics writes the coverage statistics of a result in the format the CI server reads from
+// the build log: TeamCity service messages, or Bamboo variables to inject from the build output
+func WriteCIStatistics(w io.Writer, result *models.AnalysisResult, ci string, numbers *NumberFormat) error {
+	var b strings.Builder
+	for _, statistic := range ciStatistics(result, numbers) {
+		switch ci {
+		case CITeamCity:
+			fmt.Fprintf(&b, "##teamcity[buildStatisticValue key='%s' value='%s']\n",
+				teamCityEscape(statistic.teamCity), teamCityEscape(statistic.value))
+		case CIBamboo:
+			fmt.Fprintf(&b, "%s=%s\n", statistic.bamboo, statistic.value)
+		default:
+			return fmt.Errorf("unsupported CI server: %s (valid: teamcity, bamboo)", ci)
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// teamCityEscaper escapes the characters with a meaning in TeamCity service message values
+var teamCityEscaper = strings.NewReplacer(
+	"|", "||",
+	"'", "|'",
+	"[", "|[",
+	"]", "|]",
+	"\n", "|n",
+	"\r", "|r",
+)
+
+// teamCityEscape escapes a service message value
+func teamCityEscape(s string) string {
+	return teamCityEscaper.Replace(s)
+}
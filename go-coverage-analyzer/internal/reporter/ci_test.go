@@ -0,0 +1,82 @@
+package reporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/beck/go-coverage-analyzer/pkg/models"
+)
+
+// captureOutput returns what f writes to stdout and stderr
+func captureOutput(t *testing.T, f func()) (string, string) {
+	t.Helper()
+	capture := func(file **os.File) (func() string, error) {
+		r, w, err := os.Pipe()
+		if err != nil {
+			return nil, err
+		}
+		original := *file
+		*file = w
+		var buf bytes.Buffer
+		done := make(chan struct{})
+		go func() {
+			io.Copy(&buf, r)
+			close(done)
+		}()
+		return func() string {
+			w.Close()
+			<-done
+			*file = original
+			return buf.String()
+		}, nil
+	}
+
+	stdout, err := capture(&os.Stdout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stderr, err := capture(&os.Stderr)
+	if err != nil {
+		stdout()
+		t.Fatal(err)
+	}
+	f()
+	return stdout(), stderr()
+}
+
+func TestGenerateCIStatisticsKeepStdoutReportReadable(t *testing.T) {
+	result := &models.AnalysisResult{
+		SchemaVersion:   models.SchemaVersion,
+		OverallCoverage: 75,
+		PackageCoverage: map[string]*models.Package{},
+		Summary:         &models.Summary{TotalLines: 4, CoveredLines: 3, TotalFunctions: 2, TestedFunctions: 1, FunctionCoverage: 50},
+		Metadata:        &models.Metadata{},
+	}
+
+	for _, ci := range []string{CITeamCity, CIBamboo} {
+		t.Run(ci, func(t *testing.T) {
+			var err error
+			stdout, stderr := captureOutput(t, func() {
+				err = Generate(result, &Options{Format: "json", CI: ci})
+			})
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+
+			var decoded models.AnalysisResult
+			if err := json.Unmarshal([]byte(stdout), &decoded); err != nil {
+				t.Fatalf("stdout is no JSON analysis result: %v\n%s", err, stdout)
+			}
+			if decoded.OverallCoverage != 75 {
+				t.Errorf("decoded overall coverage = %v, want 75", decoded.OverallCoverage)
+			}
+			if !strings.Contains(stderr, "75") {
+				t.Errorf("stderr = %q, want the coverage statistics", stderr)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package reporter
+
+import (
+	"fmt"
+	"os"
+)
+
+// Color modes, as given to --color
+const (
+	ColorModeAuto   = "auto"   // color when stdout is a terminal and NO_COLOR is not set
+	ColorModeAlways = "always" // color even when stdout is redirected
+	ColorModeNever  = "never"  // no ANSI escapes at all
+)
+
+// ANSI escape codes behind the colors
+const (
+	ansiReset  = "\033[0m"
+	ansiRed    = "\033[31m"
+	ansiGreen  = "\033[32m"
+	ansiYellow = "\033[33m"
+	ansiBlue   = "\033[34m"
+	ansiPurple = "\033[35m"
+	ansiCyan   = "\033[36m"
+	ansiWhite  = "\033[37m"
+	ansiBold   = "\033[1m"
+)
+
+// Colors for terminal output, empty once SetColorMode turns colors off
+var (
+	ColorReset  = ansiReset
+	ColorRed    = ansiRed
+	ColorGreen  = ansiGreen
+	ColorYellow = ansiYellow
+	ColorBlue   = ansiBlue
+	ColorPurple = ansiPurple
+	ColorCyan   = ansiCyan
+	ColorWhite  = ansiWhite
+	ColorBold   = ansiBold
+)
+
+// SetColorMode turns the colors of console output on or off. Auto follows the NO_COLOR convention
+// (https://no-color.org) and keeps escapes out of CI logs and redirected files
+func SetColorMode(mode string) error {
+	var enabled bool
+	switch mode {
+	case ColorModeAuto, "":
+		enabled = os.Getenv("NO_COLOR") == "" && IsTerminal(os.Stdout)
+	case ColorModeAlways:
+		enabled = true
+	case ColorModeNever:
+		enabled = false
+	default:
+		return fmt.Errorf("invalid color mode %q: use auto, always or never", mode)
+	}
+
+	color := func(code string) string {
+		if enabled {
+			return code
+		}
+		return ""
+	}
+	ColorReset, ColorRed, ColorGreen = color(ansiReset), color(ansiRed), color(ansiGreen)
+	ColorYellow, ColorBlue, ColorPurple = color(ansiYellow), color(ansiBlue), color(ansiPurple)
+	ColorCyan, ColorWhite, ColorBold = color(ansiCyan), color(ansiWhite), color(ansiBold)
+	return nil
+}
@@ -2,8 +2,11 @@ package reporter
 
 import (
 	"fmt"
+	"io"
+	"os"
 	"strings"
 
+	"github.com/beck/go-coverage-analyzer/internal/i18n"
 	"github.com/beck/go-coverage-analyzer/pkg/models"
 )
 
@@ -13,25 +16,24 @@ func GenerateComparisonReport(current, previous *models.AnalysisResult, opts *Op
 	fmt.Println("=" + strings.Repeat("=", 60) + "=")
 
 	// Overall comparison
-	fmt.Printf("Current Coverage:  %s%.1f%%%s\n",
-		getCoverageColor(current.OverallCoverage, opts.Threshold), current.OverallCoverage, ColorReset)
-	fmt.Printf("Previous Coverage: %s%.1f%%%s\n",
-		getCoverageColor(previous.OverallCoverage, opts.Threshold), previous.OverallCoverage, ColorReset)
+	fmt.Printf("Current Coverage:  %s%s%s\n", getCoverageColor(current.OverallCoverage, opts.Threshold),
+		opts.Numbers.Coverage(current.Summary.CoveredLines, current.Summary.TotalLines, current.OverallCoverage), ColorReset)
+	fmt.Printf("Previous Coverage: %s%s%s\n", getCoverageColor(previous.OverallCoverage, opts.Threshold),
+		opts.Numbers.Coverage(previous.Summary.CoveredLines, previous.Summary.TotalLines, previous.OverallCoverage), ColorReset)
 
 	change := current.OverallCoverage - previous.OverallCoverage
 	changeColor := ColorGreen
-	changeSymbol := "📈"
+	changeSymbol := i18n.T("📈")
 
 	if change < 0 {
 		changeColor = ColorRed
-		changeSymbol = "📉"
+		changeSymbol = i18n.T("📉")
 	} else if change == 0 {
 		changeColor = ColorYellow
-		changeSymbol = "➡️"
+		changeSymbol = i18n.T("➡️")
 	}
 
-	fmt.Printf("Change:            %s%s %.2f%% (%s%.1f%%)%s\n",
-		changeColor, changeSymbol, change, changeColor, change, ColorReset)
+	fmt.Printf("Change:            %s%s %s%s\n", changeColor, changeSymbol, opts.Numbers.Change(change), ColorReset)
 
 	fmt.Println()
 
@@ -44,9 +46,9 @@ func GenerateComparisonReport(current, previous *models.AnalysisResult, opts *Op
 	fmt.Printf("Previous Untested Functions: %s%d%s\n", ColorRed, previousUntested, ColorReset)
 
 	if functionChange > 0 {
-		fmt.Printf("Improvement: %s✅ %d functions now tested%s\n", ColorGreen, functionChange, ColorReset)
+		i18n.Printf("Improvement: %s✅ %d functions now tested%s\n", ColorGreen, functionChange, ColorReset)
 	} else if functionChange < 0 {
-		fmt.Printf("Regression:  %s❌ %d additional untested functions%s\n", ColorRed, -functionChange, ColorReset)
+		i18n.Printf("Regression:  %s❌ %d additional untested functions%s\n", ColorRed, -functionChange, ColorReset)
 	} else {
 		fmt.Printf("No change in function coverage\n")
 	}
@@ -62,9 +64,9 @@ func GenerateComparisonReport(current, previous *models.AnalysisResult, opts *Op
 	for pkgName, currentPkg := range current.PackageCoverage {
 		previousPkg, exists := previous.PackageCoverage[pkgName]
 		if !exists {
-			fmt.Printf("%-20s %s%7.1f%%%s %12s %s+NEW%s\n",
+			fmt.Printf("%-20s %s%8s%s %12s %s+NEW%s\n",
 				truncate(pkgName, 20),
-				getCoverageColor(currentPkg.Coverage, opts.Threshold), currentPkg.Coverage, ColorReset,
+				getCoverageColor(currentPkg.Coverage, opts.Threshold), opts.Numbers.Percent(currentPkg.Coverage), ColorReset,
 				"N/A",
 				ColorGreen, ColorReset)
 			continue
@@ -82,11 +84,11 @@ func GenerateComparisonReport(current, previous *models.AnalysisResult, opts *Op
 			changeColor = ColorRed
 		}
 
-		fmt.Printf("%-20s %s%7.1f%%%s %s%7.1f%%%s %s%s%6.1f%%%s\n",
+		fmt.Printf("%-20s %s%8s%s %s%8s%s %s%s%7s%s\n",
 			truncate(pkgName, 20),
-			getCoverageColor(currentPkg.Coverage, opts.Threshold), currentPkg.Coverage, ColorReset,
-			getCoverageColor(previousPkg.Coverage, opts.Threshold), previousPkg.Coverage, ColorReset,
-			changeColor, changeSymbol, pkgChange, ColorReset)
+			getCoverageColor(currentPkg.Coverage, opts.Threshold), opts.Numbers.Percent(currentPkg.Coverage), ColorReset,
+			getCoverageColor(previousPkg.Coverage, opts.Threshold), opts.Numbers.Percent(previousPkg.Coverage), ColorReset,
+			changeColor, changeSymbol, opts.Numbers.Percent(pkgChange), ColorReset)
 	}
 
 	// Identify removed packages
@@ -104,26 +106,26 @@ func GenerateComparisonReport(current, previous *models.AnalysisResult, opts *Op
 	fmt.Println(strings.Repeat("-", 30))
 
 	if change > 0 {
-		fmt.Printf("✅ Great improvement! Coverage increased by %.1f%%\n", change)
+		i18n.Printf("✅ Great improvement! Coverage increased by %s\n", opts.Numbers.Percent(change))
 	} else if change < 0 {
-		fmt.Printf("⚠️  Coverage decreased by %.1f%%. Consider reviewing recent changes\n", -change)
+		i18n.Printf("⚠️  Coverage decreased by %s. Consider reviewing recent changes\n", opts.Numbers.Percent(-change))
 	}
 
 	if functionChange > 0 {
-		fmt.Printf("🎯 Excellent! %d more functions are now tested\n", functionChange)
+		i18n.Printf("🎯 Excellent! %d more functions are now tested\n", functionChange)
 	} else if functionChange < 0 {
-		fmt.Printf("📝 %d functions lost test coverage. Review recent changes\n", -functionChange)
+		i18n.Printf("📝 %d functions lost test coverage. Review recent changes\n", -functionChange)
 	}
 
 	if current.OverallCoverage < opts.Threshold {
-		fmt.Printf("🎯 Focus on reaching %.1f%% coverage threshold\n", opts.Threshold)
+		i18n.Printf("🎯 Focus on reaching %s coverage threshold\n", opts.Numbers.Percent(opts.Threshold))
 	}
 
 	return nil
 }
 
 // PrintCoverageTrend prints a simple coverage trend
-func PrintCoverageTrend(trends []*models.CoverageTrend) {
+func PrintCoverageTrend(trends []*models.CoverageTrend, numbers *NumberFormat) {
 	if len(trends) == 0 {
 		return
 	}
@@ -132,22 +134,22 @@ func PrintCoverageTrend(trends []*models.CoverageTrend) {
 	fmt.Println(strings.Repeat("-", 40))
 
 	for i, trend := range trends {
-		symbol := "➡️"
+		symbol := i18n.T("➡️")
 		color := ColorYellow
 
 		switch trend.Direction {
 		case "up":
-			symbol = "📈"
+			symbol = i18n.T("📈")
 			color = ColorGreen
 		case "down":
-			symbol = "📉"
+			symbol = i18n.T("📉")
 			color = ColorRed
 		}
 
-		fmt.Printf("%d. %s %s%.1f%%%s (%s%+.1f%%%s)\n",
+		fmt.Printf("%d. %s %s%s%s (%s%s%s)\n",
 			i+1, symbol,
-			getCoverageColor(trend.CurrentCoverage, 80.0), trend.CurrentCoverage, ColorReset,
-			color, trend.Change, ColorReset)
+			getCoverageColor(trend.CurrentCoverage, 80.0), numbers.Percent(trend.CurrentCoverage), ColorReset,
+			color, numbers.Change(trend.Change), ColorReset)
 	}
 
 	fmt.Println()
@@ -160,7 +162,8 @@ func PrintDetailedPackageReport(pkg *models.Package, opts *Options) {
 
 	// Package summary
 	fmt.Printf("Path: %s\n", pkg.Path)
-	fmt.Printf("Coverage: %s%.1f%%%s\n", getCoverageColor(pkg.Coverage, opts.Threshold), pkg.Coverage, ColorReset)
+	fmt.Printf("Coverage: %s%s%s\n", getCoverageColor(pkg.Coverage, opts.Threshold),
+		opts.Numbers.Coverage(pkg.CoveredLines, pkg.TotalLines, pkg.Coverage), ColorReset)
 	fmt.Printf("Functions: %d total, %d covered, %d uncovered\n",
 		pkg.TotalFunctions, pkg.CoveredFunctions, pkg.TotalFunctions-pkg.CoveredFunctions)
 	fmt.Printf("Lines: %d total, %d covered, %d uncovered\n",
@@ -177,9 +180,9 @@ func PrintDetailedPackageReport(pkg *models.Package, opts *Options) {
 		fmt.Println(strings.Repeat("-", 80))
 
 		for _, file := range pkg.Files {
-			fmt.Printf("%-30s %s%7.1f%%%s %7d/%-3d %7d/%-5d %8d\n",
+			fmt.Printf("%-30s %s%8s%s %7d/%-3d %7d/%-5d %8d\n",
 				truncate(file.Name, 30),
-				getCoverageColor(file.Coverage, opts.Threshold), file.Coverage, ColorReset,
+				getCoverageColor(file.Coverage, opts.Threshold), opts.Numbers.Percent(file.Coverage), ColorReset,
 				len(file.Functions), len(file.Functions), // Simplified for now
 				file.CoveredLines, file.TotalLines,
 				file.Complexity)
@@ -205,13 +208,7 @@ func PrintDetailedPackageReport(pkg *models.Package, opts *Options) {
 		fmt.Println(strings.Repeat("-", 70))
 
 		for _, function := range uncoveredFunctions[:min(10, len(uncoveredFunctions))] {
-			complexityColor := ColorGreen
-			if function.Complexity > 5 {
-				complexityColor = ColorYellow
-			}
-			if function.Complexity > 10 {
-				complexityColor = ColorRed
-			}
+			complexityColor := getComplexityColor(function.Complexity, opts.highComplexity())
 
 			fmt.Printf("%-25s %-20s %4d-%-4d %s%8d%s\n",
 				truncate(function.Name, 25),
@@ -229,7 +226,7 @@ func PrintDetailedPackageReport(pkg *models.Package, opts *Options) {
 }
 
 // PrintFunctionDetails prints detailed information about a specific function
-func PrintFunctionDetails(function *models.Function) {
+func PrintFunctionDetails(function *models.Function, opts *Options) {
 	fmt.Printf("%s%sFUNCTION DETAILS: %s%s\n", ColorBold, ColorCyan, function.Name, ColorReset)
 	fmt.Println("=" + strings.Repeat("=", 40) + "=")
 
@@ -237,15 +234,9 @@ func PrintFunctionDetails(function *models.Function) {
 	fmt.Printf("File: %s\n", function.File)
 	fmt.Printf("Lines: %d-%d\n", function.StartLine, function.EndLine)
 	fmt.Printf("Signature: %s\n", function.Signature)
-	fmt.Printf("Coverage: %s%.1f%%%s\n", getCoverageColor(function.Coverage, 80.0), function.Coverage, ColorReset)
+	fmt.Printf("Coverage: %s%s%s\n", getCoverageColor(function.Coverage, 80.0), opts.Numbers.Percent(function.Coverage), ColorReset)
 
-	complexityColor := ColorGreen
-	if function.Complexity > 5 {
-		complexityColor = ColorYellow
-	}
-	if function.Complexity > 10 {
-		complexityColor = ColorRed
-	}
+	complexityColor := getComplexityColor(function.Complexity, opts.highComplexity())
 	fmt.Printf("Complexity: %s%d%s\n", complexityColor, function.Complexity, ColorReset)
 
 	// Properties
@@ -283,6 +274,11 @@ func PrintFunctionDetails(function *models.Function) {
 
 // PrintProgressBar prints a simple progress bar for coverage
 func PrintProgressBar(current, total int, width int) {
+	FprintProgressBar(os.Stdout, current, total, width)
+}
+
+// FprintProgressBar writes a simple progress bar to w
+func FprintProgressBar(w io.Writer, current, total int, width int) {
 	if total == 0 {
 		return
 	}
@@ -290,7 +286,7 @@ func PrintProgressBar(current, total int, width int) {
 	percentage := float64(current) / float64(total)
 	filled := int(percentage * float64(width))
 
-	fmt.Printf("[%s%s%s] %.1f%% (%d/%d)",
-		ColorGreen, strings.Repeat("=", filled), ColorReset,
+	fmt.Fprintf(w, "[%s%s%s%s] %.1f%% (%d/%d)",
+		ColorGreen, strings.Repeat("=", filled), ColorReset, strings.Repeat(" ", width-filled),
 		percentage*100, current, total)
 }
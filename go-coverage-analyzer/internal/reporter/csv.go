@@ -0,0 +1,155 @@
+package reporter
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"github.com/beck/go-coverage-analyzer/pkg/models"
+)
+
+// Granularities of the rows of CSV and TSV reports
+const (
+	GranularityPackage  = "package"  // one row per package
+	GranularityFile     = "file"     // one row per file
+	GranularityFunction = "function" // one row per testable function
+)
+
+// csvHeader names the columns of CSV and TSV reports, the same at every granularity so rows of
+// different reports can be pasted into one sheet; cells that do not apply to a row are left empty
+var csvHeader = []string{
+	"package", "file", "function", "line", "coverage", "statements", "covered_statements",
+	"uncovered_statements", "functions", "covered_functions", "complexity", "loc",
+}
+
+// ValidGranularity reports whether a granularity is one CSV and TSV reports support
+func ValidGranularity(granularity string) bool {
+	switch granularity {
+	case GranularityPackage, GranularityFile, GranularityFunction:
+		return true
+	default:
+		return false
+	}
+}
+
+// generateCSVReport writes coverage rows at the granularity of the options, separated by commas or,
+// for TSV, by tabs. Coverage is a plain number with the configured decimals, for spreadsheets to sum
+func generateCSVReport(result *models.AnalysisResult, opts *Options, separator rune) error {
+	granularity := opts.Granularity
+	if granularity == "" {
+		granularity = GranularityPackage
+	}
+	if !ValidGranularity(granularity) {
+		return fmt.Errorf("invalid granularity %q (valid: package, file, function)", granularity)
+	}
+
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	writer.Comma = separator
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+
+	var rows [][]string
+	switch granularity {
+	case GranularityPackage:
+		rows = packageRows(result, opts.Numbers)
+	case GranularityFile:
+		rows = fileRows(result, opts.Numbers)
+	case GranularityFunction:
+		rows = functionRows(result, opts.Numbers)
+	}
+	if err := writer.WriteAll(rows); err != nil {
+		return err
+	}
+	return writeOutput(buf.String(), opts.OutputFile)
+}
+
+// packageRows returns a row per package, by name
+func packageRows(result *models.AnalysisResult, numbers *NumberFormat) [][]string {
+	rows := make([][]string, 0, len(result.PackageCoverage))
+	for _, pkg := range getPackagesSortedByName(result) {
+		var functions []*models.Function
+		for _, file := range pkg.Files {
+			functions = append(functions, file.Functions...)
+		}
+		total, covered, complexity := testableCounts(functions)
+		rows = append(rows, []string{
+			pkg.Name, "", "", "",
+			numbers.Number(pkg.Coverage),
+			strconv.Itoa(pkg.TotalLines), strconv.Itoa(pkg.CoveredLines), strconv.Itoa(pkg.UncoveredLines),
+			strconv.Itoa(total), strconv.Itoa(covered),
+			strconv.Itoa(complexity), strconv.Itoa(pkg.CodeLines),
+		})
+	}
+	return rows
+}
+
+// fileRows returns a row per file, by path
+func fileRows(result *models.AnalysisResult, numbers *NumberFormat) [][]string {
+	files := make([]*models.File, 0)
+	for _, pkg := range result.PackageCoverage {
+		for _, file := range pkg.Files {
+			files = append(files, file)
+		}
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Path < files[j].Path
+	})
+
+	rows := make([][]string, 0, len(files))
+	for _, file := range files {
+		total, covered, complexity := testableCounts(file.Functions)
+		rows = append(rows, []string{
+			file.Package, filepath.ToSlash(file.Path), "", "",
+			numbers.Number(file.Coverage),
+			strconv.Itoa(file.CoveredLines + file.UncoveredLines), strconv.Itoa(file.CoveredLines), strconv.Itoa(file.UncoveredLines),
+			strconv.Itoa(total), strconv.Itoa(covered),
+			strconv.Itoa(complexity), strconv.Itoa(file.TotalLines),
+		})
+	}
+	return rows
+}
+
+// testableCounts returns how many of the functions are testable and covered, and their complexity,
+// counting the functions function rows list so that package and file rows add up to them
+func testableCounts(functions []*models.Function) (total, covered, complexity int) {
+	for _, function := range functions {
+		if !function.IsTestable {
+			continue
+		}
+		total++
+		if function.IsCovered {
+			covered++
+		}
+		complexity += function.Complexity
+	}
+	return total, covered, complexity
+}
+
+// functionRows returns a row per testable function, by file and line
+func functionRows(result *models.AnalysisResult, numbers *NumberFormat) [][]string {
+	functions := result.GetTestableFunctions()
+	sort.Slice(functions, func(i, j int) bool {
+		if functions[i].File != functions[j].File {
+			return functions[i].File < functions[j].File
+		}
+		return functions[i].StartLine < functions[j].StartLine
+	})
+
+	rows := make([][]string, 0, len(functions))
+	for _, function := range functions {
+		rows = append(rows, []string{
+			function.Package, filepath.ToSlash(function.File), function.Key(), strconv.Itoa(function.StartLine),
+			numbers.Number(function.Coverage),
+			strconv.Itoa(function.Statements), strconv.Itoa(function.CoveredStatements),
+			strconv.Itoa(function.Statements - function.CoveredStatements),
+			"", "",
+			strconv.Itoa(function.Complexity), strconv.Itoa(function.Lines),
+		})
+	}
+	return rows
+}
@@ -0,0 +1,64 @@
+package reporter
+
+import (
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/beck/go-coverage-analyzer/pkg/models"
+)
+
+func TestCSVRowsCountTestableFunctions(t *testing.T) {
+	file := &models.File{
+		Path: "calc.go", Package: "calc", CoveredLines: 2, UncoveredLines: 2, TotalLines: 12, Coverage: 50,
+		Functions: []*models.Function{
+			{Name: "Add", Package: "calc", File: "calc.go", IsTestable: true, IsCovered: true, Complexity: 1},
+			{Name: "Sub", Package: "calc", File: "calc.go", IsTestable: true, Complexity: 2},
+			{Name: "main", Package: "calc", File: "calc.go", IsCovered: true, Complexity: 4},
+		},
+	}
+	result := &models.AnalysisResult{
+		PackageCoverage: map[string]*models.Package{
+			"calc": {
+				Name: "calc", Files: map[string]*models.File{"calc.go": file},
+				TotalLines: 4, CoveredLines: 2, UncoveredLines: 2, Coverage: 50,
+				TotalFunctions: 3, CoveredFunctions: 2, Complexity: 7,
+			},
+		},
+	}
+
+	for _, granularity := range []string{GranularityPackage, GranularityFile, GranularityFunction} {
+		t.Run(granularity, func(t *testing.T) {
+			output := filepath.Join(t.TempDir(), "coverage.csv")
+			if err := Generate(result, &Options{Format: "csv", Granularity: granularity, OutputFile: output}); err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			f, err := os.Open(output)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+			records, err := csv.NewReader(f).ReadAll()
+			if err != nil {
+				t.Fatal(err)
+			}
+			rows := records[1:]
+
+			// Every granularity counts the two testable functions, of which Add is covered
+			if granularity == GranularityFunction {
+				if len(rows) != 2 {
+					t.Fatalf("function rows = %v, want Add and Sub", rows)
+				}
+				return
+			}
+			if len(rows) != 1 {
+				t.Fatalf("rows = %v, want one", rows)
+			}
+			functions, covered, complexity := rows[0][8], rows[0][9], rows[0][10]
+			if functions != "2" || covered != "1" || complexity != "3" {
+				t.Errorf("functions, covered functions, complexity = %s, %s, %s, want 2, 1, 3", functions, covered, complexity)
+			}
+		})
+	}
+}
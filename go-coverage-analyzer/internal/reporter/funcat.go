@@ -0,0 +1,121 @@
+package reporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/beck/go-coverage-analyzer/pkg/models"
+)
+
+// FunctionAt returns the innermost function or closure of an analysis enclosing a line of a file
+// relative to the project, along with the top-level function declaring it, or nils when no function
+// encloses the line
+func FunctionAt(result *models.AnalysisResult, file string, line int) (at *models.FunctionAt, declared *models.Function) {
+	file = filepath.ToSlash(file)
+	for _, pkg := range result.PackageCoverage {
+		for _, fileModel := range pkg.Files {
+			if filepath.ToSlash(fileModel.Path) != file {
+				continue
+			}
+			for _, function := range fileModel.Functions {
+				if line < function.StartLine || line > function.EndLine {
+					continue
+				}
+
+				return functionAt(fileModel, innermostClosure(function, line), line), function
+			}
+		}
+	}
+	return nil, nil
+}
+
+// FunctionsIn returns every function of a file relative to the project and their closures, in source
+// order, as func-at reports them for their first line
+func FunctionsIn(result *models.AnalysisResult, file string) []*models.FunctionAt {
+	file = filepath.ToSlash(file)
+	functions := make([]*models.FunctionAt, 0)
+	for _, pkg := range result.PackageCoverage {
+		for _, fileModel := range pkg.Files {
+			if filepath.ToSlash(fileModel.Path) != file {
+				continue
+			}
+
+			var add func(function *models.Function)
+			add = func(function *models.Function) {
+				functions = append(functions, functionAt(fileModel, function, function.StartLine))
+				for _, closure := range function.Closures {
+					add(closure)
+				}
+			}
+			for _, function := range fileModel.Functions {
+				add(function)
+			}
+		}
+	}
+
+	sort.SliceStable(functions, func(i, j int) bool {
+		return functions[i].StartLine < functions[j].StartLine
+	})
+	return functions
+}
+
+// functionAt describes a function of a file as found at a line within it
+func functionAt(file *models.File, function *models.Function, line int) *models.FunctionAt {
+	ranges, _ := blockRanges(file, function)
+	at := &models.FunctionAt{
+		File:            filepath.ToSlash(file.Path),
+		Line:            line,
+		Function:        function.Key(),
+		Package:         function.Package,
+		StartLine:       function.StartLine,
+		EndLine:         function.EndLine,
+		Coverage:        function.Coverage,
+		IsCovered:       function.IsCovered,
+		Complexity:      function.Complexity,
+		UncoveredBlocks: make([]*models.LineRange, 0, len(ranges)),
+	}
+	for _, r := range ranges {
+		at.UncoveredBlocks = append(at.UncoveredBlocks, &models.LineRange{StartLine: r.start, EndLine: r.end})
+	}
+	return at
+}
+
+// innermostClosure returns the most deeply nested closure of a function enclosing a line, or the
+// function itself
+func innermostClosure(function *models.Function, line int) *models.Function {
+	for _, closure := range function.Closures {
+		if line >= closure.StartLine && line <= closure.EndLine {
+			return innermostClosure(closure, line)
+		}
+	}
+	return function
+}
+
+// WriteFunctionAt writes what gcov func-at found out about a function, as JSON or for the console
+// with its coverage colored against the threshold and printed in the number format
+func WriteFunctionAt(w io.Writer, at *models.FunctionAt, format string, threshold float64, numbers *NumberFormat) error {
+	if strings.ToLower(format) == "json" {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(at)
+	}
+
+	fmt.Fprintf(w, "%s%s%s (%s:%d-%d)\n", ColorBold, at.Function, ColorReset, at.File, at.StartLine, at.EndLine)
+	fmt.Fprintf(w, "Coverage:   %s%s%s\n", getCoverageColor(at.Coverage, threshold), numbers.Percent(at.Coverage), ColorReset)
+	fmt.Fprintf(w, "Complexity: %d\n", at.Complexity)
+	if len(at.UncoveredBlocks) > 0 {
+		ranges := make([]string, 0, len(at.UncoveredBlocks))
+		for _, block := range at.UncoveredBlocks {
+			ranges = append(ranges, lineRange{start: block.StartLine, end: block.EndLine}.String())
+		}
+		fmt.Fprintf(w, "Uncovered:  %slines %s%s\n", ColorRed, strings.Join(ranges, ", "), ColorReset)
+	}
+	if at.GenerateCommand != "" {
+		fmt.Fprintf(w, "Generate:   %s\n", at.GenerateCommand)
+	}
+	return nil
+}
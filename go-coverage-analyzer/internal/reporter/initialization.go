@@ -0,0 +1,49 @@
+package reporter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/beck/go-coverage-analyzer/pkg/models"
+)
+
+// printInitialization prints the coverage of init functions and package-level variable initializers,
+// when they were included in the analysis
+func printInitialization(result *models.AnalysisResult, opts *Options) {
+	initialization := result.Initialization
+	if initialization == nil {
+		return
+	}
+
+	fmt.Printf("%s%sINITIALIZATION%s\n", ColorBold, ColorWhite, ColorReset)
+	fmt.Println(strings.Repeat("-", 90))
+
+	if len(initialization.Initializers) == 0 {
+		fmt.Println("No init functions or variables initialized with function literals")
+		fmt.Println()
+		return
+	}
+
+	fmt.Printf("Initialization Coverage: %s%s%s\n", getCoverageColor(initialization.Coverage, opts.Threshold),
+		opts.Numbers.Coverage(initialization.CoveredStatements, initialization.Statements, initialization.Coverage), ColorReset)
+	fmt.Println()
+
+	fmt.Printf("%-6s %-28s %-20s %-25s %-8s\n", "Kind", "Name", "Package", "File", "Coverage")
+	fmt.Println(strings.Repeat("-", 90))
+
+	for _, initializer := range initialization.Initializers {
+		coverage := "-"
+		if initializer.Statements > 0 {
+			coverage = opts.Numbers.Percent(initializer.Coverage)
+		}
+		fmt.Printf("%-6s %-28s %-20s %-25s %s%8s%s\n",
+			initializer.Kind,
+			truncate(initializer.Name, 28),
+			truncate(initializer.Package, 20),
+			truncate(fmt.Sprintf("%s:%d", initializer.File, initializer.StartLine), 25),
+			getCoverageColor(initializer.Coverage, opts.Threshold), coverage, ColorReset,
+		)
+	}
+
+	fmt.Println()
+}
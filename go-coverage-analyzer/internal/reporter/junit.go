@@ -0,0 +1,130 @@
+package reporter
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/beck/go-coverage-analyzer/internal/generator"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr,omitempty"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr,omitempty"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Skipped   *junitSkipped `xml:"skipped,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",cdata"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr"`
+}
+
+// generateValidationJUnit writes a validation result as JUnit XML, so CI systems show validation
+// failures as test failures: a syntax suite with a test case per validated file, and compile and
+// execution suites whose test cases fail with the errors of their step, or are skipped when validation
+// stopped before it
+func generateValidationJUnit(result *generator.ValidationResult, opts *Options) error {
+	syntax := junitTestSuite{Name: "syntax"}
+	for _, file := range result.Files {
+		testCase := junitTestCase{Name: file.Path, ClassName: "syntax"}
+		if file.SyntaxError != "" {
+			testCase.Failure = &junitFailure{Message: "syntax error", Type: RuleSyntaxError, Text: file.SyntaxError}
+		}
+		syntax.add(testCase)
+	}
+
+	compile := junitTestSuite{Name: "compile", Time: junitSeconds(result.CompilationTime)}
+	compile.add(junitStepCase("go build and go test -c", "compile", RuleCompileError, result.CompileChecked, result.CompileErrors))
+
+	execution := junitTestSuite{Name: "execution", Time: junitSeconds(result.ExecutionTime)}
+	execution.add(junitStepCase("go test", "execution", RuleRuntimeError, result.TestsExecuted, result.RuntimeErrors))
+	for _, flaky := range result.FlakyTests {
+		execution.add(junitTestCase{
+			Name:      flaky.Name,
+			ClassName: flaky.Package,
+			Failure: &junitFailure{
+				Message: "flaky test",
+				Type:    RuleFlakyTest,
+				Text:    fmt.Sprintf("failed %d of %d runs (%.0f%%)", flaky.Failures, flaky.Runs, flaky.FailureRate*100),
+			},
+		})
+	}
+
+	report := junitTestSuites{
+		Name:   "gcov validate",
+		Time:   junitSeconds(result.CompilationTime + result.ExecutionTime),
+		Suites: []junitTestSuite{syntax, compile, execution},
+	}
+	for _, suite := range report.Suites {
+		report.Tests += suite.Tests
+		report.Failures += suite.Failures
+		report.Skipped += suite.Skipped
+	}
+
+	data, err := xml.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JUnit XML: %w", err)
+	}
+	return writeOutput(xml.Header+string(data)+"\n", opts.OutputFile)
+}
+
+// junitStepCase returns the test case of a validation step: skipped when it did not run, failed with
+// its errors when there are any
+func junitStepCase(name, className, rule string, ran bool, errors []string) junitTestCase {
+	testCase := junitTestCase{Name: name, ClassName: className}
+	switch {
+	case !ran:
+		testCase.Skipped = &junitSkipped{Message: "not run: validation stopped before this step"}
+	case len(errors) > 0:
+		testCase.Failure = &junitFailure{
+			Message: fmt.Sprintf("%d %s errors", len(errors), className),
+			Type:    rule,
+			Text:    strings.Join(errors, "\n\n"),
+		}
+	}
+	return testCase
+}
+
+// add appends a test case to the suite and counts it
+func (s *junitTestSuite) add(testCase junitTestCase) {
+	s.TestCases = append(s.TestCases, testCase)
+	s.Tests++
+	if testCase.Failure != nil {
+		s.Failures++
+	}
+	if testCase.Skipped != nil {
+		s.Skipped++
+	}
+}
+
+// junitSeconds formats a duration as the seconds JUnit time attributes hold
+func junitSeconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
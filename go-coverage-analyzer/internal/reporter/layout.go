@@ -0,0 +1,130 @@
+package reporter
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"os"
+	"strings"
+	texttemplate "text/template"
+
+	"github.com/beck/go-coverage-analyzer/pkg/models"
+)
+
+// defaultMarkdownTemplate lays out the markdown report when no markdown template is configured
+const defaultMarkdownTemplate = `# Coverage report for {{.ProjectPath}}
+
+| Metric | Coverage |
+|--------|---------:|
+| Overall | {{coverage .Summary.CoveredLines .Summary.TotalLines .OverallCoverage}} |
+| Functions | {{coverage .Summary.TestedFunctions .Summary.TotalFunctions .FunctionCoverage}} |
+| Lines | {{coverage .Summary.CoveredLines .Summary.TotalLines .LineCoverage}} |
+| Branches | {{percent .BranchCoverage}} |
+
+{{if ge .OverallCoverage threshold}}Coverage meets the {{percent threshold}} threshold.{{else}}**Coverage is below the {{percent threshold}} threshold.**{{end}}
+
+## Packages
+
+| Package | Coverage | Functions | Lines |
+|---------|---------:|----------:|------:|
+{{- range packages .}}
+| {{.Name}} | {{percent .Coverage}} | {{.CoveredFunctions}}/{{.TotalFunctions}} | {{.CoveredLines}}/{{.TotalLines}} |
+{{- end}}
+{{- with uncovered . 20}}
+
+## Uncovered functions
+
+| Function | Package | File | Complexity |
+|----------|---------|------|-----------:|
+{{- range .}}
+| ` + "`{{.Name}}`" + ` | {{.Package}} | {{.File}}:{{.StartLine}} | {{.Complexity}} |
+{{- end}}
+{{- end}}
+`
+
+// templateFormat returns the report template key of an output format: console, html or markdown
+func templateFormat(format string) string {
+	switch format := strings.ToLower(format); format {
+	case "", "console":
+		return "console"
+	case "md":
+		return "markdown"
+	default:
+		return format
+	}
+}
+
+// templateFuncs are the helpers report templates can call besides the text/template builtins
+func templateFuncs(opts *Options) map[string]any {
+	return map[string]any{
+		"percent":   opts.Numbers.Percent,
+		"coverage":  opts.Numbers.Coverage,
+		"threshold": func() float64 { return opts.Threshold },
+		"packages":  getPackagesSortedByName,
+		"uncovered": getTopUncoveredFunctions,
+		"repeat":    strings.Repeat,
+		"upper":     strings.ToUpper,
+		"lower":     strings.ToLower,
+	}
+}
+
+// generateTemplateReport renders a report from a user-defined Go template executed against the
+// analysis result; HTML templates are escaped as html/template does
+func generateTemplateReport(result *models.AnalysisResult, opts *Options, format, templatePath string) error {
+	content, err := renderReportTemplate(result, opts, format, templatePath)
+	if err != nil {
+		return err
+	}
+
+	if format != "html" {
+		return writeOutput(content, opts.OutputFile)
+	}
+
+	outputFile := opts.OutputFile
+	if outputFile == "" {
+		outputFile = "coverage-report.html"
+	}
+	if err := writeOutput(content, outputFile); err != nil {
+		return err
+	}
+	if opts.OpenReport {
+		return OpenInBrowser(outputFile)
+	}
+	return nil
+}
+
+// renderReportTemplate executes the template at templatePath, or the default markdown layout when
+// templatePath is empty
+func renderReportTemplate(result *models.AnalysisResult, opts *Options, format, templatePath string) (string, error) {
+	text := defaultMarkdownTemplate
+	name := "markdown"
+	if templatePath != "" {
+		content, err := os.ReadFile(templatePath)
+		if err != nil {
+			return "", fmt.Errorf("failed to read %s report template: %w", format, err)
+		}
+		text = string(content)
+		name = templatePath
+	}
+
+	var buf bytes.Buffer
+	if format == "html" {
+		tmpl, err := htmltemplate.New(name).Funcs(templateFuncs(opts)).Parse(text)
+		if err != nil {
+			return "", fmt.Errorf("failed to parse %s report template: %w", format, err)
+		}
+		if err := tmpl.Execute(&buf, result); err != nil {
+			return "", fmt.Errorf("failed to render %s report template: %w", format, err)
+		}
+		return buf.String(), nil
+	}
+
+	tmpl, err := texttemplate.New(name).Funcs(templateFuncs(opts)).Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse %s report template: %w", format, err)
+	}
+	if err := tmpl.Execute(&buf, result); err != nil {
+		return "", fmt.Errorf("failed to render %s report template: %w", format, err)
+	}
+	return buf.String(), nil
+}
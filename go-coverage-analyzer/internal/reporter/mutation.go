@@ -0,0 +1,100 @@
+package reporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/beck/go-coverage-analyzer/internal/i18n"
+	"github.com/beck/go-coverage-analyzer/pkg/models"
+)
+
+// GenerateMutationReport outputs mutation testing results in the requested format
+func GenerateMutationReport(report *models.MutationReport, opts *Options) error {
+	switch strings.ToLower(opts.Format) {
+	case "json":
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		return writeOutput(string(data), opts.OutputFile)
+	case "console", "":
+		printMutationReport(report, opts)
+		return nil
+	default:
+		return fmt.Errorf("unsupported output format for mutation report: %s", opts.Format)
+	}
+}
+
+// printMutationReport prints mutation scores per package and the surviving mutants
+func printMutationReport(report *models.MutationReport, opts *Options) {
+	fmt.Printf("%s%sMUTATION TESTING REPORT%s\n", ColorBold, ColorCyan, ColorReset)
+	fmt.Println("=" + strings.Repeat("=", 70) + "=")
+	fmt.Printf("Project: %s%s%s\n", ColorBold, report.ProjectPath, ColorReset)
+	fmt.Printf("Duration: %s%v%s\n\n", ColorBlue, report.Duration, ColorReset)
+
+	fmt.Printf("Mutation Score:          %s%s%s\n",
+		getCoverageColor(report.MutationScore, opts.Threshold), opts.Numbers.Percent(report.MutationScore), ColorReset)
+	fmt.Printf("Total Mutants:           %s%d%s\n", ColorCyan, report.TotalMutants, ColorReset)
+	fmt.Printf("Killed:                  %s%s%d%s\n", ColorGreen, ColorBold, report.Killed, ColorReset)
+	fmt.Printf("Timed Out:               %s%d%s\n", ColorYellow, report.TimedOut, ColorReset)
+	fmt.Printf("Survived:                %s%s%d%s\n", ColorRed, ColorBold, report.Survived, ColorReset)
+	fmt.Printf("Invalid (did not build): %s%d%s\n\n", ColorYellow, report.Invalid, ColorReset)
+
+	if len(report.Packages) > 0 {
+		packages := make([]*models.PackageMutants, 0, len(report.Packages))
+		for _, pkg := range report.Packages {
+			packages = append(packages, pkg)
+		}
+		sort.Slice(packages, func(i, j int) bool {
+			return packages[i].Path < packages[j].Path
+		})
+
+		fmt.Printf("%s%sPACKAGE MUTATION SCORES%s\n", ColorBold, ColorWhite, ColorReset)
+		fmt.Println(strings.Repeat("-", 80))
+		fmt.Printf("%-20s %-10s %-8s %-8s %-10s %-8s %-8s\n", "Package", "Score", "Total", "Killed", "Survived", "Timeout", "Invalid")
+		fmt.Println(strings.Repeat("-", 80))
+
+		for _, pkg := range packages {
+			// Packages sharing a name are told apart by their directory
+			label := pkg.Path
+			if label == "." {
+				label = pkg.Name
+			}
+			fmt.Printf("%-20s %s%8s%s  %-8d %-8d %-10d %-8d %-8d\n",
+				truncate(label, 20),
+				getCoverageColor(pkg.MutationScore, opts.Threshold), opts.Numbers.Percent(pkg.MutationScore), ColorReset,
+				pkg.Total, pkg.Killed, pkg.Survived, pkg.TimedOut, pkg.Invalid)
+		}
+		fmt.Println()
+	}
+
+	survivors := make([]*models.Mutant, 0)
+	for _, mutant := range report.Mutants {
+		if mutant.Status == "survived" {
+			survivors = append(survivors, mutant)
+		}
+	}
+
+	if len(survivors) > 0 {
+		fmt.Printf("%s%sSURVIVING MUTANTS (Top 20)%s\n", ColorBold, ColorWhite, ColorReset)
+		fmt.Println(strings.Repeat("-", 80))
+		for _, mutant := range survivors[:min(20, len(survivors))] {
+			i18n.Printf("%s:%d %-20s %-12s %s%s → %s%s\n",
+				mutant.File, mutant.Line, truncate(mutant.Function, 20), mutant.Operator,
+				ColorRed, mutant.Original, mutant.Mutated, ColorReset)
+		}
+		if len(survivors) > 20 {
+			fmt.Printf("\n%s... and %d more surviving mutants%s\n", ColorYellow, len(survivors)-20, ColorReset)
+		}
+		fmt.Println()
+		i18n.Printf("🎯 Surviving mutants point to code that is executed but not meaningfully asserted\n")
+	}
+
+	for _, errMsg := range report.Errors {
+		i18n.Printf("%s⚠️  %s%s\n", ColorYellow, errMsg, ColorReset)
+	}
+
+	fmt.Println()
+}
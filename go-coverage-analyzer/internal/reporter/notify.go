@@ -0,0 +1,197 @@
+package reporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/beck/go-coverage-analyzer/internal/i18n"
+	"github.com/beck/go-coverage-analyzer/pkg/models"
+)
+
+// notifyTimeout bounds each webhook request
+const notifyTimeout = 10 * time.Second
+
+// defaultNotificationTemplate is used when no message template is configured
+const defaultNotificationTemplate = `Coverage report for {{.Project}}
+Coverage: {{percent .Coverage}}{{if .HasBaseline}} ({{change .Delta}} vs baseline {{percent .BaselineCoverage}}){{end}}
+Functions: {{.TestedFunctions}}/{{.TotalFunctions}} tested, {{.UntestedFunctions}} untested
+{{- if .Regressions}}
+Top regressions:
+{{- range .Regressions}}
+• {{.Package}}: {{percent .Previous}} → {{percent .Current}} ({{change .Delta}})
+{{- end}}
+{{- end}}`
+
+// NotifyOptions configures webhook notifications
+type NotifyOptions struct {
+	SlackWebhookURL string
+	TeamsWebhookURL string
+	MessageTemplate string
+	MaxRegressions  int
+	Baseline        *models.CoverageSnapshot // previous snapshot to compare against, may be nil
+	Numbers         *NumberFormat            // decimals of the percent and change template functions, nil for the defaults
+	Verbose         bool
+}
+
+// NotificationSummary is the data available to notification message templates
+type NotificationSummary struct {
+	Project           string
+	Coverage          float64
+	HasBaseline       bool
+	BaselineCoverage  float64
+	Delta             float64
+	TotalFunctions    int
+	TestedFunctions   int
+	UntestedFunctions int
+	Regressions       []*PackageRegression
+}
+
+// PackageRegression describes a package whose coverage dropped since the baseline
+type PackageRegression struct {
+	Package  string
+	Previous float64
+	Current  float64
+	Delta    float64
+}
+
+// Notify posts a coverage summary to the configured Slack and Teams webhooks
+func Notify(result *models.AnalysisResult, opts *NotifyOptions) error {
+	if opts.SlackWebhookURL == "" && opts.TeamsWebhookURL == "" {
+		return fmt.Errorf("no notification webhook configured")
+	}
+
+	message, err := renderNotification(buildNotificationSummary(result, opts), opts.MessageTemplate, opts.Numbers)
+	if err != nil {
+		return err
+	}
+
+	client := &http.Client{Timeout: notifyTimeout}
+	errors := make([]string, 0)
+
+	// Slack and Teams incoming webhooks both accept a simple text payload
+	webhooks := map[string]string{
+		"Slack": opts.SlackWebhookURL,
+		"Teams": opts.TeamsWebhookURL,
+	}
+	for _, service := range []string{"Slack", "Teams"} {
+		url := webhooks[service]
+		if url == "" {
+			continue
+		}
+
+		if err := postWebhook(client, url, message); err != nil {
+			errors = append(errors, fmt.Sprintf("%s: %v", service, err))
+			continue
+		}
+
+		if opts.Verbose {
+			i18n.Printf("📣 Sent coverage summary to %s\n", service)
+		}
+	}
+
+	if len(errors) > 0 {
+		return fmt.Errorf("notification failed: %s", strings.Join(errors, "; "))
+	}
+
+	return nil
+}
+
+// buildNotificationSummary collects the coverage figures and regressions for a notification
+func buildNotificationSummary(result *models.AnalysisResult, opts *NotifyOptions) *NotificationSummary {
+	summary := &NotificationSummary{
+		Project:     result.ProjectPath,
+		Coverage:    result.OverallCoverage,
+		Regressions: make([]*PackageRegression, 0),
+	}
+
+	if result.Summary != nil {
+		summary.TotalFunctions = result.Summary.TotalFunctions
+		summary.TestedFunctions = result.Summary.TestedFunctions
+		summary.UntestedFunctions = result.Summary.UntestedFunctions
+	}
+
+	if opts.Baseline == nil {
+		return summary
+	}
+
+	summary.HasBaseline = true
+	summary.BaselineCoverage = opts.Baseline.OverallCoverage
+	summary.Delta = result.OverallCoverage - opts.Baseline.OverallCoverage
+
+	for name, pkg := range result.PackageCoverage {
+		previous, exists := opts.Baseline.Packages[name]
+		if !exists || pkg.Coverage >= previous {
+			continue
+		}
+
+		summary.Regressions = append(summary.Regressions, &PackageRegression{
+			Package:  name,
+			Previous: previous,
+			Current:  pkg.Coverage,
+			Delta:    pkg.Coverage - previous,
+		})
+	}
+
+	// Largest drops first
+	sort.Slice(summary.Regressions, func(i, j int) bool {
+		if summary.Regressions[i].Delta != summary.Regressions[j].Delta {
+			return summary.Regressions[i].Delta < summary.Regressions[j].Delta
+		}
+		return summary.Regressions[i].Package < summary.Regressions[j].Package
+	})
+
+	if opts.MaxRegressions > 0 && len(summary.Regressions) > opts.MaxRegressions {
+		summary.Regressions = summary.Regressions[:opts.MaxRegressions]
+	}
+
+	return summary
+}
+
+// renderNotification executes the message template against the summary, with percent and change
+// formatting percentages and their changes in the number format
+func renderNotification(summary *NotificationSummary, messageTemplate string, numbers *NumberFormat) (string, error) {
+	if messageTemplate == "" {
+		messageTemplate = defaultNotificationTemplate
+	}
+
+	tmpl, err := template.New("notification").Funcs(template.FuncMap{
+		"percent": numbers.Percent,
+		"change":  numbers.Change,
+	}).Parse(messageTemplate)
+	if err != nil {
+		return "", fmt.Errorf("invalid notification template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, summary); err != nil {
+		return "", fmt.Errorf("failed to render notification: %w", err)
+	}
+
+	return buf.String(), nil
+}
+
+// postWebhook sends a text message to an incoming webhook
+func postWebhook(client *http.Client, url, message string) error {
+	payload, err := json.Marshal(map[string]string{"text": message})
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	resp, err := client.Post(url, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package reporter
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// DefaultPrecision is the number of decimals of the percentages reports print
+const DefaultPrecision = 1
+
+// maxPrecision bounds --precision, past which coverage percentages only print noise
+const maxPrecision = 4
+
+// NumberFormat is how reports print percentages: their decimals, and whether coverage leads with
+// covered/total counts instead, which audits often ask for since percentages hide the size of the
+// code behind them. A nil format prints DefaultPrecision decimals and no counts
+type NumberFormat struct {
+	Precision int
+	Absolute  bool
+}
+
+// NewNumberFormat returns the format of --precision and --absolute, rejecting decimals out of range
+func NewNumberFormat(decimals int, absolute bool) (*NumberFormat, error) {
+	if decimals < 0 || decimals > maxPrecision {
+		return nil, fmt.Errorf("invalid precision %d: use 0 to %d decimals", decimals, maxPrecision)
+	}
+	return &NumberFormat{Precision: decimals, Absolute: absolute}, nil
+}
+
+// Percent formats a percentage with the configured decimals, e.g. "38.8%"
+func (f *NumberFormat) Percent(value float64) string {
+	return f.Number(value) + "%"
+}
+
+// Number formats a percentage as a plain number with the configured decimals, e.g. "38.8"
+func (f *NumberFormat) Number(value float64) string {
+	precision := DefaultPrecision
+	if f != nil {
+		precision = f.Precision
+	}
+	return strconv.FormatFloat(value, 'f', precision, 64)
+}
+
+// Coverage formats the coverage of covered out of total statements or functions: the percentage,
+// or in absolute mode the counts followed by it, e.g. "78/201 (38.8%)"
+func (f *NumberFormat) Coverage(covered, total int, coverage float64) string {
+	if f == nil || !f.Absolute {
+		return f.Percent(coverage)
+	}
+	return fmt.Sprintf("%d/%d (%s)", covered, total, f.Percent(coverage))
+}
+
+// Change formats a change of a percentage with its sign, e.g. "+1.2%"
+func (f *NumberFormat) Change(value float64) string {
+	if value >= 0 {
+		return "+" + f.Percent(value)
+	}
+	return f.Percent(value)
+}
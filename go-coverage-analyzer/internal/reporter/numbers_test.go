@@ -0,0 +1,63 @@
+package reporter
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNumberFormat(t *testing.T) {
+	tests := []struct {
+		name         string
+		format       *NumberFormat
+		wantPercent  string
+		wantCoverage string
+		wantChange   string
+	}{
+		{"defaults", nil, "38.8%", "38.8%", "-1.2%"},
+		{"no decimals", &NumberFormat{Precision: 0}, "39%", "39%", "-1%"},
+		{"absolute", &NumberFormat{Precision: 3, Absolute: true}, "38.806%", "78/201 (38.806%)", "-1.250%"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.format.Percent(38.80597); got != tt.wantPercent {
+				t.Errorf("Percent() = %q, want %q", got, tt.wantPercent)
+			}
+			if got := tt.format.Coverage(78, 201, 38.80597); got != tt.wantCoverage {
+				t.Errorf("Coverage() = %q, want %q", got, tt.wantCoverage)
+			}
+			if got := tt.format.Change(-1.25); got != tt.wantChange {
+				t.Errorf("Change() = %q, want %q", got, tt.wantChange)
+			}
+		})
+	}
+}
+
+func TestNewNumberFormatRejectsPrecisionOutOfRange(t *testing.T) {
+	for _, decimals := range []int{-1, maxPrecision + 1} {
+		if _, err := NewNumberFormat(decimals, false); err == nil {
+			t.Errorf("NewNumberFormat(%d) error = nil, want an error", decimals)
+		}
+	}
+}
+
+func TestRenderNotificationUsesNumberFormat(t *testing.T) {
+	summary := &NotificationSummary{Project: "calc", Coverage: 75.125, HasBaseline: true, BaselineCoverage: 70, Delta: 5.125}
+
+	// Rendering with one format leaves the next rendering with the defaults unchanged
+	for _, tt := range []struct {
+		numbers *NumberFormat
+		want    string
+	}{
+		{&NumberFormat{Precision: 2}, "Coverage: 75.12% (+5.12% vs baseline 70.00%)"},
+		{nil, "Coverage: 75.1% (+5.1% vs baseline 70.0%)"},
+	} {
+		message, err := renderNotification(summary, "", tt.numbers)
+		if err != nil {
+			t.Fatalf("renderNotification() error = %v", err)
+		}
+		if !strings.Contains(message, tt.want) {
+			t.Errorf("renderNotification() = %q, want %q", message, tt.want)
+		}
+	}
+}
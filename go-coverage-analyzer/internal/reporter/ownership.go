@@ -0,0 +1,53 @@
+package reporter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/beck/go-coverage-analyzer/internal/i18n"
+	"github.com/beck/go-coverage-analyzer/pkg/models"
+)
+
+// printOwnerDetails prints coverage aggregated per owner
+func printOwnerDetails(result *models.AnalysisResult, opts *Options) {
+	if len(result.OwnerCoverage) == 0 {
+		return
+	}
+
+	fmt.Printf("%s%sCOVERAGE BY OWNER%s\n", ColorBold, ColorWhite, ColorReset)
+	fmt.Println(strings.Repeat("-", 80))
+	fmt.Printf("%-30s %-10s %-10s %-12s %-8s\n", "Owner", "Coverage", "Files", "Functions", "Untested")
+	fmt.Println(strings.Repeat("-", 80))
+
+	for _, owner := range result.OwnerCoverage {
+		fmt.Printf("%-30s %s%8s%s  %-10d %5d/%-6d %-8d\n",
+			truncate(owner.Owner, 30),
+			getCoverageColor(owner.Coverage, opts.Threshold), opts.Numbers.Percent(owner.Coverage), ColorReset,
+			owner.Files,
+			owner.CoveredFunctions, owner.TotalFunctions,
+			len(owner.UncoveredFunctions),
+		)
+	}
+
+	fmt.Println()
+}
+
+// printUncoveredByOwner prints the most complex uncovered functions for each owner
+func printUncoveredByOwner(result *models.AnalysisResult) {
+	fmt.Printf("%s%sUNCOVERED FUNCTIONS BY OWNER (Top 5 each)%s\n", ColorBold, ColorWhite, ColorReset)
+	fmt.Println(strings.Repeat("-", 80))
+
+	for _, owner := range result.OwnerCoverage {
+		if len(owner.UncoveredFunctions) == 0 {
+			continue
+		}
+
+		fmt.Printf("%s%s%s (%d uncovered)\n", ColorBold, owner.Owner, ColorReset, len(owner.UncoveredFunctions))
+		for _, function := range owner.UncoveredFunctions[:min(5, len(owner.UncoveredFunctions))] {
+			i18n.Printf("   • %-30s %s:%d (complexity %d)\n",
+				truncate(function.Name, 30), function.File, function.StartLine, function.Complexity)
+		}
+	}
+
+	fmt.Println()
+}
@@ -0,0 +1,131 @@
+package reporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/beck/go-coverage-analyzer/internal/i18n"
+	"github.com/beck/go-coverage-analyzer/pkg/models"
+)
+
+// GeneratePrioritizationReport outputs a ranked testing backlog in the requested format
+func GeneratePrioritizationReport(priorities []*models.TestPriority, result *models.AnalysisResult, opts *Options) error {
+	switch strings.ToLower(opts.Format) {
+	case "json":
+		return generatePrioritizationJSON(priorities, result, opts)
+	case "markdown", "md":
+		return writeOutput(generatePrioritizationMarkdown(priorities, result, opts.Numbers), opts.OutputFile)
+	case "console", "":
+		printPrioritization(priorities, result, opts.Numbers)
+		return nil
+	default:
+		return fmt.Errorf("unsupported output format for prioritization: %s", opts.Format)
+	}
+}
+
+// printPrioritization prints the testing backlog as a console table
+func printPrioritization(priorities []*models.TestPriority, result *models.AnalysisResult, numbers *NumberFormat) {
+	fmt.Printf("%s%sTEST PRIORITIZATION BACKLOG%s\n", ColorBold, ColorCyan, ColorReset)
+	fmt.Println("=" + strings.Repeat("=", 100) + "=")
+	fmt.Printf("Current Coverage: %s%s%s\n\n", ColorBold, numbers.Percent(result.OverallCoverage), ColorReset)
+
+	if len(priorities) == 0 {
+		i18n.Printf("%s✅ No uncovered functions to prioritize%s\n", ColorGreen, ColorReset)
+		return
+	}
+
+	fmt.Printf("%-5s %-25s %-15s %-8s %-10s %-8s %-10s %-10s\n",
+		"Rank", "Function", "File", "Complex", "Exported", "Effort", "Gain", "Projected")
+	fmt.Println(strings.Repeat("-", 100))
+
+	for _, priority := range priorities {
+		function := priority.Function
+
+		effortColor := ColorGreen
+		switch priority.Effort {
+		case "medium":
+			effortColor = ColorYellow
+		case "high":
+			effortColor = ColorRed
+		}
+
+		exported := "no"
+		if function.IsExported {
+			exported = "yes"
+		}
+
+		fmt.Printf("%-5d %-25s %-15s %7d  %-10s %s%-8s%s %s%8s%s  %8s\n",
+			priority.Rank,
+			truncate(function.Name, 25),
+			truncate(filepath.Base(function.File), 15),
+			function.Complexity,
+			exported,
+			effortColor, priority.Effort, ColorReset,
+			ColorGreen, numbers.Change(priority.EstimatedGain), ColorReset,
+			numbers.Percent(priority.ProjectedCoverage),
+		)
+	}
+
+	fmt.Println()
+	last := priorities[len(priorities)-1]
+	i18n.Printf("🎯 Testing all %d functions would raise coverage by an estimated %s%s%s to %s%s%s\n",
+		len(priorities), ColorBold, numbers.Percent(last.CumulativeGain), ColorReset, ColorBold, numbers.Percent(last.ProjectedCoverage), ColorReset)
+	fmt.Println()
+}
+
+// generatePrioritizationJSON writes the testing backlog as JSON
+func generatePrioritizationJSON(priorities []*models.TestPriority, result *models.AnalysisResult, opts *Options) error {
+	report := struct {
+		ProjectPath     string                 `json:"project_path"`
+		CurrentCoverage float64                `json:"current_coverage"`
+		Priorities      []*models.TestPriority `json:"priorities"`
+	}{
+		ProjectPath:     result.ProjectPath,
+		CurrentCoverage: result.OverallCoverage,
+		Priorities:      priorities,
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+
+	return writeOutput(string(data), opts.OutputFile)
+}
+
+// generatePrioritizationMarkdown renders the testing backlog as a Markdown table
+func generatePrioritizationMarkdown(priorities []*models.TestPriority, result *models.AnalysisResult, numbers *NumberFormat) string {
+	var md strings.Builder
+
+	md.WriteString("# Test Prioritization Backlog\n\n")
+	md.WriteString(fmt.Sprintf("Current coverage: **%s**\n\n", numbers.Percent(result.OverallCoverage)))
+
+	if len(priorities) == 0 {
+		md.WriteString("No uncovered functions to prioritize.\n")
+		return md.String()
+	}
+
+	md.WriteString("| Rank | Function | Location | Complexity | Exported | Effort | Est. Gain | Projected |\n")
+	md.WriteString("|-----:|----------|----------|-----------:|:--------:|--------|----------:|----------:|\n")
+
+	for _, priority := range priorities {
+		function := priority.Function
+		name := function.Name
+		if function.ReceiverType != "" {
+			name = fmt.Sprintf("(%s).%s", function.ReceiverType, function.Name)
+		}
+
+		exported := ""
+		if function.IsExported {
+			exported = "✓"
+		}
+
+		md.WriteString(fmt.Sprintf("| %d | `%s` | %s:%d | %d | %s | %s | %s | %s |\n",
+			priority.Rank, name, function.File, function.StartLine, function.Complexity,
+			exported, priority.Effort, numbers.Change(priority.EstimatedGain), numbers.Percent(priority.ProjectedCoverage)))
+	}
+
+	return md.String()
+}
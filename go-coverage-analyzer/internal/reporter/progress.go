@@ -0,0 +1,78 @@
+package reporter
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/beck/go-coverage-analyzer/pkg/models"
+)
+
+// progressLogInterval is how often plain progress lines are written when not attached to a terminal
+const progressLogInterval = 5 * time.Second
+
+// progressBarWidth is the width of the bar drawn on terminals
+const progressBarWidth = 30
+
+// Progress renders the progress of long analyses and generation runs with an ETA: a bar redrawn in
+// place on a terminal, and a plain line every few seconds otherwise, such as in CI logs
+type Progress struct {
+	w        io.Writer
+	terminal bool
+	stage    string
+	started  time.Time
+	logged   time.Time
+}
+
+// NewProgress creates a progress renderer writing to stderr, keeping stdout for reports
+func NewProgress() *Progress {
+	return &Progress{w: os.Stderr, terminal: IsTerminal(os.Stderr)}
+}
+
+// Func returns the callback analyses and generation runs report their progress to
+func (p *Progress) Func() models.ProgressFunc {
+	return p.Update
+}
+
+// Update renders done of total units of a stage
+func (p *Progress) Update(stage string, done, total int) {
+	if total <= 0 {
+		return
+	}
+
+	now := time.Now()
+	if stage != p.stage {
+		p.stage = stage
+		p.started = now
+		p.logged = time.Time{}
+	}
+
+	eta := ""
+	if done > 0 && done < total {
+		remaining := time.Duration(float64(now.Sub(p.started)) / float64(done) * float64(total-done))
+		eta = fmt.Sprintf(" ETA %s", remaining.Round(time.Second))
+	}
+
+	if p.terminal {
+		fmt.Fprintf(p.w, "\r%s ", stage)
+		FprintProgressBar(p.w, done, total, progressBarWidth)
+		fmt.Fprintf(p.w, "%-12s", eta)
+		if done >= total {
+			fmt.Fprintln(p.w)
+		}
+		return
+	}
+
+	if done < total && now.Sub(p.logged) < progressLogInterval {
+		return
+	}
+	p.logged = now
+	fmt.Fprintf(p.w, "%s: %d/%d (%.1f%%)%s\n", stage, done, total, float64(done)/float64(total)*100, eta)
+}
+
+// IsTerminal reports whether a file is an interactive terminal
+func IsTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -1,6 +1,7 @@
 package reporter
 
 import (
+	"context"
 	"encoding/json"
 	"encoding/xml"
 	"fmt"
@@ -10,40 +11,108 @@ import (
 	"path/filepath"
 	"runtime"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/beck/go-coverage-analyzer/internal/i18n"
 	"github.com/beck/go-coverage-analyzer/pkg/coverage"
 	"github.com/beck/go-coverage-analyzer/pkg/models"
 )
 
 // Options contains configuration for report generation
 type Options struct {
-	Format      string
-	InputFile   string
-	OutputFile  string
-	OpenReport  bool
-	Threshold   float64
-	Verbose     bool
-	ShowDetails bool
-	SortBy      string // name, coverage, complexity
-	FilterBy    string // all, uncovered, low-coverage
-}
-
-// Colors for terminal output
-const (
-	ColorReset  = "\033[0m"
-	ColorRed    = "\033[31m"
-	ColorGreen  = "\033[32m"
-	ColorYellow = "\033[33m"
-	ColorBlue   = "\033[34m"
-	ColorPurple = "\033[35m"
-	ColorCyan   = "\033[36m"
-	ColorWhite  = "\033[37m"
-	ColorBold   = "\033[1m"
-)
+	Format       string
+	InputFile    string
+	OutputFile   string
+	OpenReport   bool
+	Threshold    float64
+	APIThreshold float64 // minimum API coverage, 0 disables the check
+	Verbose      bool
+	ShowDetails  bool
+	SortBy       string // name, coverage, complexity
+	FilterBy     string // all, uncovered, low-coverage
+	GroupBy      string // package, owner, author, type
+	History      []*models.CoverageSnapshot
+
+	IncludePackages []string // Go-style package patterns analyzed when reporting from a profile, empty for all
+	ExcludePackages []string
+	Classification  *coverage.ClassificationRules // nil for the default file classification
+	Classes         []string                      // file classes reported on, empty for production code
+	StreamProfile   bool                          // read the profile line by line, for profiles too large to hold in memory
+	CommandTimeout  time.Duration                 // bounds the go list run mapping packages to directories
+	IncludeInit     bool                          // report init functions and variable initializers as initialization
+	IncludeCgo      bool                          // count the functions of cgo files in the totals
+
+	ShowUncoveredLines bool   // list the line ranges partially covered functions never ran
+	Detail             string // functions, or blocks to add the uncovered line ranges of every function, see DetailBlocks
+	Granularity        string // rows of CSV and TSV reports: package, file or function, see Granularity constants
+	CI                 string // CI server to also print coverage statistics for, see CI constants and Generate
+	UncoveredContext   int    // source lines shown around each uncovered range, 0 for none
+
+	Numbers *NumberFormat // decimals and counts of the percentages printed, nil for the defaults
+
+	Templates map[string]string // Go template files replacing the console, html and markdown layouts, by format
+
+	HighComplexity int // complexity above which functions count as highly complex, 0 to keep the result's
+
+	// Function limits when reporting from a profile, 0 for the defaults
+	MaxFunctionLines int
+	MaxParams        int
+}
 
-// Generate creates and outputs a coverage report based on the specified format
+// highComplexity returns the complexity above which functions count as highly complex
+func (o *Options) highComplexity() int {
+	if o.HighComplexity > 0 {
+		return o.HighComplexity
+	}
+	return models.DefaultHighComplexityThreshold
+}
+
+// Generate creates and outputs a coverage report based on the specified format, followed by the
+// coverage statistics of the CI server when one is set
 func Generate(result *models.AnalysisResult, opts *Options) error {
+	if err := generateReport(result, opts); err != nil {
+		return err
+	}
+	if opts.CI == "" {
+		return nil
+	}
+	if reportsToStdout(opts) {
+		// Statistics after a JSON, SARIF or CSV report would leave it unreadable to the tools it is
+		// for, so they go to stderr, which CI servers read from the build log as well
+		return WriteCIStatistics(os.Stderr, result, opts.CI, opts.Numbers)
+	}
+	return WriteCIStatistics(os.Stdout, result, opts.CI, opts.Numbers)
+}
+
+// reportsToStdout reports whether a report other than the console one is written to stdout, as those
+// of every format written to no file but HTML are
+func reportsToStdout(opts *Options) bool {
+	if opts.OutputFile != "" {
+		return false
+	}
+	switch strings.ToLower(opts.Format) {
+	case "console", "", "html":
+		return false
+	}
+	return true
+}
+
+// generateReport creates and outputs a coverage report based on the specified format
+func generateReport(result *models.AnalysisResult, opts *Options) error {
+	if opts.HighComplexity > 0 && opts.HighComplexity != result.HighComplexityThreshold() {
+		result.SetHighComplexityThreshold(opts.HighComplexity)
+	}
+
+	if format := templateFormat(opts.Format); opts.Templates[format] != "" {
+		return generateTemplateReport(result, opts, format, opts.Templates[format])
+	}
+
+	if opts.Detail == DetailBlocks {
+		applyBlockDetail(result)
+	}
+
 	switch strings.ToLower(opts.Format) {
 	case "json":
 		return generateJSONReport(result, opts)
@@ -51,6 +120,14 @@ func Generate(result *models.AnalysisResult, opts *Options) error {
 		return generateHTMLReport(result, opts)
 	case "xml":
 		return generateXMLReport(result, opts)
+	case "sarif":
+		return generateSARIFReport(result, opts)
+	case "csv":
+		return generateCSVReport(result, opts, ',')
+	case "tsv":
+		return generateCSVReport(result, opts, '\t')
+	case "markdown", "md":
+		return generateTemplateReport(result, opts, "markdown", "")
 	case "console", "":
 		return generateConsoleReport(result, opts)
 	default:
@@ -58,50 +135,107 @@ func Generate(result *models.AnalysisResult, opts *Options) error {
 	}
 }
 
-// GenerateFromProfile generates a report from an existing coverage profile
-func GenerateFromProfile(projectPath string, opts *Options) error {
+// GenerateFromProfile generates a report from an existing coverage profile, returning the analysis
+// result it reported
+func GenerateFromProfile(ctx context.Context, projectPath string, opts *Options) (*models.AnalysisResult, error) {
 	if opts.Verbose {
-		fmt.Printf("📋 Generating report from profile: %s\n", opts.InputFile)
+		i18n.Printf("📋 Generating report from profile: %s\n", opts.InputFile)
 	}
 
-	parser := coverage.NewProfileParser(opts.Verbose)
+	// Parse the existing profile, unless it is streamed during the analysis to keep memory low
+	if !opts.StreamProfile {
+		parser := coverage.NewProfileParser(opts.Verbose)
+		if _, err := parser.ParseProfile(opts.InputFile); err != nil {
+			return nil, fmt.Errorf("failed to parse profile: %w", err)
+		}
+	}
 
-	// Parse the existing profile
-	_, err := parser.ParseProfile(opts.InputFile)
+	result, err := AnalyzeProfile(ctx, projectPath, opts)
 	if err != nil {
-		return fmt.Errorf("failed to parse profile: %w", err)
+		return nil, err
 	}
+	return result, Generate(result, opts)
+}
 
-	// Create analysis engine and analyze with existing profile
+// AnalyzeProfile analyzes a project with the existing profile given as opts.InputFile, as
+// GenerateFromProfile does before reporting
+func AnalyzeProfile(ctx context.Context, projectPath string, opts *Options) (*models.AnalysisResult, error) {
 	engine := coverage.NewAnalysisEngine(opts.Verbose)
 	analysisOpts := &coverage.AnalysisOptions{
-		ProjectPath:     projectPath,
-		ProfilePath:     opts.InputFile,
-		ExcludeDirs:     []string{"vendor", ".git", "node_modules"},
-		IncludeTests:    false,
-		GenerateProfile: false,
+		ProjectPath:      projectPath,
+		ProfilePath:      opts.InputFile,
+		IncludePackages:  opts.IncludePackages,
+		ExcludePackages:  opts.ExcludePackages,
+		Classification:   opts.Classification,
+		Classes:          opts.Classes,
+		StreamProfile:    opts.StreamProfile,
+		CommandTimeout:   opts.CommandTimeout,
+		HighComplexity:   opts.HighComplexity,
+		MaxFunctionLines: opts.MaxFunctionLines,
+		MaxParams:        opts.MaxParams,
+		IncludeInit:      opts.IncludeInit,
+		IncludeCgo:       opts.IncludeCgo,
+		ExcludeDirs:      []string{"vendor", ".git", "node_modules"},
+		IncludeTests:     false,
+		GenerateProfile:  false,
 	}
 
-	result, err := engine.AnalyzeProject(analysisOpts)
+	result, err := engine.AnalyzeProject(ctx, analysisOpts)
 	if err != nil {
-		return fmt.Errorf("failed to analyze project with profile: %w", err)
+		return nil, fmt.Errorf("failed to analyze project with profile: %w", err)
 	}
-
-	return Generate(result, opts)
+	return result, nil
 }
 
 // generateConsoleReport creates a rich human-readable console report
 func generateConsoleReport(result *models.AnalysisResult, opts *Options) error {
 	printHeader(result)
-	printOverallSummary(result, opts.Threshold)
+	printOverallSummary(result, opts.Threshold, opts.Numbers)
+	printAPICoverage(result, opts.APIThreshold, opts.Numbers)
+	printInitialization(result, opts)
+	printScopedFunctions(result, opts)
+
+	groupByOwner := opts.GroupBy == "owner" && len(result.OwnerCoverage) > 0
+	if groupByOwner {
+		printOwnerDetails(result, opts)
+	}
+	groupByAuthor := opts.GroupBy == "author" && len(result.AuthorCoverage) > 0
+	if groupByAuthor {
+		printAuthorDetails(result)
+	}
+	printAreaDetails(result, opts)
+	if opts.GroupBy == "type" {
+		printTypeDetails(result, opts)
+	}
 
 	if opts.ShowDetails {
-		printPackageDetails(result, opts)
+		if groupByOwner {
+			printUncoveredByOwner(result)
+		} else if groupByAuthor {
+			printUncoveredByAuthor(result)
+		} else {
+			printPackageDetails(result, opts)
+		}
 		printUncoveredFunctions(result, opts)
+		printUntestedAPI(result)
+		printPossiblyDeadFunctions(result)
+		printUntestableFunctions(result)
+		printWeaklyCoveredFunctions(result, opts.Numbers)
+		printTestMatrix(result)
+		printHardToTestFunctions(result)
+		printClockDependencies(result)
 		printComplexityAnalysis(result)
+		printOversizedFunctions(result, opts.Numbers)
+		printUncoveredClosures(result)
+		printRiskyFunctions(result, opts.Numbers)
+		printErrorPaths(result)
 	}
 
-	printRecommendations(result, opts.Threshold)
+	if opts.ShowUncoveredLines {
+		printUncoveredLines(result, opts)
+	}
+
+	printRecommendations(result, opts.Threshold, opts.Numbers)
 	return nil
 }
 
@@ -124,11 +258,15 @@ func printHeader(result *models.AnalysisResult) {
 		fmt.Printf("Go Version: %s%s%s\n", ColorBlue, result.Metadata.GoVersion, ColorReset)
 	}
 
+	if result.Metadata.Scope != "" {
+		fmt.Printf("Scope: %s%s%s\n", ColorBlue, result.Metadata.Scope, ColorReset)
+	}
+
 	fmt.Println()
 }
 
 // printOverallSummary prints the overall coverage summary
-func printOverallSummary(result *models.AnalysisResult, threshold float64) {
+func printOverallSummary(result *models.AnalysisResult, threshold float64, numbers *NumberFormat) {
 	summary := result.Summary
 
 	fmt.Printf("%s%sOVERALL SUMMARY%s\n", ColorBold, ColorWhite, ColorReset)
@@ -136,10 +274,13 @@ func printOverallSummary(result *models.AnalysisResult, threshold float64) {
 
 	// Overall coverage with color coding
 	coverageColor := getCoverageColor(result.OverallCoverage, threshold)
-	fmt.Printf("Overall Coverage:        %s%.1f%%%s\n", coverageColor, result.OverallCoverage, ColorReset)
-	fmt.Printf("Function Coverage:       %s%.1f%%%s\n", getCoverageColor(summary.FunctionCoverage, threshold), summary.FunctionCoverage, ColorReset)
-	fmt.Printf("Line Coverage:           %s%.1f%%%s\n", getCoverageColor(summary.LineCoverage, threshold), summary.LineCoverage, ColorReset)
-	fmt.Printf("Branch Coverage:         %s%.1f%%%s\n", getCoverageColor(summary.BranchCoverage, threshold), summary.BranchCoverage, ColorReset)
+	fmt.Printf("Overall Coverage:        %s%s%s\n", coverageColor,
+		numbers.Coverage(summary.CoveredLines, summary.TotalLines, result.OverallCoverage), ColorReset)
+	fmt.Printf("Function Coverage:       %s%s%s\n", getCoverageColor(summary.FunctionCoverage, threshold),
+		numbers.Coverage(summary.TestedFunctions, summary.TotalFunctions, summary.FunctionCoverage), ColorReset)
+	fmt.Printf("Line Coverage:           %s%s%s\n", getCoverageColor(summary.LineCoverage, threshold),
+		numbers.Coverage(summary.CoveredLines, summary.TotalLines, summary.LineCoverage), ColorReset)
+	fmt.Printf("Branch Coverage:         %s%s%s\n", getCoverageColor(summary.BranchCoverage, threshold), numbers.Percent(summary.BranchCoverage), ColorReset)
 
 	fmt.Println()
 
@@ -149,6 +290,13 @@ func printOverallSummary(result *models.AnalysisResult, threshold float64) {
 	fmt.Printf("Total Functions:         %s%d%s\n", ColorCyan, summary.TotalFunctions, ColorReset)
 	fmt.Printf("Tested Functions:        %s%s%d%s\n", ColorGreen, ColorBold, summary.TestedFunctions, ColorReset)
 	fmt.Printf("Untested Functions:      %s%s%d%s\n", ColorRed, ColorBold, summary.UntestedFunctions, ColorReset)
+	if summary.UntestableFunctions > 0 {
+		fmt.Printf("Untestable Functions:    %s%d%s (%s)\n",
+			ColorYellow, summary.UntestableFunctions, ColorReset, untestableReasons(result.UntestableFunctions))
+	}
+	if excluded := excludedFilesSummary(result.Metadata); excluded != "" {
+		fmt.Printf("Excluded Files:          %s%s%s\n", ColorYellow, excluded, ColorReset)
+	}
 
 	fmt.Println()
 
@@ -159,22 +307,98 @@ func printOverallSummary(result *models.AnalysisResult, threshold float64) {
 
 	fmt.Println()
 
+	// Test depth, which coverage alone does not show
+	if summary.TotalTestFiles > 0 {
+		fmt.Printf("Test Functions:          %s%d%s in %d files\n", ColorCyan, summary.TestFunctions, ColorReset, summary.TotalTestFiles)
+		fmt.Printf("Assertions per Test:     %s%.1f%s\n", ColorCyan, summary.AssertionsPerTest, ColorReset)
+		fmt.Printf("Test-to-Code Ratio:      %s%.2f%s (%d test lines, %d code lines)\n",
+			ColorCyan, summary.TestToCodeRatio, ColorReset, summary.TestLines, summary.CodeLines)
+		if summary.AssertionFreeTests > 0 {
+			fmt.Printf("Assertion-Free Tests:    %s%d%s (%d functions weakly covered)\n",
+				ColorYellow, summary.AssertionFreeTests, ColorReset, summary.WeaklyCoveredFunctions)
+		}
+		fmt.Println()
+	}
+
 	// Complexity metrics
 	if summary.TotalComplexity > 0 {
 		fmt.Printf("Average Complexity:      %s%.1f%s\n", ColorYellow, summary.AvgComplexity, ColorReset)
 		fmt.Printf("Max Complexity:          %s%d%s\n", ColorYellow, summary.MaxComplexity, ColorReset)
-		fmt.Printf("High Complexity (>10):   %s%d%s\n", ColorYellow, summary.HighComplexityFunctions, ColorReset)
+		fmt.Printf("%-25s%s%d%s\n", fmt.Sprintf("High Complexity (>%d):", result.HighComplexityThreshold()),
+			ColorYellow, summary.HighComplexityFunctions, ColorReset)
+	}
+	if summary.UncoveredClosures > 0 {
+		fmt.Printf("Uncovered Closures:      %s%d%s (never ran inside covered functions)\n",
+			ColorYellow, summary.UncoveredClosures, ColorReset)
+	}
+	if summary.OversizedFunctions > 0 {
+		fmt.Printf("Oversized Functions:     %s%d%s (over %d lines or %d parameters)\n",
+			ColorYellow, summary.OversizedFunctions, ColorReset, summary.FunctionLineLimit, summary.ParamLimit)
 	}
 
 	fmt.Println()
 
 	// Coverage threshold check
 	if result.OverallCoverage < threshold {
-		fmt.Printf("%s⚠️  WARNING: Coverage %.1f%% is below threshold %.1f%%%s\n",
-			ColorRed, result.OverallCoverage, threshold, ColorReset)
+		i18n.Printf("%s⚠️  WARNING: Coverage %s is below threshold %s%s\n",
+			ColorRed, numbers.Percent(result.OverallCoverage), numbers.Percent(threshold), ColorReset)
 	} else {
-		fmt.Printf("%s✅ Coverage %.1f%% meets threshold %.1f%%%s\n",
-			ColorGreen, result.OverallCoverage, threshold, ColorReset)
+		i18n.Printf("%s✅ Coverage %s meets threshold %s%s\n",
+			ColorGreen, numbers.Percent(result.OverallCoverage), numbers.Percent(threshold), ColorReset)
+	}
+
+	fmt.Println()
+}
+
+// excludedFilesSummary describes the files left out of coverage by class, e.g. "3 generated, 1 helper"
+func excludedFilesSummary(metadata *models.Metadata) string {
+	if metadata == nil || len(metadata.ExcludedFiles) == 0 {
+		return ""
+	}
+
+	classes := make([]string, 0, len(metadata.ExcludedFiles))
+	for class := range metadata.ExcludedFiles {
+		classes = append(classes, class)
+	}
+	sort.Strings(classes)
+
+	parts := make([]string, len(classes))
+	for i, class := range classes {
+		parts[i] = fmt.Sprintf("%d %s", metadata.ExcludedFiles[class], class)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// printAPICoverage prints the exported API coverage metric
+func printAPICoverage(result *models.AnalysisResult, apiThreshold float64, numbers *NumberFormat) {
+	summary := result.Summary
+	if summary.ExportedAPI == 0 {
+		return
+	}
+
+	fmt.Printf("%s%sAPI COVERAGE%s\n", ColorBold, ColorWhite, ColorReset)
+	fmt.Println(strings.Repeat("-", 50))
+
+	threshold := apiThreshold
+	if threshold <= 0 {
+		threshold = 80.0
+	}
+
+	fmt.Printf("API Coverage:            %s%s%s\n", getCoverageColor(result.APICoverage, threshold),
+		numbers.Coverage(summary.TestedAPI, summary.ExportedAPI, result.APICoverage), ColorReset)
+	fmt.Printf("Exported API:            %s%d%s\n", ColorCyan, summary.ExportedAPI, ColorReset)
+	fmt.Printf("Referenced by Tests:     %s%s%d%s\n", ColorGreen, ColorBold, summary.TestedAPI, ColorReset)
+	fmt.Printf("Untested API:            %s%s%d%s\n", ColorRed, ColorBold, summary.ExportedAPI-summary.TestedAPI, ColorReset)
+
+	if apiThreshold > 0 {
+		fmt.Println()
+		if result.APICoverage < apiThreshold {
+			i18n.Printf("%s⚠️  WARNING: API coverage %s is below threshold %s%s\n",
+				ColorRed, numbers.Percent(result.APICoverage), numbers.Percent(apiThreshold), ColorReset)
+		} else {
+			i18n.Printf("%s✅ API coverage %s meets threshold %s%s\n",
+				ColorGreen, numbers.Percent(result.APICoverage), numbers.Percent(apiThreshold), ColorReset)
+		}
 	}
 
 	fmt.Println()
@@ -217,20 +441,20 @@ func printPackageDetails(result *models.AnalysisResult, opts *Options) {
 			continue
 		}
 
-		status := "✅"
+		status := i18n.T("✅")
 		statusColor := ColorGreen
 		if pkg.Coverage < opts.Threshold {
-			status = "⚠️"
+			status = i18n.T("⚠️")
 			statusColor = ColorYellow
 			if pkg.Coverage == 0 {
-				status = "❌"
+				status = i18n.T("❌")
 				statusColor = ColorRed
 			}
 		}
 
-		fmt.Printf("%-20s %s%7.1f%%%s %7d/%-3d %7d/%-5d %10d %s%s%s\n",
+		fmt.Printf("%-20s %s%8s%s %7d/%-3d %7d/%-5d %10d %s%s%s\n",
 			truncate(pkg.Name, 20),
-			getCoverageColor(pkg.Coverage, opts.Threshold), pkg.Coverage, ColorReset,
+			getCoverageColor(pkg.Coverage, opts.Threshold), opts.Numbers.Percent(pkg.Coverage), ColorReset,
 			pkg.CoveredFunctions, pkg.TotalFunctions,
 			pkg.CoveredLines, pkg.TotalLines,
 			pkg.Complexity,
@@ -247,10 +471,21 @@ func printUncoveredFunctions(result *models.AnalysisResult, opts *Options) {
 		return
 	}
 
+	// Uncovered functions have authors when the analysis blamed them
+	blamed := len(result.AuthorCoverage) > 0
+	width := 90
+	if blamed {
+		width = 111
+	}
+
 	fmt.Printf("%s%sUNCOVERED FUNCTIONS (Top 20)%s\n", ColorBold, ColorWhite, ColorReset)
-	fmt.Println(strings.Repeat("-", 90))
-	fmt.Printf("%-25s %-20s %-15s %-10s %-15s\n", "Function", "Package", "File", "Complexity", "Type")
-	fmt.Println(strings.Repeat("-", 90))
+	fmt.Println(strings.Repeat("-", width))
+	fmt.Printf("%-25s %-20s %-15s %-10s %-10s", "Function", "Package", "File", "Complexity", "Type")
+	if blamed {
+		fmt.Printf(" %-20s", "Author")
+	}
+	fmt.Println()
+	fmt.Println(strings.Repeat("-", width))
 
 	count := 0
 	for _, function := range result.UncoveredFunctions {
@@ -263,21 +498,19 @@ func printUncoveredFunctions(result *models.AnalysisResult, opts *Options) {
 			functionType = "Method"
 		}
 
-		complexityColor := ColorGreen
-		if function.Complexity > 5 {
-			complexityColor = ColorYellow
-		}
-		if function.Complexity > 10 {
-			complexityColor = ColorRed
-		}
+		complexityColor := getComplexityColor(function.Complexity, result.HighComplexityThreshold())
 
-		fmt.Printf("%-25s %-20s %-15s %s%7d%s     %-15s\n",
+		fmt.Printf("%-25s %-20s %-15s %s%7d%s     %-10s",
 			truncate(function.Name, 25),
 			truncate(function.Package, 20),
 			truncate(filepath.Base(function.File), 15),
 			complexityColor, function.Complexity, ColorReset,
 			functionType,
 		)
+		if blamed {
+			fmt.Printf(" %-20s", truncate(function.Author, 20))
+		}
+		fmt.Println()
 		count++
 	}
 
@@ -289,23 +522,260 @@ func printUncoveredFunctions(result *models.AnalysisResult, opts *Options) {
 	fmt.Println()
 }
 
+// printUntestedAPI prints exported functions and methods with no direct test references
+func printUntestedAPI(result *models.AnalysisResult) {
+	if len(result.UntestedAPI) == 0 {
+		return
+	}
+
+	fmt.Printf("%s%sUNTESTED EXPORTED API (Top 20)%s\n", ColorBold, ColorWhite, ColorReset)
+	fmt.Println(strings.Repeat("-", 90))
+	fmt.Printf("%-35s %-20s %-25s %-8s\n", "Identifier", "Package", "File", "Line")
+	fmt.Println(strings.Repeat("-", 90))
+
+	for _, function := range result.UntestedAPI[:min(20, len(result.UntestedAPI))] {
+		name := function.Name
+		if function.ReceiverType != "" {
+			name = strings.TrimPrefix(function.ReceiverType, "*") + "." + function.Name
+		}
+
+		fmt.Printf("%-35s %-20s %-25s %-8d\n",
+			truncate(name, 35),
+			truncate(function.Package, 20),
+			truncate(function.File, 25),
+			function.StartLine,
+		)
+	}
+
+	if len(result.UntestedAPI) > 20 {
+		fmt.Printf("\n%s... and %d more untested API identifiers%s\n",
+			ColorYellow, len(result.UntestedAPI)-20, ColorReset)
+	}
+
+	fmt.Println()
+}
+
+// printHardToTestFunctions prints uncovered functions that depend on the environment, files, the network or the clock
+func printHardToTestFunctions(result *models.AnalysisResult) {
+	hard := make([]*models.Function, 0)
+	for _, function := range result.UncoveredFunctions {
+		if len(function.SideEffects()) > 0 {
+			hard = append(hard, function)
+		}
+	}
+	if len(hard) == 0 {
+		return
+	}
+
+	fmt.Printf("%s%sHARD TO TEST%s\n", ColorBold, ColorWhite, ColorReset)
+	fmt.Println(strings.Repeat("-", 90))
+	fmt.Printf("%-35s %-20s %s\n", "Function", "Package", "Depends on")
+	fmt.Println(strings.Repeat("-", 90))
+
+	for _, function := range hard[:min(20, len(hard))] {
+		name := function.Name
+		if function.ReceiverType != "" {
+			name = strings.TrimPrefix(function.ReceiverType, "*") + "." + function.Name
+		}
+
+		fmt.Printf("%-35s %-20s %s\n",
+			truncate(name, 35),
+			truncate(function.Package, 20),
+			strings.Join(function.SideEffects(), ", "),
+		)
+	}
+
+	if len(hard) > 20 {
+		fmt.Printf("\n%s... and %d more hard to test functions%s\n",
+			ColorYellow, len(hard)-20, ColorReset)
+	}
+
+	fmt.Printf("%sConsider injecting these dependencies so tests can replace them.%s\n", ColorYellow, ColorReset)
+	fmt.Println()
+}
+
+// printClockDependencies prints functions that call time.Now and friends directly as clock injection candidates
+func printClockDependencies(result *models.AnalysisResult) {
+	dependent := result.GetClockDependentFunctions()
+	if len(dependent) == 0 {
+		return
+	}
+
+	fmt.Printf("%s%sDIRECT CLOCK DEPENDENCIES%s\n", ColorBold, ColorWhite, ColorReset)
+	fmt.Println(strings.Repeat("-", 90))
+	fmt.Printf("%-35s %-20s %-25s %-8s\n", "Function", "Package", "File", "Line")
+	fmt.Println(strings.Repeat("-", 90))
+
+	for _, function := range dependent[:min(20, len(dependent))] {
+		name := function.Name
+		if function.ReceiverType != "" {
+			name = strings.TrimPrefix(function.ReceiverType, "*") + "." + function.Name
+		}
+
+		fmt.Printf("%-35s %-20s %-25s %-8d\n",
+			truncate(name, 35),
+			truncate(function.Package, 20),
+			truncate(filepath.Base(function.File), 25),
+			function.StartLine,
+		)
+	}
+
+	if len(dependent) > 20 {
+		fmt.Printf("\n%s... and %d more clock dependent functions%s\n",
+			ColorYellow, len(dependent)-20, ColorReset)
+	}
+
+	fmt.Printf("%sConsider passing a clock interface with a Now() time.Time method; generated tests then freeze it.%s\n", ColorYellow, ColorReset)
+	fmt.Println()
+}
+
+// printPossiblyDeadFunctions prints exported functions that have no callers and no tests
+func printPossiblyDeadFunctions(result *models.AnalysisResult) {
+	if len(result.PossiblyDeadFunctions) == 0 {
+		return
+	}
+
+	fmt.Printf("%s%sPOSSIBLY DEAD CODE%s\n", ColorBold, ColorWhite, ColorReset)
+	fmt.Println(strings.Repeat("-", 90))
+	fmt.Printf("%-35s %-20s %-25s %-8s\n", "Function", "Package", "File", "Lines")
+	fmt.Println(strings.Repeat("-", 90))
+
+	for _, function := range result.PossiblyDeadFunctions[:min(20, len(result.PossiblyDeadFunctions))] {
+		name := function.Name
+		if function.ReceiverType != "" {
+			name = strings.TrimPrefix(function.ReceiverType, "*") + "." + function.Name
+		}
+
+		fmt.Printf("%-35s %-20s %-25s %4d-%-4d\n",
+			truncate(name, 35),
+			truncate(function.Package, 20),
+			truncate(function.File, 25),
+			function.StartLine, function.EndLine,
+		)
+	}
+
+	if len(result.PossiblyDeadFunctions) > 20 {
+		fmt.Printf("\n%s... and %d more possibly dead functions%s\n",
+			ColorYellow, len(result.PossiblyDeadFunctions)-20, ColorReset)
+	}
+
+	fmt.Printf("%sNo callers and no tests were found; consider deleting these before writing tests.%s\n", ColorYellow, ColorReset)
+	fmt.Println()
+}
+
+// printTestMatrix prints what each top-level test covered when run on its own, when coverage was
+// attributed to tests
+func printTestMatrix(result *models.AnalysisResult) {
+	if len(result.TestMatrix) == 0 {
+		return
+	}
+
+	fmt.Printf("%s%sTEST ATTRIBUTION%s\n", ColorBold, ColorWhite, ColorReset)
+	fmt.Println(strings.Repeat("-", 90))
+	fmt.Printf("%-35s %-20s %-6s %-26s\n", "Test", "Package", "Result", "Covers")
+	fmt.Println(strings.Repeat("-", 90))
+
+	for _, attribution := range result.TestMatrix[:min(20, len(result.TestMatrix))] {
+		status := "pass"
+		if !attribution.Passed {
+			status = "fail"
+		}
+		covers := "-"
+		if len(attribution.Functions) > 0 {
+			covers = strings.Join(attribution.Functions, ", ")
+		}
+
+		fmt.Printf("%-35s %-20s %-6s %s\n",
+			truncate(attribution.Test, 35),
+			truncate(attribution.Package, 20),
+			status,
+			truncate(covers, 26),
+		)
+	}
+
+	if len(result.TestMatrix) > 20 {
+		fmt.Printf("\n%s... and %d more tests%s\n",
+			ColorYellow, len(result.TestMatrix)-20, ColorReset)
+	}
+	fmt.Println()
+}
+
+// printWeaklyCoveredFunctions prints the tests that assert nothing with the functions they call, and
+// the covered functions no asserting test calls
+func printWeaklyCoveredFunctions(result *models.AnalysisResult, numbers *NumberFormat) {
+	if len(result.AssertionFreeTests) == 0 {
+		return
+	}
+
+	fmt.Printf("%s%sTESTS WITHOUT ASSERTIONS%s\n", ColorBold, ColorWhite, ColorReset)
+	fmt.Println(strings.Repeat("-", 90))
+	fmt.Printf("%-35s %-30s %-22s\n", "Test", "File", "Calls")
+	fmt.Println(strings.Repeat("-", 90))
+
+	for _, test := range result.AssertionFreeTests[:min(20, len(result.AssertionFreeTests))] {
+		calls := "-"
+		if len(test.Calls) > 0 {
+			calls = strings.Join(test.Calls, ", ")
+		}
+
+		fmt.Printf("%-35s %-30s %s\n",
+			truncate(test.Name, 35),
+			truncate(fmt.Sprintf("%s:%d", test.File, test.Line), 30),
+			truncate(calls, 22),
+		)
+	}
+
+	if len(result.AssertionFreeTests) > 20 {
+		fmt.Printf("\n%s... and %d more tests without assertions%s\n",
+			ColorYellow, len(result.AssertionFreeTests)-20, ColorReset)
+	}
+	fmt.Println()
+
+	if len(result.WeaklyCoveredFunctions) == 0 {
+		return
+	}
+
+	fmt.Printf("%s%sWEAKLY COVERED FUNCTIONS%s\n", ColorBold, ColorWhite, ColorReset)
+	fmt.Println(strings.Repeat("-", 90))
+	fmt.Printf("%-35s %-20s %-25s %-8s\n", "Function", "Package", "File", "Coverage")
+	fmt.Println(strings.Repeat("-", 90))
+
+	for _, function := range result.WeaklyCoveredFunctions[:min(20, len(result.WeaklyCoveredFunctions))] {
+		fmt.Printf("%-35s %-20s %-25s %7s\n",
+			truncate(function.Key(), 35),
+			truncate(function.Package, 20),
+			truncate(function.File, 25),
+			numbers.Percent(function.Coverage),
+		)
+	}
+
+	if len(result.WeaklyCoveredFunctions) > 20 {
+		fmt.Printf("\n%s... and %d more weakly covered functions%s\n",
+			ColorYellow, len(result.WeaklyCoveredFunctions)-20, ColorReset)
+	}
+
+	fmt.Printf("%sOnly tests without assertions call these; their coverage does not mean their results are checked.%s\n", ColorYellow, ColorReset)
+	fmt.Println()
+}
+
 // printComplexityAnalysis prints complexity analysis
 func printComplexityAnalysis(result *models.AnalysisResult) {
-	highComplexity := result.GetHighComplexityFunctions(10)
+	threshold := result.HighComplexityThreshold()
+	highComplexity := result.GetHighComplexityFunctions(threshold)
 	if len(highComplexity) == 0 {
 		return
 	}
 
-	fmt.Printf("%s%sHIGH COMPLEXITY FUNCTIONS (>10)%s\n", ColorBold, ColorWhite, ColorReset)
+	fmt.Printf("%s%sHIGH COMPLEXITY FUNCTIONS (>%d)%s\n", ColorBold, ColorWhite, threshold, ColorReset)
 	fmt.Println(strings.Repeat("-", 90))
 	fmt.Printf("%-25s %-20s %-15s %-10s %-10s\n", "Function", "Package", "File", "Complexity", "Covered")
 	fmt.Println(strings.Repeat("-", 90))
 
 	for _, function := range highComplexity[:min(10, len(highComplexity))] {
-		coveredStatus := "❌"
+		coveredStatus := i18n.T("❌")
 		coveredColor := ColorRed
 		if function.IsCovered {
-			coveredStatus = "✅"
+			coveredStatus = i18n.T("✅")
 			coveredColor = ColorGreen
 		}
 
@@ -321,23 +791,173 @@ func printComplexityAnalysis(result *models.AnalysisResult) {
 	fmt.Println()
 }
 
+// printOversizedFunctions prints the functions longer or taking more parameters than the function
+// limits, uncovered ones first
+func printOversizedFunctions(result *models.AnalysisResult, numbers *NumberFormat) {
+	oversized := result.GetOversizedFunctions()
+	if len(oversized) == 0 {
+		return
+	}
+
+	fmt.Printf("%s%sOVERSIZED FUNCTIONS (>%d lines or >%d parameters)%s\n",
+		ColorBold, ColorWhite, result.Summary.FunctionLineLimit, result.Summary.ParamLimit, ColorReset)
+	fmt.Println(strings.Repeat("-", 90))
+	fmt.Printf("%-25s %-20s %-15s %-8s %-8s %-10s\n", "Function", "Package", "File", "Lines", "Params", "Coverage")
+	fmt.Println(strings.Repeat("-", 90))
+
+	for _, function := range oversized[:min(10, len(oversized))] {
+		fmt.Printf("%-25s %-20s %-15s %6d   %6d   %s%8s%s\n",
+			truncate(function.Name, 25),
+			truncate(function.Package, 20),
+			truncate(filepath.Base(function.File), 15),
+			function.Lines, function.ParamCount,
+			getCoverageColor(function.Coverage, 80.0), numbers.Percent(function.Coverage), ColorReset,
+		)
+	}
+
+	if len(oversized) > 10 {
+		fmt.Printf("\n... and %d more oversized functions\n", len(oversized)-10)
+	}
+	fmt.Println()
+}
+
+// printUncoveredClosures prints the closures, such as goroutines and callbacks, that never ran although
+// the function holding them did
+func printUncoveredClosures(result *models.AnalysisResult) {
+	closures := result.GetUncoveredClosures()
+	if len(closures) == 0 {
+		return
+	}
+
+	fmt.Printf("%s%sUNCOVERED CLOSURES%s\n", ColorBold, ColorWhite, ColorReset)
+	fmt.Println(strings.Repeat("-", 90))
+	fmt.Printf("%-40s %-25s %-10s %-10s\n", "Closure", "File", "Lines", "Started by")
+	fmt.Println(strings.Repeat("-", 90))
+
+	for _, closure := range closures[:min(10, len(closures))] {
+		launch := closure.Launch
+		if launch == "" {
+			launch = "call"
+		}
+		fmt.Printf("%-40s %-25s %4d-%-5d %-10s\n",
+			truncate(closure.Key(), 40),
+			truncate(closure.File, 25),
+			closure.StartLine, closure.EndLine,
+			launch,
+		)
+	}
+
+	if len(closures) > 10 {
+		fmt.Printf("\n... and %d more uncovered closures\n", len(closures)-10)
+	}
+	fmt.Println()
+}
+
+// printRiskyFunctions prints the functions with the highest composite risk score
+func printRiskyFunctions(result *models.AnalysisResult, numbers *NumberFormat) {
+	risky := result.GetTopRiskyFunctions(10)
+	if len(risky) == 0 {
+		return
+	}
+
+	fmt.Printf("%s%sTOP RISKY FUNCTIONS%s\n", ColorBold, ColorWhite, ColorReset)
+	fmt.Println(strings.Repeat("-", 90))
+	fmt.Printf("%-25s %-20s %-15s %-8s %-10s %-8s\n", "Function", "Package", "File", "Risk", "Coverage", "MI")
+	fmt.Println(strings.Repeat("-", 90))
+
+	for _, function := range risky {
+		riskColor := ColorGreen
+		if function.RiskScore >= 20 {
+			riskColor = ColorYellow
+		}
+		if function.RiskScore >= 40 {
+			riskColor = ColorRed
+		}
+
+		fmt.Printf("%-25s %-20s %-15s %s%6.1f%s   %8s   %6.1f\n",
+			truncate(function.Name, 25),
+			truncate(function.Package, 20),
+			truncate(filepath.Base(function.File), 15),
+			riskColor, function.RiskScore, ColorReset,
+			numbers.Percent(function.Coverage),
+			function.MaintainabilityIndex,
+		)
+	}
+
+	fmt.Println()
+}
+
+// printErrorPaths prints the functions whose error returns the tests do not all reach
+func printErrorPaths(result *models.AnalysisResult) {
+	var functions []*models.Function
+	for _, pkg := range result.PackageCoverage {
+		for _, file := range pkg.Files {
+			for _, function := range file.Functions {
+				if function.IsTestable && function.CoveredErrorPaths() < len(function.ErrorPaths) {
+					functions = append(functions, function)
+				}
+			}
+		}
+	}
+	if len(functions) == 0 {
+		return
+	}
+
+	sort.Slice(functions, func(i, j int) bool {
+		missedI := len(functions[i].ErrorPaths) - functions[i].CoveredErrorPaths()
+		missedJ := len(functions[j].ErrorPaths) - functions[j].CoveredErrorPaths()
+		if missedI != missedJ {
+			return missedI > missedJ
+		}
+		return functions[i].Name < functions[j].Name
+	})
+
+	fmt.Printf("%s%sERROR PATHS COVERED%s\n", ColorBold, ColorWhite, ColorReset)
+	fmt.Println(strings.Repeat("-", 90))
+	fmt.Printf("%-25s %-20s %-15s %-12s %-15s\n", "Function", "Package", "File", "Covered", "Missed Lines")
+	fmt.Println(strings.Repeat("-", 90))
+
+	for _, function := range functions[:min(10, len(functions))] {
+		var missed []string
+		for _, path := range function.ErrorPaths {
+			if !path.Covered {
+				missed = append(missed, strconv.Itoa(path.Line))
+			}
+		}
+
+		fmt.Printf("%-25s %-20s %-15s %s%5d/%-6d%s %-15s\n",
+			truncate(function.Name, 25),
+			truncate(function.Package, 20),
+			truncate(filepath.Base(function.File), 15),
+			ColorRed, function.CoveredErrorPaths(), len(function.ErrorPaths), ColorReset,
+			truncate(strings.Join(missed, ", "), 15),
+		)
+	}
+
+	if len(functions) > 10 {
+		fmt.Printf("\n... and %d more functions with uncovered error paths\n", len(functions)-10)
+	}
+
+	fmt.Println()
+}
+
 // printRecommendations prints actionable recommendations
-func printRecommendations(result *models.AnalysisResult, threshold float64) {
+func printRecommendations(result *models.AnalysisResult, threshold float64, numbers *NumberFormat) {
 	fmt.Printf("%s%sRECOMMENDATIONS%s\n", ColorBold, ColorWhite, ColorReset)
 	fmt.Println(strings.Repeat("-", 50))
 
 	if result.Summary.UntestedFunctions > 0 {
-		fmt.Printf("🎯 Add tests for %s%d%s untested functions\n",
+		i18n.Printf("🎯 Add tests for %s%d%s untested functions\n",
 			ColorBold, result.Summary.UntestedFunctions, ColorReset)
 	}
 
 	if result.OverallCoverage < threshold {
 		needed := threshold - result.OverallCoverage
-		fmt.Printf("📈 Increase coverage by %s%.1f%%%s to meet threshold\n",
-			ColorYellow, needed, ColorReset)
+		i18n.Printf("📈 Increase coverage by %s%s%s to meet threshold\n",
+			ColorYellow, numbers.Percent(needed), ColorReset)
 	}
 
-	highComplexity := result.GetHighComplexityFunctions(10)
+	highComplexity := result.GetHighComplexityFunctions(result.HighComplexityThreshold())
 	uncoveredHighComplexity := 0
 	for _, fn := range highComplexity {
 		if !fn.IsCovered {
@@ -346,13 +966,39 @@ func printRecommendations(result *models.AnalysisResult, threshold float64) {
 	}
 
 	if uncoveredHighComplexity > 0 {
-		fmt.Printf("⚠️  Prioritize testing %s%d%s high-complexity functions\n",
+		i18n.Printf("⚠️  Prioritize testing %s%d%s high-complexity functions\n",
 			ColorRed, uncoveredHighComplexity, ColorReset)
 	}
 
-	fmt.Printf("🛠️  Run %s'gcov generate'%s to create test templates\n",
+	uncoveredOversized := 0
+	for _, fn := range result.GetOversizedFunctions() {
+		if !fn.IsCovered {
+			uncoveredOversized++
+		}
+	}
+	if uncoveredOversized > 0 {
+		i18n.Printf("📏 Split or test %s%d%s oversized untested functions\n",
+			ColorYellow, uncoveredOversized, ColorReset)
+	}
+
+	if result.Summary.UntestedImplementations > 0 {
+		i18n.Printf("🧩 Test the interface methods of %s%d%s types implementing exported interfaces (--group-by type)\n",
+			ColorYellow, result.Summary.UntestedImplementations, ColorReset)
+	}
+
+	if result.Summary.PossiblyDeadFunctions > 0 {
+		i18n.Printf("🪦 Review %s%d%s possibly dead functions before writing tests for them\n",
+			ColorYellow, result.Summary.PossiblyDeadFunctions, ColorReset)
+	}
+
+	if result.Summary.WeaklyCoveredFunctions > 0 {
+		i18n.Printf("🧪 Add assertions for %s%d%s weakly covered functions\n",
+			ColorYellow, result.Summary.WeaklyCoveredFunctions, ColorReset)
+	}
+
+	i18n.Printf("🛠️  Run %s'gcov generate'%s to create test templates\n",
 		ColorCyan, ColorReset)
-	fmt.Printf("📊 Run %s'gcov report --format=html'%s for detailed HTML report\n",
+	i18n.Printf("📊 Run %s'gcov report --format=html'%s for detailed HTML report\n",
 		ColorCyan, ColorReset)
 
 	fmt.Println()
@@ -382,16 +1028,31 @@ func generateHTMLReport(result *models.AnalysisResult, opts *Options) error {
 	}
 
 	if opts.OpenReport {
-		return openInBrowser(outputFile)
+		return OpenInBrowser(outputFile)
 	}
 
 	if opts.Verbose {
-		fmt.Printf("📄 HTML report generated: %s\n", outputFile)
+		i18n.Printf("📄 HTML report generated: %s\n", outputFile)
 	}
 
 	return nil
 }
 
+// RenderHTML returns the HTML report of an analysis, laid out by the configured html template if any,
+// for serving it instead of writing it to a file
+func RenderHTML(result *models.AnalysisResult, opts *Options) (string, error) {
+	if opts.HighComplexity > 0 && opts.HighComplexity != result.HighComplexityThreshold() {
+		result.SetHighComplexityThreshold(opts.HighComplexity)
+	}
+	if templatePath := opts.Templates["html"]; templatePath != "" {
+		return renderReportTemplate(result, opts, "html", templatePath)
+	}
+	if opts.Detail == DetailBlocks {
+		applyBlockDetail(result)
+	}
+	return generateHTMLContent(result, opts), nil
+}
+
 // generateHTMLContent creates the HTML report content
 func generateHTMLContent(result *models.AnalysisResult, opts *Options) string {
 	tmpl := `<!DOCTYPE html>
@@ -429,6 +1090,11 @@ func generateHTMLContent(result *models.AnalysisResult, opts *Options) string {
         .complexity-high { color: #dc3545; font-weight: bold; }
         .complexity-medium { color: #ffc107; font-weight: bold; }
         .complexity-low { color: #28a745; }
+        .treemap { width: 100%; height: auto; border: 1px solid #ddd; border-radius: 4px; }
+        .treemap rect { stroke: #fff; stroke-width: 1; }
+        .treemap .treemap-package { stroke: #333; fill-opacity: 0.35; }
+        .treemap text { font-size: 11px; fill: #222; pointer-events: none; }
+        .timeline svg { width: 100%; height: auto; border: 1px solid #ddd; border-radius: 4px; }
         .footer { margin-top: 40px; text-align: center; color: #666; font-size: 0.9em; }
     </style>
 </head>
@@ -443,13 +1109,19 @@ func generateHTMLContent(result *models.AnalysisResult, opts *Options) string {
 
         <div class="summary">
             <div class="metric">
-                <div class="metric-value {{getCoverageClass .OverallCoverage}}">{{printf "%.1f%%" .OverallCoverage}}</div>
+                <div class="metric-value {{getCoverageClass .OverallCoverage}}">{{coverage .Summary.CoveredLines .Summary.TotalLines .OverallCoverage}}</div>
                 <div class="metric-label">Overall Coverage</div>
             </div>
             <div class="metric">
-                <div class="metric-value {{getCoverageClass .Summary.FunctionCoverage}}">{{printf "%.1f%%" .Summary.FunctionCoverage}}</div>
+                <div class="metric-value {{getCoverageClass .Summary.FunctionCoverage}}">{{coverage .Summary.TestedFunctions .Summary.TotalFunctions .Summary.FunctionCoverage}}</div>
                 <div class="metric-label">Function Coverage</div>
             </div>
+            {{if .Summary.ExportedAPI}}
+            <div class="metric">
+                <div class="metric-value {{getCoverageClass .APICoverage}}">{{percent .APICoverage}}</div>
+                <div class="metric-label">API Coverage ({{.Summary.TestedAPI}}/{{.Summary.ExportedAPI}})</div>
+            </div>
+            {{end}}
             <div class="metric">
                 <div class="metric-value">{{.Summary.TotalFunctions}}</div>
                 <div class="metric-label">Total Functions</div>
@@ -458,6 +1130,16 @@ func generateHTMLContent(result *models.AnalysisResult, opts *Options) string {
                 <div class="metric-value coverage-danger">{{.Summary.UntestedFunctions}}</div>
                 <div class="metric-label">Untested Functions</div>
             </div>
+            {{if .Summary.TotalTestFiles}}
+            <div class="metric">
+                <div class="metric-value">{{printf "%.1f" .Summary.AssertionsPerTest}}</div>
+                <div class="metric-label">Assertions per Test ({{.Summary.TestFunctions}} tests)</div>
+            </div>
+            <div class="metric">
+                <div class="metric-value">{{printf "%.2f" .Summary.TestToCodeRatio}}</div>
+                <div class="metric-label">Test-to-Code Ratio ({{.Summary.TestLines}}/{{.Summary.CodeLines}} lines)</div>
+            </div>
+            {{end}}
         </div>
 
         <div class="section">
@@ -470,6 +1152,9 @@ func generateHTMLContent(result *models.AnalysisResult, opts *Options) string {
                         <th>Functions</th>
                         <th>Lines</th>
                         <th>Complexity</th>
+                        <th>Tests</th>
+                        <th>Assertions/Test</th>
+                        <th>Test:Code</th>
                     </tr>
                 </thead>
                 <tbody>
@@ -480,16 +1165,170 @@ func generateHTMLContent(result *models.AnalysisResult, opts *Options) string {
                             <div class="progress-bar">
                                 <div class="progress-fill {{getCoverageClass .Coverage}}" style="width: {{.Coverage}}%; background-color: {{getCoverageColor .Coverage}};"></div>
                             </div>
-                            <span class="{{getCoverageClass .Coverage}}">{{printf "%.1f%%" .Coverage}}</span>
+                            <span class="{{getCoverageClass .Coverage}}">{{percent .Coverage}}</span>
                         </td>
                         <td>{{.CoveredFunctions}}/{{.TotalFunctions}}</td>
                         <td>{{.CoveredLines}}/{{.TotalLines}}</td>
                         <td>{{.Complexity}}</td>
+                        <td>{{.TestFunctions}}</td>
+                        <td>{{printf "%.1f" .AssertionsPerTest}}</td>
+                        <td>{{printf "%.2f" .TestToCodeRatio}}</td>
+                    </tr>
+                    {{end}}
+                </tbody>
+            </table>
+        </div>
+
+        {{if .GroupByOwner}}
+        <div class="section">
+            <h2 class="section-title">Coverage by Owner</h2>
+            <table class="packages-table">
+                <thead>
+                    <tr>
+                        <th>Owner</th>
+                        <th>Coverage</th>
+                        <th>Files</th>
+                        <th>Functions</th>
+                        <th>Top Uncovered</th>
+                    </tr>
+                </thead>
+                <tbody>
+                    {{range .OwnerCoverage}}
+                    <tr>
+                        <td>{{.Owner}}</td>
+                        <td>
+                            <div class="progress-bar">
+                                <div class="progress-fill {{getCoverageClass .Coverage}}" style="width: {{.Coverage}}%; background-color: {{getCoverageColor .Coverage}};"></div>
+                            </div>
+                            <span class="{{getCoverageClass .Coverage}}">{{percent .Coverage}}</span>
+                        </td>
+                        <td>{{.Files}}</td>
+                        <td>{{.CoveredFunctions}}/{{.TotalFunctions}}</td>
+                        <td>{{range $i, $f := .UncoveredFunctions}}{{if lt $i 5}}<div><small>{{$f.Name}} ({{$f.File}}:{{$f.StartLine}})</small></div>{{end}}{{end}}</td>
                     </tr>
                     {{end}}
                 </tbody>
             </table>
         </div>
+        {{end}}
+
+        {{if .GroupByAuthor}}
+        <div class="section">
+            <h2 class="section-title">Uncovered Code by Author</h2>
+            <table class="packages-table">
+                <thead>
+                    <tr>
+                        <th>Author</th>
+                        <th>Uncovered Functions</th>
+                        <th>Uncovered Statements</th>
+                        <th>Top Uncovered</th>
+                    </tr>
+                </thead>
+                <tbody>
+                    {{range .AuthorCoverage}}
+                    <tr>
+                        <td>{{.Author}}</td>
+                        <td>{{len .UncoveredFunctions}}</td>
+                        <td>{{.UncoveredStatements}}</td>
+                        <td>{{range $i, $f := .UncoveredFunctions}}{{if lt $i 5}}<div><small>{{$f.Name}} ({{$f.File}}:{{$f.StartLine}})</small></div>{{end}}{{end}}</td>
+                    </tr>
+                    {{end}}
+                </tbody>
+            </table>
+        </div>
+        {{end}}
+
+        {{if .AreaCoverage}}
+        <div class="section">
+            <h2 class="section-title">Coverage by Area</h2>
+            <table class="packages-table">
+                <thead>
+                    <tr>
+                        <th>Area</th>
+                        <th>Coverage</th>
+                        <th>Threshold</th>
+                        <th>Files</th>
+                        <th>Functions</th>
+                        <th>Top Uncovered</th>
+                    </tr>
+                </thead>
+                <tbody>
+                    {{range .AreaCoverage}}
+                    <tr>
+                        <td>{{.Area}}<div><small>{{join .Patterns ", "}}</small></div></td>
+                        <td>
+                            <div class="progress-bar">
+                                <div class="progress-fill {{getCoverageClass .Coverage}}" style="width: {{.Coverage}}%; background-color: {{getCoverageColor .Coverage}};"></div>
+                            </div>
+                            <span class="{{getCoverageClass .Coverage}}">{{percent .Coverage}}</span>
+                        </td>
+                        <td>{{if .Threshold}}{{percent .Threshold}}{{else}}-{{end}}</td>
+                        <td>{{.Files}}</td>
+                        <td>{{.CoveredFunctions}}/{{.TotalFunctions}}</td>
+                        <td>{{range $i, $f := .UncoveredFunctions}}{{if lt $i 5}}<div><small>{{$f.Name}} ({{$f.File}}:{{$f.StartLine}})</small></div>{{end}}{{end}}</td>
+                    </tr>
+                    {{end}}
+                </tbody>
+            </table>
+        </div>
+        {{end}}
+
+        {{if .GroupByType}}
+        <div class="section">
+            <h2 class="section-title">Coverage by Type</h2>
+            <table class="packages-table">
+                <thead>
+                    <tr>
+                        <th>Type</th>
+                        <th>Coverage</th>
+                        <th>Methods</th>
+                        <th>Implements</th>
+                        <th>Uncovered Methods</th>
+                    </tr>
+                </thead>
+                <tbody>
+                    {{range .TypeCoverage}}
+                    <tr>
+                        <td>{{.Package}}.{{.Type}}</td>
+                        <td>
+                            <div class="progress-bar">
+                                <div class="progress-fill {{getCoverageClass .Coverage}}" style="width: {{.Coverage}}%; background-color: {{getCoverageColor .Coverage}};"></div>
+                            </div>
+                            <span class="{{getCoverageClass .Coverage}}">{{percent .Coverage}}</span>
+                        </td>
+                        <td>{{.CoveredMethods}}/{{.TotalMethods}}</td>
+                        <td>{{range .Implements}}<div><small>{{.}}</small></div>{{end}}{{if .UntestedInterfaces}}<div><small class="coverage-danger">untested: {{join .UntestedInterfaces ", "}}</small></div>{{end}}</td>
+                        <td>{{range $i, $m := .UncoveredMethods}}{{if lt $i 5}}<div><small>{{$m}}</small></div>{{end}}{{end}}</td>
+                    </tr>
+                    {{end}}
+                </tbody>
+            </table>
+        </div>
+        {{end}}
+
+        {{if .Treemap.Rects}}
+        <div class="section">
+            <h2 class="section-title">Coverage Heat Map</h2>
+            <p>Packages are sized by lines of code and files are colored by coverage, from red (uncovered) to green (fully covered).</p>
+            <svg class="treemap" viewBox="0 0 {{.Treemap.Width}} {{.Treemap.Height}}" xmlns="http://www.w3.org/2000/svg">
+                {{range .Treemap.Rects}}
+                <g>
+                    <title>{{.Title}}</title>
+                    <rect class="{{if .IsPackage}}treemap-package{{else}}treemap-file{{end}}" x="{{printf "%.1f" .X}}" y="{{printf "%.1f" .Y}}" width="{{printf "%.1f" .W}}" height="{{printf "%.1f" .H}}" fill="{{.Color}}"></rect>
+                    {{if .Label}}<text x="{{printf "%.1f" .LabelX}}" y="{{printf "%.1f" .LabelY}}">{{.Label}}</text>{{end}}
+                </g>
+                {{end}}
+            </svg>
+        </div>
+        {{end}}
+
+        {{if .Timeline}}
+        <div class="section">
+            <h2 class="section-title">Coverage History</h2>
+            <p>Overall coverage (solid) and per-package coverage (dashed) across recorded snapshots.</p>
+            <div class="timeline">{{.Timeline}}</div>
+        </div>
+        {{end}}
 
         {{if .UncoveredFunctions}}
         <div class="section">
@@ -498,13 +1337,113 @@ func generateHTMLContent(result *models.AnalysisResult, opts *Options) string {
                 {{range .TopUncoveredFunctions}}
                 <li>
                     <strong>{{.Name}}</strong> in {{.Package}}
-                    <br><small>{{.File}}:{{.StartLine}} | Complexity: <span class="{{getComplexityClass .Complexity}}">{{.Complexity}}</span></small>
+                    <br><small>{{.File}}:{{.StartLine}} | Complexity: <span class="{{getComplexityClass .Complexity}}">{{.Complexity}}</span>{{if .Author}} | Author: {{.Author}}{{end}}</small>
+                </li>
+                {{end}}
+            </ul>
+        </div>
+        {{end}}
+
+        {{if .PartialFunctions}}
+        <div class="section">
+            <h2 class="section-title">Uncovered Blocks</h2>
+            <p>Lines of partially covered functions that no test ran.</p>
+            <ul class="uncovered-list">
+                {{range .PartialFunctions}}
+                <li>
+                    <strong>{{if .ReceiverType}}{{.ReceiverType}}.{{end}}{{.Name}}</strong> in {{.Package}} ({{percent .Coverage}} covered)
+                    <br><small>{{.File}}: lines {{range $i, $r := .UncoveredBlocks}}{{if $i}}, {{end}}{{$r.StartLine}}{{if ne $r.StartLine $r.EndLine}}-{{$r.EndLine}}{{end}}{{end}}</small>
                 </li>
                 {{end}}
             </ul>
         </div>
         {{end}}
 
+        {{if .UntestedAPI}}
+        <div class="section">
+            <h2 class="section-title">Untested Exported API</h2>
+            <ul class="uncovered-list">
+                {{range .UntestedAPI}}
+                <li>
+                    <strong>{{if .ReceiverType}}{{.ReceiverType}}.{{end}}{{.Name}}</strong> in {{.Package}}
+                    <br><small>{{.File}}:{{.StartLine}} | No direct test references</small>
+                </li>
+                {{end}}
+            </ul>
+        </div>
+        {{end}}
+
+        {{if .PossiblyDeadFunctions}}
+        <div class="section">
+            <h2 class="section-title">Possibly Dead Code</h2>
+            <p>Exported functions with no callers and no tests. Consider deleting them before writing tests.</p>
+            <ul class="uncovered-list">
+                {{range .PossiblyDeadFunctions}}
+                <li>
+                    <strong>{{if .ReceiverType}}{{.ReceiverType}}.{{end}}{{.Name}}</strong> in {{.Package}}
+                    <br><small>{{.File}}:{{.StartLine}}-{{.EndLine}}</small>
+                </li>
+                {{end}}
+            </ul>
+        </div>
+        {{end}}
+
+        {{if .WeaklyCoveredFunctions}}
+        <div class="section">
+            <h2 class="section-title">Weakly Covered Functions</h2>
+            <p>Covered only by tests that make no assertions, so their results are never checked.</p>
+            <ul class="uncovered-list">
+                {{range .WeaklyCoveredFunctions}}
+                <li>
+                    <strong>{{if .ReceiverType}}{{.ReceiverType}}.{{end}}{{.Name}}</strong> in {{.Package}}
+                    <br><small>{{.File}}:{{.StartLine}}-{{.EndLine}} | {{percent .Coverage}} covered</small>
+                </li>
+                {{end}}
+            </ul>
+            {{if .AssertionFreeTests}}
+            <h3>Tests Without Assertions</h3>
+            <ul class="uncovered-list">
+                {{range .AssertionFreeTests}}
+                <li>
+                    <strong>{{.Name}}</strong> in {{.Package}}
+                    <br><small>{{.File}}:{{.Line}}{{if .Calls}} | calls {{range $i, $c := .Calls}}{{if $i}}, {{end}}{{$c}}{{end}}{{end}}</small>
+                </li>
+                {{end}}
+            </ul>
+            {{end}}
+        </div>
+        {{end}}
+
+        {{if .TopRiskyFunctions}}
+        <div class="section">
+            <h2 class="section-title">Top Risky Functions</h2>
+            <table class="packages-table">
+                <thead>
+                    <tr>
+                        <th>Function</th>
+                        <th>Location</th>
+                        <th>Risk</th>
+                        <th>Coverage</th>
+                        <th>Complexity</th>
+                        <th>Maintainability</th>
+                    </tr>
+                </thead>
+                <tbody>
+                    {{range .TopRiskyFunctions}}
+                    <tr>
+                        <td><strong>{{.Name}}</strong></td>
+                        <td>{{.File}}:{{.StartLine}}</td>
+                        <td class="{{getRiskClass .RiskScore}}">{{printf "%.1f" .RiskScore}}</td>
+                        <td class="{{getCoverageClass .Coverage}}">{{percent .Coverage}}</td>
+                        <td class="{{getComplexityClass .Complexity}}">{{.Complexity}}</td>
+                        <td>{{printf "%.1f" .MaintainabilityIndex}}</td>
+                    </tr>
+                    {{end}}
+                </tbody>
+            </table>
+        </div>
+        {{end}}
+
         <div class="footer">
             <p>Report generated by gcov v{{.Metadata.Version}} in {{.Metadata.AnalysisTime}}</p>
         </div>
@@ -529,10 +1468,21 @@ func generateHTMLContent(result *models.AnalysisResult, opts *Options) string {
 			}
 			return "#dc3545"
 		},
+		"percent":  opts.Numbers.Percent,
+		"coverage": opts.Numbers.Coverage,
+		"join":     strings.Join,
 		"getComplexityClass": func(complexity int) string {
-			if complexity > 10 {
+			if threshold := result.HighComplexityThreshold(); complexity > threshold {
+				return "complexity-high"
+			} else if complexity > threshold/2 {
+				return "complexity-medium"
+			}
+			return "complexity-low"
+		},
+		"getRiskClass": func(risk float64) string {
+			if risk >= 40 {
 				return "complexity-high"
-			} else if complexity > 5 {
+			} else if risk >= 20 {
 				return "complexity-medium"
 			}
 			return "complexity-low"
@@ -548,10 +1498,27 @@ func generateHTMLContent(result *models.AnalysisResult, opts *Options) string {
 		*models.AnalysisResult
 		PackagesByName        []*models.Package
 		TopUncoveredFunctions []*models.Function
+		TopRiskyFunctions     []*models.Function
+		PartialFunctions      []*models.Function
+		Treemap               *Treemap
+		Timeline              template.HTML
+		GroupByOwner          bool
+		GroupByAuthor         bool
+		GroupByType           bool
 	}{
 		AnalysisResult:        result,
 		PackagesByName:        getPackagesSortedByName(result),
 		TopUncoveredFunctions: getTopUncoveredFunctions(result, 15),
+		TopRiskyFunctions:     result.GetTopRiskyFunctions(10),
+		PartialFunctions:      getPartialFunctions(result),
+		Treemap:               buildTreemap(result, opts.Numbers),
+		GroupByOwner:          opts.GroupBy == "owner" && len(result.OwnerCoverage) > 0,
+		GroupByAuthor:         opts.GroupBy == "author" && len(result.AuthorCoverage) > 0,
+		GroupByType:           opts.GroupBy == "type" && len(result.TypeCoverage) > 0,
+	}
+
+	if len(opts.History) >= 2 {
+		data.Timeline = template.HTML(renderTimelineSVG(opts.History, opts.Numbers))
 	}
 
 	var buf strings.Builder
@@ -601,8 +1568,9 @@ func generateXMLReport(result *models.AnalysisResult, opts *Options) error {
 				Message string `xml:"message,attr"`
 				Text    string `xml:",chardata"`
 			}{
-				Message: fmt.Sprintf("Coverage %.1f%% below threshold %.1f%%", pkg.Coverage, opts.Threshold),
-				Text:    fmt.Sprintf("Package %s has coverage %.1f%% which is below the required threshold of %.1f%%", pkg.Name, pkg.Coverage, opts.Threshold),
+				Message: fmt.Sprintf("Coverage %s below threshold %s", opts.Numbers.Percent(pkg.Coverage), opts.Numbers.Percent(opts.Threshold)),
+				Text: fmt.Sprintf("Package %s has coverage %s which is below the required threshold of %s",
+					pkg.Name, opts.Numbers.Percent(pkg.Coverage), opts.Numbers.Percent(opts.Threshold)),
 			}
 		}
 
@@ -637,6 +1605,16 @@ func getCoverageColor(coverage, threshold float64) string {
 	return ColorRed
 }
 
+// getComplexityColor colors a complexity red above the high complexity threshold, yellow above half of it
+func getComplexityColor(complexity, threshold int) string {
+	if complexity > threshold {
+		return ColorRed
+	} else if complexity > threshold/2 {
+		return ColorYellow
+	}
+	return ColorGreen
+}
+
 func truncate(s string, length int) string {
 	if len(s) <= length {
 		return s
@@ -660,7 +1638,8 @@ func writeOutput(content, filename string) error {
 	return os.WriteFile(filename, []byte(content), 0644)
 }
 
-func openInBrowser(filename string) error {
+// OpenInBrowser opens a file or URL in the default browser
+func OpenInBrowser(filename string) error {
 	var cmd string
 	var args []string
 
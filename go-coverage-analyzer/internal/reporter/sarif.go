@@ -0,0 +1,234 @@
+package reporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+
+	"github.com/beck/go-coverage-analyzer/internal/generator"
+	"github.com/beck/go-coverage-analyzer/pkg/models"
+)
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifSrcRoot = "%SRCROOT%"
+)
+
+// SARIF rule identifiers for gcov findings
+const (
+	RuleUncoveredFunction       = "uncovered-function"
+	RuleHighComplexityUncovered = "high-complexity-uncovered"
+	RuleSyntaxError             = "syntax-error"
+	RuleCompileError            = "compile-error"
+	RuleRuntimeError            = "runtime-error"
+	RuleFlakyTest               = "flaky-test"
+)
+
+// sarifRuleDescriptions holds the short description and default level for every rule
+var sarifRuleDescriptions = map[string]struct {
+	description string
+	level       string
+}{
+	RuleUncoveredFunction:             {"Function is not covered by any test", "warning"},
+	RuleHighComplexityUncovered:       {"Function with high cyclomatic complexity is not covered by any test", "error"},
+	generator.RuleEmptyTest:           {"Test function has an empty body", "warning"},
+	generator.RuleMissingAssertions:   {"Test function does not contain any assertions", "note"},
+	generator.RuleTableTestMissingRun: {"Table-driven test does not use t.Run for its cases", "note"},
+	generator.RuleTooManyAssertions:   {"Test function makes more assertions than the configured maximum", "note"},
+	generator.RuleMissingHelper:       {"Test helper reports failures without calling t.Helper", "note"},
+	generator.RuleSleepInTest:         {"Test waits with time.Sleep", "warning"},
+	generator.RuleIgnoredError:        {"Test discards an error returned by a call", "error"},
+	generator.RuleLongTest:            {"Test function is longer than the configured maximum", "note"},
+	generator.RuleDuplicateTableCase:  {"Table-driven test repeats a case apart from its name", "warning"},
+	RuleSyntaxError:                   {"Test file has syntax errors", "error"},
+	RuleCompileError:                  {"Tests do not compile", "error"},
+	RuleRuntimeError:                  {"Tests failed during execution", "error"},
+	RuleFlakyTest:                     {"Test alternates between passing and failing across runs", "warning"},
+}
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name  string      `json:"name"`
+	Rules []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string            `json:"id"`
+	ShortDescription sarifMessage      `json:"shortDescription"`
+	DefaultConfig    sarifDefaultLevel `json:"defaultConfiguration"`
+}
+
+type sarifDefaultLevel struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+	EndLine   int `json:"endLine,omitempty"`
+}
+
+// generateSARIFReport outputs coverage findings as a SARIF 2.1 log
+func generateSARIFReport(result *models.AnalysisResult, opts *Options) error {
+	results := make([]sarifResult, 0)
+
+	for _, function := range result.UncoveredFunctions {
+		rule := RuleUncoveredFunction
+		message := fmt.Sprintf("Function %s is not covered by tests", function.Name)
+		if function.Complexity > result.HighComplexityThreshold() {
+			rule = RuleHighComplexityUncovered
+			message = fmt.Sprintf("Function %s has complexity %d and is not covered by tests", function.Name, function.Complexity)
+		}
+
+		results = append(results, newSARIFResult(rule, message, function.File, function.StartLine, function.EndLine))
+	}
+
+	return writeSARIF(results, []string{RuleUncoveredFunction, RuleHighComplexityUncovered}, opts)
+}
+
+// generateValidationSARIF outputs test validation findings as a SARIF 2.1 log
+func generateValidationSARIF(result *generator.ValidationResult, opts *Options) error {
+	results := make([]sarifResult, 0)
+
+	for _, msg := range result.SyntaxErrors {
+		results = append(results, newSARIFResult(RuleSyntaxError, msg, "", 0, 0))
+	}
+	for _, msg := range result.CompileErrors {
+		results = append(results, newSARIFResult(RuleCompileError, msg, "", 0, 0))
+	}
+	for _, msg := range result.RuntimeErrors {
+		results = append(results, newSARIFResult(RuleRuntimeError, msg, "", 0, 0))
+	}
+	for _, flaky := range result.FlakyTests {
+		message := fmt.Sprintf("Test %s in %s failed %d of %d runs", flaky.Name, flaky.Package, flaky.Failures, flaky.Runs)
+		results = append(results, newSARIFResult(RuleFlakyTest, message, "", 0, 0))
+	}
+	for _, finding := range result.Findings {
+		results = append(results, newSARIFResult(finding.Rule, finding.Message, finding.File, finding.Line, 0))
+	}
+
+	rules := []string{
+		RuleSyntaxError,
+		RuleCompileError,
+		RuleRuntimeError,
+		RuleFlakyTest,
+	}
+
+	return writeSARIF(results, append(rules, generator.QualityRules...), opts)
+}
+
+// generateTestLintSARIF outputs test smells as a SARIF 2.1 log
+func generateTestLintSARIF(report *generator.TestLintReport, opts *Options) error {
+	results := make([]sarifResult, 0)
+
+	for _, file := range report.Files {
+		if file.ParseError != "" {
+			results = append(results, newSARIFResult(RuleSyntaxError, file.ParseError, file.Path, 0, 0))
+		}
+		for _, finding := range file.Findings {
+			results = append(results, newSARIFResult(finding.Rule, finding.Message, finding.File, finding.Line, 0))
+		}
+	}
+
+	return writeSARIF(results, append([]string{RuleSyntaxError}, generator.QualityRules...), opts)
+}
+
+// newSARIFResult creates a result for a rule, located in a file when one is given
+func newSARIFResult(rule, message, file string, startLine, endLine int) sarifResult {
+	result := sarifResult{
+		RuleID:  rule,
+		Level:   sarifRuleDescriptions[rule].level,
+		Message: sarifMessage{Text: message},
+	}
+
+	if file != "" {
+		location := sarifLocation{
+			PhysicalLocation: sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{
+					URI:       filepath.ToSlash(file),
+					URIBaseID: sarifSrcRoot,
+				},
+			},
+		}
+		if startLine > 0 {
+			location.PhysicalLocation.Region = &sarifRegion{StartLine: startLine, EndLine: endLine}
+		}
+		result.Locations = []sarifLocation{location}
+	}
+
+	return result
+}
+
+// writeSARIF wraps results in a single-run SARIF log and writes it out
+func writeSARIF(results []sarifResult, ruleIDs []string, opts *Options) error {
+	rules := make([]sarifRule, 0, len(ruleIDs))
+	for _, id := range ruleIDs {
+		rules = append(rules, sarifRule{
+			ID:               id,
+			ShortDescription: sarifMessage{Text: sarifRuleDescriptions[id].description},
+			DefaultConfig:    sarifDefaultLevel{Level: sarifRuleDescriptions[id].level},
+		})
+	}
+
+	log := sarifLog{
+		Version: sarifVersion,
+		Schema:  sarifSchema,
+		Runs: []sarifRun{
+			{
+				Tool: sarifTool{
+					Driver: sarifDriver{
+						Name:  "gcov",
+						Rules: rules,
+					},
+				},
+				Results: results,
+			},
+		},
+	}
+
+	data, err := json.MarshalIndent(log, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal SARIF: %w", err)
+	}
+
+	return writeOutput(string(data)+"\n", opts.OutputFile)
+}
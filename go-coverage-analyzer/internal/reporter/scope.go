@@ -0,0 +1,53 @@
+package reporter
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/beck/go-coverage-analyzer/pkg/models"
+)
+
+// printScopedFunctions prints the coverage of every function of a scoped analysis, in source order,
+// which is what a check of the file being edited wants to see
+func printScopedFunctions(result *models.AnalysisResult, opts *Options) {
+	if result.Metadata.Scope == "" {
+		return
+	}
+
+	var functions []*models.Function
+	for _, pkg := range result.PackageCoverage {
+		for _, file := range pkg.Files {
+			functions = append(functions, file.Functions...)
+		}
+	}
+	if len(functions) == 0 {
+		return
+	}
+	sort.Slice(functions, func(i, j int) bool {
+		if functions[i].File != functions[j].File {
+			return functions[i].File < functions[j].File
+		}
+		return functions[i].StartLine < functions[j].StartLine
+	})
+
+	fmt.Printf("%s%sFUNCTIONS IN %s%s\n", ColorBold, ColorWhite, result.Metadata.Scope, ColorReset)
+	fmt.Println(strings.Repeat("-", 90))
+	fmt.Printf("%-40s %-30s %-10s %-10s\n", "Function", "Location", "Coverage", "Complexity")
+	fmt.Println(strings.Repeat("-", 90))
+
+	for _, function := range functions {
+		name := function.Name
+		if function.ReceiverType != "" {
+			name = strings.TrimPrefix(function.ReceiverType, "*") + "." + name
+		}
+		fmt.Printf("%-40s %-30s %s%8s%s   %s%7d%s\n",
+			truncate(name, 40),
+			truncate(fmt.Sprintf("%s:%d", function.File, function.StartLine), 30),
+			getCoverageColor(function.Coverage, opts.Threshold), opts.Numbers.Percent(function.Coverage), ColorReset,
+			getComplexityColor(function.Complexity, result.HighComplexityThreshold()), function.Complexity, ColorReset,
+		)
+	}
+
+	fmt.Println()
+}
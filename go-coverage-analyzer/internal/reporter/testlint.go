@@ -0,0 +1,82 @@
+package reporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/beck/go-coverage-analyzer/internal/generator"
+	"github.com/beck/go-coverage-analyzer/internal/i18n"
+)
+
+// GenerateTestLintReport outputs the test smells of a project in the requested format
+func GenerateTestLintReport(report *generator.TestLintReport, opts *Options) error {
+	switch strings.ToLower(opts.Format) {
+	case "json":
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		return writeOutput(string(data)+"\n", opts.OutputFile)
+	case "sarif":
+		return generateTestLintSARIF(report, opts)
+	case "console", "":
+		printTestLintReport(report)
+		return nil
+	default:
+		return fmt.Errorf("unsupported output format for test lint report: %s", opts.Format)
+	}
+}
+
+// printTestLintReport prints the totals and the smells of every test file that has any
+func printTestLintReport(report *generator.TestLintReport) {
+	fmt.Printf("%s%sTEST SMELL REPORT%s\n", ColorBold, ColorCyan, ColorReset)
+	fmt.Println("=" + strings.Repeat("=", 70) + "=")
+	fmt.Printf("Project: %s%s%s\n\n", ColorBold, report.ProjectPath, ColorReset)
+
+	assertionsPerTest := 0.0
+	if report.Tests > 0 {
+		assertionsPerTest = float64(report.Assertions) / float64(report.Tests)
+	}
+	fmt.Printf("Test Files:          %s%d%s\n", ColorCyan, len(report.Files), ColorReset)
+	fmt.Printf("Tests:               %s%d%s\n", ColorCyan, report.Tests, ColorReset)
+	fmt.Printf("Assertions per Test: %s%.1f%s\n", ColorCyan, assertionsPerTest, ColorReset)
+	fmt.Printf("Errors:              %s%s%d%s\n", ColorRed, ColorBold, report.Errors, ColorReset)
+	fmt.Printf("Warnings:            %s%d%s\n", ColorYellow, report.Warnings, ColorReset)
+	fmt.Printf("Info:                %s%d%s\n\n", ColorBlue, report.Infos, ColorReset)
+
+	clean := true
+	for _, file := range report.Files {
+		if len(file.Findings) == 0 && file.ParseError == "" {
+			continue
+		}
+		clean = false
+
+		fmt.Printf("%s%s%s (%d tests, %.1f assertions per test)\n",
+			ColorBold, file.Path, ColorReset, file.Tests, file.AssertionsPerTest)
+		if file.ParseError != "" {
+			fmt.Printf("   %s[error]%s   %s\n", ColorRed, ColorReset, file.ParseError)
+		}
+		for _, finding := range file.Findings {
+			fmt.Printf("   %s%-9s%s line %-5d %-22s %s\n",
+				severityColor(finding.Severity), "["+finding.Severity+"]", ColorReset, finding.Line, finding.Rule, finding.Message)
+		}
+		fmt.Println()
+	}
+
+	if clean {
+		i18n.Printf("%s✅ No test smells found%s\n", ColorGreen, ColorReset)
+	}
+}
+
+// severityColor returns the console color of a finding severity
+func severityColor(severity string) string {
+	switch severity {
+	case generator.SeverityError:
+		return ColorRed
+	case generator.SeverityWarning:
+		return ColorYellow
+	default:
+		return ColorBlue
+	}
+}
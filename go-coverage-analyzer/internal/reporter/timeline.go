@@ -0,0 +1,149 @@
+package reporter
+
+import (
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+
+	"github.com/beck/go-coverage-analyzer/internal/i18n"
+	"github.com/beck/go-coverage-analyzer/pkg/models"
+)
+
+// Timeline chart layout settings
+const (
+	timelineWidth        = 900.0
+	timelineHeight       = 380.0
+	timelineMarginLeft   = 50.0
+	timelineMarginRight  = 170.0
+	timelineMarginTop    = 20.0
+	timelineMarginBottom = 50.0
+	timelineMaxPackages  = 8
+	timelineMaxLabels    = 10
+)
+
+// timelinePalette colors the per-package series
+var timelinePalette = []string{
+	"#007bff", "#28a745", "#fd7e14", "#6f42c1", "#17a2b8", "#e83e8c", "#20c997", "#6c757d",
+}
+
+// GenerateTimelineChart writes a standalone SVG chart of coverage history
+func GenerateTimelineChart(snapshots []*models.CoverageSnapshot, opts *Options) error {
+	if len(snapshots) < 2 {
+		return fmt.Errorf("at least 2 snapshots are needed to chart coverage history, found %d", len(snapshots))
+	}
+
+	svg := renderTimelineSVG(snapshots, opts.Numbers)
+	if err := writeOutput(`<?xml version="1.0" encoding="UTF-8"?>`+"\n"+svg+"\n", opts.OutputFile); err != nil {
+		return fmt.Errorf("failed to write chart: %w", err)
+	}
+
+	if opts.Verbose && opts.OutputFile != "" {
+		i18n.Printf("📈 Coverage history chart generated: %s\n", opts.OutputFile)
+	}
+
+	return nil
+}
+
+// renderTimelineSVG draws overall and per-package coverage over the given snapshots as an SVG line chart
+func renderTimelineSVG(snapshots []*models.CoverageSnapshot, numbers *NumberFormat) string {
+	plotWidth := timelineWidth - timelineMarginLeft - timelineMarginRight
+	plotHeight := timelineHeight - timelineMarginTop - timelineMarginBottom
+
+	xFor := func(i int) float64 {
+		if len(snapshots) == 1 {
+			return timelineMarginLeft + plotWidth/2
+		}
+		return timelineMarginLeft + plotWidth*float64(i)/float64(len(snapshots)-1)
+	}
+	yFor := func(coverage float64) float64 {
+		return timelineMarginTop + plotHeight*(1-coverage/100)
+	}
+
+	var svg strings.Builder
+	svg.WriteString(fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %.0f %.0f" font-family="sans-serif" font-size="11">`,
+		timelineWidth, timelineHeight))
+	svg.WriteString(fmt.Sprintf(`<rect width="%.0f" height="%.0f" fill="#ffffff"/>`, timelineWidth, timelineHeight))
+
+	// Horizontal grid lines with percentage labels
+	for pct := 0.0; pct <= 100; pct += 25 {
+		y := yFor(pct)
+		svg.WriteString(fmt.Sprintf(`<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="#e9ecef"/>`,
+			timelineMarginLeft, y, timelineMarginLeft+plotWidth, y))
+		svg.WriteString(fmt.Sprintf(`<text x="%.1f" y="%.1f" text-anchor="end" fill="#666">%.0f%%</text>`,
+			timelineMarginLeft-6, y+4, pct))
+	}
+
+	// X axis labels, thinned out so they do not overlap
+	step := (len(snapshots) + timelineMaxLabels - 1) / timelineMaxLabels
+	for i, snapshot := range snapshots {
+		if i%step != 0 && i != len(snapshots)-1 {
+			continue
+		}
+		svg.WriteString(fmt.Sprintf(`<text x="%.1f" y="%.1f" text-anchor="middle" fill="#666">%s</text>`,
+			xFor(i), timelineMarginTop+plotHeight+18, html.EscapeString(snapshotLabel(snapshot))))
+	}
+
+	// Per-package series first so the overall line is drawn on top
+	for i, name := range timelinePackages(snapshots) {
+		color := timelinePalette[i%len(timelinePalette)]
+		points := make([]string, 0, len(snapshots))
+		for j, snapshot := range snapshots {
+			if coverage, ok := snapshot.Packages[name]; ok {
+				points = append(points, fmt.Sprintf("%.1f,%.1f", xFor(j), yFor(coverage)))
+			}
+		}
+
+		svg.WriteString(fmt.Sprintf(`<polyline points="%s" fill="none" stroke="%s" stroke-width="1.5" stroke-dasharray="4 2"/>`,
+			strings.Join(points, " "), color))
+		writeLegendEntry(&svg, i+1, color, name, plotWidth)
+	}
+
+	overall := make([]string, 0, len(snapshots))
+	for i, snapshot := range snapshots {
+		overall = append(overall, fmt.Sprintf("%.1f,%.1f", xFor(i), yFor(snapshot.OverallCoverage)))
+	}
+	svg.WriteString(fmt.Sprintf(`<polyline points="%s" fill="none" stroke="#333333" stroke-width="2.5"/>`, strings.Join(overall, " ")))
+	for i, snapshot := range snapshots {
+		svg.WriteString(fmt.Sprintf(`<circle cx="%.1f" cy="%.1f" r="3" fill="#333333"><title>%s: %s</title></circle>`,
+			xFor(i), yFor(snapshot.OverallCoverage), html.EscapeString(snapshotLabel(snapshot)), numbers.Percent(snapshot.OverallCoverage)))
+	}
+	writeLegendEntry(&svg, 0, "#333333", "overall", plotWidth)
+
+	svg.WriteString(`</svg>`)
+	return svg.String()
+}
+
+// writeLegendEntry draws a legend line to the right of the plot area
+func writeLegendEntry(svg *strings.Builder, row int, color, label string, plotWidth float64) {
+	x := timelineMarginLeft + plotWidth + 15
+	y := timelineMarginTop + 10 + float64(row)*18
+	svg.WriteString(fmt.Sprintf(`<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="%s" stroke-width="2.5"/>`,
+		x, y, x+20, y, color))
+	svg.WriteString(fmt.Sprintf(`<text x="%.1f" y="%.1f" fill="#333">%s</text>`,
+		x+26, y+4, html.EscapeString(truncate(label, 20))))
+}
+
+// timelinePackages returns the packages of the latest snapshot to chart, limited to keep the chart readable
+func timelinePackages(snapshots []*models.CoverageSnapshot) []string {
+	latest := snapshots[len(snapshots)-1]
+
+	names := make([]string, 0, len(latest.Packages))
+	for name := range latest.Packages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	if len(names) > timelineMaxPackages {
+		names = names[:timelineMaxPackages]
+	}
+	return names
+}
+
+// snapshotLabel labels a snapshot by git tag when available, otherwise by date
+func snapshotLabel(snapshot *models.CoverageSnapshot) string {
+	if snapshot.Tag != "" {
+		return snapshot.Tag
+	}
+	return snapshot.Timestamp.Format("2006-01-02")
+}
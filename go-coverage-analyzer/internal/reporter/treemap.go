@@ -0,0 +1,238 @@
+package reporter
+
+import (
+	"fmt"
+	"math"
+	"path/filepath"
+	"sort"
+
+	"github.com/beck/go-coverage-analyzer/pkg/models"
+)
+
+// Treemap layout settings for the HTML heat map
+const (
+	treemapWidth         = 1000.0
+	treemapHeight        = 500.0
+	treemapPadding       = 2.0
+	treemapHeaderHeight  = 16.0
+	treemapCharWidth     = 7.0
+	treemapMinLabelWidth = 40.0
+)
+
+// Treemap is a pre-computed SVG layout of packages and files
+type Treemap struct {
+	Width  float64
+	Height float64
+	Rects  []*TreemapRect
+}
+
+// TreemapRect is a single package or file rectangle in the treemap
+type TreemapRect struct {
+	X, Y, W, H     float64
+	Label          string
+	LabelX, LabelY float64
+	Title          string
+	Color          string
+	IsPackage      bool
+}
+
+// treemapBox is an unlabelled rectangle produced by the layout algorithm
+type treemapBox struct {
+	x, y, w, h float64
+}
+
+// buildTreemap lays out packages sized by lines of code with their files nested inside
+func buildTreemap(result *models.AnalysisResult, numbers *NumberFormat) *Treemap {
+	treemap := &Treemap{
+		Width:  treemapWidth,
+		Height: treemapHeight,
+		Rects:  make([]*TreemapRect, 0),
+	}
+
+	type packageEntry struct {
+		pkg   *models.Package
+		files []*models.File
+		lines int
+	}
+
+	entries := make([]*packageEntry, 0, len(result.PackageCoverage))
+	for _, pkg := range result.PackageCoverage {
+		entry := &packageEntry{pkg: pkg}
+		for _, file := range pkg.Files {
+			if file.TotalLines > 0 {
+				entry.files = append(entry.files, file)
+				entry.lines += file.TotalLines
+			}
+		}
+		if entry.lines == 0 {
+			continue
+		}
+
+		sort.Slice(entry.files, func(i, j int) bool {
+			if entry.files[i].TotalLines != entry.files[j].TotalLines {
+				return entry.files[i].TotalLines > entry.files[j].TotalLines
+			}
+			return entry.files[i].Path < entry.files[j].Path
+		})
+		entries = append(entries, entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].lines != entries[j].lines {
+			return entries[i].lines > entries[j].lines
+		}
+		return entries[i].pkg.Name < entries[j].pkg.Name
+	})
+
+	if len(entries) == 0 {
+		return treemap
+	}
+
+	packageSizes := make([]float64, len(entries))
+	for i, entry := range entries {
+		packageSizes[i] = float64(entry.lines)
+	}
+
+	for i, box := range squarify(packageSizes, 0, 0, treemapWidth, treemapHeight) {
+		entry := entries[i]
+		treemap.Rects = append(treemap.Rects, &TreemapRect{
+			X: box.x, Y: box.y, W: box.w, H: box.h,
+			Label:     treemapLabel(entry.pkg.Name, box.w),
+			LabelX:    box.x + 4,
+			LabelY:    box.y + 12,
+			Title:     fmt.Sprintf("%s: %s coverage, %d lines", entry.pkg.Name, numbers.Percent(entry.pkg.Coverage), entry.lines),
+			Color:     heatColor(entry.pkg.Coverage),
+			IsPackage: true,
+		})
+
+		// Nest files below the package header when there is room for them
+		inner := treemapBox{
+			x: box.x + treemapPadding,
+			y: box.y + treemapHeaderHeight,
+			w: box.w - 2*treemapPadding,
+			h: box.h - treemapHeaderHeight - treemapPadding,
+		}
+		if inner.w <= treemapPadding || inner.h <= treemapPadding {
+			continue
+		}
+
+		fileSizes := make([]float64, len(entry.files))
+		for j, file := range entry.files {
+			fileSizes[j] = float64(file.TotalLines)
+		}
+
+		for j, fileBox := range squarify(fileSizes, inner.x, inner.y, inner.w, inner.h) {
+			file := entry.files[j]
+			label := ""
+			if fileBox.h > treemapHeaderHeight {
+				label = treemapLabel(filepath.Base(file.Path), fileBox.w)
+			}
+
+			treemap.Rects = append(treemap.Rects, &TreemapRect{
+				X: fileBox.x, Y: fileBox.y, W: fileBox.w, H: fileBox.h,
+				Label:  label,
+				LabelX: fileBox.x + 3,
+				LabelY: fileBox.y + 12,
+				Title:  fmt.Sprintf("%s: %s coverage, %d lines", file.Path, numbers.Percent(file.Coverage), file.TotalLines),
+				Color:  heatColor(file.Coverage),
+			})
+		}
+	}
+
+	return treemap
+}
+
+// squarify lays out sizes (sorted descending) inside a rectangle using the squarified treemap algorithm
+func squarify(sizes []float64, x, y, w, h float64) []treemapBox {
+	boxes := make([]treemapBox, 0, len(sizes))
+
+	total := 0.0
+	for _, size := range sizes {
+		total += size
+	}
+	if total <= 0 || w <= 0 || h <= 0 {
+		return boxes
+	}
+
+	// Scale sizes to areas that exactly fill the rectangle
+	areas := make([]float64, len(sizes))
+	for i, size := range sizes {
+		areas[i] = size / total * w * h
+	}
+
+	for start := 0; start < len(areas); {
+		short := math.Min(w, h)
+
+		end := start + 1
+		for end < len(areas) && worstAspectRatio(areas[start:end+1], short) <= worstAspectRatio(areas[start:end], short) {
+			end++
+		}
+
+		row := areas[start:end]
+		rowArea := 0.0
+		for _, area := range row {
+			rowArea += area
+		}
+
+		if w >= h {
+			// Lay the row out as a column along the left edge
+			columnWidth := rowArea / h
+			cy := y
+			for _, area := range row {
+				boxHeight := area / columnWidth
+				boxes = append(boxes, treemapBox{x: x, y: cy, w: columnWidth, h: boxHeight})
+				cy += boxHeight
+			}
+			x += columnWidth
+			w -= columnWidth
+		} else {
+			// Lay the row out along the top edge
+			rowHeight := rowArea / w
+			cx := x
+			for _, area := range row {
+				boxWidth := area / rowHeight
+				boxes = append(boxes, treemapBox{x: cx, y: y, w: boxWidth, h: rowHeight})
+				cx += boxWidth
+			}
+			y += rowHeight
+			h -= rowHeight
+		}
+
+		start = end
+	}
+
+	return boxes
+}
+
+// worstAspectRatio returns the most elongated aspect ratio of a row laid along a side of the given length
+func worstAspectRatio(row []float64, side float64) float64 {
+	sum, largest, smallest := 0.0, 0.0, math.MaxFloat64
+	for _, area := range row {
+		sum += area
+		largest = math.Max(largest, area)
+		smallest = math.Min(smallest, area)
+	}
+
+	sideSquared := side * side
+	sumSquared := sum * sum
+	return math.Max(sideSquared*largest/sumSquared, sumSquared/(sideSquared*smallest))
+}
+
+// heatColor maps coverage to a red-to-green color
+func heatColor(coverage float64) string {
+	coverage = math.Max(0, math.Min(100, coverage))
+	return fmt.Sprintf("hsl(%.0f, 65%%, 50%%)", coverage*1.2)
+}
+
+// treemapLabel truncates a label to fit the rectangle width, or hides it when too narrow
+func treemapLabel(label string, width float64) string {
+	if width < treemapMinLabelWidth {
+		return ""
+	}
+
+	maxChars := int(width / treemapCharWidth)
+	if len(label) > maxChars {
+		return truncate(label, maxChars)
+	}
+	return label
+}
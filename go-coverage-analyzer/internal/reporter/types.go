@@ -0,0 +1,46 @@
+package reporter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/beck/go-coverage-analyzer/internal/i18n"
+	"github.com/beck/go-coverage-analyzer/pkg/models"
+)
+
+// printTypeDetails prints the coverage of the methods of each receiver type, then the types
+// implementing exported interfaces with uncovered implementing methods
+func printTypeDetails(result *models.AnalysisResult, opts *Options) {
+	if len(result.TypeCoverage) == 0 {
+		return
+	}
+
+	fmt.Printf("%s%sCOVERAGE BY TYPE%s\n", ColorBold, ColorWhite, ColorReset)
+	fmt.Println(strings.Repeat("-", 90))
+	fmt.Printf("%-32s %-10s %-10s %-34s\n", "Type", "Coverage", "Methods", "Implements")
+	fmt.Println(strings.Repeat("-", 90))
+
+	for _, typeCoverage := range result.TypeCoverage {
+		if typeCoverage.TotalMethods == 0 {
+			continue
+		}
+		fmt.Printf("%-32s %s%8s%s  %4d/%-5d %-34s\n",
+			truncate(typeCoverage.Package+"."+typeCoverage.Type, 32),
+			getCoverageColor(typeCoverage.Coverage, opts.Threshold), opts.Numbers.Percent(typeCoverage.Coverage), ColorReset,
+			typeCoverage.CoveredMethods, typeCoverage.TotalMethods,
+			truncate(strings.Join(typeCoverage.Implements, ", "), 34),
+		)
+	}
+
+	for _, typeCoverage := range result.TypeCoverage {
+		if len(typeCoverage.UntestedInterfaces) == 0 {
+			continue
+		}
+		i18n.Printf("%s⚠️  %s.%s implements %s untested; uncovered methods: %s%s\n",
+			ColorYellow, typeCoverage.Package, typeCoverage.Type,
+			strings.Join(typeCoverage.UntestedInterfaces, ", "),
+			strings.Join(typeCoverage.UncoveredMethods, ", "), ColorReset)
+	}
+
+	fmt.Println()
+}
@@ -0,0 +1,191 @@
+package reporter
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/beck/go-coverage-analyzer/pkg/models"
+)
+
+// lineRange is a span of source lines, both ends included
+type lineRange struct {
+	start int
+	end   int
+}
+
+// String renders the range as a line number or start-end
+func (r lineRange) String() string {
+	if r.start == r.end {
+		return fmt.Sprintf("%d", r.start)
+	}
+	return fmt.Sprintf("%d-%d", r.start, r.end)
+}
+
+// Report detail levels
+const (
+	DetailFunctions = "functions" // coverage per function
+	DetailBlocks    = "blocks"    // also the uncovered line ranges of every function
+)
+
+// uncoveredRanges returns the merged line ranges of the blocks within a function that never ran, or
+// nothing when none of its blocks ran either, as fully uncovered functions are listed on their own
+func uncoveredRanges(file *models.File, function *models.Function) []lineRange {
+	ranges, executed := blockRanges(file, function)
+	if !executed {
+		return nil
+	}
+	return ranges
+}
+
+// blockRanges returns the merged line ranges of the blocks within a function that never ran, and
+// whether any of its blocks ran
+func blockRanges(file *models.File, function *models.Function) ([]lineRange, bool) {
+	var ranges []lineRange
+	executed := false
+	for _, block := range file.CoverageBlocks {
+		if block.StartLine < function.StartLine || block.EndLine > function.EndLine {
+			continue
+		}
+		if block.Count > 0 {
+			executed = true
+			continue
+		}
+		ranges = append(ranges, lineRange{start: block.StartLine, end: block.EndLine})
+	}
+	if len(ranges) == 0 {
+		return nil, executed
+	}
+
+	sort.Slice(ranges, func(i, j int) bool {
+		return ranges[i].start < ranges[j].start
+	})
+
+	merged := ranges[:1]
+	for _, r := range ranges[1:] {
+		last := &merged[len(merged)-1]
+		if r.start <= last.end+1 {
+			last.end = max(last.end, r.end)
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged, executed
+}
+
+// applyBlockDetail records on every function the line ranges of its blocks that never ran, so
+// JSON and HTML reports show the exact gaps
+func applyBlockDetail(result *models.AnalysisResult) {
+	for _, pkg := range result.PackageCoverage {
+		for _, file := range pkg.Files {
+			for _, function := range file.Functions {
+				applyFunctionBlockDetail(file, function)
+			}
+		}
+	}
+}
+
+// applyFunctionBlockDetail records the line ranges never run on a function and its closures
+func applyFunctionBlockDetail(file *models.File, function *models.Function) {
+	ranges, _ := blockRanges(file, function)
+	function.UncoveredBlocks = nil
+	for _, r := range ranges {
+		function.UncoveredBlocks = append(function.UncoveredBlocks, &models.LineRange{StartLine: r.start, EndLine: r.end})
+	}
+	for _, closure := range function.Closures {
+		applyFunctionBlockDetail(file, closure)
+	}
+}
+
+// getPartialFunctions returns the functions that ran but have uncovered blocks, by file and line;
+// only block detail records the blocks
+func getPartialFunctions(result *models.AnalysisResult) []*models.Function {
+	functions := make([]*models.Function, 0)
+	for _, pkg := range result.PackageCoverage {
+		for _, file := range pkg.Files {
+			for _, function := range file.Functions {
+				if function.IsCovered && len(function.UncoveredBlocks) > 0 {
+					functions = append(functions, function)
+				}
+			}
+		}
+	}
+
+	sort.Slice(functions, func(i, j int) bool {
+		if functions[i].File != functions[j].File {
+			return functions[i].File < functions[j].File
+		}
+		return functions[i].StartLine < functions[j].StartLine
+	})
+	return functions
+}
+
+// printUncoveredLines prints the line ranges partially covered functions never ran, grouped by
+// function, with opts.UncoveredContext lines of source around each range when set
+func printUncoveredLines(result *models.AnalysisResult, opts *Options) {
+	fmt.Printf("%s%sUNCOVERED LINES%s\n", ColorBold, ColorWhite, ColorReset)
+	fmt.Println(strings.Repeat("-", 90))
+
+	found := false
+	for _, pkg := range getPackagesSortedByName(result) {
+		files := make([]*models.File, 0, len(pkg.Files))
+		for _, file := range pkg.Files {
+			files = append(files, file)
+		}
+		sort.Slice(files, func(i, j int) bool {
+			return files[i].Path < files[j].Path
+		})
+
+		for _, file := range files {
+			var source []string
+			for _, function := range file.Functions {
+				ranges := uncoveredRanges(file, function)
+				if len(ranges) == 0 {
+					continue
+				}
+				found = true
+
+				if source == nil && opts.UncoveredContext > 0 {
+					source = readSourceLines(filepath.Join(result.ProjectPath, file.Path))
+				}
+
+				fmt.Printf("%s%s%s %s(%s)%s\n", ColorBold, function.Key(), ColorReset, ColorYellow, opts.Numbers.Percent(function.Coverage), ColorReset)
+				for _, r := range ranges {
+					fmt.Printf("   %s:%s\n", file.Path, r)
+					if len(source) > 0 {
+						printSourceContext(source, r, opts.UncoveredContext)
+					}
+				}
+			}
+		}
+	}
+
+	if !found {
+		fmt.Printf("%sNo partially covered functions%s\n", ColorGreen, ColorReset)
+	}
+	fmt.Println()
+}
+
+// readSourceLines returns the lines of a source file, or nothing when it cannot be read
+func readSourceLines(path string) []string {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	return strings.Split(string(content), "\n")
+}
+
+// printSourceContext prints the lines of a range, marked, and up to context lines on either side
+func printSourceContext(source []string, r lineRange, context int) {
+	first := max(1, r.start-context)
+	last := min(len(source), r.end+context)
+	for line := first; line <= last; line++ {
+		text := fmt.Sprintf("  %5d | %s", line, strings.ReplaceAll(source[line-1], "\t", "    "))
+		if line >= r.start && line <= r.end {
+			text = ColorRed + ">" + text[1:] + ColorReset
+		}
+		fmt.Printf("   %s\n", text)
+	}
+}
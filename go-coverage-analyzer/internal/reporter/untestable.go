@@ -0,0 +1,68 @@
+package reporter
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/beck/go-coverage-analyzer/pkg/models"
+)
+
+// printUntestableFunctions prints the functions left out of the function totals and why, so totals
+// smaller than the code base are not a mystery
+func printUntestableFunctions(result *models.AnalysisResult) {
+	if len(result.UntestableFunctions) == 0 {
+		return
+	}
+
+	fmt.Printf("%s%sUNTESTABLE FUNCTIONS (left out of totals)%s\n", ColorBold, ColorWhite, ColorReset)
+	fmt.Println(strings.Repeat("-", 90))
+
+	fmt.Printf("By reason: %s\n", untestableReasons(result.UntestableFunctions))
+	fmt.Println()
+
+	fmt.Printf("%-35s %-20s %-25s %-15s\n", "Function", "Package", "File", "Reason")
+	fmt.Println(strings.Repeat("-", 90))
+
+	for _, function := range result.UntestableFunctions[:min(20, len(result.UntestableFunctions))] {
+		fmt.Printf("%-35s %-20s %-25s %-15s\n",
+			truncate(function.Key(), 35),
+			truncate(function.Package, 20),
+			truncate(fmt.Sprintf("%s:%d", function.File, function.StartLine), 25),
+			function.UntestableReason,
+		)
+	}
+
+	if len(result.UntestableFunctions) > 20 {
+		fmt.Printf("\n%s... and %d more untestable functions%s\n",
+			ColorYellow, len(result.UntestableFunctions)-20, ColorReset)
+	}
+
+	fmt.Println()
+}
+
+// untestableReasons counts functions by untestable reason, most common first, e.g.
+// "12 unexported, 2 main, 1 init"
+func untestableReasons(functions []*models.Function) string {
+	counts := make(map[string]int)
+	for _, function := range functions {
+		counts[function.UntestableReason]++
+	}
+
+	reasons := make([]string, 0, len(counts))
+	for reason := range counts {
+		reasons = append(reasons, reason)
+	}
+	sort.Slice(reasons, func(i, j int) bool {
+		if counts[reasons[i]] != counts[reasons[j]] {
+			return counts[reasons[i]] > counts[reasons[j]]
+		}
+		return reasons[i] < reasons[j]
+	})
+
+	parts := make([]string, len(reasons))
+	for i, reason := range reasons {
+		parts[i] = fmt.Sprintf("%d %s", counts[reason], reason)
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,27 @@
+package reporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/beck/go-coverage-analyzer/internal/generator"
+)
+
+// GenerateValidationReport outputs a test validation result in a machine-readable format
+func GenerateValidationReport(result *generator.ValidationResult, opts *Options) error {
+	switch strings.ToLower(opts.Format) {
+	case "json":
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		return writeOutput(string(data)+"\n", opts.OutputFile)
+	case "sarif":
+		return generateValidationSARIF(result, opts)
+	case "junit":
+		return generateValidationJUnit(result, opts)
+	default:
+		return fmt.Errorf("unsupported output format for validation report: %s", opts.Format)
+	}
+}
@@ -0,0 +1,73 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// httpBackend stores objects below a base URL: GET and PUT {base}/{name} read and write an object, and
+// GET {base}/ lists the objects as a JSON array of names
+type httpBackend struct {
+	base   string
+	token  string // bearer token, "" for none
+	client *http.Client
+}
+
+func (b *httpBackend) Read(name string) ([]byte, error) {
+	return b.do(http.MethodGet, b.base+"/"+url.PathEscape(name), nil)
+}
+
+func (b *httpBackend) Write(name string, data []byte) error {
+	_, err := b.do(http.MethodPut, b.base+"/"+url.PathEscape(name), data)
+	return err
+}
+
+func (b *httpBackend) List() ([]string, error) {
+	body, err := b.do(http.MethodGet, b.base+"/", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	if err := json.Unmarshal(body, &names); err != nil {
+		return nil, fmt.Errorf("failed to parse listing of %s, expected a JSON array of names: %w", b.base, err)
+	}
+	return names, nil
+}
+
+func (b *httpBackend) Location() string {
+	return b.base
+}
+
+// do sends a request and returns the response body of a successful one
+func (b *httpBackend) do(method, target string, data []byte) ([]byte, error) {
+	req, err := http.NewRequest(method, target, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if data != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if b.token != "" {
+		req.Header.Set("Authorization", "Bearer "+b.token)
+	}
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response of %s: %w", target, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, statusError(method, target, resp, body)
+	}
+	return body, nil
+}
@@ -0,0 +1,44 @@
+package storage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/beck/go-coverage-analyzer/internal/fileutil"
+)
+
+// localBackend stores objects as files in a directory
+type localBackend struct {
+	dir string
+}
+
+func (b *localBackend) Read(name string) ([]byte, error) {
+	return os.ReadFile(filepath.Join(b.dir, name))
+}
+
+func (b *localBackend) Write(name string, data []byte) error {
+	if err := os.MkdirAll(b.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", b.dir, err)
+	}
+	return fileutil.WriteAtomic(filepath.Join(b.dir, name), data, 0644)
+}
+
+func (b *localBackend) List() ([]string, error) {
+	entries, err := os.ReadDir(b.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			names = append(names, entry.Name())
+		}
+	}
+	return names, nil
+}
+
+func (b *localBackend) Location() string {
+	return b.dir
+}
@@ -0,0 +1,275 @@
+package storage
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// objectBackend stores objects in an S3 or Google Cloud Storage bucket below a key prefix, through the
+// S3-compatible XML API both offer
+type objectBackend struct {
+	location string
+	base     string // bucket URL, without trailing slash
+	prefix   string // key prefix ending in a slash, or ""
+	s3       bool   // list with ListObjectsV2, else with markers as the GCS XML API does
+	client   *http.Client
+	sign     func(req *http.Request, payload []byte) error
+}
+
+// listBucketResult is the part of an S3 or GCS object listing gcov reads
+type listBucketResult struct {
+	Contents []struct {
+		Key string `xml:"Key"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+	NextMarker            string `xml:"NextMarker"`
+}
+
+// newS3Backend returns a backend for an S3 bucket, signing requests with the AWS credentials of the
+// environment: AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and, for temporary credentials, AWS_SESSION_TOKEN
+func newS3Backend(location, bucket, prefix string, client *http.Client, opts *Options) (Backend, error) {
+	base := "https://" + bucket + ".s3." + opts.S3Region + ".amazonaws.com"
+	if opts.S3Endpoint != "" {
+		base = strings.TrimSuffix(opts.S3Endpoint, "/") + "/" + bucket
+	}
+
+	region := opts.S3Region
+	return &objectBackend{
+		location: location,
+		base:     base,
+		prefix:   keyPrefix(prefix),
+		s3:       true,
+		client:   client,
+		sign: func(req *http.Request, payload []byte) error {
+			accessKey, secretKey := os.Getenv("AWS_ACCESS_KEY_ID"), os.Getenv("AWS_SECRET_ACCESS_KEY")
+			if accessKey == "" || secretKey == "" {
+				return fmt.Errorf("no S3 credentials: set AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY")
+			}
+			signV4(req, payload, accessKey, secretKey, os.Getenv("AWS_SESSION_TOKEN"), region, time.Now().UTC())
+			return nil
+		},
+	}, nil
+}
+
+// newGCSBackend returns a backend for a Google Cloud Storage bucket, authorized with the OAuth access
+// token in the configured environment variable, such as the output of gcloud auth print-access-token
+func newGCSBackend(location, bucket, prefix string, client *http.Client, opts *Options) (Backend, error) {
+	tokenEnv := opts.GCSTokenEnv
+	return &objectBackend{
+		location: location,
+		base:     "https://storage.googleapis.com/" + bucket,
+		prefix:   keyPrefix(prefix),
+		client:   client,
+		sign: func(req *http.Request, payload []byte) error {
+			token := os.Getenv(tokenEnv)
+			if token == "" {
+				return fmt.Errorf("no GCS credentials: set %s to an OAuth access token (gcloud auth print-access-token)", tokenEnv)
+			}
+			req.Header.Set("Authorization", "Bearer "+token)
+			return nil
+		},
+	}, nil
+}
+
+func (b *objectBackend) Read(name string) ([]byte, error) {
+	return b.do(http.MethodGet, b.objectURL(name), nil)
+}
+
+func (b *objectBackend) Write(name string, data []byte) error {
+	_, err := b.do(http.MethodPut, b.objectURL(name), data)
+	return err
+}
+
+func (b *objectBackend) List() ([]string, error) {
+	names := make([]string, 0)
+	next := ""
+	for {
+		query := url.Values{}
+		query.Set("prefix", b.prefix)
+		query.Set("delimiter", "/")
+		if b.s3 {
+			query.Set("list-type", "2")
+			if next != "" {
+				query.Set("continuation-token", next)
+			}
+		} else if next != "" {
+			query.Set("marker", next)
+		}
+
+		body, err := b.do(http.MethodGet, b.base+"/?"+strings.ReplaceAll(query.Encode(), "+", "%20"), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var listing listBucketResult
+		if err := xml.Unmarshal(body, &listing); err != nil {
+			return nil, fmt.Errorf("failed to parse listing of %s: %w", b.location, err)
+		}
+		for _, object := range listing.Contents {
+			if name := strings.TrimPrefix(object.Key, b.prefix); name != "" {
+				names = append(names, name)
+			}
+		}
+
+		next = listing.NextContinuationToken
+		if !b.s3 {
+			next = listing.NextMarker
+			if next == "" && len(listing.Contents) > 0 {
+				next = listing.Contents[len(listing.Contents)-1].Key
+			}
+		}
+		if !listing.IsTruncated || next == "" {
+			return names, nil
+		}
+	}
+}
+
+func (b *objectBackend) Location() string {
+	return b.location
+}
+
+// objectURL returns the URL of an object below the prefix
+func (b *objectBackend) objectURL(name string) string {
+	return b.base + "/" + uriEncode(b.prefix+name, false)
+}
+
+// do sends a signed request and returns the response body of a successful one
+func (b *objectBackend) do(method, target string, data []byte) ([]byte, error) {
+	req, err := http.NewRequest(method, target, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if data != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if err := b.sign(req, data); err != nil {
+		return nil, err
+	}
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response of %s: %w", target, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, statusError(method, target, resp, body)
+	}
+	return body, nil
+}
+
+// keyPrefix turns a path below a bucket into a key prefix ending in a slash
+func keyPrefix(prefix string) string {
+	if prefix == "" {
+		return ""
+	}
+	return prefix + "/"
+}
+
+// signV4 signs an S3 request with AWS Signature Version 4
+func signV4(req *http.Request, payload []byte, accessKey, secretKey, sessionToken, region string, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := sha256Hex(payload)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if sessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", sessionToken)
+	}
+
+	// Sign the host and every x-amz- header, by lowercase name
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		if lower := strings.ToLower(name); strings.HasPrefix(lower, "x-amz-") {
+			headers[lower] = strings.TrimSpace(strings.Join(values, ","))
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		uriEncode(req.URL.Path, false),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+
+	key := hmacSHA256([]byte("AWS4"+secretKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		accessKey, scope, signedHeaders, signature))
+}
+
+// canonicalQuery encodes query parameters sorted by name, as Signature Version 4 requires
+func canonicalQuery(query url.Values) string {
+	pairs := make([]string, 0, len(query))
+	for name, values := range query {
+		for _, value := range values {
+			pairs = append(pairs, uriEncode(name, true)+"="+uriEncode(value, true))
+		}
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, "&")
+}
+
+// uriEncode percent-encodes everything but unreserved characters, and slashes unless encodeSlash is set
+func uriEncode(s string, encodeSlash bool) string {
+	var encoded strings.Builder
+	for _, c := range []byte(s) {
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9', c == '-', c == '_', c == '.', c == '~':
+			encoded.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			encoded.WriteByte(c)
+		default:
+			fmt.Fprintf(&encoded, "%%%02X", c)
+		}
+	}
+	return encoded.String()
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
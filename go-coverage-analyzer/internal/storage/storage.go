@@ -0,0 +1,205 @@
+// Package storage reads and writes gcov's persisted data, the coverage history and saved analysis
+// results, in a local directory or, for CI where the workspace does not survive the build, in S3
+// (s3://bucket/prefix), Google Cloud Storage (gs://bucket/prefix) or an HTTP endpoint
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Default environment variables holding credentials
+const (
+	DefaultGCSTokenEnv  = "GOOGLE_OAUTH_ACCESS_TOKEN"
+	DefaultHTTPTokenEnv = "GCOV_STORAGE_TOKEN"
+)
+
+// DefaultTimeout bounds each request to a remote store
+const DefaultTimeout = time.Minute
+
+// ErrNotExist is returned when a stored object does not exist; it matches fs.ErrNotExist
+var ErrNotExist = fs.ErrNotExist
+
+// Backend is a flat collection of named objects at a location
+type Backend interface {
+	// Read returns the content of an object, or an error matching ErrNotExist when there is none
+	Read(name string) ([]byte, error)
+	// Write creates or replaces an object
+	Write(name string, data []byte) error
+	// List returns the names of the objects directly at the location
+	List() ([]string, error)
+	// Location returns the directory or URL the backend stores objects at
+	Location() string
+}
+
+// Options configures the remote backends. Credentials are never part of the configuration, only the
+// environment variables they are read from
+type Options struct {
+	S3Region     string // default AWS_REGION, AWS_DEFAULT_REGION or us-east-1
+	S3Endpoint   string // S3-compatible endpoint such as MinIO, addressed path-style; default AWS
+	GCSTokenEnv  string // environment variable holding an OAuth access token for gs://
+	HTTPTokenEnv string // environment variable holding a bearer token for http(s):// stores
+	Timeout      time.Duration
+}
+
+var (
+	optionsMu sync.RWMutex
+	options   = &Options{}
+)
+
+// Configure sets the options of the backends Open returns from now on
+func Configure(opts *Options) {
+	optionsMu.Lock()
+	defer optionsMu.Unlock()
+	options = opts
+}
+
+// currentOptions returns the configured options with defaults filled in
+func currentOptions() Options {
+	optionsMu.RLock()
+	opts := *options
+	optionsMu.RUnlock()
+
+	if opts.S3Region == "" {
+		opts.S3Region = firstEnv("AWS_REGION", "AWS_DEFAULT_REGION")
+	}
+	if opts.S3Region == "" {
+		opts.S3Region = "us-east-1"
+	}
+	if opts.GCSTokenEnv == "" {
+		opts.GCSTokenEnv = DefaultGCSTokenEnv
+	}
+	if opts.HTTPTokenEnv == "" {
+		opts.HTTPTokenEnv = DefaultHTTPTokenEnv
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	return opts
+}
+
+// IsRemote reports whether a location is a URL of a remote store rather than a local path
+func IsRemote(location string) bool {
+	for _, scheme := range []string{"s3://", "gs://", "http://", "https://"} {
+		if strings.HasPrefix(location, scheme) {
+			return true
+		}
+	}
+	return false
+}
+
+// Open returns the backend storing objects at a location: a directory, or an s3://, gs://, http:// or
+// https:// URL
+func Open(location string) (Backend, error) {
+	if !IsRemote(location) {
+		return &localBackend{dir: location}, nil
+	}
+
+	u, err := url.Parse(location)
+	if err != nil {
+		return nil, fmt.Errorf("invalid storage location %s: %w", location, err)
+	}
+
+	opts := currentOptions()
+	client := &http.Client{Timeout: opts.Timeout}
+	prefix := strings.Trim(u.Path, "/")
+
+	switch u.Scheme {
+	case "s3":
+		if u.Host == "" {
+			return nil, fmt.Errorf("invalid storage location %s: missing bucket", location)
+		}
+		return newS3Backend(location, u.Host, prefix, client, &opts)
+	case "gs":
+		if u.Host == "" {
+			return nil, fmt.Errorf("invalid storage location %s: missing bucket", location)
+		}
+		return newGCSBackend(location, u.Host, prefix, client, &opts)
+	default:
+		return &httpBackend{
+			base:   strings.TrimSuffix(location, "/"),
+			token:  os.Getenv(opts.HTTPTokenEnv),
+			client: client,
+		}, nil
+	}
+}
+
+// ReadFile reads the object or file at a path or URL
+func ReadFile(location string) ([]byte, error) {
+	if !IsRemote(location) {
+		return os.ReadFile(location)
+	}
+
+	backend, name, err := openParent(location)
+	if err != nil {
+		return nil, err
+	}
+	return backend.Read(name)
+}
+
+// WriteFile writes the object or file at a path or URL; local files are replaced atomically
+func WriteFile(location string, data []byte) error {
+	if !IsRemote(location) {
+		dir, name := filepath.Split(location)
+		if dir == "" {
+			dir = "."
+		}
+		return (&localBackend{dir: dir}).Write(name, data)
+	}
+
+	backend, name, err := openParent(location)
+	if err != nil {
+		return err
+	}
+	return backend.Write(name, data)
+}
+
+// openParent opens the backend of the location an object URL is in and returns the object's name
+func openParent(location string) (Backend, string, error) {
+	dir, name := path.Split(location)
+	if name == "" {
+		return nil, "", fmt.Errorf("invalid storage location %s: missing object name", location)
+	}
+	backend, err := Open(dir)
+	if err != nil {
+		return nil, "", err
+	}
+	return backend, name, nil
+}
+
+// statusError turns an unsuccessful HTTP response into an error, matching ErrNotExist for 404
+func statusError(operation, target string, resp *http.Response, body []byte) error {
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%s %s: %w", operation, target, ErrNotExist)
+	}
+
+	detail := strings.TrimSpace(string(body))
+	if len(detail) > 200 {
+		detail = detail[:200] + "..."
+	}
+	return fmt.Errorf("%s %s: %s %s", operation, target, resp.Status, detail)
+}
+
+// IsNotExist reports whether an error means the object does not exist
+func IsNotExist(err error) bool {
+	return errors.Is(err, ErrNotExist)
+}
+
+// firstEnv returns the value of the first environment variable that is set
+func firstEnv(names ...string) string {
+	for _, name := range names {
+		if value := os.Getenv(name); value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -1,50 +1,138 @@
 package coverage
 
 import (
+	"context"
 	"fmt"
 	"go/ast"
 	"go/parser"
 	"go/token"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 	"time"
 
+	"github.com/beck/go-coverage-analyzer/internal/command"
+	"github.com/beck/go-coverage-analyzer/internal/i18n"
 	"github.com/beck/go-coverage-analyzer/pkg/models"
 )
 
 // AnalysisEngine handles comprehensive coverage analysis
 type AnalysisEngine struct {
-	parser  *ProfileParser
-	verbose bool
-	fset    *token.FileSet
+	parser         *ProfileParser
+	verbose        bool
+	fset           *token.FileSet
+	classifier     *ClassificationRules          // assigns parsed files to production, test, helper, generated or vendored
+	modules        *ModuleMap                    // resolves profile file names to project files
+	progress       models.ProgressFunc           // told how far parsing and analysis are, nil when not shown
+	sqlFields      map[string]*sqlField          // package-qualified struct name to its database handle field
+	httpFields     map[string]map[string]bool    // package-qualified struct name to its *http.Client fields
+	httpCandidates map[*models.Function][]string // receiver fields a method sends requests through, checked against httpFields
+	funcFields     map[string][]*models.Param    // package-qualified struct name to its function-typed fields
+	interfaces     map[string]bool               // package-qualified names of interface types
+	interfaceDecls map[string]*interfaceDecl     // package-qualified names of exported interfaces to their method sets
+	clocks         map[string]bool               // package-qualified names of interfaces with a Now() time.Time method
+	namedFields    map[string][]*models.Param    // package-qualified struct name to its fields of package-local named types
+	constants      map[string]string             // constants of the file being parsed to their literal values
+	highComplexity int                           // complexity above which functions count as highly complex
+	lineLimit      int                           // lines of code above which functions count as oversized
+	paramLimit     int                           // parameters above which functions count as oversized
+	scope          *analysisScope                // file or directory the analysis is limited to, nil for the whole project
+	parseCache     *ParseCache                   // files parsed by earlier analyses, nil to parse every file
+	includeInit    bool                          // record package-level variable initializers for the initialization category
+	includeCgo     bool                          // count the functions of cgo files as testable
+	warnings       []string                      // problems that left the analysis incomplete, see warn
 }
 
 // NewAnalysisEngine creates a new coverage analysis engine
 func NewAnalysisEngine(verbose bool) *AnalysisEngine {
 	return &AnalysisEngine{
-		parser:  NewProfileParser(verbose),
-		verbose: verbose,
-		fset:    token.NewFileSet(),
+		parser:         NewProfileParser(verbose),
+		verbose:        verbose,
+		fset:           token.NewFileSet(),
+		sqlFields:      make(map[string]*sqlField),
+		httpFields:     make(map[string]map[string]bool),
+		httpCandidates: make(map[*models.Function][]string),
+		funcFields:     make(map[string][]*models.Param),
+		interfaces:     make(map[string]bool),
+		interfaceDecls: make(map[string]*interfaceDecl),
+		clocks:         make(map[string]bool),
+		namedFields:    make(map[string][]*models.Param),
 	}
 }
 
-// AnalyzeProject performs comprehensive coverage analysis on a Go project
-func (e *AnalysisEngine) AnalyzeProject(opts *AnalysisOptions) (*models.AnalysisResult, error) {
+// AnalyzeProject performs comprehensive coverage analysis on a Go project, stopping with ctx.Err() and
+// killing a running go test when ctx is cancelled
+func (e *AnalysisEngine) AnalyzeProject(ctx context.Context, opts *AnalysisOptions) (*models.AnalysisResult, error) {
 	startTime := time.Now()
 
 	if e.verbose {
-		fmt.Printf("🔍 Starting comprehensive coverage analysis of: %s\n", opts.ProjectPath)
+		i18n.Printf("🔍 Starting comprehensive coverage analysis of: %s\n", opts.ProjectPath)
+	}
+
+	var prof *selfProfiler
+	if opts.SelfProfile {
+		prof = startSelfProfile("walk")
+		defer prof.halt()
+	}
+
+	e.progress = opts.Progress
+	e.warnings = nil
+	if opts.ParseCache != nil {
+		e.parseCache, e.fset = opts.ParseCache, opts.ParseCache.fset
+	}
+	e.includeInit, e.includeCgo = opts.IncludeInit, opts.IncludeCgo
+	e.highComplexity = opts.HighComplexity
+	if e.highComplexity <= 0 {
+		e.highComplexity = models.DefaultHighComplexityThreshold
+	}
+	e.lineLimit, e.paramLimit = opts.MaxFunctionLines, opts.MaxParams
+	if e.lineLimit <= 0 {
+		e.lineLimit = models.DefaultFunctionLineLimit
+	}
+	if e.paramLimit <= 0 {
+		e.paramLimit = models.DefaultParamLimit
+	}
+	e.classifier = opts.Classification
+	if e.classifier == nil {
+		e.classifier = DefaultClassificationRules()
+	}
+
+	// Step 0.5: Limit the analysis to a file or directory of the project
+	e.scope = nil
+	if opts.Scope != "" {
+		info, err := os.Stat(filepath.Join(opts.ProjectPath, opts.Scope))
+		if err != nil {
+			return nil, fmt.Errorf("failed to find scope %s: %w", opts.Scope, err)
+		}
+		e.scope = newAnalysisScope(opts.Scope, !info.IsDir())
 	}
 
 	// Step 1: Get project information
-	projectInfo, err := e.parser.GetProjectInfo(opts.ProjectPath)
+	projectDir := "."
+	if e.scope != nil {
+		projectDir = e.scope.dir
+	}
+	projectInfo, err := e.parser.GetProjectInfoWithin(opts.ProjectPath, projectDir)
 	if err != nil {
 		return nil, fmt.Errorf("failed to analyze project: %w", err)
 	}
 
+	// Step 1.5: Map packages and modules to their directories so profile file names resolve to project files
+	listCtx, cancel := command.WithTimeout(ctx, opts.CommandTimeout)
+	e.modules, err = LoadModules(listCtx, opts.ProjectPath)
+	cancel()
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	if err != nil {
+		e.warn("Could not list modules, resolving coverage by the go.mod module path: %v", err)
+		e.modules = NewModuleMap(opts.ProjectPath, projectInfo.ModulePath)
+	}
+
 	// Step 2: Generate coverage profile if requested
+	prof.next("profile")
 	var profilePath string
 	if opts.GenerateProfile {
 		profilePath = opts.ProfileOutput
@@ -52,7 +140,16 @@ func (e *AnalysisEngine) AnalyzeProject(opts *AnalysisOptions) (*models.Analysis
 			profilePath = filepath.Join(opts.ProjectPath, "coverage.out")
 		}
 
-		if err := e.parser.GenerateProfile(opts.ProjectPath, profilePath, opts.PackagePattern); err != nil {
+		// A scoped analysis only runs the tests of the scope's packages
+		packagePattern := opts.PackagePattern
+		if packagePattern == "" && e.scope != nil {
+			packagePattern = e.scope.packagePattern()
+		}
+
+		profileCtx, cancel := command.WithTimeout(ctx, opts.CommandTimeout)
+		err := e.parser.GenerateProfile(profileCtx, opts.ProjectPath, profilePath, packagePattern)
+		cancel()
+		if err != nil {
 			return nil, fmt.Errorf("failed to generate coverage profile: %w", err)
 		}
 
@@ -71,45 +168,145 @@ func (e *AnalysisEngine) AnalyzeProject(opts *AnalysisOptions) (*models.Analysis
 		if _, err := os.Stat(defaultProfilePath); err == nil {
 			profilePath = defaultProfilePath
 			if e.verbose {
-				fmt.Printf("📋 Found existing coverage profile: %s\n", profilePath)
+				i18n.Printf("📋 Found existing coverage profile: %s\n", profilePath)
 			}
+		} else {
+			e.warn("No coverage profile found, so no function counts as covered; generate one with --profile")
 		}
 	}
 
 	// Step 3: Parse coverage profile
 	var profile *models.CoverageProfile
 	if profilePath != "" {
-		profile, err = e.parser.ParseProfile(profilePath)
-		if err != nil {
-			return nil, fmt.Errorf("failed to parse coverage profile: %w", err)
-		}
+		// A scoped analysis streams the profile too, keeping only the blocks of its files
+		if opts.StreamProfile || e.scope != nil {
+			profile, err = e.parser.StreamProfile(profilePath, e.keepProfileFile(opts))
+			if err != nil {
+				return nil, fmt.Errorf("failed to stream coverage profile: %w", err)
+			}
+		} else {
+			profile, err = e.parser.ParseProfile(profilePath)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse coverage profile: %w", err)
+			}
 
-		if err := e.parser.ValidateProfile(profile); err != nil {
-			return nil, fmt.Errorf("invalid coverage profile: %w", err)
+			if err := e.parser.ValidateProfile(profile); err != nil {
+				return nil, fmt.Errorf("invalid coverage profile: %w", err)
+			}
 		}
 	}
 
 	// Step 4: Parse source files and build AST
-	packages, err := e.parseSourceFiles(opts.ProjectPath, opts.ExcludeDirs, opts.IncludeTests)
+	prof.next("parse")
+	packages, err := e.parseSourceFiles(ctx, opts.ProjectPath, opts.ExcludeDirs, opts.IncludeTests)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse source files: %w", err)
 	}
 
+	// Step 4.05: Keep only the packages selected by the include and exclude patterns, and the files
+	// of the classes coverage is computed over
+	filterPackages(packages, opts.IncludePackages, opts.ExcludePackages)
+	excludedFiles, excludedFunctions := filterClasses(packages, countedClasses(opts))
+	excludedFunctions = append(excludedFunctions, exemptFiles(packages, opts.ExemptFiles)...)
+
+	// Step 4.1: Link methods with SQL statements to the database handle of their receiver
+	e.applySQLReceivers(packages)
+
+	// Step 4.2: Record which named types in signatures are interfaces
+	e.applyInterfaces(packages)
+
+	// Step 4.25: Link methods to the HTTP client and function fields of their receiver
+	e.applyDependencyFields(packages)
+
+	// Step 4.27: Record the clock interfaces functions can be handed, for tests with a fake clock
+	e.applyClocks(packages)
+
+	// Step 4.3: Optionally treat complex enough unexported functions as testable
+	if opts.IncludeUnexported {
+		e.markUnexportedTestable(packages, opts.UnexportedMinComplexity)
+	}
+
+	// Step 4.4: Leave the functions matching the ignore and exemption patterns out of the totals
+	markMatchingFunctions(packages, opts.IgnoreFunctions, models.UntestableIgnored)
+	markMatchingFunctions(packages, opts.ExemptFunctions, models.UntestableExempted)
+
+	// Step 4.5: Find exported identifiers referenced from test files
+	decls := e.newDeclarations(packages)
+	testRefs, err := e.collectTestReferences(opts.ProjectPath, opts.ExcludeDirs, decls)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan test files: %w", err)
+	}
+	e.applyTestReferences(packages, testRefs, decls)
+
+	// Callers may be anywhere in the project, so a scoped analysis does not look for dead code
+	var callRefs map[string]int
+	if e.scope == nil {
+		callRefs, err = e.collectCallReferences(opts.ProjectPath, opts.ExcludeDirs, decls)
+		if err != nil {
+			return nil, fmt.Errorf("failed to collect call references: %w", err)
+		}
+	}
+
+	testMetrics, err := e.collectTestMetrics(opts.ProjectPath, opts.ExcludeDirs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to measure test files: %w", err)
+	}
+
 	// Step 5: Analyze coverage and identify gaps
+	prof.next("coverage-apply")
 	result, err := e.buildAnalysisResult(packages, profile, projectInfo, opts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to build analysis result: %w", err)
 	}
 
 	// Step 6: Calculate summary statistics
+	prof.next("summary")
 	e.calculateSummaryStatistics(result)
+	e.calculateAPICoverage(result)
+	if callRefs != nil {
+		e.applyDeadCodeDetection(result, callRefs, decls)
+	}
+	e.applyTestMetrics(result, testMetrics)
+	e.applyUntestableFunctions(result, excludedFunctions)
+	if opts.IncludeInit {
+		e.applyInitializationCoverage(result)
+	}
+	e.applyTypeCoverage(result)
+	for _, typeCoverage := range result.TypeCoverage {
+		if len(typeCoverage.UntestedInterfaces) > 0 {
+			result.Summary.UntestedImplementations++
+		}
+	}
+
+	// Step 6.5: Optionally find out what each test covers on its own, one run per test
+	if opts.AttributeTests {
+		prof.next("attribution")
+		if err := e.attributeTests(ctx, result, testMetrics, opts.CommandTimeout); err != nil {
+			return nil, fmt.Errorf("failed to attribute coverage to tests: %w", err)
+		}
+	}
 
 	result.Metadata.AnalysisTime = time.Since(startTime)
+	result.Metadata.ExcludedFiles = excludedFiles
 	result.Metadata.ProfilePath = profilePath
+	if e.scope != nil {
+		result.Metadata.Scope = opts.Scope
+	}
+	result.Metadata.SelfProfile = prof.finish()
+
+	// Step 7: An existing profile may predate changes to the sources it is matched against
+	if !opts.GenerateProfile && profilePath != "" {
+		result.StaleProfile = staleProfile(ctx, opts.ProjectPath, profilePath, result)
+		if result.StaleProfile != nil {
+			e.warn("%s is older than %d source files modified since, such as %s; regenerate it with --profile",
+				filepath.Base(profilePath), len(result.StaleProfile.ModifiedFiles), result.StaleProfile.ModifiedFiles[0])
+		}
+	}
+	result.Warnings = e.warnings
 
 	if e.verbose {
-		fmt.Printf("✅ Analysis completed in %v\n", result.Metadata.AnalysisTime)
-		fmt.Printf("📊 Found %d packages, %d files, %d functions\n",
+		i18n.Printf("✅ Analysis completed in %v\n", result.Metadata.AnalysisTime)
+		i18n.Printf("📊 Found %d packages, %d files, %d functions\n",
 			result.Summary.TotalPackages,
 			result.Summary.TotalFiles,
 			result.Summary.TotalFunctions)
@@ -124,15 +321,47 @@ type AnalysisOptions struct {
 	ProfilePath         string
 	ProfileOutput       string
 	PackagePattern      string
+	IncludePackages     []string             // Go-style patterns of the packages analyzed, empty for all
+	Classification      *ClassificationRules // nil for DefaultClassificationRules
+	Classes             []string             // file classes coverage is computed over, empty for production code and included tests
+	ExcludePackages     []string             // Go-style patterns of packages left out of the analysis
 	ExcludeDirs         []string
 	IncludeTests        bool
 	GenerateProfile     bool
+	StreamProfile       bool                // read the profile line by line, keeping blocks only for selected project files
+	Progress            models.ProgressFunc // told how many files are parsed and packages analyzed, may be nil
+	CommandTimeout      time.Duration       // bounds each go command run, such as go test for the profile; 0 for none
 	CalculateComplexity bool
 	MinComplexity       int
+	HighComplexity      int         // complexity above which functions count as highly complex, 0 for models.DefaultHighComplexityThreshold
+	MaxFunctionLines    int         // lines of code above which functions count as oversized, 0 for models.DefaultFunctionLineLimit
+	MaxParams           int         // parameters above which functions count as oversized, 0 for models.DefaultParamLimit
+	AttributeTests      bool        // run every top-level test on its own to record which functions it covers, see TestMatrix
+	SelfProfile         bool        // record the time of each analysis phase and the peak heap in Metadata.SelfProfile
+	Scope               string      // file or directory relative to the project analyzed on its own, "" for the whole project
+	ParseCache          *ParseCache // reuse the files earlier analyses parsed, unless they changed; nil to parse all
+
+	// Unexported functions are only testable when explicitly included
+	IncludeUnexported       bool
+	UnexportedMinComplexity int
+
+	// Patterns of functions left out of the totals, such as Must* or Server.Close
+	IgnoreFunctions []string
+
+	// Patterns of the functions and files of unexpired exemptions, left out of the totals as exempted.
+	// File patterns are those of ClassificationRules
+	ExemptFunctions []string
+	ExemptFiles     []string
+
+	// Report the coverage of init functions and package-level variable initializers as initialization
+	IncludeInit bool
+
+	// Count the functions of files importing "C" as testable, which are left out of the totals otherwise
+	IncludeCgo bool
 }
 
 // parseSourceFiles parses all Go source files in the project
-func (e *AnalysisEngine) parseSourceFiles(projectPath string, excludeDirs []string, includeTests bool) (map[string]*models.Package, error) {
+func (e *AnalysisEngine) parseSourceFiles(ctx context.Context, projectPath string, excludeDirs []string, includeTests bool) (map[string]*models.Package, error) {
 	packages := make(map[string]*models.Package)
 
 	excludeMap := make(map[string]bool)
@@ -140,20 +369,24 @@ func (e *AnalysisEngine) parseSourceFiles(projectPath string, excludeDirs []stri
 		excludeMap[dir] = true
 	}
 
-	err := filepath.Walk(projectPath, func(path string, info os.FileInfo, err error) error {
+	// Collect the files first, so progress can be reported against their total
+	var paths []string
+	root := e.scope.root(projectPath)
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
 
+		relPath, _ := filepath.Rel(projectPath, path)
 		if info.IsDir() {
-			// Skip excluded directories
-			if excludeMap[info.Name()] {
+			// Skip excluded directories, and hidden ones such as .gcov as the go command does
+			if excludeMap[info.Name()] || path != root && strings.HasPrefix(info.Name(), ".") || !e.scope.walkDir(relPath) {
 				return filepath.SkipDir
 			}
 			return nil
 		}
 
-		if !strings.HasSuffix(path, ".go") {
+		if !strings.HasSuffix(path, ".go") || !e.scope.contains(relPath) {
 			return nil
 		}
 
@@ -162,21 +395,40 @@ func (e *AnalysisEngine) parseSourceFiles(projectPath string, excludeDirs []stri
 			return nil
 		}
 
-		return e.parseGoFile(path, projectPath, packages)
+		paths = append(paths, path)
+		return nil
 	})
+	if err != nil {
+		return packages, err
+	}
 
-	return packages, err
+	for i, path := range paths {
+		if ctx.Err() != nil {
+			return packages, ctx.Err()
+		}
+		if err := e.parseGoFile(path, projectPath, packages); err != nil {
+			return packages, err
+		}
+		e.reportProgress("Parsing files", i+1, len(paths))
+	}
+
+	return packages, nil
+}
+
+// reportProgress tells the progress callback, when there is one, that done of total units of a stage are finished
+func (e *AnalysisEngine) reportProgress(stage string, done, total int) {
+	if e.progress != nil {
+		e.progress(stage, done, total)
+	}
 }
 
 // parseGoFile parses a single Go file and extracts functions
 func (e *AnalysisEngine) parseGoFile(filePath, projectPath string, packages map[string]*models.Package) error {
-	src, err := os.ReadFile(filePath)
-	if err != nil {
+	// Parse the file; there is no syntax tree only when it could not be read
+	src, file, err := e.parseFile(filePath, parser.ParseComments)
+	if file == nil {
 		return fmt.Errorf("failed to read file %s: %w", filePath, err)
 	}
-
-	// Parse the file
-	file, err := parser.ParseFile(e.fset, filePath, src, parser.ParseComments)
 	if err != nil {
 		return fmt.Errorf("failed to parse file %s: %w", filePath, err)
 	}
@@ -198,6 +450,18 @@ func (e *AnalysisEngine) parseGoFile(filePath, projectPath string, packages map[
 		Package:   packageName,
 		Functions: make([]*models.Function, 0),
 		HasTests:  strings.HasSuffix(filePath, "_test.go"),
+		Class:     e.classifier.Classify(relFilePath, file),
+		Cgo:       importsC(file),
+	}
+
+	e.collectSQLFields(file)
+	e.collectDependencyFields(file)
+	e.collectInterfaces(file)
+	e.collectInterfaceMethods(file)
+	e.collectClocks(file)
+	e.constants = collectConstants(file.Decls)
+	if e.includeInit {
+		e.extractInitializers(file, fileModel)
 	}
 
 	// Extract functions from the AST
@@ -252,6 +516,7 @@ func (e *AnalysisEngine) extractFunction(funcDecl *ast.FuncDecl, file *models.Fi
 
 	// Extract parameters
 	if funcDecl.Type.Params != nil {
+		function.ParamCount = funcDecl.Type.Params.NumFields()
 		for _, param := range funcDecl.Type.Params.List {
 			paramType := e.extractTypeName(param.Type)
 			for _, name := range param.Names {
@@ -267,7 +532,15 @@ func (e *AnalysisEngine) extractFunction(funcDecl *ast.FuncDecl, file *models.Fi
 	if funcDecl.Type.Results != nil {
 		for _, result := range funcDecl.Type.Results.List {
 			resultType := e.extractTypeName(result.Type)
-			function.ReturnTypes = append(function.ReturnTypes, resultType)
+
+			// Named results like (x, y int) declare one value per name
+			count := len(result.Names)
+			if count == 0 {
+				count = 1
+			}
+			for i := 0; i < count; i++ {
+				function.ReturnTypes = append(function.ReturnTypes, resultType)
+			}
 
 			// Check for error return type
 			if resultType == "error" {
@@ -279,16 +552,50 @@ func (e *AnalysisEngine) extractFunction(funcDecl *ast.FuncDecl, file *models.Fi
 	// Build function signature
 	function.Signature = e.buildFunctionSignature(function)
 
+	// Declarations without a body are implemented in assembly and have no statements to cover
+	if funcDecl.Body == nil {
+		function.IsTestable = false
+		function.UntestableReason = models.UntestableAssembly
+		return function
+	}
+
 	// Calculate cyclomatic complexity (simplified)
 	function.Complexity = e.calculateComplexity(funcDecl)
+	function.Lines = countCodeLines([]byte(source[position.Offset:endPosition.Offset]))
 
-	// Determine if function is testable
-	function.IsTestable = e.isFunctionTestable(function)
+	// Extract function literals as closures of the function, covered on their own
+	function.Closures = e.extractClosures(funcDecl.Body, function, function.Name+".func", source)
 
-	// Check if it's a test function itself
-	if strings.HasPrefix(function.Name, "Test") && file.HasTests {
-		function.IsTestable = false
+	// Flag goroutines, channels and sync primitives for leak and race checks
+	function.Concurrent = e.isConcurrent(funcDecl)
+
+	// Record temp dirs, environment variables and servers its tests need
+	e.extractFixtures(funcDecl, function)
+
+	// Record database/sql handles and statements for sqlmock-backed tests
+	e.extractSQLUsage(funcDecl, function)
+
+	// Record HTTP client usage for tests with a fake transport
+	e.extractHTTPUsage(funcDecl, function)
+
+	// Record environment, filesystem, network and clock dependencies
+	e.extractSideEffects(funcDecl, function)
+
+	// Record comparisons against constants for boundary-value cases
+	e.extractBoundaries(funcDecl, function)
+
+	// Record error returns and the conditions leading to them
+	e.extractErrorPaths(funcDecl, function, source)
+
+	// Record panics, nil map writes and unchecked indexing with the conditions leading to them
+	e.extractPanicPaths(funcDecl, function, source)
+
+	// Determine if function is testable
+	function.UntestableReason = untestableReason(function)
+	if function.UntestableReason == "" && file.Cgo && !e.includeCgo {
+		function.UntestableReason = models.UntestableCgo
 	}
+	function.IsTestable = function.UntestableReason == ""
 
 	return function
 }
@@ -307,6 +614,8 @@ func (e *AnalysisEngine) extractTypeName(expr ast.Expr) string {
 		return "*" + e.extractTypeName(t.X)
 	case *ast.ArrayType:
 		return "[]" + e.extractTypeName(t.Elt)
+	case *ast.Ellipsis:
+		return "..." + e.extractTypeName(t.Elt)
 	case *ast.MapType:
 		return "map[" + e.extractTypeName(t.Key) + "]" + e.extractTypeName(t.Value)
 	case *ast.InterfaceType:
@@ -314,12 +623,53 @@ func (e *AnalysisEngine) extractTypeName(expr ast.Expr) string {
 	case *ast.StructType:
 		return "struct{}"
 	case *ast.FuncType:
-		return "func"
+		return e.funcTypeName(t)
+	case *ast.ChanType:
+		switch t.Dir {
+		case ast.RECV:
+			return "<-chan " + e.extractTypeName(t.Value)
+		case ast.SEND:
+			return "chan<- " + e.extractTypeName(t.Value)
+		default:
+			return "chan " + e.extractTypeName(t.Value)
+		}
 	default:
 		return "unknown"
 	}
 }
 
+// funcTypeName renders a function type with its parameter and result types, e.g. func(string, int) (bool, error)
+func (e *AnalysisEngine) funcTypeName(t *ast.FuncType) string {
+	fieldTypes := func(fields *ast.FieldList) []string {
+		var types []string
+		if fields == nil {
+			return types
+		}
+		for _, field := range fields.List {
+			fieldType := e.extractTypeName(field.Type)
+			count := len(field.Names)
+			if count == 0 {
+				count = 1
+			}
+			for i := 0; i < count; i++ {
+				types = append(types, fieldType)
+			}
+		}
+		return types
+	}
+
+	name := "func(" + strings.Join(fieldTypes(t.Params), ", ") + ")"
+	results := fieldTypes(t.Results)
+	switch len(results) {
+	case 0:
+		return name
+	case 1:
+		return name + " " + results[0]
+	default:
+		return name + " (" + strings.Join(results, ", ") + ")"
+	}
+}
+
 // buildFunctionSignature creates a function signature string
 func (e *AnalysisEngine) buildFunctionSignature(function *models.Function) string {
 	var sig strings.Builder
@@ -361,10 +711,10 @@ func (e *AnalysisEngine) buildFunctionSignature(function *models.Function) strin
 }
 
 // calculateComplexity calculates cyclomatic complexity (simplified implementation)
-func (e *AnalysisEngine) calculateComplexity(funcDecl *ast.FuncDecl) int {
+func (e *AnalysisEngine) calculateComplexity(fn ast.Node) int {
 	complexity := 1 // Base complexity
 
-	ast.Inspect(funcDecl, func(n ast.Node) bool {
+	ast.Inspect(fn, func(n ast.Node) bool {
 		switch n.(type) {
 		case *ast.IfStmt, *ast.ForStmt, *ast.RangeStmt, *ast.SwitchStmt,
 			*ast.TypeSwitchStmt, *ast.SelectStmt:
@@ -378,32 +728,85 @@ func (e *AnalysisEngine) calculateComplexity(funcDecl *ast.FuncDecl) int {
 	return complexity
 }
 
-// isFunctionTestable determines if a function should have tests generated
-func (e *AnalysisEngine) isFunctionTestable(function *models.Function) bool {
+// isConcurrent reports whether a function spawns goroutines or coordinates through channels or sync primitives
+func (e *AnalysisEngine) isConcurrent(funcDecl *ast.FuncDecl) bool {
+	concurrent := false
+
+	ast.Inspect(funcDecl, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.GoStmt, *ast.SendStmt, *ast.SelectStmt, *ast.ChanType:
+			concurrent = true
+		case *ast.UnaryExpr:
+			if node.Op == token.ARROW {
+				concurrent = true
+			}
+		case *ast.SelectorExpr:
+			if pkg, ok := node.X.(*ast.Ident); ok && (pkg.Name == "sync" || pkg.Name == "atomic") {
+				concurrent = true
+			}
+			// Locking a mutex held in a field, e.g. s.mu.Lock()
+			switch node.Sel.Name {
+			case "Lock", "Unlock", "RLock", "RUnlock":
+				concurrent = true
+			}
+		}
+		return !concurrent
+	})
+
+	return concurrent
+}
+
+// untestableReason returns why a function should not have tests generated, one of the Untestable
+// constants, or "" when it should
+func untestableReason(function *models.Function) string {
+	switch {
 	// Skip test functions themselves
-	if strings.HasPrefix(function.Name, "Test") ||
+	case strings.HasPrefix(function.Name, "Test") ||
 		strings.HasPrefix(function.Name, "Benchmark") ||
-		strings.HasPrefix(function.Name, "Example") {
-		return false
+		strings.HasPrefix(function.Name, "Example"):
+		return models.UntestableTest
+	case function.Name == "init":
+		return models.UntestableInit
+	case function.Name == "main":
+		return models.UntestableMain
+	// Only test exported functions and methods
+	case !function.IsExported:
+		return models.UntestableUnexported
+	default:
+		return ""
 	}
+}
 
-	// Skip init functions
-	if function.Name == "init" {
-		return false
-	}
+// markUnexportedTestable marks unexported functions at or above the complexity floor as testable
+func (e *AnalysisEngine) markUnexportedTestable(packages map[string]*models.Package, minComplexity int) {
+	marked := 0
 
-	// Skip main function
-	if function.Name == "main" {
-		return false
+	for _, pkg := range packages {
+		for _, file := range pkg.Files {
+			for _, function := range file.Functions {
+				if function.IsExported || function.IsTestable || function.UntestableReason != models.UntestableUnexported {
+					continue
+				}
+				if function.Name == "init" || function.Name == "main" || function.Complexity < minComplexity {
+					continue
+				}
+
+				function.IsTestable = true
+				function.UntestableReason = ""
+				marked++
+			}
+		}
 	}
 
-	// Only test exported functions and methods
-	return function.IsExported
+	if e.verbose {
+		i18n.Printf("🔓 Including %d unexported functions with complexity >= %d\n", marked, minComplexity)
+	}
 }
 
 // buildAnalysisResult combines AST analysis with coverage data
 func (e *AnalysisEngine) buildAnalysisResult(packages map[string]*models.Package, profile *models.CoverageProfile, projectInfo *models.ProjectInfo, opts *AnalysisOptions) (*models.AnalysisResult, error) {
 	result := &models.AnalysisResult{
+		SchemaVersion:      models.SchemaVersion,
 		ProjectPath:        opts.ProjectPath,
 		Timestamp:          time.Now(),
 		PackageCoverage:    packages,
@@ -423,6 +826,9 @@ func (e *AnalysisEngine) buildAnalysisResult(packages map[string]*models.Package
 		e.applyCoverageData(packages, profile)
 	}
 
+	// Score functions once coverage is known
+	e.applyRiskScores(packages)
+
 	// Identify uncovered functions
 	for _, pkg := range packages {
 		for _, file := range pkg.Files {
@@ -444,57 +850,51 @@ func (e *AnalysisEngine) buildAnalysisResult(packages map[string]*models.Package
 
 // applyCoverageData applies coverage information to functions and files
 func (e *AnalysisEngine) applyCoverageData(packages map[string]*models.Package, profile *models.CoverageProfile) {
-	// Create a mapping of file paths to coverage blocks
+	// Map coverage blocks to the project files they belong to
 	fileBlocks := make(map[string][]*models.ProfileBlock)
+	unresolved := make(map[string]bool)
 
 	if e.verbose {
-		fmt.Printf("📋 Processing %d coverage blocks\n", len(profile.Blocks))
+		i18n.Printf("📋 Processing %d coverage blocks\n", len(profile.Blocks))
 	}
 
-	// Map profile blocks with multiple path variants
 	for _, block := range profile.Blocks {
-		// Store blocks under multiple keys to improve matching
-		keys := e.generatePathVariants(block.FileName)
-		for _, key := range keys {
-			fileBlocks[key] = append(fileBlocks[key], block)
+		relPath, ok := e.modules.Resolve(block.FileName)
+		if !ok {
+			unresolved[block.FileName] = true
+			continue
 		}
+		fileBlocks[relPath] = append(fileBlocks[relPath], block)
+	}
 
-		if e.verbose && len(fileBlocks) < 5 { // Only show first few for debugging
-			fmt.Printf("   Block: %s -> %v\n", block.FileName, keys)
-		}
+	if len(unresolved) > 0 {
+		e.warn("%d files of the coverage profile are not in the project, such as %s", len(unresolved), firstKey(unresolved))
 	}
 
 	// Apply coverage data to each function
+	analyzed, matched := 0, 0
 	for _, pkg := range packages {
 		for _, file := range pkg.Files {
-			// Try multiple path variants to find matching blocks
-			var blocks []*models.ProfileBlock
-			pathVariants := e.generatePathVariants(file.Path)
-
-			for _, variant := range pathVariants {
-				if foundBlocks, exists := fileBlocks[variant]; exists {
-					blocks = foundBlocks
-					if e.verbose {
-						fmt.Printf("   Matched file %s with variant %s (%d blocks)\n", file.Path, variant, len(blocks))
-					}
-					break
-				}
-			}
-
+			blocks := fileBlocks[filepath.ToSlash(file.Path)]
 			if len(blocks) == 0 {
 				if e.verbose {
-					fmt.Printf("   No coverage blocks found for file %s (tried: %v)\n", file.Path, pathVariants)
+					fmt.Printf("   No coverage blocks found for file %s\n", file.Path)
 				}
 				continue
 			}
 
+			matched++
+
 			// Convert profile blocks to internal blocks
 			file.CoverageBlocks = e.parser.ConvertToBlocks(blocks)
 
-			// Apply coverage to functions
+			// Apply coverage to functions, looking their blocks up in an interval tree of the file's blocks
+			tree := newBlockTree(blocks)
 			for _, function := range file.Functions {
-				function.Coverage, function.IsCovered = e.calculateFunctionCoverage(function, blocks)
+				e.calculateFunctionCoverage(function, tree)
+				e.calculateClosureCoverage(function.Closures, tree)
 			}
+			e.calculateInitializerCoverage(file.Initializers, tree)
 
 			// Calculate file-level coverage
 			e.calculateFileCoverage(file)
@@ -502,55 +902,79 @@ func (e *AnalysisEngine) applyCoverageData(packages map[string]*models.Package,
 
 		// Calculate package-level coverage
 		e.calculatePackageCoverage(pkg)
-	}
-}
 
-// generatePathVariants creates multiple path variants to improve matching
-func (e *AnalysisEngine) generatePathVariants(path string) []string {
-	variants := []string{path} // Always include original path
+		analyzed++
+		e.reportProgress("Analyzing packages", analyzed, len(packages))
+	}
 
-	// Add filename only variant
-	filename := filepath.Base(path)
-	if filename != path {
-		variants = append(variants, filename)
+	if matched == 0 && len(profile.Blocks) > 0 {
+		e.warn("None of the %d blocks of the coverage profile matched a project file; was it generated for another module?", len(profile.Blocks))
 	}
+}
 
-	// If path contains module prefix, create relative variant
-	if strings.Contains(path, "/") {
-		parts := strings.Split(path, "/")
-		if len(parts) > 1 {
-			// Try removing first N parts to get relative paths
-			for i := 1; i < len(parts); i++ {
-				relativePath := strings.Join(parts[i:], "/")
-				variants = append(variants, relativePath)
-			}
-		}
+// warn records a problem that leaves the analysis incomplete without failing it, such as a profile that
+// matches no file, in AnalysisResult.Warnings for --strict, and prints it in verbose mode
+func (e *AnalysisEngine) warn(format string, args ...any) {
+	message := fmt.Sprintf(format, args...)
+	if slices.Contains(e.warnings, message) {
+		return
+	}
+	e.warnings = append(e.warnings, message)
+	if e.verbose {
+		i18n.Printf("⚠️ %s\n", message)
 	}
+}
 
-	return variants
+// firstKey returns the first of the keys of a set in sorted order
+func firstKey(set map[string]bool) string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys[0]
 }
 
-// calculateFunctionCoverage determines coverage percentage for a function
-func (e *AnalysisEngine) calculateFunctionCoverage(function *models.Function, blocks []*models.ProfileBlock) (float64, bool) {
+// calculateFunctionCoverage determines coverage percentage and statement counts for a function
+func (e *AnalysisEngine) calculateFunctionCoverage(function *models.Function, blocks *blockTree) {
 	totalStmts := 0
 	coveredStmts := 0
 
-	for _, block := range blocks {
-		// Check if block overlaps with function
-		if block.StartLine >= function.StartLine && block.EndLine <= function.EndLine {
-			totalStmts += block.NumStmts
+	blocks.within(function.StartLine, function.EndLine, func(block *models.ProfileBlock) {
+		totalStmts += block.NumStmts
+		if block.Count > 0 {
+			coveredStmts += block.NumStmts
+		}
+	})
+
+	function.Statements = totalStmts
+	function.CoveredStatements = coveredStmts
+
+	// An error path is covered when a block holding its return ran
+	for _, path := range function.ErrorPaths {
+		blocks.containing(path.Line, func(block *models.ProfileBlock) bool {
 			if block.Count > 0 {
-				coveredStmts += block.NumStmts
+				path.Covered = true
 			}
-		}
+			return !path.Covered
+		})
 	}
 
 	if totalStmts == 0 {
-		return 0.0, false
+		function.Coverage, function.IsCovered = 0.0, false
+		return
 	}
 
-	coverage := float64(coveredStmts) / float64(totalStmts) * 100.0
-	return coverage, coveredStmts > 0
+	function.Coverage = float64(coveredStmts) / float64(totalStmts) * 100.0
+	function.IsCovered = coveredStmts > 0
+}
+
+// calculateClosureCoverage determines the coverage of closures and the closures within them
+func (e *AnalysisEngine) calculateClosureCoverage(closures []*models.Function, blocks *blockTree) {
+	for _, closure := range closures {
+		e.calculateFunctionCoverage(closure, blocks)
+		e.calculateClosureCoverage(closure.Closures, blocks)
+	}
 }
 
 // calculateFileCoverage calculates coverage statistics for a file
@@ -634,6 +1058,7 @@ func (e *AnalysisEngine) calculateSummaryStatistics(result *models.AnalysisResul
 	totalComplexity := 0
 	maxComplexity := 0
 	highComplexityCount := 0
+	oversizedCount := 0
 	publicFunctions := 0
 	privateFunctions := 0
 	coveredPublic := 0
@@ -661,9 +1086,12 @@ func (e *AnalysisEngine) calculateSummaryStatistics(result *models.AnalysisResul
 					maxComplexity = function.Complexity
 				}
 
-				if function.Complexity > 10 { // High complexity threshold
+				if function.Complexity > e.highComplexity {
 					highComplexityCount++
 				}
+				if len(function.OverLimits) > 0 {
+					oversizedCount++
+				}
 
 				if function.IsExported {
 					publicFunctions++
@@ -726,6 +1154,11 @@ func (e *AnalysisEngine) calculateSummaryStatistics(result *models.AnalysisResul
 	summary.TotalComplexity = totalComplexity
 	summary.MaxComplexity = maxComplexity
 	summary.HighComplexityFunctions = highComplexityCount
+	summary.HighComplexityThreshold = e.highComplexity
+	summary.OversizedFunctions = oversizedCount
+	summary.UncoveredClosures = len(result.GetUncoveredClosures())
+	summary.FunctionLineLimit = e.lineLimit
+	summary.ParamLimit = e.paramLimit
 
 	if totalFunctions > 0 {
 		summary.AvgComplexity = float64(totalComplexity) / float64(totalFunctions)
@@ -0,0 +1,163 @@
+package coverage
+
+import (
+	"go/ast"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/beck/go-coverage-analyzer/pkg/models"
+)
+
+// testReference is a reference from a test file to a function or method of the project
+type testReference struct {
+	reference
+	file string // test file relative to the project
+}
+
+// collectTestReferences scans all test files and records the references they make to functions and
+// methods of the project: pkg.Foo() to the Foo of the package imported as pkg, Foo() in an internal test
+// package to the Foo of its package, and svc.Method() to the Method of the type of svc where the syntax
+// tells it. Tests in any directory count, external test packages and integration tests included
+func (e *AnalysisEngine) collectTestReferences(projectPath string, excludeDirs []string, decls *declarations) ([]testReference, error) {
+	refs := make([]testReference, 0)
+
+	excludeMap := make(map[string]bool)
+	for _, dir := range excludeDirs {
+		excludeMap[dir] = true
+	}
+
+	// Tests sit next to the code they test, so a scoped analysis only walks the scope
+	root := e.scope.root(projectPath)
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			relDir, _ := filepath.Rel(projectPath, path)
+			if excludeMap[info.Name()] || path != root && strings.HasPrefix(info.Name(), ".") || !e.scope.walkDir(relDir) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if !strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+
+		relPath, _ := filepath.Rel(projectPath, path)
+		_, file, err := e.parseFile(path, 0)
+		if err != nil {
+			// Unparseable test files simply contribute no references
+			e.warn("Skipping %s, it does not parse: %v", relPath, err)
+			return nil
+		}
+
+		// The unqualified names of an external test package are its own, not those of the package tested
+		external := strings.HasSuffix(file.Name.Name, "_test")
+		pkgPath := decls.importPath(filepath.Dir(relPath))
+		for _, fileRefs := range decls.fileReferences(file, pkgPath, !external) {
+			for _, ref := range fileRefs {
+				refs = append(refs, testReference{reference: ref, file: relPath})
+			}
+		}
+
+		return nil
+	})
+
+	return refs, err
+}
+
+// applyTestReferences marks functions that are referenced from test files. A method called on a value
+// of unknown type counts only when a single method of that name is in the packages it may be from, so
+// one x.Close() does not mark every Close of the package as tested
+func (e *AnalysisEngine) applyTestReferences(packages map[string]*models.Package, refs []testReference, decls *declarations) {
+	testFiles := make(map[string][]string)
+	for _, ref := range refs {
+		targets := decls.targets(ref.reference)
+		if len(targets) != 1 {
+			continue
+		}
+		testFiles[targets[0]] = appendUnique(testFiles[targets[0]], ref.file)
+	}
+
+	for _, pkg := range packages {
+		for _, file := range pkg.Files {
+			pkgPath := decls.importPath(filepath.Dir(file.Path))
+			for _, function := range file.Functions {
+				if files, ok := testFiles[decls.functionKey(pkgPath, function)]; ok {
+					function.HasTests = true
+					function.TestFiles = files
+				}
+			}
+		}
+	}
+}
+
+// calculateAPICoverage computes the share of exported API surface referenced by tests.
+// Only non-main packages are considered, and methods count only when their receiver
+// type is itself exported.
+func (e *AnalysisEngine) calculateAPICoverage(result *models.AnalysisResult) {
+	untested := make([]*models.Function, 0)
+	total := 0
+
+	for _, pkg := range result.PackageCoverage {
+		if pkg.Name == "main" {
+			continue
+		}
+
+		for _, file := range pkg.Files {
+			for _, function := range file.Functions {
+				if !isAPIFunction(function) {
+					continue
+				}
+
+				total++
+				if !function.HasTests {
+					untested = append(untested, function)
+				}
+			}
+		}
+	}
+
+	sort.Slice(untested, func(i, j int) bool {
+		if untested[i].File != untested[j].File {
+			return untested[i].File < untested[j].File
+		}
+		return untested[i].StartLine < untested[j].StartLine
+	})
+
+	result.UntestedAPI = untested
+	result.Summary.ExportedAPI = total
+	result.Summary.TestedAPI = total - len(untested)
+
+	if total > 0 {
+		result.APICoverage = float64(total-len(untested)) / float64(total) * 100.0
+		result.Summary.APICoverage = result.APICoverage
+	}
+}
+
+// isAPIFunction reports whether a function is part of a package's exported API surface
+func isAPIFunction(function *models.Function) bool {
+	if !function.IsTestable || !function.IsExported {
+		return false
+	}
+
+	if function.IsMethod {
+		return ast.IsExported(strings.TrimPrefix(function.ReceiverType, "*"))
+	}
+
+	return true
+}
+
+// appendUnique appends a value to a slice if it is not already present
+func appendUnique(slice []string, value string) []string {
+	for _, existing := range slice {
+		if existing == value {
+			return slice
+		}
+	}
+	return append(slice, value)
+}
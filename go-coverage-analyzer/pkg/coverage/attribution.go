@@ -0,0 +1,155 @@
+package coverage
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"time"
+
+	"github.com/beck/go-coverage-analyzer/internal/command"
+	"github.com/beck/go-coverage-analyzer/pkg/models"
+)
+
+// attributeTests runs every top-level test of the analyzed packages on its own, leaving its coverage
+// counters in a directory of its own, and records which functions each test covers. The test binary
+// of a package is built once and run per test; packages whose tests do not build are skipped
+func (e *AnalysisEngine) attributeTests(ctx context.Context, result *models.AnalysisResult, metrics map[string]*testMetrics, timeout time.Duration) error {
+	workDir, err := os.MkdirTemp("", "gcov-attribution-*")
+	if err != nil {
+		return fmt.Errorf("failed to create attribution directory: %w", err)
+	}
+	defer os.RemoveAll(workDir)
+
+	packages := make([]*models.Package, 0, len(result.PackageCoverage))
+	total := 0
+	for _, pkg := range result.PackageCoverage {
+		if dirMetrics := metrics[pkg.Path]; dirMetrics != nil && len(dirMetrics.functions) > 0 {
+			packages = append(packages, pkg)
+			total += len(dirMetrics.functions)
+		}
+	}
+	sort.Slice(packages, func(i, j int) bool {
+		return packages[i].Path < packages[j].Path
+	})
+
+	matrix := make([]*models.TestAttribution, 0, total)
+	done := 0
+	for i, pkg := range packages {
+		tests := metrics[pkg.Path].functions
+		pkgDir := filepath.Join(result.ProjectPath, pkg.Path)
+
+		binary := filepath.Join(workDir, fmt.Sprintf("pkg%d.test", i))
+		if runtime.GOOS == "windows" {
+			binary += ".exe"
+		}
+		buildCtx, cancel := command.WithTimeout(ctx, timeout)
+		build := command.Go(buildCtx, "test", "-c", "-cover", "-covermode=set", "-o", binary, ".")
+		build.Dir = pkgDir
+		output, err := build.CombinedOutput()
+		err = command.Err(buildCtx, err)
+		cancel()
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			e.warn("Skipping test attribution for %s, its tests do not build: %v", pkg.Path, err)
+			if e.verbose {
+				fmt.Printf("%s", output)
+			}
+			done += len(tests)
+			e.reportProgress("Attributing tests", done, total)
+			continue
+		}
+
+		for j, test := range tests {
+			coverDir := filepath.Join(workDir, fmt.Sprintf("pkg%d-test%d", i, j))
+			attribution, err := e.attributeTest(ctx, pkg, pkgDir, binary, coverDir, test, timeout)
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if err != nil {
+				e.warn("Could not attribute coverage to %s: %v", test.name, err)
+			} else {
+				matrix = append(matrix, attribution)
+			}
+
+			done++
+			e.reportProgress("Attributing tests", done, total)
+		}
+	}
+
+	result.TestMatrix = matrix
+	return nil
+}
+
+// attributeTest runs one test of a package from its test binary and returns the functions it covers
+func (e *AnalysisEngine) attributeTest(ctx context.Context, pkg *models.Package, pkgDir, binary, coverDir string, test *testFunction, timeout time.Duration) (*models.TestAttribution, error) {
+	if err := os.MkdirAll(coverDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create coverage directory: %w", err)
+	}
+
+	runCtx, cancel := command.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// Subprocesses the test starts, such as the command run by the main harness, count towards it too
+	run := command.New(runCtx, binary, "-test.run=^"+regexp.QuoteMeta(test.name)+"$", "-test.gocoverdir="+coverDir)
+	run.Dir = pkgDir
+	run.Env = append(os.Environ(), SubprocessCoverDirEnv+"="+coverDir)
+	runErr := run.Run()
+	if runCtx.Err() != nil {
+		return nil, command.Err(runCtx, runErr)
+	}
+
+	profilePath := filepath.Join(coverDir, "profile.out")
+	convert := command.Go(runCtx, "tool", "covdata", "textfmt", "-i="+coverDir, "-o="+profilePath)
+	if output, err := convert.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("go tool covdata %w: %s", command.Err(runCtx, err), output)
+	}
+
+	profile, err := NewProfileParser(false).ParseProfile(profilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only blocks that ran matter, keyed by the project file they are in
+	fileBlocks := make(map[string][]*models.ProfileBlock)
+	for _, block := range profile.Blocks {
+		if block.Count == 0 {
+			continue
+		}
+		if relPath, ok := e.modules.Resolve(block.FileName); ok {
+			fileBlocks[relPath] = append(fileBlocks[relPath], block)
+		}
+	}
+
+	attribution := &models.TestAttribution{
+		Test:      test.name,
+		Package:   pkg.Name,
+		File:      test.file,
+		Line:      test.line,
+		Passed:    runErr == nil,
+		Functions: make([]string, 0),
+	}
+	for _, file := range pkg.Files {
+		blocks := fileBlocks[filepath.ToSlash(file.Path)]
+		if len(blocks) == 0 {
+			continue
+		}
+		tree := newBlockTree(blocks)
+		for _, function := range file.Functions {
+			ran := false
+			tree.within(function.StartLine, function.EndLine, func(*models.ProfileBlock) { ran = true })
+			if ran {
+				attribution.Functions = append(attribution.Functions, function.Key())
+				function.CoveredBy = append(function.CoveredBy, test.name)
+			}
+		}
+	}
+	sort.Strings(attribution.Functions)
+
+	return attribution, nil
+}
@@ -0,0 +1,93 @@
+package coverage
+
+import (
+	"sort"
+
+	"github.com/beck/go-coverage-analyzer/pkg/models"
+)
+
+// blockTree is an interval tree over the profile blocks of one file, so each function looks up its
+// blocks in logarithmic time instead of scanning all blocks of the file, which matters for generated
+// files with thousands of blocks. The blocks are sorted by start line and form an implicit balanced
+// binary tree: the node of blocks[lo:hi] is blocks[(lo+hi)/2], and maxEnd holds the greatest end line
+// within each node's subtree
+type blockTree struct {
+	blocks []*models.ProfileBlock
+	maxEnd []int
+}
+
+// newBlockTree builds the tree over a file's blocks, leaving the given slice in its order
+func newBlockTree(blocks []*models.ProfileBlock) *blockTree {
+	sorted := make([]*models.ProfileBlock, len(blocks))
+	copy(sorted, blocks)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].StartLine != sorted[j].StartLine {
+			return sorted[i].StartLine < sorted[j].StartLine
+		}
+		return sorted[i].StartCol < sorted[j].StartCol
+	})
+
+	t := &blockTree{blocks: sorted, maxEnd: make([]int, len(sorted))}
+	t.build(0, len(sorted))
+	return t
+}
+
+// build fills maxEnd for the subtree of blocks[lo:hi] and returns its greatest end line
+func (t *blockTree) build(lo, hi int) int {
+	if lo >= hi {
+		return 0
+	}
+	mid := (lo + hi) / 2
+	maxEnd := t.blocks[mid].EndLine
+	if end := t.build(lo, mid); end > maxEnd {
+		maxEnd = end
+	}
+	if end := t.build(mid+1, hi); end > maxEnd {
+		maxEnd = end
+	}
+	t.maxEnd[mid] = maxEnd
+	return maxEnd
+}
+
+// within calls visit for each block lying entirely within the lines start to end, in line order
+func (t *blockTree) within(start, end int, visit func(block *models.ProfileBlock)) {
+	first := sort.Search(len(t.blocks), func(i int) bool {
+		return t.blocks[i].StartLine >= start
+	})
+	for _, block := range t.blocks[first:] {
+		if block.StartLine > end {
+			return
+		}
+		if block.EndLine <= end {
+			visit(block)
+		}
+	}
+}
+
+// containing calls visit for each block spanning a line until visit returns false
+func (t *blockTree) containing(line int, visit func(block *models.ProfileBlock) bool) {
+	t.stab(0, len(t.blocks), line, visit)
+}
+
+// stab searches the subtree of blocks[lo:hi] for blocks spanning line, and reports whether to go on
+func (t *blockTree) stab(lo, hi, line int, visit func(block *models.ProfileBlock) bool) bool {
+	if lo >= hi {
+		return true
+	}
+	mid := (lo + hi) / 2
+	if t.maxEnd[mid] < line {
+		return true // every block below ends before the line
+	}
+	if !t.stab(lo, mid, line, visit) {
+		return false
+	}
+
+	block := t.blocks[mid]
+	if block.StartLine > line {
+		return true // this block and those to its right start after the line
+	}
+	if block.EndLine >= line && !visit(block) {
+		return false
+	}
+	return t.stab(mid+1, hi, line, visit)
+}
@@ -0,0 +1,198 @@
+package coverage
+
+import (
+	"go/ast"
+	"go/token"
+
+	"github.com/beck/go-coverage-analyzer/pkg/models"
+)
+
+// mirroredOps flips a comparison so the parameter ends up on the left
+var mirroredOps = map[token.Token]token.Token{
+	token.LSS: token.GTR,
+	token.LEQ: token.GEQ,
+	token.GTR: token.LSS,
+	token.GEQ: token.LEQ,
+	token.EQL: token.EQL,
+	token.NEQ: token.NEQ,
+}
+
+// collectConstants returns the constants declared with a literal value, mapped to that literal
+func collectConstants(decls []ast.Decl) map[string]string {
+	constants := make(map[string]string)
+	for _, decl := range decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.CONST {
+			continue
+		}
+
+		for _, spec := range genDecl.Specs {
+			valueSpec, ok := spec.(*ast.ValueSpec)
+			if !ok || len(valueSpec.Values) != len(valueSpec.Names) {
+				continue
+			}
+			for i, name := range valueSpec.Names {
+				if value, ok := literalValue(valueSpec.Values[i], nil); ok {
+					constants[name.Name] = value
+				}
+			}
+		}
+	}
+	return constants
+}
+
+// extractBoundaries records comparisons of parameters, or their lengths, against literals and constants
+func (e *AnalysisEngine) extractBoundaries(funcDecl *ast.FuncDecl, function *models.Function) {
+	if funcDecl.Body == nil || len(function.Parameters) == 0 {
+		return
+	}
+
+	params := make(map[string]bool)
+	for _, param := range function.Parameters {
+		params[param.Name] = true
+	}
+
+	// Constants declared in the function shadow those of the file
+	constants := make(map[string]string)
+	for name, value := range e.constants {
+		constants[name] = value
+	}
+	ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
+		if declStmt, ok := n.(*ast.DeclStmt); ok {
+			for name, value := range collectConstants([]ast.Decl{declStmt.Decl}) {
+				constants[name] = value
+			}
+		}
+		return true
+	})
+
+	seen := make(map[models.Boundary]*models.Boundary)
+	record := func(boundary *models.Boundary, guardsError bool) {
+		key := *boundary
+		if existing, exists := seen[key]; exists {
+			existing.GuardsError = existing.GuardsError || guardsError
+			return
+		}
+		boundary.GuardsError = guardsError
+		seen[key] = boundary
+		function.Boundaries = append(function.Boundaries, boundary)
+	}
+
+	ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.IfStmt:
+			// A condition that is a single comparison decides whether its body runs
+			if boundary := comparisonBoundary(unparen(node.Cond), params, constants); boundary != nil {
+				record(boundary, function.HasErrorReturn && returnsError(node.Body))
+			}
+		case *ast.BinaryExpr:
+			if boundary := comparisonBoundary(node, params, constants); boundary != nil {
+				record(boundary, false)
+			}
+		case *ast.SwitchStmt:
+			tag, ok := node.Tag.(*ast.Ident)
+			if !ok || !params[tag.Name] {
+				return true
+			}
+			for _, stmt := range node.Body.List {
+				clause, ok := stmt.(*ast.CaseClause)
+				if !ok {
+					continue
+				}
+				for _, expr := range clause.List {
+					if value, ok := literalValue(expr, constants); ok {
+						record(&models.Boundary{Param: tag.Name, Op: token.EQL.String(), Value: value}, function.HasErrorReturn && returnsError(&ast.BlockStmt{List: clause.Body}))
+					}
+				}
+			}
+		}
+		return true
+	})
+}
+
+// comparisonBoundary turns a comparison between a parameter and a constant into a boundary
+func comparisonBoundary(expr ast.Expr, params map[string]bool, constants map[string]string) *models.Boundary {
+	binary, ok := expr.(*ast.BinaryExpr)
+	if !ok {
+		return nil
+	}
+	op, ok := mirroredOps[binary.Op]
+	if !ok {
+		return nil
+	}
+
+	if param, length, ok := comparedParam(binary.X, params); ok {
+		if value, ok := literalValue(binary.Y, constants); ok {
+			return &models.Boundary{Param: param, Length: length, Op: binary.Op.String(), Value: value}
+		}
+	}
+	if param, length, ok := comparedParam(binary.Y, params); ok {
+		if value, ok := literalValue(binary.X, constants); ok {
+			return &models.Boundary{Param: param, Length: length, Op: op.String(), Value: value}
+		}
+	}
+	return nil
+}
+
+// comparedParam reports the parameter an operand refers to, directly or through len()
+func comparedParam(expr ast.Expr, params map[string]bool) (string, bool, bool) {
+	switch operand := unparen(expr).(type) {
+	case *ast.Ident:
+		return operand.Name, false, params[operand.Name]
+	case *ast.CallExpr:
+		fun, ok := operand.Fun.(*ast.Ident)
+		if !ok || fun.Name != "len" || len(operand.Args) != 1 {
+			return "", false, false
+		}
+		if arg, ok := unparen(operand.Args[0]).(*ast.Ident); ok && params[arg.Name] {
+			return arg.Name, true, true
+		}
+	}
+	return "", false, false
+}
+
+// literalValue returns the Go literal an operand stands for, following constants
+func literalValue(expr ast.Expr, constants map[string]string) (string, bool) {
+	switch operand := unparen(expr).(type) {
+	case *ast.BasicLit:
+		if operand.Kind == token.IMAG {
+			return "", false
+		}
+		return operand.Value, true
+	case *ast.UnaryExpr:
+		if operand.Op != token.SUB {
+			return "", false
+		}
+		if lit, ok := unparen(operand.X).(*ast.BasicLit); ok && (lit.Kind == token.INT || lit.Kind == token.FLOAT) {
+			return "-" + lit.Value, true
+		}
+	case *ast.Ident:
+		value, ok := constants[operand.Name]
+		return value, ok
+	}
+	return "", false
+}
+
+// returnsError reports whether a block ends by returning a non-nil error
+func returnsError(body *ast.BlockStmt) bool {
+	if body == nil || len(body.List) == 0 {
+		return false
+	}
+	ret, ok := body.List[len(body.List)-1].(*ast.ReturnStmt)
+	if !ok || len(ret.Results) == 0 {
+		return false
+	}
+	last, ok := ret.Results[len(ret.Results)-1].(*ast.Ident)
+	return !ok || last.Name != "nil"
+}
+
+// unparen strips any parentheses around an expression
+func unparen(expr ast.Expr) ast.Expr {
+	for {
+		paren, ok := expr.(*ast.ParenExpr)
+		if !ok {
+			return expr
+		}
+		expr = paren.X
+	}
+}
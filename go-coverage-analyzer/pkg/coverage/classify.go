@@ -0,0 +1,107 @@
+package coverage
+
+import (
+	"go/ast"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/beck/go-coverage-analyzer/pkg/models"
+)
+
+// ClassificationRules assign files to classes by pattern. Patterns without a slash match the file
+// name (*_helpers.go), patterns with one the path relative to the project (internal/testutil/*.go),
+// and patterns with ... every file below a directory, as package patterns do (third_party/...)
+type ClassificationRules struct {
+	Test      []string
+	Helper    []string
+	Generated []string
+	Vendored  []string
+}
+
+// DefaultClassificationRules returns the rules used when none are configured
+func DefaultClassificationRules() *ClassificationRules {
+	return &ClassificationRules{
+		Helper:    []string{"export_test.go", "*_helpers.go", "*_helper.go", "*_helpers_test.go"},
+		Generated: []string{"*.pb.go", "*_gen.go", "zz_generated*.go"},
+		Vendored:  []string{"vendor/...", "third_party/..."},
+	}
+}
+
+// Classify returns the class of a file from its path relative to the project and its syntax tree;
+// vendored files come first, then generated ones, helpers, tests and production code
+func (r *ClassificationRules) Classify(relPath string, file *ast.File) string {
+	relPath = filepath.ToSlash(relPath)
+	switch {
+	case MatchFilePatterns(r.Vendored, relPath):
+		return models.FileClassVendored
+	case ast.IsGenerated(file) || MatchFilePatterns(r.Generated, relPath):
+		return models.FileClassGenerated
+	case MatchFilePatterns(r.Helper, relPath):
+		return models.FileClassHelper
+	case strings.HasSuffix(relPath, "_test.go") || strings.HasSuffix(file.Name.Name, "_test") || MatchFilePatterns(r.Test, relPath):
+		return models.FileClassTest
+	default:
+		return models.FileClassProduction
+	}
+}
+
+// MatchFilePatterns reports whether a slash-separated file path matches any of the patterns
+func MatchFilePatterns(patterns []string, relPath string) bool {
+	for _, pattern := range patterns {
+		pattern = filepath.ToSlash(pattern)
+		var matched bool
+		switch {
+		case strings.Contains(pattern, "..."):
+			matched = MatchPackagePattern(pattern, path.Dir(relPath))
+		case strings.Contains(pattern, "/"):
+			matched, _ = path.Match(strings.TrimPrefix(pattern, "./"), relPath)
+		default:
+			matched, _ = path.Match(pattern, path.Base(relPath))
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// filterClasses drops the files whose class is not counted, and packages left without files,
+// returning how many files of each class were left out and the functions of the helper and generated
+// files among them, marked untestable
+func filterClasses(packages map[string]*models.Package, classes []string) (map[string]int, []*models.Function) {
+	counted := make(map[string]bool)
+	for _, class := range classes {
+		counted[class] = true
+	}
+
+	excluded := make(map[string]int)
+	var untestable []*models.Function
+	for name, pkg := range packages {
+		for filePath, file := range pkg.Files {
+			if !counted[file.Class] {
+				excluded[file.Class]++
+				untestable = append(untestable, excludedFunctions(file)...)
+				delete(pkg.Files, filePath)
+			}
+		}
+		if len(pkg.Files) == 0 {
+			delete(packages, name)
+		}
+	}
+	return excluded, untestable
+}
+
+// countedClasses returns the file classes coverage is computed over: the given ones, production code
+// when none are, and tests as well when they are included
+func countedClasses(opts *AnalysisOptions) []string {
+	classes := append([]string{}, opts.Classes...)
+	if len(classes) == 0 {
+		classes = append(classes, models.FileClassProduction)
+	}
+	if opts.IncludeTests && !slices.Contains(classes, models.FileClassTest) {
+		classes = append(classes, models.FileClassTest)
+	}
+	return classes
+}
@@ -0,0 +1,92 @@
+package coverage
+
+import (
+	"go/ast"
+	"go/token"
+	"strings"
+
+	"github.com/beck/go-coverage-analyzer/pkg/models"
+)
+
+// collectClocks remembers the clock interfaces of a file, those with a Now() time.Time method,
+// and the struct fields that may hold one
+func (e *AnalysisEngine) collectClocks(file *ast.File) {
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.TYPE {
+			continue
+		}
+
+		for _, spec := range genDecl.Specs {
+			typeSpec, ok := spec.(*ast.TypeSpec)
+			if !ok {
+				continue
+			}
+			key := file.Name.Name + "." + typeSpec.Name.Name
+
+			switch t := typeSpec.Type.(type) {
+			case *ast.InterfaceType:
+				if e.hasNowMethod(t) {
+					e.clocks[key] = true
+				}
+			case *ast.StructType:
+				// Field types are checked against the clocks once every file is parsed
+				for _, field := range t.Fields.List {
+					typeName := e.extractTypeName(field.Type)
+					if !token.IsIdentifier(typeName) {
+						continue
+					}
+					for _, name := range field.Names {
+						e.namedFields[key] = append(e.namedFields[key], &models.Param{Name: name.Name, Type: typeName})
+					}
+				}
+			}
+		}
+	}
+}
+
+// hasNowMethod reports whether an interface declares Now() time.Time
+func (e *AnalysisEngine) hasNowMethod(iface *ast.InterfaceType) bool {
+	for _, method := range iface.Methods.List {
+		funcType, ok := method.Type.(*ast.FuncType)
+		if !ok || len(method.Names) != 1 || method.Names[0].Name != "Now" {
+			continue
+		}
+		if funcType.Params.NumFields() == 0 && funcType.Results.NumFields() == 1 &&
+			e.extractTypeName(funcType.Results.List[0].Type) == "time.Time" {
+			return true
+		}
+	}
+	return false
+}
+
+// applyClocks records the clock interfaces each function can be handed through its parameters or receiver
+func (e *AnalysisEngine) applyClocks(packages map[string]*models.Package) {
+	for _, pkg := range packages {
+		for _, file := range pkg.Files {
+			for _, function := range file.Functions {
+				seen := make(map[string]bool)
+				record := func(typeName string) {
+					if !seen[typeName] && e.clocks[pkg.Name+"."+typeName] {
+						seen[typeName] = true
+						function.Clocks = append(function.Clocks, typeName)
+					}
+				}
+
+				for _, param := range function.Parameters {
+					record(param.Type)
+				}
+				if !function.IsMethod {
+					continue
+				}
+
+				for _, field := range e.namedFields[pkg.Name+"."+strings.TrimPrefix(function.ReceiverType, "*")] {
+					if e.clocks[pkg.Name+"."+field.Type] {
+						record(field.Type)
+						function.ClockFields = append(function.ClockFields, field)
+					}
+				}
+			}
+		}
+	}
+}
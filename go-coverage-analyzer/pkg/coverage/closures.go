@@ -0,0 +1,83 @@
+package coverage
+
+import (
+	"fmt"
+	"go/ast"
+
+	"github.com/beck/go-coverage-analyzer/pkg/models"
+)
+
+// Ways a function literal is started, see models.Function.Launch
+const (
+	LaunchGo    = "go"    // run as a goroutine
+	LaunchDefer = "defer" // deferred until its parent returns
+)
+
+// extractClosures returns the function literals within body as children of parent, numbered in
+// source order the way the Go runtime names them: Parent.func1, Parent.func2, and Parent.func1.1 for
+// a literal within the first one. prefix is the name the numbers are appended to
+func (e *AnalysisEngine) extractClosures(body ast.Node, parent *models.Function, prefix, source string) []*models.Function {
+	if body == nil {
+		return nil
+	}
+
+	// Literals called right away by go or defer statements run as goroutines or deferred calls
+	launches := make(map[*ast.FuncLit]string)
+	var closures []*models.Function
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.GoStmt:
+			if lit, ok := node.Call.Fun.(*ast.FuncLit); ok {
+				launches[lit] = LaunchGo
+			}
+		case *ast.DeferStmt:
+			if lit, ok := node.Call.Fun.(*ast.FuncLit); ok {
+				launches[lit] = LaunchDefer
+			}
+		case *ast.FuncLit:
+			closure := e.extractClosure(node, parent, fmt.Sprintf("%s%d", prefix, len(closures)+1), source)
+			closure.Launch = launches[node]
+			closures = append(closures, closure)
+			return false // literals within it are its own closures
+		}
+		return true
+	})
+	return closures
+}
+
+// extractClosure builds the entry of a function literal, with the literals within it as its closures
+func (e *AnalysisEngine) extractClosure(lit *ast.FuncLit, parent *models.Function, name, source string) *models.Function {
+	position := e.fset.Position(lit.Pos())
+	endPosition := e.fset.Position(lit.End())
+
+	closure := &models.Function{
+		Name:         name,
+		Signature:    e.funcTypeName(lit.Type),
+		File:         parent.File,
+		Package:      parent.Package,
+		StartLine:    position.Line,
+		EndLine:      endPosition.Line,
+		ReceiverType: parent.ReceiverType,
+		Parameters:   make([]*models.Param, 0),
+		ReturnTypes:  make([]string, 0),
+		ParamCount:   lit.Type.Params.NumFields(),
+		Complexity:   e.calculateComplexity(lit),
+		Lines:        countCodeLines([]byte(source[position.Offset:endPosition.Offset])),
+	}
+
+	for _, param := range lit.Type.Params.List {
+		paramType := e.extractTypeName(param.Type)
+		for _, paramName := range param.Names {
+			closure.Parameters = append(closure.Parameters, &models.Param{Name: paramName.Name, Type: paramType})
+		}
+	}
+	if lit.Type.Results != nil {
+		for _, result := range lit.Type.Results.List {
+			closure.ReturnTypes = append(closure.ReturnTypes, e.extractTypeName(result.Type))
+		}
+	}
+
+	// Nested literals are numbered within this one, as Parent.func1.1
+	closure.Closures = e.extractClosures(lit.Body, closure, name+".", source)
+	return closure
+}
@@ -0,0 +1,112 @@
+package coverage
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/beck/go-coverage-analyzer/pkg/models"
+)
+
+// wellKnownInterfaceMethods are invoked implicitly through standard library interfaces,
+// so a lack of direct references does not mean they are unused
+var wellKnownInterfaceMethods = map[string]bool{
+	"String": true, "Error": true, "Unwrap": true, "Is": true, "As": true, "Format": true, "GoString": true,
+	"ServeHTTP": true, "RoundTrip": true,
+	"MarshalJSON": true, "UnmarshalJSON": true, "MarshalText": true, "UnmarshalText": true,
+	"MarshalYAML": true, "UnmarshalYAML": true, "MarshalXML": true, "UnmarshalXML": true,
+	"MarshalBinary": true, "UnmarshalBinary": true, "Scan": true, "Value": true,
+	"Read": true, "Write": true, "Close": true, "Seek": true, "ReadFrom": true, "WriteTo": true,
+	"Len": true, "Less": true, "Swap": true, "Push": true, "Pop": true,
+}
+
+// collectCallReferences counts references to exported functions and methods from non-test code, by
+// the refKey of the declaration they resolve to. Any use of a name counts (calls, method values,
+// interface method declarations), qualified by the package it is imported from, but references from
+// inside a function's own body are ignored so recursion is not a caller. Methods of values whose type
+// the syntax does not tell, interface methods among them, count for every method of that name they may
+// be, since any of them may be called through an interface
+func (e *AnalysisEngine) collectCallReferences(projectPath string, excludeDirs []string, decls *declarations) (map[string]int, error) {
+	refs := make(map[string]int)
+
+	excludeMap := make(map[string]bool)
+	for _, dir := range excludeDirs {
+		excludeMap[dir] = true
+	}
+
+	err := filepath.Walk(projectPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			// Hidden directories hold no package sources
+			if excludeMap[info.Name()] || path != projectPath && strings.HasPrefix(info.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+
+		relPath, _ := filepath.Rel(projectPath, path)
+		_, file, err := e.parseFile(path, 0)
+		if err != nil {
+			e.warn("Skipping %s, it does not parse: %v", relPath, err)
+			return nil
+		}
+
+		pkgPath := decls.importPath(filepath.Dir(relPath))
+		for from, fileRefs := range decls.fileReferences(file, pkgPath, true) {
+			for _, ref := range fileRefs {
+				for _, target := range decls.targets(ref) {
+					if target != from {
+						refs[target]++
+					}
+				}
+			}
+		}
+
+		return nil
+	})
+
+	return refs, err
+}
+
+// applyDeadCodeDetection records caller counts and flags exported functions that have
+// neither callers nor tests as possibly dead
+func (e *AnalysisEngine) applyDeadCodeDetection(result *models.AnalysisResult, refs map[string]int, decls *declarations) {
+	dead := make([]*models.Function, 0)
+
+	for _, pkg := range result.PackageCoverage {
+		for _, file := range pkg.Files {
+			pkgPath := decls.importPath(filepath.Dir(file.Path))
+			for _, function := range file.Functions {
+				function.Callers = refs[decls.functionKey(pkgPath, function)]
+
+				if !function.IsTestable || !function.IsExported || function.HasTests || function.Callers > 0 {
+					continue
+				}
+				if function.IsMethod && wellKnownInterfaceMethods[function.Name] {
+					continue
+				}
+
+				function.PossiblyDead = true
+				dead = append(dead, function)
+			}
+		}
+	}
+
+	sort.Slice(dead, func(i, j int) bool {
+		if dead[i].File != dead[j].File {
+			return dead[i].File < dead[j].File
+		}
+		return dead[i].StartLine < dead[j].StartLine
+	})
+
+	result.PossiblyDeadFunctions = dead
+	result.Summary.PossiblyDeadFunctions = len(dead)
+}
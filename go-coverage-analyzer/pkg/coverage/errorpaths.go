@@ -0,0 +1,156 @@
+package coverage
+
+import (
+	"go/ast"
+	"go/token"
+
+	"github.com/beck/go-coverage-analyzer/pkg/models"
+)
+
+// errorGuard is a condition under which a block of statements runs
+type errorGuard struct {
+	body      ast.Node
+	condition string
+	guard     *models.Boundary
+}
+
+// extractErrorPaths records each return of a non-nil error with the innermost condition leading to it
+func (e *AnalysisEngine) extractErrorPaths(funcDecl *ast.FuncDecl, function *models.Function, source string) {
+	if funcDecl.Body == nil || !function.HasErrorReturn {
+		return
+	}
+
+	params := make(map[string]bool)
+	for _, param := range function.Parameters {
+		params[param.Name] = true
+	}
+
+	guards := e.collectGuards(funcDecl.Body, params, source)
+
+	var returns []*ast.ReturnStmt
+	ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			// Returns of closures are not returns of the function
+			return false
+		case *ast.ReturnStmt:
+			if returnsError(&ast.BlockStmt{List: []ast.Stmt{node}}) {
+				returns = append(returns, node)
+			}
+		}
+		return true
+	})
+
+	for _, ret := range returns {
+		path := &models.ErrorPath{Line: e.fset.Position(ret.Pos()).Line}
+		if innermost := innermostGuard(guards, ret); innermost != nil {
+			path.Guard = innermost.guard
+			path.Condition = innermost.condition
+		}
+		function.ErrorPaths = append(function.ErrorPaths, path)
+	}
+}
+
+// collectGuards returns the if conditions and switch cases of a function body, leaving out those of closures
+func (e *AnalysisEngine) collectGuards(body *ast.BlockStmt, params map[string]bool, source string) []*errorGuard {
+	var guards []*errorGuard
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.IfStmt:
+			guards = append(guards, &errorGuard{
+				body:      node.Body,
+				condition: sourceText(e.fset, source, node.Cond),
+				guard:     e.conditionGuard(node.Cond, params),
+			})
+		case *ast.SwitchStmt:
+			for _, stmt := range node.Body.List {
+				clause, ok := stmt.(*ast.CaseClause)
+				if !ok || len(clause.List) == 0 {
+					continue
+				}
+
+				// A tagless switch is a chain of if conditions
+				guard := &errorGuard{body: clause}
+				if node.Tag == nil {
+					guard.condition = sourceText(e.fset, source, clause.List[0])
+					if len(clause.List) == 1 {
+						guard.guard = e.conditionGuard(clause.List[0], params)
+					}
+					guards = append(guards, guard)
+					continue
+				}
+
+				guard.condition = sourceText(e.fset, source, node.Tag) + " == " + sourceText(e.fset, source, clause.List[0])
+				if tag, ok := node.Tag.(*ast.Ident); ok && params[tag.Name] && len(clause.List) == 1 {
+					if value, ok := literalValue(clause.List[0], e.constants); ok {
+						guard.guard = &models.Boundary{Param: tag.Name, Op: token.EQL.String(), Value: value}
+					}
+				}
+				guards = append(guards, guard)
+			}
+		}
+		return true
+	})
+	return guards
+}
+
+// innermostGuard returns the guard holding a node that decides whether it runs, nil when it always runs
+func innermostGuard(guards []*errorGuard, node ast.Node) *errorGuard {
+	var innermost *errorGuard
+	for _, guard := range guards {
+		if guard.body.Pos() <= node.Pos() && node.End() <= guard.body.End() {
+			if innermost == nil || guard.body.Pos() > innermost.body.Pos() {
+				innermost = guard
+			}
+		}
+	}
+	return innermost
+}
+
+// conditionGuard turns an if condition into a constraint on one parameter, or nil when it depends on more
+func (e *AnalysisEngine) conditionGuard(cond ast.Expr, params map[string]bool) *models.Boundary {
+	cond = unparen(cond)
+
+	if boundary := comparisonBoundary(cond, params, e.constants); boundary != nil {
+		return boundary
+	}
+
+	switch expr := cond.(type) {
+	case *ast.BinaryExpr:
+		// Nil checks like if cfg == nil
+		if expr.Op != token.EQL && expr.Op != token.NEQ {
+			return nil
+		}
+		param, _, ok := comparedParam(expr.X, params)
+		other := expr.Y
+		if !ok {
+			param, _, ok = comparedParam(expr.Y, params)
+			other = expr.X
+		}
+		if nilIdent, isIdent := unparen(other).(*ast.Ident); ok && isIdent && nilIdent.Name == "nil" {
+			return &models.Boundary{Param: param, Op: expr.Op.String(), Value: "nil"}
+		}
+	case *ast.Ident:
+		// Boolean parameters like if strict
+		if params[expr.Name] {
+			return &models.Boundary{Param: expr.Name, Op: token.EQL.String(), Value: "true"}
+		}
+	case *ast.UnaryExpr:
+		if ident, ok := unparen(expr.X).(*ast.Ident); ok && expr.Op == token.NOT && params[ident.Name] {
+			return &models.Boundary{Param: ident.Name, Op: token.EQL.String(), Value: "false"}
+		}
+	}
+	return nil
+}
+
+// sourceText returns the source of a node
+func sourceText(fset *token.FileSet, source string, node ast.Node) string {
+	start := fset.Position(node.Pos()).Offset
+	end := fset.Position(node.End()).Offset
+	if start < 0 || end > len(source) || start > end {
+		return ""
+	}
+	return source[start:end]
+}
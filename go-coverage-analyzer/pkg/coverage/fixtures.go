@@ -0,0 +1,93 @@
+package coverage
+
+import (
+	"go/ast"
+	"go/token"
+	"sort"
+	"strconv"
+
+	"github.com/beck/go-coverage-analyzer/pkg/models"
+)
+
+// fixtureCalls maps standard library calls to the shared fixture a test of the caller needs
+var fixtureCalls = map[string]string{
+	"os.Getenv":    models.FixtureEnv,
+	"os.LookupEnv": models.FixtureEnv,
+
+	"os.Open":          models.FixtureTempDir,
+	"os.OpenFile":      models.FixtureTempDir,
+	"os.Create":        models.FixtureTempDir,
+	"os.ReadFile":      models.FixtureTempDir,
+	"os.WriteFile":     models.FixtureTempDir,
+	"os.ReadDir":       models.FixtureTempDir,
+	"os.Mkdir":         models.FixtureTempDir,
+	"os.MkdirAll":      models.FixtureTempDir,
+	"os.Remove":        models.FixtureTempDir,
+	"os.RemoveAll":     models.FixtureTempDir,
+	"os.Stat":          models.FixtureTempDir,
+	"ioutil.ReadFile":  models.FixtureTempDir,
+	"ioutil.WriteFile": models.FixtureTempDir,
+	"ioutil.ReadDir":   models.FixtureTempDir,
+
+	"http.Get":                   models.FixtureServer,
+	"http.Head":                  models.FixtureServer,
+	"http.Post":                  models.FixtureServer,
+	"http.PostForm":              models.FixtureServer,
+	"http.NewRequest":            models.FixtureServer,
+	"http.NewRequestWithContext": models.FixtureServer,
+}
+
+// extractFixtures records the shared fixtures and environment variables tests of a function need
+func (e *AnalysisEngine) extractFixtures(funcDecl *ast.FuncDecl, function *models.Function) {
+	fixtures := make(map[string]bool)
+	envVars := make(map[string]bool)
+
+	for _, param := range function.Parameters {
+		if param.Type == "*http.Client" {
+			fixtures[models.FixtureServer] = true
+		}
+	}
+
+	if funcDecl.Body != nil {
+		ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			selector, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+			pkg, ok := selector.X.(*ast.Ident)
+			if !ok {
+				return true
+			}
+
+			fixture, exists := fixtureCalls[pkg.Name+"."+selector.Sel.Name]
+			if !exists {
+				return true
+			}
+			fixtures[fixture] = true
+
+			// Variables named by a literal can be set up front
+			if fixture == models.FixtureEnv && len(call.Args) > 0 {
+				if lit, ok := call.Args[0].(*ast.BasicLit); ok && lit.Kind == token.STRING {
+					if name, err := strconv.Unquote(lit.Value); err == nil {
+						envVars[name] = true
+					}
+				}
+			}
+			return true
+		})
+	}
+
+	for fixture := range fixtures {
+		function.Fixtures = append(function.Fixtures, fixture)
+	}
+	sort.Strings(function.Fixtures)
+
+	for name := range envVars {
+		function.EnvVars = append(function.EnvVars, name)
+	}
+	sort.Strings(function.EnvVars)
+}
@@ -0,0 +1,107 @@
+package coverage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/beck/go-coverage-analyzer/internal/command"
+	"github.com/beck/go-coverage-analyzer/pkg/models"
+)
+
+// staleProfile returns the Go files of the analyzed packages, tests included, modified after the
+// coverage profile was written, or nil when the profile is newer than all of them. Its numbers describe
+// the code as it was then, so functions changed since may be reported covered or uncovered wrongly.
+// In a git repository a file also has to differ from HEAD or be changed by a commit made since, as a
+// fresh checkout gives every file a modification time newer than the profile
+func staleProfile(ctx context.Context, projectPath, profilePath string, result *models.AnalysisResult) *models.StaleProfile {
+	info, err := os.Stat(profilePath)
+	if err != nil {
+		return nil
+	}
+	written := info.ModTime()
+	changed, inGit := gitChangedFiles(ctx, projectPath, written)
+
+	// Packages are keyed by name, so the files of one may be in several directories
+	dirs := make(map[string]bool)
+	for _, pkg := range result.PackageCoverage {
+		for _, file := range pkg.Files {
+			dirs[filepath.Dir(file.Path)] = true
+		}
+	}
+
+	type modifiedFile struct {
+		path string
+		info os.FileInfo
+	}
+	modified := make([]modifiedFile, 0)
+	for dir := range dirs {
+		entries, err := os.ReadDir(filepath.Join(projectPath, dir))
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".go") {
+				continue
+			}
+			fileInfo, err := entry.Info()
+			if err != nil || !fileInfo.ModTime().After(written) {
+				continue
+			}
+			path := filepath.ToSlash(filepath.Join(dir, entry.Name()))
+			if inGit && !changed[path] {
+				continue
+			}
+			modified = append(modified, modifiedFile{path, fileInfo})
+		}
+	}
+	if len(modified) == 0 {
+		return nil
+	}
+
+	// Most recently modified first, the files furthest from what the profile measured
+	sort.Slice(modified, func(i, j int) bool {
+		a, b := modified[i].info.ModTime(), modified[j].info.ModTime()
+		if !a.Equal(b) {
+			return a.After(b)
+		}
+		return modified[i].path < modified[j].path
+	})
+
+	stale := &models.StaleProfile{
+		ProfileModified: written,
+		ModifiedFiles:   make([]string, 0, len(modified)),
+	}
+	for _, file := range modified {
+		stale.ModifiedFiles = append(stale.ModifiedFiles, file.path)
+	}
+	return stale
+}
+
+// gitChangedFiles returns the Go files of a project in a git repository that differ from HEAD, are
+// untracked, or were changed by commits made since a time, by slash-separated path relative to the
+// project. It reports false when the project is in no repository or git cannot tell
+func gitChangedFiles(ctx context.Context, projectPath string, since time.Time) (map[string]bool, bool) {
+	changed := make(map[string]bool)
+	for _, args := range [][]string{
+		{"diff", "--name-only", "--relative", "HEAD"},
+		{"ls-files", "--others", "--exclude-standard"},
+		{"log", "--name-only", "--relative", "--format=", "--since=" + since.Format(time.RFC3339), "HEAD"},
+	} {
+		cmd := command.New(ctx, "git", append(args, "--", "*.go")...)
+		cmd.Dir = projectPath
+		output, err := cmd.Output()
+		if err != nil {
+			return nil, false
+		}
+		for _, path := range strings.Split(string(output), "\n") {
+			if path = strings.TrimSpace(path); path != "" {
+				changed[path] = true
+			}
+		}
+	}
+	return changed, true
+}
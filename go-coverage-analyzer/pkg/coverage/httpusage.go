@@ -0,0 +1,136 @@
+package coverage
+
+import (
+	"go/ast"
+	"go/token"
+	"strings"
+
+	"github.com/beck/go-coverage-analyzer/pkg/models"
+)
+
+// httpClientMethods are the *http.Client methods that send a request
+var httpClientMethods = map[string]bool{
+	"Do":       true,
+	"Get":      true,
+	"Head":     true,
+	"Post":     true,
+	"PostForm": true,
+}
+
+// isHTTPClientType reports whether a type is an *http.Client
+func isHTTPClientType(typeName string) bool {
+	return typeName == "*http.Client"
+}
+
+// extractHTTPUsage records the *http.Client a function sends requests with
+func (e *AnalysisEngine) extractHTTPUsage(funcDecl *ast.FuncDecl, function *models.Function) {
+	if funcDecl.Body == nil {
+		return
+	}
+
+	for _, param := range function.Parameters {
+		// A transport parameter is as good as a client
+		if (isHTTPClientType(param.Type) || param.Type == "http.RoundTripper") && param.Name != "_" {
+			function.HTTP = &models.HTTPUsage{Param: param.Name}
+			return
+		}
+	}
+
+	receiver := ""
+	if funcDecl.Recv != nil && len(funcDecl.Recv.List) > 0 && len(funcDecl.Recv.List[0].Names) > 0 {
+		receiver = funcDecl.Recv.List[0].Names[0].Name
+	}
+
+	ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
+		if function.HTTP != nil {
+			return false
+		}
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		selector, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || !httpClientMethods[selector.Sel.Name] {
+			return true
+		}
+
+		switch x := selector.X.(type) {
+		case *ast.Ident:
+			// http.Get and friends go through http.DefaultClient
+			if x.Name == "http" {
+				function.HTTP = &models.HTTPUsage{Default: true}
+			}
+		case *ast.SelectorExpr:
+			ident, ok := x.X.(*ast.Ident)
+			if !ok {
+				return true
+			}
+			if ident.Name == "http" && x.Sel.Name == "DefaultClient" {
+				function.HTTP = &models.HTTPUsage{Default: true}
+			} else if receiver != "" && ident.Name == receiver {
+				// Confirmed against the receiver's fields once all structs are known
+				e.httpCandidates[function] = append(e.httpCandidates[function], x.Sel.Name)
+			}
+		}
+		return true
+	})
+}
+
+// collectDependencyFields remembers struct fields of a file that hold an *http.Client or a function
+func (e *AnalysisEngine) collectDependencyFields(file *ast.File) {
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.TYPE {
+			continue
+		}
+
+		for _, spec := range genDecl.Specs {
+			typeSpec, ok := spec.(*ast.TypeSpec)
+			if !ok {
+				continue
+			}
+			structType, ok := typeSpec.Type.(*ast.StructType)
+			if !ok {
+				continue
+			}
+
+			key := file.Name.Name + "." + typeSpec.Name.Name
+			for _, field := range structType.Fields.List {
+				typeName := e.extractTypeName(field.Type)
+				for _, name := range field.Names {
+					switch {
+					case isHTTPClientType(typeName):
+						if e.httpFields[key] == nil {
+							e.httpFields[key] = make(map[string]bool)
+						}
+						e.httpFields[key][name.Name] = true
+					case strings.HasPrefix(typeName, "func("):
+						e.funcFields[key] = append(e.funcFields[key], &models.Param{Name: name.Name, Type: typeName})
+					}
+				}
+			}
+		}
+	}
+}
+
+// applyDependencyFields links methods to the HTTP client and function fields of their receiver
+func (e *AnalysisEngine) applyDependencyFields(packages map[string]*models.Package) {
+	for _, pkg := range packages {
+		for _, file := range pkg.Files {
+			for _, function := range file.Functions {
+				if !function.IsMethod {
+					continue
+				}
+				key := pkg.Name + "." + strings.TrimPrefix(function.ReceiverType, "*")
+
+				for _, field := range e.httpCandidates[function] {
+					if function.HTTP == nil && e.httpFields[key][field] {
+						function.HTTP = &models.HTTPUsage{Field: field}
+						function.UsesNetwork = true
+					}
+				}
+				function.FuncFields = e.funcFields[key]
+			}
+		}
+	}
+}
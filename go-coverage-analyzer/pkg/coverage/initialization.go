@@ -0,0 +1,119 @@
+package coverage
+
+import (
+	"go/ast"
+	"go/token"
+	"sort"
+	"strings"
+
+	"github.com/beck/go-coverage-analyzer/pkg/models"
+)
+
+// extractInitializers records the package-level variables of a file initialized with function literals,
+// such as var handlers = map[string]func(){...} or var x = func() int {...}(). Only function literals
+// hold statements the coverage profile counts, other initializers run code counted where it is declared
+func (e *AnalysisEngine) extractInitializers(file *ast.File, fileModel *models.File) {
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.VAR {
+			continue
+		}
+
+		for _, spec := range genDecl.Specs {
+			valueSpec, ok := spec.(*ast.ValueSpec)
+			if !ok || !hasFuncLit(valueSpec.Values) {
+				continue
+			}
+
+			names := make([]string, len(valueSpec.Names))
+			for i, name := range valueSpec.Names {
+				names[i] = name.Name
+			}
+			fileModel.Initializers = append(fileModel.Initializers, &models.Initializer{
+				Kind:      models.InitializerVar,
+				Name:      strings.Join(names, ", "),
+				Package:   fileModel.Package,
+				File:      fileModel.Path,
+				StartLine: e.fset.Position(valueSpec.Pos()).Line,
+				EndLine:   e.fset.Position(valueSpec.End()).Line,
+			})
+		}
+	}
+}
+
+// hasFuncLit reports whether any of the expressions holds a function literal
+func hasFuncLit(exprs []ast.Expr) bool {
+	found := false
+	for _, expr := range exprs {
+		ast.Inspect(expr, func(n ast.Node) bool {
+			if _, ok := n.(*ast.FuncLit); ok {
+				found = true
+			}
+			return !found
+		})
+	}
+	return found
+}
+
+// calculateInitializerCoverage determines the statement counts and coverage of the variable
+// initializers of a file
+func (e *AnalysisEngine) calculateInitializerCoverage(initializers []*models.Initializer, blocks *blockTree) {
+	for _, initializer := range initializers {
+		initializer.Statements, initializer.CoveredStatements = 0, 0
+		blocks.within(initializer.StartLine, initializer.EndLine, func(block *models.ProfileBlock) {
+			initializer.Statements += block.NumStmts
+			if block.Count > 0 {
+				initializer.CoveredStatements += block.NumStmts
+			}
+		})
+		if initializer.Statements > 0 {
+			initializer.Coverage = float64(initializer.CoveredStatements) / float64(initializer.Statements) * 100.0
+		}
+	}
+}
+
+// applyInitializationCoverage gathers the init functions and variable initializers of the project
+// into the initialization category, by file and line
+func (e *AnalysisEngine) applyInitializationCoverage(result *models.AnalysisResult) {
+	initialization := &models.InitializationCoverage{Initializers: make([]*models.Initializer, 0)}
+
+	for _, pkg := range result.PackageCoverage {
+		for _, file := range pkg.Files {
+			for _, function := range file.Functions {
+				if function.Name != "init" || function.IsMethod {
+					continue
+				}
+				initialization.Initializers = append(initialization.Initializers, &models.Initializer{
+					Kind:              models.InitializerInit,
+					Name:              function.Name,
+					Package:           function.Package,
+					File:              function.File,
+					StartLine:         function.StartLine,
+					EndLine:           function.EndLine,
+					Statements:        function.Statements,
+					CoveredStatements: function.CoveredStatements,
+					Coverage:          function.Coverage,
+				})
+			}
+			initialization.Initializers = append(initialization.Initializers, file.Initializers...)
+		}
+	}
+
+	for _, initializer := range initialization.Initializers {
+		initialization.Statements += initializer.Statements
+		initialization.CoveredStatements += initializer.CoveredStatements
+	}
+	if initialization.Statements > 0 {
+		initialization.Coverage = float64(initialization.CoveredStatements) / float64(initialization.Statements) * 100.0
+	}
+
+	sort.Slice(initialization.Initializers, func(i, j int) bool {
+		a, b := initialization.Initializers[i], initialization.Initializers[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.StartLine < b.StartLine
+	})
+
+	result.Initialization = initialization
+}
@@ -0,0 +1,54 @@
+package coverage
+
+import (
+	"go/ast"
+	"go/token"
+	"strings"
+
+	"github.com/beck/go-coverage-analyzer/pkg/models"
+)
+
+// collectInterfaces remembers the interface types declared in a file
+func (e *AnalysisEngine) collectInterfaces(file *ast.File) {
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.TYPE {
+			continue
+		}
+
+		for _, spec := range genDecl.Specs {
+			typeSpec, ok := spec.(*ast.TypeSpec)
+			if !ok {
+				continue
+			}
+			if _, ok := typeSpec.Type.(*ast.InterfaceType); ok {
+				e.interfaces[file.Name.Name+"."+typeSpec.Name.Name] = true
+			}
+		}
+	}
+}
+
+// applyInterfaces records the interface types each function takes or returns, once all packages are parsed
+func (e *AnalysisEngine) applyInterfaces(packages map[string]*models.Package) {
+	for _, pkg := range packages {
+		for _, file := range pkg.Files {
+			for _, function := range file.Functions {
+				seen := make(map[string]bool)
+				record := func(typeName string) {
+					typeName = strings.TrimPrefix(typeName, "...")
+					if !seen[typeName] && e.interfaces[pkg.Name+"."+typeName] {
+						seen[typeName] = true
+						function.Interfaces = append(function.Interfaces, typeName)
+					}
+				}
+
+				for _, param := range function.Parameters {
+					record(param.Type)
+				}
+				for _, returnType := range function.ReturnTypes {
+					record(returnType)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package coverage
+
+import (
+	"go/ast"
+	"go/token"
+	"sort"
+	"strings"
+
+	"github.com/beck/go-coverage-analyzer/pkg/models"
+)
+
+// interfaceMethod is a method an interface requires, matched against methods by name and arity
+type interfaceMethod struct {
+	name    string
+	params  int
+	results int
+}
+
+// interfaceDecl is an exported interface declared in the project
+type interfaceDecl struct {
+	pkg     string
+	name    string
+	methods []interfaceMethod
+	embeds  []string // interfaces of the same package it embeds
+}
+
+// collectInterfaceMethods remembers the method sets of the exported interfaces declared in a file
+func (e *AnalysisEngine) collectInterfaceMethods(file *ast.File) {
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.TYPE {
+			continue
+		}
+
+		for _, spec := range genDecl.Specs {
+			typeSpec, ok := spec.(*ast.TypeSpec)
+			if !ok || !typeSpec.Name.IsExported() {
+				continue
+			}
+			iface, ok := typeSpec.Type.(*ast.InterfaceType)
+			if !ok || iface.Methods == nil {
+				continue
+			}
+
+			decl := &interfaceDecl{pkg: file.Name.Name, name: typeSpec.Name.Name}
+			for _, field := range iface.Methods.List {
+				switch t := field.Type.(type) {
+				case *ast.FuncType:
+					for _, name := range field.Names {
+						decl.methods = append(decl.methods, interfaceMethod{
+							name:    name.Name,
+							params:  t.Params.NumFields(),
+							results: t.Results.NumFields(),
+						})
+					}
+				case *ast.Ident:
+					decl.embeds = append(decl.embeds, t.Name)
+				}
+			}
+			e.interfaceDecls[decl.pkg+"."+decl.name] = decl
+		}
+	}
+}
+
+// methodSet returns the methods an interface requires, those of the interfaces it embeds included
+func (e *AnalysisEngine) methodSet(decl *interfaceDecl, seen map[string]bool) []interfaceMethod {
+	methods := append([]interfaceMethod(nil), decl.methods...)
+	for _, embed := range decl.embeds {
+		key := decl.pkg + "." + embed
+		if embedded := e.interfaceDecls[key]; embedded != nil && !seen[key] {
+			seen[key] = true
+			methods = append(methods, e.methodSet(embedded, seen)...)
+		}
+	}
+	return methods
+}
+
+// applyTypeCoverage groups the methods of each package by receiver type, with the coverage of the
+// type's testable methods and the exported interfaces of the project it implements
+func (e *AnalysisEngine) applyTypeCoverage(result *models.AnalysisResult) {
+	type receiver struct{ pkg, name string }
+	types := make(map[receiver]*models.TypeCoverage)
+	methods := make(map[receiver]map[string]*models.Function)
+
+	for _, pkg := range result.PackageCoverage {
+		for _, file := range pkg.Files {
+			for _, function := range file.Functions {
+				if !function.IsMethod || function.ReceiverType == "" {
+					continue
+				}
+				key := receiver{pkg: pkg.Name, name: receiverTypeName(function.ReceiverType)}
+				if types[key] == nil {
+					types[key] = &models.TypeCoverage{Package: pkg.Name, Type: key.name}
+					methods[key] = make(map[string]*models.Function)
+				}
+				methods[key][function.Name] = function
+
+				if !function.IsTestable {
+					continue
+				}
+				typeCoverage := types[key]
+				typeCoverage.TotalMethods++
+				typeCoverage.Statements += function.Statements
+				typeCoverage.CoveredStatements += function.CoveredStatements
+				if function.IsCovered {
+					typeCoverage.CoveredMethods++
+				} else {
+					typeCoverage.UncoveredMethods = append(typeCoverage.UncoveredMethods, function.Name)
+				}
+			}
+		}
+	}
+
+	// Method sets of the interfaces, resolved once
+	interfaceNames := make([]string, 0, len(e.interfaceDecls))
+	for key := range e.interfaceDecls {
+		interfaceNames = append(interfaceNames, key)
+	}
+	sort.Strings(interfaceNames)
+	methodSets := make(map[string][]interfaceMethod, len(interfaceNames))
+	for _, key := range interfaceNames {
+		methodSets[key] = e.methodSet(e.interfaceDecls[key], map[string]bool{key: true})
+	}
+
+	result.TypeCoverage = make([]*models.TypeCoverage, 0, len(types))
+	for key, typeCoverage := range types {
+		if typeCoverage.TotalMethods > 0 {
+			typeCoverage.MethodCoverage = float64(typeCoverage.CoveredMethods) / float64(typeCoverage.TotalMethods) * 100.0
+		}
+		if typeCoverage.Statements > 0 {
+			typeCoverage.Coverage = float64(typeCoverage.CoveredStatements) / float64(typeCoverage.Statements) * 100.0
+		}
+		sort.Strings(typeCoverage.UncoveredMethods)
+
+		for _, name := range interfaceNames {
+			decl := e.interfaceDecls[name]
+			implemented, untested := implementsInterface(methods[key], methodSets[name])
+			if !implemented {
+				continue
+			}
+
+			// Interfaces of the type's own package go by their bare name
+			if decl.pkg == key.pkg {
+				name = decl.name
+			}
+			typeCoverage.Implements = append(typeCoverage.Implements, name)
+			if untested {
+				typeCoverage.UntestedInterfaces = append(typeCoverage.UntestedInterfaces, name)
+			}
+		}
+
+		result.TypeCoverage = append(result.TypeCoverage, typeCoverage)
+	}
+
+	sort.Slice(result.TypeCoverage, func(i, j int) bool {
+		if result.TypeCoverage[i].Package != result.TypeCoverage[j].Package {
+			return result.TypeCoverage[i].Package < result.TypeCoverage[j].Package
+		}
+		return result.TypeCoverage[i].Type < result.TypeCoverage[j].Type
+	})
+}
+
+// implementsInterface reports whether a type's methods provide every method of an interface, by name
+// and arity, and whether any of those implementing methods is uncovered
+func implementsInterface(methods map[string]*models.Function, required []interfaceMethod) (bool, bool) {
+	if len(required) == 0 {
+		return false, false // every type implements an empty interface
+	}
+
+	untested := false
+	for _, method := range required {
+		function := methods[method.name]
+		if function == nil || function.ParamCount != method.params || len(function.ReturnTypes) != method.results {
+			return false, false
+		}
+		if !function.IsCovered {
+			untested = true
+		}
+	}
+	return true, untested
+}
+
+// receiverTypeName returns the type of a receiver without pointer and type parameters
+func receiverTypeName(receiverType string) string {
+	name := strings.TrimPrefix(receiverType, "*")
+	if i := strings.Index(name, "["); i >= 0 {
+		name = name[:i]
+	}
+	return name
+}
@@ -0,0 +1,341 @@
+package coverage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/beck/go-coverage-analyzer/internal/command"
+)
+
+// listedModule is the part of go list -m -json output the module map needs
+type listedModule struct {
+	Path    string
+	Dir     string
+	Replace *listedModule
+}
+
+// moduleDir is a module and the directory its files are in, relative to the project
+type moduleDir struct {
+	path string
+	dir  string
+}
+
+// ModuleMap resolves the import-path-qualified file names of coverage profiles, such as
+// example.com/app/internal/db/db.go, to files in the project: by the directory go list reports for
+// the package, or else by the directory of the module, including modules that replace directives
+// point inside the repository. Names that are not import paths, such as relative or vendored ones,
+// resolve to the project file they name when it exists
+type ModuleMap struct {
+	root     string            // absolute project directory
+	packages map[string]string // import path to package directory, relative to the project
+	modules  []moduleDir       // longest module path first, so nested modules win over their parents
+
+	mu    sync.Mutex
+	files map[string]string // profile file names resolved by looking for the file, "" when there is none
+}
+
+// NewModuleMap maps a single module to the project directory, for projects go list cannot load
+func NewModuleMap(projectPath, modulePath string) *ModuleMap {
+	root, _ := filepath.Abs(projectPath)
+	m := &ModuleMap{root: root, packages: make(map[string]string)}
+	if modulePath != "" {
+		m.modules = append(m.modules, moduleDir{path: modulePath, dir: "."})
+	}
+	return m
+}
+
+// LoadModules maps the modules of a project to their directories with go list -m -json all, falling
+// back to the main and workspace modules alone when the full module graph cannot be loaded, adds the
+// nested modules found below the project root, and maps the packages of the modules in the project to
+// their directories with go list -e ./...
+func LoadModules(ctx context.Context, projectPath string) (*ModuleMap, error) {
+	root, err := filepath.Abs(projectPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get absolute path: %w", err)
+	}
+
+	// Without a module at the root, or without the go command, only go.mod files found below it are mapped
+	output, err := goListModules(ctx, root, "all")
+	if err != nil {
+		output, err = goListModules(ctx, root)
+	}
+	var modules []*listedModule
+	if err == nil {
+		if modules, err = parseListedModules(output); err != nil {
+			return nil, err
+		}
+	}
+
+	m := &ModuleMap{root: root, packages: make(map[string]string)}
+	for _, module := range modules {
+		dir := module.Dir
+		if module.Replace != nil && module.Replace.Dir != "" {
+			dir = module.Replace.Dir
+		}
+		if dir == "" {
+			continue
+		}
+
+		relDir, err := filepath.Rel(root, dir)
+		if err != nil || relDir == ".." || strings.HasPrefix(relDir, ".."+string(filepath.Separator)) {
+			// Modules outside the project have no files to attribute coverage to
+			continue
+		}
+		m.addModule(ctx, module.Path, dir)
+	}
+
+	// Nested modules nothing requires are not part of the module graph
+	if err := m.addNestedModules(ctx); err != nil {
+		return nil, err
+	}
+
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	sort.SliceStable(m.modules, func(i, j int) bool {
+		return len(m.modules[i].path) > len(m.modules[j].path)
+	})
+	return m, nil
+}
+
+// goListModules runs go list -m -json with the given arguments in dir
+func goListModules(ctx context.Context, dir string, args ...string) ([]byte, error) {
+	cmd := command.Go(ctx, append([]string{"list", "-m", "-json"}, args...)...)
+	cmd.Dir = dir
+
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("go list -m failed: %w", err)
+	}
+	return output, nil
+}
+
+// addModule records a module in the project and the directories of its packages; packages go list
+// cannot report keep resolving through the module directory
+func (m *ModuleMap) addModule(ctx context.Context, modulePath, dir string) {
+	relDir, err := filepath.Rel(m.root, dir)
+	if err != nil {
+		return
+	}
+	m.modules = append(m.modules, moduleDir{path: modulePath, dir: filepath.ToSlash(relDir)})
+
+	cmd := command.Go(ctx, "list", "-e", "-f", "{{.ImportPath}}\t{{.Dir}}", "./...")
+	cmd.Dir = dir
+
+	output, err := cmd.Output()
+	if err != nil {
+		return
+	}
+
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		importPath, pkgDir, ok := strings.Cut(line, "\t")
+		if !ok || pkgDir == "" {
+			continue
+		}
+		if relDir, err := filepath.Rel(m.root, pkgDir); err == nil {
+			m.packages[importPath] = filepath.ToSlash(relDir)
+		}
+	}
+}
+
+// addNestedModules records the modules below the project root that are not mapped yet, skipping
+// vendor, testdata and hidden directories as the go command does
+func (m *ModuleMap) addNestedModules(ctx context.Context) error {
+	mapped := make(map[string]bool)
+	for _, module := range m.modules {
+		mapped[module.dir] = true
+	}
+
+	return filepath.WalkDir(m.root, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if entry.IsDir() {
+			name := entry.Name()
+			if filePath != m.root && (name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if entry.Name() != "go.mod" {
+			return nil
+		}
+
+		dir := filepath.Dir(filePath)
+		relDir, err := filepath.Rel(m.root, dir)
+		if err != nil || mapped[filepath.ToSlash(relDir)] {
+			return nil
+		}
+		if modulePath := goModModulePath(filePath); modulePath != "" {
+			m.addModule(ctx, modulePath, dir)
+		}
+		return nil
+	})
+}
+
+// goModModulePath reads the module path declared by a go.mod file, or "" when it has none
+func goModModulePath(goModFile string) string {
+	content, err := os.ReadFile(goModFile)
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(content), "\n") {
+		if rest, ok := strings.CutPrefix(strings.TrimSpace(line), "module "); ok {
+			return strings.Trim(strings.TrimSpace(rest), `"`)
+		}
+	}
+	return ""
+}
+
+// ImportPath returns the import path of the package in dir, derived from the module path of the
+// nearest go.mod at or above it, or false when dir is in no module
+func ImportPath(dir string) (string, bool) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", false
+	}
+
+	for moduleDir := absDir; ; moduleDir = filepath.Dir(moduleDir) {
+		if modulePath := goModModulePath(filepath.Join(moduleDir, "go.mod")); modulePath != "" {
+			relDir, err := filepath.Rel(moduleDir, absDir)
+			if err != nil {
+				return "", false
+			}
+			return path.Join(modulePath, filepath.ToSlash(relDir)), true
+		}
+		if filepath.Dir(moduleDir) == moduleDir {
+			return "", false
+		}
+	}
+}
+
+// parseListedModules decodes the stream of JSON objects go list -m -json prints
+func parseListedModules(output []byte) ([]*listedModule, error) {
+	decoder := json.NewDecoder(strings.NewReader(string(output)))
+
+	var modules []*listedModule
+	for {
+		var module listedModule
+		if err := decoder.Decode(&module); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to parse go list output: %w", err)
+		}
+		modules = append(modules, &module)
+	}
+	return modules, nil
+}
+
+// Resolve returns the slash-separated path relative to the project of a profile file name, or false
+// when it is not in the project. Files outside any module are named by their absolute directory,
+// prefixed with _ in GOPATH mode
+func (m *ModuleMap) Resolve(fileName string) (string, bool) {
+	if m == nil {
+		return "", false
+	}
+
+	fileName = filepath.ToSlash(fileName)
+	dir, name := path.Split(fileName)
+	dir = strings.TrimSuffix(dir, "/")
+
+	if pkgDir, ok := m.packages[dir]; ok {
+		return path.Join(pkgDir, name), true
+	}
+
+	for _, module := range m.modules {
+		if rest, ok := strings.CutPrefix(fileName, module.path+"/"); ok {
+			return path.Join(module.dir, rest), true
+		}
+	}
+
+	if absPath := filepath.FromSlash(strings.TrimPrefix(fileName, "_")); filepath.IsAbs(absPath) {
+		relPath, err := filepath.Rel(m.root, absPath)
+		if err == nil && !strings.HasPrefix(relPath, "..") {
+			return filepath.ToSlash(relPath), true
+		}
+		return "", false
+	}
+
+	relPath := m.findFile(fileName)
+	return relPath, relPath != ""
+}
+
+// ImportPathOf returns the import path of the package in a directory of the project, given
+// slash-separated relative to it, or false when no module of the project holds it
+func (m *ModuleMap) ImportPathOf(relDir string) (string, bool) {
+	if m == nil {
+		return "", false
+	}
+	relDir = path.Clean(relDir)
+	for importPath, pkgDir := range m.packages {
+		if pkgDir == relDir {
+			return importPath, true
+		}
+	}
+
+	// The innermost module holding the directory
+	modulePath, rest, depth := "", "", -1
+	for _, module := range m.modules {
+		switch {
+		case module.dir == "." && depth < 0:
+			modulePath, rest, depth = module.path, relDir, 0
+		case relDir == module.dir && len(module.dir) > depth:
+			modulePath, rest, depth = module.path, ".", len(module.dir)
+		case strings.HasPrefix(relDir, module.dir+"/") && len(module.dir) > depth:
+			modulePath, rest, depth = module.path, strings.TrimPrefix(relDir, module.dir+"/"), len(module.dir)
+		}
+	}
+	if depth < 0 {
+		return "", false
+	}
+	return path.Join(modulePath, rest), true
+}
+
+// findFile returns the project file a name that is not an import path stands for, relative to the
+// project or to the vendor directory of one of its modules, or "" when no such file exists. Only the
+// exact path is tried, so a bare util.go never matches the util.go of some package
+func (m *ModuleMap) findFile(fileName string) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if relPath, ok := m.files[fileName]; ok {
+		return relPath
+	}
+
+	candidates := []string{path.Clean(fileName)}
+	for _, module := range m.modules {
+		candidates = append(candidates, path.Join(module.dir, "vendor", fileName))
+	}
+	if len(m.modules) == 0 {
+		candidates = append(candidates, path.Join("vendor", fileName))
+	}
+
+	relPath := ""
+	for _, candidate := range candidates {
+		if candidate == ".." || strings.HasPrefix(candidate, "../") {
+			continue
+		}
+		if info, err := os.Stat(filepath.Join(m.root, filepath.FromSlash(candidate))); err == nil && !info.IsDir() {
+			relPath = candidate
+			break
+		}
+	}
+
+	if m.files == nil {
+		m.files = make(map[string]string)
+	}
+	m.files[fileName] = relPath
+	return relPath
+}
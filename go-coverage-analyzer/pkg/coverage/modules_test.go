@@ -0,0 +1,151 @@
+package coverage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeFiles creates files under root, by slash-separated path relative to it
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestModuleMapResolve(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    map[string]string
+		resolves map[string]string // profile file name to project file, "" when it is not in the project
+	}{
+		{
+			name: "standalone module",
+			files: map[string]string{
+				"go.mod":             "module example.com/app\n\ngo 1.21\n",
+				"main.go":            "package main\n",
+				"internal/db/db.go":  "package db\n",
+				"internal/db/sql.go": "package db\n",
+			},
+			resolves: map[string]string{
+				"example.com/app/main.go":            "main.go",
+				"example.com/app/internal/db/db.go":  "internal/db/db.go",
+				"example.com/app/internal/db/sql.go": "internal/db/sql.go",
+				"example.com/other/main.go":          "",
+			},
+		},
+		{
+			name: "nested modules",
+			files: map[string]string{
+				"go.mod":               "module example.com/app\n\ngo 1.21\n",
+				"server/server.go":     "package server\n",
+				"tools/go.mod":         "module example.com/app/tools\n\ngo 1.21\n",
+				"tools/lint/lint.go":   "package lint\n",
+				"tools/internal/x.go":  "package internal\n",
+				"plugins/auth/go.mod":  "module example.com/auth\n\ngo 1.21\n",
+				"plugins/auth/auth.go": "package auth\n",
+			},
+			resolves: map[string]string{
+				"example.com/app/server/server.go":    "server/server.go",
+				"example.com/app/tools/lint/lint.go":  "tools/lint/lint.go",
+				"example.com/app/tools/internal/x.go": "tools/internal/x.go",
+				"example.com/auth/auth.go":            "plugins/auth/auth.go",
+			},
+		},
+		{
+			name: "replaced module",
+			files: map[string]string{
+				"go.mod":                         "module example.com/app\n\ngo 1.21\n\nrequire example.com/lib v1.0.0\n\nreplace example.com/lib => ./third_party/lib\n",
+				"main.go":                        "package main\n\nimport _ \"example.com/lib/codec\"\n",
+				"third_party/lib/go.mod":         "module example.com/lib\n\ngo 1.21\n",
+				"third_party/lib/codec/codec.go": "package codec\n",
+			},
+			resolves: map[string]string{
+				"example.com/app/main.go":        "main.go",
+				"example.com/lib/codec/codec.go": "third_party/lib/codec/codec.go",
+			},
+		},
+		{
+			name: "same file name in two packages",
+			files: map[string]string{
+				"go.mod":              "module example.com/app\n\ngo 1.21\n",
+				"api/util.go":         "package api\n",
+				"store/util.go":       "package store\n",
+				"store/cache/util.go": "package cache\n",
+			},
+			resolves: map[string]string{
+				"example.com/app/api/util.go":         "api/util.go",
+				"example.com/app/store/util.go":       "store/util.go",
+				"example.com/app/store/cache/util.go": "store/cache/util.go",
+				"util.go":                             "",
+			},
+		},
+		{
+			name: "names that are not import paths",
+			files: map[string]string{
+				"go.mod":                                "module example.com/app\n\ngo 1.21\n",
+				"internal/db/db.go":                     "package db\n",
+				"vendor/github.com/acme/retry/retry.go": "package retry\n",
+			},
+			resolves: map[string]string{
+				"internal/db/db.go":               "internal/db/db.go",
+				"./internal/db/db.go":             "internal/db/db.go",
+				"github.com/acme/retry/retry.go":  "vendor/github.com/acme/retry/retry.go",
+				"github.com/acme/missing/miss.go": "",
+				"../outside.go":                   "",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			writeFiles(t, root, tt.files)
+
+			modules, err := LoadModules(context.Background(), root)
+			if err != nil {
+				t.Fatalf("LoadModules() error = %v", err)
+			}
+
+			for fileName, want := range tt.resolves {
+				got, ok := modules.Resolve(fileName)
+				if ok != (want != "") || got != want {
+					t.Errorf("Resolve(%q) = %q, %v, want %q", fileName, got, ok, want)
+				}
+			}
+		})
+	}
+}
+
+func TestNewModuleMapResolve(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"go.mod":        "module example.com/app\n\ngo 1.21\n",
+		"a/util.go":     "package a\n",
+		"b/util.go":     "package b\n",
+		"vendor/x/x.go": "package x\n",
+	})
+
+	modules := NewModuleMap(root, "example.com/app")
+	tests := map[string]string{
+		"example.com/app/a/util.go": "a/util.go",
+		"example.com/app/b/util.go": "b/util.go",
+		"x/x.go":                    "vendor/x/x.go",
+		filepath.ToSlash(filepath.Join(root, "a", "util.go")): "a/util.go",
+		"example.org/else/a/util.go":                          "",
+	}
+	for fileName, want := range tests {
+		got, ok := modules.Resolve(fileName)
+		if ok != (want != "") || got != want {
+			t.Errorf("Resolve(%q) = %q, %v, want %q", fileName, got, ok, want)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package coverage
+
+import (
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/beck/go-coverage-analyzer/pkg/models"
+)
+
+// MatchPackagePattern reports whether a package directory, relative to the project, matches a Go-style
+// pattern: ./internal/... matches internal and every package below it, ./cmd/gcov only that package,
+// and ... elsewhere matches any string, as with go list
+func MatchPackagePattern(pattern, dir string) bool {
+	pattern = path.Clean(strings.TrimPrefix(filepath.ToSlash(pattern), "./"))
+	dir = path.Clean(filepath.ToSlash(dir))
+
+	if !strings.Contains(pattern, "...") {
+		return pattern == dir
+	}
+
+	expr := regexp.QuoteMeta(pattern)
+	expr = strings.ReplaceAll(expr, `\.\.\.`, `.*`)
+	// x/... matches x itself as well
+	if strings.HasSuffix(expr, `/.*`) {
+		expr = strings.TrimSuffix(expr, `/.*`) + `(/.*)?`
+	}
+	matched, err := regexp.MatchString("^"+expr+"$", dir)
+	return err == nil && matched
+}
+
+// MatchPackages reports whether a package directory is selected by include and exclude patterns;
+// no include patterns select every package
+func MatchPackages(dir string, include, exclude []string) bool {
+	for _, pattern := range exclude {
+		if MatchPackagePattern(pattern, dir) {
+			return false
+		}
+	}
+	if len(include) == 0 {
+		return true
+	}
+	for _, pattern := range include {
+		if MatchPackagePattern(pattern, dir) {
+			return true
+		}
+	}
+	return false
+}
+
+// filterPackages drops the files whose directory the patterns do not select, and packages left without files
+func filterPackages(packages map[string]*models.Package, include, exclude []string) {
+	if len(include) == 0 && len(exclude) == 0 {
+		return
+	}
+
+	for name, pkg := range packages {
+		for filePath := range pkg.Files {
+			if !MatchPackages(filepath.Dir(filePath), include, exclude) {
+				delete(pkg.Files, filePath)
+			}
+		}
+		if len(pkg.Files) == 0 {
+			delete(packages, name)
+		}
+	}
+}
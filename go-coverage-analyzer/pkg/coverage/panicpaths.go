@@ -0,0 +1,187 @@
+package coverage
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"strconv"
+	"strings"
+
+	"github.com/beck/go-coverage-analyzer/pkg/models"
+)
+
+// extractPanicPaths records explicit panics, writes to maps that may be nil and index expressions no
+// length check guards, with the condition under which they panic, and sets CanPanic when there are any
+func (e *AnalysisEngine) extractPanicPaths(funcDecl *ast.FuncDecl, function *models.Function, source string) {
+	if funcDecl.Body == nil {
+		return
+	}
+
+	params := make(map[string]bool)
+	paramTypes := make(map[string]string)
+	for _, param := range function.Parameters {
+		params[param.Name] = true
+		paramTypes[param.Name] = param.Type
+	}
+
+	guards := e.collectGuards(funcDecl.Body, params, source)
+	nilChecked, lengthChecked := checkedIdents(funcDecl.Body)
+	nilMaps := nilLocalMaps(funcDecl.Body)
+
+	// Indexing with the key of a range over the same value stays in bounds
+	rangeKeys := make(map[string]string)
+
+	seen := make(map[string]bool)
+	record := func(node ast.Node, kind, condition string, guard *models.Boundary) {
+		line := e.fset.Position(node.Pos()).Line
+		key := fmt.Sprintf("%s:%d", kind, line)
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+
+		path := &models.PanicPath{Line: line, Kind: kind, Condition: condition, Guard: guard}
+		if innermost := innermostGuard(guards, node); innermost != nil {
+			// A statement under an if or case only panics when that condition holds as well
+			path.Condition = innermost.condition
+			path.Guard = nil
+			if kind == models.PanicCall {
+				path.Guard = innermost.guard
+			}
+		}
+		function.PanicPaths = append(function.PanicPaths, path)
+	}
+
+	mapWrite := func(stmt ast.Stmt, target ast.Expr) {
+		index, ok := target.(*ast.IndexExpr)
+		if !ok {
+			return
+		}
+		m, ok := unparen(index.X).(*ast.Ident)
+		if !ok || nilChecked[m.Name] {
+			return
+		}
+		switch {
+		case nilMaps[m.Name]:
+			record(stmt, models.PanicNilMap, "", nil)
+		case params[m.Name] && strings.HasPrefix(paramTypes[m.Name], "map["):
+			record(stmt, models.PanicNilMap, m.Name+" == nil", &models.Boundary{Param: m.Name, Op: token.EQL.String(), Value: "nil"})
+		}
+	}
+
+	ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			// Closures may never be called, or recover themselves
+			return false
+		case *ast.RangeStmt:
+			key, keyOK := node.Key.(*ast.Ident)
+			x, xOK := unparen(node.X).(*ast.Ident)
+			if keyOK && xOK {
+				rangeKeys[key.Name] = x.Name
+			}
+		case *ast.CallExpr:
+			if ident, ok := node.Fun.(*ast.Ident); ok && ident.Name == "panic" {
+				record(node, models.PanicCall, "", nil)
+			}
+		case *ast.AssignStmt:
+			for _, lhs := range node.Lhs {
+				mapWrite(node, lhs)
+			}
+		case *ast.IncDecStmt:
+			mapWrite(node, node.X)
+		case *ast.IndexExpr:
+			x, ok := unparen(node.X).(*ast.Ident)
+			if !ok || !params[x.Name] || lengthChecked[x.Name] || !isIndexable(paramTypes[x.Name]) {
+				return true
+			}
+			if key, ok := unparen(node.Index).(*ast.Ident); ok && rangeKeys[key.Name] == x.Name {
+				return true
+			}
+
+			// Any index is out of range of an empty value, a constant one of values no longer than it
+			limit := "0"
+			if value, ok := literalValue(node.Index, e.constants); ok {
+				if n, err := strconv.Atoi(value); err == nil && n >= 0 {
+					limit = value
+				}
+			}
+			record(node, models.PanicIndex, fmt.Sprintf("len(%s) <= %s", x.Name, limit),
+				&models.Boundary{Param: x.Name, Length: true, Op: token.LEQ.String(), Value: limit})
+		}
+		return true
+	})
+
+	function.CanPanic = len(function.PanicPaths) > 0
+}
+
+// checkedIdents returns the identifiers a function compares against nil, which counts as guarding
+// map writes, and those it takes the length of, which counts as guarding index expressions
+func checkedIdents(body *ast.BlockStmt) (map[string]bool, map[string]bool) {
+	nilChecked := make(map[string]bool)
+	lengthChecked := make(map[string]bool)
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.BinaryExpr:
+			if node.Op != token.EQL && node.Op != token.NEQ {
+				return true
+			}
+			for _, pair := range [][2]ast.Expr{{node.X, node.Y}, {node.Y, node.X}} {
+				ident, ok := unparen(pair[0]).(*ast.Ident)
+				other, isIdent := unparen(pair[1]).(*ast.Ident)
+				if ok && isIdent && other.Name == "nil" {
+					nilChecked[ident.Name] = true
+				}
+			}
+		case *ast.CallExpr:
+			if fun, ok := node.Fun.(*ast.Ident); ok && (fun.Name == "len" || fun.Name == "cap") && len(node.Args) == 1 {
+				if ident, ok := unparen(node.Args[0]).(*ast.Ident); ok {
+					lengthChecked[ident.Name] = true
+				}
+			}
+		}
+		return true
+	})
+	return nilChecked, lengthChecked
+}
+
+// nilLocalMaps returns the maps a function declares without a value and never assigns
+func nilLocalMaps(body *ast.BlockStmt) map[string]bool {
+	declared := make(map[string]bool)
+	assigned := make(map[string]bool)
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.ValueSpec:
+			if _, ok := node.Type.(*ast.MapType); ok && len(node.Values) == 0 {
+				for _, name := range node.Names {
+					declared[name.Name] = true
+				}
+			}
+		case *ast.AssignStmt:
+			for _, lhs := range node.Lhs {
+				if ident, ok := lhs.(*ast.Ident); ok {
+					assigned[ident.Name] = true
+				}
+			}
+		case *ast.UnaryExpr:
+			// Maps whose address is taken may be set elsewhere
+			if ident, ok := node.X.(*ast.Ident); ok && node.Op == token.AND {
+				assigned[ident.Name] = true
+			}
+		}
+		return true
+	})
+
+	nilMaps := make(map[string]bool)
+	for name := range declared {
+		if !assigned[name] {
+			nilMaps[name] = true
+		}
+	}
+	return nilMaps
+}
+
+// isIndexable reports whether indexing a value of the type can go out of range
+func isIndexable(typeName string) bool {
+	return typeName == "string" || strings.HasPrefix(typeName, "[]") || strings.HasPrefix(typeName, "...")
+}
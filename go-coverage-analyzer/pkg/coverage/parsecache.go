@@ -0,0 +1,85 @@
+package coverage
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"sync"
+	"time"
+)
+
+// ParseCache keeps the source and syntax tree of the files analyses parse, so a long-running process
+// such as gcov daemon only parses the files changed since its previous analysis. Analyses sharing a
+// cache share its file set, and may run concurrently
+type ParseCache struct {
+	mu    sync.Mutex
+	fset  *token.FileSet
+	files map[string]*parsedFile
+}
+
+// parsedFile is a file as it was parsed, with the size and modification time telling when it changed
+type parsedFile struct {
+	size    int64
+	modTime time.Time
+	src     []byte
+	file    *ast.File
+	err     error
+}
+
+// NewParseCache creates an empty parse cache
+func NewParseCache() *ParseCache {
+	return &ParseCache{
+		fset:  token.NewFileSet(),
+		files: make(map[string]*parsedFile),
+	}
+}
+
+// Len returns how many files the cache holds
+func (c *ParseCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.files)
+}
+
+// parse returns the source and syntax tree, comments included, of a file, parsing it again only when
+// its size or modification time changed. Syntax errors are cached as well
+func (c *ParseCache) parse(path string) ([]byte, *ast.File, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c.mu.Lock()
+	cached, ok := c.files[path]
+	c.mu.Unlock()
+	if ok && cached.size == info.Size() && cached.modTime.Equal(info.ModTime()) {
+		return cached.src, cached.file, cached.err
+	}
+
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	file, err := parser.ParseFile(c.fset, path, src, parser.ParseComments)
+
+	c.mu.Lock()
+	c.files[path] = &parsedFile{size: info.Size(), modTime: info.ModTime(), src: src, file: file, err: err}
+	c.mu.Unlock()
+	return src, file, err
+}
+
+// parseFile reads and parses a Go file, through the parse cache of the analysis when it has one,
+// which parses comments whatever the mode
+func (e *AnalysisEngine) parseFile(path string, mode parser.Mode) ([]byte, *ast.File, error) {
+	if e.parseCache != nil {
+		return e.parseCache.parse(path)
+	}
+
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	file, err := parser.ParseFile(e.fset, path, src, mode)
+	return src, file, err
+}
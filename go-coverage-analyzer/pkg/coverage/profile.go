@@ -2,17 +2,25 @@ package coverage
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"go/build"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strconv"
 	"strings"
 
+	"github.com/beck/go-coverage-analyzer/internal/command"
+	"github.com/beck/go-coverage-analyzer/internal/fileutil"
+	"github.com/beck/go-coverage-analyzer/internal/i18n"
 	"github.com/beck/go-coverage-analyzer/pkg/models"
 )
 
+// SubprocessCoverDirEnv names the directory in which processes started by tests, such as commands
+// run by the main harness, leave their coverage counters; GenerateProfile sets it for go test and
+// merges what it finds there into the profile
+const SubprocessCoverDirEnv = "GCOV_COVERDIR"
+
 // ProfileParser handles parsing and generation of Go coverage profiles
 type ProfileParser struct {
 	verbose bool
@@ -25,10 +33,11 @@ func NewProfileParser(verbose bool) *ProfileParser {
 	}
 }
 
-// GenerateProfile runs go test with coverage and generates a coverage profile
-func (p *ProfileParser) GenerateProfile(projectPath, outputFile string, packagePattern string) error {
+// GenerateProfile runs go test with coverage and generates a coverage profile; cancelling ctx, or its
+// command.WithTimeout deadline passing, stops go test and the test binaries it runs
+func (p *ProfileParser) GenerateProfile(ctx context.Context, projectPath, outputFile string, packagePattern string) error {
 	if p.verbose {
-		fmt.Printf("🔍 Generating coverage profile for: %s\n", projectPath)
+		i18n.Printf("🔍 Generating coverage profile for: %s\n", projectPath)
 	}
 
 	// Prepare the go test command
@@ -37,9 +46,9 @@ func (p *ProfileParser) GenerateProfile(projectPath, outputFile string, packageP
 	// Add coverage mode
 	args = append(args, "-covermode=atomic")
 
-	// Add package pattern or default to all packages
+	// Add package patterns, separated by spaces, or default to all packages
 	if packagePattern != "" {
-		args = append(args, packagePattern)
+		args = append(args, strings.Fields(packagePattern)...)
 	} else {
 		args = append(args, "./...")
 	}
@@ -57,9 +66,17 @@ func (p *ProfileParser) GenerateProfile(projectPath, outputFile string, packageP
 		}
 	}
 
+	// Subprocesses built with coverage, as test binaries run by tests are, report to their own directory
+	coverDir, err := os.MkdirTemp("", "gcov-coverdir-*")
+	if err != nil {
+		return fmt.Errorf("failed to create subprocess coverage directory: %w", err)
+	}
+	defer os.RemoveAll(coverDir)
+
 	// Execute go test command
-	cmd := exec.Command("go", args...)
+	cmd := command.Go(ctx, args...)
 	cmd.Dir = projectPath
+	cmd.Env = append(os.Environ(), SubprocessCoverDirEnv+"="+coverDir)
 
 	if p.verbose {
 		cmd.Stdout = os.Stdout
@@ -68,7 +85,7 @@ func (p *ProfileParser) GenerateProfile(projectPath, outputFile string, packageP
 	}
 
 	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to generate coverage profile: %w", err)
+		return fmt.Errorf("failed to generate coverage profile: go test %w", command.Err(ctx, err))
 	}
 
 	// Verify profile was created
@@ -76,8 +93,88 @@ func (p *ProfileParser) GenerateProfile(projectPath, outputFile string, packageP
 		return fmt.Errorf("coverage profile not found at %s: %w", outputFile, err)
 	}
 
+	if err := p.mergeSubprocessCoverage(ctx, projectPath, outputFile, coverDir); err != nil {
+		return err
+	}
+
+	if p.verbose {
+		i18n.Printf("✅ Coverage profile generated: %s\n", outputFile)
+	}
+
+	return nil
+}
+
+// mergeSubprocessCoverage adds the counters subprocesses left in coverDir to the profile, converted
+// with go tool covdata; blocks both report are counted once, with their counts added
+func (p *ProfileParser) mergeSubprocessCoverage(ctx context.Context, projectPath, profilePath, coverDir string) error {
+	entries, err := os.ReadDir(coverDir)
+	if err != nil || len(entries) == 0 {
+		return nil
+	}
+
+	converted := filepath.Join(coverDir, "subprocess.out")
+	cmd := command.Go(ctx, "tool", "covdata", "textfmt", "-i="+coverDir, "-o="+converted)
+	cmd.Dir = projectPath
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to convert subprocess coverage: go tool covdata %w: %s", command.Err(ctx, err), strings.TrimSpace(string(output)))
+	}
+
+	profile, err := os.ReadFile(profilePath)
+	if err != nil {
+		return fmt.Errorf("failed to read coverage profile: %w", err)
+	}
+	subprocess, err := os.ReadFile(converted)
+	if err != nil {
+		return fmt.Errorf("failed to read subprocess coverage: %w", err)
+	}
+
+	lines := strings.Split(strings.TrimRight(string(profile), "\n"), "\n")
+	if len(lines) == 0 || !strings.HasPrefix(lines[0], "mode: ") {
+		return fmt.Errorf("coverage profile %s has no mode line", profilePath)
+	}
+	mode := strings.TrimPrefix(lines[0], "mode: ")
+
+	// Blocks are keyed by everything but their count, the last field
+	counts := make(map[string]int64)
+	order := make([]string, 0, len(lines))
+	add := func(line string) {
+		cut := strings.LastIndexByte(line, ' ')
+		if cut < 0 || strings.HasPrefix(line, "mode: ") {
+			return
+		}
+		count, err := strconv.ParseInt(line[cut+1:], 10, 64)
+		if err != nil {
+			return
+		}
+		key := line[:cut]
+		if _, seen := counts[key]; !seen {
+			order = append(order, key)
+		}
+		if mode == "set" {
+			counts[key] = max(counts[key], min(count, 1))
+		} else {
+			counts[key] += count
+		}
+	}
+	for _, line := range lines[1:] {
+		add(line)
+	}
+	profileBlocks := len(order)
+	for _, line := range strings.Split(string(subprocess), "\n") {
+		add(strings.TrimSpace(line))
+	}
+
+	var merged strings.Builder
+	merged.WriteString("mode: " + mode + "\n")
+	for _, key := range order {
+		fmt.Fprintf(&merged, "%s %d\n", key, counts[key])
+	}
+	if err := fileutil.WriteAtomic(profilePath, []byte(merged.String()), 0644); err != nil {
+		return fmt.Errorf("failed to write merged coverage profile: %w", err)
+	}
+
 	if p.verbose {
-		fmt.Printf("✅ Coverage profile generated: %s\n", outputFile)
+		i18n.Printf("🔁 Merged the coverage of subprocesses into the profile (%d new blocks)\n", len(order)-profileBlocks)
 	}
 
 	return nil
@@ -142,7 +239,7 @@ func (p *ProfileParser) ParseProfile(profilePath string) (*models.CoverageProfil
 	}
 
 	if p.verbose {
-		fmt.Printf("📊 Parsed profile: %d blocks across %d files\n", len(profile.Blocks), len(profile.Files))
+		i18n.Printf("📊 Parsed profile: %d blocks across %d files\n", len(profile.Blocks), len(profile.Files))
 	}
 
 	return profile, nil
@@ -151,10 +248,14 @@ func (p *ProfileParser) ParseProfile(profilePath string) (*models.CoverageProfil
 // parseProfileLine parses a single line from a coverage profile
 // Format: filename:startLine.startCol,endLine.endCol numStmts count
 func (p *ProfileParser) parseProfileLine(line string) (*models.ProfileBlock, error) {
+	// File names may contain spaces, as Windows user directories often do, so the counts are split off the end
 	parts := strings.Fields(line)
-	if len(parts) != 3 {
+	if len(parts) < 3 {
 		return nil, fmt.Errorf("invalid profile line format: %s", line)
 	}
+	if len(parts) > 3 {
+		parts = []string{strings.Join(parts[:len(parts)-2], " "), parts[len(parts)-2], parts[len(parts)-1]}
+	}
 
 	// Parse file and position information
 	filePos := parts[0]
@@ -269,10 +370,17 @@ func (p *ProfileParser) ConvertToBlocks(profileBlocks []*models.ProfileBlock) []
 
 // GetProjectInfo extracts information about the Go project
 func (p *ProfileParser) GetProjectInfo(projectPath string) (*models.ProjectInfo, error) {
+	return p.GetProjectInfoWithin(projectPath, ".")
+}
+
+// GetProjectInfoWithin extracts information about the Go project from the files under dir only, a
+// directory relative to the project, keeping file paths relative to the project
+func (p *ProfileParser) GetProjectInfoWithin(projectPath, dir string) (*models.ProjectInfo, error) {
 	absPath, err := filepath.Abs(projectPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get absolute path: %w", err)
 	}
+	walkRoot := filepath.Join(absPath, dir)
 
 	info := &models.ProjectInfo{
 		RootDir:      absPath,
@@ -288,7 +396,7 @@ func (p *ProfileParser) GetProjectInfo(projectPath string) (*models.ProjectInfo,
 	}
 
 	// Walk the project directory to collect information
-	err = filepath.Walk(absPath, func(path string, fileInfo os.FileInfo, err error) error {
+	err = filepath.Walk(walkRoot, func(path string, fileInfo os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
@@ -296,7 +404,7 @@ func (p *ProfileParser) GetProjectInfo(projectPath string) (*models.ProjectInfo,
 		if fileInfo.IsDir() {
 			// Skip vendor, .git, and other common directories
 			name := fileInfo.Name()
-			if name == "vendor" || name == ".git" || name == "node_modules" || strings.HasPrefix(name, ".") {
+			if path != walkRoot && (name == "vendor" || name == ".git" || name == "node_modules" || strings.HasPrefix(name, ".")) {
 				return filepath.SkipDir
 			}
 			return nil
@@ -0,0 +1,434 @@
+package coverage
+
+import (
+	"go/ast"
+	"go/token"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/beck/go-coverage-analyzer/pkg/models"
+)
+
+// reference is a use of a function or method of the project, qualified as far as the syntax tells.
+// A method called on a value of unknown type, such as a struct field, or of a type declaring no such
+// method, such as an interface, lists the packages whose methods of that name it may refer to
+type reference struct {
+	key        string   // refKey of the function or method, "" when the receiver is unknown
+	method     string   // name of the method, "" for functions
+	candidates []string // import paths of the packages the method may belong to, nil for all of them
+}
+
+// refKey identifies a function, or a method by the name of its receiver type, in the package with an
+// import path
+func refKey(pkg, receiver, name string) string {
+	if receiver == "" {
+		return pkg + "." + name
+	}
+	return pkg + "." + receiver + "." + name
+}
+
+// declarations indexes the functions and methods of the analyzed packages by import path, so that
+// references in other files resolve to the declaration they name rather than to any of the same name
+type declarations struct {
+	modules     *ModuleMap
+	importPaths map[string]string              // project directory, slash-separated, to import path
+	functions   map[string]*models.Function    // by refKey
+	receivers   map[string]map[string][]string // import path to method name to receiver types
+}
+
+// newDeclarations indexes the functions of the parsed packages, which several directories may share the
+// name of, by the import path of the directory of each file
+func (e *AnalysisEngine) newDeclarations(packages map[string]*models.Package) *declarations {
+	d := &declarations{
+		modules:     e.modules,
+		importPaths: make(map[string]string),
+		functions:   make(map[string]*models.Function),
+		receivers:   make(map[string]map[string][]string),
+	}
+	for _, pkg := range packages {
+		for _, file := range pkg.Files {
+			pkgPath := d.importPath(filepath.Dir(file.Path))
+			for _, function := range file.Functions {
+				d.functions[d.functionKey(pkgPath, function)] = function
+				if function.IsMethod {
+					if d.receivers[pkgPath] == nil {
+						d.receivers[pkgPath] = make(map[string][]string)
+					}
+					d.receivers[pkgPath][function.Name] = append(d.receivers[pkgPath][function.Name], receiverTypeName(function.ReceiverType))
+				}
+			}
+		}
+	}
+	return d
+}
+
+// importPath returns the import path of the package in a project directory, or the directory itself
+// when no module holds it, which still tells packages of the same name apart
+func (d *declarations) importPath(dir string) string {
+	dir = filepath.ToSlash(dir)
+	if pkgPath, ok := d.importPaths[dir]; ok {
+		return pkgPath
+	}
+	pkgPath, ok := d.modules.ImportPathOf(dir)
+	if !ok {
+		pkgPath = dir
+	}
+	d.importPaths[dir] = pkgPath
+	return pkgPath
+}
+
+// functionKey returns the refKey of a function declared in the package with an import path
+func (d *declarations) functionKey(pkgPath string, function *models.Function) string {
+	if function.IsMethod {
+		return refKey(pkgPath, receiverTypeName(function.ReceiverType), function.Name)
+	}
+	return refKey(pkgPath, "", function.Name)
+}
+
+// targets returns the refKeys of the declarations a reference may be to: the one it names, or else for
+// methods all those of its name in the candidate packages, or in every package when it has no candidates
+func (d *declarations) targets(ref reference) []string {
+	if d.functions[ref.key] != nil {
+		return []string{ref.key}
+	}
+	keys := make([]string, 0)
+	if ref.method == "" {
+		return keys
+	}
+	add := func(pkgPath string) {
+		for _, receiver := range d.receivers[pkgPath][ref.method] {
+			keys = append(keys, refKey(pkgPath, receiver, ref.method))
+		}
+	}
+	if ref.candidates == nil {
+		for pkgPath := range d.receivers {
+			add(pkgPath)
+		}
+		return keys
+	}
+	for _, pkgPath := range ref.candidates {
+		add(pkgPath)
+	}
+	return keys
+}
+
+// typeRef is a named type of a package of the project, the receiver of the methods called on its values
+type typeRef struct {
+	pkg  string
+	name string
+}
+
+// referenceScope follows the references of one top-level declaration of a file, with the types of the
+// variables it declares where they can be told from the syntax. Shadowing is not tracked
+type referenceScope struct {
+	d       *declarations
+	pkg     string            // import path of the package of the file
+	own     bool              // whether unqualified names refer to pkg, which they do not in external test packages
+	imports map[string]string // import path by the name the file refers to it with
+	vars    map[string]typeRef
+	refs    []reference
+}
+
+// fileReferences returns the references of a file of the package with an import path to exported
+// functions and methods of the project, by the top-level function they are made from, "" for other
+// declarations. own is false for external test packages, whose unqualified names are their own
+func (d *declarations) fileReferences(file *ast.File, pkgPath string, own bool) map[string][]reference {
+	imports := make(map[string]string)
+	for _, spec := range file.Imports {
+		importPath, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+		name := path.Base(importPath)
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		imports[name] = importPath
+	}
+
+	refs := make(map[string][]reference)
+	for _, decl := range file.Decls {
+		scope := &referenceScope{d: d, pkg: pkgPath, own: own, imports: imports, vars: make(map[string]typeRef)}
+		from := ""
+		if funcDecl, ok := decl.(*ast.FuncDecl); ok {
+			receiver := ""
+			if funcDecl.Recv != nil && len(funcDecl.Recv.List) > 0 {
+				receiver = receiverTypeName(receiverExpr(funcDecl.Recv.List[0].Type))
+				scope.bindFields(funcDecl.Recv)
+			}
+			from = refKey(pkgPath, receiver, funcDecl.Name.Name)
+			scope.bindFields(funcDecl.Type.Params)
+
+			// The name of the declaration is no reference to it
+			ast.Inspect(funcDecl.Type, scope.visit)
+			if funcDecl.Body != nil {
+				ast.Inspect(funcDecl.Body, scope.visit)
+			}
+		} else {
+			ast.Inspect(decl, scope.visit)
+		}
+		refs[from] = append(refs[from], scope.refs...)
+	}
+	return refs
+}
+
+// receiverExpr renders the receiver type of a method declaration without its type parameters
+func receiverExpr(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		return t.Name
+	case *ast.StarExpr:
+		return "*" + receiverExpr(t.X)
+	case *ast.IndexExpr:
+		return receiverExpr(t.X)
+	case *ast.IndexListExpr:
+		return receiverExpr(t.X)
+	case *ast.ParenExpr:
+		return receiverExpr(t.X)
+	}
+	return ""
+}
+
+// visit records the references of a node, following the types of the variables it declares
+func (s *referenceScope) visit(n ast.Node) bool {
+	switch node := n.(type) {
+	case *ast.FuncLit:
+		s.bindFields(node.Type.Params)
+	case *ast.AssignStmt:
+		s.bindValues(node.Lhs, node.Rhs)
+	case *ast.ValueSpec:
+		names := make([]ast.Expr, len(node.Names))
+		for i, name := range node.Names {
+			names[i] = name
+		}
+		if node.Type != nil {
+			if t, ok := s.typeOf(node.Type); ok {
+				for _, name := range node.Names {
+					s.vars[name.Name] = t
+				}
+			}
+		} else {
+			s.bindValues(names, node.Values)
+		}
+		for _, value := range node.Values {
+			ast.Inspect(value, s.visit)
+		}
+		if node.Type != nil {
+			ast.Inspect(node.Type, s.visit)
+		}
+		return false
+	case *ast.InterfaceType:
+		// Methods an interface requires may be called on any type implementing it
+		for _, field := range node.Methods.List {
+			for _, name := range field.Names {
+				if ast.IsExported(name.Name) {
+					s.refs = append(s.refs, reference{method: name.Name})
+				}
+			}
+			ast.Inspect(field.Type, s.visit)
+		}
+		return false
+	case *ast.Field:
+		// Field and parameter names are no references
+		ast.Inspect(node.Type, s.visit)
+		return false
+	case *ast.KeyValueExpr:
+		if _, ok := node.Key.(*ast.Ident); ok {
+			// Field names of composite literals are no references either
+			ast.Inspect(node.Value, s.visit)
+			return false
+		}
+	case *ast.SelectorExpr:
+		s.selector(node)
+		return false
+	case *ast.Ident:
+		if s.own && ast.IsExported(node.Name) {
+			s.refs = append(s.refs, reference{key: refKey(s.pkg, "", node.Name)})
+		}
+	}
+	return true
+}
+
+// selector records the reference of x.Name: a function of an imported package, or a method of the
+// type of x when it is known, or else of the packages the file can reach
+func (s *referenceScope) selector(sel *ast.SelectorExpr) {
+	if ident, ok := sel.X.(*ast.Ident); ok {
+		if importPath, imported := s.imports[ident.Name]; imported {
+			if _, shadowed := s.vars[ident.Name]; !shadowed {
+				if ast.IsExported(sel.Sel.Name) {
+					s.refs = append(s.refs, reference{key: refKey(importPath, "", sel.Sel.Name)})
+				}
+				return
+			}
+		}
+	}
+
+	ast.Inspect(sel.X, s.visit)
+	if !ast.IsExported(sel.Sel.Name) {
+		return
+	}
+
+	// Methods of unknown receivers may be those of the package or of the project packages it imports
+	ref := reference{method: sel.Sel.Name, candidates: make([]string, 0, len(s.imports)+1)}
+	if t, ok := s.exprType(sel.X); ok {
+		// Values of types of other modules, such as *testing.T, have none of the methods of the project
+		if t.pkg != s.pkg && s.d.receivers[t.pkg] == nil {
+			return
+		}
+		ref.key = refKey(t.pkg, t.name, sel.Sel.Name)
+	}
+	if s.own {
+		ref.candidates = append(ref.candidates, s.pkg)
+	}
+	for _, importPath := range s.imports {
+		if s.d.receivers[importPath] != nil {
+			ref.candidates = append(ref.candidates, importPath)
+		}
+	}
+	s.refs = append(s.refs, ref)
+}
+
+// bindFields records the types of named parameters and receivers
+func (s *referenceScope) bindFields(fields *ast.FieldList) {
+	if fields == nil {
+		return
+	}
+	for _, field := range fields.List {
+		t, ok := s.typeOf(field.Type)
+		if !ok {
+			continue
+		}
+		for _, name := range field.Names {
+			s.vars[name.Name] = t
+		}
+	}
+}
+
+// bindValues records the types of variables assigned values, one each or the results of one call
+func (s *referenceScope) bindValues(names, values []ast.Expr) {
+	if len(values) == 1 && len(names) > 1 {
+		call, ok := values[0].(*ast.CallExpr)
+		if !ok {
+			return
+		}
+		function, pkgPath := s.callee(call)
+		if function == nil {
+			return
+		}
+		for i, name := range names {
+			ident, ok := name.(*ast.Ident)
+			if !ok || i >= len(function.ReturnTypes) {
+				continue
+			}
+			if t, ok := resultType(function.ReturnTypes[i], pkgPath); ok {
+				s.vars[ident.Name] = t
+			}
+		}
+		return
+	}
+
+	for i, name := range names {
+		ident, ok := name.(*ast.Ident)
+		if !ok || i >= len(values) {
+			continue
+		}
+		if t, ok := s.exprType(values[i]); ok {
+			s.vars[ident.Name] = t
+		}
+	}
+}
+
+// exprType returns the project type of the value of an expression, when the syntax tells it
+func (s *referenceScope) exprType(expr ast.Expr) (typeRef, bool) {
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		t, ok := s.vars[expr.Name]
+		return t, ok
+	case *ast.ParenExpr:
+		return s.exprType(expr.X)
+	case *ast.StarExpr:
+		return s.exprType(expr.X)
+	case *ast.UnaryExpr:
+		if expr.Op == token.AND {
+			return s.exprType(expr.X)
+		}
+	case *ast.CompositeLit:
+		if expr.Type != nil {
+			return s.typeOf(expr.Type)
+		}
+	case *ast.CallExpr:
+		if ident, ok := expr.Fun.(*ast.Ident); ok && ident.Name == "new" && len(expr.Args) == 1 {
+			return s.typeOf(expr.Args[0])
+		}
+		if function, pkgPath := s.callee(expr); function != nil && len(function.ReturnTypes) > 0 {
+			return resultType(function.ReturnTypes[0], pkgPath)
+		}
+	}
+	return typeRef{}, false
+}
+
+// typeOf returns the project type a type expression names
+func (s *referenceScope) typeOf(expr ast.Expr) (typeRef, bool) {
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		if s.own {
+			return typeRef{pkg: s.pkg, name: expr.Name}, true
+		}
+	case *ast.StarExpr:
+		return s.typeOf(expr.X)
+	case *ast.ParenExpr:
+		return s.typeOf(expr.X)
+	case *ast.IndexExpr:
+		return s.typeOf(expr.X)
+	case *ast.IndexListExpr:
+		return s.typeOf(expr.X)
+	case *ast.SelectorExpr:
+		if ident, ok := expr.X.(*ast.Ident); ok {
+			if importPath, imported := s.imports[ident.Name]; imported {
+				return typeRef{pkg: importPath, name: expr.Sel.Name}, true
+			}
+		}
+	}
+	return typeRef{}, false
+}
+
+// callee returns the declaration of the project function or method a call is to, and the import path
+// of its package
+func (s *referenceScope) callee(call *ast.CallExpr) (*models.Function, string) {
+	fun := call.Fun
+	switch index := fun.(type) {
+	case *ast.IndexExpr:
+		fun = index.X
+	case *ast.IndexListExpr:
+		fun = index.X
+	}
+
+	switch fun := fun.(type) {
+	case *ast.Ident:
+		if s.own {
+			return s.d.functions[refKey(s.pkg, "", fun.Name)], s.pkg
+		}
+	case *ast.SelectorExpr:
+		if ident, ok := fun.X.(*ast.Ident); ok {
+			if importPath, imported := s.imports[ident.Name]; imported {
+				return s.d.functions[refKey(importPath, "", fun.Sel.Name)], importPath
+			}
+		}
+		if t, ok := s.exprType(fun.X); ok {
+			return s.d.functions[refKey(t.pkg, t.name, fun.Sel.Name)], t.pkg
+		}
+	}
+	return nil, ""
+}
+
+// resultType returns the type of a result declared in the package with an import path, for named types
+// of that package; types of other packages are named as the declaring file imports them, and not followed
+func resultType(result, pkgPath string) (typeRef, bool) {
+	name := receiverTypeName(result)
+	if name == "" || strings.ContainsAny(name, ".[]()*{} ") {
+		return typeRef{}, false
+	}
+	return typeRef{pkg: pkgPath, name: name}, true
+}
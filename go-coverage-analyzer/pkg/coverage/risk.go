@@ -0,0 +1,108 @@
+package coverage
+
+import (
+	"go/scanner"
+	"go/token"
+	"math"
+	"strings"
+
+	"github.com/beck/go-coverage-analyzer/pkg/models"
+)
+
+// Risk score weights. Complexity dominates because branching code is where
+// untested behaviour hides; length and parameter count add smaller penalties.
+const (
+	riskComplexityWeight = 0.5
+	riskLengthWeight     = 0.3
+	riskParamsWeight     = 0.2
+
+	// Complexity at which its factor saturates to its full weight; length and
+	// parameter count saturate at the function limits
+	riskComplexityCeiling = 20.0
+)
+
+// applyRiskScores calculates maintainability and risk metrics for every function, and records the
+// function limits it exceeds
+func (e *AnalysisEngine) applyRiskScores(packages map[string]*models.Package) {
+	for _, pkg := range packages {
+		for _, file := range pkg.Files {
+			for _, function := range file.Functions {
+				function.OverLimits = nil
+				if function.Lines > e.lineLimit {
+					function.OverLimits = append(function.OverLimits, models.LimitLines)
+				}
+				if function.ParamCount > e.paramLimit {
+					function.OverLimits = append(function.OverLimits, models.LimitParams)
+				}
+
+				function.MaintainabilityIndex = calculateMaintainabilityIndex(function)
+				function.RiskScore = calculateRiskScore(function, e.lineLimit, e.paramLimit)
+			}
+		}
+	}
+}
+
+// calculateMaintainabilityIndex computes a simplified maintainability index (0-100).
+// It uses the classic SEI formula without the Halstead volume term, which would
+// require a full operator/operand count of the function body.
+func calculateMaintainabilityIndex(function *models.Function) float64 {
+	loc := float64(functionLength(function))
+	if loc < 1 {
+		loc = 1
+	}
+
+	mi := 171.0 - 0.23*float64(function.Complexity) - 16.2*math.Log(loc)
+	mi = mi * 100.0 / 171.0
+
+	return math.Max(0, math.Min(100, mi))
+}
+
+// calculateRiskScore combines complexity, length, parameter count and coverage
+// into a single 0-100 score. Functions at the line and parameter limits get the
+// full length and parameter weights. Fully covered functions score zero.
+func calculateRiskScore(function *models.Function, lineLimit, paramLimit int) float64 {
+	complexity := math.Min(float64(function.Complexity)/riskComplexityCeiling, 1.0)
+	length := math.Min(float64(function.Lines)/float64(lineLimit), 1.0)
+	params := math.Min(float64(function.ParamCount)/float64(paramLimit), 1.0)
+
+	structural := riskComplexityWeight*complexity + riskLengthWeight*length + riskParamsWeight*params
+	uncovered := 1.0 - function.Coverage/100.0
+
+	return math.Round(structural*uncovered*1000) / 10
+}
+
+// functionLength returns the number of source lines spanned by a function
+func functionLength(function *models.Function) int {
+	if function.EndLine < function.StartLine {
+		return 0
+	}
+	return function.EndLine - function.StartLine + 1
+}
+
+// countCodeLines returns the lines of a function's source holding code, leaving out blank lines and
+// those with only comments
+func countCodeLines(src []byte) int {
+	fset := token.NewFileSet()
+	file := fset.AddFile("", fset.Base(), len(src))
+
+	var s scanner.Scanner
+	s.Init(file, src, nil, 0)
+
+	lines := make(map[int]bool)
+	for {
+		pos, tok, lit := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+		if tok == token.SEMICOLON && lit == "\n" {
+			continue // inserted at line ends, not code of its own
+		}
+
+		// Raw strings spanning lines hold code on each of them
+		line := file.Line(pos)
+		for i := 0; i <= strings.Count(lit, "\n"); i++ {
+			lines[line+i] = true
+		}
+	}
+	return len(lines)
+}
@@ -0,0 +1,71 @@
+package coverage
+
+import (
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// analysisScope limits an analysis to one file or directory subtree of the project, so editors can
+// check the code being worked on without walking and parsing the whole project
+type analysisScope struct {
+	dir  string // slash-separated directory relative to the project, the package of a file scope
+	file string // slash-separated file relative to the project, "" for a directory scope
+}
+
+// newAnalysisScope returns the scope of a file or directory relative to the project, nil for the
+// whole project
+func newAnalysisScope(relPath string, isFile bool) *analysisScope {
+	relPath = path.Clean(filepath.ToSlash(relPath))
+	if relPath == "." || relPath == "" {
+		return nil
+	}
+	if isFile {
+		return &analysisScope{dir: path.Dir(relPath), file: relPath}
+	}
+	return &analysisScope{dir: relPath}
+}
+
+// root returns the directory of the project the analysis walks, the project itself without a scope
+func (s *analysisScope) root(projectPath string) string {
+	if s == nil {
+		return projectPath
+	}
+	return filepath.Join(projectPath, filepath.FromSlash(s.dir))
+}
+
+// walkDir reports whether a directory relative to the project holds files of the scope. A file
+// scope keeps to its package directory, a directory scope includes the packages below it
+func (s *analysisScope) walkDir(relDir string) bool {
+	if s == nil {
+		return true
+	}
+	relDir = filepath.ToSlash(relDir)
+	if s.file != "" {
+		return relDir == s.dir
+	}
+	return relDir == s.dir || strings.HasPrefix(relDir, s.dir+"/")
+}
+
+// contains reports whether a file relative to the project is part of the scope
+func (s *analysisScope) contains(relPath string) bool {
+	if s == nil {
+		return true
+	}
+	relPath = filepath.ToSlash(relPath)
+	if s.file != "" {
+		return relPath == s.file
+	}
+	return s.walkDir(path.Dir(relPath))
+}
+
+// packagePattern returns the go test package pattern covering the scope
+func (s *analysisScope) packagePattern() string {
+	if s.file != "" && s.dir == "." {
+		return "."
+	}
+	if s.file != "" {
+		return "./" + s.dir
+	}
+	return "./" + s.dir + "/..."
+}
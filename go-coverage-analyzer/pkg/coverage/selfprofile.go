@@ -0,0 +1,129 @@
+package coverage
+
+import (
+	"runtime"
+	"runtime/metrics"
+	"sync"
+	"time"
+
+	"github.com/beck/go-coverage-analyzer/pkg/models"
+)
+
+// heapSampleInterval is how often the self-profiler samples the heap for its high-water mark
+const heapSampleInterval = 20 * time.Millisecond
+
+// heapMetric is the live heap size, readable without stopping the world as runtime.ReadMemStats does
+const heapMetric = "/memory/classes/heap/objects:bytes"
+
+// selfProfiler records where analysis time goes, phase by phase, and the peak heap size. Its methods
+// do nothing on a nil profiler, so analysis steps mark phases without checking whether profiling is on
+type selfProfiler struct {
+	start      time.Time
+	phases     []*models.PhaseTiming
+	phase      string
+	phaseStart time.Time
+
+	mu       sync.Mutex
+	peak     uint64
+	stop     chan struct{}
+	stopOnce sync.Once
+	done     chan struct{}
+}
+
+// startSelfProfile starts timing the first phase and sampling the heap in the background
+func startSelfProfile(phase string) *selfProfiler {
+	now := time.Now()
+	p := &selfProfiler{
+		start:      now,
+		phase:      phase,
+		phaseStart: now,
+		stop:       make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+
+	go func() {
+		defer close(p.done)
+		ticker := time.NewTicker(heapSampleInterval)
+		defer ticker.Stop()
+		for {
+			p.sampleHeap()
+			select {
+			case <-p.stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return p
+}
+
+// next ends the current phase and starts timing the named one
+func (p *selfProfiler) next(phase string) {
+	if p == nil {
+		return
+	}
+	p.endPhase()
+	p.phase = phase
+	p.phaseStart = time.Now()
+}
+
+// endPhase records the duration of the current phase, adding to an earlier phase of the same name
+func (p *selfProfiler) endPhase() {
+	p.sampleHeap()
+	elapsed := time.Since(p.phaseStart)
+	for _, phase := range p.phases {
+		if phase.Name == p.phase {
+			phase.Duration += elapsed
+			return
+		}
+	}
+	p.phases = append(p.phases, &models.PhaseTiming{Name: p.phase, Duration: elapsed})
+}
+
+// finish stops the heap sampling and returns the recorded profile
+func (p *selfProfiler) finish() *models.SelfProfile {
+	if p == nil {
+		return nil
+	}
+	p.endPhase()
+	p.halt()
+
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return &models.SelfProfile{
+		Phases:         p.phases,
+		Total:          time.Since(p.start),
+		PeakHeapBytes:  p.peak,
+		TotalAllocated: stats.TotalAlloc,
+		GCCycles:       stats.NumGC,
+		Goroutines:     runtime.NumGoroutine(),
+		CPUs:           runtime.NumCPU(),
+	}
+}
+
+// halt stops the heap sampling, also when the analysis failed before finish
+func (p *selfProfiler) halt() {
+	if p == nil {
+		return
+	}
+	p.stopOnce.Do(func() { close(p.stop) })
+	<-p.done
+}
+
+// sampleHeap raises the high-water mark to the current heap size
+func (p *selfProfiler) sampleHeap() {
+	sample := []metrics.Sample{{Name: heapMetric}}
+	metrics.Read(sample)
+	if sample[0].Value.Kind() != metrics.KindUint64 {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if heap := sample[0].Value.Uint64(); heap > p.peak {
+		p.peak = heap
+	}
+}
@@ -0,0 +1,120 @@
+package coverage
+
+import (
+	"go/ast"
+
+	"github.com/beck/go-coverage-analyzer/pkg/models"
+)
+
+// envCalls read the process environment
+var envCalls = map[string]bool{
+	"os.Getenv":      true,
+	"os.LookupEnv":   true,
+	"os.Environ":     true,
+	"os.ExpandEnv":   true,
+	"syscall.Getenv": true,
+}
+
+// fsCalls touch the filesystem
+var fsCalls = map[string]bool{
+	"os.Open":          true,
+	"os.OpenFile":      true,
+	"os.Create":        true,
+	"os.CreateTemp":    true,
+	"os.ReadFile":      true,
+	"os.WriteFile":     true,
+	"os.ReadDir":       true,
+	"os.Mkdir":         true,
+	"os.MkdirAll":      true,
+	"os.MkdirTemp":     true,
+	"os.Remove":        true,
+	"os.RemoveAll":     true,
+	"os.Rename":        true,
+	"os.Stat":          true,
+	"os.Lstat":         true,
+	"os.Chdir":         true,
+	"os.Chmod":         true,
+	"os.Getwd":         true,
+	"os.DirFS":         true,
+	"ioutil.ReadFile":  true,
+	"ioutil.WriteFile": true,
+	"ioutil.ReadDir":   true,
+	"ioutil.TempFile":  true,
+	"ioutil.TempDir":   true,
+	"filepath.Walk":    true,
+	"filepath.WalkDir": true,
+	"filepath.Glob":    true,
+}
+
+// networkPackages reach the network with any call
+var networkPackages = map[string]bool{
+	"net":  true,
+	"smtp": true,
+	"rpc":  true,
+}
+
+// networkCalls reach the network from packages that also have offline helpers
+var networkCalls = map[string]bool{
+	"http.Get":               true,
+	"http.Head":              true,
+	"http.Post":              true,
+	"http.PostForm":          true,
+	"http.ListenAndServe":    true,
+	"http.ListenAndServeTLS": true,
+	"http.Serve":             true,
+	"http.ServeTLS":          true,
+}
+
+// timeCalls depend on the wall clock or wait on it
+var timeCalls = map[string]bool{
+	"time.Now":       true,
+	"time.Since":     true,
+	"time.Until":     true,
+	"time.Sleep":     true,
+	"time.After":     true,
+	"time.AfterFunc": true,
+	"time.Tick":      true,
+	"time.NewTimer":  true,
+	"time.NewTicker": true,
+}
+
+// extractSideEffects records whether a function reads the environment, touches the filesystem,
+// reaches the network or depends on the clock, judged by the standard library calls it makes
+func (e *AnalysisEngine) extractSideEffects(funcDecl *ast.FuncDecl, function *models.Function) {
+	// HTTP clients found by extractHTTPUsage reach the network whatever they are called through
+	if function.HTTP != nil {
+		function.UsesNetwork = true
+	}
+
+	if funcDecl.Body == nil {
+		return
+	}
+
+	ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		selector, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		pkg, ok := selector.X.(*ast.Ident)
+		if !ok {
+			return true
+		}
+
+		name := pkg.Name + "." + selector.Sel.Name
+		switch {
+		case envCalls[name]:
+			function.ReadsEnv = true
+		case fsCalls[name]:
+			function.TouchesFS = true
+		case networkPackages[pkg.Name] || networkCalls[name]:
+			function.UsesNetwork = true
+		case timeCalls[name]:
+			function.UsesTime = true
+		}
+		return true
+	})
+}
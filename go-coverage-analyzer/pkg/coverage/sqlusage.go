@@ -0,0 +1,224 @@
+package coverage
+
+import (
+	"go/ast"
+	"go/token"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/beck/go-coverage-analyzer/pkg/models"
+)
+
+// sqlStatementPattern matches string literals that look like SQL statements
+var sqlStatementPattern = regexp.MustCompile(`(?is)^\s*(SELECT|INSERT|UPDATE|DELETE|WITH|REPLACE|UPSERT|MERGE|CREATE|DROP|ALTER|TRUNCATE)\b`)
+
+// sqlSelectColumns captures the select list of a SELECT statement
+var sqlSelectColumns = regexp.MustCompile(`(?is)^\s*SELECT\s+(?:DISTINCT\s+)?(.+?)\s+FROM\s`)
+
+// sqlColumnAlias captures an explicit column alias
+var sqlColumnAlias = regexp.MustCompile(`(?i)\s+AS\s+(\S+)$`)
+
+// sqlField is a struct field holding a database handle
+type sqlField struct {
+	name string
+	tx   bool
+}
+
+// sqlHandleType reports whether a type is a database/sql handle and whether it is a transaction
+func sqlHandleType(typeName string) (bool, bool) {
+	switch typeName {
+	case "*sql.DB":
+		return true, false
+	case "*sql.Tx":
+		return true, true
+	}
+	return false, false
+}
+
+// extractSQLUsage records the database handle parameter and SQL statements of a function
+func (e *AnalysisEngine) extractSQLUsage(funcDecl *ast.FuncDecl, function *models.Function) {
+	queries := extractSQLQueries(funcDecl)
+
+	for _, param := range function.Parameters {
+		if ok, tx := sqlHandleType(param.Type); ok && param.Name != "_" {
+			function.SQL = &models.SQLUsage{Param: param.Name, Tx: tx, Queries: queries}
+			return
+		}
+	}
+
+	// Methods may reach the database through a receiver field, resolved once all structs are known
+	if function.IsMethod && len(queries) > 0 {
+		function.SQL = &models.SQLUsage{Queries: queries}
+	}
+}
+
+// collectSQLFields remembers struct fields of a file that hold a database handle
+func (e *AnalysisEngine) collectSQLFields(file *ast.File) {
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.TYPE {
+			continue
+		}
+
+		for _, spec := range genDecl.Specs {
+			typeSpec, ok := spec.(*ast.TypeSpec)
+			if !ok {
+				continue
+			}
+			structType, ok := typeSpec.Type.(*ast.StructType)
+			if !ok {
+				continue
+			}
+
+			for _, field := range structType.Fields.List {
+				ok, tx := sqlHandleType(e.extractTypeName(field.Type))
+				if !ok {
+					continue
+				}
+
+				// Embedded handles are addressed by their type name
+				name := strings.TrimPrefix(e.extractTypeName(field.Type), "*sql.")
+				if len(field.Names) > 0 {
+					name = field.Names[0].Name
+				}
+				e.sqlFields[file.Name.Name+"."+typeSpec.Name.Name] = &sqlField{name: name, tx: tx}
+				break
+			}
+		}
+	}
+}
+
+// applySQLReceivers links methods with SQL statements to the database field of their receiver
+func (e *AnalysisEngine) applySQLReceivers(packages map[string]*models.Package) {
+	for _, pkg := range packages {
+		for _, file := range pkg.Files {
+			for _, function := range file.Functions {
+				if function.SQL == nil || function.SQL.Param != "" {
+					continue
+				}
+
+				field, exists := e.sqlFields[pkg.Name+"."+strings.TrimPrefix(function.ReceiverType, "*")]
+				if !exists {
+					function.SQL = nil
+					continue
+				}
+				function.SQL.Field = field.name
+				function.SQL.Tx = field.tx
+			}
+		}
+	}
+}
+
+// extractSQLQueries returns the SQL statements written as string literals in a function body
+func extractSQLQueries(funcDecl *ast.FuncDecl) []*models.SQLQuery {
+	if funcDecl.Body == nil {
+		return nil
+	}
+
+	// The database method a literal is passed to decides between Query and Exec
+	execLiterals := make(map[token.Pos]bool)
+	ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		selector, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+
+		method := selector.Sel.Name
+		if !strings.HasPrefix(method, "Exec") && !strings.HasPrefix(method, "Query") {
+			return true
+		}
+		for _, arg := range call.Args {
+			if lit, ok := arg.(*ast.BasicLit); ok && lit.Kind == token.STRING {
+				execLiterals[lit.Pos()] = strings.HasPrefix(method, "Exec")
+			}
+		}
+		return true
+	})
+
+	var queries []*models.SQLQuery
+	ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
+		lit, ok := n.(*ast.BasicLit)
+		if !ok || lit.Kind != token.STRING {
+			return true
+		}
+
+		statement, err := strconv.Unquote(lit.Value)
+		if err != nil {
+			return true
+		}
+		// The keyword is taken from the literal as written, before trimming
+		matches := sqlStatementPattern.FindStringSubmatch(statement)
+		if matches == nil {
+			return true
+		}
+		statement = strings.TrimSpace(statement)
+
+		exec, known := execLiterals[lit.Pos()]
+		if !known {
+			keyword := strings.ToUpper(matches[1])
+			exec = keyword != "SELECT" && keyword != "WITH"
+		}
+
+		query := &models.SQLQuery{Statement: statement, Exec: exec}
+		if !exec {
+			query.Columns = selectColumns(statement)
+		}
+		queries = append(queries, query)
+		return true
+	})
+
+	return queries
+}
+
+// selectColumns infers the column names a SELECT statement returns, or nil when they are unclear
+func selectColumns(statement string) []string {
+	matches := sqlSelectColumns.FindStringSubmatch(statement)
+	if matches == nil {
+		return nil
+	}
+
+	// Split the select list on commas outside of function calls
+	var parts []string
+	depth, start := 0, 0
+	list := matches[1]
+	for i, r := range list {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, list[start:i])
+				start = i + 1
+			}
+		}
+	}
+	parts = append(parts, list[start:])
+
+	columns := make([]string, 0, len(parts))
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		if part == "*" || strings.HasSuffix(part, ".*") {
+			return nil
+		}
+
+		column := part
+		if alias := sqlColumnAlias.FindStringSubmatch(part); alias != nil {
+			column = alias[1]
+		} else if fields := strings.Fields(part); len(fields) > 1 {
+			column = fields[len(fields)-1]
+		}
+		if idx := strings.LastIndex(column, "."); idx >= 0 {
+			column = column[idx+1:]
+		}
+		columns = append(columns, strings.Trim(column, "`\"[]"))
+	}
+
+	return columns
+}
@@ -0,0 +1,75 @@
+package coverage
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/beck/go-coverage-analyzer/pkg/models"
+)
+
+func TestExtractSQLQueries(t *testing.T) {
+	tests := []struct {
+		name    string
+		literal string
+		want    *models.SQLQuery // nil when the literal is no SQL statement
+	}{
+		{
+			name:    "select",
+			literal: "SELECT id, name AS n FROM users WHERE id = $1",
+			want:    &models.SQLQuery{Statement: "SELECT id, name AS n FROM users WHERE id = $1", Columns: []string{"id", "n"}},
+		},
+		{
+			name:    "leading and trailing whitespace",
+			literal: "\n\tupdate users SET name = $1\n",
+			want:    &models.SQLQuery{Statement: "update users SET name = $1", Exec: true},
+		},
+		{
+			name:    "keyword with trailing space",
+			literal: "DELETE ",
+			want:    &models.SQLQuery{Statement: "DELETE", Exec: true},
+		},
+		{
+			name:    "keyword with trailing newline",
+			literal: "update\n",
+			want:    &models.SQLQuery{Statement: "update", Exec: true},
+		},
+		{
+			name:    "keyword only",
+			literal: "WITH",
+			want:    &models.SQLQuery{Statement: "WITH"},
+		},
+		{
+			name:    "word starting with a keyword",
+			literal: "deleted",
+		},
+		{
+			name:    "whitespace only",
+			literal: " \n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := "package p\n\nfunc f() string {\n\treturn " + strconv.Quote(tt.literal) + "\n}\n"
+			file, err := parser.ParseFile(token.NewFileSet(), "p.go", src, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			queries := extractSQLQueries(file.Decls[0].(*ast.FuncDecl))
+			if tt.want == nil {
+				if len(queries) != 0 {
+					t.Errorf("extractSQLQueries() = %+v, want none", queries[0])
+				}
+				return
+			}
+			if len(queries) != 1 || !reflect.DeepEqual(queries[0], tt.want) {
+				t.Errorf("extractSQLQueries() = %+v, want %+v", queries, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package coverage
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/beck/go-coverage-analyzer/internal/i18n"
+	"github.com/beck/go-coverage-analyzer/pkg/models"
+)
+
+// StreamProfile reads a coverage profile one line at a time, for profiles too large to hold in memory.
+// Statement totals are aggregated for every file as blocks are read, but blocks are only retained for
+// the files keep selects. Blocks are validated as they are read, so the result needs no ValidateProfile
+func (p *ProfileParser) StreamProfile(profilePath string, keep func(fileName string) bool) (*models.CoverageProfile, error) {
+	file, err := os.Open(profilePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open profile file %s: %w", profilePath, err)
+	}
+	defer file.Close()
+
+	profile := &models.CoverageProfile{
+		Blocks: make([]*models.ProfileBlock, 0),
+		Files:  make(map[string]*models.FileProfile),
+	}
+	kept := make(map[string]bool)
+
+	scanner := bufio.NewScanner(file)
+	lineNum := 0
+	totalBlocks := 0
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		lineNum++
+
+		if lineNum == 1 && strings.HasPrefix(line, "mode: ") {
+			profile.Mode = strings.TrimPrefix(line, "mode: ")
+			switch profile.Mode {
+			case "set", "count", "atomic":
+			default:
+				return nil, fmt.Errorf("invalid coverage mode: %s", profile.Mode)
+			}
+			continue
+		}
+
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		block, err := p.parseProfileLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse line %d: %w", lineNum, err)
+		}
+		if err := p.validateBlock(block); err != nil {
+			return nil, fmt.Errorf("invalid block on line %d: %w", lineNum, err)
+		}
+		totalBlocks++
+
+		fileProfile, exists := profile.Files[block.FileName]
+		if !exists {
+			fileProfile = &models.FileProfile{FileName: block.FileName}
+			profile.Files[block.FileName] = fileProfile
+			kept[block.FileName] = keep(block.FileName)
+		}
+
+		fileProfile.TotalStmts += block.NumStmts
+		if block.Count > 0 {
+			fileProfile.CoveredStmts += block.NumStmts
+		}
+
+		if kept[block.FileName] {
+			// Share the map key so retained blocks do not each hold on to their line
+			block.FileName = fileProfile.FileName
+			fileProfile.Blocks = append(fileProfile.Blocks, block)
+			profile.Blocks = append(profile.Blocks, block)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading profile file: %w", err)
+	}
+	if profile.Mode == "" {
+		return nil, fmt.Errorf("profile mode is empty")
+	}
+	if totalBlocks == 0 {
+		return nil, fmt.Errorf("profile contains no coverage blocks")
+	}
+
+	for _, fileProfile := range profile.Files {
+		if fileProfile.TotalStmts > 0 {
+			fileProfile.Coverage = float64(fileProfile.CoveredStmts) / float64(fileProfile.TotalStmts) * 100.0
+		}
+	}
+
+	if p.verbose {
+		i18n.Printf("📊 Streamed profile: %d blocks across %d files, kept %d blocks\n", totalBlocks, len(profile.Files), len(profile.Blocks))
+	}
+
+	return profile, nil
+}
+
+// keepProfileFile returns whether the blocks of a profile file are needed: files in the project and
+// in the analysis scope that the include and exclude patterns and the profile package pattern select
+func (e *AnalysisEngine) keepProfileFile(opts *AnalysisOptions) func(fileName string) bool {
+	return func(fileName string) bool {
+		relPath, ok := e.modules.Resolve(fileName)
+		if !ok || !e.scope.contains(relPath) {
+			return false
+		}
+
+		relDir := path.Dir(relPath)
+		if !MatchPackages(relDir, opts.IncludePackages, opts.ExcludePackages) {
+			return false
+		}
+
+		// The profile package pattern may be relative, like ./internal/..., or an import path pattern
+		return opts.PackagePattern == "" || MatchPackagePattern(opts.PackagePattern, relDir) ||
+			MatchPackagePattern(opts.PackagePattern, path.Dir(fileName))
+	}
+}
@@ -0,0 +1,368 @@
+package coverage
+
+import (
+	"go/ast"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/beck/go-coverage-analyzer/pkg/models"
+)
+
+// testMetrics counts the test functions, assertions and lines of the test files in a directory
+type testMetrics struct {
+	files      int
+	tests      int
+	assertions int
+	lines      int
+	functions  []*testFunction
+}
+
+// testFunction is a test, with the assertions it makes and the names of the functions it calls
+type testFunction struct {
+	name       string
+	file       string
+	line       int
+	assertions int
+	calls      map[string]bool
+}
+
+// collectTestMetrics scans all test files and records, per directory, how much testing they do
+func (e *AnalysisEngine) collectTestMetrics(projectPath string, excludeDirs []string) (map[string]*testMetrics, error) {
+	metrics := make(map[string]*testMetrics)
+
+	excludeMap := make(map[string]bool)
+	for _, dir := range excludeDirs {
+		excludeMap[dir] = true
+	}
+
+	// Tests sit next to the code they test, so a scoped analysis only walks the scope
+	root := e.scope.root(projectPath)
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			relDir, _ := filepath.Rel(projectPath, path)
+			if excludeMap[info.Name()] || path != root && strings.HasPrefix(info.Name(), ".") || !e.scope.walkDir(relDir) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if !strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+
+		src, file, err := e.parseFile(path, 0)
+		if file == nil {
+			return err
+		}
+		relPath, _ := filepath.Rel(projectPath, path)
+		if err != nil {
+			// Unparseable test files count as neither tests nor test code
+			e.warn("Skipping %s, it does not parse: %v", relPath, err)
+			return nil
+		}
+
+		dir := filepath.Dir(relPath)
+		if metrics[dir] == nil {
+			metrics[dir] = &testMetrics{}
+		}
+		dirMetrics := metrics[dir]
+		dirMetrics.files++
+		dirMetrics.lines += len(strings.Split(string(src), "\n"))
+
+		helpers := FailingHelpers(file)
+		for _, decl := range file.Decls {
+			funcDecl, ok := decl.(*ast.FuncDecl)
+			if !ok || !IsTestName(funcDecl.Name.Name) || funcDecl.Body == nil {
+				continue
+			}
+
+			test := &testFunction{
+				name:       funcDecl.Name.Name,
+				file:       relPath,
+				line:       e.fset.Position(funcDecl.Pos()).Line,
+				assertions: CountAssertions(funcDecl.Body, helpers),
+				calls:      calledNames(funcDecl.Body),
+			}
+			dirMetrics.tests++
+			dirMetrics.assertions += test.assertions
+			dirMetrics.functions = append(dirMetrics.functions, test)
+		}
+		return nil
+	})
+
+	return metrics, err
+}
+
+// applyTestMetrics records the test depth of every package, comparing its test code with the lines
+// of its production files, and totals it in the summary
+func (e *AnalysisEngine) applyTestMetrics(result *models.AnalysisResult, metrics map[string]*testMetrics) {
+	summary := result.Summary
+
+	for _, pkg := range result.PackageCoverage {
+		for _, file := range pkg.Files {
+			if file.Class == "" || file.Class == models.FileClassProduction {
+				pkg.CodeLines += file.TotalLines
+			}
+		}
+
+		if dirMetrics := metrics[pkg.Path]; dirMetrics != nil {
+			pkg.TestFiles = dirMetrics.files
+			pkg.TestFunctions = dirMetrics.tests
+			pkg.Assertions = dirMetrics.assertions
+			pkg.TestLines = dirMetrics.lines
+		}
+		if pkg.TestFunctions > 0 {
+			pkg.AssertionsPerTest = float64(pkg.Assertions) / float64(pkg.TestFunctions)
+		}
+		if pkg.CodeLines > 0 {
+			pkg.TestToCodeRatio = float64(pkg.TestLines) / float64(pkg.CodeLines)
+		}
+
+		summary.TotalTestFiles += pkg.TestFiles
+		summary.TestFunctions += pkg.TestFunctions
+		summary.Assertions += pkg.Assertions
+		summary.TestLines += pkg.TestLines
+		summary.CodeLines += pkg.CodeLines
+	}
+
+	if summary.TestFunctions > 0 {
+		summary.AssertionsPerTest = float64(summary.Assertions) / float64(summary.TestFunctions)
+	}
+	if summary.CodeLines > 0 {
+		summary.TestToCodeRatio = float64(summary.TestLines) / float64(summary.CodeLines)
+	}
+
+	e.applyWeakCoverage(result, metrics)
+}
+
+// applyWeakCoverage lists the tests that assert nothing and marks the covered functions only such
+// tests call as weakly covered: their coverage shows the code ran, not that anything checked it
+func (e *AnalysisEngine) applyWeakCoverage(result *models.AnalysisResult, metrics map[string]*testMetrics) {
+	freeTests := make([]*models.AssertionFreeTest, 0)
+	weak := make([]*models.Function, 0)
+
+	for _, pkg := range result.PackageCoverage {
+		dirMetrics := metrics[pkg.Path]
+		if dirMetrics == nil {
+			continue
+		}
+
+		// Functions are matched to calls by name, as the test references are
+		asserted := make(map[string]bool)
+		unasserted := make(map[string]bool)
+		for _, test := range dirMetrics.functions {
+			for name := range test.calls {
+				if test.assertions > 0 {
+					asserted[name] = true
+				} else {
+					unasserted[name] = true
+				}
+			}
+		}
+
+		for _, test := range dirMetrics.functions {
+			if test.assertions > 0 {
+				continue
+			}
+
+			freeTest := &models.AssertionFreeTest{
+				Name:    test.name,
+				File:    test.file,
+				Line:    test.line,
+				Package: pkg.Name,
+				Calls:   make([]string, 0),
+			}
+			for _, file := range pkg.Files {
+				for _, function := range file.Functions {
+					if test.calls[function.Name] {
+						freeTest.Calls = append(freeTest.Calls, function.Key())
+					}
+				}
+			}
+			sort.Strings(freeTest.Calls)
+
+			freeTests = append(freeTests, freeTest)
+			pkg.AssertionFreeTests++
+		}
+
+		for _, file := range pkg.Files {
+			for _, function := range file.Functions {
+				if function.IsCovered && unasserted[function.Name] && !asserted[function.Name] {
+					function.WeaklyCovered = true
+					weak = append(weak, function)
+					pkg.WeaklyCoveredFunctions++
+				}
+			}
+		}
+	}
+
+	sort.Slice(freeTests, func(i, j int) bool {
+		if freeTests[i].File != freeTests[j].File {
+			return freeTests[i].File < freeTests[j].File
+		}
+		return freeTests[i].Line < freeTests[j].Line
+	})
+	sort.Slice(weak, func(i, j int) bool {
+		if weak[i].File != weak[j].File {
+			return weak[i].File < weak[j].File
+		}
+		return weak[i].StartLine < weak[j].StartLine
+	})
+
+	result.AssertionFreeTests = freeTests
+	result.WeaklyCoveredFunctions = weak
+	result.Summary.AssertionFreeTests = len(freeTests)
+	result.Summary.WeaklyCoveredFunctions = len(weak)
+}
+
+// calledNames returns the names of the functions and methods called in a body
+func calledNames(body ast.Node) map[string]bool {
+	names := make(map[string]bool)
+	ast.Inspect(body, func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpr); ok {
+			switch fun := call.Fun.(type) {
+			case *ast.Ident:
+				names[fun.Name] = true
+			case *ast.SelectorExpr:
+				names[fun.Sel.Name] = true
+			}
+		}
+		return true
+	})
+	return names
+}
+
+// IsTestName reports whether go test runs a function of this name as a test: Test followed by
+// nothing or by a character that is not a lowercase letter, TestMain excluded
+func IsTestName(name string) bool {
+	if !strings.HasPrefix(name, "Test") || name == "TestMain" {
+		return false
+	}
+	if len(name) == len("Test") {
+		return true
+	}
+	next, _ := utf8.DecodeRuneInString(name[len("Test"):])
+	return !unicode.IsLower(next)
+}
+
+// CountAssertions counts the calls in a test body that fail the test when a check does not hold,
+// including calls to the failing helpers of its file
+func CountAssertions(body ast.Node, helpers map[string]bool) int {
+	assertions := 0
+	ast.Inspect(body, func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpr); ok {
+			if ident, ok := call.Fun.(*ast.Ident); ok && helpers[ident.Name] || IsAssertion(call) {
+				assertions++
+			}
+		}
+		return true
+	})
+	return assertions
+}
+
+// IsAssertion reports whether a call fails the test when a check does not hold: t.Error and its
+// siblings, testify's assert and require, and helpers named after errors or failures
+func IsAssertion(call *ast.CallExpr) bool {
+	switch fun := call.Fun.(type) {
+	case *ast.Ident:
+		return strings.Contains(fun.Name, "Error") || strings.Contains(fun.Name, "Fail") || strings.Contains(fun.Name, "Fatal")
+	case *ast.SelectorExpr:
+		if ident, ok := fun.X.(*ast.Ident); ok {
+			switch ident.Name {
+			case "assert", "require":
+				return true
+			case "fmt", "errors":
+				return false
+			}
+		}
+		switch fun.Sel.Name {
+		case "Error", "Errorf", "Fatal", "Fatalf", "Fail", "FailNow":
+			return true
+		}
+	}
+	return false
+}
+
+// FailingHelpers returns the functions of a test file that fail the test they are given, so calls to
+// them count as assertions
+func FailingHelpers(file *ast.File) map[string]bool {
+	helpers := make(map[string]bool)
+	for _, decl := range file.Decls {
+		if funcDecl, ok := decl.(*ast.FuncDecl); ok && funcDecl.Recv == nil {
+			if _, fails, _ := HelperFailures(funcDecl); fails {
+				helpers[funcDecl.Name.Name] = true
+			}
+		}
+	}
+	return helpers
+}
+
+// HelperFailures reports whether a helper fails the test through one of its *testing.T, *testing.B or
+// testing.TB parameters, which one, and whether it marks itself with Helper
+func HelperFailures(funcDecl *ast.FuncDecl) (param string, fails, helper bool) {
+	name := funcDecl.Name.Name
+	if funcDecl.Body == nil || name == "TestMain" || strings.HasPrefix(name, "Test") ||
+		strings.HasPrefix(name, "Benchmark") || strings.HasPrefix(name, "Example") || strings.HasPrefix(name, "Fuzz") {
+		return "", false, false
+	}
+
+	for _, param := range testingParams(funcDecl) {
+		fails, helper := false, false
+		ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			selExpr, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+			if ident, ok := selExpr.X.(*ast.Ident); ok && ident.Name == param {
+				switch selExpr.Sel.Name {
+				case "Helper":
+					helper = true
+				case "Error", "Errorf", "Fatal", "Fatalf", "Fail", "FailNow":
+					fails = true
+				}
+			}
+			return true
+		})
+		if fails {
+			return param, fails, helper
+		}
+	}
+	return "", false, false
+}
+
+// testingParams returns the names of a function's *testing.T, *testing.B and testing.TB parameters
+func testingParams(funcDecl *ast.FuncDecl) []string {
+	names := make([]string, 0)
+	for _, field := range funcDecl.Type.Params.List {
+		typ := field.Type
+		if star, ok := typ.(*ast.StarExpr); ok {
+			typ = star.X
+		}
+		selExpr, ok := typ.(*ast.SelectorExpr)
+		if !ok {
+			continue
+		}
+		if pkg, ok := selExpr.X.(*ast.Ident); !ok || pkg.Name != "testing" {
+			continue
+		}
+		switch selExpr.Sel.Name {
+		case "T", "B", "TB":
+			for _, name := range field.Names {
+				names = append(names, name.Name)
+			}
+		}
+	}
+	return names
+}
@@ -0,0 +1,131 @@
+package coverage
+
+import (
+	"go/ast"
+	"path"
+	"path/filepath"
+	"sort"
+
+	"github.com/beck/go-coverage-analyzer/pkg/models"
+)
+
+// excludedFunctions returns the functions of a helper or generated file left out of coverage, marked
+// untestable for the class of the file. Functions of other excluded classes, tests and vendored code,
+// are not the project's code to test and go unreported
+func excludedFunctions(file *models.File) []*models.Function {
+	var reason string
+	switch file.Class {
+	case models.FileClassHelper:
+		reason = models.UntestableHelper
+	case models.FileClassGenerated:
+		reason = models.UntestableGenerated
+	default:
+		return nil
+	}
+
+	functions := make([]*models.Function, 0, len(file.Functions))
+	for _, function := range file.Functions {
+		function.IsTestable = false
+		function.UntestableReason = reason
+		functions = append(functions, function)
+	}
+	return functions
+}
+
+// importsC reports whether a file uses cgo, whose functions the coverage profile does not measure
+// reliably
+func importsC(file *ast.File) bool {
+	for _, spec := range file.Imports {
+		if spec.Path != nil && spec.Path.Value == `"C"` {
+			return true
+		}
+	}
+	return false
+}
+
+// markMatchingFunctions marks the testable functions matching any of the patterns untestable for the
+// reason given. Patterns match the name or Receiver.Name of a function, with the wildcards of path.Match
+func markMatchingFunctions(packages map[string]*models.Package, patterns []string, reason string) {
+	if len(patterns) == 0 {
+		return
+	}
+
+	for _, pkg := range packages {
+		for _, file := range pkg.Files {
+			for _, function := range file.Functions {
+				if function.IsTestable && MatchFunctionPatterns(patterns, function) {
+					function.IsTestable = false
+					function.UntestableReason = reason
+				}
+			}
+		}
+	}
+}
+
+// exemptFiles drops the files matching any of the exempted file patterns, and packages left without
+// files, returning their testable functions marked untestable as exempted
+func exemptFiles(packages map[string]*models.Package, patterns []string) []*models.Function {
+	if len(patterns) == 0 {
+		return nil
+	}
+
+	var exempted []*models.Function
+	for name, pkg := range packages {
+		for filePath, file := range pkg.Files {
+			if !MatchFilePatterns(patterns, filepath.ToSlash(file.Path)) {
+				continue
+			}
+			for _, function := range file.Functions {
+				if function.IsTestable {
+					function.IsTestable = false
+					function.UntestableReason = models.UntestableExempted
+					exempted = append(exempted, function)
+				}
+			}
+			delete(pkg.Files, filePath)
+		}
+		if len(pkg.Files) == 0 {
+			delete(packages, name)
+		}
+	}
+	return exempted
+}
+
+// MatchFunctionPatterns reports whether the name or Receiver.Name of a function matches any pattern
+func MatchFunctionPatterns(patterns []string, function *models.Function) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, function.Name); matched {
+			return true
+		}
+		if matched, _ := path.Match(pattern, function.Key()); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// applyUntestableFunctions records the functions left out of the function totals, those of the
+// analyzed files and those of the helper and generated files left out of coverage, by file and line
+func (e *AnalysisEngine) applyUntestableFunctions(result *models.AnalysisResult, excluded []*models.Function) {
+	untestable := append([]*models.Function{}, excluded...)
+
+	for _, pkg := range result.PackageCoverage {
+		for _, file := range pkg.Files {
+			for _, function := range file.Functions {
+				if !function.IsTestable {
+					untestable = append(untestable, function)
+				}
+			}
+		}
+	}
+
+	sort.Slice(untestable, func(i, j int) bool {
+		if untestable[i].File != untestable[j].File {
+			return untestable[i].File < untestable[j].File
+		}
+		return untestable[i].StartLine < untestable[j].StartLine
+	})
+
+	result.UntestableFunctions = untestable
+	result.Summary.UntestableFunctions = len(untestable)
+}
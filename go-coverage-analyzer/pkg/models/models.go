@@ -1,21 +1,91 @@
 package models
 
 import (
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 )
 
 // AnalysisResult represents the complete result of coverage analysis
 type AnalysisResult struct {
+	SchemaVersion      string              `json:"schema_version"` // see SchemaVersion
 	ProjectPath        string              `json:"project_path"`
 	Timestamp          time.Time           `json:"timestamp"`
 	OverallCoverage    float64             `json:"overall_coverage"`
 	FunctionCoverage   float64             `json:"function_coverage"`
 	BranchCoverage     float64             `json:"branch_coverage"`
 	LineCoverage       float64             `json:"line_coverage"`
+	APICoverage        float64             `json:"api_coverage"`
 	PackageCoverage    map[string]*Package `json:"packages"`
 	UncoveredFunctions []*Function         `json:"uncovered_functions"`
 	Summary            *Summary            `json:"summary"`
 	Metadata           *Metadata           `json:"metadata"`
+
+	// Findings derived from test and call-site scanning
+	UntestedAPI           []*Function `json:"untested_api,omitempty"`
+	PossiblyDeadFunctions []*Function `json:"possibly_dead_functions,omitempty"`
+
+	// Functions left out of the function totals, with the reason why
+	UntestableFunctions []*Function `json:"untestable_functions,omitempty"`
+
+	// Ownership dimension, populated when grouping by owner
+	OwnerCoverage []*OwnerCoverage `json:"owner_coverage,omitempty"`
+
+	// Uncovered functions by the author of most of their lines, populated when blaming uncovered code
+	AuthorCoverage []*AuthorCoverage `json:"author_coverage,omitempty"`
+
+	// Exemptions from the thresholds and whether they still hold, populated when exemptions are listed
+	Exemptions []*Exemption `json:"exemptions,omitempty"`
+
+	// Coverage of the configured feature areas, populated when areas are configured
+	AreaCoverage []*AreaCoverage `json:"area_coverage,omitempty"`
+
+	// Coverage of the methods of each receiver type, by package and type name
+	TypeCoverage []*TypeCoverage `json:"type_coverage,omitempty"`
+
+	// Coverage of init functions and package-level variable initializers, populated when included
+	Initialization *InitializationCoverage `json:"initialization,omitempty"`
+
+	// Tests that assert nothing, and the covered functions only they call
+	AssertionFreeTests     []*AssertionFreeTest `json:"assertion_free_tests,omitempty"`
+	WeaklyCoveredFunctions []*Function          `json:"weakly_covered_functions,omitempty"`
+
+	// Functions each top-level test covers when run on its own, populated when attributing tests
+	TestMatrix []*TestAttribution `json:"test_matrix,omitempty"`
+
+	// Source files modified after the coverage profile was written, populated when the profile is stale
+	StaleProfile *StaleProfile `json:"stale_profile,omitempty"`
+
+	// Problems that left the analysis incomplete without failing it, which --strict fails on
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// StaleProfile records the source files modified after an existing coverage profile was written,
+// whose coverage it may misreport
+type StaleProfile struct {
+	ProfileModified time.Time `json:"profile_modified"`
+	ModifiedFiles   []string  `json:"modified_files"` // relative to the project, most recently modified first
+}
+
+// TestAttribution is what a top-level test covers of its package when it runs on its own
+type TestAttribution struct {
+	Test      string   `json:"test"`
+	Package   string   `json:"package"`
+	File      string   `json:"file"`
+	Line      int      `json:"line"`
+	Passed    bool     `json:"passed"`
+	Functions []string `json:"functions"` // Receiver.Name for methods, Name otherwise
+}
+
+// AssertionFreeTest is a test function that makes no assertion, so the code it runs counts as
+// covered without being checked
+type AssertionFreeTest struct {
+	Name    string   `json:"name"`
+	File    string   `json:"file"`
+	Line    int      `json:"line"`
+	Package string   `json:"package"`
+	Calls   []string `json:"calls"` // functions of the package it calls, Receiver.Name for methods
 }
 
 // Package represents coverage information for a Go package
@@ -33,6 +103,18 @@ type Package struct {
 	TotalFunctions   int              `json:"total_functions"`
 	CoveredFunctions int              `json:"covered_functions"`
 	Complexity       int              `json:"complexity"`
+
+	// Test depth, from the _test.go files in the package directory
+	TestFiles         int     `json:"test_files"`
+	TestFunctions     int     `json:"test_functions"`
+	Assertions        int     `json:"assertions"`
+	AssertionsPerTest float64 `json:"assertions_per_test"`
+	TestLines         int     `json:"test_lines"`
+	CodeLines         int     `json:"code_lines"`         // source lines of the production files
+	TestToCodeRatio   float64 `json:"test_to_code_ratio"` // test lines per production line
+
+	AssertionFreeTests     int `json:"assertion_free_tests"`
+	WeaklyCoveredFunctions int `json:"weakly_covered_functions"`
 }
 
 // File represents coverage information for a Go source file
@@ -51,40 +133,273 @@ type File struct {
 	CoverageBlocks   []*Block    `json:"coverage_blocks"`
 	Complexity       int         `json:"complexity"`
 	HasTests         bool        `json:"has_tests"`
+	Class            string      `json:"class"` // production, test, helper, generated or vendored
 	TestFiles        []string    `json:"test_files,omitempty"`
+	Owners           []string    `json:"owners,omitempty"`
+	Cgo              bool        `json:"cgo,omitempty"` // imports "C"
+
+	// Package-level variables initialized with function literals, recorded when initialization is included
+	Initializers []*Initializer `json:"initializers,omitempty"`
 }
 
+// Reasons a function is not testable, and so left out of the function totals
+const (
+	UntestableMain       = "main"            // the main function of a command
+	UntestableInit       = "init"            // package initialization
+	UntestableTest       = "test"            // Test, Benchmark and Example functions
+	UntestableHelper     = "test-helper"     // declared in a test helper file
+	UntestableGenerated  = "generated"       // declared in a generated file
+	UntestableUnexported = "unexported"      // unexported, and unexported functions are not included
+	UntestableIgnored    = "ignored-pattern" // matches a pattern of ignore_functions
+	UntestableExempted   = "exempted"        // matches an unexpired exemption, see Exemption
+
+	// Not instrumentable: the coverage profile cannot tell whether they ran
+	UntestableAssembly = "assembly" // declared without a body, implemented in a .s file
+	UntestableCgo      = "cgo"      // declared in a file importing "C"
+)
+
+// ProgressFunc is told that done of total units of a stage, such as parsing files, are finished
+type ProgressFunc func(stage string, done, total int)
+
+// File classes, of which only production code counts towards coverage by default
+const (
+	FileClassProduction = "production"
+	FileClassTest       = "test"
+	FileClassHelper     = "helper"    // test helpers such as export_test.go or *_helpers.go
+	FileClassGenerated  = "generated" // files with a Code generated ... DO NOT EDIT header or matching generated patterns
+	FileClassVendored   = "vendored"
+)
+
 // Function represents a function or method that can be tested
 type Function struct {
-	Name           string   `json:"name"`
-	Signature      string   `json:"signature"`
-	File           string   `json:"file"`
-	Package        string   `json:"package"`
-	StartLine      int      `json:"start_line"`
-	EndLine        int      `json:"end_line"`
-	Coverage       float64  `json:"coverage"`
-	IsCovered      bool     `json:"is_covered"`
-	IsTestable     bool     `json:"is_testable"`
-	IsMethod       bool     `json:"is_method"`
-	IsExported     bool     `json:"is_exported"`
-	ReceiverType   string   `json:"receiver_type,omitempty"`
-	Parameters     []*Param `json:"parameters"`
-	ReturnTypes    []string `json:"return_types"`
-	Complexity     int      `json:"complexity"`
-	HasTests       bool     `json:"has_tests"`
-	TestFiles      []string `json:"test_files,omitempty"`
-	Dependencies   []string `json:"dependencies,omitempty"`
-	CallsExternal  bool     `json:"calls_external"`
-	HasErrorReturn bool     `json:"has_error_return"`
-	CanPanic       bool     `json:"can_panic"`
+	Name             string       `json:"name"`
+	Signature        string       `json:"signature"`
+	File             string       `json:"file"`
+	Package          string       `json:"package"`
+	StartLine        int          `json:"start_line"`
+	EndLine          int          `json:"end_line"`
+	Coverage         float64      `json:"coverage"`
+	IsCovered        bool         `json:"is_covered"`
+	IsTestable       bool         `json:"is_testable"`
+	UntestableReason string       `json:"untestable_reason,omitempty"` // why it is not testable, see Untestable constants
+	IsMethod         bool         `json:"is_method"`
+	IsExported       bool         `json:"is_exported"`
+	ReceiverType     string       `json:"receiver_type,omitempty"`
+	Parameters       []*Param     `json:"parameters"`
+	ReturnTypes      []string     `json:"return_types"`
+	Complexity       int          `json:"complexity"`
+	Lines            int          `json:"lines"`                 // lines of code, without blank and comment lines
+	ParamCount       int          `json:"param_count"`           // declared parameters, unnamed ones included
+	OverLimits       []string     `json:"over_limits,omitempty"` // function limits it exceeds, see Limit constants
+	HasTests         bool         `json:"has_tests"`
+	TestFiles        []string     `json:"test_files,omitempty"`
+	Dependencies     []string     `json:"dependencies,omitempty"`
+	CallsExternal    bool         `json:"calls_external"`
+	HasErrorReturn   bool         `json:"has_error_return"`
+	CanPanic         bool         `json:"can_panic"`
+	Concurrent       bool         `json:"concurrent"`            // spawns goroutines or uses channels or sync primitives
+	Fixtures         []string     `json:"fixtures,omitempty"`    // shared test fixtures the function needs, see Fixture constants
+	EnvVars          []string     `json:"env_vars,omitempty"`    // environment variables the function reads
+	Interfaces       []string     `json:"interfaces,omitempty"`  // named interface types among the parameters and results
+	Boundaries       []*Boundary  `json:"boundaries,omitempty"`  // comparisons of parameters against constants
+	ErrorPaths       []*ErrorPath `json:"error_paths,omitempty"` // returns that hand back a non-nil error
+	PanicPaths       []*PanicPath `json:"panic_paths,omitempty"` // statements that may panic, CanPanic is set when there are any
+	Callers          int          `json:"callers"`
+	PossiblyDead     bool         `json:"possibly_dead"`
+	WeaklyCovered    bool         `json:"weakly_covered"`   // covered, but only called by tests that assert nothing
+	Author           string       `json:"author,omitempty"` // of most of its lines by git blame, recorded for uncovered functions when blamed
+
+	// Statement counts from the coverage profile
+	Statements        int `json:"statements"`
+	CoveredStatements int `json:"covered_statements"`
+
+	// Lines of its blocks that never ran, recorded when reporting with block detail
+	UncoveredBlocks []*LineRange `json:"uncovered_blocks,omitempty"`
+
+	// Function literals within it, named Parent.func1 and Parent.func1.1 as the Go runtime does. Their
+	// statements also count towards the parent; closures are not testable on their own
+	Closures []*Function `json:"closures,omitempty"`
+	Launch   string      `json:"launch,omitempty"` // of a closure: go or defer when started by such a statement
+
+	// Top-level tests of its package that run it, recorded when attributing coverage to tests
+	CoveredBy []string `json:"covered_by,omitempty"`
+
+	// Quality and risk metrics
+	MaintainabilityIndex float64 `json:"maintainability_index"`
+	RiskScore            float64 `json:"risk_score"`
+
+	// Database access through database/sql, nil when the function has none
+	SQL *SQLUsage `json:"sql,omitempty"`
+
+	// HTTP calls through an *http.Client, nil when the function makes none
+	HTTP *HTTPUsage `json:"http,omitempty"`
+
+	// Function-typed fields of the receiver, stubbed when tests build it
+	FuncFields []*Param `json:"func_fields,omitempty"`
+
+	// Clock interfaces (with a Now() time.Time method) among the parameters and receiver fields,
+	// replaced by a frozen fake clock in tests
+	Clocks      []string `json:"clocks,omitempty"`
+	ClockFields []*Param `json:"clock_fields,omitempty"`
+
+	// Dependencies on the environment found among the standard library calls
+	ReadsEnv    bool `json:"reads_env"`
+	TouchesFS   bool `json:"touches_fs"`
+	UsesNetwork bool `json:"uses_network"`
+	UsesTime    bool `json:"uses_time"`
+}
+
+// HTTPUsage describes how a function reaches the *http.Client it sends requests with
+type HTTPUsage struct {
+	Param   string `json:"param,omitempty"` // parameter holding the client
+	Field   string `json:"field,omitempty"` // receiver field holding the client
+	Default bool   `json:"default"`         // uses http.DefaultClient or the http.Get family
+}
+
+// SQLUsage describes how a function reaches a *sql.DB or *sql.Tx and the statements it runs
+type SQLUsage struct {
+	Param   string      `json:"param,omitempty"` // parameter holding the handle
+	Field   string      `json:"field,omitempty"` // receiver field holding the handle
+	Tx      bool        `json:"tx"`              // the handle is a *sql.Tx rather than a *sql.DB
+	Queries []*SQLQuery `json:"queries,omitempty"`
+}
+
+// SQLQuery is a SQL statement found as a string literal in a function body
+type SQLQuery struct {
+	Statement string   `json:"statement"`
+	Exec      bool     `json:"exec"`              // run with Exec rather than Query
+	Columns   []string `json:"columns,omitempty"` // selected columns, when they can be inferred
+}
+
+// Boundary is a comparison of a parameter against a constant in a function body, like len(name) > 100
+type Boundary struct {
+	Param       string `json:"param"`
+	Length      bool   `json:"length,omitempty"`       // compares len(param) rather than the parameter itself
+	Op          string `json:"op"`                     // comparison with the parameter on the left: <, <=, >, >=, == or !=
+	Value       string `json:"value"`                  // Go literal the parameter is compared against
+	GuardsError bool   `json:"guards_error,omitempty"` // the comparison alone decides an error return
 }
 
+// ErrorPath is a return statement that hands back a non-nil error
+type ErrorPath struct {
+	Line      int       `json:"line"`
+	Condition string    `json:"condition,omitempty"` // source of the condition guarding the return, empty when unconditional
+	Guard     *Boundary `json:"guard,omitempty"`     // the condition as a constraint on one parameter, nil when inputs alone cannot take the branch
+	Covered   bool      `json:"covered"`             // executed by the existing tests
+}
+
+// PanicPath is a statement that may panic
+type PanicPath struct {
+	Line      int       `json:"line"`
+	Kind      string    `json:"kind"`                // see the Panic constants
+	Condition string    `json:"condition,omitempty"` // source of the condition under which it panics, empty when unconditional
+	Guard     *Boundary `json:"guard,omitempty"`     // the condition as a constraint on one parameter, nil when inputs alone cannot meet it
+}
+
+// Kinds of statements that may panic
+const (
+	PanicCall   = "panic"   // explicit call to panic
+	PanicNilMap = "nil_map" // write to a map that may be nil
+	PanicIndex  = "index"   // index expression without a length check
+)
+
+// CoveredErrorPaths returns how many of the function's error paths the existing tests execute
+func (f *Function) CoveredErrorPaths() int {
+	covered := 0
+	for _, path := range f.ErrorPaths {
+		if path.Covered {
+			covered++
+		}
+	}
+	return covered
+}
+
+// Key identifies the function within its package, Receiver.Name for methods and Name otherwise
+func (f *Function) Key() string {
+	if f.ReceiverType == "" {
+		return f.Name
+	}
+	return strings.TrimPrefix(f.ReceiverType, "*") + "." + f.Name
+}
+
+// TypeSignature renders the signature without parameter names, which renaming a parameter leaves unchanged,
+// e.g. func (*Config) Load(string) (int, error)
+func (f *Function) TypeSignature() string {
+	var sig strings.Builder
+	sig.WriteString("func ")
+	if f.ReceiverType != "" {
+		sig.WriteString("(" + f.ReceiverType + ") ")
+	}
+
+	params := make([]string, len(f.Parameters))
+	for i, param := range f.Parameters {
+		params[i] = param.Type
+	}
+	sig.WriteString(f.Name + "(" + strings.Join(params, ", ") + ")")
+
+	switch len(f.ReturnTypes) {
+	case 0:
+	case 1:
+		sig.WriteString(" " + f.ReturnTypes[0])
+	default:
+		sig.WriteString(" (" + strings.Join(f.ReturnTypes, ", ") + ")")
+	}
+	return sig.String()
+}
+
+// SideEffects names the environment dependencies that make the function hard to test in isolation
+func (f *Function) SideEffects() []string {
+	var effects []string
+	if f.ReadsEnv {
+		effects = append(effects, "env")
+	}
+	if f.TouchesFS {
+		effects = append(effects, "filesystem")
+	}
+	if f.UsesNetwork {
+		effects = append(effects, "network")
+	}
+	if f.UsesTime {
+		effects = append(effects, "time")
+	}
+	return effects
+}
+
+// Shared test fixtures a function may need
+const (
+	FixtureTempDir = "tempdir"
+	FixtureEnv     = "env"
+	FixtureServer  = "server"
+)
+
 // Param represents a function parameter
 type Param struct {
 	Name string `json:"name"`
 	Type string `json:"type"`
 }
 
+// LineRange is a span of source lines, both ends included
+type LineRange struct {
+	StartLine int `json:"start_line"`
+	EndLine   int `json:"end_line"`
+}
+
+// FunctionAt is what gcov func-at reports about the function enclosing a source line, the least an
+// editor needs for hovers and code lenses
+type FunctionAt struct {
+	File            string       `json:"file"` // relative to the module
+	Line            int          `json:"line"`
+	Function        string       `json:"function"` // Receiver.Name for methods, Parent.funcN for closures
+	Package         string       `json:"package"`
+	StartLine       int          `json:"start_line"`
+	EndLine         int          `json:"end_line"`
+	Coverage        float64      `json:"coverage"`
+	IsCovered       bool         `json:"is_covered"`
+	Complexity      int          `json:"complexity"`
+	UncoveredBlocks []*LineRange `json:"uncovered_blocks"`           // line ranges that never ran
+	GenerateCommand string       `json:"generate_command,omitempty"` // gcov generate invocation writing tests for it
+}
+
 // Block represents a coverage block (statement or branch)
 type Block struct {
 	StartLine int   `json:"start_line"`
@@ -96,6 +411,22 @@ type Block struct {
 	IsCovered bool  `json:"is_covered"`
 }
 
+// Function limits a function can exceed
+const (
+	LimitLines  = "lines"
+	LimitParams = "params"
+)
+
+// Default function limits, unless function_limits or --max-function-lines and --max-params set others
+const (
+	DefaultFunctionLineLimit = 80
+	DefaultParamLimit        = 6
+)
+
+// DefaultHighComplexityThreshold is the cyclomatic complexity above which a function counts as highly
+// complex, unless high_complexity_threshold or --complexity-warn set another
+const DefaultHighComplexityThreshold = 10
+
 // Summary provides high-level coverage statistics
 type Summary struct {
 	TotalPackages     int     `json:"total_packages"`
@@ -116,28 +447,82 @@ type Summary struct {
 	PrivateFunctionCoverage float64 `json:"private_function_coverage"`
 	MethodCoverage          float64 `json:"method_coverage"`
 
+	// Exported API surface referenced directly from tests
+	ExportedAPI int     `json:"exported_api"`
+	TestedAPI   int     `json:"tested_api"`
+	APICoverage float64 `json:"api_coverage"`
+
+	// Exported functions with no callers and no tests
+	PossiblyDeadFunctions int `json:"possibly_dead_functions"`
+
+	// Functions left out of the function totals, see AnalysisResult.UntestableFunctions
+	UntestableFunctions int `json:"untestable_functions"`
+
 	// Quality metrics
 	AvgComplexity           float64 `json:"avg_complexity"`
 	HighComplexityFunctions int     `json:"high_complexity_functions"`
+	HighComplexityThreshold int     `json:"high_complexity_threshold,omitempty"` // complexity above which a function counts as high
 	MaxComplexity           int     `json:"max_complexity"`
 	TotalComplexity         int     `json:"total_complexity"`
 
+	// Testable functions longer or taking more parameters than the limits
+	OversizedFunctions int `json:"oversized_functions"`
+	FunctionLineLimit  int `json:"function_line_limit,omitempty"`
+	ParamLimit         int `json:"param_limit,omitempty"`
+
+	// Closures that never ran inside functions that did, see GetUncoveredClosures
+	UncoveredClosures int `json:"uncovered_closures"`
+
+	// Types implementing an exported interface with an uncovered implementing method
+	UntestedImplementations int `json:"untested_implementations"`
+
 	// Test statistics
-	TotalTestFiles int     `json:"total_test_files"`
-	TestCoverage   float64 `json:"test_coverage"`
+	TotalTestFiles    int     `json:"total_test_files"`
+	TestCoverage      float64 `json:"test_coverage"`
+	TestFunctions     int     `json:"test_functions"`
+	Assertions        int     `json:"assertions"`
+	AssertionsPerTest float64 `json:"assertions_per_test"`
+	TestLines         int     `json:"test_lines"`
+	CodeLines         int     `json:"code_lines"`
+	TestToCodeRatio   float64 `json:"test_to_code_ratio"`
+
+	// Tests without assertions and the functions only they call
+	AssertionFreeTests     int `json:"assertion_free_tests"`
+	WeaklyCoveredFunctions int `json:"weakly_covered_functions"`
 }
 
 // Metadata contains information about the analysis execution
 type Metadata struct {
-	Version          string        `json:"version"`
-	AnalysisTime     time.Duration `json:"analysis_time"`
-	GoVersion        string        `json:"go_version"`
-	ModulePath       string        `json:"module_path"`
-	BuildConstraints []string      `json:"build_constraints,omitempty"`
-	ExcludedDirs     []string      `json:"excluded_dirs"`
-	IncludedPackages []string      `json:"included_packages"`
-	Configuration    interface{}   `json:"configuration,omitempty"`
-	ProfilePath      string        `json:"profile_path,omitempty"`
+	Version          string         `json:"version"`
+	AnalysisTime     time.Duration  `json:"analysis_time"`
+	GoVersion        string         `json:"go_version"`
+	ModulePath       string         `json:"module_path"`
+	BuildConstraints []string       `json:"build_constraints,omitempty"`
+	ExcludedDirs     []string       `json:"excluded_dirs"`
+	IncludedPackages []string       `json:"included_packages"`
+	Configuration    interface{}    `json:"configuration,omitempty"`
+	ProfilePath      string         `json:"profile_path,omitempty"`
+	ExcludedFiles    map[string]int `json:"excluded_files,omitempty"` // files left out of coverage, by class
+	SelfProfile      *SelfProfile   `json:"self_profile,omitempty"`   // where the analysis spent time and memory, with --self-profile
+	Scope            string         `json:"scope,omitempty"`          // file or directory the analysis was limited to
+}
+
+// SelfProfile records where an analysis spent its time and how much memory it needed, so slow runs on
+// large repositories can be reported with numbers
+type SelfProfile struct {
+	Phases         []*PhaseTiming `json:"phases"`
+	Total          time.Duration  `json:"total"`
+	PeakHeapBytes  uint64         `json:"peak_heap_bytes"` // high-water mark of the live heap, sampled
+	TotalAllocated uint64         `json:"total_allocated_bytes"`
+	GCCycles       uint32         `json:"gc_cycles"`
+	Goroutines     int            `json:"goroutines"`
+	CPUs           int            `json:"cpus"`
+}
+
+// PhaseTiming is the time an analysis phase took: walk, profile, parse, coverage-apply, summary or attribution
+type PhaseTiming struct {
+	Name     string        `json:"name"`
+	Duration time.Duration `json:"duration"`
 }
 
 // GenerationResult represents the result of test generation
@@ -147,12 +532,23 @@ type GenerationResult struct {
 	TestsGenerated    int              `json:"tests_generated"`
 	FilesCreated      int              `json:"files_created"`
 	FilesModified     int              `json:"files_modified"`
+	FilesUnchanged    int              `json:"files_unchanged"` // regenerated with identical content, so not rewritten
 	FunctionsCovered  int              `json:"functions_covered"`
 	GeneratedFiles    []*GeneratedFile `json:"generated_files"`
 	EstimatedCoverage float64          `json:"estimated_coverage"`
 	GenerationTime    time.Duration    `json:"generation_time"`
+	Seed              int64            `json:"seed"` // data generation seed, to reproduce the run
 	Errors            []string         `json:"errors,omitempty"`
 	Warnings          []string         `json:"warnings,omitempty"`
+	BackupRun         string           `json:"backup_run,omitempty"` // backup run holding overwritten files, for gcov restore
+
+	// Error paths that generated cases target, and those no input can reach, as file:line
+	ErrorPathsTargeted    int      `json:"error_paths_targeted"`
+	UnreachableErrorPaths []string `json:"unreachable_error_paths,omitempty"`
+
+	// Tests written by the AI backend that passed validation, and those that did not
+	AITestsAccepted int `json:"ai_tests_accepted,omitempty"`
+	AITestsRejected int `json:"ai_tests_rejected,omitempty"`
 }
 
 // GeneratedFile represents a test file that was generated
@@ -164,13 +560,57 @@ type GeneratedFile struct {
 	Size           int64       `json:"size"`
 	Created        bool        `json:"created"`
 	Modified       bool        `json:"modified"`
+	Unchanged      bool        `json:"unchanged,omitempty"` // the file already held the generated content
+}
+
+// MutationReport represents the result of a mutation testing run
+type MutationReport struct {
+	ProjectPath   string                     `json:"project_path"`
+	Timestamp     time.Time                  `json:"timestamp"`
+	TotalMutants  int                        `json:"total_mutants"`
+	Killed        int                        `json:"killed"`
+	Survived      int                        `json:"survived"`
+	TimedOut      int                        `json:"timed_out"`
+	Invalid       int                        `json:"invalid"`
+	MutationScore float64                    `json:"mutation_score"`
+	Packages      map[string]*PackageMutants `json:"packages"` // by package directory, relative to the project
+	Mutants       []*Mutant                  `json:"mutants"`
+	Duration      time.Duration              `json:"duration"`
+	Errors        []string                   `json:"errors,omitempty"`
+}
+
+// PackageMutants summarizes mutation testing results for a package
+type PackageMutants struct {
+	Name          string  `json:"name"`
+	Path          string  `json:"path"`
+	Total         int     `json:"total"`
+	Killed        int     `json:"killed"`
+	Survived      int     `json:"survived"`
+	TimedOut      int     `json:"timed_out"`
+	Invalid       int     `json:"invalid"`
+	MutationScore float64 `json:"mutation_score"`
+}
+
+// Mutant represents a single source mutation and its test outcome
+type Mutant struct {
+	ID       int    `json:"id"`
+	Package  string `json:"package"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Function string `json:"function"`
+	Operator string `json:"operator"` // conditional, arithmetic, return
+	Original string `json:"original"`
+	Mutated  string `json:"mutated"`
+	Status   string `json:"status"` // killed, survived, timeout, invalid
 }
 
 // TestCase represents a generated test case
 type TestCase struct {
 	FunctionName  string `json:"function_name"`
 	TestName      string `json:"test_name"`
-	TestType      string `json:"test_type"` // unit, table, benchmark, integration
+	TestType      string `json:"test_type"`           // unit, table, benchmark, integration
+	Target        string `json:"target,omitempty"`    // Key of the tested function
+	Signature     string `json:"signature,omitempty"` // TypeSignature of the tested function
 	InputCount    int    `json:"input_count"`
 	HasMocks      bool   `json:"has_mocks"`
 	HasSetup      bool   `json:"has_setup"`
@@ -179,6 +619,17 @@ type TestCase struct {
 	Complexity    int    `json:"complexity"`
 }
 
+// TestPriority represents a ranked recommendation to test a function
+type TestPriority struct {
+	Rank              int       `json:"rank"`
+	Function          *Function `json:"function"`
+	Score             float64   `json:"score"`
+	EstimatedGain     float64   `json:"estimated_gain"`
+	CumulativeGain    float64   `json:"cumulative_gain"`
+	ProjectedCoverage float64   `json:"projected_coverage"`
+	Effort            string    `json:"effort"` // low, medium, high
+}
+
 // ProjectInfo represents information about a Go project
 type ProjectInfo struct {
 	ModulePath     string   `json:"module_path"`
@@ -257,6 +708,47 @@ func (ar *AnalysisResult) GetUncoveredFunctions() []*Function {
 	return uncovered
 }
 
+// LinkFunctions points the function lists of a result decoded from JSON, such as UncoveredFunctions,
+// back at the functions of PackageCoverage, of which decoding makes separate copies. Without it, owners,
+// authors and exemptions recorded on the functions after decoding would be missing from the lists
+func (ar *AnalysisResult) LinkFunctions() {
+	byPosition := make(map[string]*Function)
+	var index func(functions []*Function)
+	index = func(functions []*Function) {
+		for _, function := range functions {
+			byPosition[function.File+":"+strconv.Itoa(function.StartLine)+":"+function.Key()] = function
+			index(function.Closures)
+		}
+	}
+	for _, pkg := range ar.PackageCoverage {
+		for _, file := range pkg.Files {
+			index(file.Functions)
+		}
+	}
+
+	link := func(functions []*Function) {
+		for i, function := range functions {
+			if linked := byPosition[function.File+":"+strconv.Itoa(function.StartLine)+":"+function.Key()]; linked != nil {
+				functions[i] = linked
+			}
+		}
+	}
+	link(ar.UncoveredFunctions)
+	link(ar.UntestedAPI)
+	link(ar.PossiblyDeadFunctions)
+	link(ar.UntestableFunctions)
+	link(ar.WeaklyCoveredFunctions)
+	for _, owner := range ar.OwnerCoverage {
+		link(owner.UncoveredFunctions)
+	}
+	for _, author := range ar.AuthorCoverage {
+		link(author.UncoveredFunctions)
+	}
+	for _, area := range ar.AreaCoverage {
+		link(area.UncoveredFunctions)
+	}
+}
+
 // GetPackageByName returns a package by name
 func (ar *AnalysisResult) GetPackageByName(name string) *Package {
 	return ar.PackageCoverage[name]
@@ -275,6 +767,32 @@ func (ar *AnalysisResult) GetLowCoveragePackages(threshold float64) []*Package {
 	return lowCoverage
 }
 
+// HighComplexityThreshold returns the complexity above which functions of the result count as highly
+// complex, DefaultHighComplexityThreshold for results saved before it was recorded
+func (ar *AnalysisResult) HighComplexityThreshold() int {
+	if ar.Summary == nil || ar.Summary.HighComplexityThreshold <= 0 {
+		return DefaultHighComplexityThreshold
+	}
+	return ar.Summary.HighComplexityThreshold
+}
+
+// SetHighComplexityThreshold reclassifies the testable functions of the result against another
+// high complexity threshold, recounting Summary.HighComplexityFunctions
+func (ar *AnalysisResult) SetHighComplexityThreshold(threshold int) {
+	if ar.Summary == nil {
+		return
+	}
+
+	count := 0
+	for _, function := range ar.GetHighComplexityFunctions(threshold) {
+		if function.IsTestable {
+			count++
+		}
+	}
+	ar.Summary.HighComplexityThreshold = threshold
+	ar.Summary.HighComplexityFunctions = count
+}
+
 // GetHighComplexityFunctions returns functions with complexity above threshold
 func (ar *AnalysisResult) GetHighComplexityFunctions(threshold int) []*Function {
 	var highComplexity []*Function
@@ -292,6 +810,75 @@ func (ar *AnalysisResult) GetHighComplexityFunctions(threshold int) []*Function
 	return highComplexity
 }
 
+// GetTopRiskyFunctions returns testable functions ordered by descending risk score
+func (ar *AnalysisResult) GetTopRiskyFunctions(limit int) []*Function {
+	var risky []*Function
+
+	for _, function := range ar.GetTestableFunctions() {
+		if function.RiskScore > 0 {
+			risky = append(risky, function)
+		}
+	}
+
+	sort.Slice(risky, func(i, j int) bool {
+		return risky[i].RiskScore > risky[j].RiskScore
+	})
+
+	if limit > 0 && len(risky) > limit {
+		risky = risky[:limit]
+	}
+
+	return risky
+}
+
+// GetUncoveredClosures returns the closures that never ran although their parent did, such as
+// goroutines and callbacks no test triggers, which the parent's coverage hides; by file and line
+func (ar *AnalysisResult) GetUncoveredClosures() []*Function {
+	var closures []*Function
+	var collect func(parent *Function)
+	collect = func(parent *Function) {
+		for _, closure := range parent.Closures {
+			if !closure.IsCovered && closure.Statements > 0 {
+				closures = append(closures, closure)
+				continue
+			}
+			collect(closure)
+		}
+	}
+	for _, function := range ar.GetTestableFunctions() {
+		if function.IsCovered {
+			collect(function)
+		}
+	}
+
+	sort.Slice(closures, func(i, j int) bool {
+		if closures[i].File != closures[j].File {
+			return closures[i].File < closures[j].File
+		}
+		return closures[i].StartLine < closures[j].StartLine
+	})
+	return closures
+}
+
+// GetOversizedFunctions returns the testable functions exceeding a function limit, uncovered ones
+// first and longer ones before shorter ones
+func (ar *AnalysisResult) GetOversizedFunctions() []*Function {
+	var oversized []*Function
+	for _, function := range ar.GetTestableFunctions() {
+		if len(function.OverLimits) > 0 {
+			oversized = append(oversized, function)
+		}
+	}
+
+	sort.SliceStable(oversized, func(i, j int) bool {
+		if oversized[i].IsCovered != oversized[j].IsCovered {
+			return !oversized[i].IsCovered
+		}
+		return oversized[i].Lines > oversized[j].Lines
+	})
+	return oversized
+}
+
 // GetTestableFunction returns all testable functions
 func (ar *AnalysisResult) GetTestableFunctions() []*Function {
 	var testable []*Function
@@ -343,6 +930,31 @@ func (ar *AnalysisResult) GetExternalDependencies() []*Function {
 	return external
 }
 
+// GetClockDependentFunctions returns functions that read the wall clock directly rather than through an
+// injected clock, ordered by package and name
+func (ar *AnalysisResult) GetClockDependentFunctions() []*Function {
+	var dependent []*Function
+
+	for _, pkg := range ar.PackageCoverage {
+		for _, file := range pkg.Files {
+			for _, function := range file.Functions {
+				if function.UsesTime && len(function.Clocks) == 0 {
+					dependent = append(dependent, function)
+				}
+			}
+		}
+	}
+
+	sort.Slice(dependent, func(i, j int) bool {
+		if dependent[i].Package != dependent[j].Package {
+			return dependent[i].Package < dependent[j].Package
+		}
+		return dependent[i].Name < dependent[j].Name
+	})
+
+	return dependent
+}
+
 // CoverageTrend represents coverage trends over time
 type CoverageTrend struct {
 	Timestamp        time.Time `json:"timestamp"`
@@ -352,6 +964,115 @@ type CoverageTrend struct {
 	Direction        string    `json:"direction"` // up, down, stable
 }
 
+// OwnerCoverage aggregates coverage for the files owned by a team or person
+type OwnerCoverage struct {
+	Owner              string      `json:"owner"`
+	Files              int         `json:"files"`
+	TotalFunctions     int         `json:"total_functions"`
+	CoveredFunctions   int         `json:"covered_functions"`
+	Statements         int         `json:"statements"`
+	CoveredStatements  int         `json:"covered_statements"`
+	Coverage           float64     `json:"coverage"`
+	FunctionCoverage   float64     `json:"function_coverage"`
+	UncoveredFunctions []*Function `json:"uncovered_functions,omitempty"`
+}
+
+// AuthorCoverage gathers the uncovered functions whose lines were mostly written by an author, by git blame
+type AuthorCoverage struct {
+	Author              string      `json:"author"`
+	UncoveredFunctions  []*Function `json:"uncovered_functions"`
+	UncoveredStatements int         `json:"uncovered_statements"`
+}
+
+// Exemption states, by how close an exemption is to its expiry date
+const (
+	ExemptionActive   = "active"
+	ExemptionExpiring = "expiring" // expires soon, still honored
+	ExemptionExpired  = "expired"  // no longer honored, its code counts towards coverage again
+)
+
+// Exemption leaves the functions or files matching its pattern out of the totals, and so the
+// thresholds, until it expires. Expired exemptions whose code is still uncovered fail the analysis
+type Exemption struct {
+	Function            string    `json:"function,omitempty"` // pattern matching the name or Receiver.Name of functions
+	File                string    `json:"file,omitempty"`     // pattern matching files, as classification patterns do
+	Reason              string    `json:"reason"`
+	Expires             time.Time `json:"expires"`
+	Status              string    `json:"status"`               // see Exemption constants
+	Functions           []string  `json:"functions,omitempty"`  // Receiver.Name of the testable functions it matches
+	UncoveredStatements int       `json:"uncovered_statements"` // statements of its code never run, counted once expired
+}
+
+// AreaCoverage aggregates coverage for the files of a logical feature area, as configured by path patterns
+type AreaCoverage struct {
+	Area               string      `json:"area"`
+	Patterns           []string    `json:"patterns"`
+	Threshold          float64     `json:"threshold,omitempty"` // minimum coverage of the area, 0 for none
+	Files              int         `json:"files"`
+	TotalFunctions     int         `json:"total_functions"`
+	CoveredFunctions   int         `json:"covered_functions"`
+	Statements         int         `json:"statements"`
+	CoveredStatements  int         `json:"covered_statements"`
+	Coverage           float64     `json:"coverage"`
+	FunctionCoverage   float64     `json:"function_coverage"`
+	UncoveredFunctions []*Function `json:"uncovered_functions,omitempty"`
+}
+
+// TypeCoverage aggregates coverage for the testable methods of a receiver type, and lists the exported
+// interfaces of the project the type implements
+type TypeCoverage struct {
+	Package            string   `json:"package"`
+	Type               string   `json:"type"`
+	TotalMethods       int      `json:"total_methods"`
+	CoveredMethods     int      `json:"covered_methods"`
+	Statements         int      `json:"statements"`
+	CoveredStatements  int      `json:"covered_statements"`
+	Coverage           float64  `json:"coverage"`
+	MethodCoverage     float64  `json:"method_coverage"`
+	UncoveredMethods   []string `json:"uncovered_methods,omitempty"`
+	Implements         []string `json:"implements,omitempty"`          // matched by method names and arity; package-qualified when declared elsewhere
+	UntestedInterfaces []string `json:"untested_interfaces,omitempty"` // implemented interfaces with an uncovered implementing method
+}
+
+// Kinds of initialization code
+const (
+	InitializerInit = "init" // an init function
+	InitializerVar  = "var"  // a package-level variable initialized with function literals
+)
+
+// Initializer is code that runs when its package is initialized, before any test: an init function or
+// the function literals of a package-level variable initializer
+type Initializer struct {
+	Kind              string  `json:"kind"` // see Initializer constants
+	Name              string  `json:"name"` // init, or the names of the variables
+	Package           string  `json:"package"`
+	File              string  `json:"file"`
+	StartLine         int     `json:"start_line"`
+	EndLine           int     `json:"end_line"`
+	Statements        int     `json:"statements"`
+	CoveredStatements int     `json:"covered_statements"`
+	Coverage          float64 `json:"coverage"`
+}
+
+// InitializationCoverage aggregates the coverage of the initialization code of the project, which
+// function totals leave out
+type InitializationCoverage struct {
+	Statements        int            `json:"statements"`
+	CoveredStatements int            `json:"covered_statements"`
+	Coverage          float64        `json:"coverage"`
+	Initializers      []*Initializer `json:"initializers"`
+}
+
+// CoverageSnapshot records project coverage at a point in time for the history store
+type CoverageSnapshot struct {
+	Timestamp        time.Time          `json:"timestamp"`
+	Commit           string             `json:"commit,omitempty"`
+	Tag              string             `json:"tag,omitempty"`
+	OverallCoverage  float64            `json:"overall_coverage"`
+	FunctionCoverage float64            `json:"function_coverage"`
+	Packages         map[string]float64 `json:"packages"`
+}
+
 // PackageMetrics represents detailed metrics for a package
 type PackageMetrics struct {
 	PackageName       string  `json:"package_name"`
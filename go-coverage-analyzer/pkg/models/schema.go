@@ -0,0 +1,151 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// SchemaVersion is the version of the JSON analysis result, following semantic versioning. Within a
+// major version fields are only ever added: none is removed, renamed or given another type, so
+// consumers that ignore unknown fields keep working. Minor versions add fields, patch versions only
+// tighten the schema's descriptions. A new major version may change the shape in any way, and is
+// announced in the release notes along with the schema gcov schema prints for it
+const SchemaVersion = "1.18.0"
+
+// SchemaMajor returns the major version of a schema version, or "" when it is not one
+func SchemaMajor(version string) string {
+	major, _, _ := strings.Cut(version, ".")
+	return major
+}
+
+// CheckSchemaVersion reports whether a JSON analysis result of the given schema version can be read as
+// the current one: results of the same major version can, as can results written before versioning
+func CheckSchemaVersion(version string) error {
+	if version == "" || SchemaMajor(version) == SchemaMajor(SchemaVersion) {
+		return nil
+	}
+	return fmt.Errorf("analysis result has schema version %s, this gcov reads %s.x", version, SchemaMajor(SchemaVersion))
+}
+
+// JSONSchema returns the JSON Schema (draft 2020-12) of the JSON analysis result, derived from the
+// models and their json tags. Unknown properties are allowed, as minor versions add them
+func JSONSchema() ([]byte, error) {
+	builder := &schemaBuilder{defs: make(map[string]any)}
+	root := builder.define(reflect.TypeOf(AnalysisResult{}))
+
+	schema := map[string]any{
+		"$schema":     "https://json-schema.org/draft/2020-12/schema",
+		"title":       "gcov analysis result",
+		"description": fmt.Sprintf("JSON analysis result written by gcov analyze --output json, schema version %s", SchemaVersion),
+		"$ref":        root,
+		"$defs":       builder.defs,
+	}
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+// schemaBuilder collects the definitions of the struct types a schema refers to
+type schemaBuilder struct {
+	defs map[string]any
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// define adds the definition of a struct type, unless it is defined already, and returns its reference
+func (b *schemaBuilder) define(t reflect.Type) string {
+	ref := "#/$defs/" + t.Name()
+	if _, ok := b.defs[t.Name()]; ok {
+		return ref
+	}
+	// Reserve the name first, so recursive types refer to the definition being built
+	b.defs[t.Name()] = nil
+
+	properties := make(map[string]any)
+	required := make([]string, 0)
+	b.addFields(t, properties, &required)
+
+	b.defs[t.Name()] = map[string]any{
+		"type":       "object",
+		"properties": properties,
+		"required":   required,
+	}
+	return ref
+}
+
+// addFields adds the properties encoding/json writes for the fields of a struct, flattening embedded
+// structs as it does; fields without omitempty are always written and so required
+func (b *schemaBuilder) addFields(t reflect.Type, properties map[string]any, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" || !field.IsExported() && !field.Anonymous {
+			continue
+		}
+
+		name, options, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				b.addFields(embedded, properties, required)
+				continue
+			}
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		properties[name] = b.schemaFor(field.Type)
+		if !strings.Contains(options, "omitempty") {
+			*required = append(*required, name)
+		}
+	}
+}
+
+// schemaFor returns the schema of a type as encoding/json writes it; nil pointers, slices and maps
+// are written as null
+func (b *schemaBuilder) schemaFor(t reflect.Type) map[string]any {
+	switch {
+	case t == timeType:
+		return map[string]any{"type": "string", "format": "date-time"}
+	case t == durationType:
+		return map[string]any{"type": "integer", "description": "duration in nanoseconds"}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return nullable(b.schemaFor(t.Elem()))
+	case reflect.Struct:
+		return map[string]any{"$ref": b.define(t)}
+	case reflect.Slice, reflect.Array:
+		return nullable(map[string]any{"type": "array", "items": b.schemaFor(t.Elem())})
+	case reflect.Map:
+		return nullable(map[string]any{"type": "object", "additionalProperties": b.schemaFor(t.Elem())})
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	default:
+		// Interfaces hold any JSON value
+		return map[string]any{}
+	}
+}
+
+// nullable allows null in place of a value of the schema
+func nullable(schema map[string]any) map[string]any {
+	if kind, ok := schema["type"].(string); ok {
+		schema["type"] = []string{kind, "null"}
+		return schema
+	}
+	return map[string]any{"anyOf": []any{schema, map[string]any{"type": "null"}}}
+}
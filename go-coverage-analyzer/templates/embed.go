@@ -0,0 +1,9 @@
+// Package templates embeds the built-in test template packs, so gcov finds them from any working directory
+package templates
+
+import "embed"
+
+// Packs holds the standard, table and testify template packs as <pack>/<name>.tmpl
+//
+//go:embed standard table testify
+var Packs embed.FS